@@ -154,6 +154,12 @@ func main() {
 			openDevice = func() (io.ReadWriteCloser, error) {
 				return tdx.Open()
 			}
+		case variant.QEMUTDXSimulated{}:
+			openDevice = func() (io.ReadWriteCloser, error) {
+				return tdx.OpenSimulated(vtpm.OpenVTPM)
+			}
+		case variant.QEMUSEVSNPSimulated{}:
+			openDevice = vtpm.OpenVTPM
 		default:
 			log.Fatalf("Unsupported attestation variant: %s", attestVariant)
 		}
@@ -25,21 +25,38 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// InitRequest is the rpc message sent to the Constellation bootstrapper to initiate the cluster bootstrapping.
 type InitRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	KmsUri               string                  `protobuf:"bytes,1,opt,name=kms_uri,json=kmsUri,proto3" json:"kms_uri,omitempty"`
-	StorageUri           string                  `protobuf:"bytes,2,opt,name=storage_uri,json=storageUri,proto3" json:"storage_uri,omitempty"`
-	MeasurementSalt      []byte                  `protobuf:"bytes,3,opt,name=measurement_salt,json=measurementSalt,proto3" json:"measurement_salt,omitempty"`
-	KubernetesVersion    string                  `protobuf:"bytes,5,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetes_version,omitempty"`
-	ConformanceMode      bool                    `protobuf:"varint,6,opt,name=conformance_mode,json=conformanceMode,proto3" json:"conformance_mode,omitempty"`
+	// KmsUri is an URI encoding access to the KMS service or master secret.
+	KmsUri string `protobuf:"bytes,1,opt,name=kms_uri,json=kmsUri,proto3" json:"kms_uri,omitempty"`
+	// StorageUri is an URI encoding access to the storage service.
+	StorageUri string `protobuf:"bytes,2,opt,name=storage_uri,json=storageUri,proto3" json:"storage_uri,omitempty"`
+	// MeasurementSalt is a salt used to generate the clusterID for the initial bootstrapping node.
+	MeasurementSalt []byte `protobuf:"bytes,3,opt,name=measurement_salt,json=measurementSalt,proto3" json:"measurement_salt,omitempty"`
+	// KubernetesVersion is the version of Kubernetes to install.
+	KubernetesVersion string `protobuf:"bytes,5,opt,name=kubernetes_version,json=kubernetesVersion,proto3" json:"kubernetes_version,omitempty"`
+	// ConformanceMode is a flag to indicate whether the cluster should be bootstrapped for Kubernetes conformance testing.
+	ConformanceMode bool `protobuf:"varint,6,opt,name=conformance_mode,json=conformanceMode,proto3" json:"conformance_mode,omitempty"`
+	// KubernetesComponents is a list of Kubernetes components to install.
 	KubernetesComponents []*components.Component `protobuf:"bytes,7,rep,name=kubernetes_components,json=kubernetesComponents,proto3" json:"kubernetes_components,omitempty"`
-	InitSecret           []byte                  `protobuf:"bytes,8,opt,name=init_secret,json=initSecret,proto3" json:"init_secret,omitempty"`
-	ClusterName          string                  `protobuf:"bytes,9,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
-	ApiserverCertSans    []string                `protobuf:"bytes,10,rep,name=apiserver_cert_sans,json=apiserverCertSans,proto3" json:"apiserver_cert_sans,omitempty"`
-	ServiceCidr          string                  `protobuf:"bytes,11,opt,name=service_cidr,json=serviceCidr,proto3" json:"service_cidr,omitempty"`
+	// InitSecret is a secret used to authenticate the initial bootstrapping node.
+	InitSecret []byte `protobuf:"bytes,8,opt,name=init_secret,json=initSecret,proto3" json:"init_secret,omitempty"`
+	// ClusterName is the name of the cluster.
+	ClusterName string `protobuf:"bytes,9,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	// ApiserverCertSans is a list of Subject Alternative Names to add to the apiserver certificate.
+	ApiserverCertSans []string `protobuf:"bytes,10,rep,name=apiserver_cert_sans,json=apiserverCertSans,proto3" json:"apiserver_cert_sans,omitempty"`
+	// ServiceCIDR is the CIDR to use for Kubernetes ClusterIPs. Dual-stack clusters use a comma-separated IPv4 and IPv6 CIDR.
+	ServiceCidr string `protobuf:"bytes,11,opt,name=service_cidr,json=serviceCidr,proto3" json:"service_cidr,omitempty"`
+	// KubernetesConfigOverrides are user supplied overrides of the generated kubeadm and kubelet configuration.
+	KubernetesConfigOverrides *KubernetesConfigOverrides `protobuf:"bytes,12,opt,name=kubernetes_config_overrides,json=kubernetesConfigOverrides,proto3" json:"kubernetes_config_overrides,omitempty"`
+	// DiskEncryptionProfile is the name of the encryption profile of the state disks. An empty name refers to the default profile.
+	DiskEncryptionProfile string `protobuf:"bytes,13,opt,name=disk_encryption_profile,json=diskEncryptionProfile,proto3" json:"disk_encryption_profile,omitempty"`
+	// PodCIDR is the CIDR to use for Kubernetes Pods. Dual-stack clusters use a comma-separated IPv4 and IPv6 CIDR. An empty value refers to the default pod network.
+	PodCidr string `protobuf:"bytes,14,opt,name=pod_cidr,json=podCidr,proto3" json:"pod_cidr,omitempty"`
 }
 
 func (x *InitRequest) Reset() {
@@ -144,13 +161,231 @@ func (x *InitRequest) GetServiceCidr() string {
 	return ""
 }
 
+func (x *InitRequest) GetKubernetesConfigOverrides() *KubernetesConfigOverrides {
+	if x != nil {
+		return x.KubernetesConfigOverrides
+	}
+	return nil
+}
+
+func (x *InitRequest) GetDiskEncryptionProfile() string {
+	if x != nil {
+		return x.DiskEncryptionProfile
+	}
+	return ""
+}
+
+func (x *InitRequest) GetPodCidr() string {
+	if x != nil {
+		return x.PodCidr
+	}
+	return ""
+}
+
+// KubernetesConfigOverrides is the allow-listed set of kubeadm ClusterConfiguration and KubeletConfiguration options a user may override.
+type KubernetesConfigOverrides struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ApiserverEnableAdmissionPlugins are admission plugins to enable in addition to the Kubernetes defaults.
+	ApiserverEnableAdmissionPlugins []string `protobuf:"bytes,1,rep,name=apiserver_enable_admission_plugins,json=apiserverEnableAdmissionPlugins,proto3" json:"apiserver_enable_admission_plugins,omitempty"`
+	// ApiserverDisableAdmissionPlugins are admission plugins to disable.
+	ApiserverDisableAdmissionPlugins []string `protobuf:"bytes,2,rep,name=apiserver_disable_admission_plugins,json=apiserverDisableAdmissionPlugins,proto3" json:"apiserver_disable_admission_plugins,omitempty"`
+	// ApiserverOidc configures OpenID Connect authentication for the API server.
+	ApiserverOidc *OIDCConfig `protobuf:"bytes,3,opt,name=apiserver_oidc,json=apiserverOidc,proto3" json:"apiserver_oidc,omitempty"`
+	// AuditPolicy replaces the default audit policy of the API server.
+	AuditPolicy []byte `protobuf:"bytes,4,opt,name=audit_policy,json=auditPolicy,proto3" json:"audit_policy,omitempty"`
+	// KubeletEvictionHard are the hard eviction thresholds of the kubelet.
+	KubeletEvictionHard map[string]string `protobuf:"bytes,5,rep,name=kubelet_eviction_hard,json=kubeletEvictionHard,proto3" json:"kubelet_eviction_hard,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// KubeletEvictionSoft are the soft eviction thresholds of the kubelet.
+	KubeletEvictionSoft map[string]string `protobuf:"bytes,6,rep,name=kubelet_eviction_soft,json=kubeletEvictionSoft,proto3" json:"kubelet_eviction_soft,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// KubeletEvictionSoftGracePeriod are the grace periods of the soft eviction thresholds of the kubelet.
+	KubeletEvictionSoftGracePeriod map[string]string `protobuf:"bytes,7,rep,name=kubelet_eviction_soft_grace_period,json=kubeletEvictionSoftGracePeriod,proto3" json:"kubelet_eviction_soft_grace_period,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *KubernetesConfigOverrides) Reset() {
+	*x = KubernetesConfigOverrides{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KubernetesConfigOverrides) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KubernetesConfigOverrides) ProtoMessage() {}
+
+func (x *KubernetesConfigOverrides) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KubernetesConfigOverrides.ProtoReflect.Descriptor instead.
+func (*KubernetesConfigOverrides) Descriptor() ([]byte, []int) {
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *KubernetesConfigOverrides) GetApiserverEnableAdmissionPlugins() []string {
+	if x != nil {
+		return x.ApiserverEnableAdmissionPlugins
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetApiserverDisableAdmissionPlugins() []string {
+	if x != nil {
+		return x.ApiserverDisableAdmissionPlugins
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetApiserverOidc() *OIDCConfig {
+	if x != nil {
+		return x.ApiserverOidc
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetAuditPolicy() []byte {
+	if x != nil {
+		return x.AuditPolicy
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetKubeletEvictionHard() map[string]string {
+	if x != nil {
+		return x.KubeletEvictionHard
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetKubeletEvictionSoft() map[string]string {
+	if x != nil {
+		return x.KubeletEvictionSoft
+	}
+	return nil
+}
+
+func (x *KubernetesConfigOverrides) GetKubeletEvictionSoftGracePeriod() map[string]string {
+	if x != nil {
+		return x.KubeletEvictionSoftGracePeriod
+	}
+	return nil
+}
+
+// OIDCConfig configures OpenID Connect authentication for the API server.
+type OIDCConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// IssuerUrl is the URL of the OpenID issuer.
+	IssuerUrl string `protobuf:"bytes,1,opt,name=issuer_url,json=issuerUrl,proto3" json:"issuer_url,omitempty"`
+	// ClientId is the client ID all tokens must be issued for.
+	ClientId string `protobuf:"bytes,2,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// UsernameClaim is the JWT claim to use as the user name.
+	UsernameClaim string `protobuf:"bytes,3,opt,name=username_claim,json=usernameClaim,proto3" json:"username_claim,omitempty"`
+	// UsernamePrefix is prepended to user names to prevent clashes with existing names.
+	UsernamePrefix string `protobuf:"bytes,4,opt,name=username_prefix,json=usernamePrefix,proto3" json:"username_prefix,omitempty"`
+	// GroupsClaim is the JWT claim to use as the user's groups.
+	GroupsClaim string `protobuf:"bytes,5,opt,name=groups_claim,json=groupsClaim,proto3" json:"groups_claim,omitempty"`
+	// GroupsPrefix is prepended to group names to prevent clashes with existing names.
+	GroupsPrefix string `protobuf:"bytes,6,opt,name=groups_prefix,json=groupsPrefix,proto3" json:"groups_prefix,omitempty"`
+}
+
+func (x *OIDCConfig) Reset() {
+	*x = OIDCConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OIDCConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OIDCConfig) ProtoMessage() {}
+
+func (x *OIDCConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OIDCConfig.ProtoReflect.Descriptor instead.
+func (*OIDCConfig) Descriptor() ([]byte, []int) {
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OIDCConfig) GetIssuerUrl() string {
+	if x != nil {
+		return x.IssuerUrl
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetUsernameClaim() string {
+	if x != nil {
+		return x.UsernameClaim
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetUsernamePrefix() string {
+	if x != nil {
+		return x.UsernamePrefix
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetGroupsClaim() string {
+	if x != nil {
+		return x.GroupsClaim
+	}
+	return ""
+}
+
+func (x *OIDCConfig) GetGroupsPrefix() string {
+	if x != nil {
+		return x.GroupsPrefix
+	}
+	return ""
+}
+
+// InitResponse is the rpc message sent by the Constellation bootstrapper in response to the InitRequest.
 type InitResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Kind:
-	//
 	//	*InitResponse_InitSuccess
 	//	*InitResponse_InitFailure
 	//	*InitResponse_Log
@@ -160,7 +395,7 @@ type InitResponse struct {
 func (x *InitResponse) Reset() {
 	*x = InitResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bootstrapper_initproto_init_proto_msgTypes[1]
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -173,7 +408,7 @@ func (x *InitResponse) String() string {
 func (*InitResponse) ProtoMessage() {}
 
 func (x *InitResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bootstrapper_initproto_init_proto_msgTypes[1]
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -186,7 +421,7 @@ func (x *InitResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitResponse.ProtoReflect.Descriptor instead.
 func (*InitResponse) Descriptor() ([]byte, []int) {
-	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{1}
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{3}
 }
 
 func (m *InitResponse) GetKind() isInitResponse_Kind {
@@ -239,20 +474,24 @@ func (*InitResponse_InitFailure) isInitResponse_Kind() {}
 
 func (*InitResponse_Log) isInitResponse_Kind() {}
 
+// InitSuccessResponse is the rpc message sent by the Constellation bootstrapper in response to the InitRequest when the bootstrapping was successful.
 type InitSuccessResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Kubeconfig is the kubeconfig for the bootstrapped cluster.
 	Kubeconfig []byte `protobuf:"bytes,1,opt,name=kubeconfig,proto3" json:"kubeconfig,omitempty"`
-	OwnerId    []byte `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
-	ClusterId  []byte `protobuf:"bytes,3,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// OwnerID is the owner ID of the bootstrapped cluster.
+	OwnerId []byte `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// ClusterID is the cluster ID of the bootstrapped cluster.
+	ClusterId []byte `protobuf:"bytes,3,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
 }
 
 func (x *InitSuccessResponse) Reset() {
 	*x = InitSuccessResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bootstrapper_initproto_init_proto_msgTypes[2]
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -265,7 +504,7 @@ func (x *InitSuccessResponse) String() string {
 func (*InitSuccessResponse) ProtoMessage() {}
 
 func (x *InitSuccessResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bootstrapper_initproto_init_proto_msgTypes[2]
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,7 +517,7 @@ func (x *InitSuccessResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitSuccessResponse.ProtoReflect.Descriptor instead.
 func (*InitSuccessResponse) Descriptor() ([]byte, []int) {
-	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{2}
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *InitSuccessResponse) GetKubeconfig() []byte {
@@ -302,18 +541,20 @@ func (x *InitSuccessResponse) GetClusterId() []byte {
 	return nil
 }
 
+// InitFailureResponse is the rpc message sent by the Constellation bootstrapper in response to the InitRequest when the bootstrapping failed.
 type InitFailureResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Error is the error message.
 	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 }
 
 func (x *InitFailureResponse) Reset() {
 	*x = InitFailureResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bootstrapper_initproto_init_proto_msgTypes[3]
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -326,7 +567,7 @@ func (x *InitFailureResponse) String() string {
 func (*InitFailureResponse) ProtoMessage() {}
 
 func (x *InitFailureResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_bootstrapper_initproto_init_proto_msgTypes[3]
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -339,7 +580,7 @@ func (x *InitFailureResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InitFailureResponse.ProtoReflect.Descriptor instead.
 func (*InitFailureResponse) Descriptor() ([]byte, []int) {
-	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{3}
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *InitFailureResponse) GetError() string {
@@ -349,18 +590,20 @@ func (x *InitFailureResponse) GetError() string {
 	return ""
 }
 
+// LogResponseType is the rpc message sent by the Constellation bootstrapper to stream log messages.
 type LogResponseType struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Log are the journald logs of the node.
 	Log []byte `protobuf:"bytes,1,opt,name=log,proto3" json:"log,omitempty"`
 }
 
 func (x *LogResponseType) Reset() {
 	*x = LogResponseType{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bootstrapper_initproto_init_proto_msgTypes[4]
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -373,7 +616,7 @@ func (x *LogResponseType) String() string {
 func (*LogResponseType) ProtoMessage() {}
 
 func (x *LogResponseType) ProtoReflect() protoreflect.Message {
-	mi := &file_bootstrapper_initproto_init_proto_msgTypes[4]
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,7 +629,7 @@ func (x *LogResponseType) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogResponseType.ProtoReflect.Descriptor instead.
 func (*LogResponseType) Descriptor() ([]byte, []int) {
-	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{4}
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *LogResponseType) GetLog() []byte {
@@ -396,21 +639,26 @@ func (x *LogResponseType) GetLog() []byte {
 	return nil
 }
 
+// KubernetesComponent is a Kubernetes component to install.
 type KubernetesComponent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Url         string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Hash        string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// Url to the component.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Hash of the component.
+	Hash string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	// InstallPath is the path to install the component to.
 	InstallPath string `protobuf:"bytes,3,opt,name=install_path,json=installPath,proto3" json:"install_path,omitempty"`
-	Extract     bool   `protobuf:"varint,4,opt,name=extract,proto3" json:"extract,omitempty"`
+	// Extract is a flag to indicate whether the component should be extracted.
+	Extract bool `protobuf:"varint,4,opt,name=extract,proto3" json:"extract,omitempty"`
 }
 
 func (x *KubernetesComponent) Reset() {
 	*x = KubernetesComponent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_bootstrapper_initproto_init_proto_msgTypes[5]
+		mi := &file_bootstrapper_initproto_init_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -423,7 +671,7 @@ func (x *KubernetesComponent) String() string {
 func (*KubernetesComponent) ProtoMessage() {}
 
 func (x *KubernetesComponent) ProtoReflect() protoreflect.Message {
-	mi := &file_bootstrapper_initproto_init_proto_msgTypes[5]
+	mi := &file_bootstrapper_initproto_init_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -436,7 +684,7 @@ func (x *KubernetesComponent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KubernetesComponent.ProtoReflect.Descriptor instead.
 func (*KubernetesComponent) Descriptor() ([]byte, []int) {
-	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{5}
+	return file_bootstrapper_initproto_init_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *KubernetesComponent) GetUrl() string {
@@ -475,7 +723,7 @@ var file_bootstrapper_initproto_init_proto_rawDesc = []byte{
 	0x6f, 0x74, 0x6f, 0x12, 0x04, 0x69, 0x6e, 0x69, 0x74, 0x1a, 0x2d, 0x69, 0x6e, 0x74, 0x65, 0x72,
 	0x6e, 0x61, 0x6c, 0x2f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x63, 0x6f, 0x6d,
 	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e,
-	0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd0, 0x03, 0x0a, 0x0b, 0x49, 0x6e, 0x69,
+	0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x05, 0x0a, 0x0b, 0x49, 0x6e, 0x69,
 	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6b, 0x6d, 0x73, 0x5f,
 	0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6b, 0x6d, 0x73, 0x55, 0x72,
 	0x69, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x75, 0x72, 0x69,
@@ -502,49 +750,129 @@ var file_bootstrapper_initproto_init_proto_rawDesc = []byte{
 	0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x61, 0x70, 0x69, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x43,
 	0x65, 0x72, 0x74, 0x53, 0x61, 0x6e, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x69, 0x64, 0x72, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05,
-	0x52, 0x19, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f,
-	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x22, 0xc1, 0x01, 0x0a, 0x0c,
-	0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c,
-	0x69, 0x6e, 0x69, 0x74, 0x5f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52,
-	0x0b, 0x69, 0x6e, 0x69, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x3e, 0x0a, 0x0c,
-	0x69, 0x6e, 0x69, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x46, 0x61,
-	0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52,
-	0x0b, 0x69, 0x6e, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x29, 0x0a, 0x03,
-	0x6c, 0x6f, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x69, 0x74,
-	0x2e, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x48, 0x00, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22,
-	0x6f, 0x0a, 0x13, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x75, 0x62, 0x65, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6b, 0x75, 0x62, 0x65,
-	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49,
-	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x64,
-	0x22, 0x2b, 0x0a, 0x13, 0x49, 0x6e, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x23, 0x0a,
-	0x0f, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6c,
-	0x6f, 0x67, 0x22, 0x78, 0x0a, 0x13, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73,
-	0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x68,
-	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12,
-	0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x50, 0x61,
-	0x74, 0x68, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x32, 0x36, 0x0a, 0x03,
-	0x41, 0x50, 0x49, 0x12, 0x2f, 0x0a, 0x04, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x11, 0x2e, 0x69, 0x6e,
-	0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12,
-	0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x30, 0x01, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x73, 0x79, 0x73, 0x2f, 0x63,
-	0x6f, 0x6e, 0x73, 0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f,
-	0x62, 0x6f, 0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x69,
-	0x74, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x69, 0x64, 0x72, 0x12, 0x5f, 0x0a, 0x1b, 0x6b, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f,
+	0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1f, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65,
+	0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73,
+	0x52, 0x19, 0x6b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x64,
+	0x69, 0x73, 0x6b, 0x5f, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x64, 0x69,
+	0x73, 0x6b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x6f, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x64, 0x43, 0x69, 0x64, 0x72, 0x4a, 0x04,
+	0x08, 0x04, 0x10, 0x05, 0x52, 0x19, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x22,
+	0xe4, 0x06, 0x0a, 0x19, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x4b, 0x0a,
+	0x22, 0x61, 0x70, 0x69, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x6c, 0x75, 0x67,
+	0x69, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x1f, 0x61, 0x70, 0x69, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x72, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x4d, 0x0a, 0x23, 0x61, 0x70,
+	0x69, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f,
+	0x61, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x20, 0x61, 0x70, 0x69, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x37, 0x0a, 0x0e, 0x61, 0x70, 0x69,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x6f, 0x69, 0x64, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4f, 0x49, 0x44, 0x43, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x52, 0x0d, 0x61, 0x70, 0x69, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4f, 0x69,
+	0x64, 0x63, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x75, 0x64, 0x69, 0x74, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x61, 0x75, 0x64, 0x69, 0x74, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x6c, 0x0a, 0x15, 0x6b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74,
+	0x5f, 0x65, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x61, 0x72, 0x64, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4b, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72,
+	0x72, 0x69, 0x64, 0x65, 0x73, 0x2e, 0x4b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13,
+	0x6b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48,
+	0x61, 0x72, 0x64, 0x12, 0x6c, 0x0a, 0x15, 0x6b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x5f, 0x65,
+	0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x6f, 0x66, 0x74, 0x18, 0x06, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x38, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x65, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x73, 0x2e, 0x4b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x66, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x6b, 0x75,
+	0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x66,
+	0x74, 0x12, 0x8f, 0x01, 0x0a, 0x22, 0x6b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x5f, 0x65, 0x76,
+	0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x6f, 0x66, 0x74, 0x5f, 0x67, 0x72, 0x61, 0x63,
+	0x65, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x43,
+	0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x2e,
+	0x4b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x6f, 0x66, 0x74, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x1e, 0x6b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x66, 0x74, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x1a, 0x46, 0x0a, 0x18, 0x4b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76,
+	0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x61, 0x72, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x46, 0x0a, 0x18, 0x4b,
+	0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f,
+	0x66, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x51, 0x0a, 0x23, 0x4b, 0x75, 0x62, 0x65, 0x6c, 0x65, 0x74, 0x45, 0x76,
+	0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x6f, 0x66, 0x74, 0x47, 0x72, 0x61, 0x63, 0x65, 0x50,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xe0, 0x01, 0x0a, 0x0a, 0x4f, 0x49, 0x44, 0x43, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x69, 0x73, 0x73, 0x75, 0x65,
+	0x72, 0x55, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49,
+	0x64, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6c,
+	0x61, 0x69, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x12, 0x21, 0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x5f, 0x63, 0x6c, 0x61, 0x69,
+	0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x43,
+	0x6c, 0x61, 0x69, 0x6d, 0x12, 0x23, 0x0a, 0x0d, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0xc1, 0x01, 0x0a, 0x0c, 0x49, 0x6e,
+	0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x69, 0x6e,
+	0x69, 0x74, 0x5f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x69,
+	0x6e, 0x69, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x3e, 0x0a, 0x0c, 0x69, 0x6e,
+	0x69, 0x74, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x69,
+	0x6e, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x6c, 0x6f,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x69, 0x6e, 0x69, 0x74, 0x2e, 0x4c,
+	0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00,
+	0x52, 0x03, 0x6c, 0x6f, 0x67, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x6f, 0x0a,
+	0x13, 0x49, 0x6e, 0x69, 0x74, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6b, 0x75, 0x62, 0x65, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6b, 0x75, 0x62, 0x65, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x64, 0x22, 0x2b,
+	0x0a, 0x13, 0x49, 0x6e, 0x69, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x23, 0x0a, 0x0f, 0x4c,
+	0x6f, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6c, 0x6f, 0x67,
+	0x22, 0x78, 0x0a, 0x13, 0x4b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x43, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73,
+	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x50, 0x61, 0x74, 0x68,
+	0x12, 0x18, 0x0a, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x65, 0x78, 0x74, 0x72, 0x61, 0x63, 0x74, 0x32, 0x36, 0x0a, 0x03, 0x41, 0x50,
+	0x49, 0x12, 0x2f, 0x0a, 0x04, 0x49, 0x6e, 0x69, 0x74, 0x12, 0x11, 0x2e, 0x69, 0x6e, 0x69, 0x74,
+	0x2e, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x69,
+	0x6e, 0x69, 0x74, 0x2e, 0x49, 0x6e, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x30, 0x01, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x73, 0x79, 0x73, 0x2f, 0x63, 0x6f, 0x6e,
+	0x73, 0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x62, 0x6f,
+	0x6f, 0x74, 0x73, 0x74, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x2f, 0x69, 0x6e, 0x69, 0x74, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -559,28 +887,38 @@ func file_bootstrapper_initproto_init_proto_rawDescGZIP() []byte {
 	return file_bootstrapper_initproto_init_proto_rawDescData
 }
 
-var file_bootstrapper_initproto_init_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_bootstrapper_initproto_init_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_bootstrapper_initproto_init_proto_goTypes = []interface{}{
-	(*InitRequest)(nil),          // 0: init.InitRequest
-	(*InitResponse)(nil),         // 1: init.InitResponse
-	(*InitSuccessResponse)(nil),  // 2: init.InitSuccessResponse
-	(*InitFailureResponse)(nil),  // 3: init.InitFailureResponse
-	(*LogResponseType)(nil),      // 4: init.LogResponseType
-	(*KubernetesComponent)(nil),  // 5: init.KubernetesComponent
-	(*components.Component)(nil), // 6: components.Component
+	(*InitRequest)(nil),               // 0: init.InitRequest
+	(*KubernetesConfigOverrides)(nil), // 1: init.KubernetesConfigOverrides
+	(*OIDCConfig)(nil),                // 2: init.OIDCConfig
+	(*InitResponse)(nil),              // 3: init.InitResponse
+	(*InitSuccessResponse)(nil),       // 4: init.InitSuccessResponse
+	(*InitFailureResponse)(nil),       // 5: init.InitFailureResponse
+	(*LogResponseType)(nil),           // 6: init.LogResponseType
+	(*KubernetesComponent)(nil),       // 7: init.KubernetesComponent
+	nil,                               // 8: init.KubernetesConfigOverrides.KubeletEvictionHardEntry
+	nil,                               // 9: init.KubernetesConfigOverrides.KubeletEvictionSoftEntry
+	nil,                               // 10: init.KubernetesConfigOverrides.KubeletEvictionSoftGracePeriodEntry
+	(*components.Component)(nil),      // 11: components.Component
 }
 var file_bootstrapper_initproto_init_proto_depIdxs = []int32{
-	6, // 0: init.InitRequest.kubernetes_components:type_name -> components.Component
-	2, // 1: init.InitResponse.init_success:type_name -> init.InitSuccessResponse
-	3, // 2: init.InitResponse.init_failure:type_name -> init.InitFailureResponse
-	4, // 3: init.InitResponse.log:type_name -> init.LogResponseType
-	0, // 4: init.API.Init:input_type -> init.InitRequest
-	1, // 5: init.API.Init:output_type -> init.InitResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	11, // 0: init.InitRequest.kubernetes_components:type_name -> components.Component
+	1,  // 1: init.InitRequest.kubernetes_config_overrides:type_name -> init.KubernetesConfigOverrides
+	2,  // 2: init.KubernetesConfigOverrides.apiserver_oidc:type_name -> init.OIDCConfig
+	8,  // 3: init.KubernetesConfigOverrides.kubelet_eviction_hard:type_name -> init.KubernetesConfigOverrides.KubeletEvictionHardEntry
+	9,  // 4: init.KubernetesConfigOverrides.kubelet_eviction_soft:type_name -> init.KubernetesConfigOverrides.KubeletEvictionSoftEntry
+	10, // 5: init.KubernetesConfigOverrides.kubelet_eviction_soft_grace_period:type_name -> init.KubernetesConfigOverrides.KubeletEvictionSoftGracePeriodEntry
+	4,  // 6: init.InitResponse.init_success:type_name -> init.InitSuccessResponse
+	5,  // 7: init.InitResponse.init_failure:type_name -> init.InitFailureResponse
+	6,  // 8: init.InitResponse.log:type_name -> init.LogResponseType
+	0,  // 9: init.API.Init:input_type -> init.InitRequest
+	3,  // 10: init.API.Init:output_type -> init.InitResponse
+	10, // [10:11] is the sub-list for method output_type
+	9,  // [9:10] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_bootstrapper_initproto_init_proto_init() }
@@ -602,7 +940,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 		file_bootstrapper_initproto_init_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitResponse); i {
+			switch v := v.(*KubernetesConfigOverrides); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -614,7 +952,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 		file_bootstrapper_initproto_init_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitSuccessResponse); i {
+			switch v := v.(*OIDCConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -626,7 +964,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 		file_bootstrapper_initproto_init_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*InitFailureResponse); i {
+			switch v := v.(*InitResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -638,7 +976,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 		file_bootstrapper_initproto_init_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LogResponseType); i {
+			switch v := v.(*InitSuccessResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -650,6 +988,30 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 		file_bootstrapper_initproto_init_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InitFailureResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bootstrapper_initproto_init_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LogResponseType); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_bootstrapper_initproto_init_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*KubernetesComponent); i {
 			case 0:
 				return &v.state
@@ -662,7 +1024,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			}
 		}
 	}
-	file_bootstrapper_initproto_init_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_bootstrapper_initproto_init_proto_msgTypes[3].OneofWrappers = []interface{}{
 		(*InitResponse_InitSuccess)(nil),
 		(*InitResponse_InitFailure)(nil),
 		(*InitResponse_Log)(nil),
@@ -673,7 +1035,7 @@ func file_bootstrapper_initproto_init_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_bootstrapper_initproto_init_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
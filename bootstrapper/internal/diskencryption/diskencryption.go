@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/edgelesssys/constellation/v2/internal/cryptsetup"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/spf13/afero"
 )
 
@@ -46,8 +47,9 @@ func (c *DiskEncryption) UUID() (string, error) {
 }
 
 // UpdatePassphrase switches the initial random passphrase of the mapped crypt device to a permanent passphrase.
+// The version of the permanent passphrase is recorded in the disk's token, to allow later key rotations.
 // Only works after calling Open().
-func (c *DiskEncryption) UpdatePassphrase(passphrase string) error {
+func (c *DiskEncryption) UpdatePassphrase(passphrase string, keyVersion uint32) error {
 	initialPassphrase, err := c.getInitialPassphrase()
 	if err != nil {
 		return err
@@ -55,11 +57,28 @@ func (c *DiskEncryption) UpdatePassphrase(passphrase string) error {
 	if err := c.device.KeyslotChangeByPassphrase(keyslot, keyslot, initialPassphrase, passphrase); err != nil {
 		return err
 	}
+	if err := c.device.SetConstellationStateDiskKeyVersion(keyVersion); err != nil {
+		return err
+	}
 
 	// Set token as initialized.
 	return c.device.SetConstellationStateDiskToken(cryptsetup.SetDiskInitialized)
 }
 
+// VerifyProfile checks that the disk is mapped with the parameters of the encryption profile with the given name.
+// The parameters are read from the active device, the profile name recorded in the disk's token is only used in the error message.
+// Only works after calling Open().
+func (c *DiskEncryption) VerifyProfile(name string) error {
+	params, err := c.device.EncryptionParameters()
+	if err != nil {
+		return err
+	}
+	if err := profile.Verify(name, params); err != nil {
+		return fmt.Errorf("state disk (token reports profile %q): %w", c.device.ConstellationStateDiskProfile(), err)
+	}
+	return nil
+}
+
 // getInitialPassphrase retrieves the initial passphrase used on first boot.
 func (c *DiskEncryption) getInitialPassphrase() (string, error) {
 	passphrase, err := afero.ReadFile(c.fs, initialKeyPath)
@@ -74,4 +93,7 @@ type cryptdevice interface {
 	GetUUID() (string, error)
 	KeyslotChangeByPassphrase(currentKeyslot int, newKeyslot int, currentPassphrase string, newPassphrase string) error
 	SetConstellationStateDiskToken(bool) error
+	SetConstellationStateDiskKeyVersion(uint32) error
+	ConstellationStateDiskProfile() string
+	EncryptionParameters() (profile.Profile, error)
 }
@@ -11,6 +11,7 @@ import (
 	"path"
 	"testing"
 
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,7 @@ func TestUpdatePassphrase(t *testing.T) {
 		writePassphrase              bool
 		open                         bool
 		keyslotChangeByPassphraseErr error
+		setKeyVersionErr             error
 		wantErr                      bool
 	}{
 		"updating passphrase works": {
@@ -42,6 +44,12 @@ func TestUpdatePassphrase(t *testing.T) {
 			keyslotChangeByPassphraseErr: errors.New("keyslotChangeByPassphraseErr"),
 			wantErr:                      true,
 		},
+		"setting key version can fail": {
+			open:             true,
+			writePassphrase:  true,
+			setKeyVersionErr: errors.New("setKeyVersionErr"),
+			wantErr:          true,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -55,17 +63,66 @@ func TestUpdatePassphrase(t *testing.T) {
 				require.NoError(afero.WriteFile(fs, initialKeyPath, []byte("key"), 0o777))
 			}
 
+			device := &stubCryptdevice{
+				keyslotChangeErr: tc.keyslotChangeByPassphraseErr,
+				setKeyVersionErr: tc.setKeyVersionErr,
+			}
 			crypt := DiskEncryption{
 				fs:     fs,
-				device: &stubCryptdevice{keyslotChangeErr: tc.keyslotChangeByPassphraseErr},
+				device: device,
 			}
 
-			err := crypt.UpdatePassphrase("new-key")
+			err := crypt.UpdatePassphrase("new-key", 2)
 			if tc.wantErr {
 				assert.Error(err)
 				return
 			}
 			require.NoError(err)
+			assert.Equal(uint32(2), device.keyVersion)
+		})
+	}
+}
+
+func TestVerifyProfile(t *testing.T) {
+	aesXTS, err := profile.Get(profile.AESXTS)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		device  *stubCryptdevice
+		profile string
+		wantErr bool
+	}{
+		"parameters match profile": {
+			device:  &stubCryptdevice{params: aesXTS},
+			profile: profile.AESXTS,
+		},
+		"parameters don't match profile": {
+			device:  &stubCryptdevice{params: aesXTS},
+			profile: profile.AESXTSHMACSHA256,
+			wantErr: true,
+		},
+		"token is ignored": {
+			device:  &stubCryptdevice{profile: profile.AESXTSHMACSHA256, params: aesXTS},
+			profile: profile.AESXTSHMACSHA256,
+			wantErr: true,
+		},
+		"reading parameters fails": {
+			device:  &stubCryptdevice{paramsErr: errors.New("failed")},
+			profile: profile.AESXTS,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			crypt := DiskEncryption{device: tc.device}
+
+			err := crypt.VerifyProfile(tc.profile)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
 		})
 	}
 }
@@ -74,6 +131,11 @@ type stubCryptdevice struct {
 	uuid             string
 	uuidErr          error
 	keyslotChangeErr error
+	keyVersion       uint32
+	setKeyVersionErr error
+	profile          string
+	params           profile.Profile
+	paramsErr        error
 }
 
 func (s *stubCryptdevice) InitByName(_ string) (func(), error) {
@@ -91,3 +153,16 @@ func (s *stubCryptdevice) KeyslotChangeByPassphrase(_, _ int, _, _ string) error
 func (s *stubCryptdevice) SetConstellationStateDiskToken(bool) error {
 	return nil
 }
+
+func (s *stubCryptdevice) SetConstellationStateDiskKeyVersion(version uint32) error {
+	s.keyVersion = version
+	return s.setKeyVersionErr
+}
+
+func (s *stubCryptdevice) ConstellationStateDiskProfile() string {
+	return s.profile
+}
+
+func (s *stubCryptdevice) EncryptionParameters() (profile.Profile, error) {
+	return s.params, s.paramsErr
+}
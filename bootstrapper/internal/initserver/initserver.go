@@ -189,7 +189,7 @@ func (s *Server) Init(req *initproto.InitRequest, stream initproto.API_InitServe
 	// Any errors following this call will result in a failed node that may not join any cluster.
 	s.cleaner.Clean()
 
-	if err := s.setupDisk(stream.Context(), cloudKms); err != nil {
+	if err := s.setupDisk(stream.Context(), cloudKms, req.DiskEncryptionProfile); err != nil {
 		if e := s.sendLogsWithMessage(stream, status.Errorf(codes.Internal, "setting up disk: %s", err)); e != nil {
 			err = errors.Join(err, e)
 		}
@@ -219,6 +219,9 @@ func (s *Server) Init(req *initproto.InitRequest, stream initproto.API_InitServe
 		req.KubernetesComponents,
 		req.ApiserverCertSans,
 		req.ServiceCidr,
+		req.PodCidr,
+		req.KubernetesConfigOverrides,
+		req.DiskEncryptionProfile,
 		s.log,
 	)
 	if err != nil {
@@ -297,25 +300,32 @@ func (s *Server) Stop() {
 	s.log.Infof("Stopped")
 }
 
-func (s *Server) setupDisk(ctx context.Context, cloudKms kms.CloudKMS) error {
+func (s *Server) setupDisk(ctx context.Context, cloudKms kms.CloudKMS, diskEncryptionProfile string) error {
 	free, err := s.disk.Open()
 	if err != nil {
 		return fmt.Errorf("opening encrypted disk: %w", err)
 	}
 	defer free()
 
+	// The profile of the disk is chosen based on untrusted instance metadata.
+	// Make sure the disk is mapped with the parameters of the profile requested by the user before storing any secrets on the disk.
+	if err := s.disk.VerifyProfile(diskEncryptionProfile); err != nil {
+		return fmt.Errorf("verifying disk encryption profile %q: %w", diskEncryptionProfile, err)
+	}
+
 	uuid, err := s.disk.UUID()
 	if err != nil {
 		return fmt.Errorf("retrieving uuid of disk: %w", err)
 	}
 	uuid = strings.ToLower(uuid)
 
-	diskKey, err := cloudKms.GetDEK(ctx, crypto.DEKPrefix+uuid, crypto.StateDiskKeyLength)
+	// The first control plane node always starts with the initial key version.
+	diskKey, err := cloudKms.GetDEK(ctx, crypto.DEKPrefix+crypto.StateDiskKeyID(uuid, 0), crypto.StateDiskKeyLength)
 	if err != nil {
 		return err
 	}
 
-	return s.disk.UpdatePassphrase(string(diskKey))
+	return s.disk.UpdatePassphrase(string(diskKey), 0)
 }
 
 func deriveMeasurementValues(ctx context.Context, measurementSalt []byte, cloudKms kms.CloudKMS) (clusterID []byte, err error) {
@@ -342,6 +352,9 @@ type ClusterInitializer interface {
 		kubernetesComponents components.Components,
 		apiServerCertSANs []string,
 		serviceCIDR string,
+		podCIDR string,
+		configOverrides *initproto.KubernetesConfigOverrides,
+		diskEncryptionProfile string,
 		log *logger.Logger,
 	) ([]byte, error)
 }
@@ -351,8 +364,11 @@ type encryptedDisk interface {
 	Open() (free func(), err error)
 	// UUID gets the device's UUID.
 	UUID() (string, error)
-	// UpdatePassphrase switches the initial random passphrase of the encrypted disk to a permanent passphrase.
-	UpdatePassphrase(passphrase string) error
+	// UpdatePassphrase switches the initial random passphrase of the encrypted disk to a permanent passphrase
+	// with the given key version.
+	UpdatePassphrase(passphrase string, keyVersion uint32) error
+	// VerifyProfile checks that the disk is mapped with the parameters of the given encryption profile.
+	VerifyProfile(name string) error
 }
 
 type serveStopper interface {
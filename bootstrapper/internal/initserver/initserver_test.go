@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"strings"
@@ -21,6 +22,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/crypto/testvector"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	kmssetup "github.com/edgelesssys/constellation/v2/internal/kms/setup"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
@@ -166,6 +168,18 @@ func TestInit(t *testing.T) {
 			wantErr:        true,
 			wantShutdown:   true,
 		},
+		"disk encryption profile mismatch": {
+			nodeLock:       newFakeLock(),
+			initializer:    &stubClusterInitializer{},
+			disk:           &stubDisk{profile: "aes-xts"},
+			fileHandler:    file.NewHandler(afero.NewMemMapFs()),
+			req:            &initproto.InitRequest{InitSecret: initSecret, KmsUri: masterSecret.EncodeToURI(), StorageUri: uri.NoStoreURI, DiskEncryptionProfile: "aes-xts-512"},
+			stream:         stubStream{},
+			logCollector:   stubJournaldCollector{logPipe: &stubReadCloser{reader: bytes.NewReader([]byte{})}},
+			initSecretHash: initSecretHash,
+			wantErr:        true,
+			wantShutdown:   true,
+		},
 		"write state file error": {
 			nodeLock:       newFakeLock(),
 			initializer:    &stubClusterInitializer{},
@@ -350,7 +364,7 @@ func TestSetupDisk(t *testing.T) {
 
 			cloudKms, err := kmssetup.KMS(context.Background(), uri.NoStoreURI, masterSecret.EncodeToURI())
 			require.NoError(err)
-			assert.NoError(server.setupDisk(context.Background(), cloudKms))
+			assert.NoError(server.setupDisk(context.Background(), cloudKms, ""))
 		})
 	}
 }
@@ -372,19 +386,24 @@ func (d *fakeDisk) UUID() (string, error) {
 	return d.uuid, nil
 }
 
-func (d *fakeDisk) UpdatePassphrase(passphrase string) error {
+func (d *fakeDisk) UpdatePassphrase(passphrase string, _ uint32) error {
 	if passphrase != string(d.wantKey) {
 		return errors.New("wrong passphrase")
 	}
 	return nil
 }
 
+func (d *fakeDisk) VerifyProfile(string) error {
+	return nil
+}
+
 type stubDisk struct {
 	openErr                error
 	uuid                   string
 	uuidErr                error
 	updatePassphraseErr    error
 	updatePassphraseCalled bool
+	profile                string
 }
 
 func (d *stubDisk) Open() (func(), error) {
@@ -395,11 +414,18 @@ func (d *stubDisk) UUID() (string, error) {
 	return d.uuid, d.uuidErr
 }
 
-func (d *stubDisk) UpdatePassphrase(string) error {
+func (d *stubDisk) UpdatePassphrase(string, uint32) error {
 	d.updatePassphraseCalled = true
 	return d.updatePassphraseErr
 }
 
+func (d *stubDisk) VerifyProfile(name string) error {
+	if !profile.Equal(d.profile, name) {
+		return fmt.Errorf("disk is mapped with profile %q", d.profile)
+	}
+	return nil
+}
+
 type stubClusterInitializer struct {
 	initClusterKubeconfig []byte
 	initClusterErr        error
@@ -407,7 +433,7 @@ type stubClusterInitializer struct {
 
 func (i *stubClusterInitializer) InitCluster(
 	context.Context, string, string,
-	bool, components.Components, []string, string, *logger.Logger,
+	bool, components.Components, []string, string, string, *initproto.KubernetesConfigOverrides, string, *logger.Logger,
 ) ([]byte, error) {
 	return i.initClusterKubeconfig, i.initClusterErr
 }
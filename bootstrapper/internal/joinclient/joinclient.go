@@ -29,6 +29,7 @@ import (
 
 	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/certificate"
 	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/diskencryption"
+	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
 	"github.com/edgelesssys/constellation/v2/internal/attestation"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/metadata"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
@@ -281,7 +282,7 @@ func (c *JoinClient) startNodeAndJoin(ticket *joinproto.IssueJoinTicketResponse,
 
 	c.cleaner.Clean()
 
-	if err := c.updateDiskPassphrase(string(ticket.StateDiskKey)); err != nil {
+	if err := c.updateDiskPassphrase(string(ticket.StateDiskKey), ticket.StateDiskKeyVersion, ticket.DiskEncryptionProfile); err != nil {
 		return fmt.Errorf("updating disk passphrase: %w", err)
 	}
 
@@ -311,7 +312,11 @@ func (c *JoinClient) startNodeAndJoin(ticket *joinproto.IssueJoinTicketResponse,
 		CACertHashes:      []string{ticket.DiscoveryTokenCaCertHash},
 	}
 
-	if err := c.joiner.JoinCluster(ctx, btd, c.role, ticket.KubernetesComponents, c.log); err != nil {
+	configOverrides := k8sapi.ConfigOverrides{
+		AuditPolicy:        ticket.AuditPolicy,
+		KubeletConfigPatch: ticket.KubeletConfigPatch,
+	}
+	if err := c.joiner.JoinCluster(ctx, btd, c.role, ticket.KubernetesComponents, configOverrides, c.log); err != nil {
 		return fmt.Errorf("joining Kubernetes cluster: %w", err)
 	}
 
@@ -350,13 +355,19 @@ func (c *JoinClient) getNodeMetadata() error {
 	return nil
 }
 
-func (c *JoinClient) updateDiskPassphrase(passphrase string) error {
+func (c *JoinClient) updateDiskPassphrase(passphrase string, keyVersion uint32, diskEncryptionProfile string) error {
 	free, err := c.disk.Open()
 	if err != nil {
 		return fmt.Errorf("opening disk: %w", err)
 	}
 	defer free()
-	return c.disk.UpdatePassphrase(passphrase)
+
+	// The profile of the disk is chosen based on untrusted instance metadata.
+	// Make sure the disk is mapped with the parameters of the profile of the cluster before storing any secrets on the disk.
+	if err := c.disk.VerifyProfile(diskEncryptionProfile); err != nil {
+		return fmt.Errorf("verifying disk encryption profile %q: %w", diskEncryptionProfile, err)
+	}
+	return c.disk.UpdatePassphrase(passphrase, keyVersion)
 }
 
 func (c *JoinClient) getDiskUUID() (string, error) {
@@ -423,6 +434,7 @@ type ClusterJoiner interface {
 		args *kubeadm.BootstrapTokenDiscovery,
 		peerRole role.Role,
 		k8sComponents components.Components,
+		configOverrides k8sapi.ConfigOverrides,
 		log *logger.Logger,
 	) error
 }
@@ -442,8 +454,11 @@ type encryptedDisk interface {
 	Open() (func(), error)
 	// UUID gets the device's UUID.
 	UUID() (string, error)
-	// UpdatePassphrase switches the initial random passphrase of the encrypted disk to a permanent passphrase.
-	UpdatePassphrase(passphrase string) error
+	// UpdatePassphrase switches the initial random passphrase of the encrypted disk to a permanent passphrase
+	// with the given key version.
+	UpdatePassphrase(passphrase string, keyVersion uint32) error
+	// VerifyProfile checks that the disk is mapped with the parameters of the given encryption profile.
+	VerifyProfile(name string) error
 }
 
 type cleaner interface {
@@ -9,14 +9,17 @@ package joinclient
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/metadata"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/atlscredentials"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
@@ -185,6 +188,31 @@ func TestClient(t *testing.T) {
 			disk:          &stubDisk{},
 			wantLock:      true,
 		},
+		"on control plane: disk encryption profile matches": {
+			role: role.ControlPlane,
+			apiAnswers: []any{
+				selfAnswer{instance: controlSelf},
+				listAnswer{instances: peers},
+				issueJoinTicketAnswer{resp: &joinproto.IssueJoinTicketResponse{DiskEncryptionProfile: "aes-xts"}},
+			},
+			clusterJoiner: &stubClusterJoiner{},
+			nodeLock:      newFakeLock(),
+			disk:          &stubDisk{profile: "aes-xts"},
+			wantJoin:      true,
+			wantLock:      true,
+		},
+		"on control plane: disk encryption profile mismatch": {
+			role: role.ControlPlane,
+			apiAnswers: []any{
+				selfAnswer{instance: controlSelf},
+				listAnswer{instances: peers},
+				issueJoinTicketAnswer{},
+			},
+			clusterJoiner: &stubClusterJoiner{},
+			nodeLock:      newFakeLock(),
+			disk:          &stubDisk{profile: "aes-xts"},
+			wantLock:      true,
+		},
 		"on control plane: disk open fails": {
 			role:          role.ControlPlane,
 			clusterJoiner: &stubClusterJoiner{},
@@ -401,7 +429,7 @@ type stubClusterJoiner struct {
 	joinClusterErr    error
 }
 
-func (j *stubClusterJoiner) JoinCluster(context.Context, *kubeadm.BootstrapTokenDiscovery, role.Role, components.Components, *logger.Logger) error {
+func (j *stubClusterJoiner) JoinCluster(context.Context, *kubeadm.BootstrapTokenDiscovery, role.Role, components.Components, k8sapi.ConfigOverrides, *logger.Logger) error {
 	j.joinClusterCalled = true
 	return j.joinClusterErr
 }
@@ -412,6 +440,7 @@ type stubDisk struct {
 	uuidErr                error
 	updatePassphraseErr    error
 	updatePassphraseCalled bool
+	profile                string
 }
 
 func (d *stubDisk) Open() (func(), error) {
@@ -422,11 +451,18 @@ func (d *stubDisk) UUID() (string, error) {
 	return d.uuid, d.uuidErr
 }
 
-func (d *stubDisk) UpdatePassphrase(string) error {
+func (d *stubDisk) UpdatePassphrase(string, uint32) error {
 	d.updatePassphraseCalled = true
 	return d.updatePassphraseErr
 }
 
+func (d *stubDisk) VerifyProfile(name string) error {
+	if !profile.Equal(d.profile, name) {
+		return fmt.Errorf("disk is mapped with profile %q", d.profile)
+	}
+	return nil
+}
+
 type stubCleaner struct{}
 
 func (c stubCleaner) Clean() {}
@@ -20,6 +20,8 @@ type ProviderMetadata interface {
 	Self(ctx context.Context) (metadata.InstanceMetadata, error)
 	// GetLoadBalancerEndpoint retrieves the load balancer endpoint.
 	GetLoadBalancerEndpoint(ctx context.Context) (host, port string, err error)
+	// NodeRegistration retrieves the encoded labels and taints the kubelet registers the node with.
+	NodeRegistration(ctx context.Context) (labels, taints string, err error)
 }
 
 type stubProviderMetadata struct {
@@ -31,6 +33,9 @@ type stubProviderMetadata struct {
 
 	uidErr  error
 	uidResp string
+
+	nodeRegistrationErr                            error
+	nodeRegistrationLabels, nodeRegistrationTaints string
 }
 
 func (m *stubProviderMetadata) GetLoadBalancerEndpoint(_ context.Context) (string, string, error) {
@@ -44,3 +49,7 @@ func (m *stubProviderMetadata) Self(_ context.Context) (metadata.InstanceMetadat
 func (m *stubProviderMetadata) UID(_ context.Context) (string, error) {
 	return m.uidResp, m.uidErr
 }
+
+func (m *stubProviderMetadata) NodeRegistration(_ context.Context) (string, string, error) {
+	return m.nodeRegistrationLabels, m.nodeRegistrationTaints, m.nodeRegistrationErr
+}
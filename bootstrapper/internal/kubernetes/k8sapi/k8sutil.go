@@ -87,15 +87,10 @@ func (k *KubernetesUtil) InstallComponents(ctx context.Context, kubernetesCompon
 // InitCluster instruments kubeadm to initialize the K8s cluster.
 // On success an admin kubeconfig file is returned.
 func (k *KubernetesUtil) InitCluster(
-	ctx context.Context, initConfig []byte, nodeName, clusterName string, ips []net.IP, conformanceMode bool, log *logger.Logger,
+	ctx context.Context, initConfig []byte, nodeName, clusterName string, ips []net.IP, conformanceMode bool, overrides ConfigOverrides, log *logger.Logger,
 ) ([]byte, error) {
-	// TODO(3u13r): audit policy should be user input
-	auditPolicy, err := resources.NewDefaultAuditPolicy().Marshal()
-	if err != nil {
-		return nil, fmt.Errorf("generating default audit policy: %w", err)
-	}
-	if err := os.WriteFile(auditPolicyPath, auditPolicy, 0o644); err != nil {
-		return nil, fmt.Errorf("writing default audit policy: %w", err)
+	if err := writeConfigOverrides(overrides); err != nil {
+		return nil, err
 	}
 
 	initConfigFile, err := os.CreateTemp("", "kubeadm-init.*.yaml")
@@ -181,14 +176,9 @@ func (k *KubernetesUtil) InitCluster(
 }
 
 // JoinCluster joins existing Kubernetes cluster using kubeadm join.
-func (k *KubernetesUtil) JoinCluster(ctx context.Context, joinConfig []byte, log *logger.Logger) error {
-	// TODO(3u13r): audit policy should be user input
-	auditPolicy, err := resources.NewDefaultAuditPolicy().Marshal()
-	if err != nil {
-		return fmt.Errorf("generating default audit policy: %w", err)
-	}
-	if err := os.WriteFile(auditPolicyPath, auditPolicy, 0o644); err != nil {
-		return fmt.Errorf("writing default audit policy: %w", err)
+func (k *KubernetesUtil) JoinCluster(ctx context.Context, joinConfig []byte, overrides ConfigOverrides, log *logger.Logger) error {
+	if err := writeConfigOverrides(overrides); err != nil {
+		return err
 	}
 
 	joinConfigFile, err := os.CreateTemp("", "kubeadm-join.*.yaml")
@@ -221,6 +211,33 @@ func (k *KubernetesUtil) JoinCluster(ctx context.Context, joinConfig []byte, log
 	return nil
 }
 
+// writeConfigOverrides writes the audit policy of the API server, falling back to the default policy,
+// and the kubeadm patch of the kubelet configuration to the node.
+func writeConfigOverrides(overrides ConfigOverrides) error {
+	auditPolicy := overrides.AuditPolicy
+	if len(auditPolicy) == 0 {
+		var err error
+		auditPolicy, err = resources.NewDefaultAuditPolicy().Marshal()
+		if err != nil {
+			return fmt.Errorf("generating default audit policy: %w", err)
+		}
+	}
+	if err := os.WriteFile(auditPolicyPath, auditPolicy, 0o644); err != nil {
+		return fmt.Errorf("writing audit policy: %w", err)
+	}
+
+	if len(overrides.KubeletConfigPatch) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(constants.KubeadmPatchDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating kubeadm patch directory: %w", err)
+	}
+	if err := os.WriteFile(kubeletConfigPatchPath, overrides.KubeletConfigPatch, 0o644); err != nil {
+		return fmt.Errorf("writing kubelet configuration patch: %w", err)
+	}
+	return nil
+}
+
 // StartKubelet enables and starts the kubelet systemd unit.
 func (k *KubernetesUtil) StartKubelet() error {
 	ctx, cancel := context.WithTimeout(context.TODO(), kubeletStartTimeout)
@@ -28,6 +28,18 @@ const (
 	auditPolicyPath = "/etc/kubernetes/audit-policy.yaml"
 )
 
+// kubeletConfigPatchPath is the kubeadm patch file for the KubeletConfiguration.
+// See https://kubernetes.io/docs/setup/production-environment/tools/kubeadm/control-plane-flags/#patches.
+var kubeletConfigPatchPath = filepath.Join(constants.KubeadmPatchDir, "kubeletconfiguration+strategic.json")
+
+// ConfigOverrides are user supplied files that replace or patch parts of the generated Kubernetes configuration of a node.
+type ConfigOverrides struct {
+	// AuditPolicy replaces the default audit policy of the API server, if set.
+	AuditPolicy []byte
+	// KubeletConfigPatch is a strategic merge patch applied to the kubelet configuration, if set.
+	KubeletConfigPatch []byte
+}
+
 // KubdeadmConfiguration is used to generate kubeadm configurations.
 type KubdeadmConfiguration struct{}
 
@@ -226,6 +238,31 @@ func (k *KubeadmJoinYAML) SetNodeIP(nodeIP string) {
 	}
 }
 
+// SetNodeLabels sets the labels the kubelet registers the node with.
+func (k *KubeadmJoinYAML) SetNodeLabels(labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if k.JoinConfiguration.NodeRegistration.KubeletExtraArgs == nil {
+		k.JoinConfiguration.NodeRegistration.KubeletExtraArgs = map[string]string{}
+	}
+	k.JoinConfiguration.NodeRegistration.KubeletExtraArgs["node-labels"] = kubernetes.KubeletNodeLabels(labels)
+}
+
+// SetNodeTaints sets the taints the node is registered with.
+// kubeadm only taints control plane nodes by default if no taints are set,
+// so the control plane taint is added explicitly if the node joins as control plane.
+// SetControlPlane must be called before for control plane nodes.
+func (k *KubeadmJoinYAML) SetNodeTaints(taints []corev1.Taint) {
+	if len(taints) == 0 {
+		return
+	}
+	if k.JoinConfiguration.ControlPlane != nil {
+		taints = append([]corev1.Taint{kubeconstants.ControlPlaneTaint}, taints...)
+	}
+	k.JoinConfiguration.NodeRegistration.Taints = taints
+}
+
 // SetProviderID sets the provider ID.
 func (k *KubeadmJoinYAML) SetProviderID(providerID string) {
 	k.KubeletConfiguration.ProviderID = providerID
@@ -291,6 +328,27 @@ func (k *KubeadmInitYAML) SetNodeIP(nodeIP string) {
 	}
 }
 
+// SetNodeLabels sets the labels the kubelet registers the node with.
+func (k *KubeadmInitYAML) SetNodeLabels(labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	if k.InitConfiguration.NodeRegistration.KubeletExtraArgs == nil {
+		k.InitConfiguration.NodeRegistration.KubeletExtraArgs = map[string]string{}
+	}
+	k.InitConfiguration.NodeRegistration.KubeletExtraArgs["node-labels"] = kubernetes.KubeletNodeLabels(labels)
+}
+
+// SetNodeTaints sets the taints the node is registered with.
+// kubeadm only taints the control plane node by default if no taints are set,
+// so the control plane taint is added explicitly.
+func (k *KubeadmInitYAML) SetNodeTaints(taints []corev1.Taint) {
+	if len(taints) == 0 {
+		return
+	}
+	k.InitConfiguration.NodeRegistration.Taints = append([]corev1.Taint{kubeconstants.ControlPlaneTaint}, taints...)
+}
+
 // SetProviderID sets the provider ID.
 func (k *KubeadmInitYAML) SetProviderID(providerID string) {
 	if k.InitConfiguration.NodeRegistration.KubeletExtraArgs == nil {
@@ -300,6 +358,19 @@ func (k *KubeadmInitYAML) SetProviderID(providerID string) {
 	}
 }
 
+// SetAPIServerExtraArgs adds extra arguments to the API server, overwriting existing arguments with the same name.
+func (k *KubeadmInitYAML) SetAPIServerExtraArgs(args map[string]string) {
+	if len(args) == 0 {
+		return
+	}
+	if k.ClusterConfiguration.APIServer.ExtraArgs == nil {
+		k.ClusterConfiguration.APIServer.ExtraArgs = map[string]string{}
+	}
+	for name, value := range args {
+		k.ClusterConfiguration.APIServer.ExtraArgs[name] = value
+	}
+}
+
 // SetServiceSubnet sets the service subnet.
 func (k *KubeadmInitYAML) SetServiceSubnet(subnet string) {
 	if subnet != "" {
@@ -307,6 +378,19 @@ func (k *KubeadmInitYAML) SetServiceSubnet(subnet string) {
 	}
 }
 
+// SetPodSubnet sets the pod subnet of the cluster, if set.
+// Pod IPs are allocated by Cilium, so the controller manager doesn't need to allocate node CIDRs from the pod subnet.
+func (k *KubeadmInitYAML) SetPodSubnet(subnet string) {
+	if subnet == "" {
+		return
+	}
+	k.ClusterConfiguration.Networking.PodSubnet = subnet
+	if k.ClusterConfiguration.ControllerManager.ExtraArgs == nil {
+		k.ClusterConfiguration.ControllerManager.ExtraArgs = map[string]string{}
+	}
+	k.ClusterConfiguration.ControllerManager.ExtraArgs["allocate-node-cidrs"] = "false"
+}
+
 // Marshal into a k8s resource YAML.
 func (k *KubeadmInitYAML) Marshal() ([]byte, error) {
 	return kubernetes.MarshalK8SResources(k)
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	corev1 "k8s.io/api/core/v1"
 	kubeadmUtil "k8s.io/kubernetes/cmd/kubeadm/app/util"
 )
 
@@ -36,6 +37,10 @@ func TestInitConfiguration(t *testing.T) {
 				c.SetNodeIP("192.0.2.0")
 				c.SetNodeName("node")
 				c.SetProviderID("somecloudprovider://instance-id")
+				c.SetNodeLabels(map[string]string{"pool": "default"})
+				c.SetNodeTaints([]corev1.Taint{{Key: "dedicated", Value: "control-plane", Effect: corev1.TaintEffectNoExecute}})
+				c.SetServiceSubnet("10.96.0.0/12,fd00:10:96::/108")
+				c.SetPodSubnet("10.244.0.0/16,fd00:10:244::/56")
 				return c
 			}(),
 		},
@@ -107,6 +112,8 @@ func TestJoinConfiguration(t *testing.T) {
 				c.AppendDiscoveryTokenCaCertHash("discovery-token-ca-cert-hash")
 				c.SetProviderID("somecloudprovider://instance-id")
 				c.SetControlPlane("192.0.2.0")
+				c.SetNodeLabels(map[string]string{"pool": "spot"})
+				c.SetNodeTaints([]corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}})
 				return c
 			}(),
 		},
@@ -10,13 +10,14 @@ import (
 	"context"
 	"net"
 
+	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
 )
 
 type clusterUtil interface {
 	InstallComponents(ctx context.Context, kubernetesComponents components.Components) error
-	InitCluster(ctx context.Context, initConfig []byte, nodeName, clusterName string, ips []net.IP, conformanceMode bool, log *logger.Logger) ([]byte, error)
-	JoinCluster(ctx context.Context, joinConfig []byte, log *logger.Logger) error
+	InitCluster(ctx context.Context, initConfig []byte, nodeName, clusterName string, ips []net.IP, conformanceMode bool, overrides k8sapi.ConfigOverrides, log *logger.Logger) ([]byte, error)
+	JoinCluster(ctx context.Context, joinConfig []byte, overrides k8sapi.ConfigOverrides, log *logger.Logger) error
 	StartKubelet() error
 }
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/edgelesssys/constellation/v2/bootstrapper/initproto"
 	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
 	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/kubewaiter"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
@@ -69,7 +70,8 @@ func New(cloudProvider string, clusterUtil clusterUtil, configProvider configura
 
 // InitCluster initializes a new Kubernetes cluster and applies pod network provider.
 func (k *KubeWrapper) InitCluster(
-	ctx context.Context, versionString, clusterName string, conformanceMode bool, kubernetesComponents components.Components, apiServerCertSANs []string, serviceCIDR string, log *logger.Logger,
+	ctx context.Context, versionString, clusterName string, conformanceMode bool, kubernetesComponents components.Components, apiServerCertSANs []string, serviceCIDR, podCIDR string,
+	configOverrides *initproto.KubernetesConfigOverrides, diskEncryptionProfile string, log *logger.Logger,
 ) ([]byte, error) {
 	log.With(zap.String("version", versionString)).Infof("Installing Kubernetes components")
 	if err := k.clusterUtil.InstallComponents(ctx, kubernetesComponents); err != nil {
@@ -94,6 +96,10 @@ func (k *KubeWrapper) InitCluster(
 
 	nodeIP := instance.VPCIP
 	subnetworkPodCIDR := instance.SecondaryIPRange
+	nodeLabels, nodeTaints, err := k.nodeRegistration(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// this is the endpoint in "kubeadm init --control-plane-endpoint=<IP/DNS>:<port>"
 	// TODO(malt3): switch over to DNS name on AWS and Azure
@@ -127,14 +133,22 @@ func (k *KubeWrapper) InitCluster(
 	initConfig.SetCertSANs(certSANs)
 	initConfig.SetNodeName(nodeName)
 	initConfig.SetProviderID(instance.ProviderID)
+	initConfig.SetNodeLabels(nodeLabels)
+	initConfig.SetNodeTaints(nodeTaints)
 	initConfig.SetControlPlaneEndpoint(controlPlaneHost)
 	initConfig.SetServiceSubnet(serviceCIDR)
+	initConfig.SetPodSubnet(podCIDR)
+	initConfig.SetAPIServerExtraArgs(apiServerExtraArgs(configOverrides))
 	initConfigYAML, err := initConfig.Marshal()
 	if err != nil {
 		return nil, fmt.Errorf("encoding kubeadm init configuration as YAML: %w", err)
 	}
+	nodeOverrides, err := nodeConfigOverrides(configOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("generating node configuration overrides: %w", err)
+	}
 	log.Infof("Initializing Kubernetes cluster")
-	kubeConfig, err := k.clusterUtil.InitCluster(ctx, initConfigYAML, nodeName, clusterName, validIPs, conformanceMode, log)
+	kubeConfig, err := k.clusterUtil.InitCluster(ctx, initConfigYAML, nodeName, clusterName, validIPs, conformanceMode, nodeOverrides, log)
 	if err != nil {
 		return nil, fmt.Errorf("kubeadm init: %w", err)
 	}
@@ -179,14 +193,17 @@ func (k *KubeWrapper) InitCluster(
 	}
 
 	log.Infof("Setting up internal-config ConfigMap")
-	if err := k.setupInternalConfigMap(ctx); err != nil {
+	if err := k.setupInternalConfigMap(ctx, nodeOverrides, diskEncryptionProfile); err != nil {
 		return nil, fmt.Errorf("failed to setup internal ConfigMap: %w", err)
 	}
 	return kubeConfig, nil
 }
 
 // JoinCluster joins existing Kubernetes cluster.
-func (k *KubeWrapper) JoinCluster(ctx context.Context, args *kubeadm.BootstrapTokenDiscovery, peerRole role.Role, k8sComponents components.Components, log *logger.Logger) error {
+// The config overrides are the node-local files of the user supplied Kubernetes configuration overrides, as issued by the join service.
+func (k *KubeWrapper) JoinCluster(
+	ctx context.Context, args *kubeadm.BootstrapTokenDiscovery, peerRole role.Role, k8sComponents components.Components, configOverrides k8sapi.ConfigOverrides, log *logger.Logger,
+) error {
 	log.With("k8sComponents", k8sComponents).Infof("Installing provided kubernetes components")
 	if err := k.clusterUtil.InstallComponents(ctx, k8sComponents); err != nil {
 		return fmt.Errorf("installing kubernetes components: %w", err)
@@ -204,6 +221,10 @@ func (k *KubeWrapper) JoinCluster(ctx context.Context, args *kubeadm.BootstrapTo
 	if err != nil {
 		return fmt.Errorf("generating node name: %w", err)
 	}
+	nodeLabels, nodeTaints, err := k.nodeRegistration(ctx)
+	if err != nil {
+		return err
+	}
 
 	loadBalancerHost, loadBalancerPort, err := k.providerMetadata.GetLoadBalancerEndpoint(ctx)
 	if err != nil {
@@ -234,18 +255,37 @@ func (k *KubeWrapper) JoinCluster(ctx context.Context, args *kubeadm.BootstrapTo
 	if peerRole == role.ControlPlane {
 		joinConfig.SetControlPlane(nodeInternalIP)
 	}
+	joinConfig.SetNodeLabels(nodeLabels)
+	joinConfig.SetNodeTaints(nodeTaints)
 	joinConfigYAML, err := joinConfig.Marshal()
 	if err != nil {
 		return fmt.Errorf("encoding kubeadm join configuration as YAML: %w", err)
 	}
 	log.With(zap.String("apiServerEndpoint", args.APIServerEndpoint)).Infof("Joining Kubernetes cluster")
-	if err := k.clusterUtil.JoinCluster(ctx, joinConfigYAML, log); err != nil {
+	if err := k.clusterUtil.JoinCluster(ctx, joinConfigYAML, configOverrides, log); err != nil {
 		return fmt.Errorf("joining cluster: %v; %w ", string(joinConfigYAML), err)
 	}
 
 	return nil
 }
 
+// nodeRegistration retrieves the labels and taints of the node group from the cloud provider metadata.
+func (k *KubeWrapper) nodeRegistration(ctx context.Context) (map[string]string, []corev1.Taint, error) {
+	encodedLabels, encodedTaints, err := k.providerMetadata.NodeRegistration(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("retrieving node registration metadata: %w", err)
+	}
+	labels, err := kubernetes.UnmarshalNodeLabels(encodedLabels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing node labels: %w", err)
+	}
+	taints, err := kubernetes.UnmarshalNodeTaints(encodedTaints)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing node taints: %w", err)
+	}
+	return labels, taints, nil
+}
+
 // setupK8sComponentsConfigMap applies a ConfigMap (cf. server-side apply) to store the installed k8s components.
 // It returns the name of the ConfigMap.
 func (k *KubeWrapper) setupK8sComponentsConfigMap(ctx context.Context, components components.Components, clusterVersion string) (string, error) {
@@ -262,7 +302,9 @@ func (k *KubeWrapper) setupK8sComponentsConfigMap(ctx context.Context, component
 }
 
 // setupInternalConfigMap applies a ConfigMap (cf. server-side apply) to store information that is not supposed to be user-editable.
-func (k *KubeWrapper) setupInternalConfigMap(ctx context.Context) error {
+// The node-local Kubernetes configuration overrides and the disk encryption profile are stored,
+// so that the join service can issue them to joining nodes.
+func (k *KubeWrapper) setupInternalConfigMap(ctx context.Context, configOverrides k8sapi.ConfigOverrides, diskEncryptionProfile string) error {
 	config := corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
@@ -274,6 +316,15 @@ func (k *KubeWrapper) setupInternalConfigMap(ctx context.Context) error {
 		},
 		Data: map[string]string{},
 	}
+	if len(configOverrides.AuditPolicy) > 0 {
+		config.Data[constants.AuditPolicyKey] = string(configOverrides.AuditPolicy)
+	}
+	if len(configOverrides.KubeletConfigPatch) > 0 {
+		config.Data[constants.KubeletConfigPatchKey] = string(configOverrides.KubeletConfigPatch)
+	}
+	if diskEncryptionProfile != "" {
+		config.Data[constants.DiskEncryptionProfileKey] = diskEncryptionProfile
+	}
 
 	// We do not use the client's Apply method here since we are handling a kubernetes-native type.
 	// These types don't implement our custom Marshaler interface.
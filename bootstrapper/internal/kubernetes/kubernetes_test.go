@@ -41,13 +41,17 @@ func TestInitCluster(t *testing.T) {
 	aliasIPRange := "192.0.2.0/24"
 
 	testCases := map[string]struct {
-		clusterUtil      stubClusterUtil
-		kubectl          stubKubectl
-		kubeAPIWaiter    stubKubeAPIWaiter
-		providerMetadata ProviderMetadata
-		wantConfig       k8sapi.KubeadmInitYAML
-		wantErr          bool
-		k8sVersion       versions.ValidK8sVersion
+		clusterUtil           stubClusterUtil
+		kubectl               stubKubectl
+		kubeAPIWaiter         stubKubeAPIWaiter
+		providerMetadata      ProviderMetadata
+		serviceCIDR           string
+		podCIDR               string
+		diskEncryptionProfile string
+		wantConfig            k8sapi.KubeadmInitYAML
+		wantInternalConfig    map[string]string
+		wantErr               bool
+		k8sVersion            versions.ValidK8sVersion
 	}{
 		"kubeadm init works with metadata and loadbalancer": {
 			clusterUtil:   stubClusterUtil{kubeconfig: []byte("someKubeconfig")},
@@ -80,8 +84,127 @@ func TestInitCluster(t *testing.T) {
 					},
 				},
 			},
-			wantErr:    false,
-			k8sVersion: versions.Default,
+			wantInternalConfig: map[string]string{},
+			wantErr:            false,
+			k8sVersion:         versions.Default,
+		},
+		"kubeadm init registers node labels and taints": {
+			clusterUtil:   stubClusterUtil{kubeconfig: []byte("someKubeconfig")},
+			kubeAPIWaiter: stubKubeAPIWaiter{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					Name:          nodeName,
+					ProviderID:    providerID,
+					VPCIP:         privateIP,
+					AliasIPRanges: []string{aliasIPRange},
+				},
+				getLoadBalancerHostResp: loadbalancerIP,
+				getLoadBalancerPortResp: strconv.Itoa(constants.KubernetesPort),
+				nodeRegistrationLabels:  "pool=default",
+				nodeRegistrationTaints:  "dedicated=control-plane:NoExecute",
+			},
+			wantConfig: k8sapi.KubeadmInitYAML{
+				InitConfiguration: kubeadm.InitConfiguration{
+					NodeRegistration: kubeadm.NodeRegistrationOptions{
+						KubeletExtraArgs: map[string]string{
+							"node-ip":     privateIP,
+							"provider-id": providerID,
+							"node-labels": "pool=default",
+						},
+						Taints: []corev1.Taint{
+							{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+							{Key: "dedicated", Value: "control-plane", Effect: corev1.TaintEffectNoExecute},
+						},
+						Name: nodeName,
+					},
+				},
+				ClusterConfiguration: kubeadm.ClusterConfiguration{
+					ClusterName:          "kubernetes",
+					ControlPlaneEndpoint: loadbalancerIP,
+					APIServer: kubeadm.APIServer{
+						CertSANs: []string{privateIP},
+					},
+				},
+			},
+			wantInternalConfig: map[string]string{},
+			k8sVersion:         versions.Default,
+		},
+		"kubeadm init configures dual-stack networking": {
+			clusterUtil:   stubClusterUtil{kubeconfig: []byte("someKubeconfig")},
+			kubeAPIWaiter: stubKubeAPIWaiter{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					Name:       nodeName,
+					ProviderID: providerID,
+					VPCIP:      privateIP,
+				},
+				getLoadBalancerHostResp: loadbalancerIP,
+				getLoadBalancerPortResp: strconv.Itoa(constants.KubernetesPort),
+			},
+			serviceCIDR: "10.96.0.0/12,fd00:10:96::/108",
+			podCIDR:     "10.244.0.0/16,fd00:10:244::/56",
+			wantConfig: k8sapi.KubeadmInitYAML{
+				InitConfiguration: kubeadm.InitConfiguration{
+					NodeRegistration: kubeadm.NodeRegistrationOptions{
+						KubeletExtraArgs: map[string]string{
+							"node-ip":     privateIP,
+							"provider-id": providerID,
+						},
+						Name: nodeName,
+					},
+				},
+				ClusterConfiguration: kubeadm.ClusterConfiguration{
+					ClusterName:          "kubernetes",
+					ControlPlaneEndpoint: loadbalancerIP,
+					APIServer: kubeadm.APIServer{
+						CertSANs: []string{privateIP},
+					},
+					ControllerManager: kubeadm.ControlPlaneComponent{
+						ExtraArgs: map[string]string{"allocate-node-cidrs": "false"},
+					},
+					Networking: kubeadm.Networking{
+						ServiceSubnet: "10.96.0.0/12,fd00:10:96::/108",
+						PodSubnet:     "10.244.0.0/16,fd00:10:244::/56",
+					},
+				},
+			},
+			wantInternalConfig: map[string]string{},
+			k8sVersion:         versions.Default,
+		},
+		"disk encryption profile is stored in internal config": {
+			clusterUtil:   stubClusterUtil{kubeconfig: []byte("someKubeconfig")},
+			kubeAPIWaiter: stubKubeAPIWaiter{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					Name:          nodeName,
+					ProviderID:    providerID,
+					VPCIP:         privateIP,
+					AliasIPRanges: []string{aliasIPRange},
+				},
+				getLoadBalancerHostResp: loadbalancerIP,
+				getLoadBalancerPortResp: strconv.Itoa(constants.KubernetesPort),
+			},
+			diskEncryptionProfile: "aes-xts",
+			wantConfig: k8sapi.KubeadmInitYAML{
+				InitConfiguration: kubeadm.InitConfiguration{
+					NodeRegistration: kubeadm.NodeRegistrationOptions{
+						KubeletExtraArgs: map[string]string{
+							"node-ip":     privateIP,
+							"provider-id": providerID,
+						},
+						Name: nodeName,
+					},
+				},
+				ClusterConfiguration: kubeadm.ClusterConfiguration{
+					ClusterName:          "kubernetes",
+					ControlPlaneEndpoint: loadbalancerIP,
+					APIServer: kubeadm.APIServer{
+						CertSANs: []string{privateIP},
+					},
+				},
+			},
+			wantInternalConfig: map[string]string{constants.DiskEncryptionProfileKey: "aes-xts"},
+			k8sVersion:         versions.Default,
 		},
 		"kubeadm init fails when annotating itself": {
 			clusterUtil:   stubClusterUtil{kubeconfig: []byte("someKubeconfig")},
@@ -187,7 +310,7 @@ func TestInitCluster(t *testing.T) {
 
 			_, err := kube.InitCluster(
 				context.Background(), string(tc.k8sVersion), "kubernetes",
-				false, nil, nil, "", logger.NewTest(t),
+				false, nil, nil, tc.serviceCIDR, tc.podCIDR, nil, tc.diskEncryptionProfile, logger.NewTest(t),
 			)
 
 			if tc.wantErr {
@@ -200,6 +323,15 @@ func TestInitCluster(t *testing.T) {
 			require.NoError(kubernetes.UnmarshalK8SResources(tc.clusterUtil.initConfigs[0], &kubeadmConfig))
 			require.Equal(tc.wantConfig.ClusterConfiguration, kubeadmConfig.ClusterConfiguration)
 			require.Equal(tc.wantConfig.InitConfiguration, kubeadmConfig.InitConfiguration)
+
+			var internalConfig *corev1.ConfigMap
+			for _, configMap := range tc.kubectl.createdConfigMaps {
+				if configMap.Name == constants.InternalConfigMap {
+					internalConfig = configMap
+				}
+			}
+			require.NotNil(internalConfig)
+			assert.Equal(tc.wantInternalConfig, internalConfig.Data)
 		})
 	}
 }
@@ -318,6 +450,85 @@ func TestJoinCluster(t *testing.T) {
 				SkipPhases: []string{"control-plane-prepare/download-certs"},
 			},
 		},
+		"kubeadm join worker registers node labels and taints": {
+			clusterUtil: stubClusterUtil{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					ProviderID: "provider-id",
+					Name:       "metadata-name",
+					VPCIP:      "192.0.2.1",
+				},
+				nodeRegistrationLabels: "pool=spot node.kubernetes.io/lifecycle=spot",
+				nodeRegistrationTaints: "spot=true:NoSchedule",
+			},
+			role: role.Worker,
+			wantConfig: kubeadm.JoinConfiguration{
+				Discovery: kubeadm.Discovery{
+					BootstrapToken: joinCommand,
+				},
+				NodeRegistration: kubeadm.NodeRegistrationOptions{
+					Name: "metadata-name",
+					KubeletExtraArgs: map[string]string{
+						"node-ip":     "192.0.2.1",
+						"node-labels": "node.kubernetes.io/lifecycle=spot,pool=spot",
+					},
+					Taints: []corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+		"kubeadm join control-plane node keeps control plane taint": {
+			clusterUtil: stubClusterUtil{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					ProviderID: "provider-id",
+					Name:       "metadata-name",
+					VPCIP:      "192.0.2.1",
+				},
+				nodeRegistrationTaints: "dedicated:NoExecute",
+			},
+			role: role.ControlPlane,
+			wantConfig: kubeadm.JoinConfiguration{
+				Discovery: kubeadm.Discovery{
+					BootstrapToken: joinCommand,
+				},
+				NodeRegistration: kubeadm.NodeRegistrationOptions{
+					Name:             "metadata-name",
+					KubeletExtraArgs: map[string]string{"node-ip": "192.0.2.1"},
+					Taints: []corev1.Taint{
+						{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "dedicated", Effect: corev1.TaintEffectNoExecute},
+					},
+				},
+				ControlPlane: &kubeadm.JoinControlPlane{
+					LocalAPIEndpoint: kubeadm.APIEndpoint{
+						AdvertiseAddress: "192.0.2.1",
+						BindPort:         constants.KubernetesPort,
+					},
+				},
+				SkipPhases: []string{"control-plane-prepare/download-certs"},
+			},
+		},
+		"kubeadm join worker fails with invalid node labels": {
+			clusterUtil: stubClusterUtil{},
+			providerMetadata: &stubProviderMetadata{
+				selfResp: metadata.InstanceMetadata{
+					ProviderID: "provider-id",
+					Name:       "metadata-name",
+					VPCIP:      "192.0.2.1",
+				},
+				nodeRegistrationLabels: "pool",
+			},
+			role:    role.Worker,
+			wantErr: true,
+		},
+		"kubeadm join worker fails when retrieving node registration metadata": {
+			clusterUtil: stubClusterUtil{},
+			providerMetadata: &stubProviderMetadata{
+				nodeRegistrationErr: assert.AnError,
+			},
+			role:    role.Worker,
+			wantErr: true,
+		},
 		"kubeadm join worker fails when installing remote Kubernetes components": {
 			clusterUtil: stubClusterUtil{installComponentsErr: errors.New("error")},
 			providerMetadata: &stubProviderMetadata{
@@ -359,7 +570,7 @@ func TestJoinCluster(t *testing.T) {
 				getIPAddr:        func() (string, error) { return privateIP, nil },
 			}
 
-			err := kube.JoinCluster(context.Background(), joinCommand, tc.role, tc.k8sComponents, logger.NewTest(t))
+			err := kube.JoinCluster(context.Background(), joinCommand, tc.role, tc.k8sComponents, k8sapi.ConfigOverrides{}, logger.NewTest(t))
 			if tc.wantErr {
 				assert.Error(err)
 				return
@@ -440,7 +651,7 @@ func (s *stubClusterUtil) InstallComponents(_ context.Context, _ components.Comp
 	return s.installComponentsErr
 }
 
-func (s *stubClusterUtil) InitCluster(_ context.Context, initConfig []byte, _, _ string, _ []net.IP, _ bool, _ *logger.Logger) ([]byte, error) {
+func (s *stubClusterUtil) InitCluster(_ context.Context, initConfig []byte, _, _ string, _ []net.IP, _ bool, _ k8sapi.ConfigOverrides, _ *logger.Logger) ([]byte, error) {
 	s.initConfigs = append(s.initConfigs, initConfig)
 	return s.kubeconfig, s.initClusterErr
 }
@@ -465,7 +676,7 @@ func (s *stubClusterUtil) SetupNodeOperator(_ context.Context, _ k8sapi.Client,
 	return s.setupNodeOperatorErr
 }
 
-func (s *stubClusterUtil) JoinCluster(_ context.Context, joinConfig []byte, _ *logger.Logger) error {
+func (s *stubClusterUtil) JoinCluster(_ context.Context, joinConfig []byte, _ k8sapi.ConfigOverrides, _ *logger.Logger) error {
 	s.joinConfigs = append(s.joinConfigs, joinConfig)
 	return s.joinClusterErr
 }
@@ -495,6 +706,7 @@ func (s *stubConfigProvider) JoinConfiguration(_ bool) k8sapi.KubeadmJoinYAML {
 }
 
 type stubKubectl struct {
+	createdConfigMaps                []*corev1.ConfigMap
 	createConfigMapErr               error
 	addTNodeSelectorsToDeploymentErr error
 	waitForCRDsErr                   error
@@ -509,7 +721,8 @@ func (s *stubKubectl) Initialize(_ []byte) error {
 	return nil
 }
 
-func (s *stubKubectl) CreateConfigMap(_ context.Context, _ *corev1.ConfigMap) error {
+func (s *stubKubectl) CreateConfigMap(_ context.Context, configMap *corev1.ConfigMap) error {
+	s.createdConfigMaps = append(s.createdConfigMaps, configMap)
 	return s.createConfigMapErr
 }
 
@@ -0,0 +1,87 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/bootstrapper/initproto"
+	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
+)
+
+// kubeadmAdmissionPlugins are the admission plugins kubeadm enables on the API server by default.
+// Setting "enable-admission-plugins" replaces kubeadm's value, so these plugins are always added to the user's list.
+var kubeadmAdmissionPlugins = []string{"NodeRestriction"}
+
+// apiServerExtraArgs returns the API server flags for the user supplied overrides.
+// The admission plugins enabled by kubeadm can't be disabled.
+func apiServerExtraArgs(overrides *initproto.KubernetesConfigOverrides) map[string]string {
+	args := map[string]string{}
+	if len(overrides.GetApiserverEnableAdmissionPlugins()) > 0 {
+		enabled := slices.Clone(kubeadmAdmissionPlugins)
+		for _, plugin := range overrides.GetApiserverEnableAdmissionPlugins() {
+			if !slices.Contains(enabled, plugin) {
+				enabled = append(enabled, plugin)
+			}
+		}
+		args["enable-admission-plugins"] = strings.Join(enabled, ",")
+	}
+	var disabled []string
+	for _, plugin := range overrides.GetApiserverDisableAdmissionPlugins() {
+		if !slices.Contains(kubeadmAdmissionPlugins, plugin) {
+			disabled = append(disabled, plugin)
+		}
+	}
+	if len(disabled) > 0 {
+		args["disable-admission-plugins"] = strings.Join(disabled, ",")
+	}
+
+	oidc := overrides.GetApiserverOidc()
+	for flag, value := range map[string]string{
+		"oidc-issuer-url":      oidc.GetIssuerUrl(),
+		"oidc-client-id":       oidc.GetClientId(),
+		"oidc-username-claim":  oidc.GetUsernameClaim(),
+		"oidc-username-prefix": oidc.GetUsernamePrefix(),
+		"oidc-groups-claim":    oidc.GetGroupsClaim(),
+		"oidc-groups-prefix":   oidc.GetGroupsPrefix(),
+	} {
+		if value != "" {
+			args[flag] = value
+		}
+	}
+	return args
+}
+
+// nodeConfigOverrides returns the files that have to be written to every node for the user supplied overrides.
+func nodeConfigOverrides(overrides *initproto.KubernetesConfigOverrides) (k8sapi.ConfigOverrides, error) {
+	patch := map[string]map[string]string{}
+	if len(overrides.GetKubeletEvictionHard()) > 0 {
+		patch["evictionHard"] = overrides.GetKubeletEvictionHard()
+	}
+	if len(overrides.GetKubeletEvictionSoft()) > 0 {
+		patch["evictionSoft"] = overrides.GetKubeletEvictionSoft()
+	}
+	if len(overrides.GetKubeletEvictionSoftGracePeriod()) > 0 {
+		patch["evictionSoftGracePeriod"] = overrides.GetKubeletEvictionSoftGracePeriod()
+	}
+
+	var kubeletConfigPatch []byte
+	if len(patch) > 0 {
+		var err error
+		kubeletConfigPatch, err = json.Marshal(patch)
+		if err != nil {
+			return k8sapi.ConfigOverrides{}, err
+		}
+	}
+
+	return k8sapi.ConfigOverrides{
+		AuditPolicy:        overrides.GetAuditPolicy(),
+		KubeletConfigPatch: kubeletConfigPatch,
+	}, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/bootstrapper/initproto"
+	"github.com/edgelesssys/constellation/v2/bootstrapper/internal/kubernetes/k8sapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIServerExtraArgs(t *testing.T) {
+	testCases := map[string]struct {
+		overrides *initproto.KubernetesConfigOverrides
+		wantArgs  map[string]string
+	}{
+		"no overrides": {
+			wantArgs: map[string]string{},
+		},
+		"admission plugins": {
+			overrides: &initproto.KubernetesConfigOverrides{
+				ApiserverEnableAdmissionPlugins:  []string{"AlwaysPullImages", "PodNodeSelector"},
+				ApiserverDisableAdmissionPlugins: []string{"DefaultStorageClass"},
+			},
+			wantArgs: map[string]string{
+				"enable-admission-plugins":  "NodeRestriction,AlwaysPullImages,PodNodeSelector",
+				"disable-admission-plugins": "DefaultStorageClass",
+			},
+		},
+		"kubeadm admission plugins are not duplicated": {
+			overrides: &initproto.KubernetesConfigOverrides{
+				ApiserverEnableAdmissionPlugins: []string{"AlwaysPullImages", "NodeRestriction"},
+			},
+			wantArgs: map[string]string{
+				"enable-admission-plugins": "NodeRestriction,AlwaysPullImages",
+			},
+		},
+		"kubeadm admission plugins can't be disabled": {
+			overrides: &initproto.KubernetesConfigOverrides{
+				ApiserverDisableAdmissionPlugins: []string{"NodeRestriction", "DefaultStorageClass"},
+			},
+			wantArgs: map[string]string{
+				"disable-admission-plugins": "DefaultStorageClass",
+			},
+		},
+		"oidc": {
+			overrides: &initproto.KubernetesConfigOverrides{
+				ApiserverOidc: &initproto.OIDCConfig{
+					IssuerUrl:     "https://issuer.example.com",
+					ClientId:      "constellation",
+					UsernameClaim: "email",
+				},
+			},
+			wantArgs: map[string]string{
+				"oidc-issuer-url":     "https://issuer.example.com",
+				"oidc-client-id":      "constellation",
+				"oidc-username-claim": "email",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.wantArgs, apiServerExtraArgs(tc.overrides))
+		})
+	}
+}
+
+func TestAPIServerExtraArgsKeepNodeRestriction(t *testing.T) {
+	assert := assert.New(t)
+
+	initConfig := (&k8sapi.KubdeadmConfiguration{}).InitConfiguration(true, "v1.27.9")
+	initConfig.SetAPIServerExtraArgs(apiServerExtraArgs(&initproto.KubernetesConfigOverrides{
+		ApiserverEnableAdmissionPlugins: []string{"AlwaysPullImages"},
+	}))
+
+	plugins := strings.Split(initConfig.ClusterConfiguration.APIServer.ExtraArgs["enable-admission-plugins"], ",")
+	assert.Contains(plugins, "NodeRestriction")
+	assert.Contains(plugins, "AlwaysPullImages")
+}
+
+func TestNodeConfigOverrides(t *testing.T) {
+	testCases := map[string]struct {
+		overrides   *initproto.KubernetesConfigOverrides
+		wantPolicy  []byte
+		wantPatch   string
+		wantNoPatch bool
+	}{
+		"no overrides": {
+			wantNoPatch: true,
+		},
+		"audit policy": {
+			overrides:   &initproto.KubernetesConfigOverrides{AuditPolicy: []byte("policy")},
+			wantPolicy:  []byte("policy"),
+			wantNoPatch: true,
+		},
+		"eviction thresholds": {
+			overrides: &initproto.KubernetesConfigOverrides{
+				KubeletEvictionHard:            map[string]string{"memory.available": "500Mi"},
+				KubeletEvictionSoft:            map[string]string{"memory.available": "1Gi"},
+				KubeletEvictionSoftGracePeriod: map[string]string{"memory.available": "1m30s"},
+			},
+			wantPatch: `{"evictionHard":{"memory.available":"500Mi"},"evictionSoft":{"memory.available":"1Gi"},"evictionSoftGracePeriod":{"memory.available":"1m30s"}}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			overrides, err := nodeConfigOverrides(tc.overrides)
+			require.NoError(err)
+			assert.Equal(tc.wantPolicy, overrides.AuditPolicy)
+			if tc.wantNoPatch {
+				assert.Empty(overrides.KubeletConfigPatch)
+				return
+			}
+			assert.JSONEq(tc.wantPatch, string(overrides.KubeletConfigPatch))
+		})
+	}
+}
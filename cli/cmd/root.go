@@ -46,6 +46,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.PersistentFlags().Bool("debug", false, "enable debug logging")
 	rootCmd.PersistentFlags().Bool("force", false, "disable version compatibility checks - might result in corrupted clusters")
 	rootCmd.PersistentFlags().String("tf-log", "NONE", "Terraform log level")
+	rootCmd.PersistentFlags().String("profile", "", "name of the profile from the configuration file to merge over the base configuration")
 
 	must(rootCmd.MarkPersistentFlagDirname("workspace"))
 
@@ -57,7 +58,11 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(cmd.NewVerifyCmd())
 	rootCmd.AddCommand(cmd.NewUpgradeCmd())
 	rootCmd.AddCommand(cmd.NewRecoverCmd())
+	rootCmd.AddCommand(cmd.NewBackupCmd())
+	rootCmd.AddCommand(cmd.NewRestoreCmd())
 	rootCmd.AddCommand(cmd.NewTerminateCmd())
+	rootCmd.AddCommand(cmd.NewStateCmd())
+	rootCmd.AddCommand(cmd.NewAuditCmd())
 	rootCmd.AddCommand(cmd.NewIAMCmd())
 	rootCmd.AddCommand(cmd.NewVersionCmd())
 	rootCmd.AddCommand(cmd.NewInitCmd())
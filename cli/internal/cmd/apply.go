@@ -13,31 +13,31 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"net"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/api/attestationconfigapi"
 	"github.com/edgelesssys/constellation/v2/internal/api/versionsapi"
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/compatibility"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
 	"github.com/edgelesssys/constellation/v2/internal/file"
-	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
 	"github.com/edgelesssys/constellation/v2/internal/imagefetcher"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/edgelesssys/constellation/v2/internal/role"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -134,8 +134,14 @@ func NewApplyCmd() *cobra.Command {
 		"WARNING: the command might delete or update existing resources without additional checks. Please read the docs.\n")
 	cmd.Flags().Duration("helm-timeout", 10*time.Minute, "change helm install/upgrade timeout\n"+
 		"Might be useful for slow connections or big clusters.")
+	cmd.Flags().Bool("wait-for-control-plane", false, "after initializing a new cluster, wait until all control-plane nodes joined\n"+
+		"Fails if not all control-plane nodes are attested and members of etcd within --control-plane-timeout.")
+	cmd.Flags().Duration("control-plane-timeout", 20*time.Minute, "change the time to wait for all control-plane nodes to join a new cluster\n"+
+		"Only used with --wait-for-control-plane.")
 	cmd.Flags().StringSlice("skip-phases", nil, "comma-separated list of upgrade phases to skip\n"+
 		fmt.Sprintf("one or multiple of %s", formatSkipPhases()))
+	cmd.Flags().Bool("plan", false, "print the changes apply would make to the cluster without applying them")
+	cmd.Flags().StringP("output", "o", planOutputText, fmt.Sprintf("output format of --plan {%s|%s}", planOutputText, planOutputJSON))
 
 	must(cmd.Flags().MarkHidden("helm-timeout"))
 
@@ -151,7 +157,13 @@ type applyFlags struct {
 	mergeConfigs bool
 	helmTimeout  time.Duration
 	helmWaitMode helm.WaitMode
-	skipPhases   skipPhases
+	// waitForControlPlane waits for all control-plane nodes to join a new cluster after init.
+	waitForControlPlane bool
+	// controlPlaneTimeout is the time to wait for control-plane nodes to join a new cluster.
+	controlPlaneTimeout time.Duration
+	skipPhases          skipPhases
+	plan                bool
+	planOutput          string
 }
 
 // parse the apply command flags.
@@ -185,6 +197,16 @@ func (f *applyFlags) parse(flags *pflag.FlagSet) error {
 		return fmt.Errorf("getting 'helm-timeout' flag: %w", err)
 	}
 
+	f.waitForControlPlane, err = flags.GetBool("wait-for-control-plane")
+	if err != nil {
+		return fmt.Errorf("getting 'wait-for-control-plane' flag: %w", err)
+	}
+
+	f.controlPlaneTimeout, err = flags.GetDuration("control-plane-timeout")
+	if err != nil {
+		return fmt.Errorf("getting 'control-plane-timeout' flag: %w", err)
+	}
+
 	f.conformance, err = flags.GetBool("conformance")
 	if err != nil {
 		return fmt.Errorf("getting 'conformance' flag: %w", err)
@@ -203,6 +225,22 @@ func (f *applyFlags) parse(flags *pflag.FlagSet) error {
 	if err != nil {
 		return fmt.Errorf("getting 'merge-kubeconfig' flag: %w", err)
 	}
+
+	f.plan, err = flags.GetBool("plan")
+	if err != nil {
+		return fmt.Errorf("getting 'plan' flag: %w", err)
+	}
+
+	f.planOutput, err = flags.GetString("output")
+	if err != nil {
+		return fmt.Errorf("getting 'output' flag: %w", err)
+	}
+	if f.planOutput != planOutputText && f.planOutput != planOutputJSON {
+		return fmt.Errorf("invalid output format %q, must be one of %q or %q", f.planOutput, planOutputText, planOutputJSON)
+	}
+	if flags.Changed("output") && !f.plan {
+		return errors.New("flag 'output' can only be used together with 'plan'")
+	}
 	return nil
 }
 
@@ -226,10 +264,6 @@ func runApply(cmd *cobra.Command, _ []string) error {
 
 	fileHandler := file.NewHandler(afero.NewOsFs())
 
-	newDialer := func(validator atls.Validator) *dialer.Dialer {
-		return dialer.New(nil, validator, &net.Dialer{})
-	}
-
 	upgradeID := generateUpgradeID(upgradeCmdKindApply)
 	upgradeDir := filepath.Join(constants.UpgradeDir, upgradeID)
 
@@ -244,7 +278,7 @@ func runApply(cmd *cobra.Command, _ []string) error {
 		)
 	}
 
-	applier := constellation.NewApplier(log, spinner, constellation.ApplyContextCLI, newDialer)
+	applier := constellation.NewApplier(log, spinnerProgress(spinner), constellation.ApplyContextCLI, constellation.DefaultDialer)
 
 	apply := &applyCmd{
 		fileHandler:     fileHandler,
@@ -279,6 +313,11 @@ type applyCmd struct {
 	applier      applier
 
 	newInfraApplier func(context.Context) (cloudApplier, func(), error)
+
+	// stateBackend stores the state file. If nil, it is created from the user's config.
+	stateBackend backend.Backend
+	// stateLockID is the ID of the state lock held by the command, or empty if no lock is held.
+	stateLockID string
 }
 
 /*
@@ -340,6 +379,11 @@ The control flow is as follows:
 	                       │ Apply Helm Charts │                    │Phase
 	                       └──────────┬────────┘                 ───┘
 	                                  │                          ───┐
+	     Can be skipped  ┌────────────▼───────────┐                 │ControlPlane
+	 if we didn't run    │Wait for control-plane  │                 │Phase
+	 Init RPC            │nodes to join etcd      │                 │
+	                     └────────────┬───────────┘              ───┘
+	                                  │                          ───┐
 	                    ┌─────────────▼────────────┐                │
 	     Can be skipped │Upgrade NodeVersion object│                │K8s/Image
 	 if we ran Init RPC │  (Image and K8s update)  │                │Phase
@@ -352,12 +396,20 @@ The control flow is as follows:
 func (a *applyCmd) apply(
 	cmd *cobra.Command, configFetcher attestationconfigapi.Fetcher, upgradeDir string,
 ) error {
+	// Release the state lock acquired while validating inputs
+	defer a.unlockState(cmd)
+
 	// Validate inputs
 	conf, stateFile, err := a.validateInputs(cmd, configFetcher)
 	if err != nil {
 		return err
 	}
 
+	// Only report the pending changes if the user asked for a plan
+	if a.flags.plan {
+		return a.runPlan(cmd, conf, stateFile, upgradeDir)
+	}
+
 	// Check license
 	a.checkLicenseFile(cmd, conf.GetProvider())
 
@@ -380,7 +432,8 @@ func (a *applyCmd) apply(
 		}
 	}
 
-	if a.flags.skipPhases.contains(skipAttestationConfigPhase, skipCertSANsPhase, skipHelmPhase, skipK8sPhase, skipImagePhase) {
+	if a.flags.skipPhases.contains(skipAttestationConfigPhase, skipCertSANsPhase, skipHelmPhase, skipK8sPhase, skipImagePhase) &&
+		!a.flags.waitForControlPlane {
 		cmd.Print(bufferedOutput.String())
 		return nil
 	}
@@ -421,6 +474,13 @@ func (a *applyCmd) apply(
 		}
 	}
 
+	// Wait for the control-plane nodes of a new cluster to join
+	if a.flags.waitForControlPlane {
+		if err := a.runControlPlaneWait(cmd, conf); err != nil {
+			return err
+		}
+	}
+
 	// Upgrade node image
 	if !a.flags.skipPhases.contains(skipImagePhase) {
 		if err := a.runNodeImageUpgrade(cmd, conf); err != nil {
@@ -444,7 +504,7 @@ func (a *applyCmd) apply(
 func (a *applyCmd) validateInputs(cmd *cobra.Command, configFetcher attestationconfigapi.Fetcher) (*config.Config, *state.State, error) {
 	// Read user's config and state file
 	a.log.Debugf("Reading config from %s", a.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
-	conf, err := config.New(a.fileHandler, constants.ConfigFilename, configFetcher, a.flags.force)
+	conf, err := config.New(a.fileHandler, constants.ConfigFilename, a.flags.profile, configFetcher, a.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -453,8 +513,8 @@ func (a *applyCmd) validateInputs(cmd *cobra.Command, configFetcher attestationc
 		return nil, nil, err
 	}
 
-	a.log.Debugf("Reading state file from %s", a.flags.pathPrefixer.PrefixPrintablePath(constants.StateFilename))
-	stateFile, err := state.CreateOrRead(a.fileHandler, constants.StateFilename)
+	a.log.Debugf("Locking and reading state file")
+	stateFile, err := a.lockAndReadState(cmd, conf)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -518,6 +578,13 @@ func (a *applyCmd) validateInputs(cmd *cobra.Command, configFetcher attestationc
 		return nil, nil, postInitValidateErr
 	}
 
+	// Only wait for control-plane nodes to join a new cluster.
+	// Control-plane nodes of an existing cluster may be replaced at any time.
+	if a.flags.waitForControlPlane && a.flags.skipPhases.contains(skipInitPhase) {
+		cmd.PrintErrln("Warning: --wait-for-control-plane only applies when initializing a new cluster, not waiting for control-plane nodes")
+		a.flags.waitForControlPlane = false
+	}
+
 	// Validate Kubernetes version as set in the user's config
 	// If we need to run the init RPC, the version has to be valid
 	// Otherwise, we are able to use an outdated version, meaning we skip the K8s upgrade
@@ -532,7 +599,8 @@ func (a *applyCmd) validateInputs(cmd *cobra.Command, configFetcher attestationc
 
 		if !a.flags.skipPhases.contains(skipK8sPhase) {
 			a.log.Debugf("Checking if user wants to continue anyway")
-			if !a.flags.yes {
+			// Planning doesn't change the cluster, so there is nothing to confirm
+			if !a.flags.yes && !a.flags.plan {
 				confirmed, err := askToConfirm(cmd,
 					fmt.Sprintf(
 						"WARNING: The Kubernetes patch version %s is not supported. If you continue, Kubernetes upgrades will be skipped. Do you want to continue anyway?",
@@ -586,6 +654,94 @@ func (a *applyCmd) validateInputs(cmd *cobra.Command, configFetcher attestationc
 	return conf, stateFile, nil
 }
 
+// lockAndReadState locks the state backend and reads the state from it.
+// If the backend holds no state, a new state is created. When a remote backend
+// is used for the first time, the state file of the workspace is migrated to it.
+// When only planning changes, the state is read without locking, writing or migrating it.
+func (a *applyCmd) lockAndReadState(cmd *cobra.Command, conf *config.Config) (*state.State, error) {
+	if a.stateBackend == nil {
+		stateBackend, err := newStateBackend(cmd.Context(), conf.StateBackend, a.fileHandler)
+		if err != nil {
+			return nil, err
+		}
+		a.stateBackend = stateBackend
+	}
+
+	if a.flags.plan {
+		stateFile, _, _, err := a.readState(cmd.Context(), conf)
+		return stateFile, err
+	}
+
+	lockID, err := lockState(cmd, a.stateBackend, "apply")
+	if err != nil {
+		return nil, err
+	}
+	a.stateLockID = lockID
+
+	stateFile, stored, migrate, err := a.readState(cmd.Context(), conf)
+	if err != nil || stored {
+		return stateFile, err
+	}
+
+	if migrate {
+		cmd.PrintErrf("Migrating %q to the remote state backend.\n", a.flags.pathPrefixer.PrefixPrintablePath(constants.StateFilename))
+	}
+	if err := stateFile.WriteToBackend(cmd.Context(), a.stateBackend); err != nil {
+		return nil, err
+	}
+
+	if migrate {
+		// Move the local state file out of the way, so it can't be mistaken for the current state.
+		if err := a.fileHandler.RenameFile(constants.StateFilename, constants.MigratedStateFilename); err != nil {
+			return nil, fmt.Errorf("moving migrated state file: %w", err)
+		}
+		cmd.PrintErrf("The local state file was moved to %q and is no longer used.\n", a.flags.pathPrefixer.PrefixPrintablePath(constants.MigratedStateFilename))
+	}
+	return stateFile, nil
+}
+
+// readState reads the state from the state backend, without modifying it.
+// stored reports whether the backend already holds the returned state.
+// If it doesn't, migrate reports whether the returned state was read from the
+// local state file, which has to be migrated to the remote backend.
+func (a *applyCmd) readState(ctx context.Context, conf *config.Config) (stateFile *state.State, stored, migrate bool, err error) {
+	stateFile, err = state.ReadFromBackend(ctx, a.stateBackend)
+	if err == nil {
+		return stateFile, true, false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, false, err
+	}
+
+	if conf.StateBackend.Remote() {
+		localState, err := state.ReadFromFile(a.fileHandler, constants.StateFilename)
+		if err == nil {
+			return localState, false, true, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, false, false, err
+		}
+	}
+	return state.New(), false, false, nil
+}
+
+// stateStore returns the backend the state is written to.
+func (a *applyCmd) stateStore() backend.Backend {
+	if a.stateBackend == nil {
+		return backend.NewLocal(a.fileHandler, constants.StateFilename)
+	}
+	return a.stateBackend
+}
+
+// unlockState releases the state lock, if it is held.
+func (a *applyCmd) unlockState(cmd *cobra.Command) {
+	if a.stateLockID == "" {
+		return
+	}
+	unlockState(cmd, a.stateBackend, a.stateLockID)
+	a.stateLockID = ""
+}
+
 // applyJoinConfig creates or updates the cluster's join config.
 // If the config already exists, and is different from the new config, the user is asked to confirm the upgrade.
 func (a *applyCmd) applyJoinConfig(cmd *cobra.Command, newConfig config.AttestationCfg, measurementSalt []byte,
@@ -635,6 +791,33 @@ func (a *applyCmd) applyJoinConfig(cmd *cobra.Command, newConfig config.Attestat
 	return nil
 }
 
+// runControlPlaneWait waits until the control-plane nodes requested in the config are attested and members of etcd.
+// An error listing the state of every node is returned if not all nodes joined before the timeout.
+func (a *applyCmd) runControlPlaneWait(cmd *cobra.Command, conf *config.Config) error {
+	var count int
+	for _, group := range conf.NodeGroups {
+		if group.Role == role.ControlPlane.TFString() {
+			count += group.InitialCount
+		}
+	}
+	if count <= 1 {
+		a.log.Debugf("Only %d control-plane node requested, not waiting for other nodes to join", count)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), a.flags.controlPlaneTimeout)
+	defer cancel()
+
+	cmd.Printf("Waiting for %d control-plane nodes to join the cluster\n", count)
+	if _, err := a.applier.WaitForControlPlaneNodes(ctx, count, func(node constellation.ControlPlaneNode) {
+		cmd.Printf("Control-plane node %s\n", node)
+	}); err != nil {
+		return fmt.Errorf("waiting for control-plane nodes within %s: %w", a.flags.controlPlaneTimeout, err)
+	}
+	cmd.Printf("All %d control-plane nodes joined the cluster\n", count)
+	return nil
+}
+
 func (a *applyCmd) runNodeImageUpgrade(cmd *cobra.Command, conf *config.Config) error {
 	provider := conf.GetProvider()
 	attestationVariant := conf.GetAttestationConfig().GetVariant()
@@ -644,13 +827,9 @@ func (a *applyCmd) runNodeImageUpgrade(cmd *cobra.Command, conf *config.Config)
 		return fmt.Errorf("fetching image reference: %w", err)
 	}
 
-	imageVersionInfo, err := versionsapi.NewVersionFromShortPath(conf.Image, versionsapi.VersionKindImage)
+	imageVersion, err := parseImageVersion(conf.Image)
 	if err != nil {
-		return fmt.Errorf("parsing version from image short path: %w", err)
-	}
-	imageVersion, err := semver.New(imageVersionInfo.Version())
-	if err != nil {
-		return fmt.Errorf("parsing image version: %w", err)
+		return err
 	}
 
 	err = a.applier.UpgradeNodeImage(cmd.Context(), imageVersion, imageReference, a.flags.force)
@@ -680,6 +859,19 @@ func (a *applyCmd) runK8sVersionUpgrade(cmd *cobra.Command, conf *config.Config)
 	return nil
 }
 
+// parseImageVersion returns the semantic version of an image short path.
+func parseImageVersion(image string) (semver.Semver, error) {
+	imageVersionInfo, err := versionsapi.NewVersionFromShortPath(image, versionsapi.VersionKindImage)
+	if err != nil {
+		return semver.Semver{}, fmt.Errorf("parsing version from image short path: %w", err)
+	}
+	imageVersion, err := semver.New(imageVersionInfo.Version())
+	if err != nil {
+		return semver.Semver{}, fmt.Errorf("parsing image version: %w", err)
+	}
+	return imageVersion, nil
+}
+
 // checkCreateFilesClean ensures that the workspace is clean before creating a new cluster.
 func (a *applyCmd) checkCreateFilesClean() error {
 	if err := a.checkInitFilesClean(); err != nil {
@@ -751,6 +943,13 @@ func printCreateWarnings(out io.Writer, conf *config.Config) {
 		printedAWarning = true
 	}
 
+	if variant.IsSimulated(conf.GetAttestationConfig().GetVariant()) {
+		fmt.Fprintf(out, "WARNING: The nodes use the simulated attestation variant %s.\n", conf.GetAttestationConfig().GetVariant())
+		fmt.Fprintln(out, "Anyone can forge simulated attestation reports, the cluster's confidentiality and integrity are NOT protected.")
+		fmt.Fprintln(out, "DO NOT USE THIS CLUSTER IN PRODUCTION OR WITH SENSITIVE DATA.")
+		printedAWarning = true
+	}
+
 	if conf.GetAttestationConfig().GetVariant().Equal(variant.AzureTrustedLaunch{}) {
 		fmt.Fprintln(out, "Disabling Confidential VMs is insecure. Use only for evaluation purposes.")
 		printedAWarning = true
@@ -831,8 +1030,7 @@ type applier interface {
 	GenerateMasterSecret() (uri.MasterSecret, error)
 	GenerateMeasurementSalt() ([]byte, error)
 	Init(
-		ctx context.Context, validator atls.Validator, state *state.State,
-		clusterLogWriter io.Writer, payload constellation.InitPayload,
+		ctx context.Context, validator atls.Validator, state *state.State, payload constellation.InitPayload,
 	) (constellation.InitOutput, error)
 
 	// methods required to install/upgrade Helm charts
@@ -844,12 +1042,15 @@ type applier interface {
 	// methods to interact with Kubernetes
 
 	ExtendClusterConfigCertSANs(ctx context.Context, clusterEndpoint, customEndpoint string, additionalAPIServerCertSANs []string) error
+	MissingClusterConfigCertSANs(ctx context.Context, clusterEndpoint, customEndpoint string, additionalAPIServerCertSANs []string) ([]string, error)
+	GetConstellationVersion(ctx context.Context) (kubecmd.NodeVersion, error)
 	GetClusterAttestationConfig(ctx context.Context, variant variant.Variant) (config.AttestationCfg, error)
 	ApplyJoinConfig(ctx context.Context, newAttestConfig config.AttestationCfg, measurementSalt []byte) error
 	UpgradeNodeImage(ctx context.Context, imageVersion semver.Semver, imageReference string, force bool) error
 	UpgradeKubernetesVersion(ctx context.Context, kubernetesVersion versions.ValidK8sVersion, force bool) error
-	BackupCRDs(ctx context.Context, fileHandler file.Handler, upgradeDir string) ([]apiextensionsv1.CustomResourceDefinition, error)
-	BackupCRs(ctx context.Context, fileHandler file.Handler, crds []apiextensionsv1.CustomResourceDefinition, upgradeDir string) error
+	BackupCRDs(ctx context.Context, fs afero.Fs, upgradeDir string) ([]apiextensionsv1.CustomResourceDefinition, error)
+	BackupCRs(ctx context.Context, fs afero.Fs, crds []apiextensionsv1.CustomResourceDefinition, upgradeDir string) error
+	WaitForControlPlaneNodes(ctx context.Context, count int, progress func(constellation.ControlPlaneNode)) ([]constellation.ControlPlaneNode, error)
 }
 
 // imageFetcher gets an image reference from the versionsapi.
@@ -11,7 +11,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"io/fs"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -20,19 +20,28 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
+	"github.com/edgelesssys/constellation/v2/internal/compatibility"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/internal/role"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // defaultStateFile returns a valid default state for testing.
@@ -88,6 +97,7 @@ func TestParseApplyFlags(t *testing.T) {
 		// Register persistent flags
 		flags.String("workspace", "", "")
 		flags.String("tf-log", "NONE", "")
+		flags.String("profile", "", "")
 		flags.Bool("force", false, "")
 		flags.Bool("debug", false, "")
 		return flags
@@ -101,8 +111,10 @@ func TestParseApplyFlags(t *testing.T) {
 		"default flags": {
 			flags: defaultFlags(),
 			wantFlags: applyFlags{
-				helmWaitMode: helm.WaitModeAtomic,
-				helmTimeout:  10 * time.Minute,
+				helmWaitMode:        helm.WaitModeAtomic,
+				helmTimeout:         10 * time.Minute,
+				planOutput:          planOutputText,
+				controlPlaneTimeout: 20 * time.Minute,
 			},
 		},
 		"skip phases": {
@@ -112,9 +124,11 @@ func TestParseApplyFlags(t *testing.T) {
 				return flags
 			}(),
 			wantFlags: applyFlags{
-				skipPhases:   newPhases(skipHelmPhase, skipK8sPhase),
-				helmWaitMode: helm.WaitModeAtomic,
-				helmTimeout:  10 * time.Minute,
+				skipPhases:          newPhases(skipHelmPhase, skipK8sPhase),
+				helmWaitMode:        helm.WaitModeAtomic,
+				helmTimeout:         10 * time.Minute,
+				planOutput:          planOutputText,
+				controlPlaneTimeout: 20 * time.Minute,
 			},
 		},
 		"skip helm wait": {
@@ -124,10 +138,59 @@ func TestParseApplyFlags(t *testing.T) {
 				return flags
 			}(),
 			wantFlags: applyFlags{
-				helmWaitMode: helm.WaitModeNone,
-				helmTimeout:  10 * time.Minute,
+				helmWaitMode:        helm.WaitModeNone,
+				helmTimeout:         10 * time.Minute,
+				planOutput:          planOutputText,
+				controlPlaneTimeout: 20 * time.Minute,
 			},
 		},
+		"wait for control plane": {
+			flags: func() *pflag.FlagSet {
+				flags := defaultFlags()
+				require.NoError(flags.Set("wait-for-control-plane", "true"))
+				require.NoError(flags.Set("control-plane-timeout", "30m"))
+				return flags
+			}(),
+			wantFlags: applyFlags{
+				helmWaitMode:        helm.WaitModeAtomic,
+				helmTimeout:         10 * time.Minute,
+				planOutput:          planOutputText,
+				waitForControlPlane: true,
+				controlPlaneTimeout: 30 * time.Minute,
+			},
+		},
+		"plan with json output": {
+			flags: func() *pflag.FlagSet {
+				flags := defaultFlags()
+				require.NoError(flags.Set("plan", "true"))
+				require.NoError(flags.Set("output", planOutputJSON))
+				return flags
+			}(),
+			wantFlags: applyFlags{
+				helmWaitMode:        helm.WaitModeAtomic,
+				helmTimeout:         10 * time.Minute,
+				plan:                true,
+				planOutput:          planOutputJSON,
+				controlPlaneTimeout: 20 * time.Minute,
+			},
+		},
+		"output without plan": {
+			flags: func() *pflag.FlagSet {
+				flags := defaultFlags()
+				require.NoError(flags.Set("output", planOutputJSON))
+				return flags
+			}(),
+			wantErr: true,
+		},
+		"invalid output format": {
+			flags: func() *pflag.FlagSet {
+				flags := defaultFlags()
+				require.NoError(flags.Set("plan", "true"))
+				require.NoError(flags.Set("output", "yaml"))
+				return flags
+			}(),
+			wantErr: true,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -221,6 +284,7 @@ func TestSkipPhases(t *testing.T) {
 	cmd.Flags().String("workspace", "", "")
 	cmd.Flags().Bool("force", true, "")
 	cmd.Flags().String("tf-log", "NONE", "")
+	cmd.Flags().String("profile", "", "")
 	cmd.Flags().Bool("debug", false, "")
 
 	require.NoError(cmd.Flags().Set("skip-phases", strings.Join(allPhases(), ",")))
@@ -292,6 +356,7 @@ func TestValidateInputs(t *testing.T) {
 		stdin              string
 		flags              applyFlags
 		wantPhases         skipPhases
+		wantWaitForCP      bool
 		assert             func(require *require.Assertions, assert *assert.Assertions, conf *config.Config, stateFile *state.State)
 		wantErr            bool
 	}{
@@ -304,6 +369,18 @@ func TestValidateInputs(t *testing.T) {
 			flags:              applyFlags{},
 			wantPhases:         newPhases(skipInitPhase),
 		},
+		"[upgrade] gcp: state is locked by another operation": {
+			createConfig: defaultConfig(cloudprovider.GCP),
+			createState: func(require *require.Assertions, fh file.Handler) {
+				postInitState(cloudprovider.GCP)(require, fh)
+				require.NoError(fh.WriteJSON(constants.StateFilename+".lock", backend.LockInfo{ID: "other", Operation: "apply"}))
+			},
+			createMasterSecret: defaultMasterSecret,
+			createAdminConfig:  defaultAdminConfig,
+			createTfState:      defaultTfState,
+			flags:              applyFlags{},
+			wantErr:            true,
+		},
 		"[upgrade] aws: all files exist": {
 			createConfig:       defaultConfig(cloudprovider.AWS),
 			createState:        postInitState(cloudprovider.AWS),
@@ -331,6 +408,15 @@ func TestValidateInputs(t *testing.T) {
 			flags:              applyFlags{},
 			wantPhases:         newPhases(skipInitPhase, skipImagePhase), // No image upgrades on QEMU
 		},
+		"[upgrade] wait for control plane is ignored": {
+			createConfig:       defaultConfig(cloudprovider.GCP),
+			createState:        postInitState(cloudprovider.GCP),
+			createMasterSecret: defaultMasterSecret,
+			createAdminConfig:  defaultAdminConfig,
+			createTfState:      defaultTfState,
+			flags:              applyFlags{waitForControlPlane: true},
+			wantPhases:         newPhases(skipInitPhase),
+		},
 		"no config file errors": {
 			createConfig:       func(require *require.Assertions, fh file.Handler) {},
 			createState:        postInitState(cloudprovider.GCP),
@@ -358,6 +444,16 @@ func TestValidateInputs(t *testing.T) {
 			flags:              applyFlags{},
 			wantPhases:         newPhases(skipImagePhase, skipK8sPhase),
 		},
+		"[init] wait for control plane": {
+			createConfig:       defaultConfig(cloudprovider.GCP),
+			createState:        preInitState(cloudprovider.GCP),
+			createMasterSecret: func(require *require.Assertions, fh file.Handler) {},
+			createAdminConfig:  func(require *require.Assertions, fh file.Handler) {},
+			createTfState:      defaultTfState,
+			flags:              applyFlags{waitForControlPlane: true},
+			wantPhases:         newPhases(skipImagePhase, skipK8sPhase),
+			wantWaitForCP:      true,
+		},
 		"[create] no tf state, but admin config exists errors": {
 			createConfig:       defaultConfig(cloudprovider.GCP),
 			createState:        preInitState(cloudprovider.GCP),
@@ -458,6 +554,7 @@ func TestValidateInputs(t *testing.T) {
 				t.Log(cfgErr.LongMessage())
 			}
 			assert.Equal(tc.wantPhases, a.flags.skipPhases)
+			assert.Equal(tc.wantWaitForCP, a.flags.waitForControlPlane)
 
 			if tc.assert != nil {
 				tc.assert(require, assert, conf, state)
@@ -466,6 +563,86 @@ func TestValidateInputs(t *testing.T) {
 	}
 }
 
+func TestRunControlPlaneWait(t *testing.T) {
+	joined := constellation.ControlPlaneNode{Name: "control-plane-0", Ready: true, Attested: true, EtcdMember: true}
+	pending := constellation.ControlPlaneNode{Name: "control-plane-1", Ready: true}
+
+	testCases := map[string]struct {
+		controlPlaneCount int
+		upgrader          *stubKubernetesUpgrader
+		wantWait          bool
+		wantErr           bool
+	}{
+		"all nodes joined": {
+			controlPlaneCount: 2,
+			upgrader:          &stubKubernetesUpgrader{controlPlaneNodes: []constellation.ControlPlaneNode{joined, joined}},
+			wantWait:          true,
+		},
+		"single control-plane node": {
+			controlPlaneCount: 1,
+			upgrader:          &stubKubernetesUpgrader{},
+		},
+		"not all nodes joined": {
+			controlPlaneCount: 2,
+			upgrader: &stubKubernetesUpgrader{
+				controlPlaneNodes:   []constellation.ControlPlaneNode{joined, pending},
+				waitControlPlaneErr: &constellation.ControlPlaneJoinError{Nodes: []constellation.ControlPlaneNode{joined, pending}, Requested: 2},
+			},
+			wantWait: true,
+			wantErr:  true,
+		},
+		"no quorum": {
+			controlPlaneCount: 3,
+			upgrader: &stubKubernetesUpgrader{
+				controlPlaneNodes:   []constellation.ControlPlaneNode{joined, pending},
+				waitControlPlaneErr: &constellation.ControlPlaneJoinError{Nodes: []constellation.ControlPlaneNode{joined, pending}, Requested: 3},
+			},
+			wantWait: true,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			conf := config.Default()
+			for name, group := range conf.NodeGroups {
+				if group.Role == role.ControlPlane.TFString() {
+					group.InitialCount = tc.controlPlaneCount
+					conf.NodeGroups[name] = group
+				}
+			}
+
+			cmd := NewApplyCmd()
+			var out, errOut bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&errOut)
+			cmd.SetContext(context.Background())
+
+			a := applyCmd{
+				log:     logger.NewTest(t),
+				flags:   applyFlags{controlPlaneTimeout: time.Minute},
+				applier: &stubConstellApplier{stubKubernetesUpgrader: tc.upgrader},
+			}
+
+			err := a.runControlPlaneWait(cmd, conf)
+			assert.Equal(tc.wantWait, tc.upgrader.calledControlPlaneWait)
+			if tc.wantErr {
+				assert.Error(err)
+				for _, node := range tc.upgrader.controlPlaneNodes {
+					assert.Contains(err.Error(), node.Name)
+				}
+				return
+			}
+			assert.NoError(err)
+			for _, node := range tc.upgrader.controlPlaneNodes {
+				assert.Contains(out.String(), node.Name)
+			}
+		})
+	}
+}
+
 func TestSkipPhasesCompletion(t *testing.T) {
 	testCases := map[string]struct {
 		toComplete      string
@@ -516,6 +693,195 @@ func TestSkipPhasesCompletion(t *testing.T) {
 	}
 }
 
+func TestPlanChanges(t *testing.T) {
+	newNodeVersion := func(imageVersion, k8sVersion string) kubecmd.NodeVersion {
+		nodeVersion, err := kubecmd.NewNodeVersion(updatev1alpha1.NodeVersion{
+			Spec: updatev1alpha1.NodeVersionSpec{
+				ImageVersion:             imageVersion,
+				KubernetesClusterVersion: k8sVersion,
+			},
+			Status: updatev1alpha1.NodeVersionStatus{
+				Conditions: []metav1.Condition{{Message: "up to date"}},
+			},
+		})
+		require.NoError(t, err)
+		return nodeVersion
+	}
+	conf := defaultConfigWithExpectedMeasurements(t, config.Default(), cloudprovider.QEMU)
+	conf.Image = "v2.14.0"
+	targetImage, err := parseImageVersion(conf.Image)
+	require.NoError(t, err)
+	helmChanges := []helm.ReleaseChange{{
+		ReleaseName:    "constellation-services",
+		Action:         helm.ReleaseActionUpgrade,
+		CurrentVersion: "v1.0.0",
+		TargetVersion:  "v1.1.0",
+	}}
+
+	testCases := map[string]struct {
+		skipPhases   skipPhases
+		infraApplier *stubCloudCreator
+		kubeUpgrader *stubKubernetesUpgrader
+		helmApplier  stubHelmApplier
+		wantPlan     applyPlan
+		wantWarnings int
+		wantErr      bool
+	}{
+		"upgrade without changes": {
+			skipPhases:   newPhases(skipInitPhase),
+			infraApplier: &stubCloudCreator{},
+			kubeUpgrader: &stubKubernetesUpgrader{
+				currentConfig: conf.GetAttestationConfig(),
+				nodeVersion:   newNodeVersion(targetImage.String(), string(conf.KubernetesVersion)),
+			},
+			wantPlan: applyPlan{
+				Infrastructure:    &infrastructurePlan{},
+				AttestationConfig: &attestationConfigPlan{},
+				CertSANs:          &certSANsPlan{},
+				Helm:              &helmPlan{},
+				Image:             &versionPlan{Current: targetImage.String(), Target: targetImage.String()},
+				Kubernetes:        &versionPlan{Current: string(conf.KubernetesVersion), Target: string(conf.KubernetesVersion)},
+			},
+		},
+		"upgrade with changes": {
+			skipPhases:   newPhases(skipInitPhase),
+			infraApplier: &stubCloudCreator{planDiff: true, planDiffOutput: "terraform diff"},
+			kubeUpgrader: &stubKubernetesUpgrader{
+				getClusterAttestationConfigErr: k8serrors.NewNotFound(schema.GroupResource{}, "join-config"),
+				missingCertSANs:                []string{"example.com"},
+				nodeVersion:                    newNodeVersion("v2.13.0", string(conf.KubernetesVersion)),
+			},
+			helmApplier: stubHelmApplier{changes: helmChanges},
+			wantPlan: applyPlan{
+				Infrastructure:    &infrastructurePlan{Changed: true, Diff: "terraform diff"},
+				AttestationConfig: &attestationConfigPlan{Changed: true},
+				CertSANs:          &certSANsPlan{Added: []string{"example.com"}},
+				Helm:              &helmPlan{Releases: helmChanges},
+				Image:             &versionPlan{Current: "v2.13.0", Target: targetImage.String(), Changed: true},
+				Kubernetes:        &versionPlan{Current: string(conf.KubernetesVersion), Target: string(conf.KubernetesVersion)},
+			},
+		},
+		"invalid upgrades are reported as warnings": {
+			skipPhases: newPhases(skipInfrastructurePhase, skipInitPhase, skipAttestationConfigPhase, skipCertSANsPhase, skipK8sPhase),
+			kubeUpgrader: &stubKubernetesUpgrader{
+				nodeVersion: newNodeVersion("v100.0.0", "v0.0.0"),
+			},
+			helmApplier: stubHelmApplier{err: compatibility.NewInvalidUpgradeError("v1.0.0", "v0.1.0", assert.AnError)},
+			wantPlan: applyPlan{
+				Helm:  &helmPlan{},
+				Image: &versionPlan{Current: "v100.0.0", Target: targetImage.String(), Changed: true},
+			},
+			wantWarnings: 2,
+		},
+		"cluster needs to be initialized": {
+			infraApplier: &stubCloudCreator{planDiff: true, workspaceIsEmpty: true, planDiffOutput: "terraform diff"},
+			kubeUpgrader: &stubKubernetesUpgrader{},
+			wantPlan: applyPlan{
+				Infrastructure: &infrastructurePlan{NewCluster: true, Changed: true, Diff: "terraform diff"},
+				Init: &initPlan{
+					Image:               conf.Image,
+					KubernetesVersion:   string(conf.KubernetesVersion),
+					MicroserviceVersion: conf.MicroserviceVersion.String(),
+				},
+			},
+		},
+		"infrastructure plan fails": {
+			skipPhases:   newPhases(skipInitPhase),
+			infraApplier: &stubCloudCreator{planErr: assert.AnError},
+			kubeUpgrader: &stubKubernetesUpgrader{},
+			wantErr:      true,
+		},
+		"getting attestation config fails": {
+			skipPhases:   newPhases(skipInfrastructurePhase, skipInitPhase),
+			kubeUpgrader: &stubKubernetesUpgrader{getClusterAttestationConfigErr: assert.AnError},
+			wantErr:      true,
+		},
+		"preparing Helm charts fails": {
+			skipPhases:   newPhases(skipInfrastructurePhase, skipInitPhase),
+			kubeUpgrader: &stubKubernetesUpgrader{currentConfig: conf.GetAttestationConfig()},
+			helmApplier:  stubHelmApplier{err: assert.AnError},
+			wantErr:      true,
+		},
+		"getting NodeVersion fails": {
+			skipPhases:   newPhases(skipInfrastructurePhase, skipInitPhase),
+			kubeUpgrader: &stubKubernetesUpgrader{currentConfig: conf.GetAttestationConfig(), getNodeVersionErr: assert.AnError},
+			wantErr:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			require.NoError(fileHandler.Write(constants.AdminConfFilename, []byte("admin config")))
+			require.NoError(fileHandler.WriteJSON(constants.MasterSecretFilename, &uri.MasterSecret{}))
+
+			a := &applyCmd{
+				fileHandler: fileHandler,
+				flags:       applyFlags{skipPhases: tc.skipPhases},
+				log:         logger.NewTest(t),
+				spinner:     &nopSpinner{},
+				applier: &stubConstellApplier{
+					stubKubernetesUpgrader: tc.kubeUpgrader,
+					helmApplier:            tc.helmApplier,
+				},
+				newInfraApplier: func(context.Context) (cloudApplier, func(), error) {
+					return tc.infraApplier, func() {}, nil
+				},
+			}
+
+			plan, err := a.planChanges(&cobra.Command{}, conf, defaultStateFile(cloudprovider.QEMU), "upgrade-dir")
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+
+			// the attestation config diff is tested separately
+			if plan.AttestationConfig != nil && tc.wantPlan.AttestationConfig != nil {
+				assert.Equal(tc.wantPlan.AttestationConfig.Changed, plan.AttestationConfig.Changed)
+				plan.AttestationConfig.Diff = ""
+			}
+			assert.Len(plan.Warnings, tc.wantWarnings)
+			plan.Warnings = nil
+			assert.Equal(tc.wantPlan, plan)
+		})
+	}
+}
+
+func TestApplyPlanString(t *testing.T) {
+	plan := applyPlan{
+		Infrastructure: &infrastructurePlan{Changed: true, Diff: "~ resource changed"},
+		CertSANs:       &certSANsPlan{Added: []string{"example.com", "192.0.2.2"}},
+		Helm: &helmPlan{Releases: []helm.ReleaseChange{
+			{ReleaseName: "cilium", Action: helm.ReleaseActionUpgrade, CurrentVersion: "v1.0.0", TargetVersion: "v1.1.0"},
+			{ReleaseName: "aws-load-balancer-controller", Action: helm.ReleaseActionInstall, TargetVersion: "v1.5.4", ValuesDiff: "+foo: bar\n"},
+		}},
+		Image:      &versionPlan{Current: "v2.13.0", Target: "v2.14.0", Changed: true},
+		Kubernetes: &versionPlan{Current: "v1.28.5", Target: "v1.28.5"},
+		Warnings:   []string{"some warning"},
+	}
+
+	want := "Infrastructure:\n" +
+		"\tThe following Terraform changes will be applied:\n" +
+		"\t~ resource changed\n" +
+		"Cert SANs:\n" +
+		"\tThe following SANs will be added: example.com, 192.0.2.2\n" +
+		"Helm charts:\n" +
+		"\tcilium will be upgraded from v1.0.0 to v1.1.0\n" +
+		"\taws-load-balancer-controller will be installed in version v1.5.4\n" +
+		"\t\t+foo: bar\n" +
+		"Image:\n" +
+		"\tv2.13.0 will be upgraded to v2.14.0\n" +
+		"Kubernetes:\n" +
+		"\tNo changes, staying at v1.28.5.\n" +
+		"Warnings:\n" +
+		"\tsome warning\n"
+	assert.Equal(t, want, plan.String())
+}
+
 func newPhases(phases ...skipPhase) skipPhases {
 	skipPhases := skipPhases{}
 	skipPhases.add(phases...)
@@ -548,7 +914,7 @@ func (s *stubConstellApplier) GenerateMeasurementSalt() ([]byte, error) {
 	return s.measurementSalt, s.generateMeasurementSaltErr
 }
 
-func (s *stubConstellApplier) Init(context.Context, atls.Validator, *state.State, io.Writer, constellation.InitPayload) (constellation.InitOutput, error) {
+func (s *stubConstellApplier) Init(context.Context, atls.Validator, *state.State, constellation.InitPayload) (constellation.InitOutput, error) {
 	return s.initOutput, s.initErr
 }
 
@@ -558,3 +924,123 @@ type helmApplier interface {
 	) (
 		helm.Applier, bool, error)
 }
+
+func TestLockAndReadState(t *testing.T) {
+	localState := state.New().SetClusterValues(state.ClusterValues{ClusterID: "local"})
+	remoteState := state.New().SetClusterValues(state.ClusterValues{ClusterID: "remote"})
+
+	testCases := map[string]struct {
+		remoteConfig  bool
+		localState    *state.State
+		remoteState   *state.State
+		plan          bool
+		wantClusterID string
+		wantMigrated  bool
+	}{
+		"local state is migrated to remote backend": {
+			remoteConfig:  true,
+			localState:    localState,
+			wantClusterID: "local",
+			wantMigrated:  true,
+		},
+		"remote backend holds state": {
+			remoteConfig:  true,
+			localState:    localState,
+			remoteState:   remoteState,
+			wantClusterID: "remote",
+		},
+		"new state in remote backend": {
+			remoteConfig: true,
+		},
+		"local backend": {
+			localState:    localState,
+			wantClusterID: "local",
+		},
+		"plan reads local state without migrating it": {
+			remoteConfig:  true,
+			localState:    localState,
+			plan:          true,
+			wantClusterID: "local",
+		},
+		"plan reads remote state": {
+			remoteConfig:  true,
+			localState:    localState,
+			remoteState:   remoteState,
+			plan:          true,
+			wantClusterID: "remote",
+		},
+		"plan without state": {
+			remoteConfig: true,
+			plan:         true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			if tc.localState != nil {
+				require.NoError(tc.localState.WriteToFile(fileHandler, constants.StateFilename))
+			}
+			conf := &config.Config{}
+			stateBackend := backend.NewLocal(fileHandler, constants.StateFilename)
+			if tc.remoteConfig {
+				conf.StateBackend = &config.StateBackendConfig{Kubernetes: &config.KubernetesStateBackendConfig{}}
+				stateBackend = backend.NewLocal(fileHandler, "remote/state.yaml")
+			}
+			if tc.remoteState != nil {
+				require.NoError(tc.remoteState.WriteToBackend(context.Background(), stateBackend))
+			}
+
+			cmd := NewApplyCmd()
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetContext(context.Background())
+			a := &applyCmd{
+				fileHandler:  fileHandler,
+				log:          logger.NewTest(t),
+				stateBackend: stateBackend,
+				flags:        applyFlags{plan: tc.plan},
+			}
+
+			stateFile, err := a.lockAndReadState(cmd, conf)
+			require.NoError(err)
+			if tc.plan {
+				// planning must not lock or change the state
+				assert.Empty(a.stateLockID)
+				_, err = fileHandler.Stat(constants.MigratedStateFilename)
+				assert.ErrorIs(err, fs.ErrNotExist)
+				if tc.localState != nil {
+					stored, err := state.ReadFromFile(fileHandler, constants.StateFilename)
+					require.NoError(err)
+					assert.Equal(tc.localState.ClusterValues.ClusterID, stored.ClusterValues.ClusterID)
+				}
+				stored, err := state.ReadFromBackend(context.Background(), stateBackend)
+				if tc.remoteState == nil {
+					assert.ErrorIs(err, fs.ErrNotExist)
+				} else {
+					require.NoError(err)
+					assert.Equal(tc.remoteState.ClusterValues.ClusterID, stored.ClusterValues.ClusterID)
+				}
+				assert.Equal(tc.wantClusterID, stateFile.ClusterValues.ClusterID)
+				return
+			}
+			a.unlockState(cmd)
+			assert.Equal(tc.wantClusterID, stateFile.ClusterValues.ClusterID)
+
+			stored, err := state.ReadFromBackend(context.Background(), stateBackend)
+			require.NoError(err)
+			assert.Equal(tc.wantClusterID, stored.ClusterValues.ClusterID)
+
+			_, err = fileHandler.Stat(constants.MigratedStateFilename)
+			if tc.wantMigrated {
+				assert.NoError(err)
+				_, err = fileHandler.Stat(constants.StateFilename)
+				assert.ErrorIs(err, fs.ErrNotExist)
+			} else {
+				assert.ErrorIs(err, fs.ErrNotExist)
+			}
+		})
+	}
+}
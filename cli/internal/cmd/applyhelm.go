@@ -12,7 +12,7 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/compatibility"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
@@ -31,18 +31,7 @@ func (a *applyCmd) runHelmApply(cmd *cobra.Command, conf *config.Config, stateFi
 		return fmt.Errorf("reading master secret: %w", err)
 	}
 
-	options := helm.Options{
-		CSP:                 conf.GetProvider(),
-		AttestationVariant:  conf.GetAttestationConfig().GetVariant(),
-		K8sVersion:          conf.KubernetesVersion,
-		MicroserviceVersion: conf.MicroserviceVersion,
-		DeployCSIDriver:     conf.DeployCSIDriver(),
-		Force:               a.flags.force,
-		Conformance:         a.flags.conformance,
-		HelmWaitMode:        a.flags.helmWaitMode,
-		ApplyTimeout:        a.flags.helmTimeout,
-		AllowDestructive:    helm.DenyDestructive,
-	}
+	options := a.helmOptions(conf)
 
 	a.log.Debugf("Getting service account URI")
 	serviceAccURI, err := cloudcmd.GetMarshaledServiceAccountURI(conf, a.fileHandler)
@@ -99,6 +88,24 @@ func (a *applyCmd) runHelmApply(cmd *cobra.Command, conf *config.Config, stateFi
 	return nil
 }
 
+// helmOptions returns the options to load the Helm charts for the given config.
+// Destructive actions are denied by default.
+func (a *applyCmd) helmOptions(conf *config.Config) helm.Options {
+	return helm.Options{
+		CSP:                 conf.GetProvider(),
+		AttestationVariant:  conf.GetAttestationConfig().GetVariant(),
+		K8sVersion:          conf.KubernetesVersion,
+		MicroserviceVersion: conf.MicroserviceVersion,
+		DeployCSIDriver:     conf.DeployCSIDriver(),
+		PodCIDR:             conf.PodCIDR,
+		Force:               a.flags.force,
+		Conformance:         a.flags.conformance,
+		HelmWaitMode:        a.flags.helmWaitMode,
+		ApplyTimeout:        a.flags.helmTimeout,
+		AllowDestructive:    helm.DenyDestructive,
+	}
+}
+
 // backupHelmCharts saves the Helm charts for the upgrade to disk and creates a backup of existing CRDs and CRs.
 func (a *applyCmd) backupHelmCharts(
 	ctx context.Context, executor helm.Applier, includesUpgrades bool, upgradeDir string,
@@ -112,11 +119,11 @@ func (a *applyCmd) backupHelmCharts(
 
 	if includesUpgrades {
 		a.log.Debugf("Creating backup of CRDs and CRs")
-		crds, err := a.applier.BackupCRDs(ctx, a.fileHandler, upgradeDir)
+		crds, err := a.applier.BackupCRDs(ctx, a.fileHandler.Fs(), upgradeDir)
 		if err != nil {
 			return fmt.Errorf("creating CRD backup: %w", err)
 		}
-		if err := a.applier.BackupCRs(ctx, a.fileHandler, crds, upgradeDir); err != nil {
+		if err := a.applier.BackupCRs(ctx, a.fileHandler.Fs(), crds, upgradeDir); err != nil {
 			return fmt.Errorf("creating CR backup: %w", err)
 		}
 	}
@@ -8,19 +8,21 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"path/filepath"
 	"text/tabwriter"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/spf13/cobra"
 )
 
@@ -36,34 +38,53 @@ func (a *applyCmd) runInit(cmd *cobra.Command, conf *config.Config, stateFile *s
 	}
 
 	a.log.Debugf("Running init RPC")
-	masterSecret, err := a.generateAndPersistMasterSecret(cmd.OutOrStdout())
+	restored, err := a.readRestoredSecrets()
 	if err != nil {
-		return nil, fmt.Errorf("generating master secret: %w", err)
+		return nil, err
 	}
 
-	measurementSalt, err := a.applier.GenerateMeasurementSalt()
-	if err != nil {
-		return nil, fmt.Errorf("generating measurement salt: %w", err)
+	var masterSecret uri.MasterSecret
+	var measurementSalt []byte
+	if restored != nil {
+		a.log.Debugf("Reusing secrets restored from backup")
+		masterSecret, measurementSalt = restored.MasterSecret, restored.MeasurementSalt
+		if err := a.persistMasterSecret(masterSecret, cmd.OutOrStdout()); err != nil {
+			return nil, err
+		}
+	} else {
+		masterSecret, err = a.generateAndPersistMasterSecret(cmd.OutOrStdout())
+		if err != nil {
+			return nil, fmt.Errorf("generating master secret: %w", err)
+		}
+	}
+
+	if len(measurementSalt) == 0 {
+		measurementSalt, err = a.applier.GenerateMeasurementSalt()
+		if err != nil {
+			return nil, fmt.Errorf("generating measurement salt: %w", err)
+		}
 	}
 
-	clusterLogs := &bytes.Buffer{}
 	resp, err := a.applier.Init(
-		cmd.Context(), validator, stateFile, clusterLogs,
+		cmd.Context(), validator, stateFile,
 		constellation.InitPayload{
-			MasterSecret:    masterSecret,
-			MeasurementSalt: measurementSalt,
-			K8sVersion:      conf.KubernetesVersion,
-			ConformanceMode: a.flags.conformance,
-			ServiceCIDR:     conf.ServiceCIDR,
+			MasterSecret:          masterSecret,
+			MeasurementSalt:       measurementSalt,
+			K8sVersion:            conf.KubernetesVersion,
+			ConformanceMode:       a.flags.conformance,
+			ServiceCIDR:           conf.ServiceCIDR,
+			PodCIDR:               conf.PodCIDR,
+			KubernetesOverrides:   conf.KubernetesOverrides,
+			DiskEncryptionProfile: conf.DiskEncryptionProfile,
 		})
-	if len(clusterLogs.Bytes()) > 0 {
-		if err := a.fileHandler.Write(constants.ErrorLog, clusterLogs.Bytes(), file.OptAppend); err != nil {
-			return nil, fmt.Errorf("writing bootstrapper logs: %w", err)
-		}
-	}
 	if err != nil {
 		var nonRetriable *constellation.NonRetriableInitError
 		if errors.As(err, &nonRetriable) {
+			if len(nonRetriable.ClusterLogs) > 0 {
+				if err := a.fileHandler.Write(constants.ErrorLog, nonRetriable.ClusterLogs, file.OptAppend); err != nil {
+					return nil, fmt.Errorf("writing bootstrapper logs: %w", err)
+				}
+			}
 			cmd.PrintErrln("Cluster initialization failed. This error is not recoverable.")
 			cmd.PrintErrln("Terminate your cluster and try again.")
 			if nonRetriable.LogCollectionErr != nil {
@@ -78,9 +99,14 @@ func (a *applyCmd) runInit(cmd *cobra.Command, conf *config.Config, stateFile *s
 
 	a.log.Debugf("Buffering init success message")
 	bufferedOutput := &bytes.Buffer{}
-	if err := a.writeInitOutput(stateFile, resp, a.flags.mergeConfigs, bufferedOutput, measurementSalt); err != nil {
+	if err := a.writeInitOutput(cmd.Context(), stateFile, resp, a.flags.mergeConfigs, bufferedOutput, measurementSalt); err != nil {
 		return nil, err
 	}
+	if restored != nil {
+		if err := a.fileHandler.Remove(constants.RestoredSecretsFilename); err != nil {
+			return nil, fmt.Errorf("removing %q: %w", a.flags.pathPrefixer.PrefixPrintablePath(constants.RestoredSecretsFilename), err)
+		}
+	}
 
 	return bufferedOutput, nil
 }
@@ -91,17 +117,37 @@ func (a *applyCmd) generateAndPersistMasterSecret(outWriter io.Writer) (uri.Mast
 	if err != nil {
 		return uri.MasterSecret{}, fmt.Errorf("generating master secret: %w", err)
 	}
+	if err := a.persistMasterSecret(secret, outWriter); err != nil {
+		return uri.MasterSecret{}, err
+	}
+	return secret, nil
+}
+
+// persistMasterSecret saves the master secret to disk, without overwriting an existing one.
+func (a *applyCmd) persistMasterSecret(secret uri.MasterSecret, outWriter io.Writer) error {
 	if err := a.fileHandler.WriteJSON(constants.MasterSecretFilename, secret, file.OptNone); err != nil {
-		return uri.MasterSecret{}, fmt.Errorf("writing master secret: %w", err)
+		return fmt.Errorf("writing master secret: %w", err)
 	}
 	fmt.Fprintf(outWriter, "Your Constellation master secret was successfully written to %q\n", a.flags.pathPrefixer.PrefixPrintablePath(constants.MasterSecretFilename))
-	return secret, nil
+	return nil
+}
+
+// readRestoredSecrets reads the secrets written by 'constellation restore'.
+// It returns nil if the workspace wasn't restored from a backup.
+func (a *applyCmd) readRestoredSecrets() (*restoredSecrets, error) {
+	var restored restoredSecrets
+	if err := a.fileHandler.ReadJSON(constants.RestoredSecretsFilename, &restored); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", a.flags.pathPrefixer.PrefixPrintablePath(constants.RestoredSecretsFilename), err)
+	}
+	return &restored, nil
 }
 
 // writeInitOutput writes the output of a cluster initialization to the
 // state- / kubeconfig-file and saves it to disk.
 func (a *applyCmd) writeInitOutput(
-	stateFile *state.State, initResp constellation.InitOutput,
+	ctx context.Context, stateFile *state.State, initResp constellation.InitOutput,
 	mergeConfig bool, wr io.Writer, measurementSalt []byte,
 ) error {
 	fmt.Fprint(wr, "Your Constellation cluster was successfully initialized.\n\n")
@@ -132,7 +178,7 @@ func (a *applyCmd) writeInitOutput(
 		}
 	}
 
-	if err := stateFile.WriteToFile(a.fileHandler, constants.StateFilename); err != nil {
+	if err := stateFile.WriteToBackend(ctx, a.stateStore()); err != nil {
 		return fmt.Errorf("writing Constellation state file: %w", err)
 	}
 
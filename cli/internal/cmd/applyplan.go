@@ -0,0 +1,396 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/compatibility"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// planOutputText prints the plan in a human-readable format.
+	planOutputText = "text"
+	// planOutputJSON prints the plan as JSON.
+	planOutputJSON = "json"
+)
+
+// applyPlan lists the changes "constellation apply" would make, grouped by phase.
+// Phases that are skipped are nil.
+type applyPlan struct {
+	Infrastructure    *infrastructurePlan    `json:"infrastructure,omitempty"`
+	Init              *initPlan              `json:"init,omitempty"`
+	AttestationConfig *attestationConfigPlan `json:"attestationConfig,omitempty"`
+	CertSANs          *certSANsPlan          `json:"certSANs,omitempty"`
+	Helm              *helmPlan              `json:"helm,omitempty"`
+	Image             *versionPlan           `json:"image,omitempty"`
+	Kubernetes        *versionPlan           `json:"kubernetes,omitempty"`
+	// Warnings lists changes that would be skipped or require confirmation during apply.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// infrastructurePlan describes the changes to the cloud resources.
+type infrastructurePlan struct {
+	// NewCluster is true if the cloud resources don't exist yet.
+	NewCluster bool `json:"newCluster"`
+	// Changed is true if Terraform changes are required.
+	Changed bool `json:"changed"`
+	// Diff is the Terraform plan.
+	Diff string `json:"diff,omitempty"`
+}
+
+// initPlan describes the cluster that would be initialized.
+// Attestation config, cert SANs and Helm charts are installed as part of the initialization.
+type initPlan struct {
+	Image               string `json:"image"`
+	KubernetesVersion   string `json:"kubernetesVersion"`
+	MicroserviceVersion string `json:"microserviceVersion"`
+}
+
+// attestationConfigPlan describes the changes to the cluster's attestation config.
+type attestationConfigPlan struct {
+	Changed bool   `json:"changed"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+// certSANsPlan describes the SANs that would be added to the API server certificate.
+type certSANsPlan struct {
+	Added []string `json:"added,omitempty"`
+}
+
+// helmPlan describes the Helm releases that would be installed or upgraded.
+type helmPlan struct {
+	Releases []helm.ReleaseChange `json:"releases,omitempty"`
+}
+
+// versionPlan describes a change of the version set in the NodeVersion resource.
+type versionPlan struct {
+	Current string `json:"current"`
+	Target  string `json:"target"`
+	Changed bool   `json:"changed"`
+}
+
+// runPlan collects the pending changes of all phases that are not skipped and prints them.
+// Neither the workspace nor the cluster are modified.
+func (a *applyCmd) runPlan(cmd *cobra.Command, conf *config.Config, stateFile *state.State, upgradeDir string) error {
+	plan, err := a.planChanges(cmd, conf, stateFile, upgradeDir)
+	if err != nil {
+		return err
+	}
+
+	switch a.flags.planOutput {
+	case planOutputJSON:
+		out, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling plan: %w", err)
+		}
+		cmd.Println(string(out))
+	default:
+		cmd.Print(plan.String())
+	}
+	return nil
+}
+
+func (a *applyCmd) planChanges(cmd *cobra.Command, conf *config.Config, stateFile *state.State, upgradeDir string) (applyPlan, error) {
+	var plan applyPlan
+
+	if !a.flags.skipPhases.contains(skipInfrastructurePhase) {
+		infraPlan, err := a.planInfrastructure(cmd, conf, upgradeDir)
+		if err != nil {
+			return applyPlan{}, fmt.Errorf("planning infrastructure changes: %w", err)
+		}
+		plan.Infrastructure = &infraPlan
+	}
+
+	// If the cluster still needs to be initialized, there is nothing to compare the remaining phases against
+	if !a.flags.skipPhases.contains(skipInitPhase) {
+		plan.Init = &initPlan{
+			Image:               conf.Image,
+			KubernetesVersion:   string(conf.KubernetesVersion),
+			MicroserviceVersion: conf.MicroserviceVersion.String(),
+		}
+		return plan, nil
+	}
+
+	if a.flags.skipPhases.contains(skipAttestationConfigPhase, skipCertSANsPhase, skipHelmPhase, skipK8sPhase, skipImagePhase) {
+		return plan, nil
+	}
+
+	kubeConfig, err := a.fileHandler.Read(constants.AdminConfFilename)
+	if err != nil {
+		return applyPlan{}, fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	if err := a.applier.SetKubeConfig(kubeConfig); err != nil {
+		return applyPlan{}, err
+	}
+
+	if !a.flags.skipPhases.contains(skipAttestationConfigPhase) {
+		a.log.Debugf("Planning attestation config changes")
+		attestationPlan, err := a.planAttestationConfig(cmd, conf.GetAttestationConfig())
+		if err != nil {
+			return applyPlan{}, fmt.Errorf("planning attestation config changes: %w", err)
+		}
+		plan.AttestationConfig = &attestationPlan
+	}
+
+	if !a.flags.skipPhases.contains(skipCertSANsPhase) {
+		a.log.Debugf("Planning cert SAN changes")
+		missingSANs, err := a.applier.MissingClusterConfigCertSANs(
+			cmd.Context(),
+			stateFile.Infrastructure.ClusterEndpoint,
+			conf.CustomEndpoint,
+			stateFile.Infrastructure.APIServerCertSANs,
+		)
+		if err != nil {
+			return applyPlan{}, fmt.Errorf("planning cert SAN changes: %w", err)
+		}
+		plan.CertSANs = &certSANsPlan{Added: missingSANs}
+	}
+
+	if !a.flags.skipPhases.contains(skipHelmPhase) {
+		a.log.Debugf("Planning Helm changes")
+		releases, warnings, err := a.planHelmCharts(conf, stateFile)
+		if err != nil {
+			return applyPlan{}, fmt.Errorf("planning Helm changes: %w", err)
+		}
+		plan.Helm = &helmPlan{Releases: releases}
+		plan.Warnings = append(plan.Warnings, warnings...)
+	}
+
+	if a.flags.skipPhases.contains(skipImagePhase, skipK8sPhase) {
+		return plan, nil
+	}
+
+	a.log.Debugf("Planning NodeVersion changes")
+	nodeVersion, err := a.applier.GetConstellationVersion(cmd.Context())
+	if err != nil {
+		return applyPlan{}, fmt.Errorf("getting NodeVersion: %w", err)
+	}
+
+	if !a.flags.skipPhases.contains(skipImagePhase) {
+		imageVersion, err := parseImageVersion(conf.Image)
+		if err != nil {
+			return applyPlan{}, err
+		}
+		plan.Image = a.planVersion(nodeVersion.ImageVersion(), imageVersion.String(), "image", &plan.Warnings)
+	}
+
+	if !a.flags.skipPhases.contains(skipK8sPhase) {
+		plan.Kubernetes = a.planVersion(nodeVersion.KubernetesVersion(), string(conf.KubernetesVersion), "Kubernetes", &plan.Warnings)
+	}
+
+	return plan, nil
+}
+
+// planInfrastructure returns the Terraform changes required for the given config.
+// The Terraform workspace is restored after planning.
+func (a *applyCmd) planInfrastructure(cmd *cobra.Command, conf *config.Config, upgradeDir string) (infrastructurePlan, error) {
+	terraformClient, removeClient, err := a.newInfraApplier(cmd.Context())
+	if err != nil {
+		return infrastructurePlan{}, fmt.Errorf("creating Terraform client: %w", err)
+	}
+	defer removeClient()
+	defer func() {
+		if err := a.fileHandler.RemoveAll(upgradeDir); err != nil {
+			a.log.Debugf("Removing upgrade directory %s failed: %s", upgradeDir, err)
+		}
+	}()
+
+	isNewCluster, err := terraformClient.WorkingDirIsEmpty()
+	if err != nil {
+		return infrastructurePlan{}, fmt.Errorf("checking if Terraform workspace is empty: %w", err)
+	}
+
+	a.spinner.Start("Checking for infrastructure changes", false)
+	changed, diff, err := terraformClient.PlanDiff(cmd.Context(), conf)
+	a.spinner.Stop()
+	if err != nil {
+		return infrastructurePlan{}, err
+	}
+	return infrastructurePlan{NewCluster: isNewCluster, Changed: changed, Diff: diff}, nil
+}
+
+// planAttestationConfig compares the configured attestation config with the one in the cluster.
+func (a *applyCmd) planAttestationConfig(cmd *cobra.Command, newConfig config.AttestationCfg) (attestationConfigPlan, error) {
+	clusterAttestationConfig, err := a.applier.GetClusterAttestationConfig(cmd.Context(), newConfig.GetVariant())
+	if k8serrors.IsNotFound(err) {
+		a.log.Debugf("No attestation config found in cluster, a new one would be created")
+		newYAML, err := yaml.Marshal(newConfig)
+		if err != nil {
+			return attestationConfigPlan{}, fmt.Errorf("marshalling attestation config: %w", err)
+		}
+		return attestationConfigPlan{Changed: true, Diff: string(newYAML)}, nil
+	}
+	if err != nil {
+		return attestationConfigPlan{}, fmt.Errorf("getting cluster attestation config: %w", err)
+	}
+
+	equal, err := newConfig.EqualTo(clusterAttestationConfig)
+	if err != nil {
+		return attestationConfigPlan{}, fmt.Errorf("comparing attestation configs: %w", err)
+	}
+	if equal {
+		return attestationConfigPlan{}, nil
+	}
+
+	diff, err := diffAttestationCfg(clusterAttestationConfig, newConfig)
+	if err != nil {
+		return attestationConfigPlan{}, fmt.Errorf("diffing attestation configs: %w", err)
+	}
+	return attestationConfigPlan{Changed: true, Diff: diff}, nil
+}
+
+// planHelmCharts returns the changes to the cluster's Helm releases.
+// Upgrades that would be skipped or require confirmation during apply are returned as warnings.
+func (a *applyCmd) planHelmCharts(conf *config.Config, stateFile *state.State) ([]helm.ReleaseChange, []string, error) {
+	var masterSecret uri.MasterSecret
+	if err := a.fileHandler.ReadJSON(constants.MasterSecretFilename, &masterSecret); err != nil {
+		return nil, nil, fmt.Errorf("reading master secret: %w", err)
+	}
+	serviceAccURI, err := cloudcmd.GetMarshaledServiceAccountURI(conf, a.fileHandler)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	options := a.helmOptions(conf)
+	executor, _, err := a.applier.PrepareHelmCharts(options, stateFile, serviceAccURI, masterSecret, conf.Provider.OpenStack)
+	if errors.Is(err, helm.ErrConfirmationMissing) {
+		warnings = append(warnings, "Upgrading cert-manager will destroy all custom resources you have manually created that are based on the current version of cert-manager.")
+		options.AllowDestructive = helm.AllowDestructive
+		executor, _, err = a.applier.PrepareHelmCharts(options, stateFile, serviceAccURI, masterSecret, conf.Provider.OpenStack)
+	}
+	var upgradeErr *compatibility.InvalidUpgradeError
+	if err != nil {
+		if !errors.As(err, &upgradeErr) {
+			return nil, nil, fmt.Errorf("preparing Helm charts: %w", err)
+		}
+		warnings = append(warnings, err.Error())
+	}
+
+	releases, err := executor.Changes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting Helm changes: %w", err)
+	}
+	return releases, warnings, nil
+}
+
+// planVersion compares the current and target version of a NodeVersion field.
+// If apply would skip the upgrade, a warning is added.
+func (a *applyCmd) planVersion(current, target, kind string, warnings *[]string) *versionPlan {
+	plan := &versionPlan{Current: current, Target: target, Changed: current != target}
+	if plan.Changed && !a.flags.force {
+		if err := compatibility.IsValidUpgrade(current, target); err != nil {
+			*warnings = append(*warnings, fmt.Sprintf("Skipping %s upgrade: %s", kind, err))
+		}
+	}
+	return plan
+}
+
+// String returns the human-readable representation of the plan.
+func (p applyPlan) String() string {
+	builder := strings.Builder{}
+
+	if p.Infrastructure != nil {
+		builder.WriteString("Infrastructure:\n")
+		switch {
+		case !p.Infrastructure.Changed:
+			builder.WriteString("\tNo changes.\n")
+		case p.Infrastructure.NewCluster:
+			builder.WriteString("\tThe cloud resources of a new cluster will be created:\n")
+			builder.WriteString(indentEntireStringWithTab(ensureTrailingNewline(p.Infrastructure.Diff)))
+		default:
+			builder.WriteString("\tThe following Terraform changes will be applied:\n")
+			builder.WriteString(indentEntireStringWithTab(ensureTrailingNewline(p.Infrastructure.Diff)))
+		}
+	}
+
+	if p.Init != nil {
+		builder.WriteString("Init:\n")
+		builder.WriteString(fmt.Sprintf("\tThe cluster will be initialized with image %s, Kubernetes %s and microservices %s.\n",
+			p.Init.Image, p.Init.KubernetesVersion, p.Init.MicroserviceVersion))
+		builder.WriteString("\tAttestation config, cert SANs and Helm charts will be installed during initialization.\n")
+	}
+
+	if p.AttestationConfig != nil {
+		builder.WriteString("Attestation config:\n")
+		if !p.AttestationConfig.Changed {
+			builder.WriteString("\tNo changes.\n")
+		} else {
+			builder.WriteString(indentEntireStringWithTab(ensureTrailingNewline(p.AttestationConfig.Diff)))
+		}
+	}
+
+	if p.CertSANs != nil {
+		builder.WriteString("Cert SANs:\n")
+		if len(p.CertSANs.Added) == 0 {
+			builder.WriteString("\tNo changes.\n")
+		} else {
+			builder.WriteString(fmt.Sprintf("\tThe following SANs will be added: %s\n", strings.Join(p.CertSANs.Added, ", ")))
+		}
+	}
+
+	if p.Helm != nil {
+		builder.WriteString("Helm charts:\n")
+		if len(p.Helm.Releases) == 0 {
+			builder.WriteString("\tNo changes.\n")
+		}
+		for _, release := range p.Helm.Releases {
+			switch release.Action {
+			case helm.ReleaseActionInstall:
+				builder.WriteString(fmt.Sprintf("\t%s will be installed in version %s\n", release.ReleaseName, release.TargetVersion))
+			default:
+				builder.WriteString(fmt.Sprintf("\t%s will be upgraded from %s to %s\n", release.ReleaseName, release.CurrentVersion, release.TargetVersion))
+			}
+			if release.ValuesDiff != "" {
+				builder.WriteString(indentEntireStringWithTab(indentEntireStringWithTab(ensureTrailingNewline(release.ValuesDiff))))
+			}
+		}
+	}
+
+	if p.Image != nil {
+		builder.WriteString(fmt.Sprintf("Image:\n%s", p.Image))
+	}
+	if p.Kubernetes != nil {
+		builder.WriteString(fmt.Sprintf("Kubernetes:\n%s", p.Kubernetes))
+	}
+
+	if len(p.Warnings) > 0 {
+		builder.WriteString("Warnings:\n")
+		for _, warning := range p.Warnings {
+			builder.WriteString(fmt.Sprintf("\t%s\n", warning))
+		}
+	}
+	return builder.String()
+}
+
+// String returns the human-readable representation of the version change.
+func (p versionPlan) String() string {
+	if !p.Changed {
+		return fmt.Sprintf("\tNo changes, staying at %s.\n", p.Current)
+	}
+	return fmt.Sprintf("\t%s will be upgraded to %s\n", p.Current, p.Target)
+}
+
+func ensureTrailingNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
@@ -12,8 +12,8 @@ import (
 	"io"
 	"path/filepath"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
@@ -57,8 +57,8 @@ func (a *applyCmd) runTerraformApply(cmd *cobra.Command, conf *config.Config, st
 		return fmt.Errorf("merging old state with new infrastructure values: %w", err)
 	}
 
-	// Write the new state to disk
-	if err := stateFile.WriteToFile(a.fileHandler, constants.StateFilename); err != nil {
+	// Write the new state to the state backend
+	if err := stateFile.WriteToBackend(cmd.Context(), a.stateStore()); err != nil {
 		return fmt.Errorf("writing state file: %w", err)
 	}
 	return nil
@@ -0,0 +1,57 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// NewAuditCmd returns a new cobra.Command for the audit parent command. It needs another verb and does nothing on its own.
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Export and verify the audit log of join tickets",
+		Long: "Export and verify the audit log of join tickets.\n\n" +
+			"The JoinService records every join and rejoin ticket it issues in a hash-chained audit log, " +
+			"stored in ConfigMaps in the kube-system namespace.",
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.AddCommand(newAuditExportCmd())
+	cmd.AddCommand(newAuditVerifyCmd())
+	return cmd
+}
+
+// newAuditLogClient returns a client for the ConfigMaps holding the join audit log,
+// using the kubeconfig of the workspace.
+func newAuditLogClient(fileHandler file.Handler) (configMapLister, error) {
+	kubeConfig, err := fileHandler.Read(constants.AdminConfFilename)
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating k8s client config from kubeconfig: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating k8s client from kubeconfig: %w", err)
+	}
+	return client.CoreV1().ConfigMaps(constants.ConstellationNamespace), nil
+}
+
+type configMapLister interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newAuditExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the audit log of join tickets",
+		Long: "Export the audit log of join tickets.\n\n" +
+			"The entries are written as JSON lines. The exported log can be verified later with 'constellation audit verify --file'.",
+		Args: cobra.NoArgs,
+		RunE: runAuditExport,
+	}
+	cmd.Flags().StringP("output", "o", "", "path to write the audit log to (default stdout)")
+	return cmd
+}
+
+type auditExportFlags struct {
+	rootFlags
+	output string
+}
+
+func (f *auditExportFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	output, err := flags.GetString("output")
+	if err != nil {
+		return fmt.Errorf("getting 'output' flag: %w", err)
+	}
+	f.output = output
+	return nil
+}
+
+func runAuditExport(cmd *cobra.Command, _ []string) error {
+	log, err := newCLILogger(cmd)
+	if err != nil {
+		return fmt.Errorf("creating logger: %w", err)
+	}
+	defer log.Sync()
+
+	fileHandler := file.NewHandler(afero.NewOsFs())
+	e := &auditExportCmd{log: log, fileHandler: fileHandler}
+	if err := e.flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+
+	client, err := newAuditLogClient(fileHandler)
+	if err != nil {
+		return err
+	}
+	return e.export(cmd, client)
+}
+
+type auditExportCmd struct {
+	log         debugLog
+	fileHandler file.Handler
+	flags       auditExportFlags
+}
+
+func (e *auditExportCmd) export(cmd *cobra.Command, client configMapLister) error {
+	e.log.Debugf("Reading join audit log from cluster")
+	entries, err := joinaudit.Read(cmd.Context(), client)
+	if err != nil {
+		return fmt.Errorf("reading audit log: %w", err)
+	}
+	// the log is exported even if it is broken, so it can be inspected
+	if err := joinaudit.Verify(entries, ""); err != nil {
+		cmd.PrintErrf("Warning: the audit log failed verification: %s\n", err)
+	}
+
+	if e.flags.output == "" {
+		return joinaudit.Export(cmd.OutOrStdout(), entries)
+	}
+
+	var buf bytes.Buffer
+	if err := joinaudit.Export(&buf, entries); err != nil {
+		return err
+	}
+	if err := e.fileHandler.Write(e.flags.output, buf.Bytes(), file.OptNone); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+	cmd.PrintErrf("Exported %d audit log entries to %s\n", len(entries), e.flags.pathPrefixer.PrefixPrintablePath(e.flags.output))
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestAuditExport(t *testing.T) {
+	testCases := map[string]struct {
+		output     string
+		existing   bool
+		listErr    error
+		wantStdout bool
+		wantErr    bool
+	}{
+		"to stdout": {
+			wantStdout: true,
+		},
+		"to file": {
+			output: "audit.jsonl",
+		},
+		"file exists": {
+			output:   "audit.jsonl",
+			existing: true,
+			wantErr:  true,
+		},
+		"reading log fails": {
+			listErr: assert.AnError,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cmd := NewAuditCmd()
+			cmd.SetContext(context.Background())
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&bytes.Buffer{})
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			if tc.existing {
+				require.NoError(fileHandler.Write(tc.output, []byte("old export")))
+			}
+			client := newTestAuditLog(t, 3)
+
+			e := &auditExportCmd{
+				log:         logger.NewTest(t),
+				fileHandler: fileHandler,
+				flags:       auditExportFlags{output: tc.output},
+			}
+			var lister configMapLister = client
+			if tc.listErr != nil {
+				lister = &stubConfigMapLister{listErr: tc.listErr}
+			}
+			err := e.export(cmd, lister)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			exported := stdout.Bytes()
+			if !tc.wantStdout {
+				assert.Empty(exported)
+				exported, err = fileHandler.Read(tc.output)
+				require.NoError(err)
+			}
+			entries, err := joinaudit.Import(bytes.NewReader(exported))
+			require.NoError(err)
+			assert.Len(entries, 3)
+			assert.NoError(joinaudit.Verify(entries, ""))
+		})
+	}
+}
+
+// newTestAuditLog returns a fake ConfigMap client holding an audit log with the given number of entries.
+func newTestAuditLog(t *testing.T, numEntries int) corev1client.ConfigMapInterface {
+	t.Helper()
+	client := fake.NewSimpleClientset().CoreV1().ConfigMaps(constants.ConstellationNamespace)
+	log := joinaudit.New(client)
+	for i := 0; i < numEntries; i++ {
+		_, err := log.Append(context.Background(), joinaudit.Entry{
+			Time:     time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			Ticket:   joinaudit.TicketJoin,
+			NodeName: fmt.Sprintf("node-%d", i),
+			DiskUUID: fmt.Sprintf("disk-%d", i),
+		})
+		require.NoError(t, err)
+	}
+	return client
+}
+
+type stubConfigMapLister struct {
+	listErr error
+}
+
+func (s *stubConfigMapLister) List(_ context.Context, _ metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	return nil, s.listErr
+}
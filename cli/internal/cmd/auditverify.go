@@ -0,0 +1,122 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newAuditVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the hash chain of the audit log of join tickets",
+		Long: "Verify the hash chain of the audit log of join tickets.\n\n" +
+			"The log is read from the cluster, or from a file exported with 'constellation audit export'. " +
+			"Verification fails if entries were modified, removed, or reordered. " +
+			"Pass the hash of the last entry of a previous verification as --anchor " +
+			"to detect if the log was rewritten since then.",
+		Args: cobra.NoArgs,
+		RunE: runAuditVerify,
+	}
+	cmd.Flags().String("file", "", "path to an exported audit log to verify instead of the log of the cluster")
+	cmd.Flags().String("anchor", "", "hash of a previously verified entry the log must contain")
+	return cmd
+}
+
+type auditVerifyFlags struct {
+	rootFlags
+	file   string
+	anchor string
+}
+
+func (f *auditVerifyFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	var err error
+	f.file, err = flags.GetString("file")
+	if err != nil {
+		return fmt.Errorf("getting 'file' flag: %w", err)
+	}
+	f.anchor, err = flags.GetString("anchor")
+	if err != nil {
+		return fmt.Errorf("getting 'anchor' flag: %w", err)
+	}
+	return nil
+}
+
+func runAuditVerify(cmd *cobra.Command, _ []string) error {
+	log, err := newCLILogger(cmd)
+	if err != nil {
+		return fmt.Errorf("creating logger: %w", err)
+	}
+	defer log.Sync()
+
+	fileHandler := file.NewHandler(afero.NewOsFs())
+	v := &auditVerifyCmd{log: log, fileHandler: fileHandler}
+	if err := v.flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+
+	var client configMapLister
+	if v.flags.file == "" {
+		client, err = newAuditLogClient(fileHandler)
+		if err != nil {
+			return err
+		}
+	}
+	return v.verify(cmd, client)
+}
+
+type auditVerifyCmd struct {
+	log         debugLog
+	fileHandler file.Handler
+	flags       auditVerifyFlags
+}
+
+// verify verifies the audit log read from the exported file, if set, or from the cluster otherwise.
+func (v *auditVerifyCmd) verify(cmd *cobra.Command, client configMapLister) error {
+	var entries []joinaudit.Entry
+	if v.flags.file != "" {
+		v.log.Debugf("Reading join audit log from %s", v.flags.file)
+		exported, err := v.fileHandler.Read(v.flags.file)
+		if err != nil {
+			return fmt.Errorf("reading exported audit log: %w", err)
+		}
+		entries, err = joinaudit.Import(bytes.NewReader(exported))
+		if err != nil {
+			return fmt.Errorf("parsing exported audit log: %w", err)
+		}
+	} else {
+		v.log.Debugf("Reading join audit log from cluster")
+		var err error
+		entries, err = joinaudit.Read(cmd.Context(), client)
+		if err != nil {
+			return fmt.Errorf("reading audit log: %w", err)
+		}
+	}
+
+	if err := joinaudit.Verify(entries, v.flags.anchor); err != nil {
+		return fmt.Errorf("verifying audit log: %w", err)
+	}
+
+	if len(entries) == 0 {
+		cmd.Println("The audit log is empty.")
+		return nil
+	}
+	cmd.Printf("Verified %d audit log entries.\n", len(entries))
+	cmd.Printf("Hash of the last entry: %s\n", entries[len(entries)-1].Hash)
+	cmd.Println("Use it as --anchor for the next verification to detect rewrites of the log.")
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditVerify(t *testing.T) {
+	client := newTestAuditLog(t, 3)
+	entries, err := joinaudit.Read(context.Background(), client)
+	require.NoError(t, err)
+	var export bytes.Buffer
+	require.NoError(t, joinaudit.Export(&export, entries))
+	tampered := strings.Replace(export.String(), "disk-1", "disk-9", 1)
+
+	testCases := map[string]struct {
+		exported string
+		flags    auditVerifyFlags
+		wantErr  bool
+	}{
+		"cluster log": {},
+		"cluster log with anchor": {
+			flags: auditVerifyFlags{anchor: entries[1].Hash},
+		},
+		"unknown anchor": {
+			flags:   auditVerifyFlags{anchor: "unknown"},
+			wantErr: true,
+		},
+		"exported log": {
+			exported: export.String(),
+			flags:    auditVerifyFlags{file: "audit.jsonl"},
+		},
+		"tampered export": {
+			exported: tampered,
+			flags:    auditVerifyFlags{file: "audit.jsonl"},
+			wantErr:  true,
+		},
+		"export not found": {
+			flags:   auditVerifyFlags{file: "audit.jsonl"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cmd := NewAuditCmd()
+			cmd.SetContext(context.Background())
+			var stdout bytes.Buffer
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&bytes.Buffer{})
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			if tc.exported != "" {
+				require.NoError(fileHandler.Write(tc.flags.file, []byte(tc.exported)))
+			}
+
+			v := &auditVerifyCmd{
+				log:         logger.NewTest(t),
+				fileHandler: fileHandler,
+				flags:       tc.flags,
+			}
+			err := v.verify(cmd, client)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Contains(stdout.String(), entries[2].Hash)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/backup"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// defaultBackupFilename is the default file name of a backup bundle.
+const defaultBackupFilename = "constellation-backup.enc"
+
+// NewBackupCmd returns a new cobra.Command for the backup command.
+func NewBackupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Create an encrypted backup of a Constellation cluster",
+		Long: "Create an encrypted backup of a Constellation cluster.\n\n" +
+			"The backup contains the config, state, and master secret files of the workspace, " +
+			"all CRDs, CRs, and Secrets of the cluster, and a snapshot of the etcd keyspace taken through the control plane.\n" +
+			"The backup is encrypted with the key read from the file passed to --key-file.",
+		Args: cobra.NoArgs,
+		RunE: runBackup,
+	}
+	cmd.Flags().String("key-file", "", fmt.Sprintf("path to a file holding the key used to encrypt the backup (at least %d bytes)", backup.MinKeyLength))
+	must(cmd.MarkFlagRequired("key-file"))
+	cmd.Flags().StringP("output", "o", defaultBackupFilename, "path to write the backup to")
+	return cmd
+}
+
+type backupFlags struct {
+	rootFlags
+	keyFile string
+	output  string
+}
+
+func (f *backupFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	var err error
+	f.keyFile, err = flags.GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("getting 'key-file' flag: %w", err)
+	}
+	f.output, err = flags.GetString("output")
+	if err != nil {
+		return fmt.Errorf("getting 'output' flag: %w", err)
+	}
+	return nil
+}
+
+type backupCmd struct {
+	log         debugLog
+	fileHandler file.Handler
+	spinner     spinnerInterf
+	flags       backupFlags
+}
+
+func runBackup(cmd *cobra.Command, _ []string) error {
+	log, err := newCLILogger(cmd)
+	if err != nil {
+		return fmt.Errorf("creating logger: %w", err)
+	}
+	defer log.Sync()
+	spinner, err := newSpinnerOrStderr(cmd)
+	if err != nil {
+		return fmt.Errorf("creating spinner: %w", err)
+	}
+	defer spinner.Stop()
+
+	b := &backupCmd{
+		log:         log,
+		fileHandler: file.NewHandler(afero.NewOsFs()),
+		spinner:     spinner,
+	}
+	if err := b.flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+
+	kubeConfig, err := b.fileHandler.Read(constants.AdminConfFilename)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	kubeClient, err := kubecmd.New(kubeConfig, log)
+	if err != nil {
+		return fmt.Errorf("setting up kubernetes client: %w", err)
+	}
+	return b.backup(cmd, kubeClient)
+}
+
+func (b *backupCmd) backup(cmd *cobra.Command, backupper clusterBackupper) error {
+	key, err := readBackupKey(b.fileHandler, b.flags.keyFile)
+	if err != nil {
+		return err
+	}
+	if _, err := b.fileHandler.Stat(b.flags.output); err == nil {
+		return fmt.Errorf("file %q already exists", b.flags.pathPrefixer.PrefixPrintablePath(b.flags.output))
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("checking for %q: %w", b.flags.pathPrefixer.PrefixPrintablePath(b.flags.output), err)
+	}
+
+	bundle := backup.New()
+	bundleFiles := bundle.FileHandler()
+
+	b.log.Debugf("Adding workspace files to backup")
+	for _, name := range []string{constants.ConfigFilename, constants.MasterSecretFilename} {
+		content, err := b.fileHandler.Read(name)
+		if err != nil {
+			return fmt.Errorf("reading %q: %w", b.flags.pathPrefixer.PrefixPrintablePath(name), err)
+		}
+		if err := bundleFiles.Write(name, content); err != nil {
+			return fmt.Errorf("adding %q to backup: %w", name, err)
+		}
+	}
+
+	// The state may be stored in a remote backend, so it's read from there instead of the workspace.
+	backendConf, err := config.ReadStateBackend(b.fileHandler, constants.ConfigFilename, b.flags.profile)
+	if err != nil {
+		return fmt.Errorf("reading state backend configuration: %w", err)
+	}
+	stateFile, err := readStateFromBackend(cmd.Context(), backendConf, b.fileHandler)
+	if err != nil {
+		return fmt.Errorf("reading state: %w", err)
+	}
+	if err := stateFile.WriteToFile(bundleFiles, constants.StateFilename); err != nil {
+		return fmt.Errorf("adding state to backup: %w", err)
+	}
+
+	b.spinner.Start("Backing up cluster resources", false)
+	err = b.backupCluster(cmd.Context(), backupper, bundleFiles)
+	b.spinner.Stop()
+	if err != nil {
+		return err
+	}
+
+	sealed, err := bundle.Seal(key)
+	if err != nil {
+		return fmt.Errorf("encrypting backup: %w", err)
+	}
+	if err := b.fileHandler.Write(b.flags.output, sealed); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+
+	cmd.Printf("Backup written to %s\n", b.flags.pathPrefixer.PrefixPrintablePath(b.flags.output))
+	cmd.Println("Store the backup and the key file in separate, secure locations. Anyone with access to both can access all secrets of the cluster.")
+	return nil
+}
+
+func (b *backupCmd) backupCluster(ctx context.Context, backupper clusterBackupper, bundleFiles file.Handler) error {
+	crds, err := backupper.BackupCRDs(ctx, bundleFiles, backup.ResourcesDir)
+	if err != nil {
+		return fmt.Errorf("backing up CRDs: %w", err)
+	}
+	if err := backupper.BackupCRs(ctx, bundleFiles, crds, backup.ResourcesDir); err != nil {
+		return fmt.Errorf("backing up CRs: %w", err)
+	}
+	if err := backupper.BackupSecrets(ctx, bundleFiles, backup.SecretsDir); err != nil {
+		return fmt.Errorf("backing up secrets: %w", err)
+	}
+
+	var etcdSnapshot bytes.Buffer
+	if err := backupper.SnapshotEtcd(ctx, &etcdSnapshot); err != nil {
+		return fmt.Errorf("snapshotting etcd: %w", err)
+	}
+	if err := bundleFiles.Write(backup.EtcdSnapshotFilename, etcdSnapshot.Bytes()); err != nil {
+		return fmt.Errorf("adding etcd snapshot to backup: %w", err)
+	}
+	return nil
+}
+
+// readBackupKey reads the key used to encrypt or decrypt a backup.
+func readBackupKey(fileHandler file.Handler, keyFile string) ([]byte, error) {
+	key, err := fileHandler.Read(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+	key = bytes.TrimSpace(key)
+	if len(key) < backup.MinKeyLength {
+		return nil, fmt.Errorf("key in %q must be at least %d bytes long", keyFile, backup.MinKeyLength)
+	}
+	return key, nil
+}
+
+type clusterBackupper interface {
+	BackupCRDs(ctx context.Context, fileHandler file.Handler, upgradeDir string) ([]apiextensionsv1.CustomResourceDefinition, error)
+	BackupCRs(ctx context.Context, fileHandler file.Handler, crds []apiextensionsv1.CustomResourceDefinition, upgradeDir string) error
+	BackupSecrets(ctx context.Context, fileHandler file.Handler, dir string) error
+	SnapshotEtcd(ctx context.Context, out io.Writer) error
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/backup"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestBackup(t *testing.T) {
+	backupKey := bytes.Repeat([]byte{0x01}, 32)
+	stateFile := state.New().SetClusterValues(state.ClusterValues{ClusterID: "cluster-id"})
+	workspaceFiles := func(require *require.Assertions, fh file.Handler) {
+		require.NoError(fh.Write(constants.ConfigFilename, []byte("name: config\n")))
+		require.NoError(stateFile.WriteToFile(fh, constants.StateFilename))
+		require.NoError(fh.Write(constants.MasterSecretFilename, []byte("master secret")))
+		require.NoError(fh.Write("backup.key", backupKey))
+	}
+
+	testCases := map[string]struct {
+		prepareFs func(*require.Assertions, file.Handler)
+		backupper *stubClusterBackupper
+		wantErr   bool
+	}{
+		"success": {
+			prepareFs: workspaceFiles,
+			backupper: &stubClusterBackupper{etcdSnapshot: `{"kvs":[]}`},
+		},
+		"missing master secret": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.ConfigFilename, []byte("name: config\n")))
+				require.NoError(stateFile.WriteToFile(fh, constants.StateFilename))
+				require.NoError(fh.Write("backup.key", backupKey))
+			},
+			backupper: &stubClusterBackupper{},
+			wantErr:   true,
+		},
+		"key too short": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				workspaceFiles(require, fh)
+				require.NoError(fh.Write("backup.key", []byte("short"), file.OptOverwrite))
+			},
+			backupper: &stubClusterBackupper{},
+			wantErr:   true,
+		},
+		"output exists": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				workspaceFiles(require, fh)
+				require.NoError(fh.Write(defaultBackupFilename, []byte("old backup")))
+			},
+			backupper: &stubClusterBackupper{},
+			wantErr:   true,
+		},
+		"missing state": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.ConfigFilename, []byte("name: config\n")))
+				require.NoError(fh.Write(constants.MasterSecretFilename, []byte("master secret")))
+				require.NoError(fh.Write("backup.key", backupKey))
+			},
+			backupper: &stubClusterBackupper{},
+			wantErr:   true,
+		},
+		"backing up CRs fails": {
+			prepareFs: workspaceFiles,
+			backupper: &stubClusterBackupper{backupCRsErr: assert.AnError},
+			wantErr:   true,
+		},
+		"backing up secrets fails": {
+			prepareFs: workspaceFiles,
+			backupper: &stubClusterBackupper{backupSecretsErr: assert.AnError},
+			wantErr:   true,
+		},
+		"etcd snapshot fails": {
+			prepareFs: workspaceFiles,
+			backupper: &stubClusterBackupper{snapshotErr: assert.AnError},
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			tc.prepareFs(require, fileHandler)
+
+			cmd := NewBackupCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			b := &backupCmd{
+				log:         logger.NewTest(t),
+				fileHandler: fileHandler,
+				spinner:     &nopSpinner{},
+				flags:       backupFlags{keyFile: "backup.key", output: defaultBackupFilename},
+			}
+
+			err := b.backup(cmd, tc.backupper)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			sealed, err := fileHandler.Read(defaultBackupFilename)
+			require.NoError(err)
+			bundle, err := backup.Open(sealed, backupKey)
+			require.NoError(err)
+			bundleFiles := bundle.FileHandler()
+			backedUpState, err := state.ReadFromFile(bundleFiles, constants.StateFilename)
+			require.NoError(err)
+			assert.Equal(stateFile.ClusterValues.ClusterID, backedUpState.ClusterValues.ClusterID)
+			for name, want := range map[string]string{
+				constants.ConfigFilename:        "name: config\n",
+				constants.MasterSecretFilename:  "master secret",
+				backup.EtcdSnapshotFilename:     `{"kvs":[]}`,
+				filepath.Join("secrets", "s"):   "secret",
+				filepath.Join("resources", "r"): "resource",
+			} {
+				content, err := bundleFiles.Read(name)
+				require.NoError(err, name)
+				assert.Equal(want, string(content))
+			}
+		})
+	}
+}
+
+type stubClusterBackupper struct {
+	backupCRDsErr    error
+	backupCRsErr     error
+	backupSecretsErr error
+	etcdSnapshot     string
+	snapshotErr      error
+}
+
+func (s *stubClusterBackupper) BackupCRDs(_ context.Context, _ file.Handler, _ string) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	return nil, s.backupCRDsErr
+}
+
+func (s *stubClusterBackupper) BackupCRs(_ context.Context, fileHandler file.Handler, _ []apiextensionsv1.CustomResourceDefinition, dir string) error {
+	if s.backupCRsErr != nil {
+		return s.backupCRsErr
+	}
+	return fileHandler.Write(filepath.Join(dir, "r"), []byte("resource"), file.OptMkdirAll)
+}
+
+func (s *stubClusterBackupper) BackupSecrets(_ context.Context, fileHandler file.Handler, dir string) error {
+	if s.backupSecretsErr != nil {
+		return s.backupSecretsErr
+	}
+	return fileHandler.Write(filepath.Join(dir, "s"), []byte("secret"), file.OptMkdirAll)
+}
+
+func (s *stubClusterBackupper) SnapshotEtcd(_ context.Context, out io.Writer) error {
+	if s.snapshotErr != nil {
+		return s.snapshotErr
+	}
+	_, err := out.Write([]byte(s.etcdSnapshot))
+	return err
+}
@@ -9,16 +9,17 @@ package cmd
 import (
 	"context"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 type cloudApplier interface {
 	Plan(ctx context.Context, conf *config.Config) (bool, error)
+	PlanDiff(ctx context.Context, conf *config.Config) (bool, string, error)
 	Apply(ctx context.Context, csp cloudprovider.Provider, rollback cloudcmd.RollbackBehavior) (state.Infrastructure, error)
 	RestoreWorkspace() error
 	WorkingDirIsEmpty() (bool, error)
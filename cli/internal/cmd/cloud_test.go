@@ -10,12 +10,12 @@ import (
 	"context"
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 	"go.uber.org/goleak"
 )
 
@@ -30,6 +30,7 @@ type stubCloudCreator struct {
 	state               state.Infrastructure
 	planCalled          bool
 	planDiff            bool
+	planDiffOutput      string
 	planErr             error
 	applyCalled         bool
 	applyErr            error
@@ -43,6 +44,11 @@ func (c *stubCloudCreator) Plan(_ context.Context, _ *config.Config) (bool, erro
 	return c.planDiff, c.planErr
 }
 
+func (c *stubCloudCreator) PlanDiff(_ context.Context, _ *config.Config) (bool, string, error) {
+	c.planCalled = true
+	return c.planDiff, c.planDiffOutput, c.planErr
+}
+
 func (c *stubCloudCreator) Apply(_ context.Context, _ cloudprovider.Provider, _ cloudcmd.RollbackBehavior) (state.Infrastructure, error) {
 	c.applyCalled = true
 	return c.state, c.applyErr
@@ -26,7 +26,7 @@ import (
 	"fmt"
 
 	"github.com/edgelesssys/constellation/v2/cli/internal/cmd/pathprefix"
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 	"github.com/spf13/pflag"
 )
 
@@ -35,6 +35,7 @@ import (
 type rootFlags struct {
 	pathPrefixer pathprefix.PathPrefixer
 	tfLogLevel   terraform.LogLevel
+	profile      string
 	debug        bool
 	force        bool
 }
@@ -58,6 +59,11 @@ func (f *rootFlags) parse(flags *pflag.FlagSet) error {
 		errs = errors.Join(err, fmt.Errorf("parsing 'tf-log' flag: %w", err))
 	}
 
+	f.profile, err = flags.GetString("profile")
+	if err != nil {
+		errs = errors.Join(err, fmt.Errorf("getting 'profile' flag: %w", err))
+	}
+
 	f.debug, err = flags.GetBool("debug")
 	if err != nil {
 		errs = errors.Join(err, fmt.Errorf("getting 'debug' flag: %w", err))
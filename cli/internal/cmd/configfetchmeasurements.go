@@ -121,7 +121,7 @@ func (cfm *configFetchMeasurementsCmd) configFetchMeasurements(
 
 	cfm.log.Debugf("Loading configuration file from %q", cfm.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
 
-	conf, err := config.New(fileHandler, constants.ConfigFilename, fetcher, cfm.flags.force)
+	conf, err := config.New(fileHandler, constants.ConfigFilename, cfm.flags.profile, fetcher, cfm.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -157,7 +157,7 @@ func (cfm *configFetchMeasurementsCmd) configFetchMeasurements(
 
 	cfm.log.Debugf("Updating measurements in configuration")
 	conf.UpdateMeasurements(fetchedMeasurements)
-	if err := fileHandler.WriteYAML(constants.ConfigFilename, conf, file.OptOverwrite); err != nil {
+	if err := conf.WriteToFile(fileHandler, constants.ConfigFilename, cfm.flags.profile, file.OptOverwrite); err != nil {
 		return err
 	}
 	cfm.log.Debugf("Configuration written to %s", cfm.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
@@ -69,6 +69,7 @@ func TestParseFetchMeasurementsFlags(t *testing.T) {
 			cmd.Flags().Bool("force", false, "")
 			cmd.Flags().Bool("debug", false, "")
 			cmd.Flags().String("tf-log", "NONE", "")
+			cmd.Flags().String("profile", "", "")
 
 			if tc.urlFlag != "" {
 				require.NoError(cmd.Flags().Set("url", tc.urlFlag))
@@ -37,6 +37,7 @@ func newConfigGenerateCmd() *cobra.Command {
 	}
 	cmd.Flags().StringP("kubernetes", "k", semver.MajorMinor(string(config.Default().KubernetesVersion)), "Kubernetes version to use in format MAJOR.MINOR")
 	cmd.Flags().StringP("attestation", "a", "", fmt.Sprintf("attestation variant to use %s. If not specified, the default for the cloud provider is used", printFormattedSlice(variant.GetAvailableAttestationVariants())))
+	cmd.Flags().StringSlice("profiles", nil, "names of empty profiles to add to the configuration file, e.g., dev,staging,prod. Select a profile with --profile")
 
 	return cmd
 }
@@ -45,6 +46,7 @@ type generateFlags struct {
 	rootFlags
 	k8sVersion         versions.ValidK8sVersion
 	attestationVariant variant.Variant
+	profiles           []string
 }
 
 func (f *generateFlags) parse(flags *pflag.FlagSet) error {
@@ -64,6 +66,12 @@ func (f *generateFlags) parse(flags *pflag.FlagSet) error {
 	}
 	f.attestationVariant = variant
 
+	profiles, err := flags.GetStringSlice("profiles")
+	if err != nil {
+		return fmt.Errorf("getting 'profiles' flag: %w", err)
+	}
+	f.profiles = profiles
+
 	return nil
 }
 
@@ -100,6 +108,9 @@ func (cg *configGenerateCmd) configGenerate(cmd *cobra.Command, fileHandler file
 		return fmt.Errorf("creating config: %w", err)
 	}
 	conf.KubernetesVersion = cg.flags.k8sVersion
+	for _, profile := range cg.flags.profiles {
+		conf.AddProfile(profile)
+	}
 	cg.log.Debugf("Writing YAML data to configuration file")
 	if err := fileHandler.WriteYAML(constants.ConfigFilename, conf, file.OptMkdirAll); err != nil {
 		return fmt.Errorf("writing config file: %w", err)
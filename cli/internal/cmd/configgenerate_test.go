@@ -109,6 +109,32 @@ func TestConfigGenerateDefault(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestConfigGenerateProfiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fileHandler := file.NewHandler(afero.NewMemMapFs())
+	cmd := newConfigGenerateCmd()
+
+	cg := &configGenerateCmd{
+		log: logger.NewTest(t),
+		flags: generateFlags{
+			attestationVariant: variant.Dummy{},
+			k8sVersion:         versions.Default,
+			profiles:           []string{"dev", "prod"},
+		},
+	}
+	require.NoError(cg.configGenerate(cmd, fileHandler, cloudprovider.Unknown, ""))
+
+	var readConfig config.Config
+	require.NoError(fileHandler.ReadYAMLStrict(constants.ConfigFilename, &readConfig))
+	assert.Equal([]string{"dev", "prod"}, readConfig.ProfileNames())
+
+	prod, err := readConfig.WithProfile("prod")
+	require.NoError(err)
+	assert.Equal(readConfig.Name, prod.Name)
+}
+
 func TestConfigGenerateDefaultProviderSpecific(t *testing.T) {
 	testCases := map[string]struct {
 		provider    cloudprovider.Provider
@@ -12,8 +12,8 @@ import (
 	"fmt"
 	"regexp"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
@@ -138,6 +138,13 @@ func (c *iamCreator) create(ctx context.Context) error {
 		if err := c.fileHandler.ReadYAML(constants.ConfigFilename, &conf); err != nil {
 			return fmt.Errorf("error reading the configuration file: %w", err)
 		}
+		if c.flags.profile != "" {
+			withProfile, err := conf.WithProfile(c.flags.profile)
+			if err != nil {
+				return fmt.Errorf("error reading the configuration file: %w", err)
+			}
+			conf = *withProfile
+		}
 		if err := c.providerCreator.validateConfigWithFlagCompatibility(conf); err != nil {
 			return err
 		}
@@ -164,7 +171,7 @@ func (c *iamCreator) create(ctx context.Context) error {
 	if c.flags.updateConfig {
 		c.log.Debugf("Writing IAM configuration to %s", c.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
 		c.providerCreator.writeOutputValuesToConfig(&conf, iamFile)
-		if err := c.fileHandler.WriteYAML(constants.ConfigFilename, conf, file.OptOverwrite); err != nil {
+		if err := conf.WriteToFile(c.fileHandler, constants.ConfigFilename, c.flags.profile, file.OptOverwrite); err != nil {
 			return err
 		}
 		c.cmd.Printf("Your IAM configuration was created and filled into %s successfully.\n", c.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
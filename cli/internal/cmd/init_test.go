@@ -21,7 +21,6 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/file"
@@ -29,6 +28,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,6 +94,7 @@ func TestInitialize(t *testing.T) {
 		initErr                 error
 		retriable               bool
 		masterSecretShouldExist bool
+		restoredSecrets         *restoredSecrets
 		wantErr                 bool
 	}{
 		"initialize some gcp instances": {
@@ -113,6 +114,15 @@ func TestInitialize(t *testing.T) {
 			stateFile:  preInitStateFile(cloudprovider.QEMU),
 			initOutput: testInitOutput,
 		},
+		"initialize with secrets restored from backup": {
+			provider:   cloudprovider.QEMU,
+			stateFile:  preInitStateFile(cloudprovider.QEMU),
+			initOutput: testInitOutput,
+			restoredSecrets: &restoredSecrets{
+				MasterSecret:    uri.MasterSecret{Key: bytes.Repeat([]byte{0x04}, 32), Salt: bytes.Repeat([]byte{0x05}, 32)},
+				MeasurementSalt: bytes.Repeat([]byte{0x06}, 32),
+			},
+		},
 		"non retriable error": {
 			provider:                cloudprovider.QEMU,
 			stateFile:               preInitStateFile(cloudprovider.QEMU),
@@ -216,6 +226,9 @@ func TestInitialize(t *testing.T) {
 			if tc.serviceAccKey != nil {
 				require.NoError(fileHandler.WriteJSON(serviceAccPath, tc.serviceAccKey, file.OptNone))
 			}
+			if tc.restoredSecrets != nil {
+				require.NoError(fileHandler.WriteJSON(constants.RestoredSecretsFilename, tc.restoredSecrets, file.OptNone))
+			}
 
 			ctx := context.Background()
 			ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
@@ -270,23 +283,34 @@ func TestInitialize(t *testing.T) {
 			assert.NoError(fileHandler.ReadJSON(constants.MasterSecretFilename, &secret))
 			assert.NotEmpty(secret.Key)
 			assert.NotEmpty(secret.Salt)
+			if tc.restoredSecrets != nil {
+				assert.Equal(tc.restoredSecrets.MasterSecret, secret)
+				stateFile, err := state.ReadFromFile(fileHandler, constants.StateFilename)
+				require.NoError(err)
+				assert.Equal(tc.restoredSecrets.MeasurementSalt, []byte(stateFile.ClusterValues.MeasurementSalt))
+				_, err = fileHandler.Stat(constants.RestoredSecretsFilename)
+				assert.Error(err)
+			}
 		})
 	}
 }
 
 type stubHelmApplier struct {
-	err error
+	changes []helm.ReleaseChange
+	err     error
 }
 
 func (s stubHelmApplier) PrepareHelmCharts(
 	_ helm.Options, _ *state.State, _ string, _ uri.MasterSecret, _ *config.OpenStackConfig,
 ) (helm.Applier, bool, error) {
-	return stubRunner{}, false, s.err
+	return stubRunner{changes: s.changes}, false, s.err
 }
 
 type stubRunner struct {
 	applyErr      error
 	saveChartsErr error
+	changes       []helm.ReleaseChange
+	changesErr    error
 }
 
 func (s stubRunner) Apply(_ context.Context) error {
@@ -297,6 +321,10 @@ func (s stubRunner) SaveCharts(_ string, _ file.Handler) error {
 	return s.saveChartsErr
 }
 
+func (s stubRunner) Changes() ([]helm.ReleaseChange, error) {
+	return s.changes, s.changesErr
+}
+
 func TestWriteOutput(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -369,9 +397,9 @@ func TestWriteOutput(t *testing.T) {
 		spinner:     &nopSpinner{},
 		merger:      &stubMerger{},
 		log:         logger.NewTest(t),
-		applier:     constellation.NewApplier(logger.NewTest(t), &nopSpinner{}, constellation.ApplyContextCLI, nil),
+		applier:     constellation.NewApplier(logger.NewTest(t), nil, constellation.ApplyContextCLI, nil),
 	}
-	err = i.writeInitOutput(stateFile, initOutput, false, &out, measurementSalt)
+	err = i.writeInitOutput(context.Background(), stateFile, initOutput, false, &out, measurementSalt)
 	require.NoError(err)
 	assert.Contains(out.String(), clusterID)
 	assert.Contains(out.String(), constants.AdminConfFilename)
@@ -391,7 +419,7 @@ func TestWriteOutput(t *testing.T) {
 
 	// test custom workspace
 	i.flags.pathPrefixer = pathprefix.New("/some/path")
-	err = i.writeInitOutput(stateFile, initOutput, true, &out, measurementSalt)
+	err = i.writeInitOutput(context.Background(), stateFile, initOutput, true, &out, measurementSalt)
 	require.NoError(err)
 	assert.Contains(out.String(), clusterID)
 	assert.Contains(out.String(), i.flags.pathPrefixer.PrefixPrintablePath(constants.AdminConfFilename))
@@ -401,7 +429,7 @@ func TestWriteOutput(t *testing.T) {
 	i.flags.pathPrefixer = pathprefix.PathPrefixer{}
 
 	// test config merging
-	err = i.writeInitOutput(stateFile, initOutput, true, &out, measurementSalt)
+	err = i.writeInitOutput(context.Background(), stateFile, initOutput, true, &out, measurementSalt)
 	require.NoError(err)
 	assert.Contains(out.String(), clusterID)
 	assert.Contains(out.String(), constants.AdminConfFilename)
@@ -412,7 +440,7 @@ func TestWriteOutput(t *testing.T) {
 
 	// test config merging with env vars set
 	i.merger = &stubMerger{envVar: "/some/path/to/kubeconfig"}
-	err = i.writeInitOutput(stateFile, initOutput, true, &out, measurementSalt)
+	err = i.writeInitOutput(context.Background(), stateFile, initOutput, true, &out, measurementSalt)
 	require.NoError(err)
 	assert.Contains(out.String(), clusterID)
 	assert.Contains(out.String(), constants.AdminConfFilename)
@@ -461,7 +489,7 @@ func TestGenerateMasterSecret(t *testing.T) {
 			i := &applyCmd{
 				fileHandler: fileHandler,
 				log:         logger.NewTest(t),
-				applier:     constellation.NewApplier(logger.NewTest(t), &nopSpinner{}, constellation.ApplyContextCLI, nil),
+				applier:     constellation.NewApplier(logger.NewTest(t), nil, constellation.ApplyContextCLI, nil),
 			}
 			secret, err := i.generateAndPersistMasterSecret(&out)
 
@@ -7,12 +7,13 @@ SPDX-License-Identifier: AGPL-3.0-only
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 
+	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -31,7 +32,11 @@ func newMiniDownCmd() *cobra.Command {
 }
 
 func runDown(cmd *cobra.Command, args []string) error {
-	if err := checkForMiniCluster(file.NewHandler(afero.NewOsFs())); err != nil {
+	var flags rootFlags
+	if err := flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+	if err := checkForMiniCluster(cmd.Context(), file.NewHandler(afero.NewOsFs()), flags.profile); err != nil {
 		return fmt.Errorf("failed to destroy cluster: %w. Are you in the correct working directory?", err)
 	}
 
@@ -42,8 +47,12 @@ func runDown(cmd *cobra.Command, args []string) error {
 	return err
 }
 
-func checkForMiniCluster(fileHandler file.Handler) error {
-	stateFile, err := state.ReadFromFile(fileHandler, constants.StateFilename)
+func checkForMiniCluster(ctx context.Context, fileHandler file.Handler, profile string) error {
+	backendConf, err := config.ReadStateBackend(fileHandler, constants.ConfigFilename, profile)
+	if err != nil {
+		return fmt.Errorf("reading state backend configuration: %w", err)
+	}
+	stateFile, err := readStateFromBackend(ctx, backendConf, fileHandler)
 	if err != nil {
 		return fmt.Errorf("reading state file: %w", err)
 	}
@@ -7,20 +7,27 @@ SPDX-License-Identifier: AGPL-3.0-only
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/libvirt"
 	"github.com/edgelesssys/constellation/v2/internal/api/attestationconfigapi"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/featureset"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/libvirt"
+	"github.com/edgelesssys/constellation/v2/internal/sigstore"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func newMiniUpCmd() *cobra.Command {
@@ -28,21 +35,90 @@ func newMiniUpCmd() *cobra.Command {
 		Use:   "up",
 		Short: "Create and initialize a new MiniConstellation cluster",
 		Long: "Create and initialize a new MiniConstellation cluster.\n\n" +
-			"A mini cluster consists of a single control-plane and worker node, hosted using QEMU/KVM.",
+			"A mini cluster is hosted using QEMU/KVM. By default, it consists of a single control-plane and worker node using vTPM attestation.",
 		Args: cobra.ExactArgs(0),
 		RunE: runUp,
 	}
 
+	defaults := config.DefaultMiniOptions()
 	cmd.Flags().Bool("merge-kubeconfig", true, "merge Constellation kubeconfig file with default kubeconfig file in $HOME/.kube/config")
+	cmd.Flags().Int("control-plane-nodes", defaults.ControlPlaneCount, "number of control-plane nodes")
+	cmd.Flags().Int("worker-nodes", defaults.WorkerCount, "number of worker nodes")
+	cmd.Flags().Int("vcpus", defaults.VCPUs, "number of vCPUs of each node")
+	cmd.Flags().Int("memory", defaults.Memory, "amount of memory of each node in MiB")
+	cmd.Flags().String("attestation", defaults.AttestationVariant.String(), fmt.Sprintf(
+		"attestation variant of the nodes %s. Simulated variants run without confidential computing hardware, but provide no security",
+		printFormattedSlice(variant.GetAvailableAttestationVariantsFor(cloudprovider.QEMU)),
+	))
 
 	return cmd
 }
 
+type miniUpFlags struct {
+	rootFlags
+	miniOptions config.MiniOptions
+	// customized is true if any of the flags customizing the cluster were set.
+	customized bool
+}
+
+func (f *miniUpFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	var err error
+	f.miniOptions.ControlPlaneCount, err = flags.GetInt("control-plane-nodes")
+	if err != nil {
+		return fmt.Errorf("getting 'control-plane-nodes' flag: %w", err)
+	}
+	if f.miniOptions.ControlPlaneCount < 1 {
+		return errors.New("at least one control-plane node is required")
+	}
+	f.miniOptions.WorkerCount, err = flags.GetInt("worker-nodes")
+	if err != nil {
+		return fmt.Errorf("getting 'worker-nodes' flag: %w", err)
+	}
+	if f.miniOptions.WorkerCount < 0 {
+		return errors.New("number of worker nodes must not be negative")
+	}
+	f.miniOptions.VCPUs, err = flags.GetInt("vcpus")
+	if err != nil {
+		return fmt.Errorf("getting 'vcpus' flag: %w", err)
+	}
+	if f.miniOptions.VCPUs < 1 {
+		return errors.New("nodes require at least one vCPU")
+	}
+	f.miniOptions.Memory, err = flags.GetInt("memory")
+	if err != nil {
+		return fmt.Errorf("getting 'memory' flag: %w", err)
+	}
+	if f.miniOptions.Memory < 1 {
+		return errors.New("memory of the nodes must be positive")
+	}
+	rawVariant, err := flags.GetString("attestation")
+	if err != nil {
+		return fmt.Errorf("getting 'attestation' flag: %w", err)
+	}
+	f.miniOptions.AttestationVariant, err = variant.FromString(rawVariant)
+	if err != nil {
+		return fmt.Errorf("invalid attestation variant: %s", rawVariant)
+	}
+	if !variant.ValidProvider(cloudprovider.QEMU, f.miniOptions.AttestationVariant) {
+		return fmt.Errorf("attestation variant %s is not supported by MiniConstellation", rawVariant)
+	}
+
+	for _, name := range []string{"control-plane-nodes", "worker-nodes", "vcpus", "memory", "attestation"} {
+		f.customized = f.customized || flags.Changed(name)
+	}
+	return nil
+}
+
 type miniUpCmd struct {
 	log           debugLog
 	configFetcher attestationconfigapi.Fetcher
+	verifyFetcher verifyFetcher
 	fileHandler   file.Handler
-	flags         rootFlags
+	flags         miniUpFlags
 }
 
 func runUp(cmd *cobra.Command, _ []string) error {
@@ -52,9 +128,15 @@ func runUp(cmd *cobra.Command, _ []string) error {
 	}
 	defer log.Sync()
 
+	rekor, err := sigstore.NewRekor()
+	if err != nil {
+		return fmt.Errorf("constructing Rekor client: %w", err)
+	}
+
 	m := &miniUpCmd{
 		log:           log,
 		configFetcher: attestationconfigapi.NewFetcher(),
+		verifyFetcher: measurements.NewVerifyFetcher(sigstore.NewCosignVerifier, rekor, http.DefaultClient),
 		fileHandler:   file.NewHandler(afero.NewOsFs()),
 	}
 	if err := m.flags.parse(cmd.Flags()); err != nil {
@@ -131,6 +213,9 @@ func (m *miniUpCmd) prepareConfig(cmd *cobra.Command) (*config.Config, error) {
 			return nil, err
 		}
 		if ok {
+			if m.flags.customized {
+				cmd.PrintErrln("Using the existing config, the flags customizing the cluster are ignored.")
+			}
 			return m.prepareExistingConfig(cmd)
 		}
 
@@ -148,17 +233,47 @@ func (m *miniUpCmd) prepareConfig(cmd *cobra.Command) (*config.Config, error) {
 		cmd.PrintErrln("Generating a valid default config is not supported in the OSS build of the Constellation CLI. Consult the documentation for instructions on where to download the enterprise version.")
 		return nil, errors.New("cannot create a mini cluster without a config file in the OSS build")
 	}
-	config, err := config.MiniDefault()
+	config, err := config.MiniWithOptions(m.flags.miniOptions)
 	if err != nil {
 		return nil, fmt.Errorf("mini default config is invalid: %v", err)
 	}
+	// Only measurements for the default attestation variant are embedded into the CLI.
+	if !m.flags.miniOptions.AttestationVariant.Equal(variant.GetDefaultAttestation(cloudprovider.QEMU)) {
+		if err := m.fetchMeasurements(cmd, config); err != nil {
+			return nil, err
+		}
+	}
 	m.log.Debugf("Prepared configuration")
 
 	return config, m.fileHandler.WriteYAML(constants.ConfigFilename, config, file.OptOverwrite)
 }
 
+// fetchMeasurements updates the config with the measurements of the configured image for the configured attestation variant.
+func (m *miniUpCmd) fetchMeasurements(cmd *cobra.Command, conf *config.Config) error {
+	attestationVariant := conf.GetAttestationConfig().GetVariant()
+	m.log.Debugf("Fetching measurements for image %s and attestation variant %s", conf.Image, attestationVariant)
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), time.Minute)
+	defer cancel()
+	fetchedMeasurements, err := m.verifyFetcher.FetchAndVerifyMeasurements(ctx, conf.Image, cloudprovider.QEMU, attestationVariant, false)
+	if err != nil {
+		var rekorErr *measurements.RekorError
+		if !errors.As(err, &rekorErr) {
+			return fmt.Errorf("fetching measurements for attestation variant %s: %w", attestationVariant, err)
+		}
+		cmd.PrintErrf("Ignoring Rekor related error: %v\n", err)
+		cmd.PrintErrln("Make sure the downloaded measurements are trustworthy!")
+	}
+	// Images for simulated TDX are measured by the vTPM. The registers of the simulated device are derived from its PCRs.
+	if attestationVariant.Equal(variant.QEMUTDXSimulated{}) {
+		fetchedMeasurements = tdx.SimulatedMeasurementsFromPCRs(fetchedMeasurements)
+	}
+	conf.UpdateMeasurements(fetchedMeasurements)
+	return nil
+}
+
 func (m *miniUpCmd) prepareExistingConfig(cmd *cobra.Command) (*config.Config, error) {
-	conf, err := config.New(m.fileHandler, constants.ConfigFilename, m.configFetcher, m.flags.force)
+	conf, err := config.New(m.fileHandler, constants.ConfigFilename, m.flags.profile, m.configFetcher, m.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -71,6 +71,10 @@ func (m *miniUpCmd) checkSystemRequirements(out io.Writer) error {
 	if memGB < 6 {
 		fmt.Fprintln(out, "WARNING: Less than 6GB of memory available. This may cause performance issues.")
 	}
+	opts := m.flags.miniOptions
+	if nodesMemGB := (opts.ControlPlaneCount + opts.WorkerCount) * opts.Memory / 1024; nodesMemGB > memGB {
+		fmt.Fprintf(out, "WARNING: The nodes are assigned %dGB of memory in total, but only %dGB of memory are available.\n", nodesMemGB, memGB)
+	}
 	m.log.Debugf("Checked available memory, you have %dGB available", memGB)
 
 	var stat unix.Statfs_t
@@ -13,6 +13,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/disk-mapper/recoverproto"
@@ -20,6 +21,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
@@ -39,17 +41,24 @@ func NewRecoverCmd() *cobra.Command {
 		Use:   "recover",
 		Short: "Recover a completely stopped Constellation cluster",
 		Long: "Recover a Constellation cluster by sending a recovery key to an instance in the boot stage.\n\n" +
-			"This is only required if instances restart without other instances available for bootstrapping.",
+			"This is only required if instances restart without other instances available for bootstrapping.\n\n" +
+			"By default, the recovery key is sent through the cluster endpoint until no more instances wait for recovery.\n" +
+			"If multiple endpoints are passed, or --discover is set, all instances are recovered directly and concurrently. " +
+			"--discover finds the control-plane instances using the cloud provider's APIs. " +
+			"Their VPC IP addresses must be reachable from this machine.",
 		Args: cobra.ExactArgs(0),
 		RunE: runRecover,
 	}
-	cmd.Flags().StringP("endpoint", "e", "", "endpoint of the instance, passed as HOST[:PORT]")
+	cmd.Flags().StringSliceP("endpoint", "e", nil, "endpoint of the instance, passed as HOST[:PORT]. Can be repeated to recover multiple instances")
+	cmd.Flags().Bool("discover", false, "discover all control-plane instances using the cloud provider's APIs and recover them")
+	cmd.MarkFlagsMutuallyExclusive("endpoint", "discover")
 	return cmd
 }
 
 type recoverFlags struct {
 	rootFlags
-	endpoint string
+	endpoints []string
+	discover  bool
 }
 
 func (f *recoverFlags) parse(flags *pflag.FlagSet) error {
@@ -57,17 +66,23 @@ func (f *recoverFlags) parse(flags *pflag.FlagSet) error {
 		return err
 	}
 
-	endpoint, err := flags.GetString("endpoint")
+	endpoints, err := flags.GetStringSlice("endpoint")
 	if err != nil {
 		return fmt.Errorf("getting 'endpoint' flag: %w", err)
 	}
-	f.endpoint = endpoint
+	f.endpoints = endpoints
+	discover, err := flags.GetBool("discover")
+	if err != nil {
+		return fmt.Errorf("getting 'discover' flag: %w", err)
+	}
+	f.discover = discover
 	return nil
 }
 
 type recoverCmd struct {
 	log           debugLog
 	configFetcher attestationconfigapi.Fetcher
+	newDiscoverer func(ctx context.Context, conf *config.Config, infra state.Infrastructure) (nodeDiscoverer, error)
 	flags         recoverFlags
 }
 
@@ -81,17 +96,24 @@ func runRecover(cmd *cobra.Command, _ []string) error {
 	newDialer := func(validator atls.Validator) *dialer.Dialer {
 		return dialer.New(nil, validator, &net.Dialer{})
 	}
-	r := &recoverCmd{log: log, configFetcher: attestationconfigapi.NewFetcher()}
+	r := &recoverCmd{
+		log:           log,
+		configFetcher: attestationconfigapi.NewFetcher(),
+		newDiscoverer: func(ctx context.Context, conf *config.Config, infra state.Infrastructure) (nodeDiscoverer, error) {
+			return cloudcmd.NewNodeDiscoverer(ctx, conf, infra)
+		},
+	}
 	if err := r.flags.parse(cmd.Flags()); err != nil {
 		return err
 	}
 	r.log.Debugf("Using flags: %+v", r.flags)
-	return r.recover(cmd, fileHandler, 5*time.Second, &recoverDoer{log: r.log}, newDialer)
+	newDoer := func() recoverDoerInterface { return &recoverDoer{log: r.log} }
+	return r.recover(cmd, fileHandler, 5*time.Second, newDoer, newDialer)
 }
 
 func (r *recoverCmd) recover(
 	cmd *cobra.Command, fileHandler file.Handler, interval time.Duration,
-	doer recoverDoerInterface, newDialer func(validator atls.Validator) *dialer.Dialer,
+	newDoer func() recoverDoerInterface, newDialer func(validator atls.Validator) *dialer.Dialer,
 ) error {
 	var masterSecret uri.MasterSecret
 	r.log.Debugf("Loading master secret file from %s", r.flags.pathPrefixer.PrefixPrintablePath(constants.MasterSecretFilename))
@@ -100,7 +122,7 @@ func (r *recoverCmd) recover(
 	}
 
 	r.log.Debugf("Loading configuration file from %q", r.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
-	conf, err := config.New(fileHandler, constants.ConfigFilename, r.configFetcher, r.flags.force)
+	conf, err := config.New(fileHandler, constants.ConfigFilename, r.flags.profile, r.configFetcher, r.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -115,7 +137,7 @@ func (r *recoverCmd) recover(
 		interval = 20 * time.Second // Azure LB takes a while to remove unhealthy instances
 	}
 
-	stateFile, err := state.ReadFromFile(fileHandler, constants.StateFilename)
+	stateFile, err := readStateFromBackend(cmd.Context(), conf.StateBackend, fileHandler)
 	if err != nil {
 		return fmt.Errorf("reading state file: %w", err)
 	}
@@ -123,10 +145,6 @@ func (r *recoverCmd) recover(
 		return fmt.Errorf("validating state file: %w", err)
 	}
 
-	endpoint, err := r.parseEndpoint(stateFile)
-	if err != nil {
-		return err
-	}
 	if stateFile.Infrastructure.Azure != nil {
 		conf.UpdateMAAURL(stateFile.Infrastructure.Azure.AttestationURL)
 	}
@@ -137,6 +155,20 @@ func (r *recoverCmd) recover(
 		return fmt.Errorf("creating new validator: %w", err)
 	}
 	r.log.Debugf("Created a new validator")
+
+	if r.flags.discover || len(r.flags.endpoints) > 1 {
+		nodes, err := r.recoveryNodes(cmd.Context(), conf, stateFile)
+		if err != nil {
+			return err
+		}
+		return r.recoverNodes(cmd.Context(), cmd.OutOrStdout(), interval, nodes, masterSecret.EncodeToURI(), newDoer, newDialer(validator))
+	}
+
+	endpoint, err := r.parseEndpoint(stateFile)
+	if err != nil {
+		return err
+	}
+	doer := newDoer()
 	doer.setDialer(newDialer(validator), endpoint)
 	r.log.Debugf("Set dialer for endpoint %s", endpoint)
 	doer.setURIs(masterSecret.EncodeToURI(), uri.NoStoreURI)
@@ -191,8 +223,135 @@ func (r *recoverCmd) recoverCall(ctx context.Context, out io.Writer, interval ti
 	return err
 }
 
+// recoveryNodes returns the instances to recover directly, either discovered or passed as endpoints.
+func (r *recoverCmd) recoveryNodes(ctx context.Context, conf *config.Config, stateFile *state.State) ([]recoveryNode, error) {
+	var nodes []recoveryNode
+	if r.flags.discover {
+		discoverer, err := r.newDiscoverer(ctx, conf, stateFile.Infrastructure)
+		if err != nil {
+			return nil, fmt.Errorf("setting up node discovery: %w", err)
+		}
+		defer discoverer.Close()
+		discovered, err := discoverer.ControlPlaneNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("discovering control-plane nodes: %w", err)
+		}
+		r.log.Debugf("Discovered %d control-plane nodes", len(discovered))
+		for _, node := range discovered {
+			nodes = append(nodes, recoveryNode{name: node.Name, endpoint: node.IP})
+		}
+	} else {
+		for _, endpoint := range r.flags.endpoints {
+			nodes = append(nodes, recoveryNode{name: endpoint, endpoint: endpoint})
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New("no control-plane nodes found")
+	}
+
+	for i := range nodes {
+		endpoint, err := addPortIfMissing(nodes[i].endpoint, constants.RecoveryPort)
+		if err != nil {
+			return nil, fmt.Errorf("validating endpoint of %s: %w", nodes[i].name, err)
+		}
+		nodes[i].endpoint = endpoint
+	}
+	return nodes, nil
+}
+
+// recoverNodes sends the recovery key to all given nodes concurrently and prints the result for each node.
+func (r *recoverCmd) recoverNodes(
+	ctx context.Context, out io.Writer, interval time.Duration, nodes []recoveryNode, kmsURI string,
+	newDoer func() recoverDoerInterface, dialer grpcDialer,
+) error {
+	results := make([]recoveryResult, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		i, node := i, node
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doer := newDoer()
+			doer.setDialer(dialer, node.endpoint)
+			doer.setURIs(kmsURI, uri.NoStoreURI)
+			results[i] = r.recoverNode(ctx, interval, doer)
+			r.log.Debugf("Recovery of %s: %s", node.name, results[i])
+		}()
+	}
+	wg.Wait()
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tENDPOINT\tRESULT")
+	var recovered, failed int
+	for i, node := range nodes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", node.name, node.endpoint, results[i])
+		switch {
+		case results[i].err != nil:
+			failed++
+		case !results[i].notWaiting:
+			recovered++
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Recovered %d control-plane nodes.\n", recovered)
+	if failed > 0 {
+		return fmt.Errorf("recovering %d of %d nodes failed", failed, len(nodes))
+	}
+	return nil
+}
+
+// recoverNode sends the recovery key to a single node.
+// A node that doesn't answer isn't waiting for recovery, e.g., because it's already running.
+func (r *recoverCmd) recoverNode(ctx context.Context, interval time.Duration, doer recoverDoerInterface) recoveryResult {
+	once := sync.Once{}
+	retriable := func(err error) bool {
+		if grpcRetry.LoadbalancerIsNotReady(err) {
+			return true
+		}
+		// the recovery server may not be up yet, retry once
+		var retry bool
+		once.Do(func() {
+			retry = grpcRetry.ServiceIsUnavailable(err)
+		})
+		return retry
+	}
+
+	err := retry.NewIntervalRetrier(doer, interval, retriable).Do(ctx)
+	if grpcRetry.ServiceIsUnavailable(err) {
+		return recoveryResult{notWaiting: true}
+	}
+	return recoveryResult{err: err}
+}
+
+type recoveryNode struct {
+	name     string
+	endpoint string
+}
+
+type recoveryResult struct {
+	notWaiting bool
+	err        error
+}
+
+func (r recoveryResult) String() string {
+	switch {
+	case r.err != nil:
+		return fmt.Sprintf("failed: %s", r.err)
+	case r.notWaiting:
+		return "not waiting for recovery"
+	default:
+		return "recovered"
+	}
+}
+
 func (r *recoverCmd) parseEndpoint(state *state.State) (string, error) {
-	endpoint := r.flags.endpoint
+	var endpoint string
+	if len(r.flags.endpoints) > 0 {
+		endpoint = r.flags.endpoints[0]
+	}
 	if endpoint == "" {
 		endpoint = state.Infrastructure.ClusterEndpoint
 	}
@@ -203,6 +362,11 @@ func (r *recoverCmd) parseEndpoint(state *state.State) (string, error) {
 	return endpoint, nil
 }
 
+type nodeDiscoverer interface {
+	ControlPlaneNodes(ctx context.Context) ([]cloudcmd.Node, error)
+	Close() error
+}
+
 type recoverDoerInterface interface {
 	Do(ctx context.Context) error
 	setDialer(dialer grpcDialer, endpoint string)
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"strconv"
 	"testing"
@@ -18,8 +19,10 @@ import (
 	"github.com/edgelesssys/constellation/v2/disk-mapper/recoverproto"
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
 	"github.com/edgelesssys/constellation/v2/internal/crypto/testvector"
 	"github.com/edgelesssys/constellation/v2/internal/file"
@@ -168,10 +171,11 @@ func TestRecover(t *testing.T) {
 				configFetcher: stubAttestationFetcher{},
 				flags: recoverFlags{
 					rootFlags: rootFlags{force: true},
-					endpoint:  tc.endpoint,
+					endpoints: []string{tc.endpoint},
 				},
 			}
-			err := r.recover(cmd, fileHandler, time.Millisecond, tc.doer, newDialer)
+			newDoer := func() recoverDoerInterface { return tc.doer }
+			err := r.recover(cmd, fileHandler, time.Millisecond, newDoer, newDialer)
 			if tc.wantErr {
 				assert.Error(err)
 				if tc.successfulCalls > 0 {
@@ -191,6 +195,121 @@ func TestRecover(t *testing.T) {
 	}
 }
 
+func TestRecoverNodes(t *testing.T) {
+	unavailableErr := grpcstatus.Error(codes.Unavailable, "unavailable")
+
+	testCases := map[string]struct {
+		endpoints     []string
+		discover      bool
+		discoverer    *stubNodeDiscoverer
+		doerErrs      map[string]error
+		wantRecovered int
+		wantResults   map[string]string
+		wantErr       bool
+	}{
+		"multiple endpoints": {
+			endpoints: []string{"192.0.2.1", "192.0.2.2:9999", "192.0.2.3"},
+			doerErrs: map[string]error{
+				"192.0.2.3:9999": unavailableErr,
+			},
+			wantRecovered: 2,
+			wantResults: map[string]string{
+				"192.0.2.1":      "recovered",
+				"192.0.2.2:9999": "recovered",
+				"192.0.2.3":      "not waiting for recovery",
+			},
+		},
+		"discovered nodes": {
+			discover: true,
+			discoverer: &stubNodeDiscoverer{nodes: []cloudcmd.Node{
+				{Name: "control-plane-0", IP: "192.0.2.1"},
+				{Name: "control-plane-1", IP: "192.0.2.2"},
+			}},
+			wantRecovered: 2,
+			wantResults: map[string]string{
+				"control-plane-0": "recovered",
+				"control-plane-1": "recovered",
+			},
+		},
+		"node fails": {
+			endpoints: []string{"192.0.2.1", "192.0.2.2"},
+			doerErrs: map[string]error{
+				"192.0.2.2:9999": assert.AnError,
+			},
+			wantRecovered: 1,
+			wantResults: map[string]string{
+				"192.0.2.1": "recovered",
+				"192.0.2.2": "failed",
+			},
+			wantErr: true,
+		},
+		"discovery fails": {
+			discover:   true,
+			discoverer: &stubNodeDiscoverer{err: assert.AnError},
+			wantErr:    true,
+		},
+		"no nodes discovered": {
+			discover:   true,
+			discoverer: &stubNodeDiscoverer{},
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cmd := NewRecoverCmd()
+			cmd.SetContext(context.Background())
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetErr(out)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			conf := defaultConfigWithExpectedMeasurements(t, config.Default(), cloudprovider.GCP)
+			require.NoError(fileHandler.WriteYAML(constants.ConfigFilename, conf))
+			require.NoError(fileHandler.WriteJSON(
+				constants.MasterSecretFilename,
+				uri.MasterSecret{Key: testvector.HKDFZero.Secret, Salt: testvector.HKDFZero.Salt},
+				file.OptNone,
+			))
+			require.NoError(fileHandler.WriteYAML(constants.StateFilename, defaultStateFile(cloudprovider.GCP), file.OptNone))
+
+			r := &recoverCmd{
+				log:           logger.NewTest(t),
+				configFetcher: stubAttestationFetcher{},
+				newDiscoverer: func(context.Context, *config.Config, state.Infrastructure) (nodeDiscoverer, error) {
+					return tc.discoverer, nil
+				},
+				flags: recoverFlags{
+					rootFlags: rootFlags{force: true},
+					endpoints: tc.endpoints,
+					discover:  tc.discover,
+				},
+			}
+			newDoer := func() recoverDoerInterface { return &stubEndpointDoer{errs: tc.doerErrs} }
+			newDialer := func(atls.Validator) *dialer.Dialer { return nil }
+
+			err := r.recover(cmd, fileHandler, time.Millisecond, newDoer, newDialer)
+			if tc.wantErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if tc.discoverer != nil {
+				assert.True(tc.discoverer.closed)
+			}
+			for node, result := range tc.wantResults {
+				assert.Regexp(node+`\s+\S+\s+`+result, out.String())
+			}
+			if tc.wantResults != nil {
+				assert.Contains(out.String(), fmt.Sprintf("Recovered %d control-plane nodes.", tc.wantRecovered))
+			}
+		})
+	}
+}
+
 func TestDoRecovery(t *testing.T) {
 	testCases := map[string]struct {
 		recoveryServer *stubRecoveryServer
@@ -295,3 +414,33 @@ func (d *stubDoer) Do(context.Context) error {
 func (d *stubDoer) setDialer(grpcDialer, string) {}
 
 func (d *stubDoer) setURIs(_, _ string) {}
+
+type stubEndpointDoer struct {
+	errs     map[string]error
+	endpoint string
+}
+
+func (d *stubEndpointDoer) Do(context.Context) error {
+	return d.errs[d.endpoint]
+}
+
+func (d *stubEndpointDoer) setDialer(_ grpcDialer, endpoint string) {
+	d.endpoint = endpoint
+}
+
+func (d *stubEndpointDoer) setURIs(_, _ string) {}
+
+type stubNodeDiscoverer struct {
+	nodes  []cloudcmd.Node
+	err    error
+	closed bool
+}
+
+func (d *stubNodeDiscoverer) ControlPlaneNodes(context.Context) ([]cloudcmd.Node, error) {
+	return d.nodes, d.err
+}
+
+func (d *stubNodeDiscoverer) Close() error {
+	d.closed = true
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/backup"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NewRestoreCmd returns a new cobra.Command for the restore command.
+func NewRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore BACKUP",
+		Short: "Restore a Constellation cluster from an encrypted backup",
+		Long: "Restore a Constellation cluster from an encrypted backup created by 'constellation backup'.\n\n" +
+			"Restoring a cluster onto fresh infrastructure takes two steps:\n" +
+			"1. Run restore in an empty workspace. This writes the config file of the backed up cluster to the workspace.\n" +
+			"   Create the new cluster from it using 'constellation apply'. The new cluster reuses the master secret and measurement salt of the backed up cluster.\n" +
+			"2. Run restore again in the same workspace. This restores the resources from the etcd snapshot, and the Secrets and CRs of the backed up cluster into the new cluster.\n\n" +
+			"Resources that already exist in the new cluster aren't modified. " +
+			"Resources describing the infrastructure of the backed up cluster aren't restored.\n" +
+			"Use --extract-to to write all files of the backup, including the master secret, the state file, and the etcd snapshot, to a directory for manual recovery.",
+		Args: cobra.ExactArgs(1),
+		RunE: runRestore,
+	}
+	cmd.Flags().String("key-file", "", "path to a file holding the key used to encrypt the backup")
+	must(cmd.MarkFlagRequired("key-file"))
+	cmd.Flags().String("extract-to", "", "write the decrypted contents of the backup to this directory instead of restoring the cluster")
+	return cmd
+}
+
+type restoreFlags struct {
+	rootFlags
+	keyFile   string
+	extractTo string
+}
+
+func (f *restoreFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	var err error
+	f.keyFile, err = flags.GetString("key-file")
+	if err != nil {
+		return fmt.Errorf("getting 'key-file' flag: %w", err)
+	}
+	f.extractTo, err = flags.GetString("extract-to")
+	if err != nil {
+		return fmt.Errorf("getting 'extract-to' flag: %w", err)
+	}
+	return nil
+}
+
+type restoreCmd struct {
+	log         debugLog
+	fileHandler file.Handler
+	spinner     spinnerInterf
+	flags       restoreFlags
+	newRestorer func(kubeConfig []byte) (clusterRestorer, error)
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	log, err := newCLILogger(cmd)
+	if err != nil {
+		return fmt.Errorf("creating logger: %w", err)
+	}
+	defer log.Sync()
+	spinner, err := newSpinnerOrStderr(cmd)
+	if err != nil {
+		return fmt.Errorf("creating spinner: %w", err)
+	}
+	defer spinner.Stop()
+
+	r := &restoreCmd{
+		log:         log,
+		fileHandler: file.NewHandler(afero.NewOsFs()),
+		spinner:     spinner,
+		newRestorer: func(kubeConfig []byte) (clusterRestorer, error) {
+			return kubecmd.New(kubeConfig, log)
+		},
+	}
+	if err := r.flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+	return r.restore(cmd, args[0])
+}
+
+func (r *restoreCmd) restore(cmd *cobra.Command, backupFile string) error {
+	key, err := readBackupKey(r.fileHandler, r.flags.keyFile)
+	if err != nil {
+		return err
+	}
+	sealed, err := r.fileHandler.Read(backupFile)
+	if err != nil {
+		return fmt.Errorf("reading backup: %w", err)
+	}
+	bundle, err := backup.Open(sealed, key)
+	if err != nil {
+		return fmt.Errorf("opening backup: %w", err)
+	}
+
+	if r.flags.extractTo != "" {
+		if err := bundle.Extract(r.fileHandler, r.flags.extractTo); err != nil {
+			return fmt.Errorf("extracting backup: %w", err)
+		}
+		cmd.Printf("Backup extracted to %s\n", r.flags.pathPrefixer.PrefixPrintablePath(r.flags.extractTo))
+		return nil
+	}
+
+	if _, err := r.fileHandler.Stat(constants.AdminConfFilename); errors.Is(err, fs.ErrNotExist) {
+		return r.restoreWorkspace(cmd, bundle)
+	} else if err != nil {
+		return fmt.Errorf("checking for %q: %w", r.flags.pathPrefixer.PrefixPrintablePath(constants.AdminConfFilename), err)
+	}
+
+	kubeConfig, err := r.fileHandler.Read(constants.AdminConfFilename)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	restorer, err := r.newRestorer(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("setting up kubernetes client: %w", err)
+	}
+	return r.restoreCluster(cmd, bundle, restorer)
+}
+
+// restoreWorkspace prepares a workspace to create a new cluster from the backup.
+func (r *restoreCmd) restoreWorkspace(cmd *cobra.Command, bundle *backup.Bundle) error {
+	bundleFiles := bundle.FileHandler()
+	conf, err := bundleFiles.Read(constants.ConfigFilename)
+	if err != nil {
+		return fmt.Errorf("reading config from backup: %w", err)
+	}
+	if err := r.fileHandler.Write(constants.ConfigFilename, conf); errors.Is(err, fs.ErrExist) {
+		cmd.Printf("Keeping existing config file %s\n", r.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
+	} else if err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	} else {
+		cmd.Printf("Config file of the backed up cluster written to %s\n", r.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
+	}
+
+	// The infrastructure of the backed up cluster is gone, so its state file can't be reused as is.
+	// Only the secrets the new cluster has to share with the old one are carried over to 'constellation apply'.
+	var restored restoredSecrets
+	if err := bundleFiles.ReadJSON(constants.MasterSecretFilename, &restored.MasterSecret); err != nil {
+		return fmt.Errorf("reading master secret from backup: %w", err)
+	}
+	backedUpState, err := state.ReadFromFile(bundleFiles, constants.StateFilename)
+	if err != nil {
+		return fmt.Errorf("reading state from backup: %w", err)
+	}
+	restored.MeasurementSalt = backedUpState.ClusterValues.MeasurementSalt
+	if err := r.fileHandler.WriteJSON(constants.RestoredSecretsFilename, restored, file.OptOverwrite); err != nil {
+		return fmt.Errorf("writing restored secrets: %w", err)
+	}
+	cmd.Printf("Master secret of the backed up cluster written to %s\n", r.flags.pathPrefixer.PrefixPrintablePath(constants.RestoredSecretsFilename))
+
+	cmd.Println("No cluster found in the workspace.")
+	cmd.Println("Create a new cluster with 'constellation apply', then run 'constellation restore' again to restore its resources.")
+	return nil
+}
+
+// restoreCluster restores the etcd snapshot, Secrets, and CRs of the backup into the cluster of the workspace.
+func (r *restoreCmd) restoreCluster(cmd *cobra.Command, bundle *backup.Bundle, restorer clusterRestorer) error {
+	etcdSnapshot, err := bundle.EtcdSnapshot()
+	if err != nil {
+		return fmt.Errorf("reading etcd snapshot from backup: %w", err)
+	}
+	secrets, err := bundle.Secrets()
+	if err != nil {
+		return fmt.Errorf("reading secrets from backup: %w", err)
+	}
+	crs, err := bundle.CustomResources()
+	if err != nil {
+		return fmt.Errorf("reading custom resources from backup: %w", err)
+	}
+
+	r.spinner.Start("Restoring cluster resources", false)
+	restored, err := restoreResources(cmd.Context(), restorer, etcdSnapshot, secrets, crs)
+	r.spinner.Stop()
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("Restored %d resources from the etcd snapshot, %d secrets, and %d custom resources\n", restored.etcdKeys, restored.secrets, restored.crs)
+	return nil
+}
+
+// restoredResources counts the resources restored into a cluster.
+type restoredResources struct {
+	etcdKeys int
+	secrets  int
+	crs      int
+}
+
+// restoreResources restores the backed up resources into the cluster.
+// The etcd snapshot is restored first, so resources restored from it keep their UIDs,
+// and owner references between them stay valid.
+func restoreResources(
+	ctx context.Context, restorer clusterRestorer, etcdSnapshot []byte, secrets []corev1.Secret, crs []unstructured.Unstructured,
+) (restored restoredResources, err error) {
+	if etcdSnapshot != nil {
+		restored.etcdKeys, err = restorer.RestoreEtcd(ctx, etcdSnapshot)
+		if err != nil {
+			return restored, fmt.Errorf("restoring etcd snapshot: %w", err)
+		}
+	}
+	restored.secrets, err = restorer.RestoreSecrets(ctx, secrets)
+	if err != nil {
+		return restored, fmt.Errorf("restoring secrets: %w", err)
+	}
+	restored.crs, err = restorer.RestoreCRs(ctx, crs)
+	if err != nil {
+		return restored, fmt.Errorf("restoring custom resources: %w", err)
+	}
+	return restored, nil
+}
+
+// restoredSecrets are the secrets of a backed up cluster that a new cluster is initialized with.
+type restoredSecrets struct {
+	MasterSecret    uri.MasterSecret `json:"masterSecret"`
+	MeasurementSalt []byte           `json:"measurementSalt"`
+}
+
+type clusterRestorer interface {
+	RestoreEtcd(ctx context.Context, snapshot []byte) (int, error)
+	RestoreSecrets(ctx context.Context, secrets []corev1.Secret) (int, error)
+	RestoreCRs(ctx context.Context, crs []unstructured.Unstructured) (int, error)
+}
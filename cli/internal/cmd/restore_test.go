@@ -0,0 +1,202 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/backup"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRestore(t *testing.T) {
+	backupKey := bytes.Repeat([]byte{0x01}, 32)
+	masterSecret := uri.MasterSecret{Key: []byte("master-secret-key"), Salt: []byte("master-secret-salt")}
+	measurementSalt := []byte("measurement-salt")
+	newBackup := func(t *testing.T) []byte {
+		bundle := backup.New()
+		fh := bundle.FileHandler()
+		require.NoError(t, fh.Write(constants.ConfigFilename, []byte("backed up config")))
+		require.NoError(t, fh.WriteJSON(constants.MasterSecretFilename, masterSecret))
+		require.NoError(t, state.New().SetClusterValues(state.ClusterValues{MeasurementSalt: measurementSalt}).WriteToFile(fh, constants.StateFilename))
+		require.NoError(t, fh.Write(filepath.Join(backup.SecretsDir, "app", "secret.yaml"),
+			[]byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: secret\n  namespace: app\n"), file.OptMkdirAll))
+		require.NoError(t, fh.Write(filepath.Join(backup.ResourcesDir, "backups", "cert-manager.io", "v1", "app", "Certificate", "cert.yaml"),
+			[]byte("apiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: cert\n  namespace: app\n"), file.OptMkdirAll))
+		require.NoError(t, fh.Write(backup.EtcdSnapshotFilename, []byte(`{"kvs":[]}`)))
+		sealed, err := bundle.Seal(backupKey)
+		require.NoError(t, err)
+		return sealed
+	}
+
+	testCases := map[string]struct {
+		prepareFs        func(*require.Assertions, file.Handler)
+		key              []byte
+		extractTo        string
+		restorer         *stubClusterRestorer
+		wantConfig       string
+		wantRestored     bool
+		wantEtcdSnapshot string
+		wantSecrets      int
+		wantCRs          int
+		wantExtracted    string
+		wantErr          bool
+	}{
+		"restore workspace": {
+			key:          backupKey,
+			restorer:     &stubClusterRestorer{},
+			wantConfig:   "backed up config",
+			wantRestored: true,
+		},
+		"restore workspace keeps existing config": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.ConfigFilename, []byte("existing config")))
+			},
+			key:          backupKey,
+			restorer:     &stubClusterRestorer{},
+			wantConfig:   "existing config",
+			wantRestored: true,
+		},
+		"restore cluster": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.AdminConfFilename, []byte("kubeconfig")))
+			},
+			key:              backupKey,
+			restorer:         &stubClusterRestorer{},
+			wantEtcdSnapshot: `{"kvs":[]}`,
+			wantSecrets:      1,
+			wantCRs:          1,
+		},
+		"extract": {
+			key:           backupKey,
+			extractTo:     "extracted",
+			restorer:      &stubClusterRestorer{},
+			wantExtracted: filepath.Join("extracted", constants.MasterSecretFilename),
+		},
+		"wrong key": {
+			key:      bytes.Repeat([]byte{0x02}, 32),
+			restorer: &stubClusterRestorer{},
+			wantErr:  true,
+		},
+		"restoring etcd snapshot fails": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.AdminConfFilename, []byte("kubeconfig")))
+			},
+			key:      backupKey,
+			restorer: &stubClusterRestorer{restoreEtcdErr: assert.AnError},
+			wantErr:  true,
+		},
+		"restoring secrets fails": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.AdminConfFilename, []byte("kubeconfig")))
+			},
+			key:      backupKey,
+			restorer: &stubClusterRestorer{restoreSecretsErr: assert.AnError},
+			wantErr:  true,
+		},
+		"restoring CRs fails": {
+			prepareFs: func(require *require.Assertions, fh file.Handler) {
+				require.NoError(fh.Write(constants.AdminConfFilename, []byte("kubeconfig")))
+			},
+			key:      backupKey,
+			restorer: &stubClusterRestorer{restoreCRsErr: assert.AnError},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			require.NoError(fileHandler.Write(defaultBackupFilename, newBackup(t)))
+			require.NoError(fileHandler.Write("backup.key", tc.key))
+			if tc.prepareFs != nil {
+				tc.prepareFs(require, fileHandler)
+			}
+
+			cmd := NewRestoreCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			r := &restoreCmd{
+				log:         logger.NewTest(t),
+				fileHandler: fileHandler,
+				spinner:     &nopSpinner{},
+				flags:       restoreFlags{keyFile: "backup.key", extractTo: tc.extractTo},
+				newRestorer: func([]byte) (clusterRestorer, error) {
+					return tc.restorer, nil
+				},
+			}
+
+			err := r.restore(cmd, defaultBackupFilename)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			if tc.wantConfig != "" {
+				conf, err := fileHandler.Read(constants.ConfigFilename)
+				require.NoError(err)
+				assert.Equal(tc.wantConfig, string(conf))
+			}
+			if tc.wantRestored {
+				var restored restoredSecrets
+				require.NoError(fileHandler.ReadJSON(constants.RestoredSecretsFilename, &restored))
+				assert.Equal(masterSecret, restored.MasterSecret)
+				assert.Equal(measurementSalt, restored.MeasurementSalt)
+			} else {
+				_, err := fileHandler.Stat(constants.RestoredSecretsFilename)
+				assert.ErrorIs(err, fs.ErrNotExist)
+			}
+			if tc.wantExtracted != "" {
+				_, err := fileHandler.Stat(tc.wantExtracted)
+				assert.NoError(err)
+			}
+			assert.Equal(tc.wantEtcdSnapshot, string(tc.restorer.etcdSnapshot))
+			assert.Len(tc.restorer.secrets, tc.wantSecrets)
+			assert.Len(tc.restorer.crs, tc.wantCRs)
+		})
+	}
+}
+
+type stubClusterRestorer struct {
+	etcdSnapshot      []byte
+	secrets           []corev1.Secret
+	crs               []unstructured.Unstructured
+	restoreEtcdErr    error
+	restoreSecretsErr error
+	restoreCRsErr     error
+}
+
+func (s *stubClusterRestorer) RestoreEtcd(_ context.Context, snapshot []byte) (int, error) {
+	s.etcdSnapshot = snapshot
+	return 1, s.restoreEtcdErr
+}
+
+func (s *stubClusterRestorer) RestoreSecrets(_ context.Context, secrets []corev1.Secret) (int, error) {
+	s.secrets = secrets
+	return len(secrets), s.restoreSecretsErr
+}
+
+func (s *stubClusterRestorer) RestoreCRs(_ context.Context, crs []unstructured.Unstructured) (int, error) {
+	s.crs = crs
+	return len(crs), s.restoreCRsErr
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	tty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +29,13 @@ const (
 var (
 	spinnerStates = []string{"⣷", "⣯", "⣟", "⡿", "⢿", "⣻", "⣽", "⣾"}
 	dotsStates    = []string{".  ", ".. ", "..."}
+
+	// progressTexts are the spinner texts shown for the phases of cluster operations.
+	progressTexts = map[constellation.Phase]string{
+		constellation.PhaseConnecting:   "Connecting ",
+		constellation.PhaseInitializing: "Initializing cluster ",
+		constellation.PhaseVerifying:    "Verifying ",
+	}
 )
 
 type spinnerInterf interface {
@@ -124,6 +132,22 @@ func spinNoTTY(out io.Writer, wg *sync.WaitGroup, _ *atomic.Bool, _ time.Duratio
 	fmt.Fprintln(out, text+"...")
 }
 
+// spinnerProgress returns a progress callback that shows the current phase of a cluster operation using the spinner.
+// Phases without a spinner text are ignored, since the CLI prints its own output for them.
+func spinnerProgress(s spinnerInterf) constellation.ProgressFunc {
+	return func(event constellation.ProgressEvent) {
+		text, ok := progressTexts[event.Phase]
+		if !ok {
+			return
+		}
+		if event.Done {
+			s.Stop()
+			return
+		}
+		s.Start(text, false)
+	}
+}
+
 type nopSpinner struct {
 	io.Writer
 }
@@ -0,0 +1,21 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+// NewStateCmd returns a new cobra.Command for the state parent command. It needs another verb and does nothing on its own.
+func NewStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Manage the state of your Constellation cluster",
+		Long:  "Manage the state of your Constellation cluster.",
+		Args:  cobra.ExactArgs(0),
+	}
+
+	cmd.AddCommand(newStateForceUnlockCmd())
+	return cmd
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/cobra"
+)
+
+// newStateBackend returns the backend storing the state file as configured in the user's config.
+// If no remote backend is configured, the state file in the workspace is used.
+func newStateBackend(ctx context.Context, cfg *config.StateBackendConfig, fileHandler file.Handler) (backend.Backend, error) {
+	var stateBackend backend.Backend
+	switch {
+	case cfg != nil && cfg.S3 != nil:
+		s3Backend, err := backend.NewS3(ctx, backend.S3Config{
+			Bucket:       cfg.S3.Bucket,
+			Key:          cfg.S3.Key,
+			Region:       cfg.S3.Region,
+			Endpoint:     cfg.S3.Endpoint,
+			UsePathStyle: cfg.S3.UsePathStyle,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating S3 state backend: %w", err)
+		}
+		stateBackend = s3Backend
+	case cfg != nil && cfg.Kubernetes != nil:
+		k8sBackend, err := backend.NewKubernetes(backend.KubernetesConfig{
+			Kubeconfig: cfg.Kubernetes.Kubeconfig,
+			Namespace:  cfg.Kubernetes.Namespace,
+			Name:       cfg.Kubernetes.Name,
+			Kind:       cfg.Kubernetes.Kind,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating Kubernetes state backend: %w", err)
+		}
+		stateBackend = k8sBackend
+	default:
+		stateBackend = backend.NewLocal(fileHandler, constants.StateFilename)
+	}
+
+	if cfg == nil || !cfg.Encrypt {
+		return stateBackend, nil
+	}
+	encodedKey, ok := os.LookupEnv(constants.EnvVarStateEncryptionKey)
+	if !ok {
+		return nil, fmt.Errorf("state encryption is enabled, but %s is not set", constants.EnvVarStateEncryptionKey)
+	}
+	key, err := hex.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", constants.EnvVarStateEncryptionKey, err)
+	}
+	return backend.NewEncrypted(stateBackend, key)
+}
+
+// readStateFromBackend reads the state from the backend configured in the user's config.
+func readStateFromBackend(ctx context.Context, cfg *config.StateBackendConfig, fileHandler file.Handler) (*state.State, error) {
+	stateBackend, err := newStateBackend(ctx, cfg, fileHandler)
+	if err != nil {
+		return nil, err
+	}
+	return state.ReadFromBackend(ctx, stateBackend)
+}
+
+// lockState acquires the lock of the state backend for the given operation and returns the lock ID.
+func lockState(cmd *cobra.Command, stateBackend backend.Backend, operation string) (string, error) {
+	info, err := backend.NewLockInfo(operation)
+	if err != nil {
+		return "", err
+	}
+	if err := stateBackend.Lock(cmd.Context(), info); err != nil {
+		var lockedErr *backend.LockedError
+		if errors.As(err, &lockedErr) {
+			cmd.PrintErrf("If the operation holding the lock was aborted, release the lock with \"constellation state force-unlock %s\".\n", lockedErr.Info.ID)
+		}
+		return "", fmt.Errorf("acquiring state lock: %w", err)
+	}
+	return info.ID, nil
+}
+
+// unlockState releases the lock with the given ID.
+// Since the locked operation already finished, failures are only reported as a warning.
+func unlockState(cmd *cobra.Command, stateBackend backend.Backend, id string) {
+	// Use a fresh context, so the lock is also released if the operation was canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := stateBackend.Unlock(ctx, id); err != nil {
+		cmd.PrintErrf("Warning: failed to release the state lock: %s\n", err)
+		cmd.PrintErrf("Release it with \"constellation state force-unlock %s\".\n", id)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newStateForceUnlockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "force-unlock LOCK_ID",
+		Short: "Release a stale lock of the state",
+		Long: "Release a stale lock of the state.\n\n" +
+			"Operations modifying the cluster lock its state, so that concurrent operations can't corrupt it. " +
+			"If an operation is aborted, the lock may not be released. " +
+			"Only release a lock if you are sure that the operation holding it isn't running anymore.",
+		Args: cobra.ExactArgs(1),
+		RunE: runStateForceUnlock,
+	}
+	cmd.Flags().BoolP("yes", "y", false, "release the lock without further confirmation")
+	return cmd
+}
+
+type stateForceUnlockFlags struct {
+	rootFlags
+	yes bool
+}
+
+func (f *stateForceUnlockFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	yes, err := flags.GetBool("yes")
+	if err != nil {
+		return fmt.Errorf("getting 'yes' flag: %w", err)
+	}
+	f.yes = yes
+	return nil
+}
+
+func runStateForceUnlock(cmd *cobra.Command, args []string) error {
+	log, err := newCLILogger(cmd)
+	if err != nil {
+		return fmt.Errorf("creating logger: %w", err)
+	}
+	defer log.Sync()
+
+	fileHandler := file.NewHandler(afero.NewOsFs())
+	u := &stateForceUnlockCmd{log: log}
+	if err := u.flags.parse(cmd.Flags()); err != nil {
+		return err
+	}
+
+	backendConf, err := config.ReadStateBackend(fileHandler, constants.ConfigFilename, u.flags.profile)
+	if err != nil {
+		return fmt.Errorf("reading state backend configuration: %w", err)
+	}
+	stateBackend, err := newStateBackend(cmd.Context(), backendConf, fileHandler)
+	if err != nil {
+		return err
+	}
+
+	return u.forceUnlock(cmd, stateBackend, args[0])
+}
+
+type stateForceUnlockCmd struct {
+	log   debugLog
+	flags stateForceUnlockFlags
+}
+
+func (u *stateForceUnlockCmd) forceUnlock(cmd *cobra.Command, stateBackend backend.Backend, lockID string) error {
+	if !u.flags.yes {
+		cmd.Printf("You are about to release the state lock %s.\n", lockID)
+		cmd.Println("If the operation holding the lock is still running, it may corrupt the state of your cluster.")
+		ok, err := askToConfirm(cmd, "Do you want to continue?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			cmd.Println("Releasing the state lock was aborted.")
+			return nil
+		}
+	}
+
+	u.log.Debugf("Releasing state lock %s", lockID)
+	if err := stateBackend.Unlock(cmd.Context(), lockID); err != nil {
+		return fmt.Errorf("releasing state lock: %w", err)
+	}
+	cmd.Println("The state lock was released successfully.")
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateForceUnlock(t *testing.T) {
+	heldLock := backend.LockInfo{ID: "held", Operation: "apply", Who: "alice@host"}
+
+	testCases := map[string]struct {
+		lockID     string
+		yesFlag    bool
+		stdin      string
+		wantErr    bool
+		wantLocked bool
+	}{
+		"success": {
+			lockID:  heldLock.ID,
+			yesFlag: true,
+		},
+		"interactive": {
+			lockID: heldLock.ID,
+			stdin:  "yes\n",
+		},
+		"interactive abort": {
+			lockID:     heldLock.ID,
+			stdin:      "no\n",
+			wantLocked: true,
+		},
+		"wrong lock ID": {
+			lockID:     "other",
+			yesFlag:    true,
+			wantErr:    true,
+			wantLocked: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cmd := NewStateCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetIn(bytes.NewBufferString(tc.stdin))
+
+			stateBackend := backend.NewLocal(file.NewHandler(afero.NewMemMapFs()), constants.StateFilename)
+			require.NoError(stateBackend.Lock(context.Background(), heldLock))
+
+			u := &stateForceUnlockCmd{
+				log:   logger.NewTest(t),
+				flags: stateForceUnlockFlags{yes: tc.yesFlag},
+			}
+			err := u.forceUnlock(cmd, stateBackend, tc.lockID)
+			if tc.wantErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			err = stateBackend.Lock(context.Background(), backend.LockInfo{ID: "new"})
+			if tc.wantLocked {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
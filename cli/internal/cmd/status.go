@@ -8,22 +8,46 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/api/attestationconfigapi"
+	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
+	"github.com/edgelesssys/constellation/v2/verify/verifyproto"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	statusOutputText = "text"
+	statusOutputJSON = "json"
+	statusOutputYAML = "yaml"
+)
+
+// statusSchemaVersion is the version of the machine-readable status output.
+// Fields may be added to the schema, but existing fields must not be renamed or removed without bumping the version.
+const statusSchemaVersion = 1
+
+// nodeVerifyTimeout is the maximum time spent on live-attesting a single node.
+const nodeVerifyTimeout = 30 * time.Second
+
 // NewStatusCmd returns a new cobra.Command for the statuus command.
 func NewStatusCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -34,9 +58,41 @@ func NewStatusCmd() *cobra.Command {
 		Args: cobra.NoArgs,
 		RunE: runStatus,
 	}
+	cmd.Flags().StringP("output", "o", statusOutputText,
+		fmt.Sprintf("output format {%s|%s|%s}", statusOutputText, statusOutputJSON, statusOutputYAML))
+	cmd.Flags().Bool("verify", false, "live-attest every node through its verification service\n"+
+		"The internal IP addresses of the nodes must be reachable from this machine.")
 	return cmd
 }
 
+type statusFlags struct {
+	rootFlags
+	output string
+	verify bool
+}
+
+func (f *statusFlags) parse(flags *pflag.FlagSet) error {
+	if err := f.rootFlags.parse(flags); err != nil {
+		return err
+	}
+
+	var err error
+	f.output, err = flags.GetString("output")
+	if err != nil {
+		return fmt.Errorf("getting 'output' flag: %w", err)
+	}
+	switch f.output {
+	case statusOutputText, statusOutputJSON, statusOutputYAML:
+	default:
+		return fmt.Errorf("invalid output format %q, must be one of %s, %s, %s", f.output, statusOutputText, statusOutputJSON, statusOutputYAML)
+	}
+	f.verify, err = flags.GetBool("verify")
+	if err != nil {
+		return fmt.Errorf("getting 'verify' flag: %w", err)
+	}
+	return nil
+}
+
 // runStatus runs the terminate command.
 func runStatus(cmd *cobra.Command, _ []string) error {
 	log, err := newCLILogger(cmd)
@@ -56,7 +112,7 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return fmt.Errorf("setting up helm client: %w", err)
 	}
-	helmVersionGetter := func() (fmt.Stringer, error) {
+	helmVersionGetter := func() (serviceVersions, error) {
 		return helmClient.Versions()
 	}
 
@@ -66,7 +122,11 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("setting up kubernetes client: %w", err)
 	}
 
-	s := statusCmd{log: log, fileHandler: fileHandler}
+	s := statusCmd{
+		log:          log,
+		fileHandler:  fileHandler,
+		verifyClient: newConstellationVerifier(log),
+	}
 	if err := s.flags.parse(cmd.Flags()); err != nil {
 		return err
 	}
@@ -74,17 +134,18 @@ func runStatus(cmd *cobra.Command, _ []string) error {
 }
 
 type statusCmd struct {
-	log         debugLog
-	fileHandler file.Handler
-	flags       rootFlags
+	log          debugLog
+	fileHandler  file.Handler
+	verifyClient verifyClient
+	flags        statusFlags
 }
 
 // status queries the cluster for the relevant status information and returns the output string.
 func (s *statusCmd) status(
-	cmd *cobra.Command, getHelmVersions func() (fmt.Stringer, error),
+	cmd *cobra.Command, getHelmVersions func() (serviceVersions, error),
 	kubeClient kubeCmd, fetcher attestationconfigapi.Fetcher,
 ) error {
-	conf, err := config.New(s.fileHandler, constants.ConfigFilename, fetcher, s.flags.force)
+	conf, err := config.New(s.fileHandler, constants.ConfigFilename, s.flags.profile, fetcher, s.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -102,10 +163,6 @@ func (s *statusCmd) status(
 	if err != nil {
 		return fmt.Errorf("getting attestation config: %w", err)
 	}
-	prettyYAML, err := yaml.Marshal(attestationConfig)
-	if err != nil {
-		return fmt.Errorf("marshalling attestation config: %w", err)
-	}
 
 	serviceVersions, err := getHelmVersions()
 	if err != nil {
@@ -117,13 +174,92 @@ func (s *statusCmd) status(
 		return fmt.Errorf("getting cluster status: %w", err)
 	}
 
-	cmd.Print(statusOutput(nodeVersion, serviceVersions, status, string(prettyYAML)))
+	var attestations map[string]nodeAttestationStatus
+	if s.flags.verify {
+		attestations, err = s.verifyNodes(cmd, conf, status)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch s.flags.output {
+	case statusOutputJSON:
+		out, err := json.MarshalIndent(newClusterStatus(nodeVersion, serviceVersions, status, attestationConfig, attestations), "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshalling status: %w", err)
+		}
+		cmd.Println(string(out))
+	case statusOutputYAML:
+		out, err := yaml.Marshal(newClusterStatus(nodeVersion, serviceVersions, status, attestationConfig, attestations))
+		if err != nil {
+			return fmt.Errorf("marshalling status: %w", err)
+		}
+		cmd.Print(string(out))
+	default:
+		prettyYAML, err := yaml.Marshal(attestationConfig)
+		if err != nil {
+			return fmt.Errorf("marshalling attestation config: %w", err)
+		}
+		cmd.Print(statusOutput(nodeVersion, serviceVersions, status, string(prettyYAML)))
+		if attestations != nil {
+			cmd.Print(nodeAttestationString(attestations))
+		}
+	}
 	return nil
 }
 
+// verifyNodes live-attests every node of the cluster using the attestation config of the local config file.
+// Failing to attest a node is not an error, but is recorded in the returned map.
+func (s *statusCmd) verifyNodes(
+	cmd *cobra.Command, conf *config.Config, status map[string]kubecmd.NodeStatus,
+) (map[string]nodeAttestationStatus, error) {
+	stateFile, err := readStateFromBackend(cmd.Context(), conf.StateBackend, s.fileHandler)
+	if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+	if stateFile.Infrastructure.Azure != nil {
+		conf.UpdateMAAURL(stateFile.Infrastructure.Azure.AttestationURL)
+	}
+	attConfig := conf.GetAttestationConfig()
+	if err := constellation.UpdateInitMeasurements(attConfig, stateFile.ClusterValues.OwnerID, stateFile.ClusterValues.ClusterID); err != nil {
+		return nil, fmt.Errorf("updating expected PCRs: %w", err)
+	}
+	validator, err := choose.Validator(attConfig, warnLogger{cmd: cmd, log: s.log})
+	if err != nil {
+		return nil, fmt.Errorf("creating aTLS validator: %w", err)
+	}
+
+	attestations := make(map[string]nodeAttestationStatus, len(status))
+	for name, node := range status {
+		if err := s.verifyNode(cmd.Context(), node, validator); err != nil {
+			s.log.Debugf("Attesting node %q failed: %s", name, err)
+			attestations[name] = nodeAttestationStatus{Error: err.Error()}
+			continue
+		}
+		attestations[name] = nodeAttestationStatus{Verified: true}
+	}
+	return attestations, nil
+}
+
+func (s *statusCmd) verifyNode(ctx context.Context, node kubecmd.NodeStatus, validator atls.Validator) error {
+	if node.InternalIP() == "" {
+		return errors.New("node has no internal IP address")
+	}
+	nonce, err := crypto.GenerateRandomBytes(32)
+	if err != nil {
+		return fmt.Errorf("generating random nonce: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, nodeVerifyTimeout)
+	defer cancel()
+	endpoint := net.JoinHostPort(node.InternalIP(), strconv.Itoa(constants.VerifyServiceNodePortGRPC))
+	_, err = s.verifyClient.Verify(ctx, endpoint, &verifyproto.GetAttestationRequest{Nonce: nonce}, validator)
+	return err
+}
+
 // statusOutput creates the status cmd output string by formatting the received information.
 func statusOutput(
-	nodeVersion kubecmd.NodeVersion, serviceVersions fmt.Stringer,
+	nodeVersion kubecmd.NodeVersion, serviceVersions serviceVersions,
 	status map[string]kubecmd.NodeStatus, rawAttestationConfig string,
 ) string {
 	builder := strings.Builder{}
@@ -176,6 +312,169 @@ func targetVersionsString(target kubecmd.NodeVersion) string {
 	return builder.String()
 }
 
+// nodeAttestationString creates the node attestation part of the output string.
+func nodeAttestationString(attestations map[string]nodeAttestationStatus) string {
+	builder := strings.Builder{}
+	builder.WriteString("Node attestation:\n")
+	for _, name := range sortedKeys(attestations) {
+		if attestations[name].Verified {
+			builder.WriteString(fmt.Sprintf("\t%s: verified\n", name))
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("\t%s: failed: %s\n", name, attestations[name].Error))
+	}
+	return builder.String()
+}
+
+// clusterStatus is the machine-readable representation of the cluster status.
+type clusterStatus struct {
+	SchemaVersion     int                     `json:"schemaVersion" yaml:"schemaVersion"`
+	TargetVersions    statusTargetVersions    `json:"targetVersions" yaml:"targetVersions"`
+	ServiceVersions   map[string]string       `json:"serviceVersions" yaml:"serviceVersions"`
+	Upgrade           statusUpgrade           `json:"upgrade" yaml:"upgrade"`
+	Nodes             []statusNode            `json:"nodes" yaml:"nodes"`
+	AttestationConfig statusAttestationConfig `json:"attestationConfig" yaml:"attestationConfig"`
+}
+
+type statusTargetVersions struct {
+	Image          string `json:"image" yaml:"image"`
+	ImageReference string `json:"imageReference" yaml:"imageReference"`
+	Kubernetes     string `json:"kubernetes" yaml:"kubernetes"`
+}
+
+type statusUpgrade struct {
+	// InProgress is true if the cluster is currently upgrading its Kubernetes version.
+	InProgress bool `json:"inProgress" yaml:"inProgress"`
+	// Status is a human-readable summary of the upgrade state.
+	Status string `json:"status" yaml:"status"`
+	// Conditions are the conditions reported by the NodeVersion resource.
+	Conditions []statusCondition `json:"conditions" yaml:"conditions"`
+	// UpToDateImages is the number of nodes running the target image.
+	UpToDateImages int `json:"upToDateImages" yaml:"upToDateImages"`
+	// UpToDateKubernetes is the number of nodes running the target Kubernetes version.
+	UpToDateKubernetes int `json:"upToDateKubernetes" yaml:"upToDateKubernetes"`
+	// TotalNodes is the number of nodes in the cluster.
+	TotalNodes int `json:"totalNodes" yaml:"totalNodes"`
+	// OutdatedNodes are nodes using an outdated image, as tracked by the node operator.
+	OutdatedNodes []string `json:"outdatedNodes" yaml:"outdatedNodes"`
+	// PendingNodes are nodes joining or leaving the cluster.
+	PendingNodes []string `json:"pendingNodes" yaml:"pendingNodes"`
+}
+
+type statusCondition struct {
+	Type               string    `json:"type" yaml:"type"`
+	Status             string    `json:"status" yaml:"status"`
+	Reason             string    `json:"reason" yaml:"reason"`
+	Message            string    `json:"message" yaml:"message"`
+	LastTransitionTime time.Time `json:"lastTransitionTime" yaml:"lastTransitionTime"`
+}
+
+type statusNode struct {
+	Name           string                 `json:"name" yaml:"name"`
+	Role           string                 `json:"role" yaml:"role"`
+	Ready          bool                   `json:"ready" yaml:"ready"`
+	InternalIP     string                 `json:"internalIP" yaml:"internalIP"`
+	KubeletVersion string                 `json:"kubeletVersion" yaml:"kubeletVersion"`
+	ImageVersion   string                 `json:"imageVersion" yaml:"imageVersion"`
+	UpToDate       bool                   `json:"upToDate" yaml:"upToDate"`
+	Attestation    *nodeAttestationStatus `json:"attestation,omitempty" yaml:"attestation,omitempty"`
+}
+
+type nodeAttestationStatus struct {
+	Verified bool   `json:"verified" yaml:"verified"`
+	Error    string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type statusAttestationConfig struct {
+	Variant string                `json:"variant" yaml:"variant"`
+	Config  config.AttestationCfg `json:"config" yaml:"config"`
+}
+
+// newClusterStatus bundles the received information into the machine-readable status.
+// attestations is nil if the nodes were not attested.
+func newClusterStatus(
+	nodeVersion kubecmd.NodeVersion, serviceVersions serviceVersions, status map[string]kubecmd.NodeStatus,
+	attestationConfig config.AttestationCfg, attestations map[string]nodeAttestationStatus,
+) clusterStatus {
+	out := clusterStatus{
+		SchemaVersion: statusSchemaVersion,
+		TargetVersions: statusTargetVersions{
+			Image:          nodeVersion.ImageVersion(),
+			ImageReference: nodeVersion.ImageReference(),
+			Kubernetes:     nodeVersion.KubernetesVersion(),
+		},
+		ServiceVersions: serviceVersions.Releases(),
+		Upgrade: statusUpgrade{
+			InProgress:    nodeVersion.ActiveUpgrade(),
+			Status:        nodeVersion.ClusterStatus(),
+			Conditions:    []statusCondition{},
+			TotalNodes:    len(status),
+			OutdatedNodes: append([]string{}, nodeVersion.OutdatedNodes()...),
+			PendingNodes:  append([]string{}, nodeVersion.PendingNodes()...),
+		},
+		Nodes: []statusNode{},
+		AttestationConfig: statusAttestationConfig{
+			Variant: attestationConfig.GetVariant().String(),
+			Config:  attestationConfig,
+		},
+	}
+	for _, condition := range nodeVersion.Conditions() {
+		out.Upgrade.Conditions = append(out.Upgrade.Conditions, statusCondition{
+			Type:               condition.Type,
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+
+	for _, name := range sortedKeys(status) {
+		node := status[name]
+		imageUpToDate := node.ImageVersion() == nodeVersion.ImageReference()
+		k8sUpToDate := node.KubeletVersion() == nodeVersion.KubernetesVersion()
+		if imageUpToDate {
+			out.Upgrade.UpToDateImages++
+		}
+		if k8sUpToDate {
+			out.Upgrade.UpToDateKubernetes++
+		}
+
+		role := "worker"
+		if node.ControlPlane() {
+			role = "control-plane"
+		}
+		statusNode := statusNode{
+			Name:           name,
+			Role:           role,
+			Ready:          node.Ready(),
+			InternalIP:     node.InternalIP(),
+			KubeletVersion: node.KubeletVersion(),
+			ImageVersion:   node.ImageVersion(),
+			UpToDate:       imageUpToDate && k8sUpToDate,
+		}
+		if attestation, ok := attestations[name]; ok {
+			statusNode.Attestation = &attestation
+		}
+		out.Nodes = append(out.Nodes, statusNode)
+	}
+
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type serviceVersions interface {
+	String() string
+	Releases() map[string]string
+}
+
 type kubeCmd interface {
 	ClusterStatus(ctx context.Context) (map[string]kubecmd.NodeStatus, error)
 	GetConstellationVersion(ctx context.Context) (kubecmd.NodeVersion, error)
@@ -9,7 +9,7 @@ package cmd
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"encoding/json"
 	"testing"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
@@ -19,10 +19,12 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
 	updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -287,6 +289,228 @@ func TestStatus(t *testing.T) {
 	}
 }
 
+func TestStatusMachineReadable(t *testing.T) {
+	nodeVersion, err := kubecmd.NewNodeVersion(updatev1alpha1.NodeVersion{
+		Spec: updatev1alpha1.NodeVersionSpec{
+			ImageVersion:             "v1.1.0",
+			ImageReference:           "v1.1.0",
+			KubernetesClusterVersion: "v1.2.3",
+		},
+		Status: updatev1alpha1.NodeVersionStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:    "Ready",
+					Status:  metav1.ConditionFalse,
+					Message: "Some node versions are out of date",
+				},
+			},
+			Outdated:                    []corev1.ObjectReference{{Name: "outdated"}},
+			Pending:                     []corev1.ObjectReference{{Name: "joining"}},
+			ActiveClusterVersionUpgrade: true,
+		},
+	})
+	require.NoError(t, err)
+	kubeClient := stubKubeClient{
+		status: map[string]kubecmd.NodeStatus{
+			"outdated": kubecmd.NewNodeStatus(corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "outdated",
+					Annotations: map[string]string{
+						"constellation.edgeless.systems/node-image": "v1.0.0",
+					},
+					Labels: map[string]string{
+						"node-role.kubernetes.io/control-plane": "",
+					},
+				},
+				Status: corev1.NodeStatus{
+					NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.2.3"},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+					Addresses:  []corev1.NodeAddress{{Type: corev1.NodeInternalIP, Address: "192.0.2.1"}},
+				},
+			}),
+			"uptodate": kubecmd.NewNodeStatus(corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "uptodate",
+					Annotations: map[string]string{
+						"constellation.edgeless.systems/node-image": "v1.1.0",
+					},
+				},
+				Status: corev1.NodeStatus{
+					NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: "v1.2.3"},
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+				},
+			}),
+		},
+		version: nodeVersion,
+		attestation: &config.QEMUVTPM{
+			Measurements: measurements.M{
+				15: measurements.WithAllBytes(0, measurements.Enforce, measurements.PCRMeasurementLength),
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		output          string
+		verify          bool
+		unmarshal       func([]byte, any) error
+		wantAttestation bool
+	}{
+		"json": {
+			output:    statusOutputJSON,
+			unmarshal: json.Unmarshal,
+		},
+		"yaml": {
+			output:    statusOutputYAML,
+			unmarshal: yaml.Unmarshal,
+		},
+		"json with node attestation": {
+			output:          statusOutputJSON,
+			verify:          true,
+			unmarshal:       json.Unmarshal,
+			wantAttestation: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			assert := assert.New(t)
+
+			cmd := NewStatusCmd()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetContext(context.Background())
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			cfg, err := createConfigWithAttestationVariant(cloudprovider.Azure, "", variant.AzureSEVSNP{})
+			require.NoError(err)
+			modifyConfigForAzureToPassValidate(cfg)
+			require.NoError(fileHandler.WriteYAML(constants.ConfigFilename, cfg))
+			require.NoError(defaultStateFile(cloudprovider.Azure).WriteToFile(fileHandler, constants.StateFilename))
+
+			s := statusCmd{
+				fileHandler:  fileHandler,
+				log:          logger.NewTest(t),
+				verifyClient: &stubVerifyClient{},
+				flags:        statusFlags{output: tc.output, verify: tc.verify},
+			}
+			require.NoError(s.status(cmd, stubGetVersions(versionsOutput), kubeClient, stubAttestationFetcher{}))
+
+			var status struct {
+				SchemaVersion  int `json:"schemaVersion" yaml:"schemaVersion"`
+				TargetVersions struct {
+					Image      string `json:"image" yaml:"image"`
+					Kubernetes string `json:"kubernetes" yaml:"kubernetes"`
+				} `json:"targetVersions" yaml:"targetVersions"`
+				ServiceVersions map[string]string `json:"serviceVersions" yaml:"serviceVersions"`
+				Upgrade         struct {
+					InProgress     bool     `json:"inProgress" yaml:"inProgress"`
+					UpToDateImages int      `json:"upToDateImages" yaml:"upToDateImages"`
+					TotalNodes     int      `json:"totalNodes" yaml:"totalNodes"`
+					OutdatedNodes  []string `json:"outdatedNodes" yaml:"outdatedNodes"`
+					PendingNodes   []string `json:"pendingNodes" yaml:"pendingNodes"`
+				} `json:"upgrade" yaml:"upgrade"`
+				Nodes []struct {
+					Name        string                 `json:"name" yaml:"name"`
+					Role        string                 `json:"role" yaml:"role"`
+					Ready       bool                   `json:"ready" yaml:"ready"`
+					UpToDate    bool                   `json:"upToDate" yaml:"upToDate"`
+					Attestation *nodeAttestationStatus `json:"attestation" yaml:"attestation"`
+				} `json:"nodes" yaml:"nodes"`
+				AttestationConfig struct {
+					Variant string `json:"variant" yaml:"variant"`
+				} `json:"attestationConfig" yaml:"attestationConfig"`
+			}
+			require.NoError(tc.unmarshal(out.Bytes(), &status))
+
+			assert.Equal(statusSchemaVersion, status.SchemaVersion)
+			assert.Equal("v1.1.0", status.TargetVersions.Image)
+			assert.Equal("v1.2.3", status.TargetVersions.Kubernetes)
+			assert.Equal(map[string]string{"cilium": "v1.0.0"}, status.ServiceVersions)
+			assert.True(status.Upgrade.InProgress)
+			assert.Equal(1, status.Upgrade.UpToDateImages)
+			assert.Equal(2, status.Upgrade.TotalNodes)
+			assert.Equal([]string{"outdated"}, status.Upgrade.OutdatedNodes)
+			assert.Equal([]string{"joining"}, status.Upgrade.PendingNodes)
+			assert.Equal(variant.QEMUVTPM{}.String(), status.AttestationConfig.Variant)
+
+			require.Len(status.Nodes, 2)
+			assert.Equal("outdated", status.Nodes[0].Name)
+			assert.Equal("control-plane", status.Nodes[0].Role)
+			assert.True(status.Nodes[0].Ready)
+			assert.False(status.Nodes[0].UpToDate)
+			assert.Equal("uptodate", status.Nodes[1].Name)
+			assert.Equal("worker", status.Nodes[1].Role)
+			assert.False(status.Nodes[1].Ready)
+			assert.True(status.Nodes[1].UpToDate)
+
+			if !tc.wantAttestation {
+				assert.Nil(status.Nodes[0].Attestation)
+				assert.Nil(status.Nodes[1].Attestation)
+				return
+			}
+			require.NotNil(status.Nodes[0].Attestation)
+			assert.True(status.Nodes[0].Attestation.Verified)
+			// the second node has no IP address
+			require.NotNil(status.Nodes[1].Attestation)
+			assert.False(status.Nodes[1].Attestation.Verified)
+			assert.NotEmpty(status.Nodes[1].Attestation.Error)
+		})
+	}
+}
+
+func TestParseStatusFlags(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		wantOutput string
+		wantVerify bool
+		wantErr    bool
+	}{
+		"default": {
+			wantOutput: statusOutputText,
+		},
+		"yaml": {
+			args:       []string{"-o", "yaml"},
+			wantOutput: statusOutputYAML,
+		},
+		"json with verify": {
+			args:       []string{"--output", "json", "--verify"},
+			wantOutput: statusOutputJSON,
+			wantVerify: true,
+		},
+		"invalid output": {
+			args:    []string{"-o", "xml"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cmd := NewStatusCmd()
+			cmd.Flags().String("workspace", "", "")
+			cmd.Flags().Bool("force", false, "")
+			cmd.Flags().String("tf-log", "NONE", "")
+			cmd.Flags().String("profile", "", "")
+			cmd.Flags().Bool("debug", false, "")
+			require.NoError(cmd.Flags().Parse(tc.args))
+
+			var flags statusFlags
+			err := flags.parse(cmd.Flags())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.wantOutput, flags.output)
+			assert.Equal(tc.wantVerify, flags.verify)
+		})
+	}
+}
+
 func modifyConfigForAzureToPassValidate(c *config.Config) {
 	c.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
 	c.Image = constants.BinaryVersion().String()
@@ -339,16 +563,21 @@ func (s stubKubeClient) GetClusterAttestationConfig(_ context.Context, _ variant
 	return s.attestation, s.attestationErr
 }
 
-func stubGetVersions(output string) func() (fmt.Stringer, error) {
-	return func() (fmt.Stringer, error) {
-		return stubServiceVersions{output}, nil
+func stubGetVersions(output string) func() (serviceVersions, error) {
+	return func() (serviceVersions, error) {
+		return stubServiceVersions{output: output, releases: map[string]string{"cilium": "v1.0.0"}}, nil
 	}
 }
 
 type stubServiceVersions struct {
-	output string
+	output   string
+	releases map[string]string
 }
 
 func (s stubServiceVersions) String() string {
 	return s.output
 }
+
+func (s stubServiceVersions) Releases() map[string]string {
+	return s.releases
+}
@@ -15,7 +15,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 )
@@ -95,8 +96,22 @@ func (t *terminateCmd) terminate(cmd *cobra.Command, terminator cloudTerminator,
 		}
 	}
 
+	backendConf, err := config.ReadStateBackend(t.fileHandler, constants.ConfigFilename, t.flags.profile)
+	if err != nil {
+		return fmt.Errorf("reading state backend configuration: %w", err)
+	}
+	stateBackend, err := newStateBackend(cmd.Context(), backendConf, t.fileHandler)
+	if err != nil {
+		return err
+	}
+	lockID, err := lockState(cmd, stateBackend, "terminate")
+	if err != nil {
+		return err
+	}
+	defer unlockState(cmd, stateBackend, lockID)
+
 	spinner.Start("Terminating", false)
-	err := terminator.Terminate(cmd.Context(), constants.TerraformWorkingDir, t.flags.tfLogLevel)
+	err = terminator.Terminate(cmd.Context(), constants.TerraformWorkingDir, t.flags.tfLogLevel)
 	spinner.Stop()
 	if err != nil {
 		return fmt.Errorf("terminating Constellation cluster: %w", err)
@@ -109,8 +124,8 @@ func (t *terminateCmd) terminate(cmd *cobra.Command, terminator cloudTerminator,
 		removeErr = errors.Join(err, fmt.Errorf("failed to remove file: '%s', please remove it manually", t.flags.pathPrefixer.PrefixPrintablePath(constants.AdminConfFilename)))
 	}
 
-	if err := t.fileHandler.Remove(constants.StateFilename); err != nil && !errors.Is(err, fs.ErrNotExist) {
-		removeErr = errors.Join(err, fmt.Errorf("failed to remove file: '%s', please remove it manually", t.flags.pathPrefixer.PrefixPrintablePath(constants.StateFilename)))
+	if err := stateBackend.Delete(cmd.Context()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		removeErr = errors.Join(err, fmt.Errorf("failed to remove state file: '%s', please remove it manually", t.flags.pathPrefixer.PrefixPrintablePath(constants.StateFilename)))
 	}
 
 	return removeErr
@@ -11,9 +11,9 @@ import (
 	"context"
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
@@ -24,6 +24,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -280,6 +281,9 @@ func TestUpgradeApply(t *testing.T) {
 }
 
 type stubKubernetesUpgrader struct {
+	nodeVersion                    kubecmd.NodeVersion
+	getNodeVersionErr              error
+	missingCertSANs                []string
 	nodeVersionErr                 error
 	kubernetesVersionErr           error
 	currentConfig                  config.AttestationCfg
@@ -290,14 +294,17 @@ type stubKubernetesUpgrader struct {
 	backupCRDsCalled               bool
 	backupCRsErr                   error
 	backupCRsCalled                bool
+	controlPlaneNodes              []constellation.ControlPlaneNode
+	waitControlPlaneErr            error
+	calledControlPlaneWait         bool
 }
 
-func (u *stubKubernetesUpgrader) BackupCRDs(_ context.Context, _ file.Handler, _ string) ([]apiextensionsv1.CustomResourceDefinition, error) {
+func (u *stubKubernetesUpgrader) BackupCRDs(_ context.Context, _ afero.Fs, _ string) ([]apiextensionsv1.CustomResourceDefinition, error) {
 	u.backupCRDsCalled = true
 	return []apiextensionsv1.CustomResourceDefinition{}, u.backupCRDsErr
 }
 
-func (u *stubKubernetesUpgrader) BackupCRs(_ context.Context, _ file.Handler, _ []apiextensionsv1.CustomResourceDefinition, _ string) error {
+func (u *stubKubernetesUpgrader) BackupCRs(_ context.Context, _ afero.Fs, _ []apiextensionsv1.CustomResourceDefinition, _ string) error {
 	u.backupCRsCalled = true
 	return u.backupCRsErr
 }
@@ -324,6 +331,22 @@ func (u *stubKubernetesUpgrader) ExtendClusterConfigCertSANs(_ context.Context,
 	return nil
 }
 
+func (u *stubKubernetesUpgrader) MissingClusterConfigCertSANs(_ context.Context, _, _ string, _ []string) ([]string, error) {
+	return u.missingCertSANs, nil
+}
+
+func (u *stubKubernetesUpgrader) GetConstellationVersion(_ context.Context) (kubecmd.NodeVersion, error) {
+	return u.nodeVersion, u.getNodeVersionErr
+}
+
+func (u *stubKubernetesUpgrader) WaitForControlPlaneNodes(_ context.Context, _ int, progress func(constellation.ControlPlaneNode)) ([]constellation.ControlPlaneNode, error) {
+	u.calledControlPlaneWait = true
+	for _, node := range u.controlPlaneNodes {
+		progress(node)
+	}
+	return u.controlPlaneNodes, u.waitControlPlaneErr
+}
+
 type stubTerraformUpgrader struct {
 	terraformDiff        bool
 	planTerraformErr     error
@@ -335,6 +358,10 @@ func (u stubTerraformUpgrader) Plan(_ context.Context, _ *config.Config) (bool,
 	return u.terraformDiff, u.planTerraformErr
 }
 
+func (u stubTerraformUpgrader) PlanDiff(_ context.Context, _ *config.Config) (bool, string, error) {
+	return u.terraformDiff, "", u.planTerraformErr
+}
+
 func (u stubTerraformUpgrader) Apply(_ context.Context, _ cloudprovider.Provider, _ cloudcmd.RollbackBehavior) (state.Infrastructure, error) {
 	return state.Infrastructure{}, u.applyTerraformErr
 }
@@ -356,6 +383,11 @@ func (m *mockTerraformUpgrader) Plan(ctx context.Context, conf *config.Config) (
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockTerraformUpgrader) PlanDiff(ctx context.Context, conf *config.Config) (bool, string, error) {
+	args := m.Called(ctx, conf)
+	return args.Bool(0), args.String(1), args.Error(2)
+}
+
 func (m *mockTerraformUpgrader) Apply(ctx context.Context, provider cloudprovider.Provider, rollback cloudcmd.RollbackBehavior) (state.Infrastructure, error) {
 	args := m.Called(ctx, provider, rollback)
 	return args.Get(0).(state.Infrastructure), args.Error(1)
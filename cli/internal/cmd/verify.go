@@ -7,12 +7,8 @@ SPDX-License-Identifier: AGPL-3.0-only
 package cmd
 
 import (
-	"bytes"
 	"context"
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,7 +24,6 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/snp"
-	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/config"
@@ -36,13 +31,12 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
 	"github.com/edgelesssys/constellation/v2/internal/file"
-	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
 	"github.com/edgelesssys/constellation/v2/internal/verify"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	"github.com/edgelesssys/constellation/v2/verify/verifyproto"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"google.golang.org/grpc"
 )
 
 // NewVerifyCmd returns a new cobra.Command for the verify command.
@@ -104,10 +98,7 @@ func runVerify(cmd *cobra.Command, _ []string) error {
 	defer log.Sync()
 
 	fileHandler := file.NewHandler(afero.NewOsFs())
-	verifyClient := &constellationVerifier{
-		dialer: dialer.New(nil, nil, &net.Dialer{}),
-		log:    log,
-	}
+	verifyClient := newConstellationVerifier(log)
 	formatterFactory := func(output string, provider cloudprovider.Provider, log debugLog) (attestationDocFormatter, error) {
 		if output == "json" && (provider != cloudprovider.Azure && provider != cloudprovider.AWS) {
 			return nil, errors.New("json output is only supported for Azure and AWS")
@@ -139,7 +130,7 @@ type formatterFactory func(output string, provider cloudprovider.Provider, log d
 
 func (c *verifyCmd) verify(cmd *cobra.Command, verifyClient verifyClient, factory formatterFactory, configFetcher attestationconfigapi.Fetcher) error {
 	c.log.Debugf("Loading configuration file from %q", c.flags.pathPrefixer.PrefixPrintablePath(constants.ConfigFilename))
-	conf, err := config.New(c.fileHandler, constants.ConfigFilename, configFetcher, c.flags.force)
+	conf, err := config.New(c.fileHandler, constants.ConfigFilename, c.flags.profile, configFetcher, c.flags.force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -148,7 +139,7 @@ func (c *verifyCmd) verify(cmd *cobra.Command, verifyClient verifyClient, factor
 		return fmt.Errorf("loading config file: %w", err)
 	}
 
-	stateFile, err := state.ReadFromFile(c.fileHandler, constants.StateFilename)
+	stateFile, err := readStateFromBackend(cmd.Context(), conf.StateBackend, c.fileHandler)
 	if err != nil {
 		return fmt.Errorf("reading state file: %w", err)
 	}
@@ -173,7 +164,7 @@ func (c *verifyCmd) verify(cmd *cobra.Command, verifyClient verifyClient, factor
 
 	c.log.Debugf("Updating expected PCRs")
 	attConfig := conf.GetAttestationConfig()
-	if err := updateInitMeasurements(attConfig, ownerID, clusterID); err != nil {
+	if err := constellation.UpdateInitMeasurements(attConfig, ownerID, clusterID); err != nil {
 		return fmt.Errorf("updating expected PCRs: %w", err)
 	}
 
@@ -377,51 +368,34 @@ type attestationDoc struct {
 	UserData     string `json:"UserData"`
 }
 
+func newConstellationVerifier(log debugLog) *constellationVerifier {
+	return &constellationVerifier{
+		applier: constellation.NewApplier(log, nil, constellation.ApplyContextCLI, constellation.DefaultDialer),
+	}
+}
+
 type constellationVerifier struct {
-	dialer grpcInsecureDialer
-	log    debugLog
+	applier *constellation.Applier
 }
 
 // Verify retrieves an attestation statement from the Constellation and verifies it using the validator.
 func (v *constellationVerifier) Verify(
 	ctx context.Context, endpoint string, req *verifyproto.GetAttestationRequest, validator atls.Validator,
 ) (string, error) {
-	v.log.Debugf("Dialing endpoint: %q", endpoint)
-	conn, err := v.dialer.DialInsecure(ctx, endpoint)
-	if err != nil {
-		return "", fmt.Errorf("dialing init server: %w", err)
-	}
-	defer conn.Close()
-
-	client := verifyproto.NewAPIClient(conn)
-
-	v.log.Debugf("Sending attestation request")
-	resp, err := client.GetAttestation(ctx, req)
-	if err != nil {
-		return "", fmt.Errorf("getting attestation: %w", err)
-	}
-
-	v.log.Debugf("Verifying attestation")
-	signedData, err := validator.Validate(ctx, resp.Attestation, req.Nonce)
+	out, err := v.applier.Verify(ctx, validator, constellation.VerifyOptions{
+		Endpoint: endpoint,
+		Nonce:    req.Nonce,
+	})
 	if err != nil {
-		return "", fmt.Errorf("validating attestation: %w", err)
+		return "", err
 	}
-
-	if !bytes.Equal(signedData, []byte(constants.ConstellationVerifyServiceUserData)) {
-		return "", errors.New("signed data in attestation does not match expected user data")
-	}
-
-	return string(resp.Attestation), nil
+	return string(out.AttestationDocument), nil
 }
 
 type verifyClient interface {
 	Verify(ctx context.Context, endpoint string, req *verifyproto.GetAttestationRequest, validator atls.Validator) (string, error)
 }
 
-type grpcInsecureDialer interface {
-	DialInsecure(ctx context.Context, endpoint string) (conn *grpc.ClientConn, err error)
-}
-
 // writeIndentfln writes a formatted string to the builder with the given indentation level
 // and a newline at the end.
 func writeIndentfln(b *strings.Builder, indentLvl int, format string, args ...any) {
@@ -460,83 +434,3 @@ func addPortIfMissing(endpoint string, defaultPort int) (string, error) {
 
 	return "", err
 }
-
-// UpdateInitMeasurements sets the owner and cluster measurement values in the attestation config depending on the
-// attestation variant.
-func updateInitMeasurements(config config.AttestationCfg, ownerID, clusterID string) error {
-	m := config.GetMeasurements()
-
-	switch config.GetVariant() {
-	case variant.AWSNitroTPM{}, variant.AWSSEVSNP{}, variant.AzureTrustedLaunch{}, variant.AzureSEVSNP{}, variant.GCPSEVES{}, variant.QEMUVTPM{}:
-		if err := updateMeasurementTPM(m, uint32(measurements.PCRIndexOwnerID), ownerID); err != nil {
-			return err
-		}
-		return updateMeasurementTPM(m, uint32(measurements.PCRIndexClusterID), clusterID)
-	case variant.QEMUTDX{}:
-		// Measuring ownerID is currently not implemented for Constellation
-		// Since adding support for measuring ownerID to TDX would require additional code changes,
-		// the current implementation does not support it, but can be changed if we decide to add support in the future
-		return updateMeasurementTDX(m, uint32(measurements.TDXIndexClusterID), clusterID)
-	default:
-		return errors.New("selecting attestation variant: unknown attestation variant")
-	}
-}
-
-// updateMeasurementTDX updates the TDX measurement value in the attestation config for the given measurement index.
-func updateMeasurementTDX(m measurements.M, measurementIdx uint32, encoded string) error {
-	if encoded == "" {
-		delete(m, measurementIdx)
-		return nil
-	}
-	decoded, err := decodeMeasurement(encoded)
-	if err != nil {
-		return err
-	}
-
-	// new_measurement_value := hash(old_measurement_value || data_to_extend)
-	// Since we use the DG.MR.RTMR.EXTEND call to extend the register, data_to_extend is the hash of our input
-	hashedInput := sha512.Sum384(decoded)
-	oldExpected := m[measurementIdx].Expected
-	expectedMeasurementSum := sha512.Sum384(append(oldExpected[:], hashedInput[:]...))
-	m[measurementIdx] = measurements.Measurement{
-		Expected:      expectedMeasurementSum[:],
-		ValidationOpt: m[measurementIdx].ValidationOpt,
-	}
-	return nil
-}
-
-// updateMeasurementTPM updates the TPM measurement value in the attestation config for the given measurement index.
-func updateMeasurementTPM(m measurements.M, measurementIdx uint32, encoded string) error {
-	if encoded == "" {
-		delete(m, measurementIdx)
-		return nil
-	}
-	decoded, err := decodeMeasurement(encoded)
-	if err != nil {
-		return err
-	}
-
-	// new_pcr_value := hash(old_pcr_value || data_to_extend)
-	// Since we use the TPM2_PCR_Event call to extend the PCR, data_to_extend is the hash of our input
-	hashedInput := sha256.Sum256(decoded)
-	oldExpected := m[measurementIdx].Expected
-	expectedMeasurement := sha256.Sum256(append(oldExpected[:], hashedInput[:]...))
-	m[measurementIdx] = measurements.Measurement{
-		Expected:      expectedMeasurement[:],
-		ValidationOpt: m[measurementIdx].ValidationOpt,
-	}
-	return nil
-}
-
-// decodeMeasurement is a utility function that decodes the given string as hex or base64.
-func decodeMeasurement(encoded string) ([]byte, error) {
-	decoded, err := hex.DecodeString(encoded)
-	if err != nil {
-		hexErr := err
-		decoded, err = base64.StdEncoding.DecodeString(encoded)
-		if err != nil {
-			return nil, fmt.Errorf("input [%s] could neither be hex decoded (%w) nor base64 decoded (%w)", encoded, hexErr, err)
-		}
-	}
-	return decoded, nil
-}
@@ -18,6 +18,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/deploy"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/diagnostics"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/info"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/logcollector"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/metadata"
@@ -105,7 +106,8 @@ func main() {
 	download := deploy.New(log.Named("download"), &net.Dialer{}, serviceManager, filetransferer, infoMap)
 
 	sched := metadata.NewScheduler(log.Named("scheduler"), fetcher, download)
-	serv := server.New(log.Named("server"), serviceManager, filetransferer, infoMap)
+	diag := diagnostics.New(log.Named("diagnostics"))
+	serv := server.New(log.Named("server"), serviceManager, filetransferer, download, diag, infoMap)
 
 	writeDebugBanner(log)
 
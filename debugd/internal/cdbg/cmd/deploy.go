@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/accesstoken"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/logcollector"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/filetransfer"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/filetransfer/streamer"
@@ -30,10 +31,17 @@ import (
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
-const deployEndpointTimeout = 20 * time.Minute
+const (
+	deployEndpointTimeout = 20 * time.Minute
+	// uploadAttempts is the number of times an upload is attempted. Failed uploads are resumed.
+	uploadAttempts     = 5
+	uploadRetryBackoff = 5 * time.Second
+)
 
 func newDeployCmd() *cobra.Command {
 	deployCmd := &cobra.Command{
@@ -42,7 +50,11 @@ func newDeployCmd() *cobra.Command {
 		Long: `Deploys a self-compiled bootstrapper binary on the current constellation.
 	Uses config provided by --config and reads constellation config from its default location.
 	If required, you can override the IP addresses that are used for a deployment by specifying "--ips" and a list of IP addresses.
-	Specifying --bootstrapper will upload the bootstrapper from the specified path.`,
+	Specifying --bootstrapper will upload the bootstrapper from the specified path.
+	Files that are already present on an instance are skipped and interrupted uploads are resumed.
+	If multiple IP addresses are given, the files are uploaded to the first instance only and
+	the other instances fetch them from there, unless --direct-upload is set.
+	On first use, an access token for "cdbg logs" and "cdbg exec" is stored in the workspace.`,
 		RunE:    runDeploy,
 		Example: "cdbg deploy\ncdbg deploy -C /path/to/workspace --bindir $(pwd)\ncdbg deploy -C /path/to/workspace --bootstrapper /path/to/bootstrapper --ips 192.0.2.1,192.0.2.2,192.0.2.3",
 	}
@@ -50,6 +62,7 @@ func newDeployCmd() *cobra.Command {
 	deployCmd.Flags().String("bindir", "", "override the base path that binaries are read from")
 	deployCmd.Flags().String("bootstrapper", "bootstrapper", "override the path to the bootstrapper binary uploaded to instances")
 	deployCmd.Flags().String("upgrade-agent", "upgrade-agent", "override the path to the upgrade-agent binary uploaded to instances")
+	deployCmd.Flags().Bool("direct-upload", false, "upload the files to every instance instead of letting instances fetch them from the first one")
 	deployCmd.Flags().StringToString("info", nil, "additional info to be passed to the debugd, in the form --info key1=value1,key2=value2")
 	deployCmd.Flags().Int("verbosity", 0, logger.CmdLineVerbosityDescription)
 	return deployCmd
@@ -70,7 +83,7 @@ func runDeploy(cmd *cobra.Command, _ []string) error {
 	fileHandler := file.NewHandler(fs)
 	streamer := streamer.New(fs)
 	transfer := filetransfer.New(log, streamer, filetransfer.ShowProgress)
-	constellationConfig, err := config.New(fileHandler, constants.ConfigFilename, attestationconfigapi.NewFetcher(), force)
+	constellationConfig, err := config.New(fileHandler, constants.ConfigFilename, "", attestationconfigapi.NewFetcher(), force)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		cmd.PrintErrln(configValidationErr.LongMessage())
@@ -108,18 +121,15 @@ func deploy(cmd *cobra.Command, fileHandler file.Handler, constellationConfig *c
 		log.Infof("If you create the cluster with a debug image, you should also set debugCluster to true.")
 	}
 
-	ips, err := cmd.Flags().GetStringSlice("ips")
+	ips, err := deployIPs(cmd, fileHandler)
 	if err != nil {
 		return err
 	}
-	if len(ips) == 0 {
-		var stateFile clusterStateFile
-		if err := fileHandler.ReadYAML(constants.StateFilename, &stateFile); err != nil {
-			return fmt.Errorf("reading cluster state file: %w", err)
-		}
-		ips = []string{stateFile.Infrastructure.ClusterEndpoint}
-	}
 
+	directUpload, err := cmd.Flags().GetBool("direct-upload")
+	if err != nil {
+		return err
+	}
 	info, err := cmd.Flags().GetStringToString("info")
 	if err != nil {
 		return err
@@ -128,6 +138,16 @@ func deploy(cmd *cobra.Command, fileHandler file.Handler, constellationConfig *c
 		return err
 	}
 
+	// only the digest of the access token is shared with the instances
+	token, err := loadOrCreateAccessToken(fileHandler)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		info = map[string]string{}
+	}
+	info[accesstoken.InfoKey] = accesstoken.Digest(token)
+
 	files := []filetransfer.FileStat{
 		{
 			SourcePath:          prependBinDir(binDir, bootstrapperPath),
@@ -143,9 +163,11 @@ func deploy(cmd *cobra.Command, fileHandler file.Handler, constellationConfig *c
 		},
 	}
 
+	var peer string
 	for _, ip := range ips {
 		input := deployOnEndpointInput{
 			debugdEndpoint: ip,
+			peer:           peer,
 			infos:          info,
 			files:          files,
 			transfer:       transfer,
@@ -154,6 +176,9 @@ func deploy(cmd *cobra.Command, fileHandler file.Handler, constellationConfig *c
 		if err := deployOnEndpoint(cmd.Context(), input); err != nil {
 			return fmt.Errorf("deploying endpoint on %q: %w", ip, err)
 		}
+		if peer == "" && !directUpload {
+			peer = ip
+		}
 	}
 
 	return nil
@@ -168,6 +193,7 @@ func prependBinDir(bindDir, binary string) string {
 
 type deployOnEndpointInput struct {
 	debugdEndpoint string
+	peer           string // instance that already received the files, if set the files are fetched from there
 	files          []filetransfer.FileStat
 	infos          map[string]string
 	transfer       fileTransferer
@@ -191,6 +217,14 @@ func deployOnEndpoint(ctx context.Context, in deployOnEndpointInput) error {
 		return fmt.Errorf("sending info: %w", err)
 	}
 
+	if in.peer != "" {
+		err := fetchFiles(ctx, client, in)
+		if err == nil {
+			return nil
+		}
+		in.log.Warnf("Fetching files from %v failed, uploading them instead: %v", in.peer, err)
+	}
+
 	if err := uploadFiles(ctx, client, in); err != nil {
 		return fmt.Errorf("uploading bootstrapper: %w", err)
 	}
@@ -247,16 +281,60 @@ func setInfo(ctx context.Context, log *logger.Logger, client pb.DebugdClient, in
 	return nil
 }
 
+// fetchFiles makes the debugd instance fetch the files from the peer that already received them.
+func fetchFiles(ctx context.Context, client pb.DebugdClient, in deployOnEndpointInput) error {
+	in.log.Infof("Fetching files from %v", in.peer)
+
+	resp, err := client.FetchFiles(ctx, &pb.FetchFilesRequest{Peer: in.peer}, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("fetching files: %w", err)
+	}
+	switch resp.Status {
+	case pb.FetchFilesStatus_FETCH_FILES_SUCCESS:
+		in.log.Infof("Fetch successful")
+		return nil
+	case pb.FetchFilesStatus_FETCH_FILES_ALREADY_STARTED:
+		return fmt.Errorf("receiving files already started on %v", in.debugdEndpoint)
+	default:
+		return fmt.Errorf("fetching files failed with status %v", resp.Status)
+	}
+}
+
+// uploadFiles uploads the files to the debugd instance.
+// If an upload fails, it is retried and resumed where the instance left off.
 func uploadFiles(ctx context.Context, client pb.DebugdClient, in deployOnEndpointInput) error {
-	in.log.Infof("Uploading files")
+	in.transfer.SetFiles(in.files)
 
+	var err error
+	for attempt := 1; attempt <= uploadAttempts; attempt++ {
+		if attempt > 1 {
+			in.log.Warnf("Uploading files to %v failed, retrying: %v", in.debugdEndpoint, err)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(uploadRetryBackoff):
+			}
+		}
+		if err = uploadFilesOnce(ctx, client, in); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func uploadFilesOnce(ctx context.Context, client pb.DebugdClient, in deployOnEndpointInput) error {
+	opts, err := getSendOptions(ctx, client, in.files)
+	if err != nil {
+		return err
+	}
+
+	in.log.Infof("Uploading files")
 	stream, err := client.UploadFiles(ctx, grpc.WaitForReady(true))
 	if err != nil {
 		return fmt.Errorf("starting bootstrapper upload to instance %v: %w", in.debugdEndpoint, err)
 	}
 
-	in.transfer.SetFiles(in.files)
-	if err := in.transfer.SendFiles(stream); err != nil {
+	if err := in.transfer.SendFiles(stream, opts); err != nil {
 		return fmt.Errorf("sending files to %v: %w", in.debugdEndpoint, err)
 	}
 
@@ -280,8 +358,30 @@ func uploadFiles(ctx context.Context, client pb.DebugdClient, in deployOnEndpoin
 	return nil
 }
 
+// getSendOptions returns the options for sending files to the debugd instance,
+// depending on the files the instance already has.
+func getSendOptions(ctx context.Context, client pb.DebugdClient, files []filetransfer.FileStat) (filetransfer.SendOptions, error) {
+	targetPaths := make([]string, 0, len(files))
+	for _, file := range files {
+		targetPaths = append(targetPaths, file.TargetPath)
+	}
+
+	resp, err := client.GetFileStates(ctx, &pb.GetFileStatesRequest{TargetPaths: targetPaths}, grpc.WaitForReady(true))
+	if status.Code(err) == codes.Unimplemented {
+		// older debugd versions don't support skipping files, resuming or compression
+		return filetransfer.SendOptions{}, nil
+	}
+	if err != nil {
+		return filetransfer.SendOptions{}, fmt.Errorf("getting file states: %w", err)
+	}
+	return filetransfer.SendOptions{
+		ReceiverStates: filetransfer.FileStatesFromProto(resp.States),
+		Compression:    pb.Compression_COMPRESSION_ZSTD,
+	}, nil
+}
+
 type fileTransferer interface {
-	SendFiles(stream filetransfer.SendFilesStream) error
+	SendFiles(stream filetransfer.SendFilesStream, opts filetransfer.SendOptions) error
 	SetFiles(files []filetransfer.FileStat)
 }
 
@@ -0,0 +1,110 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/diagnostics"
+	pb "github.com/edgelesssys/constellation/v2/debugd/service"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newExecCmd() *cobra.Command {
+	execCmd := &cobra.Command{
+		Use:   "exec -- COMMAND [ARGS...]",
+		Short: "Run a diagnostic command on all instances",
+		Long: `Runs a diagnostic command on all instances running debugd and prints the output of each instance.
+	The instances are discovered through the Kubernetes API, using the kubeconfig in the workspace.
+	Only the following commands are allowed:
+	  ` + strings.Join(diagnostics.AllowedCommands(), "\n	  ") + `
+	Requires the access token created by "cdbg deploy" in the workspace.`,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runExec,
+		Example: "cdbg exec -- cryptsetup status state\ncdbg exec --ips 192.0.2.1 -- tpm2_pcrread sha256:all\ncdbg exec -- crictl ps -a",
+	}
+	execCmd.Flags().StringSlice("ips", nil, "override the ips of the instances (defaults to all nodes of the cluster, or the cluster endpoint if the cluster isn't initialized)")
+	execCmd.Flags().Int("verbosity", 0, logger.CmdLineVerbosityDescription)
+	return execCmd
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	verbosity, err := cmd.Flags().GetInt("verbosity")
+	if err != nil {
+		return err
+	}
+	log := logger.New(logger.PlainLog, logger.VerbosityFromInt(verbosity))
+	fileHandler := file.NewHandler(afero.NewOsFs())
+
+	ips, err := instanceIPs(cmd.Context(), cmd, fileHandler, log)
+	if err != nil {
+		return err
+	}
+	token, err := loadAccessToken(fileHandler)
+	if err != nil {
+		return err
+	}
+
+	var mux sync.Mutex
+	return onInstances(cmd.Context(), ips, token, log, func(ctx context.Context, ip string, client pb.DebugdClient, creds grpc.CallOption) error {
+		var output bytes.Buffer
+		exitCode, err := execOnInstance(ctx, client, args, creds, &output)
+
+		// print the output of each instance as a whole, so outputs of different instances don't get mixed
+		mux.Lock()
+		defer mux.Unlock()
+		cmd.Printf("=== %s ===\n", ip)
+		cmd.Print(output.String())
+		if err != nil {
+			return err
+		}
+		cmd.Printf("=== %s exited with code %d ===\n", ip, exitCode)
+		if exitCode != 0 {
+			return fmt.Errorf("command exited with code %d", exitCode)
+		}
+		return nil
+	})
+}
+
+// execOnInstance runs the command on an instance, writes stdout and stderr to out and returns the exit code.
+func execOnInstance(ctx context.Context, client pb.DebugdClient, command []string, creds grpc.CallOption, out io.Writer) (int32, error) {
+	stream, err := client.Exec(ctx, &pb.ExecRequest{Command: command}, creds, grpc.WaitForReady(true))
+	if err != nil {
+		return 0, fmt.Errorf("executing command: %w", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return 0, errors.New("command output ended without exit code")
+		}
+		if err != nil {
+			return 0, fmt.Errorf("executing command: %w", err)
+		}
+
+		switch kind := resp.Kind.(type) {
+		case *pb.ExecResponse_Stdout:
+			_, err = out.Write(kind.Stdout)
+		case *pb.ExecResponse_Stderr:
+			_, err = out.Write(kind.Stderr)
+		case *pb.ExecResponse_ExitCode:
+			return kind.ExitCode, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("writing output: %w", err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pb "github.com/edgelesssys/constellation/v2/debugd/service"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+func newLogsCmd() *cobra.Command {
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the journal of all instances",
+		Long: `Shows the systemd journal of all instances running debugd.
+	The instances are discovered through the Kubernetes API, using the kubeconfig in the workspace.
+	Lines are prefixed with the IP of the instance they originate from.
+	Specifying --bundle downloads a diagnostic bundle of each instance instead, containing the journal of the current boot
+	and the output of all diagnostic commands available through "cdbg exec".
+	Requires the access token created by "cdbg deploy" in the workspace.`,
+		Args:    cobra.NoArgs,
+		RunE:    runLogs,
+		Example: "cdbg logs -u bootstrapper.service -n 100\ncdbg logs -f -u kubelet --ips 192.0.2.1,192.0.2.2\ncdbg logs --bundle ./diagnostics",
+	}
+	logsCmd.Flags().StringSlice("ips", nil, "override the ips of the instances (defaults to all nodes of the cluster, or the cluster endpoint if the cluster isn't initialized)")
+	logsCmd.Flags().StringSliceP("unit", "u", nil, "show the journal of the given systemd units only")
+	logsCmd.Flags().Uint32P("lines", "n", 0, "show the given number of most recent lines only")
+	logsCmd.Flags().BoolP("follow", "f", false, "keep showing new journal entries")
+	logsCmd.Flags().String("bundle", "", "download a diagnostic bundle of each instance to the given directory")
+	logsCmd.Flags().Int("verbosity", 0, logger.CmdLineVerbosityDescription)
+	return logsCmd
+}
+
+func runLogs(cmd *cobra.Command, _ []string) error {
+	verbosity, err := cmd.Flags().GetInt("verbosity")
+	if err != nil {
+		return err
+	}
+	log := logger.New(logger.PlainLog, logger.VerbosityFromInt(verbosity))
+	fs := afero.NewOsFs()
+	fileHandler := file.NewHandler(fs)
+
+	ips, err := instanceIPs(cmd.Context(), cmd, fileHandler, log)
+	if err != nil {
+		return err
+	}
+	token, err := loadAccessToken(fileHandler)
+	if err != nil {
+		return err
+	}
+
+	bundleDir, err := cmd.Flags().GetString("bundle")
+	if err != nil {
+		return err
+	}
+	if bundleDir != "" {
+		if err := fs.MkdirAll(bundleDir, 0o700); err != nil {
+			return fmt.Errorf("creating bundle directory: %w", err)
+		}
+		return onInstances(cmd.Context(), ips, token, log, func(ctx context.Context, ip string, client pb.DebugdClient, creds grpc.CallOption) error {
+			path := filepath.Join(bundleDir, fmt.Sprintf("diagnostics-%s.tar.gz", ip))
+			if err := downloadBundle(ctx, fs, path, client, creds); err != nil {
+				return err
+			}
+			cmd.Printf("Downloaded diagnostic bundle of %s to %s\n", ip, path)
+			return nil
+		})
+	}
+
+	units, err := cmd.Flags().GetStringSlice("unit")
+	if err != nil {
+		return err
+	}
+	lines, err := cmd.Flags().GetUint32("lines")
+	if err != nil {
+		return err
+	}
+	follow, err := cmd.Flags().GetBool("follow")
+	if err != nil {
+		return err
+	}
+	req := &pb.GetJournalRequest{Units: units, Lines: lines, Follow: follow}
+
+	var mux sync.Mutex
+	return onInstances(cmd.Context(), ips, token, log, func(ctx context.Context, ip string, client pb.DebugdClient, creds grpc.CallOption) error {
+		return streamJournal(ctx, client, req, creds, prefixWriter{mux: &mux, out: cmd.OutOrStdout(), prefix: ip})
+	})
+}
+
+// streamJournal writes the journal of an instance to out.
+func streamJournal(ctx context.Context, client pb.DebugdClient, req *pb.GetJournalRequest, creds grpc.CallOption, out prefixWriter) error {
+	stream, err := client.GetJournal(ctx, req, creds, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("requesting journal: %w", err)
+	}
+	for {
+		entry, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("receiving journal: %w", err)
+		}
+		if err := out.WriteLine(entry.Line); err != nil {
+			return err
+		}
+	}
+}
+
+// downloadBundle writes the diagnostic bundle of an instance to path.
+func downloadBundle(ctx context.Context, fs afero.Fs, path string, client pb.DebugdClient, creds grpc.CallOption) (retErr error) {
+	stream, err := client.GetDiagnosticBundle(ctx, &pb.GetDiagnosticBundleRequest{}, creds, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("requesting diagnostic bundle: %w", err)
+	}
+
+	bundle, err := fs.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating bundle file: %w", err)
+	}
+	defer func() {
+		retErr = errors.Join(retErr, bundle.Close())
+	}()
+
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("receiving diagnostic bundle: %w", err)
+		}
+		if _, err := bundle.Write(chunk.Content); err != nil {
+			return fmt.Errorf("writing bundle file: %w", err)
+		}
+		if chunk.Last {
+			return nil
+		}
+	}
+}
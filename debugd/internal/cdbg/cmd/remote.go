@@ -0,0 +1,185 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/accesstoken"
+	pb "github.com/edgelesssys/constellation/v2/debugd/service"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes/kubectl"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// deployIPs returns the IPs set by the "ips" flag, or the cluster endpoint from the state file.
+func deployIPs(cmd *cobra.Command, fileHandler file.Handler) ([]string, error) {
+	ips, err := cmd.Flags().GetStringSlice("ips")
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) > 0 {
+		return ips, nil
+	}
+	return clusterEndpoint(fileHandler)
+}
+
+// instanceIPs returns the IPs set by the "ips" flag.
+// Otherwise, the nodes of the cluster are discovered through the Kubernetes API, using the admin kubeconfig of the workspace.
+// If there is no kubeconfig, because the cluster isn't initialized yet, only the cluster endpoint from the state file is returned.
+func instanceIPs(ctx context.Context, cmd *cobra.Command, fileHandler file.Handler, log *logger.Logger) ([]string, error) {
+	ips, err := cmd.Flags().GetStringSlice("ips")
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) > 0 {
+		return ips, nil
+	}
+
+	kubeConfig, err := fileHandler.Read(constants.AdminConfFilename)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Infof("No %s in workspace, connecting to the cluster endpoint only", constants.AdminConfFilename)
+		return clusterEndpoint(fileHandler)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	kubectl, err := kubectl.NewFromConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating Kubernetes client: %w", err)
+	}
+	nodes, err := kubectl.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discovering nodes, set the instances with --ips instead: %w", err)
+	}
+
+	ips = nodeIPs(nodes)
+	if len(ips) == 0 {
+		return nil, errors.New("no node addresses found, set the instances with --ips instead")
+	}
+	log.Debugf("Discovered instances %v", ips)
+	return ips, nil
+}
+
+// nodeIPs returns one IP per node.
+// External IPs are preferred, since internal IPs of cloud instances aren't reachable from outside of the cluster network.
+func nodeIPs(nodes []corev1.Node) []string {
+	var ips []string
+	for _, node := range nodes {
+		var internalIP, externalIP string
+		for _, address := range node.Status.Addresses {
+			switch {
+			case address.Type == corev1.NodeExternalIP && externalIP == "":
+				externalIP = address.Address
+			case address.Type == corev1.NodeInternalIP && internalIP == "":
+				internalIP = address.Address
+			}
+		}
+		switch {
+		case externalIP != "":
+			ips = append(ips, externalIP)
+		case internalIP != "":
+			ips = append(ips, internalIP)
+		}
+	}
+	return ips
+}
+
+// clusterEndpoint returns the cluster endpoint from the state file.
+func clusterEndpoint(fileHandler file.Handler) ([]string, error) {
+	var stateFile clusterStateFile
+	if err := fileHandler.ReadYAML(constants.StateFilename, &stateFile); err != nil {
+		return nil, fmt.Errorf("reading cluster state file: %w", err)
+	}
+	return []string{stateFile.Infrastructure.ClusterEndpoint}, nil
+}
+
+// loadOrCreateAccessToken returns the access token of the workspace and creates it if it doesn't exist yet.
+func loadOrCreateAccessToken(fileHandler file.Handler) (string, error) {
+	token, err := fileHandler.Read(accesstoken.Filename)
+	if err == nil {
+		return strings.TrimSpace(string(token)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("reading access token: %w", err)
+	}
+
+	newToken, err := accesstoken.New()
+	if err != nil {
+		return "", err
+	}
+	if err := fileHandler.Write(accesstoken.Filename, []byte(newToken)); err != nil {
+		return "", fmt.Errorf("writing access token: %w", err)
+	}
+	return newToken, nil
+}
+
+// loadAccessToken returns the access token of the workspace, which is created by "cdbg deploy".
+func loadAccessToken(fileHandler file.Handler) (string, error) {
+	token, err := fileHandler.Read(accesstoken.Filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("access token %q not found, run \"cdbg deploy\" from this workspace first", accesstoken.Filename)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading access token: %w", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// onInstances connects to all instances in parallel and calls fn with an authenticated client for each of them.
+// Errors of all instances are joined.
+func onInstances(ctx context.Context, ips []string, token string, log *logger.Logger,
+	fn func(ctx context.Context, ip string, client pb.DebugdClient, creds grpc.CallOption) error,
+) error {
+	creds := grpc.PerRPCCredentials(accesstoken.Credentials(token))
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(ips))
+	for i, ip := range ips {
+		wg.Add(1)
+		go func(i int, ip string) {
+			defer wg.Done()
+			client, closeAndWaitFn, err := newDebugdClient(ctx, ip, log)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: creating debugd client: %w", ip, err)
+				return
+			}
+			defer closeAndWaitFn()
+			if err := fn(ctx, ip, client, creds); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", ip, err)
+			}
+		}(i, ip)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// prefixWriter writes complete lines to out, prefixed with the instance they originate from.
+// Writers of different instances share a lock, so lines don't get interleaved.
+type prefixWriter struct {
+	mux    *sync.Mutex
+	out    io.Writer
+	prefix string
+}
+
+// WriteLine writes a single line.
+func (w prefixWriter) WriteLine(line string) error {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	_, err := fmt.Fprintf(w.out, "[%s] %s\n", w.prefix, strings.TrimSuffix(line, "\n"))
+	return err
+}
@@ -19,7 +19,8 @@ func newRootCmd() *cobra.Command {
 		Use:   "cdbg",
 		Short: "Constellation debugging client",
 		Long: `cdbg is the constellation debugging client.
-	It connects to Constellation instances running debugd and deploys a self-compiled version of the bootstrapper.`,
+	It connects to Constellation instances running debugd and deploys a self-compiled version of the bootstrapper.
+	It can also show the journal of instances and run diagnostic commands on them.`,
 		PersistentPreRunE: preRunRoot,
 	}
 	cmd.PersistentFlags().StringP("workspace", "C", "", "path to the Constellation workspace")
@@ -28,6 +29,8 @@ func newRootCmd() *cobra.Command {
 	must(cmd.MarkPersistentFlagDirname("workspace"))
 
 	cmd.AddCommand(newDeployCmd())
+	cmd.AddCommand(newLogsCmd())
+	cmd.AddCommand(newExecCmd())
 	return cmd
 }
 
@@ -0,0 +1,98 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package accesstoken authenticates requests to privileged debugd RPCs.
+
+cdbg generates a random access token per workspace and passes its SHA-256 digest to debugd as part of the info.
+Since the info is shared between all debugd instances of a cluster, every instance knows the digest,
+while the token itself never leaves the workspace.
+Requests carry the token as bearer token in the gRPC metadata.
+*/
+package accesstoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// InfoKey is the key of the info holding the hex encoded SHA-256 digest of the access token.
+	InfoKey = "debugd.access-token-sha256"
+	// Filename is the name of the file in the Constellation workspace holding the access token.
+	Filename = "constellation-debugd-token"
+
+	tokenSize           = 32
+	authorizationHeader = "authorization"
+	bearerPrefix        = "Bearer "
+)
+
+// New generates a new random access token.
+func New() (string, error) {
+	token := make([]byte, tokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generating access token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// Digest returns the hex encoded SHA-256 digest of the token.
+func Digest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// Credentials returns gRPC credentials attaching the token to every request.
+func Credentials(token string) credentials.PerRPCCredentials {
+	return tokenCredentials(token)
+}
+
+// Verify checks that the request context carries a token matching the hex encoded digest.
+// The returned error is a gRPC status error.
+func Verify(ctx context.Context, digest string) error {
+	if digest == "" {
+		return status.Error(codes.Unauthenticated, "no access token configured on this instance, redeploy with a recent cdbg")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+	values := md.Get(authorizationHeader)
+	if len(values) != 1 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return status.Error(codes.Unauthenticated, "missing access token")
+	}
+
+	got := Digest(strings.TrimPrefix(values[0], bearerPrefix))
+	if subtle.ConstantTimeCompare([]byte(got), []byte(strings.ToLower(digest))) != 1 {
+		return status.Error(codes.PermissionDenied, "invalid access token")
+	}
+	return nil
+}
+
+// tokenCredentials attaches the access token to requests.
+// debugd doesn't use transport security, so the credentials don't require it either.
+type tokenCredentials string
+
+// GetRequestMetadata returns the authorization header.
+func (t tokenCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{authorizationHeader: bearerPrefix + string(t)}, nil
+}
+
+// RequireTransportSecurity returns false, since debugd is only used with insecure connections.
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package accesstoken
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestVerify(t *testing.T) {
+	token, err := New()
+	require.NoError(t, err)
+	otherToken, err := New()
+	require.NoError(t, err)
+	require.NotEqual(t, token, otherToken)
+
+	withToken := func(token string) context.Context {
+		md, err := Credentials(token).GetRequestMetadata(context.Background())
+		require.NoError(t, err)
+		return metadata.NewIncomingContext(context.Background(), metadata.New(md))
+	}
+
+	testCases := map[string]struct {
+		ctx      context.Context
+		digest   string
+		wantCode codes.Code
+	}{
+		"valid token": {
+			ctx:      withToken(token),
+			digest:   Digest(token),
+			wantCode: codes.OK,
+		},
+		"invalid token": {
+			ctx:      withToken(otherToken),
+			digest:   Digest(token),
+			wantCode: codes.PermissionDenied,
+		},
+		"missing token": {
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.MD{}),
+			digest:   Digest(token),
+			wantCode: codes.Unauthenticated,
+		},
+		"missing metadata": {
+			ctx:      context.Background(),
+			digest:   Digest(token),
+			wantCode: codes.Unauthenticated,
+		},
+		"wrong scheme": {
+			ctx:      metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic "+token)),
+			digest:   Digest(token),
+			wantCode: codes.Unauthenticated,
+		},
+		"no digest configured": {
+			ctx:      withToken(token),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := Verify(tc.ctx, tc.digest)
+			assert.Equal(tc.wantCode, status.Code(err))
+		})
+	}
+}
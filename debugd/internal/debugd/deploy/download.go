@@ -82,7 +82,11 @@ func (d *Download) DownloadDeployment(ctx context.Context, ip string) error {
 	defer closer.Close()
 
 	log.Infof("Trying to download files")
-	stream, err := client.DownloadFiles(ctx, &pb.DownloadFilesRequest{})
+	stream, err := client.DownloadFiles(ctx, &pb.DownloadFilesRequest{
+		// resume a previously failed download
+		States:      filetransfer.FileStatesToProto(d.transfer.AbortedFileStates()),
+		Compression: pb.Compression_COMPRESSION_ZSTD,
+	})
 	if err != nil {
 		return fmt.Errorf("starting file download from other instance: %w", err)
 	}
@@ -153,6 +157,7 @@ type serviceManager interface {
 type fileTransferer interface {
 	RecvFiles(stream filetransfer.RecvFilesStream) error
 	GetFiles() []filetransfer.FileStat
+	AbortedFileStates() map[string]filetransfer.FileState
 }
 
 // NetDialer can open a net.Conn.
@@ -19,6 +19,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/grpc/testdialer"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
 )
@@ -33,10 +34,12 @@ func TestMain(m *testing.M) {
 func TestDownloadDeployment(t *testing.T) {
 	testCases := map[string]struct {
 		files                  []filetransfer.FileStat
+		abortedStates          map[string]filetransfer.FileState
 		recvFilesErr           error
 		overrideServiceUnitErr error
 		wantErr                bool
 		wantOverrideCalls      []struct{ UnitName, ExecStart string }
+		wantRequestStates      []*pb.FileState
 	}{
 		"download works": {
 			files: []filetransfer.FileStat{
@@ -56,6 +59,14 @@ func TestDownloadDeployment(t *testing.T) {
 				{"unitA", "target/testfileA"},
 			},
 		},
+		"aborted download is resumed": {
+			abortedStates: map[string]filetransfer.FileState{
+				"target/testfileA": {Size: 2, SHA256: []byte{0x01}},
+			},
+			wantRequestStates: []*pb.FileState{
+				{TargetPath: "target/testfileA", Size: 2, Sha256: []byte{0x01}},
+			},
+		},
 		"recv files error is detected": {
 			recvFilesErr: errors.New("some error"),
 			wantErr:      true,
@@ -102,9 +113,10 @@ func TestDownloadDeployment(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
+			require := require.New(t)
 
 			ip := "192.0.2.0"
-			transfer := &stubTransfer{recvFilesErr: tc.recvFilesErr, files: tc.files}
+			transfer := &stubTransfer{recvFilesErr: tc.recvFilesErr, files: tc.files, abortedStates: tc.abortedStates}
 			serviceMgr := &stubServiceManager{overrideServiceUnitExecStartErr: tc.overrideServiceUnitErr}
 			dialer := testdialer.NewBufconnDialer()
 
@@ -131,6 +143,14 @@ func TestDownloadDeployment(t *testing.T) {
 			}
 
 			assert.Equal(tc.wantOverrideCalls, serviceMgr.overrideCalls)
+			require.NotNil(server.request)
+			assert.Equal(pb.Compression_COMPRESSION_ZSTD, server.request.Compression)
+			require.Len(server.request.States, len(tc.wantRequestStates))
+			for i, want := range tc.wantRequestStates {
+				assert.Equal(want.TargetPath, server.request.States[i].TargetPath)
+				assert.Equal(want.Size, server.request.States[i].Size)
+				assert.Equal(want.Sha256, server.request.States[i].Sha256)
+			}
 		})
 	}
 }
@@ -226,11 +246,18 @@ func (s *stubServiceManager) OverrideServiceUnitExecStart(_ context.Context, uni
 }
 
 type stubTransfer struct {
-	recvFilesErr error
-	files        []filetransfer.FileStat
+	recvFilesErr  error
+	files         []filetransfer.FileStat
+	abortedStates map[string]filetransfer.FileState
 }
 
-func (t *stubTransfer) RecvFiles(_ filetransfer.RecvFilesStream) error {
+func (t *stubTransfer) RecvFiles(stream filetransfer.RecvFilesStream) error {
+	// consume the stream, so the download request reaches the server
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
 	return t.recvFilesErr
 }
 
@@ -238,14 +265,20 @@ func (t *stubTransfer) GetFiles() []filetransfer.FileStat {
 	return t.files
 }
 
+func (t *stubTransfer) AbortedFileStates() map[string]filetransfer.FileState {
+	return t.abortedStates
+}
+
 // stubDownloadServer implements DebugdServer; only stubs DownloadFiles, panics on every other rpc.
 type stubDownloadServer struct {
+	request    *pb.DownloadFilesRequest
 	downladErr error
 
 	pb.UnimplementedDebugdServer
 }
 
-func (s *stubDownloadServer) DownloadFiles(_ *pb.DownloadFilesRequest, _ pb.Debugd_DownloadFilesServer) error {
+func (s *stubDownloadServer) DownloadFiles(req *pb.DownloadFilesRequest, _ pb.Debugd_DownloadFilesServer) error {
+	s.request = req
 	return s.downladErr
 }
 
@@ -0,0 +1,305 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package diagnostics runs diagnostic commands on a debugd instance.
+
+Only an allow-list of read-only commands can be executed.
+Arguments are validated against the allow-list as well and commands are executed without a shell.
+*/
+package diagnostics
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"go.uber.org/zap"
+)
+
+const containerdEndpoint = "unix:///run/containerd/containerd.sock"
+
+var (
+	// ErrCommandNotAllowed is returned if a command is not on the allow-list.
+	ErrCommandNotAllowed = errors.New("command not allowed")
+
+	mapperNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+	pcrSelectPattern  = regexp.MustCompile(`^(sha1|sha256|sha384|sha512):(all|[0-9]+(,[0-9]+)*)(\+(sha1|sha256|sha384|sha512):(all|[0-9]+(,[0-9]+)*))*$`)
+	unitPattern       = regexp.MustCompile(`^[a-zA-Z0-9@._:\\-]+$`)
+)
+
+// allowedCommand is a command that may be executed by clients.
+type allowedCommand struct {
+	// command is the fixed part of the command line clients have to send.
+	command []string
+	// usage describes the command and its arguments.
+	usage string
+	// minArgs and maxArgs limit the number of additional arguments.
+	minArgs, maxArgs int
+	// validArg validates an additional argument.
+	validArg func(string) bool
+	// extraArgs are added to the command line after the fixed part, but are not sent by clients.
+	extraArgs []string
+	// bundleArgs are the additional arguments used when collecting a diagnostic bundle.
+	bundleArgs []string
+}
+
+// allowedCommands lists the commands that can be executed.
+var allowedCommands = []allowedCommand{
+	{
+		command:    []string{"cryptsetup", "status"},
+		usage:      "cryptsetup status MAPPER_NAME",
+		minArgs:    1,
+		maxArgs:    1,
+		validArg:   mapperNamePattern.MatchString,
+		bundleArgs: []string{"state"},
+	},
+	{
+		command:  []string{"tpm2_pcrread"},
+		usage:    "tpm2_pcrread [PCR_SELECTION]",
+		maxArgs:  1,
+		validArg: pcrSelectPattern.MatchString,
+	},
+	{
+		command: []string{"crictl", "ps"},
+		usage:   "crictl ps [-a|--all] [-q|--quiet] [-v|--verbose]",
+		maxArgs: 3,
+		validArg: func(arg string) bool {
+			return slices.Contains([]string{"-a", "--all", "-q", "--quiet", "-v", "--verbose"}, arg)
+		},
+		// crictl has no default runtime endpoint configured on Constellation nodes
+		extraArgs:  []string{"--runtime-endpoint", containerdEndpoint},
+		bundleArgs: []string{"--all"},
+	},
+}
+
+// AllowedCommands returns the usage of the allowed commands.
+func AllowedCommands() []string {
+	var usages []string
+	for _, allowed := range allowedCommands {
+		usages = append(usages, allowed.usage)
+	}
+	return usages
+}
+
+// Diagnostics runs diagnostic commands.
+type Diagnostics struct {
+	log    *logger.Logger
+	runner commandRunner
+}
+
+// New creates a new Diagnostics.
+func New(log *logger.Logger) *Diagnostics {
+	return &Diagnostics{
+		log:    log,
+		runner: execRunner{},
+	}
+}
+
+// Exec runs an allow-listed command and writes its output to stdout and stderr.
+// A non-zero exit code of the command is not considered an error.
+func (d *Diagnostics) Exec(ctx context.Context, command []string, stdout, stderr io.Writer) (int, error) {
+	argv, err := resolveCommand(command)
+	if err != nil {
+		return 0, err
+	}
+	d.log.With(zap.Strings("command", command)).Infof("Executing command")
+	return d.runner.Run(ctx, argv, stdout, stderr)
+}
+
+// Journal writes the journal of the given systemd units line by line to send.
+// If no units are given, the whole journal is returned.
+// If lines is greater than zero, only the last lines of the journal are returned.
+// If follow is set, Journal blocks and sends new entries until the context is canceled.
+func (d *Diagnostics) Journal(ctx context.Context, units []string, lines uint32, follow bool, send func(line string) error) error {
+	argv := []string{"journalctl", "--no-pager", "--output=short-iso"}
+	for _, unit := range units {
+		if !unitPattern.MatchString(unit) || strings.HasPrefix(unit, "-") {
+			return fmt.Errorf("invalid unit name %q", unit)
+		}
+		argv = append(argv, "--unit="+unit)
+	}
+	if lines > 0 {
+		argv = append(argv, "--lines="+strconv.FormatUint(uint64(lines), 10))
+	}
+	if follow {
+		argv = append(argv, "--follow")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	reader, writer := io.Pipe()
+	sendErr := make(chan error, 1)
+	go func() {
+		defer reader.Close()
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if err := send(scanner.Text()); err != nil {
+				// stop journalctl, since nobody receives its output anymore
+				cancel()
+				sendErr <- err
+				return
+			}
+		}
+		sendErr <- scanner.Err()
+	}()
+
+	var stderr bytes.Buffer
+	exitCode, runErr := d.runner.Run(ctx, argv, writer, &stderr)
+	writer.Close()
+	if err := <-sendErr; err != nil {
+		return fmt.Errorf("sending journal: %w", err)
+	}
+	if follow && ctx.Err() != nil {
+		// following the journal ends with the request
+		return nil
+	}
+	if runErr != nil {
+		return fmt.Errorf("running journalctl: %w", runErr)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("journalctl exited with code %d: %s", exitCode, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// Bundle writes a gzip compressed tar archive with the journal of the current boot
+// and the output of all allowed commands to w.
+// Failing commands don't fail the bundle, their errors are part of the archive instead.
+func (d *Diagnostics) Bundle(ctx context.Context, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	now := time.Now()
+
+	addFile := func(name string, content []byte) error {
+		if err := archive.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}); err != nil {
+			return fmt.Errorf("writing archive header for %q: %w", name, err)
+		}
+		if _, err := archive.Write(content); err != nil {
+			return fmt.Errorf("writing %q to archive: %w", name, err)
+		}
+		return nil
+	}
+
+	journal, err := d.runBundleCommand(ctx, []string{"journalctl", "--no-pager", "--output=short-iso", "--boot"})
+	if err != nil {
+		return err
+	}
+	if err := addFile("journal.log", journal); err != nil {
+		return err
+	}
+
+	for _, allowed := range allowedCommands {
+		command := append(slices.Clone(allowed.command), allowed.bundleArgs...)
+		argv, err := resolveCommand(command)
+		if err != nil {
+			return fmt.Errorf("resolving bundle command: %w", err)
+		}
+		output, err := d.runBundleCommand(ctx, argv)
+		if err != nil {
+			return err
+		}
+		if err := addFile(bundlePath(command), output); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("closing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compressing archive: %w", err)
+	}
+	return nil
+}
+
+// runBundleCommand runs the command and returns its combined output, followed by the exit code or error.
+// Only a canceled context is returned as error.
+func (d *Diagnostics) runBundleCommand(ctx context.Context, argv []string) ([]byte, error) {
+	var output bytes.Buffer
+	exitCode, err := d.runner.Run(ctx, argv, &output, &output)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		d.log.With(zap.Error(err), zap.Strings("command", argv)).Warnf("Running diagnostic command failed")
+		fmt.Fprintf(&output, "\n%s failed: %s\n", argv[0], err)
+	} else {
+		fmt.Fprintf(&output, "\n%s exited with code %d\n", argv[0], exitCode)
+	}
+	return output.Bytes(), nil
+}
+
+// resolveCommand checks the command against the allow-list and returns the command line to execute.
+func resolveCommand(command []string) ([]string, error) {
+	for _, allowed := range allowedCommands {
+		if len(command) < len(allowed.command) || !slices.Equal(command[:len(allowed.command)], allowed.command) {
+			continue
+		}
+		args := command[len(allowed.command):]
+		if len(args) < allowed.minArgs || len(args) > allowed.maxArgs {
+			return nil, fmt.Errorf("%w: %q expects %d to %d arguments, got %d",
+				ErrCommandNotAllowed, strings.Join(allowed.command, " "), allowed.minArgs, allowed.maxArgs, len(args))
+		}
+		for _, arg := range args {
+			if !allowed.validArg(arg) {
+				return nil, fmt.Errorf("%w: invalid argument %q for %q", ErrCommandNotAllowed, arg, strings.Join(allowed.command, " "))
+			}
+		}
+
+		argv := slices.Clone(allowed.command)
+		argv = append(argv, allowed.extraArgs...)
+		return append(argv, args...), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrCommandNotAllowed, strings.Join(command, " "))
+}
+
+// bundlePath returns the path of the command output in the diagnostic bundle.
+func bundlePath(command []string) string {
+	return "commands/" + strings.Join(command, "_") + ".log"
+}
+
+type commandRunner interface {
+	Run(ctx context.Context, argv []string, stdout, stderr io.Writer) (int, error)
+}
+
+// execRunner runs commands as child processes.
+type execRunner struct{}
+
+// Run runs the command and returns its exit code.
+func (execRunner) Run(ctx context.Context, argv []string, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
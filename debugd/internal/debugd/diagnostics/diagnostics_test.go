@@ -0,0 +1,202 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCommand(t *testing.T) {
+	testCases := map[string]struct {
+		command  []string
+		wantArgv []string
+		wantErr  bool
+	}{
+		"cryptsetup status": {
+			command:  []string{"cryptsetup", "status", "state"},
+			wantArgv: []string{"cryptsetup", "status", "state"},
+		},
+		"cryptsetup status without device": {
+			command: []string{"cryptsetup", "status"},
+			wantErr: true,
+		},
+		"cryptsetup status with option": {
+			command: []string{"cryptsetup", "status", "--debug"},
+			wantErr: true,
+		},
+		"cryptsetup subcommand not allowed": {
+			command: []string{"cryptsetup", "close", "state"},
+			wantErr: true,
+		},
+		"tpm2_pcrread": {
+			command:  []string{"tpm2_pcrread"},
+			wantArgv: []string{"tpm2_pcrread"},
+		},
+		"tpm2_pcrread with selection": {
+			command:  []string{"tpm2_pcrread", "sha256:0,4,15+sha1:all"},
+			wantArgv: []string{"tpm2_pcrread", "sha256:0,4,15+sha1:all"},
+		},
+		"tpm2_pcrread with output file": {
+			command: []string{"tpm2_pcrread", "-o", "/etc/passwd"},
+			wantErr: true,
+		},
+		"crictl ps": {
+			command:  []string{"crictl", "ps", "-a"},
+			wantArgv: []string{"crictl", "ps", "--runtime-endpoint", containerdEndpoint, "-a"},
+		},
+		"crictl ps with unknown flag": {
+			command: []string{"crictl", "ps", "--runtime-endpoint", "unix:///evil.sock"},
+			wantErr: true,
+		},
+		"unknown command": {
+			command: []string{"sh", "-c", "id"},
+			wantErr: true,
+		},
+		"empty command": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			argv, err := resolveCommand(tc.command)
+			if tc.wantErr {
+				assert.ErrorIs(err, ErrCommandNotAllowed)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantArgv, argv)
+		})
+	}
+}
+
+func TestJournal(t *testing.T) {
+	testCases := map[string]struct {
+		units     []string
+		lines     uint32
+		follow    bool
+		runner    *stubRunner
+		sendErr   error
+		wantArgv  []string
+		wantLines []string
+		wantErr   bool
+	}{
+		"units and lines": {
+			units:     []string{"bootstrapper.service", "kubelet"},
+			lines:     10,
+			runner:    &stubRunner{stdout: "first\nsecond\n"},
+			wantArgv:  []string{"journalctl", "--no-pager", "--output=short-iso", "--unit=bootstrapper.service", "--unit=kubelet", "--lines=10"},
+			wantLines: []string{"first", "second"},
+		},
+		"follow": {
+			follow:    true,
+			runner:    &stubRunner{stdout: "first\n"},
+			wantArgv:  []string{"journalctl", "--no-pager", "--output=short-iso", "--follow"},
+			wantLines: []string{"first"},
+		},
+		"invalid unit": {
+			units:   []string{"--file=/etc/shadow"},
+			runner:  &stubRunner{},
+			wantErr: true,
+		},
+		"journalctl fails": {
+			runner:  &stubRunner{exitCode: 1},
+			wantErr: true,
+		},
+		"send fails": {
+			runner:  &stubRunner{stdout: "first\n"},
+			sendErr: errors.New("failed"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			d := &Diagnostics{log: logger.NewTest(t), runner: tc.runner}
+			var lines []string
+			err := d.Journal(context.Background(), tc.units, tc.lines, tc.follow, func(line string) error {
+				lines = append(lines, line)
+				return tc.sendErr
+			})
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal([][]string{tc.wantArgv}, tc.runner.argvs)
+			assert.Equal(tc.wantLines, lines)
+		})
+	}
+}
+
+func TestBundle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	runner := &stubRunner{stdout: "output", exitCode: 1}
+	d := &Diagnostics{log: logger.NewTest(t), runner: runner}
+
+	var buf bytes.Buffer
+	require.NoError(d.Bundle(context.Background(), &buf))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(err)
+	archive := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		content, err := io.ReadAll(archive)
+		require.NoError(err)
+		files[header.Name] = string(content)
+	}
+
+	assert.Len(files, len(allowedCommands)+1)
+	assert.Contains(files, "journal.log")
+	assert.Contains(files, "commands/cryptsetup_status_state.log")
+	assert.Equal("output\ntpm2_pcrread exited with code 1\n", files["commands/tpm2_pcrread.log"])
+}
+
+type stubRunner struct {
+	mux      sync.Mutex
+	argvs    [][]string
+	stdout   string
+	exitCode int
+	runErr   error
+}
+
+func (s *stubRunner) Run(_ context.Context, argv []string, stdout, _ io.Writer) (int, error) {
+	s.mux.Lock()
+	s.argvs = append(s.argvs, argv)
+	s.mux.Unlock()
+
+	if _, err := io.Copy(stdout, strings.NewReader(s.stdout)); err != nil {
+		return 0, fmt.Errorf("writing output: %w", err)
+	}
+	return s.exitCode, s.runErr
+}
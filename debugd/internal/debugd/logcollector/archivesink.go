@@ -0,0 +1,182 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awscredentials "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// archiveRecord is the JSON representation of a log entry used by the OpenSearch, file and S3 sinks.
+type archiveRecord struct {
+	Timestamp time.Time         `json:"@timestamp"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+func archiveRecordFromEntry(e entry, metadata map[string]string) archiveRecord {
+	return archiveRecord{
+		Timestamp: e.Time,
+		Message:   e.Message,
+		Fields:    e.Fields,
+		Metadata:  metadata,
+	}
+}
+
+// encodeArchiveRecords writes the entries as JSON lines to w.
+func encodeArchiveRecords(w io.Writer, entries []entry, metadata map[string]string) error {
+	encoder := json.NewEncoder(w)
+	for _, e := range entries {
+		if err := encoder.Encode(archiveRecordFromEntry(e, metadata)); err != nil {
+			return fmt.Errorf("encoding log entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// fileSink appends log entries as JSON lines to a local file.
+type fileSink struct {
+	mux      sync.Mutex
+	file     *os.File
+	metadata map[string]string
+}
+
+func newFileSink(cfg sinkConfig, metadata map[string]string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(cfg.path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+	file, err := os.OpenFile(cfg.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	return &fileSink{file: file, metadata: metadata}, nil
+}
+
+// Write appends the entries to the log file.
+func (s *fileSink) Write(_ context.Context, entries []entry) error {
+	var buf bytes.Buffer
+	if err := encodeArchiveRecords(&buf, entries, s.metadata); err != nil {
+		return err
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing log file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the log file.
+func (s *fileSink) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.file.Close()
+}
+
+type s3PutObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// s3Sink uploads every batch of log entries as gzip compressed JSON lines object to an S3 bucket.
+type s3Sink struct {
+	client   s3PutObjectAPI
+	bucket   string
+	prefix   string
+	metadata map[string]string
+
+	mux sync.Mutex
+	seq int
+}
+
+func newS3Sink(ctx context.Context, cfg sinkConfig, metadata map[string]string) (*s3Sink, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.region))
+	}
+	if cfg.accessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			awscredentials.NewStaticCredentialsProvider(cfg.accessKeyID, cfg.secretAccessKey, ""),
+		))
+	}
+	clientCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading S3 client config: %w", err)
+	}
+
+	client := s3.NewFromConfig(clientCfg, func(o *s3.Options) {
+		if cfg.endpoint != "" {
+			// S3 compatible object stores, like MinIO, usually don't support virtual hosted buckets.
+			o.EndpointResolver = s3.EndpointResolverFromURL(cfg.endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &s3Sink{
+		client:   client,
+		bucket:   cfg.bucket,
+		prefix:   cfg.prefix,
+		metadata: metadata,
+	}, nil
+}
+
+// Write uploads the entries as a new object.
+func (s *s3Sink) Write(ctx context.Context, entries []entry) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := encodeArchiveRecords(gz, entries, s.metadata); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compressing log entries: %w", err)
+	}
+
+	key := s.objectKey(time.Now())
+	if _, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		Body:            bytes.NewReader(buf.Bytes()),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	}); err != nil {
+		return fmt.Errorf("uploading log archive %q: %w", key, err)
+	}
+	return nil
+}
+
+// objectKey returns a unique key for the next log archive of this node.
+func (s *s3Sink) objectKey(now time.Time) string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.seq++
+
+	node := s.metadata["name"]
+	if node == "" {
+		node = "unknown"
+	}
+	return path.Join(s.prefix, node, fmt.Sprintf("%s-%06d.jsonl.gz", now.UTC().Format("20060102T150405Z"), s.seq))
+}
+
+// Close is a no-op.
+func (s *s3Sink) Close() error {
+	return nil
+}
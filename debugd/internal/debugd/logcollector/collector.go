@@ -0,0 +1,123 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+)
+
+const (
+	// defaultBatchSize is the number of entries after which a batch is shipped to the sink.
+	defaultBatchSize = 500
+	// defaultFlushInterval is the interval after which a batch is shipped to the sink, even if it isn't full.
+	defaultFlushInterval = 5 * time.Second
+	// maxBufferedEntries is the number of entries kept while the sink is unavailable.
+	// If more entries are collected, the oldest ones are dropped.
+	maxBufferedEntries = 50 * defaultBatchSize
+)
+
+// entry is a single log line.
+type entry struct {
+	Time    time.Time
+	Message string
+	// Fields describe the origin of the log line, e.g. the systemd unit or Kubernetes pod.
+	Fields map[string]string
+}
+
+// source produces log entries until its context is canceled.
+type source interface {
+	Run(ctx context.Context, out chan<- entry) error
+	Name() string
+}
+
+// sink ships log entries to their destination.
+type sink interface {
+	Write(ctx context.Context, entries []entry) error
+	Close() error
+}
+
+// collector reads log entries from its sources and ships them to the sink in batches.
+type collector struct {
+	sources       []source
+	sink          sink
+	batchSize     int
+	flushInterval time.Duration
+	log           *logger.Logger
+}
+
+func newCollector(sources []source, sink sink, log *logger.Logger) *collector {
+	return &collector{
+		sources:       sources,
+		sink:          sink,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		log:           log,
+	}
+}
+
+// Run collects logs until the context is canceled.
+// Entries that are still buffered when the context is canceled are shipped before returning.
+func (c *collector) Run(ctx context.Context) {
+	entries := make(chan entry, c.batchSize)
+
+	var wg sync.WaitGroup
+	for _, src := range c.sources {
+		wg.Add(1)
+		go func(src source) {
+			defer wg.Done()
+			if err := src.Run(ctx, entries); err != nil && ctx.Err() == nil {
+				c.log.Errorf("Log source %s stopped: %v", src.Name(), err)
+			}
+		}(src)
+	}
+	go func() {
+		wg.Wait()
+		close(entries)
+	}()
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	var buffer []entry
+	flush := func(ctx context.Context) {
+		if len(buffer) == 0 {
+			return
+		}
+		if err := c.sink.Write(ctx, buffer); err != nil {
+			c.log.Errorf("Shipping %d log entries: %v", len(buffer), err)
+			if len(buffer) > maxBufferedEntries {
+				c.log.Warnf("Dropping %d log entries", len(buffer)-maxBufferedEntries)
+				buffer = buffer[len(buffer)-maxBufferedEntries:]
+			}
+			return
+		}
+		buffer = nil
+	}
+
+	for {
+		select {
+		case e, ok := <-entries:
+			if !ok {
+				// all sources stopped, ship what is left without the canceled context
+				flushCtx, cancel := context.WithTimeout(context.Background(), c.flushInterval)
+				flush(flushCtx)
+				cancel()
+				return
+			}
+			buffer = append(buffer, e)
+			if len(buffer) >= c.batchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
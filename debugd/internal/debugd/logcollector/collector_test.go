@@ -0,0 +1,101 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	testCases := map[string]struct {
+		numEntries  int
+		batchSize   int
+		writeErr    error
+		wantBatches []int
+	}{
+		"single batch on shutdown": {
+			numEntries:  3,
+			batchSize:   10,
+			wantBatches: []int{3},
+		},
+		"full batches": {
+			numEntries:  25,
+			batchSize:   10,
+			wantBatches: []int{10, 10, 5},
+		},
+		"no entries": {
+			batchSize: 10,
+		},
+		"sink unavailable": {
+			numEntries:  5,
+			batchSize:   10,
+			writeErr:    errors.New("failed"),
+			wantBatches: []int{5},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			sink := &stubSink{writeErr: tc.writeErr}
+			c := newCollector([]source{&stubSource{numEntries: tc.numEntries}}, sink, logger.NewTest(t))
+			c.batchSize = tc.batchSize
+			c.flushInterval = time.Hour
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			c.Run(ctx)
+
+			assert.Equal(tc.wantBatches, sink.batches)
+		})
+	}
+}
+
+// stubSource emits numEntries entries and stops.
+type stubSource struct {
+	numEntries int
+}
+
+func (s *stubSource) Name() string {
+	return "stub"
+}
+
+func (s *stubSource) Run(ctx context.Context, out chan<- entry) error {
+	for i := 0; i < s.numEntries; i++ {
+		select {
+		case out <- entry{Time: time.Now(), Message: "log"}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+type stubSink struct {
+	mux      sync.Mutex
+	batches  []int
+	writeErr error
+}
+
+func (s *stubSink) Write(_ context.Context, entries []entry) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.batches = append(s.batches, len(entries))
+	return s.writeErr
+}
+
+func (s *stubSink) Close() error {
+	return nil
+}
@@ -221,6 +221,9 @@ type awsSecretManagerAPI interface {
 	) (*awssecretmanager.GetSecretValueOutput, error)
 }
 
+// qemuOpenSearchPasswordInfoKey holds the password for the default OpenSearch instance on QEMU.
+const qemuOpenSearchPasswordInfoKey = "qemu.opensearch-pw"
+
 type qemuCloudCredentialGetter struct {
 	creds credentials
 }
@@ -228,12 +231,12 @@ type qemuCloudCredentialGetter struct {
 func newQemuCloudCredentialGetter(infoMap *info.Map) (*qemuCloudCredentialGetter, error) {
 	const username = "cluster-instance-qemu"
 
-	password, ok, err := infoMap.Get("qemu.opensearch-pw")
+	password, ok, err := infoMap.Get(qemuOpenSearchPasswordInfoKey)
 	if err != nil {
-		return nil, fmt.Errorf("getting qemu.opensearch-pw from info: %w", err)
+		return nil, fmt.Errorf("getting %s from info: %w", qemuOpenSearchPasswordInfoKey, err)
 	}
 	if !ok {
-		return nil, errors.New("qemu.opensearch-pw not found in info")
+		return nil, fmt.Errorf("%s not found in info", qemuOpenSearchPasswordInfoKey)
 	}
 
 	return &qemuCloudCredentialGetter{
@@ -63,6 +63,9 @@ func (f Fields) Check() error {
 		if !strings.HasPrefix(k, DebugdLogcollectPrefix) {
 			continue
 		}
+		if k == DebugdLogcollectPrefix+"sink" || strings.HasPrefix(k, DebugdLogcollectPrefix+"sink.") {
+			continue // log sink configuration, not a field
+		}
 		subkey := strings.TrimPrefix(k, DebugdLogcollectPrefix)
 
 		if _, ok := AllowedFields[subkey]; !ok {
@@ -0,0 +1,330 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const httpSinkTimeout = 30 * time.Second
+
+// httpPoster sends a request body to an HTTP endpoint, using basic authentication if configured.
+type httpPoster struct {
+	client   *http.Client
+	username string
+	password string
+	headers  map[string]string
+}
+
+func newHTTPPoster(cfg sinkConfig, headers map[string]string) httpPoster {
+	return httpPoster{
+		client:   &http.Client{Timeout: httpSinkTimeout},
+		username: cfg.username,
+		password: cfg.password,
+		headers:  headers,
+	}
+}
+
+// post sends body to url and returns the response body.
+func (p httpPoster) post(ctx context.Context, url string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, respBody)
+	}
+	return respBody, nil
+}
+
+// openSearchSink ships log entries to an OpenSearch instance using the bulk API.
+type openSearchSink struct {
+	url      string
+	metadata map[string]string
+	poster   httpPoster
+}
+
+func newOpenSearchSink(cfg sinkConfig, metadata map[string]string) *openSearchSink {
+	return &openSearchSink{
+		url:      strings.TrimSuffix(cfg.endpoint, "/") + "/_bulk",
+		metadata: metadata,
+		poster:   newHTTPPoster(cfg, map[string]string{"Content-Type": "application/x-ndjson"}),
+	}
+}
+
+// Write ships the entries to the weekly logs index.
+func (s *openSearchSink) Write(ctx context.Context, entries []entry) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, e := range entries {
+		// Use the ISO week, since the calendar year doesn't handle weeks spanning the turn of the year.
+		year, week := e.Time.ISOWeek()
+		action := map[string]map[string]string{"index": {"_index": fmt.Sprintf("logs-%04d.%02d", year, week)}}
+		if err := encoder.Encode(action); err != nil {
+			return fmt.Errorf("encoding bulk action: %w", err)
+		}
+		if err := encoder.Encode(archiveRecordFromEntry(e, s.metadata)); err != nil {
+			return fmt.Errorf("encoding log entry: %w", err)
+		}
+	}
+
+	respBody, err := s.poster.post(ctx, s.url, body.Bytes())
+	if err != nil {
+		return fmt.Errorf("shipping logs to OpenSearch: %w", err)
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("unmarshaling OpenSearch response: %w", err)
+	}
+	if resp.Errors {
+		return fmt.Errorf("OpenSearch rejected some log entries: %s", respBody)
+	}
+	return nil
+}
+
+// Close is a no-op.
+func (s *openSearchSink) Close() error {
+	return nil
+}
+
+// otlpSink ships log entries to an OpenTelemetry collector using OTLP/HTTP with JSON encoding.
+type otlpSink struct {
+	url      string
+	resource otlpResource
+	poster   httpPoster
+}
+
+func newOTLPSink(cfg sinkConfig, metadata map[string]string) *otlpSink {
+	url := strings.TrimSuffix(cfg.endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/logs") {
+		url += "/v1/logs"
+	}
+	return &otlpSink{
+		url:      url,
+		resource: otlpResource{Attributes: otlpAttributes(metadata)},
+		poster:   newHTTPPoster(cfg, map[string]string{"Content-Type": "application/json"}),
+	}
+}
+
+// Write ships the entries as log records of a single resource.
+func (s *otlpSink) Write(ctx context.Context, entries []entry) error {
+	records := make([]otlpLogRecord, 0, len(entries))
+	for _, e := range entries {
+		records = append(records, otlpLogRecord{
+			TimeUnixNano: strconv.FormatInt(e.Time.UnixNano(), 10),
+			Body:         otlpValue{StringValue: e.Message},
+			Attributes:   otlpAttributes(e.Fields),
+		})
+	}
+	req := otlpExportLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: s.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: "constellation-debugd"},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshaling OTLP request: %w", err)
+	}
+	if _, err := s.poster.post(ctx, s.url, body); err != nil {
+		return fmt.Errorf("shipping logs to OTLP endpoint: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op.
+func (s *otlpSink) Close() error {
+	return nil
+}
+
+// The following types are the JSON encoding of an OTLP ExportLogsServiceRequest.
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/collector/logs/v1/logs_service.proto.
+
+type otlpExportLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Body         otlpValue      `json:"body"`
+	Attributes   []otlpKeyValue `json:"attributes"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpAttributes converts a map to OTLP attributes, sorted by key.
+func otlpAttributes(m map[string]string) []otlpKeyValue {
+	attributes := make([]otlpKeyValue, 0, len(m))
+	for k, v := range m {
+		attributes = append(attributes, otlpKeyValue{Key: k, Value: otlpValue{StringValue: v}})
+	}
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Key < attributes[j].Key })
+	return attributes
+}
+
+// lokiStreamFields are the entry fields used as Loki stream labels, in addition to the metadata.
+// Other fields are high in cardinality and are therefore part of the log line.
+var lokiStreamFields = []string{"source", "systemd.unit", "kubernetes.namespace", "kubernetes.container_name"}
+
+var lokiInvalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// lokiSink ships log entries to Loki using the push API.
+type lokiSink struct {
+	url      string
+	metadata map[string]string
+	poster   httpPoster
+}
+
+func newLokiSink(cfg sinkConfig, metadata map[string]string) *lokiSink {
+	return &lokiSink{
+		url:      strings.TrimSuffix(cfg.endpoint, "/") + "/loki/api/v1/push",
+		metadata: metadata,
+		poster:   newHTTPPoster(cfg, map[string]string{"Content-Type": "application/json"}),
+	}
+}
+
+// Write ships the entries, grouped into streams by their labels.
+func (s *lokiSink) Write(ctx context.Context, entries []entry) error {
+	// Loki expects the values of a stream to be ordered by time.
+	entries = slices.Clone(entries)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+
+	streams := make(map[string]*lokiStream)
+	var streamKeys []string
+	for _, e := range entries {
+		labels := make(map[string]string, len(s.metadata)+len(lokiStreamFields))
+		for k, v := range s.metadata {
+			labels[lokiLabelName(k)] = v
+		}
+		var line strings.Builder
+		line.WriteString(e.Message)
+		for _, k := range sortedKeys(e.Fields) {
+			if slices.Contains(lokiStreamFields, k) {
+				labels[lokiLabelName(k)] = e.Fields[k]
+				continue
+			}
+			fmt.Fprintf(&line, " %s=%q", lokiLabelName(k), e.Fields[k])
+		}
+
+		key := fmt.Sprint(sortedLabels(labels))
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+			streamKeys = append(streamKeys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.Time.UnixNano(), 10), line.String()})
+	}
+
+	req := lokiPushRequest{Streams: make([]lokiStream, 0, len(streams))}
+	for _, key := range streamKeys {
+		req.Streams = append(req.Streams, *streams[key])
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshaling Loki push request: %w", err)
+	}
+	if _, err := s.poster.post(ctx, s.url, body); err != nil {
+		return fmt.Errorf("shipping logs to Loki: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op.
+func (s *lokiSink) Close() error {
+	return nil
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiLabelName converts a field name to a valid Loki label name.
+func lokiLabelName(name string) string {
+	return lokiInvalidLabelChars.ReplaceAllString(name, "_")
+}
+
+func sortedLabels(labels map[string]string) []string {
+	pairs := make([]string, 0, len(labels))
+	for _, k := range sortedKeys(labels) {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return pairs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
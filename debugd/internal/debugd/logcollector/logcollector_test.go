@@ -0,0 +1,32 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterInfoMap(t *testing.T) {
+	assert := assert.New(t)
+
+	in := map[string]string{
+		"logcollect":                        "true",
+		"logcollect.admin":                  "jane",
+		"logcollect.github.run-id":          "1234",
+		"logcollect.sink":                   "s3",
+		"logcollect.sink.bucket":            "logs",
+		"logcollect.sink.secret-access-key": "secret",
+		"other":                             "value",
+	}
+
+	assert.Equal(map[string]string{
+		"admin":         "jane",
+		"github.run-id": "1234",
+	}, filterInfoMap(in))
+}
@@ -0,0 +1,202 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// The log sink is configured through the following info keys. They are not forwarded as fields.
+var (
+	// sinkInfoKey selects the log sink. Defaults to sinkOpenSearch.
+	sinkInfoKey = DebugdLogcollectPrefix + "sink"
+	// sinkOptionPrefix is the prefix of the info keys holding the options of the log sink,
+	// e.g. "logcollect.sink.endpoint".
+	sinkOptionPrefix = sinkInfoKey + "."
+)
+
+// Supported log sinks.
+const (
+	sinkOpenSearch = "opensearch"
+	sinkOTLP       = "otlp"
+	sinkLoki       = "loki"
+	sinkFile       = "file"
+	sinkS3         = "s3"
+)
+
+const (
+	// defaultOpenSearchEndpoint is the OpenSearch instance logs are shipped to if no endpoint is configured.
+	defaultOpenSearchEndpoint = "https://search-e2e-logs-y46renozy42lcojbvrt3qq7csm.eu-central-1.es.amazonaws.com:443"
+	// defaultFileSinkPath is the file logs are written to by the file sink if no path is configured.
+	defaultFileSinkPath = "/run/logcollect/logs.jsonl"
+)
+
+// sinkConfig is the configuration of the log sink.
+type sinkConfig struct {
+	kind string
+	// endpoint is the base URL of the OpenSearch, OTLP/HTTP or Loki endpoint,
+	// or of an S3 compatible object store.
+	endpoint string
+	// username and password are used for basic authentication against HTTP endpoints.
+	username string
+	password string
+	// path is the file the file sink writes to.
+	path string
+	// bucket, region and prefix describe where the S3 sink stores log archives.
+	bucket string
+	region string
+	prefix string
+	// accessKeyID and secretAccessKey are static credentials for the S3 sink.
+	// If not set, the default credential chain of the node is used.
+	accessKeyID     string
+	secretAccessKey string
+}
+
+// sinkOptions lists the options each sink accepts.
+var sinkOptions = map[string][]string{
+	sinkOpenSearch: {"endpoint", "username", "password"},
+	sinkOTLP:       {"endpoint", "username", "password"},
+	sinkLoki:       {"endpoint", "username", "password"},
+	sinkFile:       {"path"},
+	sinkS3:         {"endpoint", "bucket", "region", "prefix", "access-key-id", "secret-access-key"},
+}
+
+// secretSinkOptions lists the options holding credentials.
+// Their values must not be returned by the unauthenticated GetInfo endpoint of debugd.
+var secretSinkOptions = []string{"password", "secret-access-key"}
+
+// IsSecretInfoKey returns true if the value of the info key is a credential used by the log collection.
+func IsSecretInfoKey(key string) bool {
+	if key == qemuOpenSearchPasswordInfoKey {
+		return true
+	}
+	return strings.HasPrefix(key, sinkOptionPrefix) && slices.Contains(secretSinkOptions, strings.TrimPrefix(key, sinkOptionPrefix))
+}
+
+// sinkConfigFromInfo parses the log sink configuration from the info map.
+func sinkConfigFromInfo(infoMap map[string]string) (sinkConfig, error) {
+	cfg := sinkConfig{kind: infoMap[sinkInfoKey]}
+	if cfg.kind == "" {
+		cfg.kind = sinkOpenSearch
+	}
+	allowed, ok := sinkOptions[cfg.kind]
+	if !ok {
+		return sinkConfig{}, fmt.Errorf("unknown log sink %q, supported sinks are %s", cfg.kind, strings.Join(supportedSinks(), ", "))
+	}
+
+	options := make(map[string]string)
+	for key, value := range infoMap {
+		if !strings.HasPrefix(key, sinkOptionPrefix) {
+			continue
+		}
+		option := strings.TrimPrefix(key, sinkOptionPrefix)
+		if !slices.Contains(allowed, option) {
+			return sinkConfig{}, fmt.Errorf("option %q is not supported by log sink %q", option, cfg.kind)
+		}
+		options[option] = value
+	}
+
+	cfg.endpoint = options["endpoint"]
+	cfg.username = options["username"]
+	cfg.password = options["password"]
+	cfg.path = options["path"]
+	cfg.bucket = options["bucket"]
+	cfg.region = options["region"]
+	cfg.prefix = options["prefix"]
+	cfg.accessKeyID = options["access-key-id"]
+	cfg.secretAccessKey = options["secret-access-key"]
+
+	switch cfg.kind {
+	case sinkOpenSearch:
+		if cfg.endpoint == "" {
+			cfg.endpoint = defaultOpenSearchEndpoint
+		}
+	case sinkOTLP, sinkLoki:
+		if cfg.endpoint == "" {
+			return sinkConfig{}, fmt.Errorf("log sink %q requires option %q", cfg.kind, sinkOptionPrefix+"endpoint")
+		}
+	case sinkFile:
+		if cfg.path == "" {
+			cfg.path = defaultFileSinkPath
+		}
+	case sinkS3:
+		if cfg.bucket == "" {
+			return sinkConfig{}, fmt.Errorf("log sink %q requires option %q", cfg.kind, sinkOptionPrefix+"bucket")
+		}
+		if (cfg.accessKeyID == "") != (cfg.secretAccessKey == "") {
+			return sinkConfig{}, fmt.Errorf("options %q and %q must be set together",
+				sinkOptionPrefix+"access-key-id", sinkOptionPrefix+"secret-access-key")
+		}
+	}
+
+	if cfg.endpoint != "" {
+		endpoint, err := url.Parse(cfg.endpoint)
+		if err != nil {
+			return sinkConfig{}, fmt.Errorf("parsing endpoint of log sink %q: %w", cfg.kind, err)
+		}
+		if (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return sinkConfig{}, fmt.Errorf("endpoint of log sink %q must be an http or https URL, got %q", cfg.kind, cfg.endpoint)
+		}
+	}
+	return cfg, nil
+}
+
+// newSink creates the log sink described by cfg.
+// metadata is attached to every shipped log entry.
+func newSink(ctx context.Context, cfg sinkConfig, metadata map[string]string, creds credentialGetterFunc) (sink, error) {
+	switch cfg.kind {
+	case sinkOpenSearch:
+		if cfg.username == "" {
+			c, err := creds(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("getting OpenSearch credentials: %w", err)
+			}
+			cfg.username = c.Username
+			cfg.password = c.Password
+		}
+		return newOpenSearchSink(cfg, metadata), nil
+	case sinkOTLP:
+		return newOTLPSink(cfg, metadata), nil
+	case sinkLoki:
+		return newLokiSink(cfg, metadata), nil
+	case sinkFile:
+		s, err := newFileSink(cfg, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("creating file sink: %w", err)
+		}
+		return s, nil
+	case sinkS3:
+		s, err := newS3Sink(ctx, cfg, metadata)
+		if err != nil {
+			return nil, fmt.Errorf("creating S3 sink: %w", err)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown log sink %q", cfg.kind)
+	}
+}
+
+// credentialGetterFunc returns the credentials for the default OpenSearch instance.
+type credentialGetterFunc func(ctx context.Context) (credentials, error)
+
+// isSinkInfoKey returns true if the info key configures the log sink.
+func isSinkInfoKey(key string) bool {
+	return key == sinkInfoKey || strings.HasPrefix(key, sinkOptionPrefix)
+}
+
+func supportedSinks() []string {
+	sinks := make([]string, 0, len(sinkOptions))
+	for sink := range sinkOptions {
+		sinks = append(sinks, sink)
+	}
+	slices.Sort(sinks)
+	return sinks
+}
@@ -0,0 +1,409 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinkConfigFromInfo(t *testing.T) {
+	testCases := map[string]struct {
+		infoMap map[string]string
+		wantCfg sinkConfig
+		wantErr bool
+	}{
+		"default is opensearch": {
+			infoMap: map[string]string{"logcollect": "true"},
+			wantCfg: sinkConfig{kind: sinkOpenSearch, endpoint: defaultOpenSearchEndpoint},
+		},
+		"opensearch with custom endpoint": {
+			infoMap: map[string]string{
+				"logcollect.sink":          "opensearch",
+				"logcollect.sink.endpoint": "https://opensearch.local:9200",
+				"logcollect.sink.username": "user",
+				"logcollect.sink.password": "pass",
+			},
+			wantCfg: sinkConfig{kind: sinkOpenSearch, endpoint: "https://opensearch.local:9200", username: "user", password: "pass"},
+		},
+		"otlp": {
+			infoMap: map[string]string{
+				"logcollect.sink":          "otlp",
+				"logcollect.sink.endpoint": "http://otel-collector:4318",
+			},
+			wantCfg: sinkConfig{kind: sinkOTLP, endpoint: "http://otel-collector:4318"},
+		},
+		"otlp without endpoint": {
+			infoMap: map[string]string{"logcollect.sink": "otlp"},
+			wantErr: true,
+		},
+		"loki": {
+			infoMap: map[string]string{
+				"logcollect.sink":          "loki",
+				"logcollect.sink.endpoint": "http://loki:3100",
+			},
+			wantCfg: sinkConfig{kind: sinkLoki, endpoint: "http://loki:3100"},
+		},
+		"loki with invalid endpoint": {
+			infoMap: map[string]string{
+				"logcollect.sink":          "loki",
+				"logcollect.sink.endpoint": "loki:3100",
+			},
+			wantErr: true,
+		},
+		"file with default path": {
+			infoMap: map[string]string{"logcollect.sink": "file"},
+			wantCfg: sinkConfig{kind: sinkFile, path: defaultFileSinkPath},
+		},
+		"file with unsupported option": {
+			infoMap: map[string]string{
+				"logcollect.sink":          "file",
+				"logcollect.sink.endpoint": "http://loki:3100",
+			},
+			wantErr: true,
+		},
+		"s3": {
+			infoMap: map[string]string{
+				"logcollect.sink":                   "s3",
+				"logcollect.sink.bucket":            "logs",
+				"logcollect.sink.region":            "us-east-1",
+				"logcollect.sink.prefix":            "cluster",
+				"logcollect.sink.endpoint":          "http://minio:9000",
+				"logcollect.sink.access-key-id":     "id",
+				"logcollect.sink.secret-access-key": "secret",
+			},
+			wantCfg: sinkConfig{
+				kind:            sinkS3,
+				endpoint:        "http://minio:9000",
+				bucket:          "logs",
+				region:          "us-east-1",
+				prefix:          "cluster",
+				accessKeyID:     "id",
+				secretAccessKey: "secret",
+			},
+		},
+		"s3 without bucket": {
+			infoMap: map[string]string{"logcollect.sink": "s3"},
+			wantErr: true,
+		},
+		"s3 with incomplete credentials": {
+			infoMap: map[string]string{
+				"logcollect.sink":               "s3",
+				"logcollect.sink.bucket":        "logs",
+				"logcollect.sink.access-key-id": "id",
+			},
+			wantErr: true,
+		},
+		"unknown sink": {
+			infoMap: map[string]string{"logcollect.sink": "syslog"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cfg, err := sinkConfigFromInfo(tc.infoMap)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantCfg, cfg)
+		})
+	}
+}
+
+func TestNewSinkOpenSearchCredentials(t *testing.T) {
+	testCases := map[string]struct {
+		cfg          sinkConfig
+		creds        credentialGetterFunc
+		wantUsername string
+		wantErr      bool
+	}{
+		"credentials from cloud": {
+			cfg: sinkConfig{kind: sinkOpenSearch, endpoint: defaultOpenSearchEndpoint},
+			creds: func(context.Context) (credentials, error) {
+				return credentials{Username: "cloud", Password: "pass"}, nil
+			},
+			wantUsername: "cloud",
+		},
+		"configured credentials": {
+			cfg: sinkConfig{kind: sinkOpenSearch, endpoint: defaultOpenSearchEndpoint, username: "user", password: "pass"},
+			creds: func(context.Context) (credentials, error) {
+				return credentials{}, errors.New("failed")
+			},
+			wantUsername: "user",
+		},
+		"getting credentials fails": {
+			cfg: sinkConfig{kind: sinkOpenSearch, endpoint: defaultOpenSearchEndpoint},
+			creds: func(context.Context) (credentials, error) {
+				return credentials{}, errors.New("failed")
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			s, err := newSink(context.Background(), tc.cfg, nil, tc.creds)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantUsername, s.(*openSearchSink).poster.username)
+		})
+	}
+}
+
+func TestOpenSearchSink(t *testing.T) {
+	testCases := map[string]struct {
+		response string
+		status   int
+		wantErr  bool
+	}{
+		"success": {
+			response: `{"errors":false}`,
+			status:   http.StatusOK,
+		},
+		"rejected entries": {
+			response: `{"errors":true}`,
+			status:   http.StatusOK,
+			wantErr:  true,
+		},
+		"unauthorized": {
+			status:  http.StatusUnauthorized,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var gotLines []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal("/_bulk", r.URL.Path)
+				user, pass, ok := r.BasicAuth()
+				assert.True(ok)
+				assert.Equal("user", user)
+				assert.Equal("pass", pass)
+				gotLines = readLines(t, r.Body)
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			s := newOpenSearchSink(sinkConfig{endpoint: server.URL, username: "user", password: "pass"}, map[string]string{"role": "worker"})
+			err := s.Write(context.Background(), testEntries())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			require.Len(gotLines, 4)
+			assert.JSONEq(`{"index":{"_index":"logs-2023.44"}}`, gotLines[0])
+			var record archiveRecord
+			require.NoError(json.Unmarshal([]byte(gotLines[1]), &record))
+			assert.Equal("first", record.Message)
+			assert.Equal("worker", record.Metadata["role"])
+			assert.Equal("kubelet.service", record.Fields["systemd.unit"])
+		})
+	}
+}
+
+func TestOTLPSink(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var got otlpExportLogsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/v1/logs", r.URL.Path)
+		assert.Equal("application/json", r.Header.Get("Content-Type"))
+		assert.NoError(json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	s := newOTLPSink(sinkConfig{endpoint: server.URL}, map[string]string{"role": "worker", "name": "node-0"})
+	require.NoError(s.Write(context.Background(), testEntries()))
+
+	require.Len(got.ResourceLogs, 1)
+	assert.Equal([]otlpKeyValue{
+		{Key: "name", Value: otlpValue{StringValue: "node-0"}},
+		{Key: "role", Value: otlpValue{StringValue: "worker"}},
+	}, got.ResourceLogs[0].Resource.Attributes)
+	require.Len(got.ResourceLogs[0].ScopeLogs, 1)
+	records := got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(records, 2)
+	assert.Equal("first", records[0].Body.StringValue)
+	assert.Equal("1699000000000000000", records[0].TimeUnixNano)
+}
+
+func TestLokiSink(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var got lokiPushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/loki/api/v1/push", r.URL.Path)
+		assert.NoError(json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	entries := testEntries()
+	// entries out of order must be sorted within their stream
+	entries = append(entries, entry{
+		Time:    entries[0].Time.Add(-time.Second),
+		Message: "earlier",
+		Fields:  map[string]string{"source": "journal", "systemd.unit": "kubelet.service"},
+	})
+
+	s := newLokiSink(sinkConfig{endpoint: server.URL}, map[string]string{"role": "worker"})
+	require.NoError(s.Write(context.Background(), entries))
+
+	require.Len(got.Streams, 2)
+	assert.Equal(map[string]string{"role": "worker", "source": "journal", "systemd_unit": "kubelet.service"}, got.Streams[0].Stream)
+	require.Len(got.Streams[0].Values, 2)
+	assert.Equal("earlier", got.Streams[0].Values[0][1])
+	assert.Equal(`first syslog_identifier="kubelet"`, got.Streams[0].Values[1][1])
+	assert.Equal(map[string]string{
+		"role":                      "worker",
+		"source":                    "pods",
+		"kubernetes_namespace":      "kube-system",
+		"kubernetes_container_name": "cilium",
+	}, got.Streams[1].Stream)
+}
+
+func TestFileSink(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "logs", "logs.jsonl")
+	s, err := newFileSink(sinkConfig{path: path}, map[string]string{"role": "worker"})
+	require.NoError(err)
+	require.NoError(s.Write(context.Background(), testEntries()))
+	require.NoError(s.Write(context.Background(), testEntries()[:1]))
+	require.NoError(s.Close())
+
+	file, err := os.Open(path)
+	require.NoError(err)
+	defer file.Close()
+	stat, err := file.Stat()
+	require.NoError(err)
+	assert.Equal(os.FileMode(0o600), stat.Mode().Perm())
+
+	lines := readLines(t, file)
+	require.Len(lines, 3)
+	var record archiveRecord
+	require.NoError(json.Unmarshal([]byte(lines[2]), &record))
+	assert.Equal("first", record.Message)
+	assert.Equal(map[string]string{"role": "worker"}, record.Metadata)
+}
+
+func TestS3Sink(t *testing.T) {
+	testCases := map[string]struct {
+		client  *stubS3Client
+		wantErr bool
+	}{
+		"success": {
+			client: &stubS3Client{},
+		},
+		"upload fails": {
+			client:  &stubS3Client{putErr: errors.New("failed")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			s := &s3Sink{
+				client:   tc.client,
+				bucket:   "logs",
+				prefix:   "cluster",
+				metadata: map[string]string{"name": "node-0"},
+			}
+			err := s.Write(context.Background(), testEntries())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			require.Len(tc.client.puts, 1)
+			put := tc.client.puts[0]
+			assert.Equal("logs", *put.Bucket)
+			assert.True(strings.HasPrefix(*put.Key, "cluster/node-0/"))
+			assert.True(strings.HasSuffix(*put.Key, "-000001.jsonl.gz"))
+
+			gz, err := gzip.NewReader(put.Body)
+			require.NoError(err)
+			assert.Len(readLines(t, gz), 2)
+		})
+	}
+}
+
+type stubS3Client struct {
+	puts   []*s3.PutObjectInput
+	putErr error
+}
+
+func (c *stubS3Client) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	c.puts = append(c.puts, params)
+	return &s3.PutObjectOutput{}, c.putErr
+}
+
+func testEntries() []entry {
+	now := time.Unix(1699000000, 0).UTC()
+	return []entry{
+		{
+			Time:    now,
+			Message: "first",
+			Fields:  map[string]string{"source": "journal", "systemd.unit": "kubelet.service", "syslog.identifier": "kubelet"},
+		},
+		{
+			Time:    now.Add(time.Second),
+			Message: "second",
+			Fields:  map[string]string{"source": "pods", "kubernetes.namespace": "kube-system", "kubernetes.container_name": "cilium"},
+		},
+	}
+}
+
+func readLines(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
@@ -0,0 +1,278 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// podLogGlob matches the container logs written by the kubelet, as seen from the host.
+	podLogGlob = "/run/state/var/log/pods/*/*/*.log"
+	// podLogPollInterval is the interval in which pod log files are checked for new lines.
+	podLogPollInterval = time.Second
+)
+
+// journalSource reads the systemd journal of the current boot by following journalctl's JSON output.
+type journalSource struct{}
+
+// Name returns the name of the source.
+func (journalSource) Name() string {
+	return "journal"
+}
+
+// Run follows the journal until the context is canceled.
+func (journalSource) Run(ctx context.Context, out chan<- entry) error {
+	cmd := exec.CommandContext(ctx, "journalctl", "--boot", "--lines=all", "--follow", "--output=json")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("getting journalctl output: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting journalctl: %w", err)
+	}
+
+	readErr := readJournal(ctx, stdout, out)
+	if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("running journalctl: %w", err)
+	}
+	return readErr
+}
+
+// readJournal parses the journal entries in journalctl's JSON output format from r.
+func readJournal(ctx context.Context, r io.Reader, out chan<- entry) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		e, err := parseJournalEntry(scanner.Bytes())
+		if err != nil {
+			continue // skip entries we can't make sense of
+		}
+		select {
+		case out <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return scanner.Err()
+}
+
+// parseJournalEntry parses a single line of journalctl's JSON output.
+func parseJournalEntry(line []byte) (entry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return entry{}, fmt.Errorf("unmarshaling journal entry: %w", err)
+	}
+
+	timestamp, err := strconv.ParseInt(journalField(fields, "__REALTIME_TIMESTAMP"), 10, 64)
+	if err != nil {
+		return entry{}, fmt.Errorf("parsing journal timestamp: %w", err)
+	}
+
+	e := entry{
+		Time:    time.UnixMicro(timestamp).UTC(),
+		Message: journalField(fields, "MESSAGE"),
+		Fields:  map[string]string{"source": "journal"},
+	}
+	for journalKey, fieldKey := range map[string]string{
+		"_SYSTEMD_UNIT":     "systemd.unit",
+		"SYSLOG_IDENTIFIER": "syslog.identifier",
+		"PRIORITY":          "syslog.priority",
+		"_HOSTNAME":         "host.hostname",
+		"_PID":              "process.pid",
+	} {
+		if value := journalField(fields, journalKey); value != "" {
+			e.Fields[fieldKey] = value
+		}
+	}
+	return e, nil
+}
+
+// journalField returns the value of a journal field as string.
+// journalctl encodes fields that aren't valid UTF-8 as an array of bytes.
+func journalField(fields map[string]json.RawMessage, key string) string {
+	raw, ok := fields[key]
+	if !ok {
+		return ""
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return str
+	}
+	var ints []int
+	if err := json.Unmarshal(raw, &ints); err == nil {
+		b := make([]byte, 0, len(ints))
+		for _, i := range ints {
+			b = append(b, byte(i))
+		}
+		return string(b)
+	}
+	return ""
+}
+
+// podLogSource tails the container log files written by the kubelet.
+type podLogSource struct {
+	glob         string
+	pollInterval time.Duration
+	// offsets holds the read offset per file.
+	offsets map[string]int64
+}
+
+func newPodLogSource() *podLogSource {
+	return &podLogSource{
+		glob:         podLogGlob,
+		pollInterval: podLogPollInterval,
+		offsets:      make(map[string]int64),
+	}
+}
+
+// Name returns the name of the source.
+func (s *podLogSource) Name() string {
+	return "pods"
+}
+
+// Run polls the pod log files for new lines until the context is canceled.
+func (s *podLogSource) Run(ctx context.Context, out chan<- entry) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.poll(ctx, out); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll reads all lines that were appended to the pod log files since the last poll.
+func (s *podLogSource) poll(ctx context.Context, out chan<- entry) error {
+	paths, err := filepath.Glob(s.glob)
+	if err != nil {
+		return fmt.Errorf("listing pod logs: %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		seen[path] = struct{}{}
+		if err := s.readFile(ctx, path, out); err != nil {
+			if errors.Is(err, ctx.Err()) {
+				return err
+			}
+			continue // log files are rotated and removed by the kubelet at any time
+		}
+	}
+
+	// forget about removed files
+	for path := range s.offsets {
+		if _, ok := seen[path]; !ok {
+			delete(s.offsets, path)
+		}
+	}
+	return nil
+}
+
+func (s *podLogSource) readFile(ctx context.Context, path string, out chan<- entry) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset := s.offsets[path]
+	if stat.Size() < offset {
+		offset = 0 // file was truncated
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	fields := podLogFields(path)
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// incomplete lines are read again on the next poll
+			break
+		}
+		offset += int64(len(line))
+
+		e, err := parseCRILogLine(strings.TrimSuffix(line, "\n"), fields)
+		if err != nil {
+			continue
+		}
+		select {
+		case out <- e:
+		case <-ctx.Done():
+			s.offsets[path] = offset
+			return ctx.Err()
+		}
+	}
+	s.offsets[path] = offset
+	return nil
+}
+
+// podLogFields returns the Kubernetes fields encoded in a pod log path,
+// which has the form /var/log/pods/<namespace>_<pod>_<uid>/<container>/<restart count>.log.
+func podLogFields(path string) map[string]string {
+	fields := map[string]string{
+		"source":   "pods",
+		"log.file": path,
+	}
+	container := filepath.Base(filepath.Dir(path))
+	pod := strings.SplitN(filepath.Base(filepath.Dir(filepath.Dir(path))), "_", 3)
+	if len(pod) == 3 {
+		fields["kubernetes.namespace"] = pod[0]
+		fields["kubernetes.pod_name"] = pod[1]
+		fields["kubernetes.uid"] = pod[2]
+	}
+	fields["kubernetes.container_name"] = container
+	return fields
+}
+
+// parseCRILogLine parses a line in the CRI log format: "<RFC3339Nano time> <stream> <tag> <message>".
+func parseCRILogLine(line string, fields map[string]string) (entry, error) {
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) < 3 {
+		return entry{}, fmt.Errorf("invalid CRI log line %q", line)
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return entry{}, fmt.Errorf("parsing CRI log timestamp: %w", err)
+	}
+
+	e := entry{
+		Time:   timestamp.UTC(),
+		Fields: make(map[string]string, len(fields)+1),
+	}
+	if len(parts) == 4 {
+		e.Message = parts[3]
+	}
+	for k, v := range fields {
+		e.Fields[k] = v
+	}
+	e.Fields["stream"] = parts[1]
+	return e, nil
+}
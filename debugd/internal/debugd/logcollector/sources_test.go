@@ -0,0 +1,195 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package logcollector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJournalEntry(t *testing.T) {
+	testCases := map[string]struct {
+		line      string
+		wantEntry entry
+		wantErr   bool
+	}{
+		"full entry": {
+			line: `{"__REALTIME_TIMESTAMP":"1699000000000001","MESSAGE":"Started kubelet","_SYSTEMD_UNIT":"kubelet.service",` +
+				`"SYSLOG_IDENTIFIER":"kubelet","PRIORITY":"6","_HOSTNAME":"node-0","_PID":"42"}`,
+			wantEntry: entry{
+				Time:    time.UnixMicro(1699000000000001).UTC(),
+				Message: "Started kubelet",
+				Fields: map[string]string{
+					"source":            "journal",
+					"systemd.unit":      "kubelet.service",
+					"syslog.identifier": "kubelet",
+					"syslog.priority":   "6",
+					"host.hostname":     "node-0",
+					"process.pid":       "42",
+				},
+			},
+		},
+		"binary message": {
+			line: `{"__REALTIME_TIMESTAMP":"1699000000000000","MESSAGE":[104,105]}`,
+			wantEntry: entry{
+				Time:    time.UnixMicro(1699000000000000).UTC(),
+				Message: "hi",
+				Fields:  map[string]string{"source": "journal"},
+			},
+		},
+		"missing timestamp": {
+			line:    `{"MESSAGE":"hello"}`,
+			wantErr: true,
+		},
+		"invalid json": {
+			line:    `MESSAGE=hello`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			e, err := parseJournalEntry([]byte(tc.line))
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantEntry, e)
+		})
+	}
+}
+
+func TestReadJournal(t *testing.T) {
+	assert := assert.New(t)
+
+	journal := strings.Join([]string{
+		`{"__REALTIME_TIMESTAMP":"1699000000000000","MESSAGE":"first"}`,
+		`not json`,
+		`{"__REALTIME_TIMESTAMP":"1699000000000001","MESSAGE":"second"}`,
+	}, "\n")
+	out := make(chan entry, 3)
+
+	assert.NoError(readJournal(context.Background(), strings.NewReader(journal), out))
+	close(out)
+	var messages []string
+	for e := range out {
+		messages = append(messages, e.Message)
+	}
+	assert.Equal([]string{"first", "second"}, messages)
+}
+
+func TestParseCRILogLine(t *testing.T) {
+	testCases := map[string]struct {
+		line      string
+		wantEntry entry
+		wantErr   bool
+	}{
+		"full line": {
+			line: "2023-11-03T08:26:40.123456789Z stderr F level=info msg=started",
+			wantEntry: entry{
+				Time:    time.Date(2023, 11, 3, 8, 26, 40, 123456789, time.UTC),
+				Message: "level=info msg=started",
+				Fields:  map[string]string{"source": "pods", "stream": "stderr"},
+			},
+		},
+		"empty message": {
+			line: "2023-11-03T08:26:40Z stdout F",
+			wantEntry: entry{
+				Time:   time.Date(2023, 11, 3, 8, 26, 40, 0, time.UTC),
+				Fields: map[string]string{"source": "pods", "stream": "stdout"},
+			},
+		},
+		"invalid timestamp": {
+			line:    "yesterday stdout F hello",
+			wantErr: true,
+		},
+		"too short": {
+			line:    "2023-11-03T08:26:40Z",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			e, err := parseCRILogLine(tc.line, map[string]string{"source": "pods"})
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantEntry, e)
+		})
+	}
+}
+
+func TestPodLogFields(t *testing.T) {
+	assert := assert.New(t)
+
+	path := "/run/state/var/log/pods/kube-system_cilium-abcde_1234-5678/cilium-agent/0.log"
+	assert.Equal(map[string]string{
+		"source":                    "pods",
+		"log.file":                  path,
+		"kubernetes.namespace":      "kube-system",
+		"kubernetes.pod_name":       "cilium-abcde",
+		"kubernetes.uid":            "1234-5678",
+		"kubernetes.container_name": "cilium-agent",
+	}, podLogFields(path))
+}
+
+func TestPodLogSourcePoll(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir := filepath.Join(t.TempDir(), "kube-system_cilium-abcde_1234", "cilium-agent")
+	require.NoError(os.MkdirAll(dir, 0o755))
+	path := filepath.Join(dir, "0.log")
+
+	s := newPodLogSource()
+	s.glob = filepath.Join(filepath.Dir(filepath.Dir(dir)), "*", "*", "*.log")
+	out := make(chan entry, 10)
+	poll := func() []string {
+		require.NoError(s.poll(context.Background(), out))
+		var messages []string
+		for len(out) > 0 {
+			messages = append(messages, (<-out).Message)
+		}
+		return messages
+	}
+
+	// incomplete lines are only read once they are terminated
+	require.NoError(os.WriteFile(path, []byte("2023-11-03T08:26:40Z stdout F first\n2023-11-03T08:26:41Z stdout F sec"), 0o644))
+	assert.Equal([]string{"first"}, poll())
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(err)
+	_, err = file.WriteString("ond\n")
+	require.NoError(err)
+	require.NoError(file.Close())
+	assert.Equal([]string{"second"}, poll())
+	assert.Empty(poll())
+
+	// truncated files are read from the start
+	require.NoError(os.WriteFile(path, []byte("2023-11-03T08:26:42Z stdout F third\n"), 0o644))
+	assert.Equal([]string{"third"}, poll())
+
+	// removed files are forgotten
+	require.NoError(os.Remove(path))
+	assert.Empty(poll())
+	assert.Empty(s.offsets)
+}
@@ -8,39 +8,56 @@ SPDX-License-Identifier: AGPL-3.0-only
 package server
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"io"
 	"net"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/accesstoken"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/deploy"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/diagnostics"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/info"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/logcollector"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/filetransfer"
 	pb "github.com/edgelesssys/constellation/v2/debugd/service"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
+// redactedInfoValue replaces the values of secret info keys returned by GetInfo.
+const redactedInfoValue = "<redacted>"
+
 type debugdServer struct {
 	log            *logger.Logger
 	serviceManager serviceManager
 	transfer       fileTransferer
+	downloader     downloader
+	diagnoser      diagnoser
 	info           *info.Map
 
 	pb.UnimplementedDebugdServer
 }
 
 // New creates a new debugdServer according to the gRPC spec.
-func New(log *logger.Logger, serviceManager serviceManager, transfer fileTransferer, infos *info.Map) pb.DebugdServer {
+func New(log *logger.Logger, serviceManager serviceManager, transfer fileTransferer, downloader downloader,
+	diagnoser diagnoser, infos *info.Map,
+) pb.DebugdServer {
 	return &debugdServer{
 		log:            log,
 		serviceManager: serviceManager,
 		transfer:       transfer,
+		downloader:     downloader,
+		diagnoser:      diagnoser,
 		info:           infos,
 	}
 }
@@ -73,6 +90,7 @@ func (s *debugdServer) SetInfo(_ context.Context, req *pb.SetInfoRequest) (*pb.S
 }
 
 // GetInfo returns the info of the debugd instance.
+// The values of credentials, like the password of the log sink, are redacted.
 func (s *debugdServer) GetInfo(_ context.Context, _ *pb.GetInfoRequest) (*pb.GetInfoResponse, error) {
 	s.log.Infof("Received GetInfo request")
 
@@ -80,6 +98,11 @@ func (s *debugdServer) GetInfo(_ context.Context, _ *pb.GetInfoRequest) (*pb.Get
 	if err != nil {
 		return nil, err
 	}
+	for _, i := range info {
+		if logcollector.IsSecretInfoKey(i.Key) {
+			i.Value = redactedInfoValue
+		}
+	}
 
 	return &pb.GetInfoResponse{Info: info}, nil
 }
@@ -131,9 +154,40 @@ func (s *debugdServer) UploadFiles(stream pb.Debugd_UploadFilesServer) error {
 }
 
 // DownloadFiles streams the previously received files to other instances.
-func (s *debugdServer) DownloadFiles(_ *pb.DownloadFilesRequest, stream pb.Debugd_DownloadFilesServer) error {
+// Files the other instance already has are skipped.
+func (s *debugdServer) DownloadFiles(req *pb.DownloadFilesRequest, stream pb.Debugd_DownloadFilesServer) error {
 	s.log.Infof("Sending files to other instance")
-	return s.transfer.SendFiles(stream)
+	return s.transfer.SendFiles(stream, filetransfer.SendOptions{
+		ReceiverStates: filetransfer.FileStatesFromProto(req.States),
+		Compression:    req.Compression,
+	})
+}
+
+// GetFileStates returns size and digest of the requested files, so a sender can skip unchanged files
+// and resume partial transfers.
+func (s *debugdServer) GetFileStates(_ context.Context, req *pb.GetFileStatesRequest) (*pb.GetFileStatesResponse, error) {
+	s.log.Infof("Received GetFileStates request")
+	states := s.transfer.FileStates(req.TargetPaths)
+	return &pb.GetFileStatesResponse{States: filetransfer.FileStatesToProto(states)}, nil
+}
+
+// FetchFiles downloads the files from another debugd instance that already received them.
+func (s *debugdServer) FetchFiles(ctx context.Context, req *pb.FetchFilesRequest) (*pb.FetchFilesResponse, error) {
+	log := s.log.With(zap.String("peer", req.Peer))
+	log.Infof("Received FetchFiles request")
+
+	err := s.downloader.DownloadDeployment(ctx, req.Peer)
+	switch {
+	case err == nil:
+		log.Infof("Fetching files succeeded")
+	case errors.Is(err, filetransfer.ErrReceiveRunning):
+		log.Warnf("Receiving files already in progress")
+		return &pb.FetchFilesResponse{Status: pb.FetchFilesStatus_FETCH_FILES_ALREADY_STARTED}, nil
+	default:
+		log.With(zap.Error(err)).Errorf("Fetching files failed")
+		return &pb.FetchFilesResponse{Status: pb.FetchFilesStatus_FETCH_FILES_FAILED}, nil
+	}
+	return &pb.FetchFilesResponse{Status: pb.FetchFilesStatus_FETCH_FILES_SUCCESS}, nil
 }
 
 // UploadSystemServiceUnits receives systemd service units, writes them to a service file and schedules a daemon-reload.
@@ -148,6 +202,113 @@ func (s *debugdServer) UploadSystemServiceUnits(ctx context.Context, in *pb.Uplo
 	return &pb.UploadSystemdServiceUnitsResponse{Status: pb.UploadSystemdServiceUnitsStatus_UPLOAD_SYSTEMD_SERVICE_UNITS_SUCCESS}, nil
 }
 
+// GetJournal streams the journal of the requested systemd units.
+func (s *debugdServer) GetJournal(req *pb.GetJournalRequest, stream pb.Debugd_GetJournalServer) error {
+	log := s.log.With(zap.Strings("units", req.Units), zap.Bool("follow", req.Follow))
+	log.Infof("Received GetJournal request")
+	if err := s.authorize(stream.Context()); err != nil {
+		log.With(zap.Error(err)).Warnf("Unauthorized GetJournal request")
+		return err
+	}
+
+	return s.diagnoser.Journal(stream.Context(), req.Units, req.Lines, req.Follow, func(line string) error {
+		return stream.Send(&pb.JournalEntry{Line: line})
+	})
+}
+
+// Exec runs an allow-listed diagnostic command and streams its output.
+func (s *debugdServer) Exec(req *pb.ExecRequest, stream pb.Debugd_ExecServer) error {
+	log := s.log.With(zap.Strings("command", req.Command))
+	log.Infof("Received Exec request")
+	if err := s.authorize(stream.Context()); err != nil {
+		log.With(zap.Error(err)).Warnf("Unauthorized Exec request")
+		return err
+	}
+
+	out := &execOutputStream{stream: stream}
+	exitCode, err := s.diagnoser.Exec(stream.Context(), req.Command, out.stdout(), out.stderr())
+	if errors.Is(err, diagnostics.ErrCommandNotAllowed) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Executing command failed")
+		return err
+	}
+	return stream.Send(&pb.ExecResponse{Kind: &pb.ExecResponse_ExitCode{ExitCode: int32(exitCode)}})
+}
+
+// GetDiagnosticBundle streams a gzip compressed tar archive with diagnostic information of the instance.
+func (s *debugdServer) GetDiagnosticBundle(_ *pb.GetDiagnosticBundleRequest, stream pb.Debugd_GetDiagnosticBundleServer) error {
+	s.log.Infof("Received GetDiagnosticBundle request")
+	if err := s.authorize(stream.Context()); err != nil {
+		s.log.With(zap.Error(err)).Warnf("Unauthorized GetDiagnosticBundle request")
+		return err
+	}
+
+	chunks := bufio.NewWriterSize(chunkStreamWriter{stream: stream}, debugd.Chunksize)
+	if err := s.diagnoser.Bundle(stream.Context(), chunks); err != nil {
+		s.log.With(zap.Error(err)).Errorf("Creating diagnostic bundle failed")
+		return err
+	}
+	if err := chunks.Flush(); err != nil {
+		return err
+	}
+	return stream.Send(&pb.Chunk{Last: true})
+}
+
+// authorize checks that the request carries the access token configured through the info.
+func (s *debugdServer) authorize(ctx context.Context) error {
+	// without info, no access token is configured and Verify rejects every request
+	digest, _, _ := s.info.Get(accesstoken.InfoKey)
+	return accesstoken.Verify(ctx, digest)
+}
+
+// execOutputStream sends the output of a command as ExecResponse messages.
+type execOutputStream struct {
+	mux    sync.Mutex
+	stream pb.Debugd_ExecServer
+}
+
+func (o *execOutputStream) stdout() io.Writer {
+	return execOutputWriter(func(p []byte) error {
+		return o.send(&pb.ExecResponse{Kind: &pb.ExecResponse_Stdout{Stdout: p}})
+	})
+}
+
+func (o *execOutputStream) stderr() io.Writer {
+	return execOutputWriter(func(p []byte) error {
+		return o.send(&pb.ExecResponse{Kind: &pb.ExecResponse_Stderr{Stderr: p}})
+	})
+}
+
+// send sends a message. Output of stdout and stderr is copied concurrently, but the stream doesn't support concurrent sends.
+func (o *execOutputStream) send(resp *pb.ExecResponse) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	return o.stream.Send(resp)
+}
+
+type execOutputWriter func(p []byte) error
+
+func (w execOutputWriter) Write(p []byte) (int, error) {
+	if err := w(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// chunkStreamWriter sends everything written to it as chunks.
+type chunkStreamWriter struct {
+	stream pb.Debugd_GetDiagnosticBundleServer
+}
+
+func (w chunkStreamWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&pb.Chunk{Content: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // Start will start the gRPC server as goroutine.
 func Start(log *logger.Logger, wg *sync.WaitGroup, serv pb.DebugdServer) {
 	wg.Add(1)
@@ -180,6 +341,17 @@ type serviceManager interface {
 
 type fileTransferer interface {
 	RecvFiles(stream filetransfer.RecvFilesStream) error
-	SendFiles(stream filetransfer.SendFilesStream) error
+	SendFiles(stream filetransfer.SendFilesStream, opts filetransfer.SendOptions) error
 	GetFiles() []filetransfer.FileStat
+	FileStates(paths []string) map[string]filetransfer.FileState
+}
+
+type downloader interface {
+	DownloadDeployment(ctx context.Context, ip string) error
+}
+
+type diagnoser interface {
+	Journal(ctx context.Context, units []string, lines uint32, follow bool, send func(line string) error) error
+	Exec(ctx context.Context, command []string, stdout, stderr io.Writer) (int, error)
+	Bundle(ctx context.Context, w io.Writer) error
 }
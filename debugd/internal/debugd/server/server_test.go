@@ -7,6 +7,7 @@ SPDX-License-Identifier: AGPL-3.0-only
 package server
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
@@ -14,7 +15,9 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/accesstoken"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/deploy"
+	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/diagnostics"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/debugd/info"
 	"github.com/edgelesssys/constellation/v2/debugd/internal/filetransfer"
 	pb "github.com/edgelesssys/constellation/v2/debugd/service"
@@ -25,11 +28,16 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
+	goleak.VerifyTestMain(m,
+		// https://github.com/census-instrumentation/opencensus-go/issues/1262
+		goleak.IgnoreTopFunction("go.opencensus.io/stats/view.(*worker).start"),
+	)
 }
 
 func TestSetInfo(t *testing.T) {
@@ -98,14 +106,32 @@ func TestGetInfo(t *testing.T) {
 	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
 
 	testCases := map[string]struct {
-		info    *info.Map
-		getInfo []*pb.Info
-		wantErr bool
+		info     *info.Map
+		getInfo  []*pb.Info
+		wantInfo map[string]string
+		wantErr  bool
 	}{
 		"get info works": {
 			getInfo: []*pb.Info{{Key: "foo", Value: "bar"}},
 			info:    info.NewMap(),
 		},
+		"sink credentials are redacted": {
+			getInfo: []*pb.Info{
+				{Key: "logcollect.sink", Value: "s3"},
+				{Key: "logcollect.sink.access-key-id", Value: "id"},
+				{Key: "logcollect.sink.secret-access-key", Value: "secret"},
+				{Key: "logcollect.sink.password", Value: "password"},
+				{Key: "qemu.opensearch-pw", Value: "password"},
+			},
+			wantInfo: map[string]string{
+				"logcollect.sink":                   "s3",
+				"logcollect.sink.access-key-id":     "id",
+				"logcollect.sink.password":          redactedInfoValue,
+				"logcollect.sink.secret-access-key": redactedInfoValue,
+				"qemu.opensearch-pw":                redactedInfoValue,
+			},
+			info: info.NewMap(),
+		},
 		"get empty info works": {
 			getInfo: []*pb.Info{},
 			info:    info.NewMap(),
@@ -145,6 +171,11 @@ func TestGetInfo(t *testing.T) {
 			} else {
 				assert.NoError(err)
 				assert.Equal(len(tc.getInfo), len(resp.Info))
+				for _, i := range resp.Info {
+					if want, ok := tc.wantInfo[i.Key]; ok {
+						assert.Equal(want, i.Value)
+					}
+				}
 			}
 		})
 	}
@@ -222,11 +253,25 @@ func TestDownloadFiles(t *testing.T) {
 		canSend           bool
 		wantRecvErr       bool
 		wantSendFileCalls int
+		wantSendOpts      filetransfer.SendOptions
 	}{
 		"download works": {
 			request:           &pb.DownloadFilesRequest{},
 			canSend:           true,
 			wantSendFileCalls: 1,
+			wantSendOpts:      filetransfer.SendOptions{ReceiverStates: map[string]filetransfer.FileState{}},
+		},
+		"receiver states and compression are passed on": {
+			request: &pb.DownloadFilesRequest{
+				States:      []*pb.FileState{{TargetPath: "file", Size: 4, Sha256: []byte{0x01}}},
+				Compression: pb.Compression_COMPRESSION_ZSTD,
+			},
+			canSend:           true,
+			wantSendFileCalls: 1,
+			wantSendOpts: filetransfer.SendOptions{
+				ReceiverStates: map[string]filetransfer.FileState{"file": {Size: 4, SHA256: []byte{0x01}}},
+				Compression:    pb.Compression_COMPRESSION_ZSTD,
+			},
 		},
 	}
 
@@ -258,10 +303,304 @@ func TestDownloadFiles(t *testing.T) {
 			require.NoError(err)
 
 			assert.Equal(tc.wantSendFileCalls, transfer.sendFilesCount)
+			assert.Equal(tc.wantSendOpts, transfer.sendOpts)
+		})
+	}
+}
+
+func TestGetFileStates(t *testing.T) {
+	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
+
+	testCases := map[string]struct {
+		states     map[string]filetransfer.FileState
+		wantStates []*pb.FileState
+	}{
+		"no files": {
+			wantStates: nil,
+		},
+		"files": {
+			states: map[string]filetransfer.FileState{
+				"fileB": {Size: 2, SHA256: []byte{0x02}},
+				"fileA": {Size: 1, SHA256: []byte{0x01}},
+			},
+			wantStates: []*pb.FileState{
+				{TargetPath: "fileA", Size: 1, Sha256: []byte{0x01}},
+				{TargetPath: "fileB", Size: 2, Sha256: []byte{0x02}},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			serv := debugdServer{
+				log:      logger.NewTest(t),
+				transfer: &stubTransfer{fileStates: tc.states},
+			}
+			grpcServ, conn, err := setupServerWithConn(endpoint, &serv)
+			require.NoError(err)
+			defer conn.Close()
+			client := pb.NewDebugdClient(conn)
+			resp, err := client.GetFileStates(context.Background(), &pb.GetFileStatesRequest{TargetPaths: []string{"fileA", "fileB"}})
+			grpcServ.GracefulStop()
+
+			require.NoError(err)
+			require.Len(resp.States, len(tc.wantStates))
+			for i := range tc.wantStates {
+				assert.Equal(tc.wantStates[i].TargetPath, resp.States[i].TargetPath)
+				assert.Equal(tc.wantStates[i].Size, resp.States[i].Size)
+				assert.Equal(tc.wantStates[i].Sha256, resp.States[i].Sha256)
+			}
+		})
+	}
+}
+
+func TestFetchFiles(t *testing.T) {
+	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
+
+	testCases := map[string]struct {
+		downloadErr error
+		wantStatus  pb.FetchFilesStatus
+	}{
+		"fetch works": {
+			wantStatus: pb.FetchFilesStatus_FETCH_FILES_SUCCESS,
+		},
+		"download already running": {
+			downloadErr: filetransfer.ErrReceiveRunning,
+			wantStatus:  pb.FetchFilesStatus_FETCH_FILES_ALREADY_STARTED,
+		},
+		"download fails": {
+			downloadErr: errors.New("download error"),
+			wantStatus:  pb.FetchFilesStatus_FETCH_FILES_FAILED,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			downloader := &stubDownloader{downloadErr: tc.downloadErr}
+			serv := debugdServer{
+				log:        logger.NewTest(t),
+				downloader: downloader,
+			}
+			grpcServ, conn, err := setupServerWithConn(endpoint, &serv)
+			require.NoError(err)
+			defer conn.Close()
+			client := pb.NewDebugdClient(conn)
+			resp, err := client.FetchFiles(context.Background(), &pb.FetchFilesRequest{Peer: "192.0.2.2"})
+			grpcServ.GracefulStop()
+
+			require.NoError(err)
+			assert.Equal(tc.wantStatus, resp.Status)
+			assert.Equal([]string{"192.0.2.2"}, downloader.ips)
 		})
 	}
 }
 
+func TestGetJournal(t *testing.T) {
+	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
+
+	testCases := map[string]struct {
+		infos      []*pb.Info
+		token      string
+		journalErr error
+		wantLines  []string
+		wantCode   codes.Code
+	}{
+		"journal works": {
+			infos:     []*pb.Info{{Key: accesstoken.InfoKey, Value: accesstoken.Digest("token")}},
+			token:     "token",
+			wantLines: []string{"first", "second"},
+		},
+		"journal fails": {
+			infos:      []*pb.Info{{Key: accesstoken.InfoKey, Value: accesstoken.Digest("token")}},
+			token:      "token",
+			journalErr: errors.New("journalctl failed"),
+			wantCode:   codes.Unknown,
+		},
+		"wrong token": {
+			infos:    []*pb.Info{{Key: accesstoken.InfoKey, Value: accesstoken.Digest("token")}},
+			token:    "other",
+			wantCode: codes.PermissionDenied,
+		},
+		"no token configured": {
+			infos:    []*pb.Info{},
+			token:    "token",
+			wantCode: codes.Unauthenticated,
+		},
+		"info not set": {
+			token:    "token",
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			infos := info.NewMap()
+			if tc.infos != nil {
+				require.NoError(infos.SetProto(tc.infos))
+			}
+			diag := &stubDiagnoser{journal: []string{"first", "second"}, journalErr: tc.journalErr}
+			serv := debugdServer{
+				log:       logger.NewTest(t),
+				diagnoser: diag,
+				info:      infos,
+			}
+			grpcServ, conn, err := setupServerWithConn(endpoint, &serv)
+			require.NoError(err)
+			defer conn.Close()
+			client := pb.NewDebugdClient(conn)
+
+			stream, err := client.GetJournal(context.Background(),
+				&pb.GetJournalRequest{Units: []string{"bootstrapper.service"}, Lines: 5},
+				grpc.PerRPCCredentials(accesstoken.Credentials(tc.token)),
+			)
+			require.NoError(err)
+			var lines []string
+			for {
+				var entry *pb.JournalEntry
+				entry, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				lines = append(lines, entry.Line)
+			}
+			grpcServ.GracefulStop()
+
+			if tc.wantCode != codes.OK {
+				assert.Equal(tc.wantCode, status.Code(err))
+				return
+			}
+			assert.ErrorIs(err, io.EOF)
+			assert.Equal(tc.wantLines, lines)
+			assert.Equal([]string{"bootstrapper.service"}, diag.units)
+		})
+	}
+}
+
+func TestExec(t *testing.T) {
+	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
+
+	testCases := map[string]struct {
+		token        string
+		execErr      error
+		wantStdout   string
+		wantStderr   string
+		wantExitCode int32
+		wantCode     codes.Code
+	}{
+		"exec works": {
+			token:        "token",
+			wantStdout:   "out",
+			wantStderr:   "err",
+			wantExitCode: 3,
+		},
+		"command not allowed": {
+			token:    "token",
+			execErr:  diagnostics.ErrCommandNotAllowed,
+			wantCode: codes.PermissionDenied,
+		},
+		"wrong token": {
+			token:    "other",
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			infos := info.NewMap()
+			require.NoError(infos.SetProto([]*pb.Info{{Key: accesstoken.InfoKey, Value: accesstoken.Digest("token")}}))
+			serv := debugdServer{
+				log:       logger.NewTest(t),
+				diagnoser: &stubDiagnoser{stdout: "out", stderr: "err", exitCode: 3, execErr: tc.execErr},
+				info:      infos,
+			}
+			grpcServ, conn, err := setupServerWithConn(endpoint, &serv)
+			require.NoError(err)
+			defer conn.Close()
+			client := pb.NewDebugdClient(conn)
+
+			stream, err := client.Exec(context.Background(),
+				&pb.ExecRequest{Command: []string{"tpm2_pcrread"}},
+				grpc.PerRPCCredentials(accesstoken.Credentials(tc.token)),
+			)
+			require.NoError(err)
+			var stdout, stderr string
+			var exitCode int32
+			for {
+				var resp *pb.ExecResponse
+				resp, err = stream.Recv()
+				if err != nil {
+					break
+				}
+				stdout += string(resp.GetStdout())
+				stderr += string(resp.GetStderr())
+				exitCode = resp.GetExitCode()
+			}
+			grpcServ.GracefulStop()
+
+			if tc.wantCode != codes.OK {
+				assert.Equal(tc.wantCode, status.Code(err))
+				return
+			}
+			assert.ErrorIs(err, io.EOF)
+			assert.Equal(tc.wantStdout, stdout)
+			assert.Equal(tc.wantStderr, stderr)
+			assert.Equal(tc.wantExitCode, exitCode)
+		})
+	}
+}
+
+func TestGetDiagnosticBundle(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
+
+	infos := info.NewMap()
+	require.NoError(infos.SetProto([]*pb.Info{{Key: accesstoken.InfoKey, Value: accesstoken.Digest("token")}}))
+	bundle := bytes.Repeat([]byte("bundle"), 1000)
+	serv := debugdServer{
+		log:       logger.NewTest(t),
+		diagnoser: &stubDiagnoser{bundle: bundle},
+		info:      infos,
+	}
+	grpcServ, conn, err := setupServerWithConn(endpoint, &serv)
+	require.NoError(err)
+	defer conn.Close()
+	client := pb.NewDebugdClient(conn)
+
+	stream, err := client.GetDiagnosticBundle(context.Background(), &pb.GetDiagnosticBundleRequest{},
+		grpc.PerRPCCredentials(accesstoken.Credentials("token")),
+	)
+	require.NoError(err)
+	var received []byte
+	var last bool
+	for {
+		var chunk *pb.Chunk
+		chunk, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		received = append(received, chunk.Content...)
+		last = chunk.Last
+	}
+	grpcServ.GracefulStop()
+
+	assert.ErrorIs(err, io.EOF)
+	assert.True(last)
+	assert.Equal(bundle, received)
+}
+
 func TestUploadSystemServiceUnits(t *testing.T) {
 	endpoint := "192.0.2.1:" + strconv.Itoa(constants.DebugdPort)
 
@@ -383,7 +722,9 @@ func dial(ctx context.Context, dialer netDialer, target string) (*grpc.ClientCon
 type stubTransfer struct {
 	recvFilesCount int
 	sendFilesCount int
+	sendOpts       filetransfer.SendOptions
 	files          []filetransfer.FileStat
+	fileStates     map[string]filetransfer.FileState
 	canSend        bool
 	recvFilesErr   error
 	sendFilesErr   error
@@ -394,11 +735,16 @@ func (t *stubTransfer) RecvFiles(_ filetransfer.RecvFilesStream) error {
 	return t.recvFilesErr
 }
 
-func (t *stubTransfer) SendFiles(_ filetransfer.SendFilesStream) error {
+func (t *stubTransfer) SendFiles(_ filetransfer.SendFilesStream, opts filetransfer.SendOptions) error {
 	t.sendFilesCount++
+	t.sendOpts = opts
 	return t.sendFilesErr
 }
 
+func (t *stubTransfer) FileStates(_ []string) map[string]filetransfer.FileState {
+	return t.fileStates
+}
+
 func (t *stubTransfer) GetFiles() []filetransfer.FileStat {
 	return t.files
 }
@@ -407,6 +753,58 @@ func (t *stubTransfer) CanSend() bool {
 	return t.canSend
 }
 
+type stubDownloader struct {
+	ips         []string
+	downloadErr error
+}
+
+func (d *stubDownloader) DownloadDeployment(_ context.Context, ip string) error {
+	d.ips = append(d.ips, ip)
+	return d.downloadErr
+}
+
+type stubDiagnoser struct {
+	units      []string
+	journal    []string
+	journalErr error
+	stdout     string
+	stderr     string
+	exitCode   int
+	execErr    error
+	bundle     []byte
+}
+
+func (d *stubDiagnoser) Journal(_ context.Context, units []string, _ uint32, _ bool, send func(line string) error) error {
+	d.units = units
+	if d.journalErr != nil {
+		return d.journalErr
+	}
+	for _, line := range d.journal {
+		if err := send(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *stubDiagnoser) Exec(_ context.Context, _ []string, stdout, stderr io.Writer) (int, error) {
+	if d.execErr != nil {
+		return 0, d.execErr
+	}
+	if _, err := io.WriteString(stdout, d.stdout); err != nil {
+		return 0, err
+	}
+	if _, err := io.WriteString(stderr, d.stderr); err != nil {
+		return 0, err
+	}
+	return d.exitCode, nil
+}
+
+func (d *stubDiagnoser) Bundle(_ context.Context, w io.Writer) error {
+	_, err := w.Write(d.bundle)
+	return err
+}
+
 func setupServerWithConn(endpoint string, serv *debugdServer) (*grpc.Server, *grpc.ClientConn, error) {
 	dialer := testdialer.NewBufconnDialer()
 	grpcServ := grpc.NewServer()
@@ -9,9 +9,12 @@ SPDX-License-Identifier: AGPL-3.0-only
 package filetransfer
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -39,8 +42,10 @@ type FileTransferer struct {
 	receiveStarted  bool
 	receiveFinished atomic.Bool
 	files           []FileStat
-	streamer        streamReadWriter
-	showProgress    bool
+	// abortedFiles are the files of the last failed receive. Their transfer may be resumed.
+	abortedFiles []FileStat
+	streamer     streamReadWriter
+	showProgress bool
 }
 
 // New creates a new FileTransferer.
@@ -53,8 +58,10 @@ func New(log *logger.Logger, streamer streamReadWriter, showProgress bool) *File
 }
 
 // SendFiles sends files to the given stream.
+// Files the receiver already has, according to opts.ReceiverStates, are skipped
+// and partially received files are resumed.
 // If the FileTransferer has not received any files to send, an error is returned.
-func (s *FileTransferer) SendFiles(stream SendFilesStream) error {
+func (s *FileTransferer) SendFiles(stream SendFilesStream, opts SendOptions) error {
 	if !s.receiveFinished.Load() {
 		return errors.New("cannot send files before receiving them")
 	}
@@ -63,7 +70,7 @@ func (s *FileTransferer) SendFiles(stream SendFilesStream) error {
 	defer s.fileMux.RUnlock()
 
 	for _, file := range s.files {
-		if err := s.handleFileSend(stream, file); err != nil {
+		if err := s.handleFileSend(stream, file, opts); err != nil {
 			return err
 		}
 	}
@@ -91,6 +98,33 @@ func (s *FileTransferer) RecvFiles(stream RecvFilesStream) (err error) {
 	return err
 }
 
+// FileStates returns the states of the given files on the local filesystem.
+// Files that don't exist or can't be read are left out.
+func (s *FileTransferer) FileStates(paths []string) map[string]FileState {
+	states := make(map[string]FileState, len(paths))
+	for _, path := range paths {
+		sum, size, err := s.streamer.Hash(path, -1)
+		if err != nil {
+			s.log.With(zap.Error(err)).Debugf("Not reporting state of %q", path)
+			continue
+		}
+		states[path] = FileState{Size: size, SHA256: sum}
+	}
+	return states
+}
+
+// AbortedFileStates returns the local states of the files of the last failed receive.
+// They can be passed to the sender to resume the transfer.
+func (s *FileTransferer) AbortedFileStates() map[string]FileState {
+	s.fileMux.RLock()
+	paths := make([]string, 0, len(s.abortedFiles))
+	for _, file := range s.abortedFiles {
+		paths = append(paths, file.TargetPath)
+	}
+	s.fileMux.RUnlock()
+	return s.FileStates(paths)
+}
+
 // GetFiles returns the a copy of the list of files that have been received.
 func (s *FileTransferer) GetFiles() []FileStat {
 	s.fileMux.RLock()
@@ -112,21 +146,54 @@ func (s *FileTransferer) SetFiles(files []FileStat) {
 	s.receiveFinished.Store(true)
 }
 
-func (s *FileTransferer) handleFileSend(stream SendFilesStream, file FileStat) error {
+func (s *FileTransferer) handleFileSend(stream SendFilesStream, file FileStat, opts SendOptions) error {
+	sum, size, err := s.streamer.Hash(file.SourcePath, -1)
+	if err != nil {
+		return fmt.Errorf("hashing %q: %w", file.SourcePath, err)
+	}
 	header := &pb.FileTransferMessage_Header{
 		Header: &pb.FileTransferHeader{
-			TargetPath: file.TargetPath,
-			Mode:       uint32(file.Mode),
+			TargetPath:  file.TargetPath,
+			Mode:        uint32(file.Mode),
+			Sha256:      sum,
+			Size:        uint64(size),
+			Compression: opts.Compression,
 		},
 	}
 	if file.OverrideServiceUnit != "" {
 		header.Header.OverrideServiceUnit = &file.OverrideServiceUnit
 	}
+
+	if have, ok := opts.ReceiverStates[file.TargetPath]; ok {
+		switch {
+		case have.Size == size && bytes.Equal(have.SHA256, sum):
+			header.Header.Unchanged = true
+		case have.Size > 0 && have.Size < size:
+			// resume if the receiver has a prefix of the file, e.g. after a dropped connection
+			prefixSum, _, err := s.streamer.Hash(file.SourcePath, have.Size)
+			if err != nil {
+				return fmt.Errorf("hashing %q: %w", file.SourcePath, err)
+			}
+			if bytes.Equal(have.SHA256, prefixSum) {
+				header.Header.Offset = uint64(have.Size)
+			}
+		}
+	}
+
 	if err := stream.Send(&pb.FileTransferMessage{Kind: header}); err != nil {
 		return err
 	}
+	switch {
+	case header.Header.Unchanged:
+		s.log.Infof("Skipping unchanged file %q", file.TargetPath)
+		return nil
+	case header.Header.Offset > 0:
+		s.log.Infof("Resuming transfer of %q at offset %d", file.TargetPath, header.Header.Offset)
+	}
+
 	sendChunkStream := &sendChunkStream{stream: stream}
-	return s.streamer.ReadStream(file.SourcePath, sendChunkStream, debugd.Chunksize, s.showProgress)
+	streamOpts := streamer.Options{Offset: int64(header.Header.Offset), Compression: opts.Compression}
+	return s.streamer.ReadStream(file.SourcePath, sendChunkStream, debugd.Chunksize, streamOpts, s.showProgress)
 }
 
 // handleFileRecv handles the file receive of a single file.
@@ -146,7 +213,6 @@ func (s *FileTransferer) handleFileRecv(stream RecvFilesStream) (bool, error) {
 	if header == nil {
 		return false, errors.New("first message must be a header message")
 	}
-	s.log.Infof("Starting file receive of %q", header.TargetPath)
 	s.addFile(FileStat{
 		SourcePath: header.TargetPath,
 		TargetPath: header.TargetPath,
@@ -158,10 +224,28 @@ func (s *FileTransferer) handleFileRecv(stream RecvFilesStream) (bool, error) {
 			return ""
 		}(),
 	})
-	recvChunkStream := &recvChunkStream{stream: stream}
-	if err := s.streamer.WriteStream(header.TargetPath, recvChunkStream, s.showProgress); err != nil {
-		s.log.With(zap.Error(err)).Errorf("Receive of file %q failed", header.TargetPath)
-		return false, err
+
+	if header.Unchanged {
+		s.log.Infof("File %q is unchanged", header.TargetPath)
+	} else {
+		s.log.Infof("Starting file receive of %q", header.TargetPath)
+		recvChunkStream := &recvChunkStream{stream: stream}
+		streamOpts := streamer.Options{Offset: int64(header.Offset), Compression: header.Compression}
+		if err := s.streamer.WriteStream(header.TargetPath, recvChunkStream, streamOpts, s.showProgress); err != nil {
+			s.log.With(zap.Error(err)).Errorf("Receive of file %q failed", header.TargetPath)
+			return false, err
+		}
+	}
+
+	// senders that don't support hashing leave the digest empty
+	if len(header.Sha256) > 0 {
+		sum, size, err := s.streamer.Hash(header.TargetPath, -1)
+		if err != nil {
+			return false, fmt.Errorf("hashing received file %q: %w", header.TargetPath, err)
+		}
+		if uint64(size) != header.Size || !bytes.Equal(sum, header.Sha256) {
+			return false, fmt.Errorf("received file %q doesn't match the digest sent by the sender", header.TargetPath)
+		}
 	}
 	s.log.Infof("Finished file receive of %q", header.TargetPath)
 	return false, nil
@@ -183,6 +267,7 @@ func (s *FileTransferer) startRecv() error {
 // This allows for a retry of the file receive.
 func (s *FileTransferer) abortRecv() {
 	s.receiveStarted = false
+	s.abortedFiles = s.files
 	s.files = nil
 }
 
@@ -190,6 +275,7 @@ func (s *FileTransferer) abortRecv() {
 // This allows other debugd instances to request files from this server.
 func (s *FileTransferer) finishRecv() {
 	s.receiveStarted = false
+	s.abortedFiles = nil
 	s.receiveFinished.Store(true)
 }
 
@@ -206,6 +292,39 @@ type FileStat struct {
 	OverrideServiceUnit string // optional name of the service unit to override
 }
 
+// FileState describes the content of a file at the receiver.
+type FileState struct {
+	Size   int64
+	SHA256 []byte
+}
+
+// SendOptions control how files are sent.
+type SendOptions struct {
+	// ReceiverStates are the states of files the receiver already has, keyed by target path.
+	ReceiverStates map[string]FileState
+	// Compression of the sent chunks. Must be supported by the receiver.
+	Compression pb.Compression
+}
+
+// FileStatesFromProto converts file states received over gRPC.
+func FileStatesFromProto(states []*pb.FileState) map[string]FileState {
+	res := make(map[string]FileState, len(states))
+	for _, state := range states {
+		res[state.TargetPath] = FileState{Size: int64(state.Size), SHA256: state.Sha256}
+	}
+	return res
+}
+
+// FileStatesToProto converts file states to be sent over gRPC.
+func FileStatesToProto(states map[string]FileState) []*pb.FileState {
+	res := make([]*pb.FileState, 0, len(states))
+	for path, state := range states {
+		res = append(res, &pb.FileState{TargetPath: path, Size: uint64(state.Size), Sha256: state.SHA256})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].TargetPath < res[j].TargetPath })
+	return res
+}
+
 var (
 	// ErrReceiveRunning is returned if a file receive is already running.
 	ErrReceiveRunning = errors.New("receive already running")
@@ -221,6 +340,7 @@ const (
 )
 
 type streamReadWriter interface {
-	WriteStream(filename string, stream streamer.ReadChunkStream, showProgress bool) error
-	ReadStream(filename string, stream streamer.WriteChunkStream, chunksize uint, showProgress bool) error
+	WriteStream(filename string, stream streamer.ReadChunkStream, opts streamer.Options, showProgress bool) error
+	ReadStream(filename string, stream streamer.WriteChunkStream, chunksize uint, opts streamer.Options, showProgress bool) error
+	Hash(filename string, limit int64) ([]byte, int64, error)
 }
@@ -7,8 +7,10 @@ SPDX-License-Identifier: AGPL-3.0-only
 package filetransfer
 
 import (
+	"crypto/sha256"
 	"errors"
 	"io"
+	"sync"
 	"testing"
 
 	"github.com/edgelesssys/constellation/v2/debugd/internal/filetransfer/streamer"
@@ -24,12 +26,19 @@ func TestMain(m *testing.M) {
 }
 
 func TestSendFiles(t *testing.T) {
+	content := []byte("test")
+	contentSum := sha256.Sum256(content)
+	prefixSum := sha256.Sum256(content[:2])
+	emptySum := sha256.Sum256(nil)
+
 	testCases := map[string]struct {
 		files           *[]FileStat
+		opts            SendOptions
 		receiveFinished bool
 		sendErr         error
 		readStreamErr   error
 		wantHeaders     []*pb.FileTransferMessage
+		wantReadStreams []streamer.Options
 		wantErr         bool
 	}{
 		"can send files": {
@@ -53,6 +62,7 @@ func TestSendFiles(t *testing.T) {
 							TargetPath:          "testfileA",
 							Mode:                0o644,
 							OverrideServiceUnit: func() *string { s := "somesvcA"; return &s }(),
+							Sha256:              emptySum[:],
 						},
 					},
 				},
@@ -62,10 +72,83 @@ func TestSendFiles(t *testing.T) {
 							TargetPath:          "testfileB",
 							Mode:                0o644,
 							OverrideServiceUnit: func() *string { s := "somesvcB"; return &s }(),
+							Sha256:              emptySum[:],
+						},
+					},
+				},
+			},
+			wantReadStreams: []streamer.Options{{}, {}},
+		},
+		"unchanged file is skipped": {
+			files: &[]FileStat{
+				{SourcePath: "source", TargetPath: "target", Mode: 0o644},
+			},
+			opts: SendOptions{
+				ReceiverStates: map[string]FileState{"target": {Size: 4, SHA256: contentSum[:]}},
+				Compression:    pb.Compression_COMPRESSION_ZSTD,
+			},
+			receiveFinished: true,
+			wantHeaders: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath:  "target",
+							Mode:        0o644,
+							Sha256:      contentSum[:],
+							Size:        4,
+							Unchanged:   true,
+							Compression: pb.Compression_COMPRESSION_ZSTD,
+						},
+					},
+				},
+			},
+		},
+		"partially received file is resumed": {
+			files: &[]FileStat{
+				{SourcePath: "source", TargetPath: "target", Mode: 0o644},
+			},
+			opts: SendOptions{
+				ReceiverStates: map[string]FileState{"target": {Size: 2, SHA256: prefixSum[:]}},
+				Compression:    pb.Compression_COMPRESSION_ZSTD,
+			},
+			receiveFinished: true,
+			wantHeaders: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath:  "target",
+							Mode:        0o644,
+							Sha256:      contentSum[:],
+							Size:        4,
+							Offset:      2,
+							Compression: pb.Compression_COMPRESSION_ZSTD,
 						},
 					},
 				},
 			},
+			wantReadStreams: []streamer.Options{{Offset: 2, Compression: pb.Compression_COMPRESSION_ZSTD}},
+		},
+		"changed file is sent completely": {
+			files: &[]FileStat{
+				{SourcePath: "source", TargetPath: "target", Mode: 0o644},
+			},
+			opts: SendOptions{
+				ReceiverStates: map[string]FileState{"target": {Size: 2, SHA256: emptySum[:]}},
+			},
+			receiveFinished: true,
+			wantHeaders: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath: "target",
+							Mode:       0o644,
+							Sha256:     contentSum[:],
+							Size:       4,
+						},
+					},
+				},
+			},
+			wantReadStreams: []streamer.Options{{}},
 		},
 		"not finished receiving": {
 			files: &[]FileStat{
@@ -114,7 +197,10 @@ func TestSendFiles(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			streamer := &stubStreamReadWriter{readStreamErr: tc.readStreamErr}
+			streamer := &stubStreamReadWriter{
+				readStreamErr: tc.readStreamErr,
+				contents:      map[string][]byte{"source": content},
+			}
 			stream := &stubSendFilesStream{sendErr: tc.sendErr}
 			transfer := &FileTransferer{
 				log:          logger.NewTest(t),
@@ -126,7 +212,7 @@ func TestSendFiles(t *testing.T) {
 			}
 			transfer.receiveFinished.Store(tc.receiveFinished)
 
-			err := transfer.SendFiles(stream)
+			err := transfer.SendFiles(stream, tc.opts)
 
 			if tc.wantErr {
 				assert.Error(err)
@@ -134,11 +220,15 @@ func TestSendFiles(t *testing.T) {
 			}
 			require.NoError(err)
 			assert.Equal(tc.wantHeaders, stream.msgs)
+			assert.Equal(tc.wantReadStreams, streamer.readStreamOpts)
 		})
 	}
 }
 
 func TestRecvFiles(t *testing.T) {
+	content := []byte("test")
+	contentSum := sha256.Sum256(content)
+
 	testCases := map[string]struct {
 		msgs                []*pb.FileTransferMessage
 		recvAlreadyStarted  bool
@@ -146,6 +236,7 @@ func TestRecvFiles(t *testing.T) {
 		recvErr             error
 		writeStreamErr      error
 		wantFiles           []FileStat
+		wantWriteStreams    []streamer.Options
 		wantErr             bool
 	}{
 		"can recv files": {
@@ -183,6 +274,61 @@ func TestRecvFiles(t *testing.T) {
 					Mode:       0o644,
 				},
 			},
+			wantWriteStreams: []streamer.Options{{}, {}},
+		},
+		"unchanged file is verified": {
+			msgs: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath: "target",
+							Mode:       0o644,
+							Sha256:     contentSum[:],
+							Size:       4,
+							Unchanged:  true,
+						},
+					},
+				},
+			},
+			wantFiles: []FileStat{
+				{SourcePath: "target", TargetPath: "target", Mode: 0o644},
+			},
+		},
+		"compressed file is resumed": {
+			msgs: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath:  "target",
+							Mode:        0o644,
+							Sha256:      contentSum[:],
+							Size:        4,
+							Offset:      2,
+							Compression: pb.Compression_COMPRESSION_ZSTD,
+						},
+					},
+				},
+				// Chunk messages left out since they would be consumed by the streamReadWriter
+			},
+			wantFiles: []FileStat{
+				{SourcePath: "target", TargetPath: "target", Mode: 0o644},
+			},
+			wantWriteStreams: []streamer.Options{{Offset: 2, Compression: pb.Compression_COMPRESSION_ZSTD}},
+		},
+		"digest mismatch": {
+			msgs: []*pb.FileTransferMessage{
+				{
+					Kind: &pb.FileTransferMessage_Header{
+						Header: &pb.FileTransferHeader{
+							TargetPath: "target",
+							Mode:       0o644,
+							Sha256:     []byte{0x01},
+							Size:       4,
+						},
+					},
+				},
+			},
+			wantErr: true,
 		},
 		"no messages": {},
 		"recv fails": {
@@ -252,7 +398,10 @@ func TestRecvFiles(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			streamer := &stubStreamReadWriter{writeStreamErr: tc.writeStreamErr}
+			streamer := &stubStreamReadWriter{
+				writeStreamErr: tc.writeStreamErr,
+				contents:       map[string][]byte{"target": content},
+			}
 			stream := &fakeRecvFilesStream{msgs: tc.msgs, recvErr: tc.recvErr}
 			transfer := New(logger.NewTest(t), streamer, false)
 			if tc.recvAlreadyStarted {
@@ -269,10 +418,34 @@ func TestRecvFiles(t *testing.T) {
 			}
 			require.NoError(err)
 			assert.Equal(tc.wantFiles, transfer.files)
+			assert.Equal(tc.wantWriteStreams, streamer.writeStreamOpts)
 		})
 	}
 }
 
+func TestFileStates(t *testing.T) {
+	assert := assert.New(t)
+
+	content := []byte("test")
+	contentSum := sha256.Sum256(content)
+	streamer := &stubStreamReadWriter{contents: map[string][]byte{"fileA": content}}
+	transfer := New(logger.NewTest(t), streamer, false)
+
+	wantStates := map[string]FileState{"fileA": {Size: 4, SHA256: contentSum[:]}}
+	assert.Equal(wantStates, transfer.FileStates([]string{"fileA", "missing"}))
+
+	// files of a failed receive can be resumed
+	assert.Empty(transfer.AbortedFileStates())
+	err := transfer.RecvFiles(&fakeRecvFilesStream{
+		msgs: []*pb.FileTransferMessage{
+			{Kind: &pb.FileTransferMessage_Header{Header: &pb.FileTransferHeader{TargetPath: "fileA"}}},
+			{Kind: &pb.FileTransferMessage_Chunk{}},
+		},
+	})
+	assert.Error(err)
+	assert.Equal(wantStates, transfer.AbortedFileStates())
+}
+
 func TestGetSetFiles(t *testing.T) {
 	testCases := map[string]struct {
 		setFiles  *[]FileStat
@@ -322,7 +495,7 @@ func TestConcurrency(t *testing.T) {
 	ft := New(logger.NewTest(t), &stubStreamReadWriter{}, false)
 
 	sendFiles := func() {
-		_ = ft.SendFiles(&stubSendFilesStream{})
+		_ = ft.SendFiles(&stubSendFilesStream{}, SendOptions{})
 	}
 
 	recvFiles := func() {
@@ -356,18 +529,41 @@ func TestConcurrency(t *testing.T) {
 }
 
 type stubStreamReadWriter struct {
-	readStreamErr  error
-	writeStreamErr error
+	readStreamErr   error
+	writeStreamErr  error
+	contents        map[string][]byte
+	readStreamOpts  []streamer.Options
+	writeStreamOpts []streamer.Options
+	mux             sync.Mutex
 }
 
-func (s *stubStreamReadWriter) ReadStream(_ string, _ streamer.WriteChunkStream, _ uint, _ bool) error {
+func (s *stubStreamReadWriter) ReadStream(_ string, _ streamer.WriteChunkStream, _ uint, opts streamer.Options, _ bool) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.readStreamOpts = append(s.readStreamOpts, opts)
 	return s.readStreamErr
 }
 
-func (s *stubStreamReadWriter) WriteStream(_ string, _ streamer.ReadChunkStream, _ bool) error {
+func (s *stubStreamReadWriter) WriteStream(_ string, _ streamer.ReadChunkStream, opts streamer.Options, _ bool) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.writeStreamOpts = append(s.writeStreamOpts, opts)
 	return s.writeStreamErr
 }
 
+// Hash returns the digest of the stubbed file content. Files without content are treated as empty.
+func (s *stubStreamReadWriter) Hash(filename string, limit int64) ([]byte, int64, error) {
+	if filename == "missing" {
+		return nil, 0, errors.New("file not found")
+	}
+	content := s.contents[filename]
+	if limit >= 0 && limit < int64(len(content)) {
+		content = content[:limit]
+	}
+	sum := sha256.Sum256(content)
+	return sum[:], int64(len(content)), nil
+}
+
 type fakeRecvFilesStream struct {
 	msgs    []*pb.FileTransferMessage
 	pos     int
@@ -389,10 +585,14 @@ func (s *fakeRecvFilesStream) Recv() (*pb.FileTransferMessage, error) {
 
 type dummyStreamReadWriter struct{}
 
-func (s *dummyStreamReadWriter) ReadStream(_ string, _ streamer.WriteChunkStream, _ uint, _ bool) error {
+func (s *dummyStreamReadWriter) ReadStream(_ string, _ streamer.WriteChunkStream, _ uint, _ streamer.Options, _ bool) error {
+	panic("dummy")
+}
+
+func (s *dummyStreamReadWriter) WriteStream(_ string, _ streamer.ReadChunkStream, _ streamer.Options, _ bool) error {
 	panic("dummy")
 }
 
-func (s *dummyStreamReadWriter) WriteStream(_ string, _ streamer.ReadChunkStream, _ bool) error {
+func (s *dummyStreamReadWriter) Hash(_ string, _ int64) ([]byte, int64, error) {
 	panic("dummy")
 }
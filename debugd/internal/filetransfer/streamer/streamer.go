@@ -8,6 +8,7 @@ SPDX-License-Identifier: AGPL-3.0-only
 package streamer
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	"sync"
 
 	pb "github.com/edgelesssys/constellation/v2/debugd/service"
+	"github.com/klauspost/compress/zstd"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/afero"
 )
@@ -43,8 +45,17 @@ func New(fs afero.Fs) *FileStreamer {
 	}
 }
 
+// Options control how a file is streamed.
+type Options struct {
+	// Offset is the position in the file the stream starts at.
+	// When writing, the bytes before the offset are kept and everything after is replaced.
+	Offset int64
+	// Compression of the chunk contents.
+	Compression pb.Compression
+}
+
 // WriteStream opens a file to write to and streams chunks from a gRPC stream into the file.
-func (f *FileStreamer) WriteStream(filename string, stream ReadChunkStream, showProgress bool) error {
+func (f *FileStreamer) WriteStream(filename string, stream ReadChunkStream, opts Options, showProgress bool) error {
 	f.mux.Lock()
 	defer f.mux.Unlock()
 	file, err := f.fs.OpenFile(filename, os.O_WRONLY|os.O_CREATE, os.ModePerm)
@@ -56,18 +67,27 @@ func (f *FileStreamer) WriteStream(filename string, stream ReadChunkStream, show
 	if err != nil {
 		return fmt.Errorf("performing stat on %v to get the file size: %w", filename, err)
 	}
+	if stat.Size() < opts.Offset {
+		return fmt.Errorf("cannot resume writing %v at offset %d: file has only %d bytes", filename, opts.Offset, stat.Size())
+	}
+	if err := file.Truncate(opts.Offset); err != nil {
+		return fmt.Errorf("truncating %v: %w", filename, err)
+	}
+	if _, err := file.Seek(opts.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking %v: %w", filename, err)
+	}
 
 	var bar *progressbar.ProgressBar
 	if showProgress {
-		bar = newProgressBar(stat.Size())
+		bar = newProgressBar(-1)
 		defer bar.Close()
 	}
 
-	return writeInner(file, stream, bar)
+	return writeInner(file, stream, opts.Compression, bar)
 }
 
 // ReadStream opens a file to read from and streams its contents chunkwise over gRPC.
-func (f *FileStreamer) ReadStream(filename string, stream WriteChunkStream, chunksize uint, showProgress bool) error {
+func (f *FileStreamer) ReadStream(filename string, stream WriteChunkStream, chunksize uint, opts Options, showProgress bool) error {
 	if chunksize == 0 {
 		return errors.New("invalid chunksize")
 	}
@@ -86,54 +106,184 @@ func (f *FileStreamer) ReadStream(filename string, stream WriteChunkStream, chun
 	if err != nil {
 		return fmt.Errorf("performing stat on %v to get the file size: %w", filename, err)
 	}
+	if _, err := file.Seek(opts.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seeking %v: %w", filename, err)
+	}
 
 	var bar *progressbar.ProgressBar
 	if showProgress {
-		bar = newProgressBar(stat.Size())
+		bar = newProgressBar(stat.Size() - opts.Offset)
 		defer bar.Close()
 	}
 
-	return readInner(file, stream, chunksize, bar)
+	return readInner(file, stream, chunksize, opts.Compression, bar)
+}
+
+// Hash returns the SHA-256 digest of the first limit bytes of a file and the number of bytes hashed.
+// If limit is negative, the whole file is hashed.
+func (f *FileStreamer) Hash(filename string, limit int64) ([]byte, int64, error) {
+	// fail if file is currently RW locked
+	if f.mux.TryRLock() {
+		defer f.mux.RUnlock()
+	} else {
+		return nil, 0, errors.New("a file is opened for writing so cannot read at this time")
+	}
+	file, err := f.fs.OpenFile(filename, os.O_RDONLY, 0o755)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open %v for reading: %w", filename, err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if limit >= 0 {
+		r = io.LimitReader(file, limit)
+	}
+	hash := sha256.New()
+	n, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("hashing %v: %w", filename, err)
+	}
+	return hash.Sum(nil), n, nil
 }
 
 // readInner reads from a an io.Reader and sends chunks over a gRPC stream.
-func readInner(fp io.Reader, stream WriteChunkStream, chunksize uint, bar *progressbar.ProgressBar) error {
+func readInner(fp io.Reader, stream WriteChunkStream, chunksize uint, compression pb.Compression, bar *progressbar.ProgressBar) error {
+	chunks := &chunkWriter{stream: stream, buf: make([]byte, 0, chunksize)}
+	var w io.Writer = chunks
+	var encoder *zstd.Encoder
+	switch compression {
+	case pb.Compression_COMPRESSION_NONE:
+	case pb.Compression_COMPRESSION_ZSTD:
+		var err error
+		encoder, err = zstd.NewWriter(chunks, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("creating zstd encoder: %w", err)
+		}
+		w = encoder
+	default:
+		return fmt.Errorf("unsupported compression %v", compression)
+	}
+
 	buf := make([]byte, chunksize)
 	for {
 		n, readErr := fp.Read(buf)
-		isLast := errors.Is(readErr, io.EOF)
-		if readErr != nil && !isLast {
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
 			return fmt.Errorf("reading file chunk: %w", readErr)
 		}
-		if err := stream.Send(&pb.Chunk{Content: buf[:n], Last: isLast}); err != nil {
+		if _, err := w.Write(buf[:n]); err != nil {
 			return fmt.Errorf("sending chunk: %w", err)
 		}
 		if bar != nil {
 			_ = bar.Add(n)
 		}
-		if isLast {
-			return nil
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+	if encoder != nil {
+		// flush the end of the zstd stream
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("sending chunk: %w", err)
 		}
 	}
+	if err := chunks.Close(); err != nil {
+		return fmt.Errorf("sending chunk: %w", err)
+	}
+	return nil
 }
 
 // writeInner writes chunks from a gRPC stream to an io.Writer.
-func writeInner(fp io.Writer, stream ReadChunkStream, bar *progressbar.ProgressBar) error {
-	for {
-		chunk, recvErr := stream.Recv()
-		if recvErr != nil {
-			return fmt.Errorf("reading stream: %w", recvErr)
+func writeInner(fp io.Writer, stream ReadChunkStream, compression pb.Compression, bar *progressbar.ProgressBar) error {
+	chunks := &chunkReader{stream: stream}
+	var r io.Reader = chunks
+	switch compression {
+	case pb.Compression_COMPRESSION_NONE:
+	case pb.Compression_COMPRESSION_ZSTD:
+		decoder, err := zstd.NewReader(chunks, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("creating zstd decoder: %w", err)
 		}
-		if _, err := fp.Write(chunk.Content); err != nil {
-			return fmt.Errorf("writing chunk to disk: %w", err)
+		defer decoder.Close()
+		r = decoder
+	default:
+		return fmt.Errorf("unsupported compression %v", compression)
+	}
+
+	var w io.Writer = fp
+	if bar != nil {
+		w = io.MultiWriter(fp, bar)
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	// the decoder may stop reading at the end of the zstd frame, before the last chunk was received
+	return chunks.drain()
+}
+
+// chunkWriter sends the data written to it as chunks of up to cap(buf) bytes.
+// Close sends the remaining data as last chunk.
+type chunkWriter struct {
+	stream WriteChunkStream
+	buf    []byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+		if len(w.buf) == cap(w.buf) {
+			if err := w.stream.Send(&pb.Chunk{Content: w.buf}); err != nil {
+				return written, err
+			}
+			w.buf = w.buf[:0]
 		}
-		if bar != nil {
-			_ = bar.Add(len(chunk.Content))
+	}
+	return written, nil
+}
+
+func (w *chunkWriter) Close() error {
+	err := w.stream.Send(&pb.Chunk{Content: w.buf, Last: true})
+	w.buf = w.buf[:0]
+	return err
+}
+
+// chunkReader reads the contents of chunks from a stream until the last chunk was received.
+type chunkReader struct {
+	stream ReadChunkStream
+	buf    []byte
+	last   bool
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.last {
+			return 0, io.EOF
 		}
-		if chunk.Last {
-			return nil
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, fmt.Errorf("reading stream: %w", err)
 		}
+		r.buf = chunk.Content
+		r.last = chunk.Last
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// drain consumes the stream up to the last chunk. It fails if the remaining chunks contain data.
+func (r *chunkReader) drain() error {
+	n, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("received %d unexpected bytes after end of file", n)
 	}
+	return nil
 }
 
 // newProgressBar creates a new progress bar.
@@ -7,6 +7,8 @@ SPDX-License-Identifier: AGPL-3.0-only
 package streamer
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"io"
 	"testing"
@@ -83,7 +85,7 @@ func TestWriteStream(t *testing.T) {
 			require := require.New(t)
 
 			writer := New(tc.fs)
-			err := writer.WriteStream(filename, &tc.readChunkStream, tc.showProgress)
+			err := writer.WriteStream(filename, &tc.readChunkStream, Options{}, tc.showProgress)
 
 			if tc.wantErr {
 				assert.Error(err)
@@ -170,7 +172,7 @@ func TestReadStream(t *testing.T) {
 			fs := afero.NewMemMapFs()
 			assert.NoError(afero.WriteFile(fs, correctFilename, []byte("test"), 0o755))
 			reader := New(fs)
-			err := reader.ReadStream(tc.filename, &tc.writeChunkStream, tc.chunksize, tc.showProgress)
+			err := reader.ReadStream(tc.filename, &tc.writeChunkStream, tc.chunksize, Options{}, tc.showProgress)
 
 			if tc.wantErr {
 				assert.Error(err)
@@ -182,6 +184,142 @@ func TestReadStream(t *testing.T) {
 	}
 }
 
+func TestResumeStream(t *testing.T) {
+	testCases := map[string]struct {
+		existing     []byte
+		offset       int64
+		chunks       [][]byte
+		wantContents []byte
+		wantErr      bool
+	}{
+		"remaining content is appended": {
+			existing:     []byte("te"),
+			offset:       2,
+			chunks:       [][]byte{[]byte("st")},
+			wantContents: []byte("test"),
+		},
+		"content after offset is replaced": {
+			existing:     []byte("tezz"),
+			offset:       2,
+			chunks:       [][]byte{[]byte("st")},
+			wantContents: []byte("test"),
+		},
+		"file smaller than offset": {
+			existing: []byte("t"),
+			offset:   2,
+			chunks:   [][]byte{[]byte("st")},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fs := afero.NewMemMapFs()
+			require.NoError(afero.WriteFile(fs, "testfile", tc.existing, 0o755))
+			writer := New(fs)
+			err := writer.WriteStream("testfile", &fakeReadChunkStream{chunks: tc.chunks}, Options{Offset: tc.offset}, false)
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			fileContents, err := afero.ReadFile(fs, "testfile")
+			require.NoError(err)
+			assert.Equal(tc.wantContents, fileContents)
+		})
+	}
+}
+
+func TestCompressedStream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	content := bytes.Repeat([]byte("test"), 1024)
+	fs := afero.NewMemMapFs()
+	require.NoError(afero.WriteFile(fs, "source", content, 0o755))
+	streamer := New(fs)
+
+	opts := Options{Offset: 4, Compression: pb.Compression_COMPRESSION_ZSTD}
+	sent := &stubWriteChunkStream{}
+	require.NoError(streamer.ReadStream("source", sent, 64, opts, false))
+	var compressedSize int
+	for _, chunk := range sent.chunks {
+		compressedSize += len(chunk)
+	}
+	assert.Less(compressedSize, len(content))
+
+	require.NoError(afero.WriteFile(fs, "target", content[:4], 0o755))
+	require.NoError(streamer.WriteStream("target", &fakeReadChunkStream{chunks: sent.chunks}, opts, false))
+	received, err := afero.ReadFile(fs, "target")
+	require.NoError(err)
+	assert.Equal(content, received)
+
+	// trailing data after the end of the compressed stream is rejected
+	corrupted := append(sent.chunks[:len(sent.chunks)-1:len(sent.chunks)-1], []byte("garbage"), nil)
+	assert.Error(streamer.WriteStream("target", &fakeReadChunkStream{chunks: corrupted}, opts, false))
+}
+
+func TestHash(t *testing.T) {
+	content := []byte("test")
+	fullSum := sha256.Sum256(content)
+	prefixSum := sha256.Sum256(content[:2])
+
+	testCases := map[string]struct {
+		filename string
+		limit    int64
+		wantSum  []byte
+		wantSize int64
+		wantErr  bool
+	}{
+		"whole file": {
+			filename: "testfile",
+			limit:    -1,
+			wantSum:  fullSum[:],
+			wantSize: 4,
+		},
+		"prefix": {
+			filename: "testfile",
+			limit:    2,
+			wantSum:  prefixSum[:],
+			wantSize: 2,
+		},
+		"limit exceeds file size": {
+			filename: "testfile",
+			limit:    10,
+			wantSum:  fullSum[:],
+			wantSize: 4,
+		},
+		"file does not exist": {
+			filename: "incorrect-filename",
+			limit:    -1,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fs := afero.NewMemMapFs()
+			require.NoError(afero.WriteFile(fs, "testfile", content, 0o755))
+			sum, size, err := New(fs).Hash(tc.filename, tc.limit)
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.wantSum, sum)
+			assert.Equal(tc.wantSize, size)
+		})
+	}
+}
+
 type fakeReadChunkStream struct {
 	chunks  [][]byte
 	pos     int
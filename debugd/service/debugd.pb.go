@@ -70,6 +70,52 @@ func (SetInfoStatus) EnumDescriptor() ([]byte, []int) {
 	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{0}
 }
 
+type Compression int32
+
+const (
+	Compression_COMPRESSION_NONE Compression = 0
+	Compression_COMPRESSION_ZSTD Compression = 1 // chunk contents of a file form a single zstd stream
+)
+
+// Enum value maps for Compression.
+var (
+	Compression_name = map[int32]string{
+		0: "COMPRESSION_NONE",
+		1: "COMPRESSION_ZSTD",
+	}
+	Compression_value = map[string]int32{
+		"COMPRESSION_NONE": 0,
+		"COMPRESSION_ZSTD": 1,
+	}
+)
+
+func (x Compression) Enum() *Compression {
+	p := new(Compression)
+	*p = x
+	return p
+}
+
+func (x Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_debugd_service_debugd_proto_enumTypes[1].Descriptor()
+}
+
+func (Compression) Type() protoreflect.EnumType {
+	return &file_debugd_service_debugd_proto_enumTypes[1]
+}
+
+func (x Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Compression.Descriptor instead.
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{1}
+}
+
 type UploadFilesStatus int32
 
 const (
@@ -109,11 +155,11 @@ func (x UploadFilesStatus) String() string {
 }
 
 func (UploadFilesStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_debugd_service_debugd_proto_enumTypes[1].Descriptor()
+	return file_debugd_service_debugd_proto_enumTypes[2].Descriptor()
 }
 
 func (UploadFilesStatus) Type() protoreflect.EnumType {
-	return &file_debugd_service_debugd_proto_enumTypes[1]
+	return &file_debugd_service_debugd_proto_enumTypes[2]
 }
 
 func (x UploadFilesStatus) Number() protoreflect.EnumNumber {
@@ -122,7 +168,56 @@ func (x UploadFilesStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use UploadFilesStatus.Descriptor instead.
 func (UploadFilesStatus) EnumDescriptor() ([]byte, []int) {
-	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{1}
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{2}
+}
+
+type FetchFilesStatus int32
+
+const (
+	FetchFilesStatus_FETCH_FILES_SUCCESS         FetchFilesStatus = 0
+	FetchFilesStatus_FETCH_FILES_FAILED          FetchFilesStatus = 1
+	FetchFilesStatus_FETCH_FILES_ALREADY_STARTED FetchFilesStatus = 2
+)
+
+// Enum value maps for FetchFilesStatus.
+var (
+	FetchFilesStatus_name = map[int32]string{
+		0: "FETCH_FILES_SUCCESS",
+		1: "FETCH_FILES_FAILED",
+		2: "FETCH_FILES_ALREADY_STARTED",
+	}
+	FetchFilesStatus_value = map[string]int32{
+		"FETCH_FILES_SUCCESS":         0,
+		"FETCH_FILES_FAILED":          1,
+		"FETCH_FILES_ALREADY_STARTED": 2,
+	}
+)
+
+func (x FetchFilesStatus) Enum() *FetchFilesStatus {
+	p := new(FetchFilesStatus)
+	*p = x
+	return p
+}
+
+func (x FetchFilesStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FetchFilesStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_debugd_service_debugd_proto_enumTypes[3].Descriptor()
+}
+
+func (FetchFilesStatus) Type() protoreflect.EnumType {
+	return &file_debugd_service_debugd_proto_enumTypes[3]
+}
+
+func (x FetchFilesStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FetchFilesStatus.Descriptor instead.
+func (FetchFilesStatus) EnumDescriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{3}
 }
 
 type UploadSystemdServiceUnitsStatus int32
@@ -155,11 +250,11 @@ func (x UploadSystemdServiceUnitsStatus) String() string {
 }
 
 func (UploadSystemdServiceUnitsStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_debugd_service_debugd_proto_enumTypes[2].Descriptor()
+	return file_debugd_service_debugd_proto_enumTypes[4].Descriptor()
 }
 
 func (UploadSystemdServiceUnitsStatus) Type() protoreflect.EnumType {
-	return &file_debugd_service_debugd_proto_enumTypes[2]
+	return &file_debugd_service_debugd_proto_enumTypes[4]
 }
 
 func (x UploadSystemdServiceUnitsStatus) Number() protoreflect.EnumNumber {
@@ -168,7 +263,7 @@ func (x UploadSystemdServiceUnitsStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use UploadSystemdServiceUnitsStatus.Descriptor instead.
 func (UploadSystemdServiceUnitsStatus) EnumDescriptor() ([]byte, []int) {
-	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{2}
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{4}
 }
 
 type SetInfoRequest struct {
@@ -409,6 +504,9 @@ type DownloadFilesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	States      []*FileState `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`                                    // files the requesting instance already has, used to skip or resume transfers
+	Compression Compression  `protobuf:"varint,2,opt,name=compression,proto3,enum=debugd.Compression" json:"compression,omitempty"` // compression the requesting instance wants the chunks to be sent with
 }
 
 func (x *DownloadFilesRequest) Reset() {
@@ -443,13 +541,26 @@ func (*DownloadFilesRequest) Descriptor() ([]byte, []int) {
 	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{5}
 }
 
+func (x *DownloadFilesRequest) GetStates() []*FileState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *DownloadFilesRequest) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_COMPRESSION_NONE
+}
+
 type FileTransferMessage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Kind:
-	//
 	//	*FileTransferMessage_Header
 	//	*FileTransferMessage_Chunk
 	Kind isFileTransferMessage_Kind `protobuf_oneof:"kind"`
@@ -513,11 +624,11 @@ type isFileTransferMessage_Kind interface {
 }
 
 type FileTransferMessage_Header struct {
-	Header *FileTransferHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+	Header *FileTransferHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"` // start of transfer
 }
 
 type FileTransferMessage_Chunk struct {
-	Chunk *Chunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+	Chunk *Chunk `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"` // file content as chunks
 }
 
 func (*FileTransferMessage_Header) isFileTransferMessage_Kind() {}
@@ -529,9 +640,14 @@ type FileTransferHeader struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TargetPath          string  `protobuf:"bytes,1,opt,name=targetPath,proto3" json:"targetPath,omitempty"`
-	Mode                uint32  `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
-	OverrideServiceUnit *string `protobuf:"bytes,4,opt,name=overrideServiceUnit,proto3,oneof" json:"overrideServiceUnit,omitempty"`
+	TargetPath          string      `protobuf:"bytes,1,opt,name=targetPath,proto3" json:"targetPath,omitempty"`
+	Mode                uint32      `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	OverrideServiceUnit *string     `protobuf:"bytes,4,opt,name=overrideServiceUnit,proto3,oneof" json:"overrideServiceUnit,omitempty"`
+	Sha256              []byte      `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`                                    // digest of the complete file, empty if not known by the sender
+	Size                uint64      `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`                                       // size of the complete file
+	Offset              uint64      `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`                                   // offset the chunks start at, the receiver already has the bytes before
+	Unchanged           bool        `protobuf:"varint,8,opt,name=unchanged,proto3" json:"unchanged,omitempty"`                             // the receiver already has the file, no chunks follow
+	Compression         Compression `protobuf:"varint,9,opt,name=compression,proto3,enum=debugd.Compression" json:"compression,omitempty"` // compression of the chunk contents
 }
 
 func (x *FileTransferHeader) Reset() {
@@ -587,6 +703,41 @@ func (x *FileTransferHeader) GetOverrideServiceUnit() string {
 	return ""
 }
 
+func (x *FileTransferHeader) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+func (x *FileTransferHeader) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileTransferHeader) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FileTransferHeader) GetUnchanged() bool {
+	if x != nil {
+		return x.Unchanged
+	}
+	return false
+}
+
+func (x *FileTransferHeader) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_COMPRESSION_NONE
+}
+
 type Chunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -689,17 +840,18 @@ func (x *UploadFilesResponse) GetStatus() UploadFilesStatus {
 	return UploadFilesStatus_UPLOAD_FILES_SUCCESS
 }
 
-type ServiceUnit struct {
+type FileState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Contents string `protobuf:"bytes,2,opt,name=contents,proto3" json:"contents,omitempty"`
+	TargetPath string `protobuf:"bytes,1,opt,name=targetPath,proto3" json:"targetPath,omitempty"`
+	Size       uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256     []byte `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
 }
 
-func (x *ServiceUnit) Reset() {
-	*x = ServiceUnit{}
+func (x *FileState) Reset() {
+	*x = FileState{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_debugd_service_debugd_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -707,13 +859,13 @@ func (x *ServiceUnit) Reset() {
 	}
 }
 
-func (x *ServiceUnit) String() string {
+func (x *FileState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServiceUnit) ProtoMessage() {}
+func (*FileState) ProtoMessage() {}
 
-func (x *ServiceUnit) ProtoReflect() protoreflect.Message {
+func (x *FileState) ProtoReflect() protoreflect.Message {
 	mi := &file_debugd_service_debugd_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -725,35 +877,42 @@ func (x *ServiceUnit) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServiceUnit.ProtoReflect.Descriptor instead.
-func (*ServiceUnit) Descriptor() ([]byte, []int) {
+// Deprecated: Use FileState.ProtoReflect.Descriptor instead.
+func (*FileState) Descriptor() ([]byte, []int) {
 	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ServiceUnit) GetName() string {
+func (x *FileState) GetTargetPath() string {
 	if x != nil {
-		return x.Name
+		return x.TargetPath
 	}
 	return ""
 }
 
-func (x *ServiceUnit) GetContents() string {
+func (x *FileState) GetSize() uint64 {
 	if x != nil {
-		return x.Contents
+		return x.Size
 	}
-	return ""
+	return 0
 }
 
-type UploadSystemdServiceUnitsRequest struct {
+func (x *FileState) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+type GetFileStatesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Units []*ServiceUnit `protobuf:"bytes,1,rep,name=units,proto3" json:"units,omitempty"`
+	TargetPaths []string `protobuf:"bytes,1,rep,name=targetPaths,proto3" json:"targetPaths,omitempty"`
 }
 
-func (x *UploadSystemdServiceUnitsRequest) Reset() {
-	*x = UploadSystemdServiceUnitsRequest{}
+func (x *GetFileStatesRequest) Reset() {
+	*x = GetFileStatesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_debugd_service_debugd_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -761,13 +920,13 @@ func (x *UploadSystemdServiceUnitsRequest) Reset() {
 	}
 }
 
-func (x *UploadSystemdServiceUnitsRequest) String() string {
+func (x *GetFileStatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadSystemdServiceUnitsRequest) ProtoMessage() {}
+func (*GetFileStatesRequest) ProtoMessage() {}
 
-func (x *UploadSystemdServiceUnitsRequest) ProtoReflect() protoreflect.Message {
+func (x *GetFileStatesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_debugd_service_debugd_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -779,28 +938,28 @@ func (x *UploadSystemdServiceUnitsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadSystemdServiceUnitsRequest.ProtoReflect.Descriptor instead.
-func (*UploadSystemdServiceUnitsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetFileStatesRequest.ProtoReflect.Descriptor instead.
+func (*GetFileStatesRequest) Descriptor() ([]byte, []int) {
 	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *UploadSystemdServiceUnitsRequest) GetUnits() []*ServiceUnit {
+func (x *GetFileStatesRequest) GetTargetPaths() []string {
 	if x != nil {
-		return x.Units
+		return x.TargetPaths
 	}
 	return nil
 }
 
-type UploadSystemdServiceUnitsResponse struct {
+type GetFileStatesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Status UploadSystemdServiceUnitsStatus `protobuf:"varint,1,opt,name=status,proto3,enum=debugd.UploadSystemdServiceUnitsStatus" json:"status,omitempty"`
+	States []*FileState `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"` // files that don't exist are left out
 }
 
-func (x *UploadSystemdServiceUnitsResponse) Reset() {
-	*x = UploadSystemdServiceUnitsResponse{}
+func (x *GetFileStatesResponse) Reset() {
+	*x = GetFileStatesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_debugd_service_debugd_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -808,13 +967,13 @@ func (x *UploadSystemdServiceUnitsResponse) Reset() {
 	}
 }
 
-func (x *UploadSystemdServiceUnitsResponse) String() string {
+func (x *GetFileStatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadSystemdServiceUnitsResponse) ProtoMessage() {}
+func (*GetFileStatesResponse) ProtoMessage() {}
 
-func (x *UploadSystemdServiceUnitsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetFileStatesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_debugd_service_debugd_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -826,237 +985,943 @@ func (x *UploadSystemdServiceUnitsResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadSystemdServiceUnitsResponse.ProtoReflect.Descriptor instead.
-func (*UploadSystemdServiceUnitsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetFileStatesResponse.ProtoReflect.Descriptor instead.
+func (*GetFileStatesResponse) Descriptor() ([]byte, []int) {
 	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *UploadSystemdServiceUnitsResponse) GetStatus() UploadSystemdServiceUnitsStatus {
+func (x *GetFileStatesResponse) GetStates() []*FileState {
 	if x != nil {
-		return x.Status
+		return x.States
 	}
-	return UploadSystemdServiceUnitsStatus_UPLOAD_SYSTEMD_SERVICE_UNITS_SUCCESS
+	return nil
 }
 
-var File_debugd_service_debugd_proto protoreflect.FileDescriptor
+type FetchFilesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_debugd_service_debugd_proto_rawDesc = []byte{
-	0x0a, 0x1b, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x64,
-	0x65, 0x62, 0x75, 0x67, 0x64, 0x22, 0x32, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x40, 0x0a, 0x0f, 0x53, 0x65, 0x74,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64,
-	0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x10, 0x0a, 0x0e, 0x47,
-	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x33, 0x0a,
-	0x0f, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x20, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
-	0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e,
-	0x66, 0x6f, 0x22, 0x2e, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x22, 0x16, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
-	0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x7a, 0x0a, 0x13, 0x46, 0x69,
-	0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x12, 0x34, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52,
-	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
-	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06,
-	0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x97, 0x01, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a,
-	0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a,
-	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64,
-	0x65, 0x12, 0x35, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
-	0x52, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x55, 0x6e, 0x69, 0x74, 0x88, 0x01, 0x01, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x6f, 0x76, 0x65,
-	0x72, 0x72, 0x69, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74,
-	0x22, 0x35, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31,
-	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19,
-	0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
-	0x6c, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x22, 0x3d, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73,
-	0x22, 0x4d, 0x0a, 0x20, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
-	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x53, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x22,
-	0x64, 0x0a, 0x21, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x2a, 0x3f, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e,
-	0x46, 0x4f, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14,
-	0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59,
-	0x5f, 0x53, 0x45, 0x54, 0x10, 0x01, 0x2a, 0xb1, 0x01, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x14,
-	0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x53, 0x55, 0x43,
-	0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44,
-	0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x41,
-	0x49, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44,
-	0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x53,
-	0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x21, 0x0a, 0x1d, 0x55, 0x50, 0x4c, 0x4f,
-	0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59,
-	0x5f, 0x46, 0x49, 0x4e, 0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x55,
-	0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x52,
-	0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x75, 0x0a, 0x1f, 0x55, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a,
-	0x24, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x44, 0x5f,
-	0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f, 0x53, 0x55,
-	0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x28, 0x0a, 0x24, 0x55, 0x50, 0x4c, 0x4f, 0x41,
-	0x44, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43,
-	0x45, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10,
-	0x01, 0x32, 0x94, 0x03, 0x0a, 0x06, 0x44, 0x65, 0x62, 0x75, 0x67, 0x64, 0x12, 0x3c, 0x0a, 0x07,
-	0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64,
-	0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x17, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x07, 0x47, 0x65,
-	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x47,
-	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
-	0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0b, 0x55, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64,
-	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70,
-	0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x4e, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61,
-	0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
-	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46, 0x69,
-	0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x71, 0x0a, 0x18, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74,
-	0x73, 0x12, 0x28, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55,
-	0x6e, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x64, 0x65,
-	0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65,
-	0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x73,
-	0x79, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x2f, 0x76, 0x32, 0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Peer string `protobuf:"bytes,1,opt,name=peer,proto3" json:"peer,omitempty"` // IP of the debugd instance to fetch the files from
 }
 
-var (
-	file_debugd_service_debugd_proto_rawDescOnce sync.Once
-	file_debugd_service_debugd_proto_rawDescData = file_debugd_service_debugd_proto_rawDesc
-)
+func (x *FetchFilesRequest) Reset() {
+	*x = FetchFilesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_debugd_service_debugd_proto_rawDescGZIP() []byte {
-	file_debugd_service_debugd_proto_rawDescOnce.Do(func() {
-		file_debugd_service_debugd_proto_rawDescData = protoimpl.X.CompressGZIP(file_debugd_service_debugd_proto_rawDescData)
-	})
-	return file_debugd_service_debugd_proto_rawDescData
+func (x *FetchFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_debugd_service_debugd_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_debugd_service_debugd_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
-var file_debugd_service_debugd_proto_goTypes = []interface{}{
-	(SetInfoStatus)(0),                        // 0: debugd.SetInfoStatus
-	(UploadFilesStatus)(0),                    // 1: debugd.UploadFilesStatus
-	(UploadSystemdServiceUnitsStatus)(0),      // 2: debugd.UploadSystemdServiceUnitsStatus
-	(*SetInfoRequest)(nil),                    // 3: debugd.SetInfoRequest
-	(*SetInfoResponse)(nil),                   // 4: debugd.SetInfoResponse
-	(*GetInfoRequest)(nil),                    // 5: debugd.GetInfoRequest
-	(*GetInfoResponse)(nil),                   // 6: debugd.GetInfoResponse
-	(*Info)(nil),                              // 7: debugd.Info
-	(*DownloadFilesRequest)(nil),              // 8: debugd.DownloadFilesRequest
-	(*FileTransferMessage)(nil),               // 9: debugd.FileTransferMessage
-	(*FileTransferHeader)(nil),                // 10: debugd.FileTransferHeader
-	(*Chunk)(nil),                             // 11: debugd.Chunk
-	(*UploadFilesResponse)(nil),               // 12: debugd.UploadFilesResponse
-	(*ServiceUnit)(nil),                       // 13: debugd.ServiceUnit
-	(*UploadSystemdServiceUnitsRequest)(nil),  // 14: debugd.UploadSystemdServiceUnitsRequest
-	(*UploadSystemdServiceUnitsResponse)(nil), // 15: debugd.UploadSystemdServiceUnitsResponse
+func (*FetchFilesRequest) ProtoMessage() {}
+
+func (x *FetchFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_debugd_service_debugd_proto_depIdxs = []int32{
-	7,  // 0: debugd.SetInfoRequest.info:type_name -> debugd.Info
-	0,  // 1: debugd.SetInfoResponse.status:type_name -> debugd.SetInfoStatus
-	7,  // 2: debugd.GetInfoResponse.info:type_name -> debugd.Info
-	10, // 3: debugd.FileTransferMessage.header:type_name -> debugd.FileTransferHeader
-	11, // 4: debugd.FileTransferMessage.chunk:type_name -> debugd.Chunk
-	1,  // 5: debugd.UploadFilesResponse.status:type_name -> debugd.UploadFilesStatus
-	13, // 6: debugd.UploadSystemdServiceUnitsRequest.units:type_name -> debugd.ServiceUnit
-	2,  // 7: debugd.UploadSystemdServiceUnitsResponse.status:type_name -> debugd.UploadSystemdServiceUnitsStatus
-	3,  // 8: debugd.Debugd.SetInfo:input_type -> debugd.SetInfoRequest
-	5,  // 9: debugd.Debugd.GetInfo:input_type -> debugd.GetInfoRequest
-	9,  // 10: debugd.Debugd.UploadFiles:input_type -> debugd.FileTransferMessage
-	8,  // 11: debugd.Debugd.DownloadFiles:input_type -> debugd.DownloadFilesRequest
-	14, // 12: debugd.Debugd.UploadSystemServiceUnits:input_type -> debugd.UploadSystemdServiceUnitsRequest
-	4,  // 13: debugd.Debugd.SetInfo:output_type -> debugd.SetInfoResponse
-	6,  // 14: debugd.Debugd.GetInfo:output_type -> debugd.GetInfoResponse
-	12, // 15: debugd.Debugd.UploadFiles:output_type -> debugd.UploadFilesResponse
-	9,  // 16: debugd.Debugd.DownloadFiles:output_type -> debugd.FileTransferMessage
-	15, // 17: debugd.Debugd.UploadSystemServiceUnits:output_type -> debugd.UploadSystemdServiceUnitsResponse
-	13, // [13:18] is the sub-list for method output_type
-	8,  // [8:13] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+
+// Deprecated: Use FetchFilesRequest.ProtoReflect.Descriptor instead.
+func (*FetchFilesRequest) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{13}
 }
 
-func init() { file_debugd_service_debugd_proto_init() }
-func file_debugd_service_debugd_proto_init() {
-	if File_debugd_service_debugd_proto != nil {
-		return
+func (x *FetchFilesRequest) GetPeer() string {
+	if x != nil {
+		return x.Peer
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_debugd_service_debugd_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetInfoRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_debugd_service_debugd_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetInfoResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+type FetchFilesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status FetchFilesStatus `protobuf:"varint,1,opt,name=status,proto3,enum=debugd.FetchFilesStatus" json:"status,omitempty"`
+}
+
+func (x *FetchFilesResponse) Reset() {
+	*x = FetchFilesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchFilesResponse) ProtoMessage() {}
+
+func (x *FetchFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_debugd_service_debugd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchFilesResponse.ProtoReflect.Descriptor instead.
+func (*FetchFilesResponse) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FetchFilesResponse) GetStatus() FetchFilesStatus {
+	if x != nil {
+		return x.Status
+	}
+	return FetchFilesStatus_FETCH_FILES_SUCCESS
+}
+
+type ServiceUnit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Contents string `protobuf:"bytes,2,opt,name=contents,proto3" json:"contents,omitempty"`
+}
+
+func (x *ServiceUnit) Reset() {
+	*x = ServiceUnit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceUnit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceUnit) ProtoMessage() {}
+
+func (x *ServiceUnit) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceUnit.ProtoReflect.Descriptor instead.
+func (*ServiceUnit) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ServiceUnit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ServiceUnit) GetContents() string {
+	if x != nil {
+		return x.Contents
+	}
+	return ""
+}
+
+type UploadSystemdServiceUnitsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Units []*ServiceUnit `protobuf:"bytes,1,rep,name=units,proto3" json:"units,omitempty"`
+}
+
+func (x *UploadSystemdServiceUnitsRequest) Reset() {
+	*x = UploadSystemdServiceUnitsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadSystemdServiceUnitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSystemdServiceUnitsRequest) ProtoMessage() {}
+
+func (x *UploadSystemdServiceUnitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSystemdServiceUnitsRequest.ProtoReflect.Descriptor instead.
+func (*UploadSystemdServiceUnitsRequest) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UploadSystemdServiceUnitsRequest) GetUnits() []*ServiceUnit {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+type UploadSystemdServiceUnitsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status UploadSystemdServiceUnitsStatus `protobuf:"varint,1,opt,name=status,proto3,enum=debugd.UploadSystemdServiceUnitsStatus" json:"status,omitempty"`
+}
+
+func (x *UploadSystemdServiceUnitsResponse) Reset() {
+	*x = UploadSystemdServiceUnitsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadSystemdServiceUnitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadSystemdServiceUnitsResponse) ProtoMessage() {}
+
+func (x *UploadSystemdServiceUnitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadSystemdServiceUnitsResponse.ProtoReflect.Descriptor instead.
+func (*UploadSystemdServiceUnitsResponse) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UploadSystemdServiceUnitsResponse) GetStatus() UploadSystemdServiceUnitsStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UploadSystemdServiceUnitsStatus_UPLOAD_SYSTEMD_SERVICE_UNITS_SUCCESS
+}
+
+type GetJournalRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Units  []string `protobuf:"bytes,1,rep,name=units,proto3" json:"units,omitempty"`    // systemd units to return the journal of, all units if empty
+	Lines  uint32   `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`   // number of most recent lines to return, all lines if 0
+	Follow bool     `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"` // keep streaming new journal entries
+}
+
+func (x *GetJournalRequest) Reset() {
+	*x = GetJournalRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetJournalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJournalRequest) ProtoMessage() {}
+
+func (x *GetJournalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJournalRequest.ProtoReflect.Descriptor instead.
+func (*GetJournalRequest) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetJournalRequest) GetUnits() []string {
+	if x != nil {
+		return x.Units
+	}
+	return nil
+}
+
+func (x *GetJournalRequest) GetLines() uint32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *GetJournalRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type JournalEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Line string `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"`
+}
+
+func (x *JournalEntry) Reset() {
+	*x = JournalEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JournalEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JournalEntry) ProtoMessage() {}
+
+func (x *JournalEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JournalEntry.ProtoReflect.Descriptor instead.
+func (*JournalEntry) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JournalEntry) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type ExecRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command []string `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"` // command line of an allow-listed diagnostic command
+}
+
+func (x *ExecRequest) Reset() {
+	*x = ExecRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecRequest) ProtoMessage() {}
+
+func (x *ExecRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecRequest.ProtoReflect.Descriptor instead.
+func (*ExecRequest) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ExecRequest) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+type ExecResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*ExecResponse_Stdout
+	//	*ExecResponse_Stderr
+	//	*ExecResponse_ExitCode
+	Kind isExecResponse_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *ExecResponse) Reset() {
+	*x = ExecResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecResponse) ProtoMessage() {}
+
+func (x *ExecResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecResponse.ProtoReflect.Descriptor instead.
+func (*ExecResponse) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{21}
+}
+
+func (m *ExecResponse) GetKind() isExecResponse_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *ExecResponse) GetStdout() []byte {
+	if x, ok := x.GetKind().(*ExecResponse_Stdout); ok {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (x *ExecResponse) GetStderr() []byte {
+	if x, ok := x.GetKind().(*ExecResponse_Stderr); ok {
+		return x.Stderr
+	}
+	return nil
+}
+
+func (x *ExecResponse) GetExitCode() int32 {
+	if x, ok := x.GetKind().(*ExecResponse_ExitCode); ok {
+		return x.ExitCode
+	}
+	return 0
+}
+
+type isExecResponse_Kind interface {
+	isExecResponse_Kind()
+}
+
+type ExecResponse_Stdout struct {
+	Stdout []byte `protobuf:"bytes,1,opt,name=stdout,proto3,oneof"`
+}
+
+type ExecResponse_Stderr struct {
+	Stderr []byte `protobuf:"bytes,2,opt,name=stderr,proto3,oneof"`
+}
+
+type ExecResponse_ExitCode struct {
+	ExitCode int32 `protobuf:"varint,3,opt,name=exitCode,proto3,oneof"` // last message of the stream
+}
+
+func (*ExecResponse_Stdout) isExecResponse_Kind() {}
+
+func (*ExecResponse_Stderr) isExecResponse_Kind() {}
+
+func (*ExecResponse_ExitCode) isExecResponse_Kind() {}
+
+type GetDiagnosticBundleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetDiagnosticBundleRequest) Reset() {
+	*x = GetDiagnosticBundleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_debugd_service_debugd_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDiagnosticBundleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDiagnosticBundleRequest) ProtoMessage() {}
+
+func (x *GetDiagnosticBundleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_debugd_service_debugd_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDiagnosticBundleRequest.ProtoReflect.Descriptor instead.
+func (*GetDiagnosticBundleRequest) Descriptor() ([]byte, []int) {
+	return file_debugd_service_debugd_proto_rawDescGZIP(), []int{22}
+}
+
+var File_debugd_service_debugd_proto protoreflect.FileDescriptor
+
+var file_debugd_service_debugd_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x64,
+	0x65, 0x62, 0x75, 0x67, 0x64, 0x22, 0x32, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x40, 0x0a, 0x0f, 0x53, 0x65, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64,
+	0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x10, 0x0a, 0x0e, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x33, 0x0a,
+	0x0f, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x20, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e,
+	0x66, 0x6f, 0x22, 0x2e, 0x0a, 0x04, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0x78, 0x0a, 0x14, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x64, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x64, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x7a, 0x0a, 0x13,
+	0x46, 0x69, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48,
+	0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x05, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x64, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0xb0, 0x02, 0x0a, 0x12, 0x46, 0x69, 0x6c,
+	0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12,
+	0x1e, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12,
+	0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x12, 0x35, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68,
+	0x61, 0x32, 0x35, 0x36, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32,
+	0x35, 0x36, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x75, 0x6e, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x12, 0x35, 0x0a, 0x0b,
+	0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x13, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x22, 0x35, 0x0a, 0x05, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6c, 0x61, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6c, 0x61,
+	0x73, 0x74, 0x22, 0x48, 0x0a, 0x13, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x64, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x57, 0x0a, 0x09,
+	0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73,
+	0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x38, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a,
+	0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0b, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22,
+	0x42, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x64, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x73, 0x22, 0x27, 0x0a, 0x11, 0x46, 0x65, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x65, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x65, 0x65, 0x72, 0x22, 0x46, 0x0a, 0x12,
+	0x46, 0x65, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46, 0x65, 0x74, 0x63,
+	0x68, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x3d, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55,
+	0x6e, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x73, 0x22, 0x4d, 0x0a, 0x20, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x52, 0x05, 0x75, 0x6e, 0x69,
+	0x74, 0x73, 0x22, 0x64, 0x0a, 0x21, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64,
+	0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x57, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4a,
+	0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x75, 0x6e,
+	0x69, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c,
+	0x6c, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x6c, 0x6f,
+	0x77, 0x22, 0x22, 0x0a, 0x0c, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x27, 0x0a, 0x0b, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x68,
+	0x0a, 0x0c, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00,
+	0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65,
+	0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65,
+	0x72, 0x72, 0x12, 0x1c, 0x0a, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x08, 0x65, 0x78, 0x69, 0x74, 0x43, 0x6f, 0x64, 0x65,
+	0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x1c, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x44,
+	0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2a, 0x3f, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x45, 0x54, 0x5f, 0x49,
+	0x4e, 0x46, 0x4f, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x18, 0x0a,
+	0x14, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44,
+	0x59, 0x5f, 0x53, 0x45, 0x54, 0x10, 0x01, 0x2a, 0x39, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45,
+	0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10,
+	0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x5a, 0x53, 0x54, 0x44,
+	0x10, 0x01, 0x2a, 0xb1, 0x01, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x14, 0x55, 0x50, 0x4c, 0x4f,
+	0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53,
+	0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c,
+	0x45, 0x53, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x01, 0x12, 0x20, 0x0a, 0x1c, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46, 0x49, 0x4c,
+	0x45, 0x53, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54,
+	0x45, 0x44, 0x10, 0x02, 0x12, 0x21, 0x0a, 0x1d, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x46,
+	0x49, 0x4c, 0x45, 0x53, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x46, 0x49, 0x4e,
+	0x49, 0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1d, 0x0a, 0x19, 0x55, 0x50, 0x4c, 0x4f, 0x41,
+	0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x5f, 0x46, 0x41,
+	0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x64, 0x0a, 0x10, 0x46, 0x65, 0x74, 0x63, 0x68, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x13, 0x46, 0x45,
+	0x54, 0x43, 0x48, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53,
+	0x53, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x46, 0x45, 0x54, 0x43, 0x48, 0x5f, 0x46, 0x49, 0x4c,
+	0x45, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x46,
+	0x45, 0x54, 0x43, 0x48, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41,
+	0x44, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x75, 0x0a, 0x1f,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x28, 0x0a, 0x24, 0x55, 0x50, 0x4c, 0x4f, 0x41, 0x44, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d,
+	0x44, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f,
+	0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x00, 0x12, 0x28, 0x0a, 0x24, 0x55, 0x50, 0x4c,
+	0x4f, 0x41, 0x44, 0x5f, 0x53, 0x59, 0x53, 0x54, 0x45, 0x4d, 0x44, 0x5f, 0x53, 0x45, 0x52, 0x56,
+	0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52,
+	0x45, 0x10, 0x01, 0x32, 0xf3, 0x05, 0x0a, 0x06, 0x44, 0x65, 0x62, 0x75, 0x67, 0x64, 0x12, 0x3c,
+	0x0a, 0x07, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x64, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x07,
+	0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64,
+	0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x17, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0b, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x64, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x4e, 0x0a, 0x0d, 0x44, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x64, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
+	0x46, 0x69, 0x6c, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x46, 0x69,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67,
+	0x64, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
+	0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x0a, 0x46, 0x65, 0x74, 0x63, 0x68,
+	0x46, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x71,
+	0x0a, 0x18, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x64, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d,
+	0x64, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x64, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x41, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x12,
+	0x19, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x75, 0x72,
+	0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x64, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x22, 0x00, 0x30, 0x01, 0x12, 0x35, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x12, 0x13, 0x2e, 0x64,
+	0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x14, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x30, 0x01, 0x12, 0x4c, 0x0a, 0x13, 0x47,
+	0x65, 0x74, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x42, 0x75, 0x6e, 0x64,
+	0x6c, 0x65, 0x12, 0x22, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e, 0x47, 0x65, 0x74, 0x44,
+	0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0d, 0x2e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2e,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30, 0x01, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73, 0x73,
+	0x73, 0x79, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x64, 0x65, 0x62, 0x75, 0x67, 0x64, 0x2f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_debugd_service_debugd_proto_rawDescOnce sync.Once
+	file_debugd_service_debugd_proto_rawDescData = file_debugd_service_debugd_proto_rawDesc
+)
+
+func file_debugd_service_debugd_proto_rawDescGZIP() []byte {
+	file_debugd_service_debugd_proto_rawDescOnce.Do(func() {
+		file_debugd_service_debugd_proto_rawDescData = protoimpl.X.CompressGZIP(file_debugd_service_debugd_proto_rawDescData)
+	})
+	return file_debugd_service_debugd_proto_rawDescData
+}
+
+var file_debugd_service_debugd_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_debugd_service_debugd_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_debugd_service_debugd_proto_goTypes = []interface{}{
+	(SetInfoStatus)(0),                        // 0: debugd.SetInfoStatus
+	(Compression)(0),                          // 1: debugd.Compression
+	(UploadFilesStatus)(0),                    // 2: debugd.UploadFilesStatus
+	(FetchFilesStatus)(0),                     // 3: debugd.FetchFilesStatus
+	(UploadSystemdServiceUnitsStatus)(0),      // 4: debugd.UploadSystemdServiceUnitsStatus
+	(*SetInfoRequest)(nil),                    // 5: debugd.SetInfoRequest
+	(*SetInfoResponse)(nil),                   // 6: debugd.SetInfoResponse
+	(*GetInfoRequest)(nil),                    // 7: debugd.GetInfoRequest
+	(*GetInfoResponse)(nil),                   // 8: debugd.GetInfoResponse
+	(*Info)(nil),                              // 9: debugd.Info
+	(*DownloadFilesRequest)(nil),              // 10: debugd.DownloadFilesRequest
+	(*FileTransferMessage)(nil),               // 11: debugd.FileTransferMessage
+	(*FileTransferHeader)(nil),                // 12: debugd.FileTransferHeader
+	(*Chunk)(nil),                             // 13: debugd.Chunk
+	(*UploadFilesResponse)(nil),               // 14: debugd.UploadFilesResponse
+	(*FileState)(nil),                         // 15: debugd.FileState
+	(*GetFileStatesRequest)(nil),              // 16: debugd.GetFileStatesRequest
+	(*GetFileStatesResponse)(nil),             // 17: debugd.GetFileStatesResponse
+	(*FetchFilesRequest)(nil),                 // 18: debugd.FetchFilesRequest
+	(*FetchFilesResponse)(nil),                // 19: debugd.FetchFilesResponse
+	(*ServiceUnit)(nil),                       // 20: debugd.ServiceUnit
+	(*UploadSystemdServiceUnitsRequest)(nil),  // 21: debugd.UploadSystemdServiceUnitsRequest
+	(*UploadSystemdServiceUnitsResponse)(nil), // 22: debugd.UploadSystemdServiceUnitsResponse
+	(*GetJournalRequest)(nil),                 // 23: debugd.GetJournalRequest
+	(*JournalEntry)(nil),                      // 24: debugd.JournalEntry
+	(*ExecRequest)(nil),                       // 25: debugd.ExecRequest
+	(*ExecResponse)(nil),                      // 26: debugd.ExecResponse
+	(*GetDiagnosticBundleRequest)(nil),        // 27: debugd.GetDiagnosticBundleRequest
+}
+var file_debugd_service_debugd_proto_depIdxs = []int32{
+	9,  // 0: debugd.SetInfoRequest.info:type_name -> debugd.Info
+	0,  // 1: debugd.SetInfoResponse.status:type_name -> debugd.SetInfoStatus
+	9,  // 2: debugd.GetInfoResponse.info:type_name -> debugd.Info
+	15, // 3: debugd.DownloadFilesRequest.states:type_name -> debugd.FileState
+	1,  // 4: debugd.DownloadFilesRequest.compression:type_name -> debugd.Compression
+	12, // 5: debugd.FileTransferMessage.header:type_name -> debugd.FileTransferHeader
+	13, // 6: debugd.FileTransferMessage.chunk:type_name -> debugd.Chunk
+	1,  // 7: debugd.FileTransferHeader.compression:type_name -> debugd.Compression
+	2,  // 8: debugd.UploadFilesResponse.status:type_name -> debugd.UploadFilesStatus
+	15, // 9: debugd.GetFileStatesResponse.states:type_name -> debugd.FileState
+	3,  // 10: debugd.FetchFilesResponse.status:type_name -> debugd.FetchFilesStatus
+	20, // 11: debugd.UploadSystemdServiceUnitsRequest.units:type_name -> debugd.ServiceUnit
+	4,  // 12: debugd.UploadSystemdServiceUnitsResponse.status:type_name -> debugd.UploadSystemdServiceUnitsStatus
+	5,  // 13: debugd.Debugd.SetInfo:input_type -> debugd.SetInfoRequest
+	7,  // 14: debugd.Debugd.GetInfo:input_type -> debugd.GetInfoRequest
+	11, // 15: debugd.Debugd.UploadFiles:input_type -> debugd.FileTransferMessage
+	10, // 16: debugd.Debugd.DownloadFiles:input_type -> debugd.DownloadFilesRequest
+	16, // 17: debugd.Debugd.GetFileStates:input_type -> debugd.GetFileStatesRequest
+	18, // 18: debugd.Debugd.FetchFiles:input_type -> debugd.FetchFilesRequest
+	21, // 19: debugd.Debugd.UploadSystemServiceUnits:input_type -> debugd.UploadSystemdServiceUnitsRequest
+	23, // 20: debugd.Debugd.GetJournal:input_type -> debugd.GetJournalRequest
+	25, // 21: debugd.Debugd.Exec:input_type -> debugd.ExecRequest
+	27, // 22: debugd.Debugd.GetDiagnosticBundle:input_type -> debugd.GetDiagnosticBundleRequest
+	6,  // 23: debugd.Debugd.SetInfo:output_type -> debugd.SetInfoResponse
+	8,  // 24: debugd.Debugd.GetInfo:output_type -> debugd.GetInfoResponse
+	14, // 25: debugd.Debugd.UploadFiles:output_type -> debugd.UploadFilesResponse
+	11, // 26: debugd.Debugd.DownloadFiles:output_type -> debugd.FileTransferMessage
+	17, // 27: debugd.Debugd.GetFileStates:output_type -> debugd.GetFileStatesResponse
+	19, // 28: debugd.Debugd.FetchFiles:output_type -> debugd.FetchFilesResponse
+	22, // 29: debugd.Debugd.UploadSystemServiceUnits:output_type -> debugd.UploadSystemdServiceUnitsResponse
+	24, // 30: debugd.Debugd.GetJournal:output_type -> debugd.JournalEntry
+	26, // 31: debugd.Debugd.Exec:output_type -> debugd.ExecResponse
+	13, // 32: debugd.Debugd.GetDiagnosticBundle:output_type -> debugd.Chunk
+	23, // [23:33] is the sub-list for method output_type
+	13, // [13:23] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_debugd_service_debugd_proto_init() }
+func file_debugd_service_debugd_proto_init() {
+	if File_debugd_service_debugd_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_debugd_service_debugd_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Info); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadFilesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileTransferMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileTransferHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
 			case 2:
 				return &v.unknownFields
 			default:
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoResponse); i {
+		file_debugd_service_debugd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Chunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1067,8 +1932,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Info); i {
+		file_debugd_service_debugd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadFilesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1079,8 +1944,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DownloadFilesRequest); i {
+		file_debugd_service_debugd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileState); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1091,8 +1956,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileTransferMessage); i {
+		file_debugd_service_debugd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileStatesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1103,8 +1968,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FileTransferHeader); i {
+		file_debugd_service_debugd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFileStatesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1115,8 +1980,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Chunk); i {
+		file_debugd_service_debugd_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchFilesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1127,8 +1992,8 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadFilesResponse); i {
+		file_debugd_service_debugd_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchFilesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1139,7 +2004,7 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_debugd_service_debugd_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ServiceUnit); i {
 			case 0:
 				return &v.state
@@ -1151,7 +2016,7 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_debugd_service_debugd_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadSystemdServiceUnitsRequest); i {
 			case 0:
 				return &v.state
@@ -1163,7 +2028,7 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
-		file_debugd_service_debugd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_debugd_service_debugd_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UploadSystemdServiceUnitsResponse); i {
 			case 0:
 				return &v.state
@@ -1175,19 +2040,84 @@ func file_debugd_service_debugd_proto_init() {
 				return nil
 			}
 		}
+		file_debugd_service_debugd_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetJournalRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JournalEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_debugd_service_debugd_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDiagnosticBundleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_debugd_service_debugd_proto_msgTypes[6].OneofWrappers = []interface{}{
 		(*FileTransferMessage_Header)(nil),
 		(*FileTransferMessage_Chunk)(nil),
 	}
 	file_debugd_service_debugd_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_debugd_service_debugd_proto_msgTypes[21].OneofWrappers = []interface{}{
+		(*ExecResponse_Stdout)(nil),
+		(*ExecResponse_Stderr)(nil),
+		(*ExecResponse_ExitCode)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_debugd_service_debugd_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   13,
+			NumEnums:      5,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1218,7 +2148,13 @@ type DebugdClient interface {
 	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
 	UploadFiles(ctx context.Context, opts ...grpc.CallOption) (Debugd_UploadFilesClient, error)
 	DownloadFiles(ctx context.Context, in *DownloadFilesRequest, opts ...grpc.CallOption) (Debugd_DownloadFilesClient, error)
+	GetFileStates(ctx context.Context, in *GetFileStatesRequest, opts ...grpc.CallOption) (*GetFileStatesResponse, error)
+	FetchFiles(ctx context.Context, in *FetchFilesRequest, opts ...grpc.CallOption) (*FetchFilesResponse, error)
 	UploadSystemServiceUnits(ctx context.Context, in *UploadSystemdServiceUnitsRequest, opts ...grpc.CallOption) (*UploadSystemdServiceUnitsResponse, error)
+	// The following RPCs require an access token, see debugd/internal/debugd/accesstoken.
+	GetJournal(ctx context.Context, in *GetJournalRequest, opts ...grpc.CallOption) (Debugd_GetJournalClient, error)
+	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Debugd_ExecClient, error)
+	GetDiagnosticBundle(ctx context.Context, in *GetDiagnosticBundleRequest, opts ...grpc.CallOption) (Debugd_GetDiagnosticBundleClient, error)
 }
 
 type debugdClient struct {
@@ -1313,6 +2249,24 @@ func (x *debugdDownloadFilesClient) Recv() (*FileTransferMessage, error) {
 	return m, nil
 }
 
+func (c *debugdClient) GetFileStates(ctx context.Context, in *GetFileStatesRequest, opts ...grpc.CallOption) (*GetFileStatesResponse, error) {
+	out := new(GetFileStatesResponse)
+	err := c.cc.Invoke(ctx, "/debugd.Debugd/GetFileStates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *debugdClient) FetchFiles(ctx context.Context, in *FetchFilesRequest, opts ...grpc.CallOption) (*FetchFilesResponse, error) {
+	out := new(FetchFilesResponse)
+	err := c.cc.Invoke(ctx, "/debugd.Debugd/FetchFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *debugdClient) UploadSystemServiceUnits(ctx context.Context, in *UploadSystemdServiceUnitsRequest, opts ...grpc.CallOption) (*UploadSystemdServiceUnitsResponse, error) {
 	out := new(UploadSystemdServiceUnitsResponse)
 	err := c.cc.Invoke(ctx, "/debugd.Debugd/UploadSystemServiceUnits", in, out, opts...)
@@ -1322,13 +2276,115 @@ func (c *debugdClient) UploadSystemServiceUnits(ctx context.Context, in *UploadS
 	return out, nil
 }
 
+func (c *debugdClient) GetJournal(ctx context.Context, in *GetJournalRequest, opts ...grpc.CallOption) (Debugd_GetJournalClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Debugd_serviceDesc.Streams[2], "/debugd.Debugd/GetJournal", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugdGetJournalClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Debugd_GetJournalClient interface {
+	Recv() (*JournalEntry, error)
+	grpc.ClientStream
+}
+
+type debugdGetJournalClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugdGetJournalClient) Recv() (*JournalEntry, error) {
+	m := new(JournalEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *debugdClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (Debugd_ExecClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Debugd_serviceDesc.Streams[3], "/debugd.Debugd/Exec", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugdExecClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Debugd_ExecClient interface {
+	Recv() (*ExecResponse, error)
+	grpc.ClientStream
+}
+
+type debugdExecClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugdExecClient) Recv() (*ExecResponse, error) {
+	m := new(ExecResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *debugdClient) GetDiagnosticBundle(ctx context.Context, in *GetDiagnosticBundleRequest, opts ...grpc.CallOption) (Debugd_GetDiagnosticBundleClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Debugd_serviceDesc.Streams[4], "/debugd.Debugd/GetDiagnosticBundle", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &debugdGetDiagnosticBundleClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Debugd_GetDiagnosticBundleClient interface {
+	Recv() (*Chunk, error)
+	grpc.ClientStream
+}
+
+type debugdGetDiagnosticBundleClient struct {
+	grpc.ClientStream
+}
+
+func (x *debugdGetDiagnosticBundleClient) Recv() (*Chunk, error) {
+	m := new(Chunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DebugdServer is the server API for Debugd service.
 type DebugdServer interface {
 	SetInfo(context.Context, *SetInfoRequest) (*SetInfoResponse, error)
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
 	UploadFiles(Debugd_UploadFilesServer) error
 	DownloadFiles(*DownloadFilesRequest, Debugd_DownloadFilesServer) error
+	GetFileStates(context.Context, *GetFileStatesRequest) (*GetFileStatesResponse, error)
+	FetchFiles(context.Context, *FetchFilesRequest) (*FetchFilesResponse, error)
 	UploadSystemServiceUnits(context.Context, *UploadSystemdServiceUnitsRequest) (*UploadSystemdServiceUnitsResponse, error)
+	// The following RPCs require an access token, see debugd/internal/debugd/accesstoken.
+	GetJournal(*GetJournalRequest, Debugd_GetJournalServer) error
+	Exec(*ExecRequest, Debugd_ExecServer) error
+	GetDiagnosticBundle(*GetDiagnosticBundleRequest, Debugd_GetDiagnosticBundleServer) error
 }
 
 // UnimplementedDebugdServer can be embedded to have forward compatible implementations.
@@ -1347,9 +2403,24 @@ func (*UnimplementedDebugdServer) UploadFiles(Debugd_UploadFilesServer) error {
 func (*UnimplementedDebugdServer) DownloadFiles(*DownloadFilesRequest, Debugd_DownloadFilesServer) error {
 	return status.Errorf(codes.Unimplemented, "method DownloadFiles not implemented")
 }
+func (*UnimplementedDebugdServer) GetFileStates(context.Context, *GetFileStatesRequest) (*GetFileStatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFileStates not implemented")
+}
+func (*UnimplementedDebugdServer) FetchFiles(context.Context, *FetchFilesRequest) (*FetchFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchFiles not implemented")
+}
 func (*UnimplementedDebugdServer) UploadSystemServiceUnits(context.Context, *UploadSystemdServiceUnitsRequest) (*UploadSystemdServiceUnitsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UploadSystemServiceUnits not implemented")
 }
+func (*UnimplementedDebugdServer) GetJournal(*GetJournalRequest, Debugd_GetJournalServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetJournal not implemented")
+}
+func (*UnimplementedDebugdServer) Exec(*ExecRequest, Debugd_ExecServer) error {
+	return status.Errorf(codes.Unimplemented, "method Exec not implemented")
+}
+func (*UnimplementedDebugdServer) GetDiagnosticBundle(*GetDiagnosticBundleRequest, Debugd_GetDiagnosticBundleServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetDiagnosticBundle not implemented")
+}
 
 func RegisterDebugdServer(s *grpc.Server, srv DebugdServer) {
 	s.RegisterService(&_Debugd_serviceDesc, srv)
@@ -1438,6 +2509,42 @@ func (x *debugdDownloadFilesServer) Send(m *FileTransferMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Debugd_GetFileStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileStatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugdServer).GetFileStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/debugd.Debugd/GetFileStates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugdServer).GetFileStates(ctx, req.(*GetFileStatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Debugd_FetchFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DebugdServer).FetchFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/debugd.Debugd/FetchFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DebugdServer).FetchFiles(ctx, req.(*FetchFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Debugd_UploadSystemServiceUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UploadSystemdServiceUnitsRequest)
 	if err := dec(in); err != nil {
@@ -1456,6 +2563,69 @@ func _Debugd_UploadSystemServiceUnits_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Debugd_GetJournal_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetJournalRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugdServer).GetJournal(m, &debugdGetJournalServer{stream})
+}
+
+type Debugd_GetJournalServer interface {
+	Send(*JournalEntry) error
+	grpc.ServerStream
+}
+
+type debugdGetJournalServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugdGetJournalServer) Send(m *JournalEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Debugd_Exec_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugdServer).Exec(m, &debugdExecServer{stream})
+}
+
+type Debugd_ExecServer interface {
+	Send(*ExecResponse) error
+	grpc.ServerStream
+}
+
+type debugdExecServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugdExecServer) Send(m *ExecResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Debugd_GetDiagnosticBundle_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetDiagnosticBundleRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DebugdServer).GetDiagnosticBundle(m, &debugdGetDiagnosticBundleServer{stream})
+}
+
+type Debugd_GetDiagnosticBundleServer interface {
+	Send(*Chunk) error
+	grpc.ServerStream
+}
+
+type debugdGetDiagnosticBundleServer struct {
+	grpc.ServerStream
+}
+
+func (x *debugdGetDiagnosticBundleServer) Send(m *Chunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Debugd_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "debugd.Debugd",
 	HandlerType: (*DebugdServer)(nil),
@@ -1468,6 +2638,14 @@ var _Debugd_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetInfo",
 			Handler:    _Debugd_GetInfo_Handler,
 		},
+		{
+			MethodName: "GetFileStates",
+			Handler:    _Debugd_GetFileStates_Handler,
+		},
+		{
+			MethodName: "FetchFiles",
+			Handler:    _Debugd_FetchFiles_Handler,
+		},
 		{
 			MethodName: "UploadSystemServiceUnits",
 			Handler:    _Debugd_UploadSystemServiceUnits_Handler,
@@ -1484,6 +2662,21 @@ var _Debugd_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Debugd_DownloadFiles_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetJournal",
+			Handler:       _Debugd_GetJournal_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Exec",
+			Handler:       _Debugd_Exec_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetDiagnosticBundle",
+			Handler:       _Debugd_GetDiagnosticBundle_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "debugd/service/debugd.proto",
 }
@@ -9,6 +9,7 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"io"
 	"net"
 	"os"
@@ -30,6 +31,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/cloud/openstack"
 	qemucloud "github.com/edgelesssys/constellation/v2/internal/cloud/qemu"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
 	kmssetup "github.com/edgelesssys/constellation/v2/internal/kms/setup"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
@@ -127,10 +129,15 @@ func main() {
 
 	// Use TDX if available
 	openDevice := vtpm.OpenVTPM
-	if attestVariant.OID().Equal(variant.QEMUTDX{}.OID()) {
+	switch {
+	case attestVariant.Equal(variant.QEMUTDX{}):
 		openDevice = func() (io.ReadWriteCloser, error) {
 			return tdx.Open()
 		}
+	case attestVariant.Equal(variant.QEMUTDXSimulated{}):
+		openDevice = func() (io.ReadWriteCloser, error) {
+			return tdx.OpenSimulated(vtpm.OpenVTPM)
+		}
 	}
 	setupManger := setup.New(
 		log.Named("setupManager"),
@@ -171,9 +178,26 @@ func main() {
 
 		err = setupManger.PrepareExistingDisk(setup.NewNodeRecoverer(recoveryServer, rejoinClient))
 	} else {
-		err = setupManger.PrepareNewDisk()
+		var encryptionProfile profile.Profile
+		encryptionProfile, err = getDiskEncryptionProfile(context.Background(), metadataClient)
+		if err != nil {
+			log.With(zap.Error(err)).Fatalf("Failed to get disk encryption profile")
+		}
+		log.Infof("Using disk encryption profile %q", encryptionProfile.Name)
+		err = setupManger.PrepareNewDisk(encryptionProfile)
 	}
 	if err != nil {
 		log.With(zap.Error(err)).Fatalf("Failed to prepare state disk")
 	}
 }
+
+// getDiskEncryptionProfile returns the disk encryption profile configured for the instance.
+// The profile name set in the instance metadata isn't trusted. It's verified against the profile
+// of the cluster by the bootstrapper after the node was initialized or joined the cluster.
+func getDiskEncryptionProfile(ctx context.Context, metadataClient setup.MetadataAPI) (profile.Profile, error) {
+	name, err := metadataClient.DiskEncryptionProfile(ctx)
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("retrieving disk encryption profile from metadata: %w", err)
+	}
+	return profile.Get(name)
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/cryptsetup"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"go.uber.org/zap"
 )
@@ -55,8 +56,9 @@ func (d *DiskEncryption) DiskUUID() (string, error) {
 	return d.device.GetUUID()
 }
 
-// FormatDisk formats the disk and adds passphrase in keyslot 0.
-func (d *DiskEncryption) FormatDisk(passphrase string) error {
+// FormatDisk formats the disk using the given encryption profile and adds passphrase in keyslot 0.
+// The name of the profile is recorded in the disk's token.
+func (d *DiskEncryption) FormatDisk(passphrase string, encryptionProfile profile.Profile) error {
 	// Successfully calling LoadLUKS2() before FormatDisk() will cause format to fail.
 	// To make sure format is idempotent, we need to run it on a freshly initialized device.
 	// Therefore we free the device and reinitialize it.
@@ -65,7 +67,7 @@ func (d *DiskEncryption) FormatDisk(passphrase string) error {
 		return fmt.Errorf("re-initializing crypt device for disk %q: %w", d.devicePath, err)
 	}
 
-	if err := d.device.Format(cryptsetup.FormatIntegrity); err != nil {
+	if err := d.device.FormatWithProfile(encryptionProfile); err != nil {
 		return fmt.Errorf("formatting disk: %w", err)
 	}
 
@@ -73,17 +75,28 @@ func (d *DiskEncryption) FormatDisk(passphrase string) error {
 		return fmt.Errorf("adding keyslot: %w", err)
 	}
 
-	// wipe using 64MiB block size
-	if err := d.Wipe(67108864); err != nil {
-		return fmt.Errorf("wiping disk: %w", err)
+	// integrity checksums need to be initialized before the disk can be read
+	if encryptionProfile.HasIntegrity() {
+		// wipe using 64MiB block size
+		if err := d.Wipe(67108864); err != nil {
+			return fmt.Errorf("wiping disk: %w", err)
+		}
 	}
 
 	if err := d.device.SetConstellationStateDiskToken(cryptsetup.SetDiskNotInitialized); err != nil {
 		return fmt.Errorf("setting disk token: %w", err)
 	}
+	if err := d.device.SetConstellationStateDiskProfile(encryptionProfile.Name); err != nil {
+		return fmt.Errorf("setting disk encryption profile: %w", err)
+	}
 	return nil
 }
 
+// DiskProfile returns the encryption profile the disk was formatted with.
+func (d *DiskEncryption) DiskProfile() (profile.Profile, error) {
+	return profile.Get(d.device.ConstellationStateDiskProfile())
+}
+
 // MapDisk maps a crypt device to /dev/mapper/target using the provided passphrase.
 func (d *DiskEncryption) MapDisk(target, passphrase string) error {
 	if err := d.device.ActivateByPassphrase(target, 0, passphrase, cryptsetup.ReadWriteQueueBypass); err != nil {
@@ -92,6 +105,31 @@ func (d *DiskEncryption) MapDisk(target, passphrase string) error {
 	return nil
 }
 
+// DiskKeyVersion returns the version of the key the disk's passphrase was derived from.
+func (d *DiskEncryption) DiskKeyVersion() uint32 {
+	return d.device.ConstellationStateDiskKeyVersion()
+}
+
+// RotatePassphrase replaces the passphrase in keyslot 0 with newPassphrase,
+// and records newKeyVersion as the disk's key version.
+//
+// Only the passphrase keyslot is rotated. The volume key stays the same,
+// since LUKS2 online reencryption does not support devices with authenticated encryption.
+func (d *DiskEncryption) RotatePassphrase(passphrase, newPassphrase string, newKeyVersion uint32) error {
+	if err := d.device.KeyslotChangeByPassphrase(0, 0, passphrase, newPassphrase); err != nil {
+		return fmt.Errorf("changing passphrase of keyslot: %w", err)
+	}
+	return d.SetDiskKeyVersion(newKeyVersion)
+}
+
+// SetDiskKeyVersion records the version of the key the disk's passphrase was derived from.
+func (d *DiskEncryption) SetDiskKeyVersion(keyVersion uint32) error {
+	if err := d.device.SetConstellationStateDiskKeyVersion(keyVersion); err != nil {
+		return fmt.Errorf("setting disk key version: %w", err)
+	}
+	return nil
+}
+
 // UnmapDisk removes the mapping of target.
 func (d *DiskEncryption) UnmapDisk(target string) error {
 	return d.device.Deactivate(target)
@@ -122,13 +160,18 @@ type cryptDevice interface {
 	ActivateByPassphrase(deviceName string, keyslot int, passphrase string, flags int) error
 	ActivateByVolumeKey(deviceName string, volumeKey string, volumeKeySize int, flags int) error
 	Deactivate(deviceName string) error
-	Format(integrity bool) error
+	FormatWithProfile(encryptionProfile profile.Profile) error
 	Free()
 	GetUUID() (string, error)
 	Init(path string) (func(), error)
 	LoadLUKS2() error
 	KeyslotAddByVolumeKey(keyslot int, volumeKey string, passphrase string) error
+	KeyslotChangeByPassphrase(currentKeyslot int, newKeyslot int, currentPassphrase string, newPassphrase string) error
 	SetConstellationStateDiskToken(diskIsInitialized bool) error
 	ConstellationStateDiskTokenIsInitialized() bool
+	SetConstellationStateDiskProfile(name string) error
+	ConstellationStateDiskProfile() string
+	SetConstellationStateDiskKeyVersion(version uint32) error
+	ConstellationStateDiskKeyVersion() uint32
 	Wipe(name string, wipeBlockSize int, flags int, logCallback func(size, offset uint64), logFrequency time.Duration) error
 }
@@ -39,7 +39,7 @@ type kmsFactory func(ctx context.Context, storageURI string, kmsURI string) (kms
 type RecoveryServer struct {
 	mux sync.Mutex
 
-	diskUUID          string
+	diskKeyID         string
 	stateDiskKey      []byte
 	measurementSecret []byte
 	grpcServer        server
@@ -71,9 +71,10 @@ func New(issuer atls.Issuer, factory kmsFactory, log *logger.Logger) *RecoverySe
 // It blocks until a recover request call is successful.
 // The server will shut down when the call is successful and the keys are returned.
 // Additionally, the server can be shutdown by canceling the context.
-func (s *RecoveryServer) Serve(ctx context.Context, listener net.Listener, diskUUID string) (diskKey, measurementSecret []byte, err error) {
+// The state disk key is derived using diskKeyID, see crypto.StateDiskKeyID.
+func (s *RecoveryServer) Serve(ctx context.Context, listener net.Listener, diskKeyID string) (diskKey, measurementSecret []byte, err error) {
 	s.log.Infof("Starting RecoveryServer")
-	s.diskUUID = diskUUID
+	s.diskKeyID = diskKeyID
 	recoveryDone := make(chan struct{}, 1)
 	var serveErr error
 
@@ -118,7 +119,7 @@ func (s *RecoveryServer) Recover(ctx context.Context, req *recoverproto.RecoverM
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "requesting measurementSecret: %s", err)
 	}
-	stateDiskKey, err := cloudKms.GetDEK(ctx, crypto.DEKPrefix+s.diskUUID, crypto.StateDiskKeyLength)
+	stateDiskKey, err := cloudKms.GetDEK(ctx, crypto.DEKPrefix+s.diskKeyID, crypto.StateDiskKeyLength)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "requesting stateDiskKey: %s", err)
 	}
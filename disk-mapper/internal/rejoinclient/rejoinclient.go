@@ -37,8 +37,9 @@ const (
 // RejoinClient is a client for requesting the needed information
 // for rejoining a cluster as a restarting worker or control-plane node.
 type RejoinClient struct {
-	diskUUID string
-	nodeInfo metadata.InstanceMetadata
+	diskUUID       string
+	diskKeyVersion uint32
+	nodeInfo       metadata.InstanceMetadata
 
 	timeout  time.Duration
 	interval time.Duration
@@ -69,9 +70,11 @@ func New(dial grpcDialer, nodeInfo metadata.InstanceMetadata,
 // The client will continuously request available control-plane endpoints
 // from the metadata API and send rejoin requests to them.
 // The function returns after a successful rejoin request has been performed.
-func (c *RejoinClient) Start(ctx context.Context, diskUUID string) (diskKey, measurementSecret []byte) {
+// If the JoinService requests a rotation of the disk's key, the new key is returned as well.
+func (c *RejoinClient) Start(ctx context.Context, diskUUID string, diskKeyVersion uint32) (diskKey, measurementSecret []byte, keyRotation *joinproto.StateDiskKeyRotation) {
 	c.log.Infof("Starting RejoinClient")
 	c.diskUUID = diskUUID
+	c.diskKeyVersion = diskKeyVersion
 	ticker := c.clock.NewTicker(c.interval)
 
 	defer ticker.Stop()
@@ -83,40 +86,40 @@ func (c *RejoinClient) Start(ctx context.Context, diskUUID string) (diskKey, mea
 			c.log.With(zap.Error(err)).Errorf("Failed to get control-plane endpoints")
 		} else {
 			c.log.With(zap.Strings("endpoints", endpoints)).Infof("Received list with JoinService endpoints")
-			diskKey, measurementSecret, err = c.tryRejoinWithAvailableServices(ctx, endpoints)
+			rejoinTicket, err := c.tryRejoinWithAvailableServices(ctx, endpoints)
 			if err == nil {
 				c.log.Infof("Successfully retrieved rejoin ticket")
-				return diskKey, measurementSecret
+				return rejoinTicket.StateDiskKey, rejoinTicket.MeasurementSecret, rejoinTicket.StateDiskKeyRotation
 			}
 		}
 
 		select {
 		case <-ctx.Done():
-			return nil, nil
+			return nil, nil, nil
 		case <-ticker.C():
 		}
 	}
 }
 
 // tryRejoinWithAvailableServices tries sending rejoin requests to the available endpoints.
-func (c *RejoinClient) tryRejoinWithAvailableServices(ctx context.Context, endpoints []string) (diskKey, measurementSecret []byte, err error) {
+func (c *RejoinClient) tryRejoinWithAvailableServices(ctx context.Context, endpoints []string) (*joinproto.IssueRejoinTicketResponse, error) {
 	for _, endpoint := range endpoints {
 		c.log.With(zap.String("endpoint", endpoint)).Infof("Requesting rejoin ticket")
 		rejoinTicket, err := c.requestRejoinTicket(endpoint)
 		if err == nil {
-			return rejoinTicket.StateDiskKey, rejoinTicket.MeasurementSecret, nil
+			return rejoinTicket, nil
 		}
 		c.log.With(zap.Error(err), zap.String("endpoint", endpoint)).Warnf("Failed to rejoin on endpoint")
 
 		// stop requesting additional endpoints if the context is done
 		select {
 		case <-ctx.Done():
-			return nil, nil, ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 	}
 	c.log.Errorf("Failed to rejoin on all endpoints")
-	return nil, nil, errors.New("failed to join on all endpoints")
+	return nil, errors.New("failed to join on all endpoints")
 }
 
 // requestRejoinTicket requests a rejoin ticket from the endpoint.
@@ -130,7 +133,11 @@ func (c *RejoinClient) requestRejoinTicket(endpoint string) (*joinproto.IssueRej
 	}
 	defer conn.Close()
 
-	return joinproto.NewAPIClient(conn).IssueRejoinTicket(ctx, &joinproto.IssueRejoinTicketRequest{DiskUuid: c.diskUUID})
+	return joinproto.NewAPIClient(conn).IssueRejoinTicket(ctx, &joinproto.IssueRejoinTicketRequest{
+		DiskUuid:            c.diskUUID,
+		StateDiskKeyVersion: c.diskKeyVersion,
+		NodeName:            c.nodeInfo.Name,
+	})
 }
 
 // getJoinEndpoints requests the available control-plane endpoints from the metadata API.
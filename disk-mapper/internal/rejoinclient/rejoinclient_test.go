@@ -77,7 +77,7 @@ func TestStartCancel(t *testing.T) {
 
 	go func() {
 		defer wg.Done()
-		client.Start(ctx, "uuid")
+		client.Start(ctx, "uuid", 0)
 	}()
 
 	clock.Step(time.Millisecond)
@@ -233,20 +233,34 @@ func TestGetJoinEndpoints(t *testing.T) {
 
 func TestStart(t *testing.T) {
 	testCases := map[string]struct {
-		nodeInfo metadata.InstanceMetadata
+		nodeInfo    metadata.InstanceMetadata
+		keyRotation *joinproto.StateDiskKeyRotation
 	}{
 		"worker node": {
 			nodeInfo: metadata.InstanceMetadata{
+				Name:  "worker",
 				Role:  role.Worker,
 				VPCIP: "192.0.2.99",
 			},
 		},
 		"control-plane node": {
 			nodeInfo: metadata.InstanceMetadata{
+				Name:  "control-plane",
 				Role:  role.ControlPlane,
 				VPCIP: "192.0.2.99",
 			},
 		},
+		"key rotation requested": {
+			nodeInfo: metadata.InstanceMetadata{
+				Name:  "worker",
+				Role:  role.Worker,
+				VPCIP: "192.0.2.99",
+			},
+			keyRotation: &joinproto.StateDiskKeyRotation{
+				StateDiskKey:        []byte("new-disk-key"),
+				StateDiskKeyVersion: 2,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -261,8 +275,9 @@ func TestStart(t *testing.T) {
 			rejoinServer := grpc.NewServer(grpc.Creds(serverCreds))
 			rejoinServiceAPI := &stubRejoinServiceAPI{
 				rejoinTicketResponse: &joinproto.IssueRejoinTicketResponse{
-					StateDiskKey:      diskKey,
-					MeasurementSecret: measurementSecret,
+					StateDiskKey:         diskKey,
+					MeasurementSecret:    measurementSecret,
+					StateDiskKeyRotation: tc.keyRotation,
 				},
 			}
 			joinproto.RegisterAPIServer(rejoinServer, rejoinServiceAPI)
@@ -294,9 +309,18 @@ func TestStart(t *testing.T) {
 
 			client := New(dialer, tc.nodeInfo, meta, logger.NewTest(t))
 
-			passphrase, secret := client.Start(context.Background(), "uuid")
+			passphrase, secret, keyRotation := client.Start(context.Background(), "uuid", 1)
 			assert.Equal(diskKey, passphrase)
 			assert.Equal(measurementSecret, secret)
+			if tc.keyRotation == nil {
+				assert.Nil(keyRotation)
+			} else {
+				assert.Equal(tc.keyRotation.StateDiskKey, keyRotation.StateDiskKey)
+				assert.Equal(tc.keyRotation.StateDiskKeyVersion, keyRotation.StateDiskKeyVersion)
+			}
+			assert.Equal("uuid", rejoinServiceAPI.request.DiskUuid)
+			assert.Equal(uint32(1), rejoinServiceAPI.request.StateDiskKeyVersion)
+			assert.Equal(tc.nodeInfo.Name, rejoinServiceAPI.request.NodeName)
 		})
 	}
 }
@@ -318,11 +342,13 @@ func (s stubMetadataAPI) GetLoadBalancerEndpoint(_ context.Context) (string, str
 
 type stubRejoinServiceAPI struct {
 	rejoinTicketResponse *joinproto.IssueRejoinTicketResponse
+	request              *joinproto.IssueRejoinTicketRequest
 	err                  error
 	joinproto.UnimplementedAPIServer
 }
 
-func (s *stubRejoinServiceAPI) IssueRejoinTicket(context.Context, *joinproto.IssueRejoinTicketRequest,
+func (s *stubRejoinServiceAPI) IssueRejoinTicket(_ context.Context, req *joinproto.IssueRejoinTicketRequest,
 ) (*joinproto.IssueRejoinTicketResponse, error) {
+	s.request = req
 	return s.rejoinTicketResponse, s.err
 }
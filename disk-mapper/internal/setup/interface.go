@@ -12,6 +12,8 @@ import (
 	"os"
 
 	"github.com/edgelesssys/constellation/v2/internal/cloud/metadata"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
+	"github.com/edgelesssys/constellation/v2/joinservice/joinproto"
 )
 
 // Mounter is an interface for mount and unmount operations.
@@ -24,9 +26,13 @@ type Mounter interface {
 // DeviceMapper is an interface for device mapping operations.
 type DeviceMapper interface {
 	DiskUUID() (string, error)
-	FormatDisk(passphrase string) error
+	FormatDisk(passphrase string, encryptionProfile profile.Profile) error
+	DiskProfile() (profile.Profile, error)
 	MapDisk(target string, passphrase string) error
 	UnmapDisk(target string) error
+	DiskKeyVersion() uint32
+	RotatePassphrase(passphrase, newPassphrase string, newKeyVersion uint32) error
+	SetDiskKeyVersion(keyVersion uint32) error
 }
 
 // ConfigurationGenerator is an interface for generating systemd-cryptsetup@.service unit files.
@@ -39,12 +45,14 @@ type MetadataAPI interface {
 	metadata.InstanceSelfer
 	metadata.InstanceLister
 	GetLoadBalancerEndpoint(ctx context.Context) (host, port string, err error)
+	DiskEncryptionProfile(ctx context.Context) (string, error)
 }
 
 // RecoveryDoer is an interface to perform key recovery operations.
 // Calls to Do may be blocking, and if successful return a passphrase and measurementSecret.
+// If a rotation of the disk's key was requested, the new key is returned as well.
 type RecoveryDoer interface {
-	Do(uuid, endpoint string) (passphrase, measurementSecret []byte, err error)
+	Do(uuid, endpoint string, keyVersion uint32) (passphrase, measurementSecret []byte, keyRotation *joinproto.StateDiskKeyRotation, err error)
 }
 
 // DiskMounter uses the syscall package to mount disks.
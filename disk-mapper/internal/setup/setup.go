@@ -30,9 +30,11 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/nodestate"
+	"github.com/edgelesssys/constellation/v2/joinservice/joinproto"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
 )
@@ -42,7 +44,6 @@ const (
 	keyFile             = "state.key"
 	stateDiskMappedName = "state"
 	stateDiskMountPath  = "/var/run/state"
-	cryptsetupOptions   = "cipher=aes-xts-plain64,integrity=hmac-sha256"
 	stateInfoPath       = stateDiskMountPath + "/constellation/node_state.json"
 	msrdonly            = 0x1 // same as syscall.MS_RDONLY
 )
@@ -76,21 +77,29 @@ func New(log *logger.Logger, csp string, diskPath string, fs afero.Afero,
 }
 
 // PrepareExistingDisk requests and waits for a decryption key to remap the encrypted state disk.
+// If a rotation of the disk's key was requested, the disk's passphrase is replaced by the new key.
 // Once the disk is mapped, the function taints the node as initialized by updating it's PCRs.
 func (s *Manager) PrepareExistingDisk(recover RecoveryDoer) error {
 	uuid, err := s.mapper.DiskUUID()
 	if err != nil {
 		return err
 	}
-	s.log.With(zap.String("uuid", uuid)).Infof("Preparing existing state disk")
+	keyVersion := s.mapper.DiskKeyVersion()
+	s.log.With(zap.String("uuid", uuid), zap.Uint32("keyVersion", keyVersion)).Infof("Preparing existing state disk")
 	endpoint := net.JoinHostPort("0.0.0.0", strconv.Itoa(constants.RecoveryPort))
 
-	passphrase, measurementSecret, err := recover.Do(uuid, endpoint)
+	passphrase, measurementSecret, keyRotation, err := recover.Do(uuid, endpoint, keyVersion)
 	if err != nil {
 		return fmt.Errorf("failed to perform recovery: %w", err)
 	}
 
-	if err := s.mapper.MapDisk(stateDiskMappedName, string(passphrase)); err != nil {
+	encryptionProfile, err := s.mapper.DiskProfile()
+	if err != nil {
+		return err
+	}
+
+	passphrase, err = s.mapDisk(passphrase, keyRotation)
+	if err != nil {
 		return err
 	}
 
@@ -118,7 +127,7 @@ func (s *Manager) PrepareExistingDisk(recover RecoveryDoer) error {
 		return err
 	}
 
-	if err := s.saveConfiguration(passphrase); err != nil {
+	if err := s.saveConfiguration(passphrase, encryptionProfile); err != nil {
 		return err
 	}
 
@@ -126,26 +135,56 @@ func (s *Manager) PrepareExistingDisk(recover RecoveryDoer) error {
 }
 
 // PrepareNewDisk prepares an instances state disk by formatting the disk as a LUKS device using a random passphrase.
-func (s *Manager) PrepareNewDisk() error {
+// The disk is formatted using the given encryption profile.
+func (s *Manager) PrepareNewDisk(encryptionProfile profile.Profile) error {
 	uuid, _ := s.mapper.DiskUUID()
-	s.log.With(zap.String("uuid", uuid)).Infof("Preparing new state disk")
+	s.log.With(zap.String("uuid", uuid), zap.String("profile", encryptionProfile.Name)).Infof("Preparing new state disk")
 
 	// generate and save temporary passphrase
 	passphrase := make([]byte, crypto.RNGLengthDefault)
 	if _, err := rand.Read(passphrase); err != nil {
 		return err
 	}
-	if err := s.saveConfiguration(passphrase); err != nil {
+	if err := s.saveConfiguration(passphrase, encryptionProfile); err != nil {
 		return err
 	}
 
-	if err := s.mapper.FormatDisk(string(passphrase)); err != nil {
+	if err := s.mapper.FormatDisk(string(passphrase), encryptionProfile); err != nil {
 		return err
 	}
 
 	return s.mapper.MapDisk(stateDiskMappedName, string(passphrase))
 }
 
+// mapDisk maps the state disk and rotates its passphrase if requested.
+// It returns the passphrase the disk can be unlocked with afterwards.
+func (s *Manager) mapDisk(passphrase []byte, keyRotation *joinproto.StateDiskKeyRotation) ([]byte, error) {
+	if err := s.mapper.MapDisk(stateDiskMappedName, string(passphrase)); err != nil {
+		if keyRotation == nil {
+			return nil, err
+		}
+		// A previous rotation may have been interrupted after replacing the passphrase,
+		// but before recording the new key version.
+		s.log.With(zap.Error(err)).Warnf("Mapping state disk failed, retrying with rotated key")
+		if err := s.mapper.MapDisk(stateDiskMappedName, string(keyRotation.StateDiskKey)); err != nil {
+			return nil, err
+		}
+		if err := s.mapper.SetDiskKeyVersion(keyRotation.StateDiskKeyVersion); err != nil {
+			return nil, err
+		}
+		return keyRotation.StateDiskKey, nil
+	}
+	if keyRotation == nil {
+		return passphrase, nil
+	}
+
+	s.log.With(zap.Uint32("keyVersion", keyRotation.StateDiskKeyVersion)).Infof("Rotating state disk passphrase")
+	if err := s.mapper.RotatePassphrase(string(passphrase), string(keyRotation.StateDiskKey), keyRotation.StateDiskKeyVersion); err != nil {
+		return nil, fmt.Errorf("rotating state disk passphrase: %w", err)
+	}
+	return keyRotation.StateDiskKey, nil
+}
+
 func (s *Manager) readMeasurementSalt(path string) ([]byte, error) {
 	handler := file.NewHandler(s.fs)
 	var state nodestate.NodeState
@@ -161,7 +200,7 @@ func (s *Manager) readMeasurementSalt(path string) ([]byte, error) {
 }
 
 // saveConfiguration saves the given passphrase and cryptsetup mapping configuration to disk.
-func (s *Manager) saveConfiguration(passphrase []byte) error {
+func (s *Manager) saveConfiguration(passphrase []byte, encryptionProfile profile.Profile) error {
 	// passphrase
 	if err := s.fs.MkdirAll(keyPath, os.ModePerm); err != nil {
 		return err
@@ -171,7 +210,7 @@ func (s *Manager) saveConfiguration(passphrase []byte) error {
 	}
 
 	// systemd cryptsetup unit
-	return s.config.Generate(stateDiskMappedName, s.diskPath, filepath.Join(keyPath, keyFile), cryptsetupOptions)
+	return s.config.Generate(stateDiskMappedName, s.diskPath, filepath.Join(keyPath, keyFile), encryptionProfile.CrypttabOptions())
 }
 
 // LogDevices logs all available block devices and partitions (lsblk like).
@@ -226,7 +265,7 @@ type RecoveryServer interface {
 
 // RejoinClient interface starts a rejoin client.
 type RejoinClient interface {
-	Start(context.Context, string) (key, secret []byte)
+	Start(context.Context, string, uint32) (key, secret []byte, keyRotation *joinproto.StateDiskKeyRotation)
 }
 
 // NodeRecoverer bundles a RecoveryServer and RejoinClient.
@@ -246,13 +285,14 @@ func NewNodeRecoverer(recoveryServer RecoveryServer, rejoinClient RejoinClient)
 // Do performs a recovery procedure on the given state disk.
 // The method starts a gRPC server to allow manual recovery by a user.
 // At the same time it tries to request a decryption key from all available Constellation control-plane nodes.
-func (r *NodeRecoverer) Do(uuid, endpoint string) (passphrase, measurementSecret []byte, err error) {
+// Only the rejoin client can return a key rotation, manual recovery always uses the disk's current key version.
+func (r *NodeRecoverer) Do(uuid, endpoint string, keyVersion uint32) (passphrase, measurementSecret []byte, keyRotation *joinproto.StateDiskKeyRotation, err error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	lis, err := net.Listen("tcp", endpoint)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer lis.Close()
 
@@ -262,7 +302,7 @@ func (r *NodeRecoverer) Do(uuid, endpoint string) (passphrase, measurementSecret
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		key, secret, serveErr := r.recoveryServer.Serve(ctx, lis, uuid)
+		key, secret, serveErr := r.recoveryServer.Serve(ctx, lis, crypto.StateDiskKeyID(uuid, keyVersion))
 		once.Do(func() {
 			cancel()
 			passphrase = key
@@ -276,14 +316,15 @@ func (r *NodeRecoverer) Do(uuid, endpoint string) (passphrase, measurementSecret
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		key, secret := r.rejoinClient.Start(ctx, uuid)
+		key, secret, rotation := r.rejoinClient.Start(ctx, uuid, keyVersion)
 		once.Do(func() {
 			cancel()
 			passphrase = key
 			measurementSecret = secret
+			keyRotation = rotation
 		})
 	}()
 
 	wg.Wait()
-	return passphrase, measurementSecret, err
+	return passphrase, measurementSecret, keyRotation, err
 }
@@ -18,9 +18,11 @@ import (
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/nodestate"
+	"github.com/edgelesssys/constellation/v2/joinservice/joinproto"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,14 +40,23 @@ func TestPrepareExistingDisk(t *testing.T) {
 		secret:     []byte("secret"),
 	}
 
+	testKeyRotation := &joinproto.StateDiskKeyRotation{
+		StateDiskKey:        []byte("new-passphrase"),
+		StateDiskKeyVersion: 2,
+	}
+
 	testCases := map[string]struct {
-		recoveryDoer    *stubRecoveryDoer
-		mapper          *stubMapper
-		mounter         *stubMounter
-		configGenerator *stubConfigurationGenerator
-		openDevice      vtpm.TPMOpenFunc
-		missingState    bool
-		wantErr         bool
+		recoveryDoer      *stubRecoveryDoer
+		mapper            *stubMapper
+		mounter           *stubMounter
+		configGenerator   *stubConfigurationGenerator
+		openDevice        vtpm.TPMOpenFunc
+		missingState      bool
+		wantPassphrase    []byte
+		wantRotation      bool
+		wantKeyVersionSet bool
+		wantOptions       string
+		wantErr           bool
 	}{
 		"success": {
 			recoveryDoer:    testRecoveryDoer,
@@ -53,6 +64,48 @@ func TestPrepareExistingDisk(t *testing.T) {
 			mounter:         &stubMounter{},
 			configGenerator: &stubConfigurationGenerator{},
 			openDevice:      vtpm.OpenNOPTPM,
+			wantPassphrase:  []byte("passphrase"),
+		},
+		"key rotation": {
+			recoveryDoer: &stubRecoveryDoer{
+				passphrase:  []byte("passphrase"),
+				secret:      []byte("secret"),
+				keyRotation: testKeyRotation,
+			},
+			mapper:          &stubMapper{uuid: "test", keyVersion: 1},
+			mounter:         &stubMounter{},
+			configGenerator: &stubConfigurationGenerator{},
+			openDevice:      vtpm.OpenNOPTPM,
+			wantPassphrase:  []byte("new-passphrase"),
+			wantRotation:    true,
+		},
+		"key rotation fails": {
+			recoveryDoer: &stubRecoveryDoer{
+				passphrase:  []byte("passphrase"),
+				secret:      []byte("secret"),
+				keyRotation: testKeyRotation,
+			},
+			mapper:          &stubMapper{uuid: "test", rotatePassphraseErr: someErr},
+			mounter:         &stubMounter{},
+			configGenerator: &stubConfigurationGenerator{},
+			openDevice:      vtpm.OpenNOPTPM,
+			wantErr:         true,
+		},
+		"interrupted key rotation is completed": {
+			recoveryDoer: &stubRecoveryDoer{
+				passphrase:  []byte("passphrase"),
+				secret:      []byte("secret"),
+				keyRotation: testKeyRotation,
+			},
+			mapper: &stubMapper{
+				uuid:              "test",
+				mapDiskPassphrase: "new-passphrase",
+			},
+			mounter:           &stubMounter{},
+			configGenerator:   &stubConfigurationGenerator{},
+			openDevice:        vtpm.OpenNOPTPM,
+			wantPassphrase:    []byte("new-passphrase"),
+			wantKeyVersionSet: true,
 		},
 		"WaitForDecryptionKey fails": {
 			recoveryDoer:    &stubRecoveryDoer{recoveryErr: someErr},
@@ -73,6 +126,23 @@ func TestPrepareExistingDisk(t *testing.T) {
 			openDevice:      vtpm.OpenNOPTPM,
 			wantErr:         true,
 		},
+		"disk without integrity": {
+			recoveryDoer:    testRecoveryDoer,
+			mapper:          &stubMapper{uuid: "test", diskProfile: profile.AESXTS},
+			mounter:         &stubMounter{},
+			configGenerator: &stubConfigurationGenerator{},
+			openDevice:      vtpm.OpenNOPTPM,
+			wantPassphrase:  []byte("passphrase"),
+			wantOptions:     "cipher=aes-xts-plain64",
+		},
+		"DiskProfile fails": {
+			recoveryDoer:    testRecoveryDoer,
+			mapper:          &stubMapper{uuid: "test", diskProfileErr: someErr},
+			mounter:         &stubMounter{},
+			configGenerator: &stubConfigurationGenerator{},
+			openDevice:      vtpm.OpenNOPTPM,
+			wantErr:         true,
+		},
 		"MkdirAll fails": {
 			recoveryDoer:    testRecoveryDoer,
 			mapper:          &stubMapper{uuid: "test"},
@@ -155,6 +225,19 @@ func TestPrepareExistingDisk(t *testing.T) {
 				assert.True(tc.mounter.mountCalled)
 				assert.True(tc.mounter.unmountCalled)
 				assert.False(tc.mapper.formatDiskCalled)
+				assert.Equal(tc.mapper.keyVersion, tc.recoveryDoer.keyVersion)
+				assert.Equal(tc.wantRotation, tc.mapper.rotatePassphraseCalled)
+				assert.Equal(tc.wantKeyVersionSet, tc.mapper.setDiskKeyVersionCalled)
+
+				passphrase, err := fs.ReadFile(filepath.Join(keyPath, keyFile))
+				assert.NoError(err)
+				assert.Equal(tc.wantPassphrase, passphrase)
+
+				wantOptions := tc.wantOptions
+				if wantOptions == "" {
+					wantOptions = "cipher=aes-xts-plain64,integrity=hmac-sha256"
+				}
+				assert.Equal(wantOptions, tc.configGenerator.options)
 			}
 		})
 	}
@@ -170,12 +253,23 @@ func TestPrepareNewDisk(t *testing.T) {
 		fs              afero.Afero
 		mapper          *stubMapper
 		configGenerator *stubConfigurationGenerator
+		profile         string
+		wantOptions     string
 		wantErr         bool
 	}{
 		"success": {
 			fs:              afero.Afero{Fs: afero.NewMemMapFs()},
 			mapper:          &stubMapper{uuid: "test"},
 			configGenerator: &stubConfigurationGenerator{},
+			profile:         profile.AESXTSHMACSHA256,
+			wantOptions:     "cipher=aes-xts-plain64,integrity=hmac-sha256",
+		},
+		"success without integrity": {
+			fs:              afero.Afero{Fs: afero.NewMemMapFs()},
+			mapper:          &stubMapper{uuid: "test"},
+			configGenerator: &stubConfigurationGenerator{},
+			profile:         profile.AESXTS,
+			wantOptions:     "cipher=aes-xts-plain64",
 		},
 		"creating directory fails": {
 			fs:              afero.Afero{Fs: afero.NewReadOnlyFs(afero.NewMemMapFs())},
@@ -222,13 +316,18 @@ func TestPrepareNewDisk(t *testing.T) {
 				config:   tc.configGenerator,
 			}
 
-			err := setupManager.PrepareNewDisk()
+			encryptionProfile, err := profile.Get(tc.profile)
+			require.NoError(t, err)
+
+			err = setupManager.PrepareNewDisk(encryptionProfile)
 			if tc.wantErr {
 				assert.Error(err)
 			} else {
 				assert.NoError(err)
 				assert.True(tc.mapper.formatDiskCalled)
 				assert.True(tc.mapper.mapDiskCalled)
+				assert.Equal(tc.profile, tc.mapper.formatDiskProfile)
+				assert.Equal(tc.wantOptions, tc.configGenerator.options)
 
 				data, err := tc.fs.ReadFile(filepath.Join(keyPath, keyFile))
 				require.NoError(t, err)
@@ -313,7 +412,7 @@ func TestRecoveryDoer(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		key, secret, err = recoverer.Do("", "")
+		key, secret, _, err = recoverer.Do("", "", 0)
 	}()
 	recoveryServer.sendKeys <- struct{}{}
 	wg.Wait()
@@ -326,7 +425,7 @@ func TestRecoveryDoer(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		key, secret, err = recoverer.Do("", "")
+		key, secret, _, err = recoverer.Do("", "", 0)
 	}()
 	recoveryServer.sendKeys <- struct{}{}
 	wg.Wait()
@@ -340,7 +439,7 @@ func TestRecoveryDoer(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		key, secret, err = recoverer.Do("", "")
+		key, secret, _, err = recoverer.Do("", "", 0)
 	}()
 	rejoinClient.sendKeys <- struct{}{}
 	wg.Wait()
@@ -373,41 +472,74 @@ type stubRejoinClient struct {
 	sendKeys chan struct{}
 }
 
-func (s *stubRejoinClient) Start(ctx context.Context, _ string) ([]byte, []byte) {
+func (s *stubRejoinClient) Start(ctx context.Context, _ string, _ uint32) ([]byte, []byte, *joinproto.StateDiskKeyRotation) {
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, nil
+			return nil, nil, nil
 		case <-s.sendKeys:
-			return s.key, s.secret
+			return s.key, s.secret, nil
 		}
 	}
 }
 
 type stubMapper struct {
-	formatDiskCalled bool
-	formatDiskErr    error
-	mapDiskCalled    bool
-	mapDiskErr       error
-	unmapDiskCalled  bool
-	unmapDiskErr     error
-	uuid             string
+	formatDiskCalled        bool
+	formatDiskErr           error
+	formatDiskProfile       string
+	diskProfile             string
+	diskProfileErr          error
+	mapDiskCalled           bool
+	mapDiskErr              error
+	mapDiskPassphrase       string
+	unmapDiskCalled         bool
+	unmapDiskErr            error
+	uuid                    string
+	keyVersion              uint32
+	rotatePassphraseCalled  bool
+	rotatePassphraseErr     error
+	setDiskKeyVersionCalled bool
 }
 
 func (s *stubMapper) DiskUUID() (string, error) {
 	return s.uuid, nil
 }
 
-func (s *stubMapper) FormatDisk(string) error {
+func (s *stubMapper) FormatDisk(_ string, encryptionProfile profile.Profile) error {
 	s.formatDiskCalled = true
+	s.formatDiskProfile = encryptionProfile.Name
 	return s.formatDiskErr
 }
 
-func (s *stubMapper) MapDisk(string, string) error {
+func (s *stubMapper) DiskProfile() (profile.Profile, error) {
+	if s.diskProfileErr != nil {
+		return profile.Profile{}, s.diskProfileErr
+	}
+	return profile.Get(s.diskProfile)
+}
+
+func (s *stubMapper) MapDisk(_ string, passphrase string) error {
 	s.mapDiskCalled = true
+	if s.mapDiskPassphrase != "" && passphrase != s.mapDiskPassphrase {
+		return errors.New("wrong passphrase")
+	}
 	return s.mapDiskErr
 }
 
+func (s *stubMapper) DiskKeyVersion() uint32 {
+	return s.keyVersion
+}
+
+func (s *stubMapper) RotatePassphrase(_, _ string, _ uint32) error {
+	s.rotatePassphraseCalled = true
+	return s.rotatePassphraseErr
+}
+
+func (s *stubMapper) SetDiskKeyVersion(uint32) error {
+	s.setDiskKeyVersionCalled = true
+	return nil
+}
+
 func (s *stubMapper) UnmapDisk(string) error {
 	s.unmapDiskCalled = true
 	return s.unmapDiskErr
@@ -442,17 +574,22 @@ func (s *stubMounter) MkdirAll(_ string, _ fs.FileMode) error {
 type stubRecoveryDoer struct {
 	passphrase  []byte
 	secret      []byte
+	keyRotation *joinproto.StateDiskKeyRotation
+	keyVersion  uint32
 	recoveryErr error
 }
 
-func (s *stubRecoveryDoer) Do(_, _ string) (passphrase, measurementSecret []byte, err error) {
-	return s.passphrase, s.secret, s.recoveryErr
+func (s *stubRecoveryDoer) Do(_, _ string, keyVersion uint32) (passphrase, measurementSecret []byte, keyRotation *joinproto.StateDiskKeyRotation, err error) {
+	s.keyVersion = keyVersion
+	return s.passphrase, s.secret, s.keyRotation, s.recoveryErr
 }
 
 type stubConfigurationGenerator struct {
+	options     string
 	generateErr error
 }
 
-func (s *stubConfigurationGenerator) Generate(_, _, _, _ string) error {
+func (s *stubConfigurationGenerator) Generate(_, _, _, options string) error {
+	s.options = options
 	return s.generateErr
 }
@@ -10,10 +10,14 @@ package integration
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/edgelesssys/constellation/v2/disk-mapper/internal/diskencryption"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/martinjungblut/go-cryptsetup"
 	"go.uber.org/zap/zapcore"
@@ -45,7 +49,11 @@ func BenchmarkMapper(b *testing.B) {
 	}
 	defer free()
 
-	if err := mapper.FormatDisk(passphrase); err != nil {
+	defaultProfile, err := profile.Get(profile.Default)
+	if err != nil {
+		b.Fatal("Failed to get default profile:", err)
+	}
+	if err := mapper.FormatDisk(passphrase, defaultProfile); err != nil {
 		b.Fatal("Failed to format disk:", err)
 	}
 
@@ -112,3 +120,77 @@ func BenchmarkMapper(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkProfiles measures the sequential write throughput of a mapped disk for every disk encryption profile.
+func BenchmarkProfiles(b *testing.B) {
+	cryptsetup.SetDebugLevel(cryptsetup.CRYPT_LOG_ERROR)
+	cryptsetup.SetLogCallback(func(_ int, message string) { fmt.Println(message) })
+
+	testPath := *diskPath
+	if testPath == "" {
+		// no disk specified, use 1GB loopback disk
+		testPath = devicePath
+		if err := setup(1); err != nil {
+			b.Fatal("Failed to setup test environment:", err)
+		}
+
+		defer func() {
+			if err := teardown(); err != nil {
+				b.Fatal("failed to delete test disk:", err)
+			}
+		}()
+	}
+
+	passphrase := "benchmark"
+	blockSize := int(math.Pow(2, 22)) // 4MiB
+	block := make([]byte, blockSize)
+
+	for _, name := range profile.Names() {
+		b.Run(name, func(b *testing.B) {
+			encryptionProfile, err := profile.Get(name)
+			if err != nil {
+				b.Fatal("Failed to get profile:", err)
+			}
+
+			mapper, free, err := diskencryption.New(testPath, logger.New(logger.PlainLog, zapcore.InfoLevel))
+			if err != nil {
+				b.Fatal("Failed to create mapper:", err)
+			}
+			defer free()
+
+			if err := mapper.FormatDisk(passphrase, encryptionProfile); err != nil {
+				b.Fatal("Failed to format disk:", err)
+			}
+			if err := mapper.MapDisk(mappedDevice, passphrase); err != nil {
+				b.Fatal("Failed to map disk:", err)
+			}
+			defer func() {
+				if err := mapper.UnmapDisk(mappedDevice); err != nil {
+					b.Fatal("Failed to unmap disk:", err)
+				}
+			}()
+
+			device, err := os.OpenFile(filepath.Join("/dev/mapper", mappedDevice), os.O_WRONLY, 0)
+			if err != nil {
+				b.Fatal("Failed to open mapped disk:", err)
+			}
+			defer device.Close()
+			size, err := device.Seek(0, io.SeekEnd)
+			if err != nil {
+				b.Fatal("Failed to get size of mapped disk:", err)
+			}
+			blocks := size / int64(blockSize)
+
+			b.SetBytes(int64(blockSize))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := device.WriteAt(block, (int64(i)%blocks)*int64(blockSize)); err != nil {
+					b.Fatal("Failed to write to mapped disk:", err)
+				}
+			}
+			if err := device.Sync(); err != nil {
+				b.Fatal("Failed to sync mapped disk:", err)
+			}
+		})
+	}
+}
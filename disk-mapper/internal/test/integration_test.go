@@ -22,6 +22,7 @@ import (
 	"github.com/bazelbuild/rules_go/go/runfiles"
 	"github.com/edgelesssys/constellation/v2/disk-mapper/internal/diskencryption"
 	ccryptsetup "github.com/edgelesssys/constellation/v2/internal/cryptsetup"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	cryptsetup "github.com/martinjungblut/go-cryptsetup"
 	"github.com/stretchr/testify/assert"
@@ -108,9 +109,12 @@ func TestMapper(t *testing.T) {
 
 	assert.False(mapper.IsInitialized())
 
+	defaultProfile, err := profile.Get(profile.Default)
+	require.NoError(err)
+
 	// Format and map disk
 	passphrase := "unit-test"
-	require.NoError(mapper.FormatDisk(passphrase), "failed to format disk")
+	require.NoError(mapper.FormatDisk(passphrase, defaultProfile), "failed to format disk")
 	require.NoError(mapper.MapDisk(mappedDevice, passphrase), "failed to map disk")
 	require.NoError(mapper.UnmapDisk(mappedDevice), "failed to remove disk mapping")
 
@@ -131,6 +135,7 @@ func TestMapper(t *testing.T) {
 	require.NoError(json.Unmarshal([]byte(tokenJSON), &token))
 	assert.False(token.DiskIsInitialized, "disk should be marked as not initialized")
 	assert.False(ccrypt.ConstellationStateDiskTokenIsInitialized(), "disk should be marked as not initialized")
+	assert.Equal(profile.Default, ccrypt.ConstellationStateDiskProfile(), "profile should have been recorded")
 
 	// Disk should still be marked as not initialized because token is set to false.
 	assert.False(mapper.IsInitialized())
@@ -140,7 +145,17 @@ func TestMapper(t *testing.T) {
 
 	// Disk can be reformatted without manually re-initializing a mapper
 	passphrase2 := passphrase + "2"
-	require.NoError(mapper.FormatDisk(passphrase2), "failed to format disk")
+	require.NoError(mapper.FormatDisk(passphrase2, defaultProfile), "failed to format disk")
 	require.NoError(mapper.MapDisk(mappedDevice, passphrase2), "failed to map disk")
 	require.NoError(mapper.UnmapDisk(mappedDevice), "failed to remove disk mapping")
+
+	// Disk can be formatted with a different profile
+	noIntegrityProfile, err := profile.Get(profile.AESXTS)
+	require.NoError(err)
+	require.NoError(mapper.FormatDisk(passphrase, noIntegrityProfile), "failed to format disk")
+	diskProfile, err := mapper.DiskProfile()
+	require.NoError(err)
+	assert.Equal(noIntegrityProfile, diskProfile)
+	require.NoError(mapper.MapDisk(mappedDevice, passphrase), "failed to map disk")
+	require.NoError(mapper.UnmapDisk(mappedDevice), "failed to remove disk mapping")
 }
@@ -40,7 +40,7 @@ func main() {
 
 	fh := file.NewHandler(afero.NewOsFs())
 	attFetcher := attestationconfigapi.NewFetcher()
-	conf, err := config.New(fh, filepath.Join(cwd, constants.ConfigFilename), attFetcher, true)
+	conf, err := config.New(fh, filepath.Join(cwd, constants.ConfigFilename), "", attFetcher, true)
 	var configValidationErr *config.ValidationError
 	if errors.As(err, &configValidationErr) {
 		fmt.Println(configValidationErr.LongMessage())
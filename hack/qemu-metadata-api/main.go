@@ -24,6 +24,7 @@ func main() {
 	targetNetwork := flag.String("network", "constellation-network", "Name of the network in QEMU to use")
 	libvirtURI := flag.String("libvirt-uri", "qemu:///system", "URI of the libvirt connection")
 	initSecretHash := flag.String("initsecrethash", "", "brcypt hash of the init secret")
+	diskEncryptionProfile := flag.String("diskencryptionprofile", "", "name of the disk encryption profile of the state disks")
 	flag.Parse()
 
 	log := logger.New(logger.JSONLog, zapcore.InfoLevel)
@@ -34,7 +35,7 @@ func main() {
 	}
 	defer conn.Close()
 
-	serv := server.New(log, *targetNetwork, *initSecretHash, &virtwrapper.Connect{Conn: conn})
+	serv := server.New(log, *targetNetwork, *initSecretHash, *diskEncryptionProfile, &virtwrapper.Connect{Conn: conn})
 	if err := serv.ListenAndServe(*bindPort); err != nil {
 		log.With(zap.Error(err)).Fatalf("Failed to serve")
 	}
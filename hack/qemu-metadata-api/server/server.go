@@ -23,19 +23,21 @@ import (
 
 // Server that provides QEMU metadata.
 type Server struct {
-	log               *logger.Logger
-	virt              virConnect
-	network           string
-	initSecretHashVal []byte
+	log                      *logger.Logger
+	virt                     virConnect
+	network                  string
+	initSecretHashVal        []byte
+	diskEncryptionProfileVal []byte
 }
 
 // New creates a new Server.
-func New(log *logger.Logger, network, initSecretHash string, conn virConnect) *Server {
+func New(log *logger.Logger, network, initSecretHash, diskEncryptionProfile string, conn virConnect) *Server {
 	return &Server{
-		log:               log,
-		virt:              conn,
-		network:           network,
-		initSecretHashVal: []byte(initSecretHash),
+		log:                      log,
+		virt:                     conn,
+		network:                  network,
+		initSecretHashVal:        []byte(initSecretHash),
+		diskEncryptionProfileVal: []byte(diskEncryptionProfile),
 	}
 }
 
@@ -47,6 +49,7 @@ func (s *Server) ListenAndServe(port string) error {
 	mux.Handle("/log", http.HandlerFunc(s.postLog))
 	mux.Handle("/endpoint", http.HandlerFunc(s.getEndpoint))
 	mux.Handle("/initsecrethash", http.HandlerFunc(s.initSecretHash))
+	mux.Handle("/diskencryptionprofile", http.HandlerFunc(s.diskEncryptionProfile))
 
 	server := http.Server{
 		Handler: mux,
@@ -136,6 +139,27 @@ func (s *Server) initSecretHash(w http.ResponseWriter, r *http.Request) {
 	log.Infof("Request successful")
 }
 
+// diskEncryptionProfile returns the name of the disk encryption profile.
+// An empty body is returned if no profile is set.
+func (s *Server) diskEncryptionProfile(w http.ResponseWriter, r *http.Request) {
+	log := s.log.With(zap.String("diskEncryptionProfile", r.RemoteAddr))
+	if r.Method != http.MethodGet {
+		log.With(zap.String("method", r.Method)).Errorf("Invalid method for /diskencryptionprofile")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	log.Infof("Serving GET request for /diskencryptionprofile")
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, err := w.Write(s.diskEncryptionProfileVal)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed to write disk encryption profile")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Infof("Request successful")
+}
+
 // getEndpoint returns the IP address of the first control-plane instance.
 // This allows us to fake a load balancer for QEMU instances.
 func (s *Server) getEndpoint(w http.ResponseWriter, r *http.Request) {
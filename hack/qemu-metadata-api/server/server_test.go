@@ -68,7 +68,7 @@ func TestListAll(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			assert := assert.New(t)
 
-			server := New(logger.NewTest(t), "test", "initSecretHash", tc.connect)
+			server := New(logger.NewTest(t), "test", "initSecretHash", "", tc.connect)
 
 			res, err := server.listAll()
 
@@ -139,7 +139,7 @@ func TestListSelf(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			server := New(logger.NewTest(t), "test", "initSecretHash", tc.connect)
+			server := New(logger.NewTest(t), "test", "initSecretHash", "", tc.connect)
 
 			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://192.0.0.1/self", nil)
 			require.NoError(err)
@@ -199,7 +199,7 @@ func TestListPeers(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			server := New(logger.NewTest(t), "test", "initSecretHash", tc.connect)
+			server := New(logger.NewTest(t), "test", "initSecretHash", "", tc.connect)
 
 			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://192.0.0.1/peers", nil)
 			require.NoError(err)
@@ -254,7 +254,7 @@ func TestPostLog(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			server := New(logger.NewTest(t), "test", "initSecretHash", &stubConnect{})
+			server := New(logger.NewTest(t), "test", "initSecretHash", "", &stubConnect{})
 
 			req, err := http.NewRequestWithContext(context.Background(), tc.method, "http://192.0.0.1/logs", tc.message)
 			require.NoError(err)
@@ -303,7 +303,7 @@ func TestInitSecretHash(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			server := New(logger.NewTest(t), "test", tc.wantHash, defaultConnect)
+			server := New(logger.NewTest(t), "test", tc.wantHash, "", defaultConnect)
 
 			req, err := http.NewRequestWithContext(context.Background(), tc.method, "http://192.0.0.1/initsecrethash", nil)
 			require.NoError(err)
@@ -322,6 +322,49 @@ func TestInitSecretHash(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfile(t *testing.T) {
+	testCases := map[string]struct {
+		method      string
+		wantProfile string
+		wantErr     bool
+	}{
+		"success": {
+			method:      http.MethodGet,
+			wantProfile: "aes-xts",
+		},
+		"profile not set": {
+			method: http.MethodGet,
+		},
+		"wrong method": {
+			method:  http.MethodPost,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			server := New(logger.NewTest(t), "test", "initSecretHash", tc.wantProfile, &stubConnect{})
+
+			req, err := http.NewRequestWithContext(context.Background(), tc.method, "http://192.0.0.1/diskencryptionprofile", nil)
+			require.NoError(err)
+
+			w := httptest.NewRecorder()
+			server.diskEncryptionProfile(w, req)
+
+			if tc.wantErr {
+				assert.NotEqual(http.StatusOK, w.Code)
+				return
+			}
+
+			assert.Equal(http.StatusOK, w.Code)
+			assert.Equal(tc.wantProfile, w.Body.String())
+		})
+	}
+}
+
 type stubConnect struct {
 	network       stubNetwork
 	getNetworkErr error
@@ -29,6 +29,14 @@ VARIANTS = [
         "attestation_variant": "qemu-vtpm",
         "csp": "qemu",
     },
+    {
+        "attestation_variant": "qemu-tdx-simulated",
+        "csp": "qemu",
+    },
+    {
+        "attestation_variant": "qemu-sev-snp-simulated",
+        "csp": "qemu",
+    },
 ]
 
 STREAMS = [
@@ -115,6 +123,16 @@ attestation_variant_settings = {
             "constel.attestation-variant": "gcp-sev-snp",
         },
     },
+    "qemu-sev-snp-simulated": {
+        "kernel_command_line_dict": {
+            "constel.attestation-variant": "qemu-sev-snp-simulated",
+        },
+    },
+    "qemu-tdx-simulated": {
+        "kernel_command_line_dict": {
+            "constel.attestation-variant": "qemu-tdx-simulated",
+        },
+    },
     "qemu-vtpm": {
         "kernel_command_line_dict": {
             "constel.attestation-variant": "qemu-vtpm",
@@ -30,6 +30,7 @@ package attestation
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"crypto/sha256"
 
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
@@ -41,6 +42,8 @@ const (
 	// MeasurementSecretContext is the value to use for info
 	// when deriving the measurement secret from the master secret.
 	MeasurementSecretContext = "measurementSecret"
+	// simulationKeySeed is the seed of the key signing simulated attestation reports.
+	simulationKeySeed = "constellation simulated attestation"
 )
 
 // Logger is a logger used to print warnings and infos during attestation validation.
@@ -87,3 +90,11 @@ func CompareExtraData(quoteData, expectedData []byte) bool {
 	}
 	return bytes.Equal(quoteData, expectedData)
 }
+
+// SimulationSigningKey returns the key used to sign simulated hardware attestation reports.
+// The key is derived from a public seed, so anyone can forge simulated reports.
+// Simulated attestation only exercises the attestation flow and must never be used in production.
+func SimulationSigningKey() ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(simulationKeySeed))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/attestation/qemu"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 )
 
@@ -39,6 +40,10 @@ func Issuer(attestationVariant variant.Variant, log attestation.Logger) (atls.Is
 		return qemu.NewIssuer(log), nil
 	case variant.QEMUTDX{}:
 		return tdx.NewIssuer(log), nil
+	case variant.QEMUTDXSimulated{}:
+		return tdx.NewSimulatedIssuer(vtpm.OpenVTPM, log), nil
+	case variant.QEMUSEVSNPSimulated{}:
+		return qemu.NewSimulatedSNPIssuer(log), nil
 	case variant.Dummy{}:
 		return atls.NewFakeIssuer(variant.Dummy{}), nil
 	default:
@@ -63,6 +68,10 @@ func Validator(cfg config.AttestationCfg, log attestation.Logger) (atls.Validato
 		return qemu.NewValidator(cfg, log), nil
 	case *config.QEMUTDX:
 		return tdx.NewValidator(cfg, log), nil
+	case *config.QEMUTDXSimulated:
+		return tdx.NewSimulatedValidator(cfg, log), nil
+	case *config.QEMUSEVSNPSimulated:
+		return qemu.NewSimulatedSNPValidator(cfg, log), nil
 	case *config.DummyCfg:
 		return atls.NewFakeValidator(variant.Dummy{}), nil
 	default:
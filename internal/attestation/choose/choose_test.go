@@ -40,6 +40,12 @@ func TestIssuer(t *testing.T) {
 		"qemu-vtpm": {
 			variant: variant.QEMUVTPM{},
 		},
+		"qemu-tdx-simulated": {
+			variant: variant.QEMUTDXSimulated{},
+		},
+		"qemu-sev-snp-simulated": {
+			variant: variant.QEMUSEVSNPSimulated{},
+		},
 		"dummy": {
 			variant: variant.Dummy{},
 		},
@@ -86,6 +92,12 @@ func TestValidator(t *testing.T) {
 		"qemu-vtpm": {
 			cfg: &config.QEMUVTPM{},
 		},
+		"qemu-tdx-simulated": {
+			cfg: &config.QEMUTDXSimulated{},
+		},
+		"qemu-sev-snp-simulated": {
+			cfg: &config.QEMUSEVSNPSimulated{},
+		},
 		"dummy": {
 			cfg: &config.DummyCfg{},
 		},
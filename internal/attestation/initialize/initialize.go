@@ -28,6 +28,9 @@ func MarkNodeAsBootstrapped(openDevice func() (io.ReadWriteCloser, error), clust
 	}
 	defer device.Close()
 
+	if simulated, ok := device.(*tdx.SimulatedDevice); ok {
+		return simulated.ExtendRTMR(clusterID, measurements.RTMRIndexClusterID)
+	}
 	// The TDX device is of type *os.File, while the TPM device may be
 	// *os.File or an emulated device over a unix socket.
 	// Therefore, we can't simply use a type switch here,
@@ -46,18 +49,23 @@ func IsNodeBootstrapped(openDevice func() (io.ReadWriteCloser, error)) (bool, er
 	}
 	defer device.Close()
 
+	if simulated, ok := device.(*tdx.SimulatedDevice); ok {
+		return tdxIsNodeBootstrapped(simulated.ReadMeasurements)
+	}
 	// The TDX device is of type *os.File, while the TPM device may be
 	// *os.File or an emulated device over a unix socket.
 	// Therefore, we can't simply use a type switch here,
 	// since the TPM may implement the same methods as the TDX device
 	if handle, ok := tdx.IsTDXDevice(device); ok {
-		return tdxIsNodeBootstrapped(handle)
+		return tdxIsNodeBootstrapped(func() ([5][48]byte, error) {
+			return tdxapi.ReadMeasurements(handle)
+		})
 	}
 	return tpmIsNodeBootstrapped(device)
 }
 
-func tdxIsNodeBootstrapped(handle tdx.Device) (bool, error) {
-	tdMeasure, err := tdxapi.ReadMeasurements(handle)
+func tdxIsNodeBootstrapped(readMeasurements func() ([5][48]byte, error)) (bool, error) {
+	tdMeasure, err := readMeasurements()
 	if err != nil {
 		return false, err
 	}
@@ -509,6 +509,13 @@ func DefaultsFor(provider cloudprovider.Provider, attestationVariant variant.Var
 	case provider == cloudprovider.QEMU && attestationVariant == variant.QEMUVTPM{}:
 		return qemu_QEMUVTPM.Copy()
 
+	// Simulated variants use the same measurement registers as their hardware counterparts.
+	case provider == cloudprovider.QEMU && attestationVariant == variant.QEMUTDXSimulated{}:
+		return qemu_QEMUTDX.Copy()
+
+	case provider == cloudprovider.QEMU && attestationVariant == variant.QEMUSEVSNPSimulated{}:
+		return qemu_QEMUVTPM.Copy()
+
 	default:
 		return nil
 	}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package qemu
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	tpmclient "github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm-tools/proto/attest"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// SimulatedSNPIssuer issues vTPM attestation documents bound to a simulated SEV-SNP report.
+// Like on SEV-SNP CVMs, the report binds the attestation key of the vTPM to the VM.
+// The report is signed by the public simulation key and provides no security guarantees.
+type SimulatedSNPIssuer struct {
+	variant.QEMUSEVSNPSimulated
+	*vtpm.Issuer
+}
+
+// NewSimulatedSNPIssuer initializes a new QEMU Issuer using simulated SEV-SNP attestation.
+func NewSimulatedSNPIssuer(log attestation.Logger) *SimulatedSNPIssuer {
+	return &SimulatedSNPIssuer{
+		Issuer: vtpm.NewIssuer(
+			vtpm.OpenVTPM,
+			tpmclient.AttestationKeyRSA,
+			getSimulatedSNPReport,
+			log,
+		),
+	}
+}
+
+// SimulatedSNPValidator validates vTPM attestation documents bound to a simulated SEV-SNP report.
+type SimulatedSNPValidator struct {
+	variant.QEMUSEVSNPSimulated
+	*vtpm.Validator
+}
+
+// NewSimulatedSNPValidator initializes a new QEMU validator using simulated SEV-SNP attestation.
+func NewSimulatedSNPValidator(cfg *config.QEMUSEVSNPSimulated, log attestation.Logger) *SimulatedSNPValidator {
+	return &SimulatedSNPValidator{
+		Validator: vtpm.NewValidator(
+			cfg.Measurements,
+			trustedKeyFromSimulatedSNPReport,
+			func(vtpm.AttestationDocument, *attest.MachineState) error { return nil },
+			log,
+		),
+	}
+}
+
+// simulatedSNPReport mimics the parts of an SEV-SNP attestation report used to establish trust in the attestation key.
+type simulatedSNPReport struct {
+	// ReportData is the SHA-512 digest of the PKIX encoded attestation key.
+	ReportData []byte
+	// Signature is the signature over ReportData by the simulation key.
+	Signature []byte
+}
+
+// getSimulatedSNPReport returns a simulated SEV-SNP report binding the attestation key of the vTPM.
+func getSimulatedSNPReport(_ context.Context, tpm io.ReadWriteCloser, _ []byte) ([]byte, error) {
+	tpmAk, err := tpmclient.AttestationKeyRSA(tpm)
+	if err != nil {
+		return nil, fmt.Errorf("creating RSA attestation key: %w", err)
+	}
+	defer tpmAk.Close()
+
+	akDigest, err := akDigest(tpmAk.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(simulatedSNPReport{
+		ReportData: akDigest,
+		Signature:  ed25519.Sign(attestation.SimulationSigningKey(), akDigest),
+	})
+}
+
+// trustedKeyFromSimulatedSNPReport returns the attestation key if it's bound by the simulated SEV-SNP report.
+func trustedKeyFromSimulatedSNPReport(_ context.Context, attDoc vtpm.AttestationDocument, _ []byte) (crypto.PublicKey, error) {
+	pubArea, err := tpm2.DecodePublic(attDoc.Attestation.AkPub)
+	if err != nil {
+		return nil, fmt.Errorf("decoding attestation key: %w", err)
+	}
+	pubKey, err := pubArea.Key()
+	if err != nil {
+		return nil, fmt.Errorf("getting public key: %w", err)
+	}
+
+	var report simulatedSNPReport
+	if err := json.Unmarshal(attDoc.InstanceInfo, &report); err != nil {
+		return nil, fmt.Errorf("unmarshaling simulated SNP report: %w", err)
+	}
+	publicKey := attestation.SimulationSigningKey().Public().(ed25519.PublicKey)
+	if !ed25519.Verify(publicKey, report.ReportData, report.Signature) {
+		return nil, errors.New("invalid signature of simulated SNP report")
+	}
+
+	wantDigest, err := akDigest(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(report.ReportData, wantDigest) {
+		return nil, errors.New("simulated SNP report is not bound to the attestation key")
+	}
+	return pubKey, nil
+}
+
+// akDigest returns the SHA-512 digest of the PKIX encoded attestation key.
+func akDigest(key crypto.PublicKey) ([]byte, error) {
+	encoded, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling attestation key: %w", err)
+	}
+	digest := sha512.Sum512(encoded)
+	return digest[:], nil
+}
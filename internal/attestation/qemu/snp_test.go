@@ -0,0 +1,88 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package qemu
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/simulator"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	tpmclient "github.com/google/go-tpm-tools/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatedSNPAttestation(t *testing.T) {
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("skipping test because CGO is disabled and tpm simulator requires it")
+	}
+
+	testCases := map[string]struct {
+		modifyReport func(*simulatedSNPReport)
+		wantErr      bool
+	}{
+		"success": {},
+		"report not bound to attestation key": {
+			modifyReport: func(report *simulatedSNPReport) {
+				report.ReportData[0] ^= 0xFF
+			},
+			wantErr: true,
+		},
+		"invalid signature": {
+			modifyReport: func(report *simulatedSNPReport) {
+				report.Signature[0] ^= 0xFF
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			openTPM, tpmCloser := simulator.NewSimulatedTPMOpenFunc()
+			defer tpmCloser.Close()
+
+			issuer := &SimulatedSNPIssuer{
+				Issuer: vtpm.NewIssuer(openTPM, tpmclient.AttestationKeyRSA, getSimulatedSNPReport, nil),
+			}
+			validator := NewSimulatedSNPValidator(&config.QEMUSEVSNPSimulated{Measurements: measurements.M{}}, nil)
+			assert.True(issuer.OID().Equal(validator.OID()))
+
+			userData := []byte("user data")
+			nonce := []byte("nonce")
+			attDoc, err := issuer.Issue(context.Background(), userData, nonce)
+			require.NoError(err)
+
+			if tc.modifyReport != nil {
+				var doc vtpm.AttestationDocument
+				require.NoError(json.Unmarshal(attDoc, &doc))
+				var report simulatedSNPReport
+				require.NoError(json.Unmarshal(doc.InstanceInfo, &report))
+				tc.modifyReport(&report)
+				doc.InstanceInfo, err = json.Marshal(report)
+				require.NoError(err)
+				attDoc, err = json.Marshal(doc)
+				require.NoError(err)
+			}
+
+			gotUserData, err := validator.Validate(context.Background(), attDoc, nonce)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(userData, gotUserData)
+		})
+	}
+}
@@ -0,0 +1,343 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package tdx
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	tdxpb "github.com/google/go-tdx-guest/proto/tdx"
+	"github.com/google/go-tdx-guest/verify/trust"
+	tpmclient "github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+)
+
+// SimulatedDevicePath is the file holding the extendable registers of the simulated TDX device.
+// It's located on a tmpfs, so the registers are reset on reboot, like the registers of a TDX guest.
+const SimulatedDevicePath = "/run/constellation/tdx-simulated.json"
+
+// MRTD, RTMR[0] and RTMR[1] of the simulated device are derived from the vTPM PCRs:
+// MRTD reflects the firmware, RTMR[0] the firmware configuration, and RTMR[1] the OS image.
+// RTMR[2] and RTMR[3] start zeroed and can be extended at runtime, e.g., to mark the node as initialized.
+var simulatedDerivedRegisters = [3][]int{
+	{0},
+	{1, 2, 3, 5, 6, 7},
+	{4, 8, 9, 11, 12, 13},
+}
+
+// SimulatedDevice emulates the measurement registers and quote generation of a TDX guest in software.
+// It wraps the vTPM of the VM, from which the registers measuring the boot are derived.
+// Simulated quotes are signed by the public simulation key and provide no security guarantees.
+type SimulatedDevice struct {
+	io.ReadWriteCloser
+	path string
+}
+
+// OpenSimulated opens the simulated TDX device of a QEMU VM on top of the TPM opened by openTPM.
+func OpenSimulated(openTPM func() (io.ReadWriteCloser, error)) (*SimulatedDevice, error) {
+	return openSimulated(SimulatedDevicePath, openTPM)
+}
+
+func openSimulated(path string, openTPM func() (io.ReadWriteCloser, error)) (*SimulatedDevice, error) {
+	tpm, err := openTPM()
+	if err != nil {
+		return nil, fmt.Errorf("opening vTPM: %w", err)
+	}
+	return &SimulatedDevice{ReadWriteCloser: tpm, path: path}, nil
+}
+
+// ReadMeasurements returns MRTD and RTMR[0-3] of the simulated device.
+func (d *SimulatedDevice) ReadMeasurements() ([5][48]byte, error) {
+	var res [5][48]byte
+
+	pcrs, err := tpmclient.ReadPCRs(d, tpm2.PCRSelection{
+		Hash: tpm2.AlgSHA256,
+		PCRs: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 11, 12, 13},
+	})
+	if err != nil {
+		return res, fmt.Errorf("reading PCRs: %w", err)
+	}
+	for idx, selection := range simulatedDerivedRegisters {
+		res[idx], _ = deriveRegister(pcrs.Pcrs, selection)
+	}
+
+	runtimeRegisters, err := d.load()
+	if err != nil {
+		return res, err
+	}
+	for i, rtmr := range runtimeRegisters {
+		res[len(simulatedDerivedRegisters)+i] = rtmr
+	}
+	return res, nil
+}
+
+// ExtendRTMR extends the RTMR with the given index by the SHA-384 digest of extendData,
+// the same way the TDX module does. Only RTMR[2] and RTMR[3] can be extended.
+func (d *SimulatedDevice) ExtendRTMR(extendData []byte, index uint8) error {
+	// MRTD is not an RTMR, so RTMR[i] is the register at index i+1.
+	runtimeIdx := int(index) + 1 - len(simulatedDerivedRegisters)
+	if runtimeIdx < 0 || runtimeIdx >= len(simulatedRuntimeRegisters{}) {
+		return fmt.Errorf("RTMR[%d] of the simulated device can't be extended", index)
+	}
+	registers, err := d.load()
+	if err != nil {
+		return err
+	}
+	registers[runtimeIdx] = extend(registers[runtimeIdx], extendData)
+	return d.store(registers)
+}
+
+// GenerateQuote generates a simulated quote over the registers of the device and the given report data.
+func (d *SimulatedDevice) GenerateQuote(reportData []byte) ([]byte, error) {
+	if len(reportData) > 64 {
+		return nil, fmt.Errorf("report data must not be longer than 64 bytes, got %d bytes", len(reportData))
+	}
+	registers, err := d.ReadMeasurements()
+	if err != nil {
+		return nil, err
+	}
+	quote := simulatedQuote{MRTD: registers[0]}
+	copy(quote.RTMR[:], registers[1:])
+	copy(quote.ReportData[:], reportData)
+	quote.Signature = ed25519.Sign(attestation.SimulationSigningKey(), quote.signedData())
+	return json.Marshal(quote)
+}
+
+// load returns the extendable registers. They are zeroed if they were never extended.
+func (d *SimulatedDevice) load() (simulatedRuntimeRegisters, error) {
+	var registers simulatedRuntimeRegisters
+	data, err := os.ReadFile(d.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return registers, nil
+	} else if err != nil {
+		return registers, fmt.Errorf("reading simulated TDX registers: %w", err)
+	}
+	if err := json.Unmarshal(data, &registers); err != nil {
+		return registers, fmt.Errorf("unmarshaling simulated TDX registers: %w", err)
+	}
+	return registers, nil
+}
+
+func (d *SimulatedDevice) store(registers simulatedRuntimeRegisters) error {
+	data, err := json.Marshal(registers)
+	if err != nil {
+		return fmt.Errorf("marshaling simulated TDX registers: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return fmt.Errorf("creating directory for simulated TDX registers: %w", err)
+	}
+	// Replace the file atomically, so concurrent readers never see partially written registers.
+	tmpPath := d.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("writing simulated TDX registers: %w", err)
+	}
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		return fmt.Errorf("writing simulated TDX registers: %w", err)
+	}
+	return nil
+}
+
+// SimulatedMeasurementsFromPCRs converts expected PCR measurements of a QEMU image
+// into the expected measurements of the simulated TDX device running that image.
+// Registers derived from PCRs that are missing in pcrs are omitted.
+// A derived register is only enforced if all PCRs it's derived from are enforced.
+func SimulatedMeasurementsFromPCRs(pcrs measurements.M) measurements.M {
+	pcrValues := make(map[uint32][]byte, len(pcrs))
+	for idx, pcr := range pcrs {
+		pcrValues[idx] = pcr.Expected
+	}
+
+	res := make(measurements.M)
+	for idx, selection := range simulatedDerivedRegisters {
+		register, ok := deriveRegister(pcrValues, selection)
+		if !ok {
+			continue
+		}
+		validationOpt := measurements.Enforce
+		for _, pcrIdx := range selection {
+			if pcrs[uint32(pcrIdx)].ValidationOpt == measurements.WarnOnly {
+				validationOpt = measurements.WarnOnly
+			}
+		}
+		res[uint32(idx)] = measurements.Measurement{Expected: register[:], ValidationOpt: validationOpt}
+	}
+	for idx := len(simulatedDerivedRegisters); idx < 5; idx++ {
+		res[uint32(idx)] = measurements.WithAllBytes(0x00, measurements.Enforce, measurements.TDXMeasurementLength)
+	}
+	return res
+}
+
+// SimulatedIssuer issues simulated TDX attestation documents.
+type SimulatedIssuer struct {
+	variant.QEMUTDXSimulated
+
+	open func() (*SimulatedDevice, error)
+	log  attestation.Logger
+}
+
+// NewSimulatedIssuer initializes a new simulated TDX Issuer using the TPM opened by openTPM.
+func NewSimulatedIssuer(openTPM func() (io.ReadWriteCloser, error), log attestation.Logger) *SimulatedIssuer {
+	if log == nil {
+		log = attestation.NOPLogger{}
+	}
+	return &SimulatedIssuer{
+		open: func() (*SimulatedDevice, error) { return OpenSimulated(openTPM) },
+		log:  log,
+	}
+}
+
+// Issue issues a simulated TDX attestation document.
+func (i *SimulatedIssuer) Issue(_ context.Context, userData []byte, nonce []byte) (attDoc []byte, err error) {
+	i.log.Infof("Issuing simulated attestation statement")
+	defer func() {
+		if err != nil {
+			i.log.Warnf("Failed to issue attestation document: %s", err)
+		}
+	}()
+
+	device, err := i.open()
+	if err != nil {
+		return nil, fmt.Errorf("opening simulated TDX device: %w", err)
+	}
+	defer device.Close()
+
+	quote, err := device.GenerateQuote(attestation.MakeExtraData(userData, nonce))
+	if err != nil {
+		return nil, fmt.Errorf("generating quote: %w", err)
+	}
+
+	rawAttDoc, err := json.Marshal(tdxAttestationDocument{
+		RawQuote: quote,
+		UserData: userData,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling attestation document: %w", err)
+	}
+
+	return rawAttDoc, nil
+}
+
+// SimulatedValidator validates simulated TDX attestation documents.
+// Apart from the quote verification, the same checks as for TDX attestation are applied.
+type SimulatedValidator struct {
+	variant.QEMUTDXSimulated
+	*Validator
+}
+
+// NewSimulatedValidator initializes a new simulated TDX Validator.
+func NewSimulatedValidator(cfg *config.QEMUTDXSimulated, log attestation.Logger) *SimulatedValidator {
+	if log == nil {
+		log = attestation.NOPLogger{}
+	}
+	return &SimulatedValidator{
+		Validator: &Validator{
+			tdx:      simulatedVerifier{},
+			expected: cfg.Measurements,
+			log:      log,
+		},
+	}
+}
+
+// simulatedVerifier verifies the signature of simulated quotes.
+type simulatedVerifier struct{}
+
+// Verify verifies a simulated quote and returns it as the body of a TDX quote.
+// Simulated quotes don't require any collateral, so the getter is ignored.
+func (simulatedVerifier) Verify(rawQuote []byte, _ trust.HTTPSGetter) (*tdxpb.TDQuoteBody, error) {
+	var quote simulatedQuote
+	if err := json.Unmarshal(rawQuote, &quote); err != nil {
+		return nil, fmt.Errorf("unmarshaling simulated quote: %w", err)
+	}
+	publicKey := attestation.SimulationSigningKey().Public().(ed25519.PublicKey)
+	if !ed25519.Verify(publicKey, quote.signedData(), quote.Signature) {
+		return nil, errors.New("invalid signature of simulated quote")
+	}
+	return quote.body(), nil
+}
+
+// Parse returns a simulated quote as the body of a TDX quote without verifying its signature.
+func (simulatedVerifier) Parse(rawQuote []byte) (*tdxpb.TDQuoteBody, error) {
+	var quote simulatedQuote
+	if err := json.Unmarshal(rawQuote, &quote); err != nil {
+		return nil, fmt.Errorf("unmarshaling simulated quote: %w", err)
+	}
+	return quote.body(), nil
+}
+
+// GetSelectedSimulatedMeasurements returns the selected measurements from the simulated TDX device.
+func GetSelectedSimulatedMeasurements(openTPM func() (io.ReadWriteCloser, error), selection []int) (measurements.M, error) {
+	device, err := OpenSimulated(openTPM)
+	if err != nil {
+		return nil, err
+	}
+	defer device.Close()
+	return selectMeasurements(device.ReadMeasurements, selection)
+}
+
+// simulatedRuntimeRegisters are RTMR[2] and RTMR[3] of the simulated device.
+type simulatedRuntimeRegisters [2][48]byte
+
+type simulatedQuote struct {
+	MRTD       [48]byte
+	RTMR       [4][48]byte
+	ReportData [64]byte
+	Signature  []byte
+}
+
+// body returns the registers and report data of the quote as the body of a TDX quote.
+func (q simulatedQuote) body() *tdxpb.TDQuoteBody {
+	rtmrs := make([][]byte, len(q.RTMR))
+	for idx := range q.RTMR {
+		rtmrs[idx] = q.RTMR[idx][:]
+	}
+	return &tdxpb.TDQuoteBody{
+		MrTd:       q.MRTD[:],
+		Rtmrs:      rtmrs,
+		ReportData: q.ReportData[:],
+	}
+}
+
+func (q simulatedQuote) signedData() []byte {
+	data := append([]byte{}, q.MRTD[:]...)
+	for _, rtmr := range q.RTMR {
+		data = append(data, rtmr[:]...)
+	}
+	return append(data, q.ReportData[:]...)
+}
+
+// deriveRegister returns the register resulting from extending a zeroed register with the selected PCRs.
+// It returns false if any of the selected PCRs is missing.
+func deriveRegister(pcrs map[uint32][]byte, selection []int) ([48]byte, bool) {
+	var register [48]byte
+	for _, idx := range selection {
+		pcr, ok := pcrs[uint32(idx)]
+		if !ok {
+			return [48]byte{}, false
+		}
+		register = extend(register, pcr)
+	}
+	return register, true
+}
+
+// extend returns SHA384(register || SHA384(data)).
+func extend(register [48]byte, data []byte) [48]byte {
+	digest := sha512.Sum384(data)
+	return sha512.Sum384(bytes.Join([][]byte{register[:], digest[:]}, nil))
+}
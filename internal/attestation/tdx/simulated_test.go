@@ -0,0 +1,188 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package tdx
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/simulator"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatedAttestation(t *testing.T) {
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("skipping test because CGO is disabled and tpm simulator requires it")
+	}
+	clusterID := []byte("cluster-id")
+
+	testCases := map[string]struct {
+		markBootstrapped bool
+		modifyExpected   func(measurements.M)
+		modifyAttDoc     func(*tdxAttestationDocument)
+		nonce            []byte
+		wantErr          bool
+	}{
+		"success": {},
+		"bootstrapped node": {
+			markBootstrapped: true,
+		},
+		"measurement mismatch": {
+			modifyExpected: func(m measurements.M) {
+				m[1] = measurements.WithAllBytes(0x11, measurements.Enforce, measurements.TDXMeasurementLength)
+			},
+			wantErr: true,
+		},
+		"nonce mismatch": {
+			nonce:   []byte("other nonce"),
+			wantErr: true,
+		},
+		"forged quote": {
+			modifyAttDoc: func(attDoc *tdxAttestationDocument) {
+				var quote simulatedQuote
+				require.NoError(t, json.Unmarshal(attDoc.RawQuote, &quote))
+				quote.RTMR[0][0] ^= 0xFF
+				rawQuote, err := json.Marshal(quote)
+				require.NoError(t, err)
+				attDoc.RawQuote = rawQuote
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			tpm, err := simulator.OpenSimulatedTPM()
+			require.NoError(err)
+			defer tpm.Close()
+			openTPM := func() (io.ReadWriteCloser, error) { return nopCloser{tpm}, nil }
+			path := filepath.Join(t.TempDir(), "tdx-simulated.json")
+			open := func() (*SimulatedDevice, error) { return openSimulated(path, openTPM) }
+
+			device, err := open()
+			require.NoError(err)
+			registers, err := device.ReadMeasurements()
+			require.NoError(err)
+			assert.Equal([48]byte{}, registers[measurements.TDXIndexClusterID])
+			assert.NotEqual([48]byte{}, registers[0])
+
+			expected := measurements.M{}
+			for idx, register := range registers {
+				expected[uint32(idx)] = measurements.WithAllBytes(0x00, measurements.Enforce, measurements.TDXMeasurementLength)
+				copy(expected[uint32(idx)].Expected, register[:])
+			}
+			if tc.markBootstrapped {
+				require.NoError(device.ExtendRTMR(clusterID, measurements.RTMRIndexClusterID))
+				// Same calculation as the CLI uses to derive the expected measurement.
+				hashedClusterID := sha512.Sum384(clusterID)
+				clusterIDMeasurement := sha512.Sum384(append(make([]byte, 48), hashedClusterID[:]...))
+				expected[uint32(measurements.TDXIndexClusterID)] = measurements.Measurement{
+					Expected:      clusterIDMeasurement[:],
+					ValidationOpt: measurements.Enforce,
+				}
+			}
+			if tc.modifyExpected != nil {
+				tc.modifyExpected(expected)
+			}
+
+			issuer := NewSimulatedIssuer(openTPM, nil)
+			issuer.open = open
+			validator := NewSimulatedValidator(&config.QEMUTDXSimulated{Measurements: expected}, nil)
+			assert.True(issuer.OID().Equal(validator.OID()))
+
+			userData := []byte("user data")
+			nonce := []byte("nonce")
+			attDoc, err := issuer.Issue(context.Background(), userData, nonce)
+			require.NoError(err)
+			if tc.modifyAttDoc != nil {
+				var doc tdxAttestationDocument
+				require.NoError(json.Unmarshal(attDoc, &doc))
+				tc.modifyAttDoc(&doc)
+				attDoc, err = json.Marshal(doc)
+				require.NoError(err)
+			}
+			if tc.nonce != nil {
+				nonce = tc.nonce
+			}
+
+			gotUserData, err := validator.Validate(context.Background(), attDoc, nonce)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(userData, gotUserData)
+		})
+	}
+}
+
+type nopCloser struct {
+	io.ReadWriteCloser
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestSimulatedMeasurementsFromPCRs(t *testing.T) {
+	pcrs := measurements.M{
+		4:  measurements.WithAllBytes(0x44, measurements.Enforce, measurements.PCRMeasurementLength),
+		8:  measurements.WithAllBytes(0x88, measurements.Enforce, measurements.PCRMeasurementLength),
+		9:  measurements.WithAllBytes(0x99, measurements.Enforce, measurements.PCRMeasurementLength),
+		11: measurements.WithAllBytes(0x11, measurements.Enforce, measurements.PCRMeasurementLength),
+		12: measurements.WithAllBytes(0x12, measurements.WarnOnly, measurements.PCRMeasurementLength),
+		13: measurements.WithAllBytes(0x13, measurements.Enforce, measurements.PCRMeasurementLength),
+	}
+
+	testCases := map[string]struct {
+		pcrs         measurements.M
+		wantIndexes  []uint32
+		wantRTMR1Opt measurements.MeasurementValidationOption
+	}{
+		"firmware PCRs missing": {
+			pcrs:         pcrs,
+			wantIndexes:  []uint32{2, 3, 4},
+			wantRTMR1Opt: measurements.WarnOnly,
+		},
+		"image PCRs missing": {
+			pcrs:        measurements.M{},
+			wantIndexes: []uint32{3, 4},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got := SimulatedMeasurementsFromPCRs(tc.pcrs)
+
+			var gotIndexes []uint32
+			for idx := range got {
+				gotIndexes = append(gotIndexes, idx)
+			}
+			assert.ElementsMatch(tc.wantIndexes, gotIndexes)
+			for _, idx := range []uint32{3, 4} {
+				assert.Equal(measurements.WithAllBytes(0x00, measurements.Enforce, measurements.TDXMeasurementLength), got[idx])
+			}
+			if rtmr1, ok := got[2]; ok {
+				assert.Equal(tc.wantRTMR1Opt, rtmr1.ValidationOpt)
+				assert.Len(rtmr1.Expected, measurements.TDXMeasurementLength)
+			}
+		})
+	}
+}
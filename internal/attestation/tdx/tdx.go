@@ -34,19 +34,25 @@ type OpenFunc func() (Device, error)
 
 // GetSelectedMeasurements returns the selected measurements from the RTMRs.
 func GetSelectedMeasurements(open OpenFunc, selection []int) (measurements.M, error) {
-	for _, idx := range selection {
-		if idx < 0 || idx >= 5 {
-			return nil, fmt.Errorf("invalid measurement index %d", idx)
-		}
-	}
-
 	handle, err := open()
 	if err != nil {
 		return nil, err
 	}
 	defer handle.Close()
 
-	tdxMeasurements, err := tdx.ReadMeasurements(handle)
+	return selectMeasurements(func() ([5][48]byte, error) {
+		return tdx.ReadMeasurements(handle)
+	}, selection)
+}
+
+func selectMeasurements(read func() ([5][48]byte, error), selection []int) (measurements.M, error) {
+	for _, idx := range selection {
+		if idx < 0 || idx >= 5 {
+			return nil, fmt.Errorf("invalid measurement index %d", idx)
+		}
+	}
+
+	tdxMeasurements, err := read()
 	if err != nil {
 		return nil, err
 	}
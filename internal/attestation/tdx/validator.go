@@ -11,17 +11,29 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/config"
-	"github.com/edgelesssys/go-tdx-qpl/verification"
-	"github.com/edgelesssys/go-tdx-qpl/verification/types"
+	"github.com/google/go-tdx-guest/abi"
+	tdxpb "github.com/google/go-tdx-guest/proto/tdx"
+	"github.com/google/go-tdx-guest/verify"
+	"github.com/google/go-tdx-guest/verify/trust"
 )
 
+// CollateralGetter retrieves the collateral required to verify TDX quotes from the given URL.
+// Requests must be aborted once ctx is done.
+type CollateralGetter interface {
+	GetContext(ctx context.Context, url string) (header map[string][]string, body []byte, err error)
+}
+
 type tdxVerifier interface {
-	Verify(ctx context.Context, quote []byte) (types.SGXQuote4, error)
+	Verify(rawQuote []byte, getter trust.HTTPSGetter) (*tdxpb.TDQuoteBody, error)
+	Parse(rawQuote []byte) (*tdxpb.TDQuoteBody, error)
 }
 
 // Validator is the TDX attestation validator.
@@ -29,6 +41,7 @@ type Validator struct {
 	variant.QEMUTDX
 
 	tdx      tdxVerifier
+	getter   CollateralGetter
 	expected measurements.M
 
 	log attestation.Logger
@@ -41,13 +54,22 @@ func NewValidator(cfg *config.QEMUTDX, log attestation.Logger) *Validator {
 	}
 
 	return &Validator{
-		tdx:      verification.New(),
+		tdx:      quoteVerifier{},
+		getter:   NewPCSGetter(http.DefaultClient),
 		expected: cfg.Measurements,
 		log:      log,
 	}
 }
 
+// SetCollateralGetter sets the getter used to retrieve the collateral (TCB info, QE identity and CRLs)
+// required to verify TDX quotes. By default, the collateral is retrieved from Intel PCS.
+// The collateral is verified against Intel's root certificate, so the getter doesn't need to be trusted.
+func (v *Validator) SetCollateralGetter(getter CollateralGetter) {
+	v.getter = getter
+}
+
 // Validate validates the given attestation document using TDX attestation.
+// Retrieving the collateral to verify the quote is aborted once ctx is done.
 func (v *Validator) Validate(ctx context.Context, attDocRaw []byte, nonce []byte) (userData []byte, err error) {
 	v.log.Infof("Validating attestation document")
 	defer func() {
@@ -62,26 +84,19 @@ func (v *Validator) Validate(ctx context.Context, attDocRaw []byte, nonce []byte
 	}
 
 	// Verify the quote.
-	quote, err := v.tdx.Verify(ctx, attDoc.RawQuote)
+	body, err := v.tdx.Verify(attDoc.RawQuote, contextGetter{ctx: ctx, getter: v.getter})
 	if err != nil {
 		return nil, fmt.Errorf("verifying TDX quote: %w", err)
 	}
 
 	// Report data
 	extraData := attestation.MakeExtraData(attDoc.UserData, nonce)
-	if !attestation.CompareExtraData(quote.Body.ReportData[:], extraData) {
+	if !attestation.CompareExtraData(body.GetReportData(), extraData) {
 		return nil, fmt.Errorf("report data in TDX quote does not match provided nonce")
 	}
 
-	// Convert RTMRs and MRTD to map.
-	tdMeasure := make(map[uint32][]byte, 5)
-	tdMeasure[0] = quote.Body.MRTD[:]
-	for idx := 0; idx < len(quote.Body.RTMR); idx++ {
-		tdMeasure[uint32(idx+1)] = quote.Body.RTMR[idx][:]
-	}
-
 	// Verify the quote against the expected measurements.
-	warnings, errs := v.expected.Compare(tdMeasure)
+	warnings, errs := v.expected.Compare(tdMeasurements(body))
 	for _, warning := range warnings {
 		v.log.Warnf(warning)
 	}
@@ -91,3 +106,105 @@ func (v *Validator) Validate(ctx context.Context, attDocRaw []byte, nonce []byte
 
 	return attDoc.UserData, nil
 }
+
+// MeasuredValues returns MRTD and the RTMRs of the quote in the attestation document.
+// The quote isn't verified, so only use the values of documents that passed Validate.
+func (v *Validator) MeasuredValues(attDocRaw []byte) (map[uint32][]byte, error) {
+	var attDoc tdxAttestationDocument
+	if err := json.Unmarshal(attDocRaw, &attDoc); err != nil {
+		return nil, fmt.Errorf("unmarshaling attestation document: %w", err)
+	}
+	body, err := v.tdx.Parse(attDoc.RawQuote)
+	if err != nil {
+		return nil, fmt.Errorf("parsing TDX quote: %w", err)
+	}
+	return tdMeasurements(body), nil
+}
+
+// tdMeasurements returns MRTD at index 0, followed by the RTMRs.
+func tdMeasurements(body *tdxpb.TDQuoteBody) map[uint32][]byte {
+	rtmrs := body.GetRtmrs()
+	tdMeasure := make(map[uint32][]byte, len(rtmrs)+1)
+	tdMeasure[0] = body.GetMrTd()
+	for idx := 0; idx < len(rtmrs); idx++ {
+		tdMeasure[uint32(idx+1)] = rtmrs[idx]
+	}
+	return tdMeasure
+}
+
+// quoteVerifier verifies TDX quotes and their collateral against Intel's root certificate.
+// Quotes are verified with go-tdx-guest, since it retrieves the collateral using a replaceable getter,
+// which allows serving cached collateral while Intel PCS is unavailable.
+type quoteVerifier struct{}
+
+// Verify verifies the given quote, checking the TCB status and revocation of the PCK certificate chain
+// using the collateral retrieved by getter, and returns the body of the quote.
+func (quoteVerifier) Verify(rawQuote []byte, getter trust.HTTPSGetter) (*tdxpb.TDQuoteBody, error) {
+	quote, err := abi.QuoteToProto(rawQuote)
+	if err != nil {
+		return nil, fmt.Errorf("parsing quote: %w", err)
+	}
+	if err := verify.TdxQuote(quote, &verify.Options{
+		CheckRevocations: true,
+		GetCollateral:    true,
+		Getter:           getter,
+		Now:              time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return quote.GetTdQuoteBody(), nil
+}
+
+// Parse returns the body of the given quote without verifying it.
+func (quoteVerifier) Parse(rawQuote []byte) (*tdxpb.TDQuoteBody, error) {
+	quote, err := abi.QuoteToProto(rawQuote)
+	if err != nil {
+		return nil, fmt.Errorf("parsing quote: %w", err)
+	}
+	return quote.GetTdQuoteBody(), nil
+}
+
+// contextGetter binds a collateral getter to the context of a validation,
+// since go-tdx-guest retrieves collateral without a context.
+type contextGetter struct {
+	ctx    context.Context
+	getter CollateralGetter
+}
+
+// Get retrieves the collateral at the given URL, unless the context of the validation is done.
+func (g contextGetter) Get(url string) (map[string][]string, []byte, error) {
+	return g.getter.GetContext(g.ctx, url)
+}
+
+// PCSGetter retrieves collateral from Intel PCS.
+// Unlike the default getter of go-tdx-guest, it doesn't retry failed requests,
+// so a validation doesn't outlive its context, and callers can fall back to cached collateral without delay.
+type PCSGetter struct {
+	client *http.Client
+}
+
+// NewPCSGetter returns a PCSGetter sending its requests with client.
+func NewPCSGetter(client *http.Client) *PCSGetter {
+	return &PCSGetter{client: client}
+}
+
+// GetContext sends a GET request to the given URL and returns the header and body of the response.
+func (g *PCSGetter) GetContext(ctx context.Context, url string) (map[string][]string, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading response body: %w", err)
+	}
+	return resp.Header, body, nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package tdx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/google/go-tdx-guest/abi"
+	tdxpb "github.com/google/go-tdx-guest/proto/tdx"
+	"github.com/google/go-tdx-guest/testing/testdata"
+	"github.com/google/go-tdx-guest/verify/trust"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	userData := []byte("user data")
+	nonce := []byte("nonce")
+
+	newBody := func() *tdxpb.TDQuoteBody {
+		reportData := make([]byte, 64)
+		copy(reportData, attestation.MakeExtraData(userData, nonce))
+		return &tdxpb.TDQuoteBody{
+			MrTd: bytes.Repeat([]byte{0x01}, 48),
+			Rtmrs: [][]byte{
+				bytes.Repeat([]byte{0x02}, 48),
+				bytes.Repeat([]byte{0x03}, 48),
+				bytes.Repeat([]byte{0x04}, 48),
+				bytes.Repeat([]byte{0x05}, 48),
+			},
+			ReportData: reportData,
+		}
+	}
+	expected := measurements.M{
+		0: measurements.WithAllBytes(0x01, measurements.Enforce, measurements.TDXMeasurementLength),
+		1: measurements.WithAllBytes(0x02, measurements.Enforce, measurements.TDXMeasurementLength),
+		4: measurements.WithAllBytes(0x05, measurements.Enforce, measurements.TDXMeasurementLength),
+	}
+
+	testCases := map[string]struct {
+		verifier *stubVerifier
+		expected measurements.M
+		nonce    []byte
+		wantErr  bool
+	}{
+		"success": {
+			verifier: &stubVerifier{body: newBody()},
+			expected: expected,
+			nonce:    nonce,
+		},
+		"verification fails": {
+			verifier: &stubVerifier{verifyErr: errors.New("failed")},
+			expected: expected,
+			nonce:    nonce,
+			wantErr:  true,
+		},
+		"nonce mismatch": {
+			verifier: &stubVerifier{body: newBody()},
+			expected: expected,
+			nonce:    []byte("other nonce"),
+			wantErr:  true,
+		},
+		"measurement mismatch": {
+			verifier: &stubVerifier{body: newBody()},
+			expected: measurements.M{
+				2: measurements.WithAllBytes(0xFF, measurements.Enforce, measurements.TDXMeasurementLength),
+			},
+			nonce:   nonce,
+			wantErr: true,
+		},
+		"warn only measurement mismatch": {
+			verifier: &stubVerifier{body: newBody()},
+			expected: measurements.M{
+				2: measurements.WithAllBytes(0xFF, measurements.WarnOnly, measurements.TDXMeasurementLength),
+			},
+			nonce: nonce,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			validator := NewValidator(&config.QEMUTDX{Measurements: tc.expected}, nil)
+			validator.tdx = tc.verifier
+			getter := &stubGetter{}
+			validator.SetCollateralGetter(getter)
+
+			attDoc, err := json.Marshal(tdxAttestationDocument{
+				RawQuote: []byte("quote"),
+				UserData: userData,
+			})
+			require.NoError(err)
+
+			ctx := context.Background()
+			out, err := validator.Validate(ctx, attDoc, tc.nonce)
+			assert.Equal(contextGetter{ctx: ctx, getter: getter}, tc.verifier.getter)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(userData, out)
+		})
+	}
+}
+
+func TestValidateCanceled(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	validator := NewValidator(&config.QEMUTDX{}, nil)
+	getter := &stubGetter{}
+	validator.SetCollateralGetter(getter)
+	attDoc, err := json.Marshal(tdxAttestationDocument{RawQuote: testdata.RawQuote})
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = validator.Validate(ctx, attDoc, []byte("nonce"))
+	assert.Error(err)
+
+	// collateral is retrieved with the context of the validation
+	require.NotEmpty(getter.urls)
+	assert.ErrorIs(getter.ctxErr, context.Canceled)
+}
+
+func TestPCSGetter(t *testing.T) {
+	testCases := map[string]struct {
+		status   int
+		canceled bool
+		wantErr  bool
+	}{
+		"success": {
+			status: http.StatusOK,
+		},
+		"unexpected status code": {
+			status:  http.StatusServiceUnavailable,
+			wantErr: true,
+		},
+		"context canceled": {
+			status:   http.StatusOK,
+			canceled: true,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("TCB-Info-Issuer-Chain", "chain")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte("collateral"))
+			}))
+			defer server.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.canceled {
+				cancel()
+			}
+
+			header, body, err := NewPCSGetter(server.Client()).GetContext(ctx, server.URL)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal([]string{"chain"}, header["Tcb-Info-Issuer-Chain"])
+			assert.Equal([]byte("collateral"), body)
+		})
+	}
+}
+
+func TestMeasuredValues(t *testing.T) {
+	quote, err := abi.QuoteToProto(testdata.RawQuote)
+	require.NoError(t, err)
+	body := quote.GetTdQuoteBody()
+
+	testCases := map[string]struct {
+		attDoc     tdxAttestationDocument
+		wantValues map[uint32][]byte
+		wantErr    bool
+	}{
+		"success": {
+			attDoc: tdxAttestationDocument{RawQuote: testdata.RawQuote},
+			wantValues: map[uint32][]byte{
+				0: body.GetMrTd(),
+				1: body.GetRtmrs()[0],
+				2: body.GetRtmrs()[1],
+				3: body.GetRtmrs()[2],
+				4: body.GetRtmrs()[3],
+			},
+		},
+		"invalid quote": {
+			attDoc:  tdxAttestationDocument{RawQuote: []byte("invalid")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			attDoc, err := json.Marshal(tc.attDoc)
+			require.NoError(err)
+
+			// collateral isn't needed to read the measured values
+			validator := NewValidator(&config.QEMUTDX{}, nil)
+			validator.SetCollateralGetter(&stubGetter{err: errors.New("unavailable")})
+
+			values, err := validator.MeasuredValues(attDoc)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantValues, values)
+		})
+	}
+}
+
+func TestQuoteVerifier(t *testing.T) {
+	testCases := map[string]struct {
+		rawQuote     []byte
+		wantGetCalls bool
+	}{
+		"invalid quote": {
+			rawQuote: []byte("invalid"),
+		},
+		"collateral unavailable": {
+			rawQuote:     testdata.RawQuote,
+			wantGetCalls: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			getter := &stubGetter{err: errors.New("unavailable")}
+			_, err := quoteVerifier{}.Verify(tc.rawQuote, contextGetter{ctx: context.Background(), getter: getter})
+			assert.Error(err)
+
+			if !tc.wantGetCalls {
+				assert.Empty(getter.urls)
+				return
+			}
+			// collateral must be requested from the configured getter
+			require.NotEmpty(t, getter.urls)
+			assert.True(strings.HasPrefix(getter.urls[0], "https://api.trustedservices.intel.com/"))
+		})
+	}
+}
+
+type stubVerifier struct {
+	body      *tdxpb.TDQuoteBody
+	verifyErr error
+	getter    trust.HTTPSGetter
+}
+
+func (s *stubVerifier) Verify(_ []byte, getter trust.HTTPSGetter) (*tdxpb.TDQuoteBody, error) {
+	s.getter = getter
+	return s.body, s.verifyErr
+}
+
+func (s *stubVerifier) Parse(_ []byte) (*tdxpb.TDQuoteBody, error) {
+	return s.body, s.verifyErr
+}
+
+type stubGetter struct {
+	urls   []string
+	ctxErr error
+	err    error
+}
+
+func (s *stubGetter) GetContext(ctx context.Context, url string) (map[string][]string, []byte, error) {
+	s.urls = append(s.urls, url)
+	s.ctxErr = ctx.Err()
+	if s.ctxErr != nil {
+		return nil, nil, s.ctxErr
+	}
+	return nil, nil, s.err
+}
@@ -40,21 +40,23 @@ import (
 )
 
 const (
-	dummy              = "dummy"
-	awsNitroTPM        = "aws-nitro-tpm"
-	awsSEVSNP          = "aws-sev-snp"
-	gcpSEVES           = "gcp-sev-es"
-	azureSEVSNP        = "azure-sev-snp"
-	azureTrustedLaunch = "azure-trustedlaunch"
-	qemuVTPM           = "qemu-vtpm"
-	qemuTDX            = "qemu-tdx"
+	dummy               = "dummy"
+	awsNitroTPM         = "aws-nitro-tpm"
+	awsSEVSNP           = "aws-sev-snp"
+	gcpSEVES            = "gcp-sev-es"
+	azureSEVSNP         = "azure-sev-snp"
+	azureTrustedLaunch  = "azure-trustedlaunch"
+	qemuVTPM            = "qemu-vtpm"
+	qemuTDX             = "qemu-tdx"
+	qemuTDXSimulated    = "qemu-tdx-simulated"
+	qemuSEVSNPSimulated = "qemu-sev-snp-simulated"
 )
 
 var providerAttestationMapping = map[cloudprovider.Provider][]Variant{
 	cloudprovider.AWS:       {AWSSEVSNP{}, AWSNitroTPM{}},
 	cloudprovider.Azure:     {AzureSEVSNP{}, AzureTrustedLaunch{}},
 	cloudprovider.GCP:       {GCPSEVES{}},
-	cloudprovider.QEMU:      {QEMUVTPM{}},
+	cloudprovider.QEMU:      {QEMUVTPM{}, QEMUTDX{}, QEMUTDXSimulated{}, QEMUSEVSNPSimulated{}},
 	cloudprovider.OpenStack: {QEMUVTPM{}},
 }
 
@@ -86,6 +88,21 @@ func GetAvailableAttestationVariants() []Variant {
 	return RemoveDuplicate(res)
 }
 
+// IsSimulated returns true if the variant simulates hardware attestation in software.
+// Simulated variants provide no security and are only allowed for debug clusters.
+func IsSimulated(v Variant) bool {
+	switch v.(type) {
+	case QEMUTDXSimulated, QEMUSEVSNPSimulated:
+		return true
+	}
+	return false
+}
+
+// GetAvailableAttestationVariantsFor returns the attestation variants available for the given provider.
+func GetAvailableAttestationVariantsFor(provider cloudprovider.Provider) []Variant {
+	return append([]Variant{}, providerAttestationMapping[provider]...)
+}
+
 // Getter returns an ASN.1 Object Identifier.
 type Getter interface {
 	OID() asn1.ObjectIdentifier
@@ -117,6 +134,10 @@ func FromString(oid string) (Variant, error) {
 		return QEMUVTPM{}, nil
 	case qemuTDX:
 		return QEMUTDX{}, nil
+	case qemuTDXSimulated:
+		return QEMUTDXSimulated{}, nil
+	case qemuSEVSNPSimulated:
+		return QEMUSEVSNPSimulated{}, nil
 	}
 	return nil, fmt.Errorf("unknown OID: %q", oid)
 }
@@ -280,6 +301,44 @@ func (QEMUTDX) Equal(other Getter) bool {
 	return other.OID().Equal(QEMUTDX{}.OID())
 }
 
+// QEMUTDXSimulated holds the OID for QEMU VMs using software-simulated TDX attestation.
+// It provides no security guarantees and must only be used for testing.
+type QEMUTDXSimulated struct{}
+
+// OID returns the struct's object identifier.
+func (QEMUTDXSimulated) OID() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 3, 9900, 5, 2}
+}
+
+// String returns the string representation of the OID.
+func (QEMUTDXSimulated) String() string {
+	return qemuTDXSimulated
+}
+
+// Equal returns true if the other variant is also QEMUTDXSimulated.
+func (QEMUTDXSimulated) Equal(other Getter) bool {
+	return other.OID().Equal(QEMUTDXSimulated{}.OID())
+}
+
+// QEMUSEVSNPSimulated holds the OID for QEMU VMs using software-simulated SEV-SNP attestation.
+// It provides no security guarantees and must only be used for testing.
+type QEMUSEVSNPSimulated struct{}
+
+// OID returns the struct's object identifier.
+func (QEMUSEVSNPSimulated) OID() asn1.ObjectIdentifier {
+	return asn1.ObjectIdentifier{1, 3, 9900, 5, 3}
+}
+
+// String returns the string representation of the OID.
+func (QEMUSEVSNPSimulated) String() string {
+	return qemuSEVSNPSimulated
+}
+
+// Equal returns true if the other variant is also QEMUSEVSNPSimulated.
+func (QEMUSEVSNPSimulated) Equal(other Getter) bool {
+	return other.OID().Equal(QEMUSEVSNPSimulated{}.OID())
+}
+
 // RemoveDuplicate removes duplicate elements from a slice.
 func RemoveDuplicate[T comparable](sliceList []T) []T {
 	allKeys := make(map[T]bool)
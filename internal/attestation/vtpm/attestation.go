@@ -184,20 +184,9 @@ func (v *Validator) Validate(ctx context.Context, attDocRaw []byte, nonce []byte
 		}
 	}()
 
-	// Explicitly initialize this struct, as TeeAttestation
-	// is a "oneof" protobuf field, which needs an explicit
-	// type to be set to be unmarshaled correctly.
-	// Note: this value is incompatible with TDX attestation!
-	// TODO(msanft): select the correct attestation type (SEV-SNP, TDX, ...) here.
-	attDoc := AttestationDocument{
-		Attestation: &attest.Attestation{
-			TeeAttestation: &attest.Attestation_SevSnpAttestation{
-				SevSnpAttestation: &sevsnp.Attestation{},
-			},
-		},
-	}
-	if err := json.Unmarshal(attDocRaw, &attDoc); err != nil {
-		return nil, fmt.Errorf("unmarshaling TPM attestation document: %w", err)
+	attDoc, err := unmarshalAttestationDocument(attDocRaw)
+	if err != nil {
+		return nil, err
 	}
 
 	extraData := attestation.MakeExtraData(attDoc.UserData, nonce)
@@ -243,6 +232,39 @@ func (v *Validator) Validate(ctx context.Context, attDocRaw []byte, nonce []byte
 	return attDoc.UserData, nil
 }
 
+// MeasuredValues returns the SHA-256 PCR values quoted in the attestation document.
+// The document isn't validated, so only use the values of documents that passed Validate.
+func (v *Validator) MeasuredValues(attDocRaw []byte) (map[uint32][]byte, error) {
+	attDoc, err := unmarshalAttestationDocument(attDocRaw)
+	if err != nil {
+		return nil, err
+	}
+	quoteIdx, err := GetSHA256QuoteIndex(attDoc.Attestation.Quotes)
+	if err != nil {
+		return nil, err
+	}
+	return attDoc.Attestation.Quotes[quoteIdx].Pcrs.Pcrs, nil
+}
+
+func unmarshalAttestationDocument(attDocRaw []byte) (AttestationDocument, error) {
+	// Explicitly initialize this struct, as TeeAttestation
+	// is a "oneof" protobuf field, which needs an explicit
+	// type to be set to be unmarshaled correctly.
+	// Note: this value is incompatible with TDX attestation!
+	// TODO(msanft): select the correct attestation type (SEV-SNP, TDX, ...) here.
+	attDoc := AttestationDocument{
+		Attestation: &attest.Attestation{
+			TeeAttestation: &attest.Attestation_SevSnpAttestation{
+				SevSnpAttestation: &sevsnp.Attestation{},
+			},
+		},
+	}
+	if err := json.Unmarshal(attDocRaw, &attDoc); err != nil {
+		return AttestationDocument{}, fmt.Errorf("unmarshaling TPM attestation document: %w", err)
+	}
+	return attDoc, nil
+}
+
 // GetSHA256QuoteIndex performs safety checks and returns the index for SHA256 PCR quotes.
 func GetSHA256QuoteIndex(quotes []*tpmProto.Quote) (int, error) {
 	if len(quotes) == 0 {
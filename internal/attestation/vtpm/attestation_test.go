@@ -105,6 +105,16 @@ func TestValidate(t *testing.T) {
 	require.NoError(err)
 	require.Equal(challenge, out)
 
+	// measured values are the quoted SHA-256 PCRs
+	measured, err := validator.MeasuredValues(attDocRaw)
+	require.NoError(err)
+	quoteIdx, err := GetSHA256QuoteIndex(attDoc.Attestation.Quotes)
+	require.NoError(err)
+	assert.Equal(t, attDoc.Attestation.Quotes[quoteIdx].Pcrs.Pcrs, measured)
+	for idx, pcr := range testExpectedPCRs {
+		assert.Equal(t, pcr.Expected, measured[idx])
+	}
+
 	// validation must fail after bootstrapping (change of enforced PCR)
 	require.NoError(initialize.MarkNodeAsBootstrapped(tpmOpen, []byte{2}))
 	attDocBootstrappedRaw, err := issuer.Issue(ctx, challenge, nonce)
@@ -38,8 +38,12 @@ import (
 
 const (
 	tagName = "Name"
+	// autoScalingGroupTag is set by AWS on instances launched by an auto scaling group.
+	autoScalingGroupTag = "aws:autoscaling:groupName"
 )
 
+var errTagNotFound = errors.New("tag not found")
+
 type resourceAPI interface {
 	GetResources(context.Context, *resourcegroupstaggingapi.GetResourcesInput, ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error)
 }
@@ -129,6 +133,33 @@ func (c *Cloud) InitSecretHash(ctx context.Context) ([]byte, error) {
 	return []byte(initSecretHash), nil
 }
 
+// DiskEncryptionProfile returns the name of the encryption profile for the state disk of the current instance.
+// If the instance has no profile tag, an empty string is returned.
+func (c *Cloud) DiskEncryptionProfile(ctx context.Context) (string, error) {
+	profile, err := c.readInstanceTag(ctx, cloud.TagDiskEncryptionProfile)
+	if errors.Is(err, errTagNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("retrieving disk encryption profile tag: %w", err)
+	}
+	return profile, nil
+}
+
+// NodeRegistration returns the encoded labels and taints the kubelet registers the current instance with.
+// Missing tags result in empty values.
+func (c *Cloud) NodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	labels, err = c.readInstanceTag(ctx, cloud.TagNodeLabels)
+	if err != nil && !errors.Is(err, errTagNotFound) {
+		return "", "", fmt.Errorf("retrieving node labels tag: %w", err)
+	}
+	taints, err = c.readInstanceTag(ctx, cloud.TagNodeTaints)
+	if err != nil && !errors.Is(err, errTagNotFound) {
+		return "", "", fmt.Errorf("retrieving node taints tag: %w", err)
+	}
+	return labels, taints, nil
+}
+
 // GetLoadBalancerEndpoint returns the endpoint of the load balancer.
 func (c *Cloud) GetLoadBalancerEndpoint(ctx context.Context) (host, port string, err error) {
 	hostname, err := c.getLoadBalancerDNSName(ctx)
@@ -259,6 +290,11 @@ func (c *Cloud) convertToMetadataInstance(ec2Instances []ec2Types.Instance) ([]m
 		}
 		newInstance.Role = role.FromString(instanceRole)
 
+		// instances launched by an auto scaling group are tagged with its name
+		if scalingGroup, err := findTag(ec2Instance.Tags, autoScalingGroupTag); err == nil {
+			newInstance.ScalingGroupID = scalingGroup
+		}
+
 		// Set ProviderID
 		if ec2Instance.Placement != nil {
 			// set to aws:///<region>/<instance-id>
@@ -307,5 +343,5 @@ func findTag(tags []ec2Types.Tag, wantKey string) (string, error) {
 			return *tag.Value, nil
 		}
 	}
-	return "", fmt.Errorf("tag %q not found", wantKey)
+	return "", fmt.Errorf("%w: %q", errTagNotFound, wantKey)
 }
@@ -197,6 +197,163 @@ func TestSelf(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfile(t *testing.T) {
+	testIMDS := &stubIMDS{
+		instanceDocumentResp: &imds.GetInstanceIdentityDocumentOutput{
+			InstanceIdentityDocument: imds.InstanceIdentityDocument{
+				InstanceID: "test-instance-id",
+			},
+		},
+	}
+	instanceWithTags := func(tags ...ec2Types.Tag) *ec2.DescribeInstancesOutput {
+		return &ec2.DescribeInstancesOutput{
+			Reservations: []ec2Types.Reservation{
+				{
+					Instances: []ec2Types.Instance{
+						{
+							InstanceId: aws.String("test-instance-id"),
+							Tags:       tags,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		imds        *stubIMDS
+		ec2API      *stubEC2
+		wantProfile string
+		wantErr     bool
+	}{
+		"profile set": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				selfInstance: instanceWithTags(ec2Types.Tag{
+					Key:   aws.String(cloud.TagDiskEncryptionProfile),
+					Value: aws.String("aes-xts"),
+				}),
+			},
+			wantProfile: "aes-xts",
+		},
+		"profile not set": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				selfInstance: instanceWithTags(ec2Types.Tag{
+					Key:   aws.String(cloud.TagRole),
+					Value: aws.String("worker"),
+				}),
+			},
+		},
+		"get instance error": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				describeInstancesErr: assert.AnError,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			m := &Cloud{
+				imds: tc.imds,
+				ec2:  tc.ec2API,
+			}
+
+			profile, err := m.DiskEncryptionProfile(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.wantProfile, profile)
+		})
+	}
+}
+
+func TestNodeRegistration(t *testing.T) {
+	testIMDS := &stubIMDS{
+		instanceDocumentResp: &imds.GetInstanceIdentityDocumentOutput{
+			InstanceIdentityDocument: imds.InstanceIdentityDocument{
+				InstanceID: "test-instance-id",
+			},
+		},
+	}
+	instanceWithTags := func(tags ...ec2Types.Tag) *ec2.DescribeInstancesOutput {
+		return &ec2.DescribeInstancesOutput{
+			Reservations: []ec2Types.Reservation{
+				{
+					Instances: []ec2Types.Instance{
+						{
+							InstanceId: aws.String("test-instance-id"),
+							Tags:       tags,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		imds       *stubIMDS
+		ec2API     *stubEC2
+		wantLabels string
+		wantTaints string
+		wantErr    bool
+	}{
+		"labels and taints set": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				selfInstance: instanceWithTags(
+					ec2Types.Tag{Key: aws.String(cloud.TagNodeLabels), Value: aws.String("pool=spot")},
+					ec2Types.Tag{Key: aws.String(cloud.TagNodeTaints), Value: aws.String("spot=true:NoSchedule")},
+				),
+			},
+			wantLabels: "pool=spot",
+			wantTaints: "spot=true:NoSchedule",
+		},
+		"nothing set": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				selfInstance: instanceWithTags(ec2Types.Tag{
+					Key:   aws.String(cloud.TagRole),
+					Value: aws.String("worker"),
+				}),
+			},
+		},
+		"get instance error": {
+			imds: testIMDS,
+			ec2API: &stubEC2{
+				describeInstancesErr: assert.AnError,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			m := &Cloud{
+				imds: tc.imds,
+				ec2:  tc.ec2API,
+			}
+
+			labels, taints, err := m.NodeRegistration(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tc.wantLabels, labels)
+			assert.Equal(tc.wantTaints, taints)
+		})
+	}
+}
+
 func TestList(t *testing.T) {
 	someErr := errors.New("failed")
 
@@ -739,6 +896,37 @@ func TestConvertToMetadataInstance(t *testing.T) {
 				},
 			},
 		},
+		"instance in auto scaling group": {
+			in: []ec2Types.Instance{
+				{
+					State:            &ec2Types.InstanceState{Name: ec2Types.InstanceStateNameRunning},
+					InstanceId:       aws.String("id-1"),
+					PrivateIpAddress: aws.String("192.0.2.1"),
+					Placement: &ec2Types.Placement{
+						AvailabilityZone: aws.String("test-zone"),
+					},
+					Tags: []ec2Types.Tag{
+						{
+							Key:   aws.String(cloud.TagRole),
+							Value: aws.String("worker"),
+						},
+						{
+							Key:   aws.String("aws:autoscaling:groupName"),
+							Value: aws.String("worker-group"),
+						},
+					},
+				},
+			},
+			wantInstances: []metadata.InstanceMetadata{
+				{
+					Name:           "id-1",
+					Role:           role.Worker,
+					ProviderID:     "aws:///test-zone/id-1",
+					VPCIP:          "192.0.2.1",
+					ScalingGroupID: "worker-group",
+				},
+			},
+		},
 		"fallback to instance ID": {
 			in: []ec2Types.Instance{
 				{
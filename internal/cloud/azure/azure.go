@@ -205,6 +205,26 @@ func (c *Cloud) InitSecretHash(ctx context.Context) ([]byte, error) {
 	return []byte(initSecretHash), nil
 }
 
+// DiskEncryptionProfile retrieves the name of the disk encryption profile of the current instance.
+// An empty string is returned if no profile is set.
+func (c *Cloud) DiskEncryptionProfile(ctx context.Context) (string, error) {
+	diskEncryptionProfile, err := c.imds.diskEncryptionProfile(ctx)
+	if err != nil {
+		return "", fmt.Errorf("retrieving disk encryption profile: %w", err)
+	}
+	return diskEncryptionProfile, nil
+}
+
+// NodeRegistration retrieves the encoded labels and taints the kubelet registers the current instance with.
+// Empty strings are returned if they aren't set.
+func (c *Cloud) NodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	labels, taints, err = c.imds.nodeRegistration(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("retrieving node registration tags: %w", err)
+	}
+	return labels, taints, nil
+}
+
 // getLoadBalancer retrieves a load balancer from cloud provider metadata.
 func (c *Cloud) getLoadBalancer(ctx context.Context, resourceGroup, uid string) (*armnetwork.LoadBalancer, error) {
 	pager := c.loadBalancerAPI.NewListPager(resourceGroup, nil)
@@ -531,10 +551,15 @@ func convertToInstanceMetadata(vm armcompute.VirtualMachineScaleSetVM, networkIn
 		}
 	}
 
+	providerID := "azure://" + *vm.ID
+	// VMs that are not part of a scale set don't have a scaling group
+	scaleSetID, _ := azureshared.ScaleSetIDFromProviderID(providerID)
+
 	return metadata.InstanceMetadata{
-		Name:       *vm.Properties.OSProfile.ComputerName,
-		ProviderID: "azure://" + *vm.ID,
-		Role:       role.FromString(instanceRole),
-		VPCIP:      privateIP,
+		Name:           *vm.Properties.OSProfile.ComputerName,
+		ProviderID:     providerID,
+		Role:           role.FromString(instanceRole),
+		VPCIP:          privateIP,
+		ScalingGroupID: scaleSetID,
 	}, nil
 }
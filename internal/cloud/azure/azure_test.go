@@ -80,10 +80,11 @@ func TestGetInstance(t *testing.T) {
 			providerID:           sampleProviderID,
 			IMDSAPI:              &stubIMDSAPI{},
 			wantInstance: metadata.InstanceMetadata{
-				Name:       "scale-set-name-instance-id",
-				ProviderID: sampleProviderID,
-				Role:       role.Worker,
-				VPCIP:      "192.0.2.1",
+				Name:           "scale-set-name-instance-id",
+				ProviderID:     sampleProviderID,
+				Role:           role.Worker,
+				VPCIP:          "192.0.2.1",
+				ScalingGroupID: "/subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set-name",
 			},
 		},
 		"success control-plane": {
@@ -112,10 +113,11 @@ func TestGetInstance(t *testing.T) {
 			networkInterfacesAPI: successNetworkAPI,
 			providerID:           sampleProviderID,
 			wantInstance: metadata.InstanceMetadata{
-				Name:       "scale-set-name-instance-id",
-				ProviderID: sampleProviderID,
-				Role:       role.ControlPlane,
-				VPCIP:      "192.0.2.1",
+				Name:           "scale-set-name-instance-id",
+				ProviderID:     sampleProviderID,
+				Role:           role.ControlPlane,
+				VPCIP:          "192.0.2.1",
+				ScalingGroupID: "/subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set-name",
 			},
 		},
 		"invalid provider ID": {
@@ -233,6 +235,86 @@ func TestInitSecretHash(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfile(t *testing.T) {
+	testCases := map[string]struct {
+		imdsAPI *stubIMDSAPI
+		wantErr bool
+	}{
+		"success": {
+			imdsAPI: &stubIMDSAPI{
+				diskProfileVal: "aes-xts",
+			},
+		},
+		"profile not set": {
+			imdsAPI: &stubIMDSAPI{},
+		},
+		"error": {
+			imdsAPI: &stubIMDSAPI{
+				diskProfileErr: errors.New("failed"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cloud := &Cloud{
+				imds: tc.imdsAPI,
+			}
+			diskProfile, err := cloud.DiskEncryptionProfile(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.imdsAPI.diskProfileVal, diskProfile)
+		})
+	}
+}
+
+func TestNodeRegistration(t *testing.T) {
+	testCases := map[string]struct {
+		imdsAPI *stubIMDSAPI
+		wantErr bool
+	}{
+		"success": {
+			imdsAPI: &stubIMDSAPI{
+				nodeLabelsVal: "pool=spot",
+				nodeTaintsVal: "spot=true:NoSchedule",
+			},
+		},
+		"nothing set": {
+			imdsAPI: &stubIMDSAPI{},
+		},
+		"error": {
+			imdsAPI: &stubIMDSAPI{
+				nodeRegErr: errors.New("failed"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cloud := &Cloud{
+				imds: tc.imdsAPI,
+			}
+			labels, taints, err := cloud.NodeRegistration(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.imdsAPI.nodeLabelsVal, labels)
+			assert.Equal(tc.imdsAPI.nodeTaintsVal, taints)
+		})
+	}
+}
+
 func TestList(t *testing.T) {
 	someErr := errors.New("failed")
 	networkIfaceResponse := &stubNetworkInterfacesAPI{
@@ -284,10 +366,11 @@ func TestList(t *testing.T) {
 	}
 
 	workerInstance := metadata.InstanceMetadata{
-		Name:       "scale-set-0",
-		ProviderID: "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set/virtualMachines/0",
-		Role:       role.Worker,
-		VPCIP:      "192.0.2.0",
+		Name:           "scale-set-0",
+		ProviderID:     "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set/virtualMachines/0",
+		Role:           role.Worker,
+		VPCIP:          "192.0.2.0",
+		ScalingGroupID: "/subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set",
 	}
 
 	testCases := map[string]struct {
@@ -350,10 +433,11 @@ func TestList(t *testing.T) {
 			wantInstances: []metadata.InstanceMetadata{
 				workerInstance,
 				{
-					Name:       "control-set-0",
-					ProviderID: "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/control-set/virtualMachines/0",
-					Role:       role.ControlPlane,
-					VPCIP:      "192.0.2.0",
+					Name:           "control-set-0",
+					ProviderID:     "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/control-set/virtualMachines/0",
+					Role:           role.ControlPlane,
+					VPCIP:          "192.0.2.0",
+					ScalingGroupID: "/subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/control-set",
 				},
 			},
 		},
@@ -733,6 +817,11 @@ type stubIMDSAPI struct {
 	nameVal           string
 	initSecretHashVal string
 	initSecretHashErr error
+	diskProfileVal    string
+	diskProfileErr    error
+	nodeLabelsVal     string
+	nodeTaintsVal     string
+	nodeRegErr        error
 }
 
 func (a *stubIMDSAPI) providerID(_ context.Context) (string, error) {
@@ -759,6 +848,14 @@ func (a *stubIMDSAPI) initSecretHash(_ context.Context) (string, error) {
 	return a.initSecretHashVal, a.initSecretHashErr
 }
 
+func (a *stubIMDSAPI) diskEncryptionProfile(_ context.Context) (string, error) {
+	return a.diskProfileVal, a.diskProfileErr
+}
+
+func (a *stubIMDSAPI) nodeRegistration(_ context.Context) (string, string, error) {
+	return a.nodeLabelsVal, a.nodeTaintsVal, a.nodeRegErr
+}
+
 type stubVirtualMachineScaleSetVMPager struct {
 	list     []armcompute.VirtualMachineScaleSetVM
 	fetchErr error
@@ -159,6 +159,44 @@ func (c *IMDSClient) initSecretHash(ctx context.Context) (string, error) {
 	return "", fmt.Errorf("unable to get tag %s from metadata tags %v", cloud.TagInitSecretHash, c.cache.Compute.Tags)
 }
 
+// diskEncryptionProfile returns the name of the disk encryption profile of the instance.
+// An empty string is returned if the tag isn't set.
+func (c *IMDSClient) diskEncryptionProfile(ctx context.Context) (string, error) {
+	return c.optionalTag(ctx, cloud.TagDiskEncryptionProfile)
+}
+
+// nodeRegistration returns the encoded labels and taints the kubelet registers the instance with.
+// Empty strings are returned for tags that aren't set.
+func (c *IMDSClient) nodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	labels, err = c.optionalTag(ctx, cloud.TagNodeLabels)
+	if err != nil {
+		return "", "", err
+	}
+	taints, err = c.optionalTag(ctx, cloud.TagNodeTaints)
+	if err != nil {
+		return "", "", err
+	}
+	return labels, taints, nil
+}
+
+// optionalTag returns the value of the tag with the given name.
+// An empty string is returned if the tag isn't set.
+func (c *IMDSClient) optionalTag(ctx context.Context, name string) (string, error) {
+	if c.timeForUpdate() || len(c.cache.Compute.Tags) == 0 {
+		if err := c.update(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	for _, tag := range c.cache.Compute.Tags {
+		if tag.Name == name {
+			return tag.Value, nil
+		}
+	}
+
+	return "", nil
+}
+
 // role returns the role of the instance the function is called from.
 func (c *IMDSClient) role(ctx context.Context) (role.Role, error) {
 	if c.timeForUpdate() || len(c.cache.Compute.Tags) == 0 {
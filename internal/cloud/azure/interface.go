@@ -22,6 +22,8 @@ type imdsAPI interface {
 	subscriptionID(ctx context.Context) (string, error)
 	uid(ctx context.Context) (string, error)
 	initSecretHash(ctx context.Context) (string, error)
+	diskEncryptionProfile(ctx context.Context) (string, error)
+	nodeRegistration(ctx context.Context) (labels, taints string, err error)
 }
 
 type virtualNetworksAPI interface {
@@ -33,3 +33,13 @@ func ScaleSetInformationFromProviderID(providerID string) (subscriptionID, resou
 	}
 	return matches[1], matches[2], matches[3], matches[4], nil
 }
+
+// ScaleSetIDFromProviderID returns the resource ID of the scale set a scale set VM belongs to.
+func ScaleSetIDFromProviderID(providerID string) (string, error) {
+	subscriptionID, resourceGroup, scaleSet, _, err := ScaleSetInformationFromProviderID(providerID)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachineScaleSets/%s",
+		subscriptionID, resourceGroup, scaleSet), nil
+}
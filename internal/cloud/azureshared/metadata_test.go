@@ -93,3 +93,35 @@ func TestScaleSetInformationFromProviderID(t *testing.T) {
 		})
 	}
 }
+
+func TestScaleSetIDFromProviderID(t *testing.T) {
+	testCases := map[string]struct {
+		providerID string
+		wantID     string
+		wantErr    bool
+	}{
+		"providerID for scale set instance works": {
+			providerID: "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set-name/virtualMachines/instance-id",
+			wantID:     "/subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachineScaleSets/scale-set-name",
+		},
+		"providerID for individual instance must fail": {
+			providerID: "azure:///subscriptions/subscription-id/resourceGroups/resource-group/providers/Microsoft.Compute/virtualMachines/instance-name",
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			id, err := ScaleSetIDFromProviderID(tc.providerID)
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantID, id)
+		})
+	}
+}
@@ -21,6 +21,8 @@ A cloud package should implement the following interface:
 		Self(ctx context.Context) (metadata.InstanceMetadata, error)
 		GetLoadBalancerEndpoint(ctx context.Context) (string, error)
 		InitSecretHash(ctx context.Context) ([]byte, error)
+		DiskEncryptionProfile(ctx context.Context) (string, error)
+		NodeRegistration(ctx context.Context) (labels, taints string, err error)
 		UID(ctx context.Context) (string, error)
 	}
 */
@@ -33,6 +35,18 @@ const (
 	TagUID = "constellation-uid"
 	// TagInitSecretHash is the tag/label key used to identify the hash of the init secret.
 	TagInitSecretHash = "constellation-init-secret-hash"
+	// TagDiskEncryptionProfile is the tag/label key used to identify the encryption profile of the state disk.
+	TagDiskEncryptionProfile = "constellation-disk-encryption-profile"
+	// TagNodeLabels is the tag/label key used to identify the labels the kubelet registers a node with.
+	TagNodeLabels = "constellation-node-labels"
+	// TagNodeTaints is the tag/label key used to identify the taints the kubelet registers a node with.
+	TagNodeTaints = "constellation-node-taints"
+	// TagSpot is the tag/label key used to mark node groups that use spot instances.
+	TagSpot = "constellation-spot"
+	// TagAutoscalingMin is the tag/label key used to identify the minimum size of an autoscaled node group.
+	TagAutoscalingMin = "constellation-autoscaling-min"
+	// TagAutoscalingMax is the tag/label key used to identify the maximum size of an autoscaled node group.
+	TagAutoscalingMax = "constellation-autoscaling-max"
 	// TagCustomEndpoint is the tag/label key used to identify the custom endpoint
 	// or dns name that should be added to tls cert SANs.
 	TagCustomEndpoint = "constellation-custom-endpoint"
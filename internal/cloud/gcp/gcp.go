@@ -45,7 +45,9 @@ const (
 
 var (
 	zoneFromRegionRegex = regexp.MustCompile("([a-z]*-[a-z]*[0-9])")
-	errNoForwardingRule = errors.New("no forwarding rule found")
+	// instanceGroupManagerRegexp matches the URI of an instance group manager, with or without the compute API prefix.
+	instanceGroupManagerRegexp = regexp.MustCompile(`projects/([^/]+)/zones/([^/]+)/instanceGroupManagers/([^/]+)$`)
+	errNoForwardingRule        = errors.New("no forwarding rule found")
 )
 
 // Cloud provides GCP cloud metadata information and API access.
@@ -321,6 +323,34 @@ func (c *Cloud) InitSecretHash(ctx context.Context) ([]byte, error) {
 	return []byte(initSecretHash), nil
 }
 
+// DiskEncryptionProfile retrieves the name of the disk encryption profile of the current instance.
+// An empty string is returned if no profile is set.
+func (c *Cloud) DiskEncryptionProfile(ctx context.Context) (string, error) {
+	project, zone, instanceName, err := c.retrieveInstanceInfo()
+	if err != nil {
+		return "", err
+	}
+	diskEncryptionProfile, err := c.diskEncryptionProfile(ctx, project, zone, instanceName)
+	if err != nil {
+		return "", fmt.Errorf("retrieving disk encryption profile: %w", err)
+	}
+	return diskEncryptionProfile, nil
+}
+
+// NodeRegistration retrieves the encoded labels and taints the kubelet registers the current instance with.
+// Empty strings are returned if the metadata items aren't set.
+func (c *Cloud) NodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	project, zone, instanceName, err := c.retrieveInstanceInfo()
+	if err != nil {
+		return "", "", err
+	}
+	items, err := c.metadataItems(ctx, project, zone, instanceName)
+	if err != nil {
+		return "", "", fmt.Errorf("retrieving node registration metadata: %w", err)
+	}
+	return items[cloud.TagNodeLabels], items[cloud.TagNodeTaints], nil
+}
+
 // getInstance retrieves an instance using its project, zone and name, and parses it to metadata.InstanceMetadata.
 func (c *Cloud) getInstance(ctx context.Context, project, zone, instanceName string) (metadata.InstanceMetadata, error) {
 	gcpInstance, err := c.instanceAPI.Get(ctx, &computepb.GetInstanceRequest{
@@ -445,6 +475,39 @@ func (c *Cloud) initSecretHash(ctx context.Context, project, zone, instanceName
 	return "", errors.New("retrieving compute instance: received instance with no init secret hash label")
 }
 
+// diskEncryptionProfile retrieves the disk encryption profile of the instance identified by project, zone and instanceName.
+// The profile is retrieved from the instance's metadata. An empty string is returned if the metadata item isn't set.
+func (c *Cloud) diskEncryptionProfile(ctx context.Context, project, zone, instanceName string) (string, error) {
+	items, err := c.metadataItems(ctx, project, zone, instanceName)
+	if err != nil {
+		return "", err
+	}
+	return items[cloud.TagDiskEncryptionProfile], nil
+}
+
+// metadataItems retrieves the metadata items of the instance identified by project, zone and instanceName.
+func (c *Cloud) metadataItems(ctx context.Context, project, zone, instanceName string) (map[string]string, error) {
+	instance, err := c.instanceAPI.Get(ctx, &computepb.GetInstanceRequest{
+		Project:  project,
+		Zone:     zone,
+		Instance: instanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("retrieving compute instance: %w", err)
+	}
+	if instance == nil || instance.Metadata == nil {
+		return nil, errors.New("retrieving compute instance: received instance with invalid metadata")
+	}
+	items := make(map[string]string, len(instance.Metadata.Items))
+	for _, item := range instance.Metadata.Items {
+		if item == nil || item.Key == nil || item.Value == nil {
+			return nil, errors.New("retrieving compute instance: received instance with invalid metadata item")
+		}
+		items[*item.Key] = *item.Value
+	}
+	return items, nil
+}
+
 // region retrieves the region that this instance is located in.
 func (c *Cloud) region() (string, error) {
 	c.cacheMux.Lock()
@@ -538,14 +601,34 @@ func convertToInstanceMetadata(in *computepb.Instance, project string, zone stri
 	}
 
 	return metadata.InstanceMetadata{
-		Name:          *in.Name,
-		ProviderID:    gcpshared.JoinProviderID(project, zone, *in.Name),
-		Role:          role.FromString(in.Labels[cloud.TagRole]),
-		VPCIP:         vpcIP,
-		AliasIPRanges: ips,
+		Name:           *in.Name,
+		ProviderID:     gcpshared.JoinProviderID(project, zone, *in.Name),
+		Role:           role.FromString(in.Labels[cloud.TagRole]),
+		VPCIP:          vpcIP,
+		AliasIPRanges:  ips,
+		ScalingGroupID: instanceGroupID(in.Metadata, project),
 	}, nil
 }
 
+// instanceGroupID returns the ID of the instance group manager that created the instance, if any.
+// The "created-by" metadata references the project by number, so it is replaced with the project ID.
+func instanceGroupID(instanceMetadata *computepb.Metadata, project string) string {
+	if instanceMetadata == nil {
+		return ""
+	}
+	for _, item := range instanceMetadata.Items {
+		if item == nil || item.Key == nil || item.Value == nil || *item.Key != "created-by" {
+			continue
+		}
+		matches := instanceGroupManagerRegexp.FindStringSubmatch(*item.Value)
+		if len(matches) != 4 {
+			return ""
+		}
+		return fmt.Sprintf("projects/%s/zones/%s/instanceGroupManagers/%s", project, matches[2], matches[3])
+	}
+	return ""
+}
+
 func regionFromZone(zone string) (string, error) {
 	zoneParts := strings.Split(zone, "-")
 	if len(zoneParts) != 3 {
@@ -1180,6 +1180,97 @@ func TestInitSecretHash(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfile(t *testing.T) {
+	someErr := errors.New("failed")
+	imds := stubIMDS{
+		projectID:    "someProject",
+		zone:         "someZone-west3-b",
+		instanceName: "someInstance",
+	}
+
+	testCases := map[string]struct {
+		imds            stubIMDS
+		instanceAPI     stubInstanceAPI
+		wantDiskProfile string
+		wantErr         bool
+	}{
+		"success": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instance: &computepb.Instance{
+					Name: proto.String("someInstance"),
+					Metadata: &computepb.Metadata{
+						Items: []*computepb.Items{
+							{
+								Key:   proto.String(cloud.TagInitSecretHash),
+								Value: proto.String("initSecretHash"),
+							},
+							{
+								Key:   proto.String(cloud.TagDiskEncryptionProfile),
+								Value: proto.String("aes-xts"),
+							},
+						},
+					},
+				},
+			},
+			wantDiskProfile: "aes-xts",
+		},
+		"profile not set": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instance: &computepb.Instance{
+					Name:     proto.String("someInstance"),
+					Metadata: &computepb.Metadata{},
+				},
+			},
+		},
+		"imds error": {
+			imds: stubIMDS{
+				projectIDErr: someErr,
+			},
+			wantErr: true,
+		},
+		"instance error": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instanceErr: someErr,
+			},
+			wantErr: true,
+		},
+		"invalid metadata item": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instance: &computepb.Instance{
+					Name: proto.String("someInstance"),
+					Metadata: &computepb.Metadata{
+						Items: []*computepb.Items{{Key: proto.String(cloud.TagDiskEncryptionProfile)}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cloud := &Cloud{
+				imds:        &tc.imds,
+				instanceAPI: &tc.instanceAPI,
+			}
+
+			diskProfile, err := cloud.DiskEncryptionProfile(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantDiskProfile, diskProfile)
+		})
+	}
+}
+
 type stubGlobalForwardingRulesAPI struct {
 	iterator forwardingRuleIterator
 }
@@ -1336,3 +1427,123 @@ func (s *stubZoneIterator) Next() (*computepb.Zone, error) {
 	s.ctr++
 	return s.zones[s.ctr-1], nil
 }
+
+func TestNodeRegistration(t *testing.T) {
+	someErr := errors.New("failed")
+	imds := stubIMDS{
+		projectID:    "someProject",
+		zone:         "someZone-west3-b",
+		instanceName: "someInstance",
+	}
+
+	testCases := map[string]struct {
+		imds        stubIMDS
+		instanceAPI stubInstanceAPI
+		wantLabels  string
+		wantTaints  string
+		wantErr     bool
+	}{
+		"success": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instance: &computepb.Instance{
+					Name: proto.String("someInstance"),
+					Metadata: &computepb.Metadata{
+						Items: []*computepb.Items{
+							{
+								Key:   proto.String(cloud.TagNodeLabels),
+								Value: proto.String("pool=spot"),
+							},
+							{
+								Key:   proto.String(cloud.TagNodeTaints),
+								Value: proto.String("spot=true:NoSchedule"),
+							},
+						},
+					},
+				},
+			},
+			wantLabels: "pool=spot",
+			wantTaints: "spot=true:NoSchedule",
+		},
+		"nothing set": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instance: &computepb.Instance{
+					Name:     proto.String("someInstance"),
+					Metadata: &computepb.Metadata{},
+				},
+			},
+		},
+		"imds error": {
+			imds: stubIMDS{
+				projectIDErr: someErr,
+			},
+			wantErr: true,
+		},
+		"instance error": {
+			imds: imds,
+			instanceAPI: stubInstanceAPI{
+				instanceErr: someErr,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cloud := &Cloud{
+				imds:        &tc.imds,
+				instanceAPI: &tc.instanceAPI,
+			}
+
+			labels, taints, err := cloud.NodeRegistration(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantLabels, labels)
+			assert.Equal(tc.wantTaints, taints)
+		})
+	}
+}
+
+func TestInstanceGroupID(t *testing.T) {
+	testCases := map[string]struct {
+		metadata *computepb.Metadata
+		wantID   string
+	}{
+		"created by instance group manager": {
+			metadata: &computepb.Metadata{
+				Items: []*computepb.Items{
+					{Key: proto.String("ssh-keys"), Value: proto.String("key")},
+					{Key: proto.String("created-by"), Value: proto.String("projects/123456789/zones/someZone-west3-b/instanceGroupManagers/worker-group")},
+				},
+			},
+			wantID: "projects/someProject/zones/someZone-west3-b/instanceGroupManagers/worker-group",
+		},
+		"created by something else": {
+			metadata: &computepb.Metadata{
+				Items: []*computepb.Items{
+					{Key: proto.String("created-by"), Value: proto.String("projects/123456789/global/images/someImage")},
+				},
+			},
+		},
+		"no created-by item": {
+			metadata: &computepb.Metadata{
+				Items: []*computepb.Items{
+					{Key: proto.String("ssh-keys"), Value: proto.String("key")},
+				},
+			},
+		},
+		"no metadata": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.wantID, instanceGroupID(tc.metadata, "someProject"))
+		})
+	}
+}
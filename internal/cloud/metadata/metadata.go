@@ -26,6 +26,10 @@ type InstanceMetadata struct {
 	// AliasIPRanges is a list of IP ranges that are attached.
 	// May be empty on certain CSPs.
 	AliasIPRanges []string
+	// ScalingGroupID is the ID of the scaling group the instance belongs to,
+	// in the format used by the node operator's ScalingGroup resources.
+	// May be empty on certain CSPs or if the instance isn't part of a scaling group.
+	ScalingGroupID string
 }
 
 // InstanceSelfer provide instance metadata about themselves.
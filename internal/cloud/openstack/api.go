@@ -21,6 +21,8 @@ type imdsAPI interface {
 	projectID(ctx context.Context) (string, error)
 	uid(ctx context.Context) (string, error)
 	initSecretHash(ctx context.Context) (string, error)
+	diskEncryptionProfile(ctx context.Context) (string, error)
+	nodeRegistration(ctx context.Context) (labels, taints string, err error)
 	role(ctx context.Context) (role.Role, error)
 	vpcIP(ctx context.Context) (string, error)
 	networkIDs(ctx context.Context) ([]string, error)
@@ -26,6 +26,11 @@ type stubIMDSClient struct {
 	uidErr               error
 	initSecretHashResult string
 	initSecretHashErr    error
+	diskProfileResult    string
+	diskProfileErr       error
+	nodeLabelsResult     string
+	nodeTaintsResult     string
+	nodeRegErr           error
 	roleResult           role.Role
 	roleErr              error
 	vpcIPResult          string
@@ -54,6 +59,14 @@ func (c *stubIMDSClient) initSecretHash(_ context.Context) (string, error) {
 	return c.initSecretHashResult, c.initSecretHashErr
 }
 
+func (c *stubIMDSClient) diskEncryptionProfile(_ context.Context) (string, error) {
+	return c.diskProfileResult, c.diskProfileErr
+}
+
+func (c *stubIMDSClient) nodeRegistration(_ context.Context) (string, string, error) {
+	return c.nodeLabelsResult, c.nodeTaintsResult, c.nodeRegErr
+}
+
 func (c *stubIMDSClient) role(_ context.Context) (role.Role, error) {
 	return c.roleResult, c.roleErr
 }
@@ -116,6 +116,30 @@ func (c *imdsClient) initSecretHash(ctx context.Context) (string, error) {
 	return c.cache.Tags.InitSecretHash, nil
 }
 
+// diskEncryptionProfile returns the name of the disk encryption profile, based on the tags on the instance
+// the function is called from. An empty string is returned if the tag isn't set.
+func (c *imdsClient) diskEncryptionProfile(ctx context.Context) (string, error) {
+	if c.timeForUpdate(c.cacheTime) {
+		if err := c.update(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	return c.cache.Tags.DiskEncryptionProfile, nil
+}
+
+// nodeRegistration returns the encoded labels and taints the kubelet registers the instance with, based on the tags
+// on the instance the function is called from. Empty strings are returned if the tags aren't set.
+func (c *imdsClient) nodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	if c.timeForUpdate(c.cacheTime) {
+		if err := c.update(ctx); err != nil {
+			return "", "", err
+		}
+	}
+
+	return c.cache.Tags.NodeLabels, c.cache.Tags.NodeTaints, nil
+}
+
 // role returns the role of the instance the function is called from.
 func (c *imdsClient) role(ctx context.Context) (role.Role, error) {
 	if c.timeForUpdate(c.cacheTime) || len(c.cache.Tags.Role) == 0 {
@@ -272,13 +296,16 @@ type metadataResponse struct {
 }
 
 type metadataTags struct {
-	InitSecretHash string `json:"constellation-init-secret-hash,omitempty"`
-	Role           string `json:"constellation-role,omitempty"`
-	UID            string `json:"constellation-uid,omitempty"`
-	AuthURL        string `json:"openstack-auth-url,omitempty"`
-	UserDomainName string `json:"openstack-user-domain-name,omitempty"`
-	Username       string `json:"openstack-username,omitempty"`
-	Password       string `json:"openstack-password,omitempty"`
+	InitSecretHash        string `json:"constellation-init-secret-hash,omitempty"`
+	DiskEncryptionProfile string `json:"constellation-disk-encryption-profile,omitempty"`
+	NodeLabels            string `json:"constellation-node-labels,omitempty"`
+	NodeTaints            string `json:"constellation-node-taints,omitempty"`
+	Role                  string `json:"constellation-role,omitempty"`
+	UID                   string `json:"constellation-uid,omitempty"`
+	AuthURL               string `json:"openstack-auth-url,omitempty"`
+	UserDomainName        string `json:"openstack-user-domain-name,omitempty"`
+	Username              string `json:"openstack-username,omitempty"`
+	Password              string `json:"openstack-password,omitempty"`
 }
 
 // networkResponse contains networkResponse with only the required values.
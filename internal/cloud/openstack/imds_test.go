@@ -185,6 +185,58 @@ func TestProviderID(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfileIMDS(t *testing.T) {
+	testCases := map[string]struct {
+		cache      metadataResponse
+		cacheTime  time.Time
+		newClient  httpClientJSONCreateFunc
+		wantResult string
+		wantErr    bool
+	}{
+		"cached": {
+			cache:      metadataResponse{Tags: metadataTags{DiskEncryptionProfile: "aes-xts"}},
+			cacheTime:  time.Now(),
+			wantResult: "aes-xts",
+		},
+		"from http": {
+			newClient:  newStubHTTPClientJSONFunc(metadataResponse{Tags: metadataTags{DiskEncryptionProfile: "aes-xts"}}, nil),
+			wantResult: "aes-xts",
+		},
+		"tag not set": {
+			newClient: newStubHTTPClientJSONFunc(metadataResponse{}, nil),
+		},
+		"http error": {
+			newClient: newStubHTTPClientJSONFunc(metadataResponse{}, errors.New("failed")),
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var client *stubHTTPClientJSON
+			if tc.newClient != nil {
+				client = tc.newClient(require)
+			}
+			imds := &imdsClient{
+				client:    client,
+				cache:     tc.cache,
+				cacheTime: tc.cacheTime,
+			}
+
+			result, err := imds.diskEncryptionProfile(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantResult, result)
+		})
+	}
+}
+
 func TestRole(t *testing.T) {
 	someErr := errors.New("failed")
 	mResp1 := metadataResponse{Tags: metadataTags{Role: "control-plane"}}
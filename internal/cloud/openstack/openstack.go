@@ -228,6 +228,26 @@ func (c *Cloud) InitSecretHash(ctx context.Context) ([]byte, error) {
 	return []byte(initSecretHash), nil
 }
 
+// DiskEncryptionProfile retrieves the name of the disk encryption profile of the current instance.
+// An empty string is returned if no profile is set.
+func (c *Cloud) DiskEncryptionProfile(ctx context.Context) (string, error) {
+	diskEncryptionProfile, err := c.imds.diskEncryptionProfile(ctx)
+	if err != nil {
+		return "", fmt.Errorf("retrieving disk encryption profile: %w", err)
+	}
+	return diskEncryptionProfile, nil
+}
+
+// NodeRegistration retrieves the encoded labels and taints the kubelet registers the current instance with.
+// Empty strings are returned if they aren't set.
+func (c *Cloud) NodeRegistration(ctx context.Context) (labels, taints string, err error) {
+	labels, taints, err = c.imds.nodeRegistration(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("retrieving node registration tags: %w", err)
+	}
+	return labels, taints, nil
+}
+
 // GetLoadBalancerEndpoint returns the endpoint of the load balancer.
 // For OpenStack, the load balancer is a floating ip attached to
 // a control plane node.
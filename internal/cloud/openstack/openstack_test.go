@@ -441,6 +441,43 @@ func TestInitSecretHash(t *testing.T) {
 	}
 }
 
+func TestDiskEncryptionProfile(t *testing.T) {
+	testCases := map[string]struct {
+		imds    *stubIMDSClient
+		want    string
+		wantErr bool
+	}{
+		"error returned from IMDS client": {
+			imds:    &stubIMDSClient{diskProfileErr: errors.New("failed")},
+			wantErr: true,
+		},
+		"profile returned from IMDS client": {
+			imds: &stubIMDSClient{diskProfileResult: "aes-xts"},
+			want: "aes-xts",
+		},
+		"profile not set": {
+			imds: &stubIMDSClient{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			c := &Cloud{imds: tc.imds}
+
+			got, err := c.DiskEncryptionProfile(context.Background())
+
+			if tc.wantErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(tc.want, got)
+			}
+		})
+	}
+}
+
 func TestGetLoadBalancerEndpoint(t *testing.T) {
 	// newTestAddrs returns a set of raw server addresses as we would get from
 	// a ListServers call and as expected by the parseSeverAddresses function.
@@ -729,3 +766,43 @@ func newSeverPager(srvs []servers.Server, err error) stubPager {
 		},
 	}
 }
+
+func TestNodeRegistration(t *testing.T) {
+	testCases := map[string]struct {
+		imds       *stubIMDSClient
+		wantLabels string
+		wantTaints string
+		wantErr    bool
+	}{
+		"error returned from IMDS client": {
+			imds:    &stubIMDSClient{nodeRegErr: errors.New("failed")},
+			wantErr: true,
+		},
+		"labels and taints returned from IMDS client": {
+			imds:       &stubIMDSClient{nodeLabelsResult: "pool=spot", nodeTaintsResult: "spot=true:NoSchedule"},
+			wantLabels: "pool=spot",
+			wantTaints: "spot=true:NoSchedule",
+		},
+		"nothing set": {
+			imds: &stubIMDSClient{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			c := &Cloud{imds: tc.imds}
+
+			labels, taints, err := c.NodeRegistration(context.Background())
+
+			if tc.wantErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(tc.wantLabels, labels)
+				assert.Equal(tc.wantTaints, taints)
+			}
+		})
+	}
+}
@@ -85,6 +85,22 @@ func (c *Cloud) InitSecretHash(ctx context.Context) ([]byte, error) {
 	return initSecretHash, nil
 }
 
+// DiskEncryptionProfile returns the name of the disk encryption profile.
+// An empty string is returned if no profile is set.
+func (c *Cloud) DiskEncryptionProfile(ctx context.Context) (string, error) {
+	diskEncryptionProfile, err := c.retrieveMetadata(ctx, "/diskencryptionprofile")
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve disk encryption profile: %w", err)
+	}
+	return string(diskEncryptionProfile), nil
+}
+
+// NodeRegistration returns the encoded labels and taints the kubelet registers the node with.
+// Node labels and taints aren't supported on QEMU, so empty strings are returned.
+func (c *Cloud) NodeRegistration(_ context.Context) (labels, taints string, err error) {
+	return "", "", nil
+}
+
 // UID returns the UID of the constellation.
 func (c *Cloud) UID(_ context.Context) (string, error) {
 	// We expect only one constellation to be deployed in the same QEMU / libvirt environment.
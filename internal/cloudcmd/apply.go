@@ -7,21 +7,24 @@ SPDX-License-Identifier: AGPL-3.0-only
 package cloudcmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/libvirt"
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/imagefetcher"
+	"github.com/edgelesssys/constellation/v2/internal/libvirt"
 	"github.com/edgelesssys/constellation/v2/internal/maa"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 const (
@@ -75,14 +78,66 @@ func NewApplier(
 
 // Plan plans the given configuration and prepares the Terraform workspace.
 func (a *Applier) Plan(ctx context.Context, conf *config.Config) (bool, error) {
+	return a.plan(ctx, conf, a.out)
+}
+
+// PlanDiff plans the given configuration and returns if changes are required, together with the Terraform diff of these changes.
+// Unlike [Applier.Plan], the Terraform workspace is restored afterwards, so planning leaves no changes behind.
+func (a *Applier) PlanDiff(ctx context.Context, conf *config.Config) (hasDiff bool, diff string, retErr error) {
+	vars, err := a.terraformApplyVars(ctx, conf)
+	if err != nil {
+		return false, "", fmt.Errorf("creating terraform variables: %w", err)
+	}
+
+	isNewWorkspace, err := a.WorkingDirIsEmpty()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, "", fmt.Errorf("checking if workspace is empty: %w", err)
+		}
+		isNewWorkspace = true
+	}
+	backupDir := filepath.Join(a.backupDir, constants.TerraformUpgradeBackupDir)
+	if err := ensureFileNotExist(a.fileHandler, backupDir); err != nil {
+		return false, "", fmt.Errorf("backup directory %s already exists: %w", backupDir, err)
+	}
+
+	defer func() {
+		// An existing workspace may only be restored if planning backed it up.
+		// Otherwise, restoring would remove the workspace without a backup to recover it from.
+		if !isNewWorkspace {
+			if _, err := a.fileHandler.Stat(backupDir); err != nil {
+				return
+			}
+		}
+		if err := a.RestoreWorkspace(); err != nil {
+			retErr = errors.Join(retErr, fmt.Errorf("restoring Terraform workspace: %w", err))
+		}
+	}()
+
+	hasDiff, err = a.planWorkspace(ctx, conf.GetProvider(), vars, io.Discard)
+	if err != nil || !hasDiff {
+		return false, "", err
+	}
+
+	planOutput := &bytes.Buffer{}
+	if err := a.terraformClient.ShowPlan(ctx, a.logLevel, planOutput); err != nil {
+		return false, "", fmt.Errorf("terraform show plan: %w", err)
+	}
+	return true, planOutput.String(), nil
+}
+
+func (a *Applier) plan(ctx context.Context, conf *config.Config, out io.Writer) (bool, error) {
 	vars, err := a.terraformApplyVars(ctx, conf)
 	if err != nil {
 		return false, fmt.Errorf("creating terraform variables: %w", err)
 	}
+	return a.planWorkspace(ctx, conf.GetProvider(), vars, out)
+}
 
+func (a *Applier) planWorkspace(ctx context.Context, csp cloudprovider.Provider, vars terraform.Variables, out io.Writer) (bool, error) {
 	return plan(
-		ctx, a.terraformClient, a.fileHandler, a.out, a.logLevel, vars,
-		filepath.Join(constants.TerraformEmbeddedDir, strings.ToLower(conf.GetProvider().String())),
+		ctx, a.terraformClient, a.fileHandler, out, a.logLevel, vars,
+		filepath.Join(constants.TerraformEmbeddedDir, strings.ToLower(csp.String())),
 		a.workingDir,
 		filepath.Join(a.backupDir, constants.TerraformUpgradeBackupDir),
 	)
@@ -114,6 +169,15 @@ func (a *Applier) Apply(ctx context.Context, csp cloudprovider.Provider, withRol
 	return infraState, nil
 }
 
+// Show reads the infrastructure state from the Terraform workspace of the Applier.
+func (a *Applier) Show(ctx context.Context, csp cloudprovider.Provider) (state.Infrastructure, error) {
+	infraState, err := a.terraformClient.ShowInfrastructure(ctx, csp)
+	if err != nil {
+		return state.Infrastructure{}, fmt.Errorf("terraform show: %w", err)
+	}
+	return infraState, nil
+}
+
 // RestoreWorkspace rolls back the existing workspace to the backup directory created when planning an action,
 // and the user decides to not apply it.
 // Note that this will not apply the restored state from the backup.
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -18,11 +19,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 func TestApplier(t *testing.T) {
@@ -314,6 +316,99 @@ func TestPlan(t *testing.T) {
 	}
 }
 
+func TestPlanDiff(t *testing.T) {
+	testCases := map[string]struct {
+		tf                *stubTerraformClient
+		fetchReferenceErr error
+		existingBackup    bool
+		wantDiff          bool
+		want              string
+		wantErr           bool
+	}{
+		"no diff": {
+			tf: &stubTerraformClient{showPlanOutput: "some diff"},
+		},
+		"diff": {
+			tf:       &stubTerraformClient{planDiff: true, showPlanOutput: "some diff"},
+			wantDiff: true,
+			want:     "some diff",
+		},
+		"plan error": {
+			tf:      &stubTerraformClient{planErr: assert.AnError},
+			wantErr: true,
+		},
+		"show plan error": {
+			tf:      &stubTerraformClient{planDiff: true, showPlanErr: assert.AnError},
+			wantErr: true,
+		},
+		"image lookup error": {
+			tf:                &stubTerraformClient{},
+			fetchReferenceErr: assert.AnError,
+			wantErr:           true,
+		},
+		"prepare workspace error": {
+			tf:      &stubTerraformClient{prepareWorkspaceErr: assert.AnError},
+			wantErr: true,
+		},
+		"backup already exists": {
+			tf:             &stubTerraformClient{},
+			existingBackup: true,
+			wantErr:        true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fs := file.NewHandler(afero.NewMemMapFs())
+			require.NoError(fs.Write("test/terraform.tfstate", []byte("state"), file.OptMkdirAll))
+			backupDir := filepath.Join(constants.UpgradeDir, "1234")
+			if tc.existingBackup {
+				require.NoError(fs.Write(filepath.Join(backupDir, constants.TerraformUpgradeBackupDir, "terraform.tfstate"), []byte("old state"), file.OptMkdirAll))
+			}
+
+			u := &Applier{
+				terraformClient: tc.tf,
+				policyPatcher:   stubPolicyPatcher{},
+				fileHandler:     fs,
+				imageFetcher:    &stubImageFetcher{reference: "some-image", fetchReferenceErr: tc.fetchReferenceErr},
+				rawDownloader:   &stubRawDownloader{destination: "some-destination"},
+				libvirtRunner:   &stubLibvirtRunner{},
+				logLevel:        terraform.LogLevelDebug,
+				backupDir:       backupDir,
+				workingDir:      "test",
+				out:             io.Discard,
+			}
+
+			cfg := config.Default()
+			cfg.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+
+			hasDiff, diff, err := u.PlanDiff(context.Background(), cfg)
+
+			// the existing workspace always survives planning
+			tfState, readErr := fs.Read("test/terraform.tfstate")
+			assert.NoError(readErr)
+			assert.Equal([]byte("state"), tfState)
+			_, statErr := fs.Stat(filepath.Join(backupDir, constants.TerraformUpgradeBackupDir))
+			if tc.existingBackup {
+				assert.NoError(statErr)
+			} else {
+				assert.ErrorIs(statErr, os.ErrNotExist)
+			}
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantDiff, hasDiff)
+			assert.Equal(tc.want, diff)
+		})
+	}
+}
+
 func TestApply(t *testing.T) {
 	testCases := map[string]struct {
 		upgradeID     string
@@ -362,6 +457,48 @@ func TestApply(t *testing.T) {
 	}
 }
 
+func TestShow(t *testing.T) {
+	testCases := map[string]struct {
+		tf        *stubTerraformClient
+		wantInfra state.Infrastructure
+		wantErr   bool
+	}{
+		"success": {
+			tf: &stubTerraformClient{
+				infraState: state.Infrastructure{UID: "uid", ClusterEndpoint: "192.0.2.1"},
+			},
+			wantInfra: state.Infrastructure{UID: "uid", ClusterEndpoint: "192.0.2.1"},
+		},
+		"show error": {
+			tf: &stubTerraformClient{
+				showInfrastructureErr: assert.AnError,
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			u := &Applier{
+				terraformClient: tc.tf,
+				workingDir:      "test",
+				out:             io.Discard,
+			}
+
+			infra, err := u.Show(context.Background(), cloudprovider.GCP)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantInfra, infra)
+			assert.True(tc.tf.showCalled)
+		})
+	}
+}
+
 type stubPolicyPatcher struct {
 	patchErr error
 }
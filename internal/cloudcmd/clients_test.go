@@ -11,10 +11,10 @@ import (
 	"io"
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 
 	"go.uber.org/goleak"
 )
@@ -46,6 +46,7 @@ type stubTerraformClient struct {
 	showIAMErr             error
 	planDiff               bool
 	planErr                error
+	showPlanOutput         string
 	showPlanErr            error
 }
 
@@ -96,8 +97,12 @@ func (c *stubTerraformClient) Plan(_ context.Context, _ terraform.LogLevel) (boo
 	return c.planDiff, c.planErr
 }
 
-func (c *stubTerraformClient) ShowPlan(_ context.Context, _ terraform.LogLevel, _ io.Writer) error {
-	return c.showPlanErr
+func (c *stubTerraformClient) ShowPlan(_ context.Context, _ terraform.LogLevel, output io.Writer) error {
+	if c.showPlanErr != nil {
+		return c.showPlanErr
+	}
+	_, err := io.WriteString(output, c.showPlanOutput)
+	return err
 }
 
 type stubLibvirtRunner struct {
@@ -0,0 +1,275 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cloudcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	armcomputev5 "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	armnetwork "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v5"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/edgelesssys/constellation/v2/internal/cloud"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/role"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+)
+
+// Node is an instance of a Constellation cluster found using the cloud provider's APIs.
+type Node struct {
+	// Name is the name of the instance at the cloud provider.
+	Name string
+	// IP is the IP address of the instance in the VPC of the cluster.
+	IP string
+}
+
+// NodeDiscoverer finds the instances of a Constellation cluster using the cloud provider's APIs.
+// The credentials of the local environment are used, just like for Terraform.
+type NodeDiscoverer struct {
+	list  func(ctx context.Context, uid string, nodeRole role.Role) ([]Node, error)
+	close func() error
+	uid   string
+}
+
+// NewNodeDiscoverer creates a new NodeDiscoverer for the cluster described by conf and infra.
+func NewNodeDiscoverer(ctx context.Context, conf *config.Config, infra state.Infrastructure) (*NodeDiscoverer, error) {
+	d := &NodeDiscoverer{uid: infra.UID, close: func() error { return nil }}
+	switch provider := conf.GetProvider(); provider {
+	case cloudprovider.AWS:
+		cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(conf.Provider.AWS.Region))
+		if err != nil {
+			return nil, fmt.Errorf("loading AWS config: %w", err)
+		}
+		d.list = (&awsNodeLister{ec2: ec2.NewFromConfig(cfg)}).list
+	case cloudprovider.GCP:
+		if infra.GCP == nil {
+			return nil, errors.New("state file is missing GCP infrastructure values")
+		}
+		client, err := compute.NewInstancesRESTClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("creating GCP instances client: %w", err)
+		}
+		d.list = (&gcpNodeLister{instances: &gcpInstancesClient{client}, project: infra.GCP.ProjectID}).list
+		d.close = client.Close
+	case cloudprovider.Azure:
+		if infra.Azure == nil {
+			return nil, errors.New("state file is missing Azure infrastructure values")
+		}
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("loading Azure credentials: %w", err)
+		}
+		scaleSets, err := armcomputev5.NewVirtualMachineScaleSetsClient(infra.Azure.SubscriptionID, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating Azure scale set client: %w", err)
+		}
+		interfaces, err := armnetwork.NewInterfacesClient(infra.Azure.SubscriptionID, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating Azure network interface client: %w", err)
+		}
+		d.list = (&azureNodeLister{
+			scaleSets:     scaleSets,
+			interfaces:    interfaces,
+			resourceGroup: infra.Azure.ResourceGroup,
+		}).list
+	default:
+		return nil, fmt.Errorf("discovering nodes is not supported on %s", provider)
+	}
+	return d, nil
+}
+
+// ControlPlaneNodes returns all running control-plane nodes of the cluster, sorted by name.
+func (d *NodeDiscoverer) ControlPlaneNodes(ctx context.Context) ([]Node, error) {
+	nodes, err := d.list(ctx, d.uid, role.ControlPlane)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	return nodes, nil
+}
+
+// Close releases the clients of the NodeDiscoverer.
+func (d *NodeDiscoverer) Close() error {
+	return d.close()
+}
+
+type ec2API interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+type awsNodeLister struct {
+	ec2 ec2API
+}
+
+func (l *awsNodeLister) list(ctx context.Context, uid string, nodeRole role.Role) ([]Node, error) {
+	req := &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("tag:" + cloud.TagUID), Values: []string{uid}},
+			{Name: aws.String("tag:" + cloud.TagRole), Values: []string{nodeRole.TFString()}},
+			{Name: aws.String("instance-state-name"), Values: []string{string(ec2types.InstanceStateNameRunning)}},
+		},
+	}
+
+	var nodes []Node
+	for {
+		out, err := l.ec2.DescribeInstances(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving instances: %w", err)
+		}
+		for _, reservation := range out.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.InstanceId == nil || instance.PrivateIpAddress == nil {
+					continue
+				}
+				nodes = append(nodes, Node{Name: *instance.InstanceId, IP: *instance.PrivateIpAddress})
+			}
+		}
+		if out.NextToken == nil {
+			return nodes, nil
+		}
+		req.NextToken = out.NextToken
+	}
+}
+
+type gcpInstancesAPI interface {
+	AggregatedList(ctx context.Context, req *computepb.AggregatedListInstancesRequest) gcpInstanceIterator
+}
+
+type gcpInstanceIterator interface {
+	Next() (compute.InstancesScopedListPair, error)
+}
+
+type gcpInstancesClient struct {
+	*compute.InstancesClient
+}
+
+func (c *gcpInstancesClient) AggregatedList(ctx context.Context, req *computepb.AggregatedListInstancesRequest) gcpInstanceIterator {
+	return c.InstancesClient.AggregatedList(ctx, req)
+}
+
+type gcpNodeLister struct {
+	instances gcpInstancesAPI
+	project   string
+}
+
+func (l *gcpNodeLister) list(ctx context.Context, uid string, nodeRole role.Role) ([]Node, error) {
+	iter := l.instances.AggregatedList(ctx, &computepb.AggregatedListInstancesRequest{
+		Project: l.project,
+		Filter: proto.String(fmt.Sprintf(
+			`(labels.%s = "%s") AND (labels.%s = "%s") AND (status = "RUNNING")`,
+			cloud.TagUID, uid, cloud.TagRole, nodeRole.TFString(),
+		)),
+	})
+
+	var nodes []Node
+	for {
+		pair, err := iter.Next()
+		if errors.Is(err, iterator.Done) {
+			return nodes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("retrieving instances: %w", err)
+		}
+		if pair.Value == nil {
+			continue
+		}
+		for _, instance := range pair.Value.Instances {
+			if len(instance.NetworkInterfaces) == 0 || instance.NetworkInterfaces[0].NetworkIP == nil {
+				continue
+			}
+			nodes = append(nodes, Node{Name: instance.GetName(), IP: *instance.NetworkInterfaces[0].NetworkIP})
+		}
+	}
+}
+
+type azureScaleSetsAPI interface {
+	NewListPager(resourceGroupName string, options *armcomputev5.VirtualMachineScaleSetsClientListOptions,
+	) *runtime.Pager[armcomputev5.VirtualMachineScaleSetsClientListResponse]
+}
+
+type azureInterfacesAPI interface {
+	NewListVirtualMachineScaleSetNetworkInterfacesPager(resourceGroupName string, virtualMachineScaleSetName string,
+		options *armnetwork.InterfacesClientListVirtualMachineScaleSetNetworkInterfacesOptions,
+	) *runtime.Pager[armnetwork.InterfacesClientListVirtualMachineScaleSetNetworkInterfacesResponse]
+}
+
+type azureNodeLister struct {
+	scaleSets     azureScaleSetsAPI
+	interfaces    azureInterfacesAPI
+	resourceGroup string
+}
+
+func (l *azureNodeLister) list(ctx context.Context, uid string, nodeRole role.Role) ([]Node, error) {
+	var nodes []Node
+	pager := l.scaleSets.NewListPager(l.resourceGroup, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving scale sets: %w", err)
+		}
+		for _, scaleSet := range page.Value {
+			if scaleSet == nil || scaleSet.Name == nil || azureTag(scaleSet.Tags, cloud.TagUID) != uid ||
+				role.FromString(azureTag(scaleSet.Tags, cloud.TagRole)) != nodeRole {
+				continue
+			}
+			scaleSetNodes, err := l.listScaleSet(ctx, *scaleSet.Name)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, scaleSetNodes...)
+		}
+	}
+	return nodes, nil
+}
+
+func (l *azureNodeLister) listScaleSet(ctx context.Context, scaleSet string) ([]Node, error) {
+	var nodes []Node
+	pager := l.interfaces.NewListVirtualMachineScaleSetNetworkInterfacesPager(l.resourceGroup, scaleSet, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving network interfaces of scale set %s: %w", scaleSet, err)
+		}
+		for _, iface := range page.Value {
+			if iface == nil || iface.Properties == nil || iface.Properties.VirtualMachine == nil ||
+				iface.Properties.VirtualMachine.ID == nil {
+				continue
+			}
+			for _, ipConfig := range iface.Properties.IPConfigurations {
+				if ipConfig == nil || ipConfig.Properties == nil || ipConfig.Properties.PrivateIPAddress == nil {
+					continue
+				}
+				nodes = append(nodes, Node{
+					Name: scaleSet + "_" + path.Base(*iface.Properties.VirtualMachine.ID),
+					IP:   *ipConfig.Properties.PrivateIPAddress,
+				})
+				break
+			}
+		}
+	}
+	return nodes, nil
+}
+
+func azureTag(tags map[string]*string, key string) string {
+	if value, ok := tags[key]; ok && value != nil {
+		return *value
+	}
+	return ""
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package cloudcmd
+
+import (
+	"context"
+	"testing"
+
+	compute "cloud.google.com/go/compute/apiv1"
+	"cloud.google.com/go/compute/apiv1/computepb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/edgelesssys/constellation/v2/internal/role"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestControlPlaneNodesAWS(t *testing.T) {
+	testCases := map[string]struct {
+		ec2       *stubEC2API
+		wantNodes []Node
+		wantErr   bool
+	}{
+		"success": {
+			ec2: &stubEC2API{outputs: []*ec2.DescribeInstancesOutput{
+				{
+					Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+						{InstanceId: aws.String("i-2"), PrivateIpAddress: aws.String("192.0.2.2")},
+						{InstanceId: aws.String("i-no-ip")},
+					}}},
+					NextToken: aws.String("next"),
+				},
+				{
+					Reservations: []ec2types.Reservation{{Instances: []ec2types.Instance{
+						{InstanceId: aws.String("i-1"), PrivateIpAddress: aws.String("192.0.2.1")},
+					}}},
+				},
+			}},
+			wantNodes: []Node{{Name: "i-1", IP: "192.0.2.1"}, {Name: "i-2", IP: "192.0.2.2"}},
+		},
+		"api error": {
+			ec2:     &stubEC2API{err: assert.AnError},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			d := &NodeDiscoverer{list: (&awsNodeLister{ec2: tc.ec2}).list, uid: "uid"}
+			nodes, err := d.ControlPlaneNodes(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantNodes, nodes)
+			for _, req := range tc.ec2.requests {
+				assert.Contains(req.Filters, ec2types.Filter{Name: aws.String("tag:constellation-uid"), Values: []string{"uid"}})
+				assert.Contains(req.Filters, ec2types.Filter{Name: aws.String("tag:constellation-role"), Values: []string{role.ControlPlane.TFString()}})
+			}
+		})
+	}
+}
+
+func TestControlPlaneNodesGCP(t *testing.T) {
+	testCases := map[string]struct {
+		iter      *stubGCPInstanceIterator
+		wantNodes []Node
+		wantErr   bool
+	}{
+		"success": {
+			iter: &stubGCPInstanceIterator{pairs: []compute.InstancesScopedListPair{
+				{Key: "zones/europe-west3-b", Value: &computepb.InstancesScopedList{Instances: []*computepb.Instance{
+					{Name: proto.String("control-plane-b"), NetworkInterfaces: []*computepb.NetworkInterface{{NetworkIP: proto.String("192.0.2.2")}}},
+				}}},
+				{Key: "zones/europe-west3-c"},
+				{Key: "zones/europe-west3-a", Value: &computepb.InstancesScopedList{Instances: []*computepb.Instance{
+					{Name: proto.String("control-plane-a"), NetworkInterfaces: []*computepb.NetworkInterface{{NetworkIP: proto.String("192.0.2.1")}}},
+					{Name: proto.String("no-interface")},
+				}}},
+			}},
+			wantNodes: []Node{{Name: "control-plane-a", IP: "192.0.2.1"}, {Name: "control-plane-b", IP: "192.0.2.2"}},
+		},
+		"api error": {
+			iter:    &stubGCPInstanceIterator{err: assert.AnError},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			instances := &stubGCPInstancesAPI{iter: tc.iter}
+			d := &NodeDiscoverer{list: (&gcpNodeLister{instances: instances, project: "project"}).list, uid: "uid"}
+			nodes, err := d.ControlPlaneNodes(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantNodes, nodes)
+			assert.Equal("project", instances.req.Project)
+			assert.Contains(instances.req.GetFilter(), `labels.constellation-uid = "uid"`)
+		})
+	}
+}
+
+type stubEC2API struct {
+	outputs  []*ec2.DescribeInstancesOutput
+	err      error
+	requests []ec2.DescribeInstancesInput
+}
+
+func (s *stubEC2API) DescribeInstances(_ context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.requests = append(s.requests, *in)
+	out := s.outputs[0]
+	s.outputs = s.outputs[1:]
+	return out, nil
+}
+
+type stubGCPInstancesAPI struct {
+	iter *stubGCPInstanceIterator
+	req  *computepb.AggregatedListInstancesRequest
+}
+
+func (s *stubGCPInstancesAPI) AggregatedList(_ context.Context, req *computepb.AggregatedListInstancesRequest) gcpInstanceIterator {
+	s.req = req
+	return s.iter
+}
+
+type stubGCPInstanceIterator struct {
+	pairs []compute.InstancesScopedListPair
+	err   error
+}
+
+func (s *stubGCPInstanceIterator) Next() (compute.InstancesScopedListPair, error) {
+	if s.err != nil {
+		return compute.InstancesScopedListPair{}, s.err
+	}
+	if len(s.pairs) == 0 {
+		return compute.InstancesScopedListPair{}, iterator.Done
+	}
+	pair := s.pairs[0]
+	s.pairs = s.pairs[1:]
+	return pair, nil
+}
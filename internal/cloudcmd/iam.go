@@ -15,10 +15,10 @@ import (
 	"path"
 	"strings"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 // IAMDestroyer destroys an IAM configuration.
@@ -154,12 +154,7 @@ func (c *IAMCreator) createGCP(ctx context.Context, cl tfIAMClient, opts *IAMCon
 		return IAMOutput{}, err
 	}
 
-	return IAMOutput{
-		CloudProvider: cloudprovider.GCP,
-		GCPOutput: GCPIAMOutput{
-			ServiceAccountKey: iamOutput.GCP.SaKey,
-		},
-	}, nil
+	return newIAMOutput(cloudprovider.GCP, iamOutput), nil
 }
 
 // createAzure creates the IAM configuration on Azure.
@@ -181,14 +176,7 @@ func (c *IAMCreator) createAzure(ctx context.Context, cl tfIAMClient, opts *IAMC
 		return IAMOutput{}, err
 	}
 
-	return IAMOutput{
-		CloudProvider: cloudprovider.Azure,
-		AzureOutput: AzureIAMOutput{
-			SubscriptionID: iamOutput.Azure.SubscriptionID,
-			TenantID:       iamOutput.Azure.TenantID,
-			UAMIID:         iamOutput.Azure.UAMIID,
-		},
-	}, nil
+	return newIAMOutput(cloudprovider.Azure, iamOutput), nil
 }
 
 // createAWS creates the IAM configuration on AWS.
@@ -209,13 +197,45 @@ func (c *IAMCreator) createAWS(ctx context.Context, cl tfIAMClient, opts *IAMCon
 		return IAMOutput{}, err
 	}
 
-	return IAMOutput{
-		CloudProvider: cloudprovider.AWS,
-		AWSOutput: AWSIAMOutput{
-			WorkerNodeInstanceProfile:   iamOutput.AWS.WorkerNodeInstanceProfile,
-			ControlPlaneInstanceProfile: iamOutput.AWS.ControlPlaneInstanceProfile,
-		},
-	}, nil
+	return newIAMOutput(cloudprovider.AWS, iamOutput), nil
+}
+
+// Show reads the outputs of an existing IAM configuration from the given Terraform workspace.
+func (c *IAMCreator) Show(ctx context.Context, provider cloudprovider.Provider, tfWorkspace string) (IAMOutput, error) {
+	cl, err := c.newTerraformClient(ctx, tfWorkspace)
+	if err != nil {
+		return IAMOutput{}, err
+	}
+	defer cl.RemoveInstaller()
+
+	iamOutput, err := cl.ShowIAM(ctx, provider)
+	if err != nil {
+		return IAMOutput{}, fmt.Errorf("getting terraform state: %w", err)
+	}
+	return newIAMOutput(provider, iamOutput), nil
+}
+
+// newIAMOutput converts the Terraform IAM output of the given provider to an IAMOutput.
+func newIAMOutput(provider cloudprovider.Provider, tfOutput terraform.IAMOutput) IAMOutput {
+	output := IAMOutput{CloudProvider: provider}
+	switch provider {
+	case cloudprovider.GCP:
+		output.GCPOutput = GCPIAMOutput{
+			ServiceAccountKey: tfOutput.GCP.SaKey,
+		}
+	case cloudprovider.Azure:
+		output.AzureOutput = AzureIAMOutput{
+			SubscriptionID: tfOutput.Azure.SubscriptionID,
+			TenantID:       tfOutput.Azure.TenantID,
+			UAMIID:         tfOutput.Azure.UAMIID,
+		}
+	case cloudprovider.AWS:
+		output.AWSOutput = AWSIAMOutput{
+			WorkerNodeInstanceProfile:   tfOutput.AWS.WorkerNodeInstanceProfile,
+			ControlPlaneInstanceProfile: tfOutput.AWS.ControlPlaneInstanceProfile,
+		}
+	}
+	return output
 }
 
 // IAMOutput is the output of creating a new IAM profile.
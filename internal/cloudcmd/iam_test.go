@@ -13,9 +13,9 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -148,6 +148,83 @@ func TestIAMCreator(t *testing.T) {
 	}
 }
 
+func TestIAMShow(t *testing.T) {
+	testCases := map[string]struct {
+		tfClient  *stubTerraformClient
+		provider  cloudprovider.Provider
+		wantIAM   IAMOutput
+		wantErr   bool
+		newTfErr  error
+		wantShown bool
+	}{
+		"aws": {
+			tfClient: &stubTerraformClient{iamOutput: terraform.IAMOutput{
+				AWS: terraform.AWSIAMOutput{ControlPlaneInstanceProfile: "cp", WorkerNodeInstanceProfile: "worker"},
+			}},
+			provider: cloudprovider.AWS,
+			wantIAM: IAMOutput{
+				CloudProvider: cloudprovider.AWS,
+				AWSOutput:     AWSIAMOutput{ControlPlaneInstanceProfile: "cp", WorkerNodeInstanceProfile: "worker"},
+			},
+			wantShown: true,
+		},
+		"azure": {
+			tfClient: &stubTerraformClient{iamOutput: terraform.IAMOutput{
+				Azure: terraform.AzureIAMOutput{SubscriptionID: "sub", TenantID: "tenant", UAMIID: "uami"},
+			}},
+			provider: cloudprovider.Azure,
+			wantIAM: IAMOutput{
+				CloudProvider: cloudprovider.Azure,
+				AzureOutput:   AzureIAMOutput{SubscriptionID: "sub", TenantID: "tenant", UAMIID: "uami"},
+			},
+			wantShown: true,
+		},
+		"gcp": {
+			tfClient: &stubTerraformClient{iamOutput: terraform.IAMOutput{
+				GCP: terraform.GCPIAMOutput{SaKey: "key"},
+			}},
+			provider: cloudprovider.GCP,
+			wantIAM: IAMOutput{
+				CloudProvider: cloudprovider.GCP,
+				GCPOutput:     GCPIAMOutput{ServiceAccountKey: "key"},
+			},
+			wantShown: true,
+		},
+		"show error": {
+			tfClient:  &stubTerraformClient{showIAMErr: assert.AnError},
+			provider:  cloudprovider.GCP,
+			wantErr:   true,
+			wantShown: true,
+		},
+		"new terraform client error": {
+			tfClient: &stubTerraformClient{},
+			provider: cloudprovider.GCP,
+			newTfErr: assert.AnError,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			creator := &IAMCreator{newTerraformClient: func(_ context.Context, _ string) (tfIAMClient, error) {
+				return tc.tfClient, tc.newTfErr
+			}}
+
+			iam, err := creator.Show(context.Background(), tc.provider, "")
+			assert.Equal(tc.wantShown, tc.tfClient.showCalled)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantIAM, iam)
+			assert.True(tc.tfClient.removeInstallerCalled)
+		})
+	}
+}
+
 func TestDestroyIAMConfiguration(t *testing.T) {
 	newError := func() error {
 		return errors.New("failed")
@@ -12,7 +12,7 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -14,13 +14,12 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/openstack"
 	"github.com/edgelesssys/constellation/v2/internal/config"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 )
 
 // GetMarshaledServiceAccountURI returns the service account URI for the given cloud provider.
 func GetMarshaledServiceAccountURI(config *config.Config, fileHandler file.Handler) (string, error) {
-	payload := constellation.ServiceAccountPayload{}
+	payload := ServiceAccountPayload{}
 	switch config.GetProvider() {
 	case cloudprovider.GCP:
 		var key gcpshared.ServiceAccountKey
@@ -50,5 +49,36 @@ func GetMarshaledServiceAccountURI(config *config.Config, fileHandler file.Handl
 		}
 
 	}
-	return constellation.MarshalServiceAccountURI(config.GetProvider(), payload)
+	return MarshalServiceAccountURI(config.GetProvider(), payload)
+}
+
+// MarshalServiceAccountURI returns the service account URI for the given cloud provider.
+func MarshalServiceAccountURI(provider cloudprovider.Provider, payload ServiceAccountPayload) (string, error) {
+	switch provider {
+	case cloudprovider.GCP:
+		return payload.GCP.ToCloudServiceAccountURI(), nil
+
+	case cloudprovider.AWS:
+		return "", nil // AWS does not need a service account URI
+
+	case cloudprovider.Azure:
+		return payload.Azure.ToCloudServiceAccountURI(), nil
+
+	case cloudprovider.OpenStack:
+		return payload.OpenStack.ToCloudServiceAccountURI(), nil
+
+	case cloudprovider.QEMU:
+		return "", nil // QEMU does not use service account keys
+
+	default:
+		return "", fmt.Errorf("unsupported cloud provider %q", provider)
+	}
+}
+
+// ServiceAccountPayload is data a service account URI can be built
+// from for a given cloud provider.
+type ServiceAccountPayload struct {
+	GCP       gcpshared.ServiceAccountKey
+	Azure     azureshared.ApplicationCredentials
+	OpenStack openstack.AccountKey
 }
@@ -13,8 +13,8 @@ import (
 	"io"
 	"os"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 // plan prepares a workspace and plans the possible Terraform actions.
@@ -39,7 +39,8 @@ func plan(
 			return false, fmt.Errorf("backup directory %s already exists: %w", backupDir, err)
 		}
 		if err := fileHandler.CopyDir(existingWorkspace, backupDir); err != nil {
-			return false, fmt.Errorf("backing up old workspace: %w", err)
+			// Don't leave an incomplete backup behind that could later replace the workspace.
+			return false, errors.Join(fmt.Errorf("backing up old workspace: %w", err), fileHandler.RemoveAll(backupDir))
 		}
 	}
 
@@ -17,15 +17,16 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/libvirt"
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes"
+	"github.com/edgelesssys/constellation/v2/internal/libvirt"
 	"github.com/edgelesssys/constellation/v2/internal/mpimage"
 	"github.com/edgelesssys/constellation/v2/internal/role"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 )
 
 // The azurerm Terraform provider enforces its own convention of case sensitivity for Azure URIs which Azure's API itself does not enforce or, even worse, actually returns.
@@ -97,6 +98,11 @@ func awsTerraformVars(conf *config.Config, imageRef string) *terraform.AWSCluste
 			Zone:            group.Zone,
 			InstanceType:    group.InstanceType,
 			DiskType:        group.StateDiskType,
+			NodeLabels:      kubernetes.MarshalNodeLabels(group.Labels),
+			NodeTaints:      kubernetes.MarshalNodeTaints(group.KubernetesTaints()),
+			Spot:            group.Spot,
+			MinCount:        group.MinCount,
+			MaxCount:        group.MaxCount,
 		}
 	}
 	return &terraform.AWSClusterVariables{
@@ -111,6 +117,8 @@ func awsTerraformVars(conf *config.Config, imageRef string) *terraform.AWSCluste
 		EnableSNP:              conf.GetAttestationConfig().GetVariant().Equal(variant.AWSSEVSNP{}),
 		CustomEndpoint:         conf.CustomEndpoint,
 		InternalLoadBalancer:   conf.InternalLoadBalancer,
+		DiskEncryptionProfile:  conf.DiskEncryptionProfile,
+		EnableIPv6:             conf.DualStack(),
 	}
 }
 
@@ -150,21 +158,27 @@ func azureTerraformVars(conf *config.Config, imageRef string) (*terraform.AzureC
 			DiskSizeGB:   group.StateDiskSizeGB,
 			DiskType:     group.StateDiskType,
 			Zones:        zones,
+			NodeLabels:   kubernetes.MarshalNodeLabels(group.Labels),
+			NodeTaints:   kubernetes.MarshalNodeTaints(group.KubernetesTaints()),
+			Spot:         group.Spot,
+			MinCount:     group.MinCount,
+			MaxCount:     group.MaxCount,
 		}
 	}
 	vars := &terraform.AzureClusterVariables{
-		Name:                 conf.Name,
-		NodeGroups:           nodeGroups,
-		Location:             conf.Provider.Azure.Location,
-		CreateMAA:            toPtr(conf.GetAttestationConfig().GetVariant().Equal(variant.AzureSEVSNP{})),
-		Debug:                toPtr(conf.IsDebugCluster()),
-		ConfidentialVM:       toPtr(conf.GetAttestationConfig().GetVariant().Equal(variant.AzureSEVSNP{})),
-		SecureBoot:           conf.Provider.Azure.SecureBoot,
-		UserAssignedIdentity: conf.Provider.Azure.UserAssignedIdentity,
-		ResourceGroup:        conf.Provider.Azure.ResourceGroup,
-		CustomEndpoint:       conf.CustomEndpoint,
-		InternalLoadBalancer: conf.InternalLoadBalancer,
-		MarketplaceImage:     nil,
+		Name:                  conf.Name,
+		NodeGroups:            nodeGroups,
+		Location:              conf.Provider.Azure.Location,
+		CreateMAA:             toPtr(conf.GetAttestationConfig().GetVariant().Equal(variant.AzureSEVSNP{})),
+		Debug:                 toPtr(conf.IsDebugCluster()),
+		ConfidentialVM:        toPtr(conf.GetAttestationConfig().GetVariant().Equal(variant.AzureSEVSNP{})),
+		SecureBoot:            conf.Provider.Azure.SecureBoot,
+		UserAssignedIdentity:  conf.Provider.Azure.UserAssignedIdentity,
+		ResourceGroup:         conf.Provider.Azure.ResourceGroup,
+		CustomEndpoint:        conf.CustomEndpoint,
+		InternalLoadBalancer:  conf.InternalLoadBalancer,
+		DiskEncryptionProfile: conf.DiskEncryptionProfile,
+		MarketplaceImage:      nil,
 	}
 
 	if conf.UseMarketplaceImage() {
@@ -214,18 +228,24 @@ func gcpTerraformVars(conf *config.Config, imageRef string) *terraform.GCPCluste
 			Zone:            group.Zone,
 			InstanceType:    group.InstanceType,
 			DiskType:        group.StateDiskType,
+			NodeLabels:      kubernetes.MarshalNodeLabels(group.Labels),
+			NodeTaints:      kubernetes.MarshalNodeTaints(group.KubernetesTaints()),
+			Spot:            group.Spot,
+			MinCount:        group.MinCount,
+			MaxCount:        group.MaxCount,
 		}
 	}
 	return &terraform.GCPClusterVariables{
-		Name:                 conf.Name,
-		NodeGroups:           nodeGroups,
-		Project:              conf.Provider.GCP.Project,
-		Region:               conf.Provider.GCP.Region,
-		Zone:                 conf.Provider.GCP.Zone,
-		ImageID:              imageRef,
-		Debug:                conf.IsDebugCluster(),
-		CustomEndpoint:       conf.CustomEndpoint,
-		InternalLoadBalancer: conf.InternalLoadBalancer,
+		Name:                  conf.Name,
+		NodeGroups:            nodeGroups,
+		Project:               conf.Provider.GCP.Project,
+		Region:                conf.Provider.GCP.Region,
+		Zone:                  conf.Provider.GCP.Zone,
+		ImageID:               imageRef,
+		Debug:                 conf.IsDebugCluster(),
+		CustomEndpoint:        conf.CustomEndpoint,
+		InternalLoadBalancer:  conf.InternalLoadBalancer,
+		DiskEncryptionProfile: conf.DiskEncryptionProfile,
 	}
 }
 
@@ -262,6 +282,8 @@ func openStackTerraformVars(conf *config.Config, imageRef string) (*terraform.Op
 			FlavorID:        group.InstanceType,
 			Zone:            group.Zone,
 			StateDiskType:   group.StateDiskType,
+			NodeLabels:      kubernetes.MarshalNodeLabels(group.Labels),
+			NodeTaints:      kubernetes.MarshalNodeTaints(group.KubernetesTaints()),
 		}
 	}
 	return &terraform.OpenStackClusterVariables{
@@ -277,6 +299,7 @@ func openStackTerraformVars(conf *config.Config, imageRef string) (*terraform.Op
 		NodeGroups:              nodeGroups,
 		CustomEndpoint:          conf.CustomEndpoint,
 		InternalLoadBalancer:    conf.InternalLoadBalancer,
+		DiskEncryptionProfile:   conf.DiskEncryptionProfile,
 	}, nil
 }
 
@@ -352,15 +375,17 @@ func qemuTerraformVars(
 		LibvirtSocketPath: libvirtSocketPath,
 		// TODO(malt3): auto select boot mode based on attestation variant.
 		// requires image info v2.
-		BootMode:           "uefi",
-		ImagePath:          imagePath,
-		ImageFormat:        conf.Provider.QEMU.ImageFormat,
-		NodeGroups:         nodeGroups,
-		Machine:            "q35", // TODO(elchead): make configurable AB#3225
-		MetadataAPIImage:   conf.Provider.QEMU.MetadataAPIImage,
-		MetadataLibvirtURI: metadataLibvirtURI,
-		NVRAM:              conf.Provider.QEMU.NVRAM,
-		Firmware:           firmware,
+		BootMode:              "uefi",
+		ImagePath:             imagePath,
+		ImageFormat:           conf.Provider.QEMU.ImageFormat,
+		NodeGroups:            nodeGroups,
+		Machine:               "q35", // TODO(elchead): make configurable AB#3225
+		MetadataAPIImage:      conf.Provider.QEMU.MetadataAPIImage,
+		MetadataLibvirtURI:    metadataLibvirtURI,
+		NVRAM:                 conf.Provider.QEMU.NVRAM,
+		Firmware:              firmware,
+		DiskEncryptionProfile: conf.DiskEncryptionProfile,
+		EnableIPv6:            conf.DualStack(),
 		// TODO(malt3) enable once we have a way to auto-select values for these
 		// requires image info v2.
 		// BzImagePath:        placeholder,
@@ -9,7 +9,7 @@ package cloudcmd
 import (
 	"testing"
 
-	"github.com/edgelesssys/constellation/v2/cli/internal/terraform"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
 	"github.com/stretchr/testify/assert"
 )
 
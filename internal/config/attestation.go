@@ -50,6 +50,10 @@ func UnmarshalAttestationConfig(data []byte, attestVariant variant.Variant) (Att
 		return unmarshalTypedConfig[*QEMUVTPM](data)
 	case variant.QEMUTDX{}:
 		return unmarshalTypedConfig[*QEMUTDX](data)
+	case variant.QEMUTDXSimulated{}:
+		return unmarshalTypedConfig[*QEMUTDXSimulated](data)
+	case variant.QEMUSEVSNPSimulated{}:
+		return unmarshalTypedConfig[*QEMUSEVSNPSimulated](data)
 	case variant.Dummy{}:
 		return unmarshalTypedConfig[*DummyCfg](data)
 	default:
@@ -47,6 +47,12 @@ func TestUnmarshalAttestationConfig(t *testing.T) {
 		"QEMUTDX": {
 			cfg: &QEMUTDX{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUTDX{})},
 		},
+		"QEMUTDXSimulated": {
+			cfg: &QEMUTDXSimulated{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUTDXSimulated{})},
+		},
+		"QEMUSEVSNPSimulated": {
+			cfg: &QEMUSEVSNPSimulated{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUSEVSNPSimulated{})},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -32,6 +32,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/edgelesssys/constellation/v2/internal/api/attestationconfigapi"
 	"github.com/edgelesssys/constellation/v2/internal/api/versionsapi"
@@ -42,6 +43,8 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/config/imageversion"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes"
+	"github.com/edgelesssys/constellation/v2/internal/role"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
 )
@@ -85,8 +88,17 @@ type Config struct {
 	//   Flag to enable/disable the internal load balancer. If enabled, the Constellation is only accessible from within the VPC.
 	InternalLoadBalancer bool `yaml:"internalLoadBalancer" validate:"omitempty"`
 	// description: |
-	//   The Kubernetes Service CIDR to be used for the cluster. This value will only be used during the first initialization of the Constellation.
-	ServiceCIDR string `yaml:"serviceCIDR" validate:"omitempty,cidrv4"`
+	//   Optional encryption profile for the state disks of the nodes. Supported profiles are "aes-xts-hmac-sha256" (default), "aes-xts" and "aes-xts-512". The profile can't be changed after the cluster was created.
+	DiskEncryptionProfile string `yaml:"diskEncryptionProfile,omitempty" validate:"omitempty,disk_encryption_profile"`
+	// description: |
+	//   The Kubernetes Service CIDR to be used for the cluster. For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., "10.96.0.0/12,fd00:10:96::/108". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. This value will only be used during the first initialization of the Constellation.
+	ServiceCIDR string `yaml:"serviceCIDR" validate:"omitempty,cidr_list"`
+	// description: |
+	//   Optional Kubernetes Pod CIDR to be used for the cluster. Defaults to "10.244.0.0/16". For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., "10.244.0.0/16,fd00:10:244::/56". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. Not supported on GCP, where Pods use the secondary IP range of the subnetwork. This value can't be changed after the first initialization of the Constellation.
+	PodCIDR string `yaml:"podCIDR,omitempty" validate:"omitempty,cidr_list"`
+	// description: |
+	//   Optional overrides for the configuration of the Kubernetes API server and kubelet. Only allow-listed settings can be changed. This value will only be used during the first initialization of the Constellation.
+	KubernetesOverrides *KubernetesOverrides `yaml:"kubernetesOverrides,omitempty" validate:"omitempty"`
 	// description: |
 	//   Supported cloud providers and their specific configurations.
 	Provider ProviderConfig `yaml:"provider" validate:"dive"`
@@ -96,6 +108,12 @@ type Config struct {
 	// description: |
 	//   Configuration for attestation validation. This configuration provides sensible defaults for the Constellation version it was created for.\nSee the docs for an overview on attestation: https://docs.edgeless.systems/constellation/architecture/attestation
 	Attestation AttestationConfig `yaml:"attestation" validate:"dive"`
+	// description: |
+	//   Optional remote backend to store the state file in. Operations modifying the cluster lock the state, so that concurrent operations can't corrupt it. Defaults to the state file in the workspace.
+	StateBackend *StateBackendConfig `yaml:"stateBackend,omitempty" validate:"omitempty"`
+	// description: |
+	//   Optional named overlays of this configuration, e.g., for dev, staging and prod clusters. Select a profile with the --profile flag to merge it over the base configuration. Mappings are merged recursively, all other values replace the values of the base configuration.
+	Profiles map[string]*ProfileOverlay `yaml:"profiles,omitempty" validate:"-"`
 }
 
 // ProviderConfig are cloud-provider specific configuration values used by the CLI.
@@ -288,10 +306,126 @@ type AttestationConfig struct {
 	//   QEMU tdx attestation.
 	QEMUTDX *QEMUTDX `yaml:"qemuTDX,omitempty" validate:"omitempty,dive"`
 	// description: |
+	//   QEMU attestation using software-simulated TDX. Provides no security and requires debugCluster to be true. Only use this for testing.
+	QEMUTDXSimulated *QEMUTDXSimulated `yaml:"qemuTDXSimulated,omitempty" validate:"omitempty,dive"`
+	// description: |
+	//   QEMU attestation using software-simulated SEV-SNP. Provides no security and requires debugCluster to be true. Only use this for testing.
+	QEMUSEVSNPSimulated *QEMUSEVSNPSimulated `yaml:"qemuSEVSNPSimulated,omitempty" validate:"omitempty,dive"`
+	// description: |
 	//   QEMU vTPM attestation.
 	QEMUVTPM *QEMUVTPM `yaml:"qemuVTPM,omitempty" validate:"omitempty,dive"`
 }
 
+// KubernetesOverrides are allow-listed overrides for the Kubernetes configuration of the cluster.
+type KubernetesOverrides struct {
+	// description: |
+	//   Overrides for the Kubernetes API server.
+	APIServer *APIServerOverrides `yaml:"apiServer,omitempty" validate:"omitempty"`
+	// description: |
+	//   Overrides for the kubelet of all nodes.
+	Kubelet *KubeletOverrides `yaml:"kubelet,omitempty" validate:"omitempty"`
+}
+
+// APIServerOverrides are allow-listed overrides for the Kubernetes API server.
+type APIServerOverrides struct {
+	// description: |
+	//   Admission plugins to enable in addition to the default plugins.
+	EnableAdmissionPlugins []string `yaml:"enableAdmissionPlugins,omitempty" validate:"omitempty,dive,admission_plugin"`
+	// description: |
+	//   Default admission plugins to disable.
+	DisableAdmissionPlugins []string `yaml:"disableAdmissionPlugins,omitempty" validate:"omitempty,dive,admission_plugin"`
+	// description: |
+	//   OpenID Connect authentication for the API server.
+	OIDC *OIDCConfig `yaml:"oidc,omitempty" validate:"omitempty"`
+	// description: |
+	//   Audit policy (audit.k8s.io/v1 Policy) in YAML format. Replaces the default audit policy.
+	AuditPolicy string `yaml:"auditPolicy,omitempty" validate:"omitempty,audit_policy"`
+}
+
+// OIDCConfig configures OpenID Connect authentication for the API server.
+type OIDCConfig struct {
+	// description: |
+	//   URL of the OpenID issuer. Only the https scheme is accepted.
+	IssuerURL string `yaml:"issuerURL" validate:"required,url,startswith=https://"`
+	// description: |
+	//   Client ID for the OpenID Connect client. All tokens must be issued for this client ID.
+	ClientID string `yaml:"clientID" validate:"required"`
+	// description: |
+	//   JWT claim to use as the user name.
+	UsernameClaim string `yaml:"usernameClaim,omitempty"`
+	// description: |
+	//   Prefix prepended to username claims to prevent clashes with existing names.
+	UsernamePrefix string `yaml:"usernamePrefix,omitempty"`
+	// description: |
+	//   JWT claim to use as the user's group.
+	GroupsClaim string `yaml:"groupsClaim,omitempty"`
+	// description: |
+	//   Prefix prepended to group claims to prevent clashes with existing names.
+	GroupsPrefix string `yaml:"groupsPrefix,omitempty"`
+}
+
+// KubeletOverrides are allow-listed overrides for the kubelet configuration.
+type KubeletOverrides struct {
+	// description: |
+	//   Hard eviction thresholds, e.g. memory.available: 100Mi.
+	EvictionHard map[string]string `yaml:"evictionHard,omitempty" validate:"omitempty,dive,keys,eviction_signal,endkeys,required"`
+	// description: |
+	//   Soft eviction thresholds. Each threshold requires a grace period.
+	EvictionSoft map[string]string `yaml:"evictionSoft,omitempty" validate:"omitempty,dive,keys,eviction_signal,endkeys,required"`
+	// description: |
+	//   Grace periods for the soft eviction thresholds, e.g. memory.available: 1m30s.
+	EvictionSoftGracePeriod map[string]string `yaml:"evictionSoftGracePeriod,omitempty" validate:"omitempty,dive,keys,eviction_signal,endkeys,duration"`
+}
+
+// StateBackendConfig configures where the state file is stored.
+// At most one remote backend may be configured.
+type StateBackendConfig struct {
+	// description: |
+	//   Store the state as an object in an S3-compatible bucket. The object storage must support conditional writes (If-Match and If-None-Match headers).
+	S3 *S3StateBackendConfig `yaml:"s3,omitempty" validate:"omitempty"`
+	// description: |
+	//   Store the state in a Kubernetes Secret or ConfigMap.
+	Kubernetes *KubernetesStateBackendConfig `yaml:"kubernetes,omitempty" validate:"omitempty"`
+	// description: |
+	//   Encrypt the state at rest with AES-256-GCM. The hex-encoded 32 byte key is read from the CONSTELL_STATE_ENCRYPTION_KEY environment variable.
+	Encrypt bool `yaml:"encrypt,omitempty"`
+}
+
+// S3StateBackendConfig configures storing the state in an S3-compatible bucket.
+type S3StateBackendConfig struct {
+	// description: |
+	//   Name of the bucket.
+	Bucket string `yaml:"bucket" validate:"required"`
+	// description: |
+	//   Object key of the state. The lock is stored at the same key with the suffix ".lock".
+	Key string `yaml:"key" validate:"required"`
+	// description: |
+	//   Region of the bucket.
+	Region string `yaml:"region" validate:"required"`
+	// description: |
+	//   Optional endpoint of an S3-compatible object storage.
+	Endpoint string `yaml:"endpoint,omitempty" validate:"omitempty,url"`
+	// description: |
+	//   Address the bucket in the path of the URL instead of the host name.
+	UsePathStyle bool `yaml:"usePathStyle,omitempty"`
+}
+
+// KubernetesStateBackendConfig configures storing the state in a Kubernetes Secret or ConfigMap.
+type KubernetesStateBackendConfig struct {
+	// description: |
+	//   Path to the kubeconfig of the cluster storing the state. Defaults to the kubectl loading rules, e.g., the KUBECONFIG environment variable.
+	Kubeconfig string `yaml:"kubeconfig,omitempty"`
+	// description: |
+	//   Namespace of the Secret or ConfigMap.
+	Namespace string `yaml:"namespace" validate:"required"`
+	// description: |
+	//   Name of the Secret or ConfigMap. The lock is a Lease with the same name and the suffix "-lock".
+	Name string `yaml:"name" validate:"required"`
+	// description: |
+	//   Kind of the object storing the state. Valid values are "Secret" and "ConfigMap".
+	Kind string `yaml:"kind" validate:"required,oneof=Secret ConfigMap"`
+}
+
 // NodeGroup defines a group of nodes with the same role and configuration.
 // Cloud providers use scaling groups to manage nodes of a group.
 type NodeGroup struct {
@@ -313,6 +447,53 @@ type NodeGroup struct {
 	// description: |
 	//   Number of nodes to be initially created.
 	InitialCount int `yaml:"initialCount" validate:"min=0"`
+	// description: |
+	//   Labels the kubelet registers the nodes of this group with.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// description: |
+	//   Taints the kubelet registers the nodes of this group with.
+	Taints []Taint `yaml:"taints,omitempty"`
+	// description: |
+	//   Use spot (preemptible) instances for the nodes of this group. Only supported for worker groups on AWS, Azure and GCP.
+	Spot bool `yaml:"spot,omitempty"`
+	// description: |
+	//   Minimum number of nodes the cluster autoscaler scales this group down to. Setting minCount or maxCount enables autoscaling for the group.
+	MinCount int `yaml:"minCount,omitempty" validate:"min=0"`
+	// description: |
+	//   Maximum number of nodes the cluster autoscaler scales this group up to. Setting minCount or maxCount enables autoscaling for the group.
+	MaxCount int `yaml:"maxCount,omitempty" validate:"min=0"`
+}
+
+// Autoscaling returns true if autoscaling bounds are configured for the node group.
+func (g NodeGroup) Autoscaling() bool {
+	return g.MinCount > 0 || g.MaxCount > 0
+}
+
+// Taint is a Kubernetes node taint.
+type Taint struct {
+	// description: |
+	//   Key of the taint.
+	Key string `yaml:"key"`
+	// description: |
+	//   Value of the taint.
+	Value string `yaml:"value,omitempty"`
+	// description: |
+	//   Effect of the taint on pods that don't tolerate it. Valid values are "NoSchedule", "PreferNoSchedule" and "NoExecute".
+	Effect string `yaml:"effect"`
+}
+
+// ToKubernetes converts the taint to a Kubernetes taint.
+func (t Taint) ToKubernetes() corev1.Taint {
+	return corev1.Taint{Key: t.Key, Value: t.Value, Effect: corev1.TaintEffect(t.Effect)}
+}
+
+// KubernetesTaints returns the taints of the node group as Kubernetes taints.
+func (g NodeGroup) KubernetesTaints() []corev1.Taint {
+	var taints []corev1.Taint
+	for _, taint := range g.Taints {
+		taints = append(taints, taint.ToKubernetes())
+	}
+	return taints
 }
 
 // Default returns a struct with the default config.
@@ -390,24 +571,73 @@ func Default() *Config {
 		// AWS uses aws-nitro-tpm as attestation variant
 		// AWS will have aws-sev-snp as attestation variant
 		Attestation: AttestationConfig{
-			AWSSEVSNP:          DefaultForAWSSEVSNP(),
-			AWSNitroTPM:        &AWSNitroTPM{Measurements: measurements.DefaultsFor(cloudprovider.AWS, variant.AWSNitroTPM{})},
-			AzureSEVSNP:        DefaultForAzureSEVSNP(),
-			AzureTrustedLaunch: &AzureTrustedLaunch{Measurements: measurements.DefaultsFor(cloudprovider.Azure, variant.AzureTrustedLaunch{})},
-			GCPSEVES:           &GCPSEVES{Measurements: measurements.DefaultsFor(cloudprovider.GCP, variant.GCPSEVES{})},
-			QEMUVTPM:           &QEMUVTPM{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUVTPM{})},
+			AWSSEVSNP:           DefaultForAWSSEVSNP(),
+			AWSNitroTPM:         &AWSNitroTPM{Measurements: measurements.DefaultsFor(cloudprovider.AWS, variant.AWSNitroTPM{})},
+			AzureSEVSNP:         DefaultForAzureSEVSNP(),
+			AzureTrustedLaunch:  &AzureTrustedLaunch{Measurements: measurements.DefaultsFor(cloudprovider.Azure, variant.AzureTrustedLaunch{})},
+			GCPSEVES:            &GCPSEVES{Measurements: measurements.DefaultsFor(cloudprovider.GCP, variant.GCPSEVES{})},
+			QEMUVTPM:            &QEMUVTPM{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUVTPM{})},
+			QEMUTDX:             &QEMUTDX{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUTDX{})},
+			QEMUTDXSimulated:    &QEMUTDXSimulated{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUTDXSimulated{})},
+			QEMUSEVSNPSimulated: &QEMUSEVSNPSimulated{Measurements: measurements.DefaultsFor(cloudprovider.QEMU, variant.QEMUSEVSNPSimulated{})},
 		},
 	}
 }
 
+// MiniOptions are the customizable properties of a mini cluster.
+type MiniOptions struct {
+	// ControlPlaneCount is the number of control-plane nodes.
+	ControlPlaneCount int
+	// WorkerCount is the number of worker nodes.
+	WorkerCount int
+	// VCPUs is the number of vCPUs of each VM.
+	VCPUs int
+	// Memory is the amount of memory of each VM in MiB.
+	Memory int
+	// AttestationVariant is the attestation variant of the nodes.
+	AttestationVariant variant.Variant
+}
+
+// DefaultMiniOptions returns the options of a mini cluster consisting of
+// a single control-plane and worker node using vTPM attestation.
+func DefaultMiniOptions() MiniOptions {
+	qemu := Default().Provider.QEMU
+	return MiniOptions{
+		ControlPlaneCount:  1,
+		WorkerCount:        1,
+		VCPUs:              qemu.VCPUs,
+		Memory:             qemu.Memory,
+		AttestationVariant: variant.GetDefaultAttestation(cloudprovider.QEMU),
+	}
+}
+
 // MiniDefault returns a default config for a mini cluster.
 func MiniDefault() (*Config, error) {
+	return MiniWithOptions(DefaultMiniOptions())
+}
+
+// MiniWithOptions returns a config for a mini cluster customized by opts.
+func MiniWithOptions(opts MiniOptions) (*Config, error) {
+	if !variant.ValidProvider(cloudprovider.QEMU, opts.AttestationVariant) {
+		return nil, fmt.Errorf("attestation variant %s is not supported for mini clusters", opts.AttestationVariant)
+	}
+
 	config := Default()
 	config.Name = constants.MiniConstellationUID
-	config.RemoveProviderAndAttestationExcept(cloudprovider.QEMU)
+	config.RemoveProviderExcept(cloudprovider.QEMU)
+	config.SetAttestation(opts.AttestationVariant)
+	// simulated attestation is only allowed for debug clusters
+	if variant.IsSimulated(opts.AttestationVariant) {
+		config.DebugCluster = toPtr(true)
+	}
+	config.Provider.QEMU.VCPUs = opts.VCPUs
+	config.Provider.QEMU.Memory = opts.Memory
 	for groupName, group := range config.NodeGroups {
 		group.StateDiskSizeGB = 8
-		group.InitialCount = 1
+		group.InitialCount = opts.WorkerCount
+		if group.Role == role.ControlPlane.TFString() {
+			group.InitialCount = opts.ControlPlaneCount
+		}
 		config.NodeGroups[groupName] = group
 	}
 	// only release images (e.g. v2.7.0) use the production NVRAM
@@ -418,9 +648,10 @@ func MiniDefault() (*Config, error) {
 }
 
 // fromFile returns config file with `name` read from `fileHandler` by parsing
-// it as YAML. You should prefer config.New to read env vars and validate
+// it as YAML. If profile is not empty, the profile with that name is merged over the
+// base configuration. You should prefer config.New to read env vars and validate
 // config in a consistent manner.
-func fromFile(fileHandler file.Handler, name string) (*Config, error) {
+func fromFile(fileHandler file.Handler, name, profile string) (*Config, error) {
 	var conf Config
 	if err := fileHandler.ReadYAMLStrict(name, &conf); err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
@@ -431,7 +662,36 @@ func fromFile(fileHandler file.Handler, name string) (*Config, error) {
 		}
 		return nil, fmt.Errorf("could not load config from file %s: %w", name, err)
 	}
-	return &conf, nil
+	if profile == "" {
+		return &conf, nil
+	}
+	return conf.WithProfile(profile)
+}
+
+// ReadStateBackend reads the state backend configuration from the config file with name,
+// merging the selected profile, if any. Unlike New, the config isn't validated, so commands
+// that don't need the full config can still locate the state.
+// If the config file doesn't exist, nil is returned and the state is stored in the workspace.
+func ReadStateBackend(fileHandler file.Handler, name, profile string) (*StateBackendConfig, error) {
+	conf := &Config{}
+	if err := fileHandler.ReadYAML(name, conf); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not load config from file %s: %w", name, err)
+	}
+	if profile != "" {
+		var err error
+		if conf, err = conf.WithProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+	return conf.StateBackend, nil
+}
+
+// Remote returns true if the state is stored in a remote backend instead of the workspace.
+func (c *StateBackendConfig) Remote() bool {
+	return c != nil && (c.S3 != nil || c.Kubernetes != nil)
 }
 
 func isAppClientIDError(err error) bool {
@@ -454,13 +714,13 @@ func (e *UnsupportedAppRegistrationError) Error() string {
 }
 
 // New creates a new config by:
-// 1. Reading config file via provided fileHandler from file with name.
+// 1. Reading config file via provided fileHandler from file with name and merging the selected profile, if any.
 // 2. For "latest" version values of the attestation variants fetch the version numbers.
 // 3. Read secrets from environment variables.
 // 4. Validate config. If `--force` is set the version validation will be disabled and any version combination is allowed.
-func New(fileHandler file.Handler, name string, fetcher attestationconfigapi.Fetcher, force bool) (*Config, error) {
+func New(fileHandler file.Handler, name, profile string, fetcher attestationconfigapi.Fetcher, force bool) (*Config, error) {
 	// Read config file
-	c, err := fromFile(fileHandler, name)
+	c, err := fromFile(fileHandler, name, profile)
 	if err != nil {
 		return nil, err
 	}
@@ -528,6 +788,15 @@ func (c *Config) UpdateMeasurements(newMeasurements measurements.M) {
 	if c.Attestation.QEMUVTPM != nil {
 		c.Attestation.QEMUVTPM.Measurements.CopyFrom(newMeasurements)
 	}
+	if c.Attestation.QEMUTDX != nil {
+		c.Attestation.QEMUTDX.Measurements.CopyFrom(newMeasurements)
+	}
+	if c.Attestation.QEMUTDXSimulated != nil {
+		c.Attestation.QEMUTDXSimulated.Measurements.CopyFrom(newMeasurements)
+	}
+	if c.Attestation.QEMUSEVSNPSimulated != nil {
+		c.Attestation.QEMUSEVSNPSimulated.Measurements.CopyFrom(newMeasurements)
+	}
 }
 
 // RemoveProviderAndAttestationExcept calls RemoveProviderExcept and sets the default attestations for the provider (only used for convenience in tests).
@@ -577,6 +846,12 @@ func (c *Config) SetAttestation(attestation variant.Variant) {
 		c.Attestation = AttestationConfig{GCPSEVES: currentAttestationConfigs.GCPSEVES}
 	case variant.QEMUVTPM:
 		c.Attestation = AttestationConfig{QEMUVTPM: currentAttestationConfigs.QEMUVTPM}
+	case variant.QEMUTDX:
+		c.Attestation = AttestationConfig{QEMUTDX: currentAttestationConfigs.QEMUTDX}
+	case variant.QEMUTDXSimulated:
+		c.Attestation = AttestationConfig{QEMUTDXSimulated: currentAttestationConfigs.QEMUTDXSimulated}
+	case variant.QEMUSEVSNPSimulated:
+		c.Attestation = AttestationConfig{QEMUSEVSNPSimulated: currentAttestationConfigs.QEMUSEVSNPSimulated}
 	}
 }
 
@@ -644,6 +919,15 @@ func (c *Config) GetAttestationConfig() AttestationCfg {
 	if c.Attestation.QEMUVTPM != nil {
 		return c.Attestation.QEMUVTPM
 	}
+	if c.Attestation.QEMUTDX != nil {
+		return c.Attestation.QEMUTDX
+	}
+	if c.Attestation.QEMUTDXSimulated != nil {
+		return c.Attestation.QEMUTDXSimulated
+	}
+	if c.Attestation.QEMUSEVSNPSimulated != nil {
+		return c.Attestation.QEMUSEVSNPSimulated
+	}
 	return &DummyCfg{}
 }
 
@@ -692,6 +976,12 @@ func (c *Config) DeployCSIDriver() bool {
 		c.Provider.OpenStack != nil && c.Provider.OpenStack.DeployCSIDriver != nil && *c.Provider.OpenStack.DeployCSIDriver
 }
 
+// DualStack returns whether the cluster uses dual-stack IPv4/IPv6 networking.
+func (c *Config) DualStack() bool {
+	serviceCIDRs, err := kubernetes.ParseCIDRs(c.ServiceCIDR)
+	return err == nil && serviceCIDRs.DualStack()
+}
+
 // DeployYawolLoadBalancer returns whether the Yawol load balancer should be deployed.
 func (c *Config) DeployYawolLoadBalancer() bool {
 	return c.Provider.OpenStack != nil && c.Provider.OpenStack.DeployYawolLoadBalancer != nil && *c.Provider.OpenStack.DeployYawolLoadBalancer
@@ -807,8 +1097,53 @@ func (c *Config) Validate(force bool) error {
 		return err
 	}
 
-	// Register NodeGroup validation
-	validate.RegisterStructValidation(validateNodeGroups, Config{})
+	if err := validate.RegisterTranslation("node_label", trans, registerNodeLabelError, translateNodeLabelError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("node_taint", trans, registerNodeTaintError, translateNodeTaintError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("autoscaling_bounds", trans, registerAutoscalingBoundsError, translateAutoscalingBoundsError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("spot_control_plane", trans, registerSpotControlPlaneError, translateSpotControlPlaneError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("node_group_provider_unsupported", trans, registerNodeGroupProviderUnsupportedError, translateNodeGroupProviderUnsupportedError); err != nil {
+		return err
+	}
+
+	// Register networking validation
+	if err := validate.RegisterValidation("cidr_list", validateCIDRList); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("cidr_list", trans, registerCIDRListError, translateCIDRListError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("ipv4_primary", trans, registerIPv4PrimaryError, translateIPv4PrimaryError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("dual_stack_mismatch", trans, registerDualStackMismatchError, translateDualStackMismatchError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("network_provider_unsupported", trans, registerNetworkProviderUnsupportedError, translateNetworkProviderUnsupportedError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("dual_stack_provider_unsupported", trans, registerDualStackProviderUnsupportedError, translateDualStackProviderUnsupportedError); err != nil {
+		return err
+	}
+
+	if err := validate.RegisterTranslation("profile", trans, registerProfileError, translateProfileError); err != nil {
+		return err
+	}
+
+	if err := validate.RegisterTranslation("simulated_attestation", trans, registerSimulatedAttestationError, translateSimulatedAttestationError); err != nil {
+		return err
+	}
+
+	// Register NodeGroup, networking and profile validation
+	validate.RegisterStructValidation(validateConfig, Config{})
+	validate.RegisterStructValidation(validateNodeGroup, NodeGroup{})
 
 	// Register Attestation validation error types
 	if err := validate.RegisterTranslation("no_attestation", trans, registerNoAttestationError, translateNoAttestationError); err != nil {
@@ -837,6 +1172,51 @@ func (c *Config) Validate(force bool) error {
 		return err
 	}
 
+	if err := validate.RegisterValidation("disk_encryption_profile", validateDiskEncryptionProfile); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("disk_encryption_profile", trans, registerDiskEncryptionProfileError, translateDiskEncryptionProfileError); err != nil {
+		return err
+	}
+
+	// Register Kubernetes overrides validation
+	if err := validate.RegisterValidation("admission_plugin", validateAdmissionPlugin); err != nil {
+		return err
+	}
+	if err := validate.RegisterValidation("eviction_signal", validateEvictionSignal); err != nil {
+		return err
+	}
+	if err := validate.RegisterValidation("duration", validateDuration); err != nil {
+		return err
+	}
+	if err := validate.RegisterValidation("audit_policy", validateAuditPolicy); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("admission_plugin", trans, registerAdmissionPluginError, translateAdmissionPluginError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("eviction_signal", trans, registerEvictionSignalError, translateEvictionSignalError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("duration", trans, registerDurationError, translateDurationError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("audit_policy", trans, registerAuditPolicyError, translateAuditPolicyError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("admission_plugin_conflict", trans, registerAdmissionPluginConflictError, translateAdmissionPluginConflictError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("missing_grace_period", trans, registerMissingGracePeriodError, translateMissingGracePeriodError); err != nil {
+		return err
+	}
+	if err := validate.RegisterTranslation("state_backend", trans, registerStateBackendError, translateStateBackendError); err != nil {
+		return err
+	}
+	validate.RegisterStructValidation(validateAPIServerOverrides, APIServerOverrides{})
+	validate.RegisterStructValidation(validateKubeletOverrides, KubeletOverrides{})
+	validate.RegisterStructValidation(validateStateBackend, StateBackendConfig{})
+
 	validate.RegisterStructValidation(validateMeasurement, measurements.Measurement{})
 	validate.RegisterStructValidation(validateAttestation, AttestationConfig{})
 
@@ -1049,6 +1429,70 @@ func (c QEMUTDX) EqualTo(other AttestationCfg) (bool, error) {
 	return c.Measurements.EqualTo(otherCfg.Measurements), nil
 }
 
+// QEMUTDXSimulated is the configuration for QEMU attestation using simulated TDX.
+// Simulated attestation provides no security guarantees and must only be used for testing.
+type QEMUTDXSimulated struct {
+	// description: |
+	//   Expected TDX measurements.
+	Measurements measurements.M `json:"measurements" yaml:"measurements" validate:"required,no_placeholders"`
+}
+
+// GetVariant returns qemu-tdx-simulated as the variant.
+func (QEMUTDXSimulated) GetVariant() variant.Variant {
+	return variant.QEMUTDXSimulated{}
+}
+
+// GetMeasurements returns the measurements used for attestation.
+func (c QEMUTDXSimulated) GetMeasurements() measurements.M {
+	return c.Measurements
+}
+
+// SetMeasurements updates a config's measurements using the given measurements.
+func (c *QEMUTDXSimulated) SetMeasurements(m measurements.M) {
+	c.Measurements = m
+}
+
+// EqualTo returns true if the config is equal to the given config.
+func (c QEMUTDXSimulated) EqualTo(other AttestationCfg) (bool, error) {
+	otherCfg, ok := other.(*QEMUTDXSimulated)
+	if !ok {
+		return false, fmt.Errorf("cannot compare %T with %T", c, other)
+	}
+	return c.Measurements.EqualTo(otherCfg.Measurements), nil
+}
+
+// QEMUSEVSNPSimulated is the configuration for QEMU attestation using simulated SEV-SNP.
+// Simulated attestation provides no security guarantees and must only be used for testing.
+type QEMUSEVSNPSimulated struct {
+	// description: |
+	//   Expected TPM measurements.
+	Measurements measurements.M `json:"measurements" yaml:"measurements" validate:"required,no_placeholders"`
+}
+
+// GetVariant returns qemu-sev-snp-simulated as the variant.
+func (QEMUSEVSNPSimulated) GetVariant() variant.Variant {
+	return variant.QEMUSEVSNPSimulated{}
+}
+
+// GetMeasurements returns the measurements used for attestation.
+func (c QEMUSEVSNPSimulated) GetMeasurements() measurements.M {
+	return c.Measurements
+}
+
+// SetMeasurements updates a config's measurements using the given measurements.
+func (c *QEMUSEVSNPSimulated) SetMeasurements(m measurements.M) {
+	c.Measurements = m
+}
+
+// EqualTo returns true if the config is equal to the given config.
+func (c QEMUSEVSNPSimulated) EqualTo(other AttestationCfg) (bool, error) {
+	otherCfg, ok := other.(*QEMUSEVSNPSimulated)
+	if !ok {
+		return false, fmt.Errorf("cannot compare %T with %T", c, other)
+	}
+	return c.Measurements.EqualTo(otherCfg.Measurements), nil
+}
+
 // AWSSEVSNP is the configuration for AWS SEV-SNP attestation.
 type AWSSEVSNP struct {
 	// description: |
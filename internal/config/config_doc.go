@@ -19,12 +19,22 @@ var (
 	OpenStackConfigDoc                 encoder.Doc
 	QEMUConfigDoc                      encoder.Doc
 	AttestationConfigDoc               encoder.Doc
+	KubernetesOverridesDoc             encoder.Doc
+	APIServerOverridesDoc              encoder.Doc
+	OIDCConfigDoc                      encoder.Doc
+	KubeletOverridesDoc                encoder.Doc
+	StateBackendConfigDoc              encoder.Doc
+	S3StateBackendConfigDoc            encoder.Doc
+	KubernetesStateBackendConfigDoc    encoder.Doc
 	NodeGroupDoc                       encoder.Doc
+	TaintDoc                           encoder.Doc
 	UnsupportedAppRegistrationErrorDoc encoder.Doc
 	SNPFirmwareSignerConfigDoc         encoder.Doc
 	GCPSEVESDoc                        encoder.Doc
 	QEMUVTPMDoc                        encoder.Doc
 	QEMUTDXDoc                         encoder.Doc
+	QEMUTDXSimulatedDoc                encoder.Doc
+	QEMUSEVSNPSimulatedDoc             encoder.Doc
 	AWSSEVSNPDoc                       encoder.Doc
 	AWSNitroTPMDoc                     encoder.Doc
 	AzureSEVSNPDoc                     encoder.Doc
@@ -35,7 +45,7 @@ func init() {
 	ConfigDoc.Type = "Config"
 	ConfigDoc.Comments[encoder.LineComment] = "Config defines configuration used by CLI."
 	ConfigDoc.Description = "Config defines configuration used by CLI."
-	ConfigDoc.Fields = make([]encoder.Doc, 12)
+	ConfigDoc.Fields = make([]encoder.Doc, 17)
 	ConfigDoc.Fields[0].Name = "version"
 	ConfigDoc.Fields[0].Type = "string"
 	ConfigDoc.Fields[0].Note = ""
@@ -76,26 +86,51 @@ func init() {
 	ConfigDoc.Fields[7].Note = ""
 	ConfigDoc.Fields[7].Description = "Flag to enable/disable the internal load balancer. If enabled, the Constellation is only accessible from within the VPC."
 	ConfigDoc.Fields[7].Comments[encoder.LineComment] = "Flag to enable/disable the internal load balancer. If enabled, the Constellation is only accessible from within the VPC."
-	ConfigDoc.Fields[8].Name = "serviceCIDR"
+	ConfigDoc.Fields[8].Name = "diskEncryptionProfile"
 	ConfigDoc.Fields[8].Type = "string"
 	ConfigDoc.Fields[8].Note = ""
-	ConfigDoc.Fields[8].Description = "The Kubernetes Service CIDR to be used for the cluster. This value will only be used during the first initialization of the Constellation."
-	ConfigDoc.Fields[8].Comments[encoder.LineComment] = "The Kubernetes Service CIDR to be used for the cluster. This value will only be used during the first initialization of the Constellation."
-	ConfigDoc.Fields[9].Name = "provider"
-	ConfigDoc.Fields[9].Type = "ProviderConfig"
+	ConfigDoc.Fields[8].Description = "Optional encryption profile for the state disks of the nodes. Supported profiles are \"aes-xts-hmac-sha256\" (default), \"aes-xts\" and \"aes-xts-512\". The profile can't be changed after the cluster was created."
+	ConfigDoc.Fields[8].Comments[encoder.LineComment] = "Optional encryption profile for the state disks of the nodes. Supported profiles are \"aes-xts-hmac-sha256\" (default), \"aes-xts\" and \"aes-xts-512\". The profile can't be changed after the cluster was created."
+	ConfigDoc.Fields[9].Name = "serviceCIDR"
+	ConfigDoc.Fields[9].Type = "string"
 	ConfigDoc.Fields[9].Note = ""
-	ConfigDoc.Fields[9].Description = "Supported cloud providers and their specific configurations."
-	ConfigDoc.Fields[9].Comments[encoder.LineComment] = "Supported cloud providers and their specific configurations."
-	ConfigDoc.Fields[10].Name = "nodeGroups"
-	ConfigDoc.Fields[10].Type = "map[string]NodeGroup"
+	ConfigDoc.Fields[9].Description = "The Kubernetes Service CIDR to be used for the cluster. For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., \"10.96.0.0/12,fd00:10:96::/108\". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. This value will only be used during the first initialization of the Constellation."
+	ConfigDoc.Fields[9].Comments[encoder.LineComment] = "The Kubernetes Service CIDR to be used for the cluster. For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., \"10.96.0.0/12,fd00:10:96::/108\". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. This value will only be used during the first initialization of the Constellation."
+	ConfigDoc.Fields[10].Name = "podCIDR"
+	ConfigDoc.Fields[10].Type = "string"
 	ConfigDoc.Fields[10].Note = ""
-	ConfigDoc.Fields[10].Description = "Node groups to be created in the cluster."
-	ConfigDoc.Fields[10].Comments[encoder.LineComment] = "Node groups to be created in the cluster."
-	ConfigDoc.Fields[11].Name = "attestation"
-	ConfigDoc.Fields[11].Type = "AttestationConfig"
+	ConfigDoc.Fields[10].Description = "Optional Kubernetes Pod CIDR to be used for the cluster. Defaults to \"10.244.0.0/16\". For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., \"10.244.0.0/16,fd00:10:244::/56\". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. Not supported on GCP, where Pods use the secondary IP range of the subnetwork. This value can't be changed after the first initialization of the Constellation."
+	ConfigDoc.Fields[10].Comments[encoder.LineComment] = "Optional Kubernetes Pod CIDR to be used for the cluster. Defaults to \"10.244.0.0/16\". For dual-stack clusters, specify an IPv4 and an IPv6 CIDR separated by a comma, e.g., \"10.244.0.0/16,fd00:10:244::/56\". Dual-stack is only supported on AWS and QEMU, IPv6 single-stack isn't supported. Not supported on GCP, where Pods use the secondary IP range of the subnetwork. This value can't be changed after the first initialization of the Constellation."
+	ConfigDoc.Fields[11].Name = "kubernetesOverrides"
+	ConfigDoc.Fields[11].Type = "KubernetesOverrides"
 	ConfigDoc.Fields[11].Note = ""
-	ConfigDoc.Fields[11].Description = "Configuration for attestation validation. This configuration provides sensible defaults for the Constellation version it was created for.\nSee the docs for an overview on attestation: https://docs.edgeless.systems/constellation/architecture/attestation"
-	ConfigDoc.Fields[11].Comments[encoder.LineComment] = "Configuration for attestation validation. This configuration provides sensible defaults for the Constellation version it was created for.\nSee the docs for an overview on attestation: https://docs.edgeless.systems/constellation/architecture/attestation"
+	ConfigDoc.Fields[11].Description = "Optional overrides for the configuration of the Kubernetes API server and kubelet. Only allow-listed settings can be changed. This value will only be used during the first initialization of the Constellation."
+	ConfigDoc.Fields[11].Comments[encoder.LineComment] = "Optional overrides for the configuration of the Kubernetes API server and kubelet. Only allow-listed settings can be changed. This value will only be used during the first initialization of the Constellation."
+	ConfigDoc.Fields[12].Name = "provider"
+	ConfigDoc.Fields[12].Type = "ProviderConfig"
+	ConfigDoc.Fields[12].Note = ""
+	ConfigDoc.Fields[12].Description = "Supported cloud providers and their specific configurations."
+	ConfigDoc.Fields[12].Comments[encoder.LineComment] = "Supported cloud providers and their specific configurations."
+	ConfigDoc.Fields[13].Name = "nodeGroups"
+	ConfigDoc.Fields[13].Type = "map[string]NodeGroup"
+	ConfigDoc.Fields[13].Note = ""
+	ConfigDoc.Fields[13].Description = "Node groups to be created in the cluster."
+	ConfigDoc.Fields[13].Comments[encoder.LineComment] = "Node groups to be created in the cluster."
+	ConfigDoc.Fields[14].Name = "attestation"
+	ConfigDoc.Fields[14].Type = "AttestationConfig"
+	ConfigDoc.Fields[14].Note = ""
+	ConfigDoc.Fields[14].Description = "Configuration for attestation validation. This configuration provides sensible defaults for the Constellation version it was created for.\nSee the docs for an overview on attestation: https://docs.edgeless.systems/constellation/architecture/attestation"
+	ConfigDoc.Fields[14].Comments[encoder.LineComment] = "Configuration for attestation validation. This configuration provides sensible defaults for the Constellation version it was created for.\nSee the docs for an overview on attestation: https://docs.edgeless.systems/constellation/architecture/attestation"
+	ConfigDoc.Fields[15].Name = "stateBackend"
+	ConfigDoc.Fields[15].Type = "StateBackendConfig"
+	ConfigDoc.Fields[15].Note = ""
+	ConfigDoc.Fields[15].Description = "Optional remote backend to store the state file in. Operations modifying the cluster lock the state, so that concurrent operations can't corrupt it. Defaults to the state file in the workspace."
+	ConfigDoc.Fields[15].Comments[encoder.LineComment] = "Optional remote backend to store the state file in. Operations modifying the cluster lock the state, so that concurrent operations can't corrupt it. Defaults to the state file in the workspace."
+	ConfigDoc.Fields[16].Name = "profiles"
+	ConfigDoc.Fields[16].Type = "map[string]ProfileOverlay"
+	ConfigDoc.Fields[16].Note = ""
+	ConfigDoc.Fields[16].Description = "Optional named overlays of this configuration, e.g., for dev, staging and prod clusters. Select a profile with the --profile flag to merge it over the base configuration. Mappings are merged recursively, all other values replace the values of the base configuration."
+	ConfigDoc.Fields[16].Comments[encoder.LineComment] = "Optional named overlays of this configuration, e.g., for dev, staging and prod clusters. Select a profile with the --profile flag to merge it over the base configuration. Mappings are merged recursively, all other values replace the values of the base configuration."
 
 	ProviderConfigDoc.Type = "ProviderConfig"
 	ProviderConfigDoc.Comments[encoder.LineComment] = "ProviderConfig are cloud-provider specific configuration values used by the CLI."
@@ -407,7 +442,7 @@ func init() {
 			FieldName: "attestation",
 		},
 	}
-	AttestationConfigDoc.Fields = make([]encoder.Doc, 7)
+	AttestationConfigDoc.Fields = make([]encoder.Doc, 9)
 	AttestationConfigDoc.Fields[0].Name = "awsSEVSNP"
 	AttestationConfigDoc.Fields[0].Type = "AWSSEVSNP"
 	AttestationConfigDoc.Fields[0].Note = ""
@@ -438,11 +473,233 @@ func init() {
 	AttestationConfigDoc.Fields[5].Note = ""
 	AttestationConfigDoc.Fields[5].Description = "QEMU tdx attestation."
 	AttestationConfigDoc.Fields[5].Comments[encoder.LineComment] = "QEMU tdx attestation."
-	AttestationConfigDoc.Fields[6].Name = "qemuVTPM"
-	AttestationConfigDoc.Fields[6].Type = "QEMUVTPM"
+	AttestationConfigDoc.Fields[6].Name = "qemuTDXSimulated"
+	AttestationConfigDoc.Fields[6].Type = "QEMUTDXSimulated"
 	AttestationConfigDoc.Fields[6].Note = ""
-	AttestationConfigDoc.Fields[6].Description = "QEMU vTPM attestation."
-	AttestationConfigDoc.Fields[6].Comments[encoder.LineComment] = "QEMU vTPM attestation."
+	AttestationConfigDoc.Fields[6].Description = "QEMU attestation using software-simulated TDX. Provides no security and requires debugCluster to be true. Only use this for testing."
+	AttestationConfigDoc.Fields[6].Comments[encoder.LineComment] = "QEMU attestation using software-simulated TDX. Provides no security and requires debugCluster to be true. Only use this for testing."
+	AttestationConfigDoc.Fields[7].Name = "qemuSEVSNPSimulated"
+	AttestationConfigDoc.Fields[7].Type = "QEMUSEVSNPSimulated"
+	AttestationConfigDoc.Fields[7].Note = ""
+	AttestationConfigDoc.Fields[7].Description = "QEMU attestation using software-simulated SEV-SNP. Provides no security and requires debugCluster to be true. Only use this for testing."
+	AttestationConfigDoc.Fields[7].Comments[encoder.LineComment] = "QEMU attestation using software-simulated SEV-SNP. Provides no security and requires debugCluster to be true. Only use this for testing."
+	AttestationConfigDoc.Fields[8].Name = "qemuVTPM"
+	AttestationConfigDoc.Fields[8].Type = "QEMUVTPM"
+	AttestationConfigDoc.Fields[8].Note = ""
+	AttestationConfigDoc.Fields[8].Description = "QEMU vTPM attestation."
+	AttestationConfigDoc.Fields[8].Comments[encoder.LineComment] = "QEMU vTPM attestation."
+
+	KubernetesOverridesDoc.Type = "KubernetesOverrides"
+	KubernetesOverridesDoc.Comments[encoder.LineComment] = "KubernetesOverrides are allow-listed overrides for the Kubernetes configuration of the cluster."
+	KubernetesOverridesDoc.Description = "KubernetesOverrides are allow-listed overrides for the Kubernetes configuration of the cluster."
+	KubernetesOverridesDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Config",
+			FieldName: "kubernetesOverrides",
+		},
+	}
+	KubernetesOverridesDoc.Fields = make([]encoder.Doc, 2)
+	KubernetesOverridesDoc.Fields[0].Name = "apiServer"
+	KubernetesOverridesDoc.Fields[0].Type = "APIServerOverrides"
+	KubernetesOverridesDoc.Fields[0].Note = ""
+	KubernetesOverridesDoc.Fields[0].Description = "Overrides for the Kubernetes API server."
+	KubernetesOverridesDoc.Fields[0].Comments[encoder.LineComment] = "Overrides for the Kubernetes API server."
+	KubernetesOverridesDoc.Fields[1].Name = "kubelet"
+	KubernetesOverridesDoc.Fields[1].Type = "KubeletOverrides"
+	KubernetesOverridesDoc.Fields[1].Note = ""
+	KubernetesOverridesDoc.Fields[1].Description = "Overrides for the kubelet of all nodes."
+	KubernetesOverridesDoc.Fields[1].Comments[encoder.LineComment] = "Overrides for the kubelet of all nodes."
+
+	APIServerOverridesDoc.Type = "APIServerOverrides"
+	APIServerOverridesDoc.Comments[encoder.LineComment] = "APIServerOverrides are allow-listed overrides for the Kubernetes API server."
+	APIServerOverridesDoc.Description = "APIServerOverrides are allow-listed overrides for the Kubernetes API server."
+	APIServerOverridesDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubernetesOverrides",
+			FieldName: "apiServer",
+		},
+	}
+	APIServerOverridesDoc.Fields = make([]encoder.Doc, 4)
+	APIServerOverridesDoc.Fields[0].Name = "enableAdmissionPlugins"
+	APIServerOverridesDoc.Fields[0].Type = "[]string"
+	APIServerOverridesDoc.Fields[0].Note = ""
+	APIServerOverridesDoc.Fields[0].Description = "Admission plugins to enable in addition to the default plugins."
+	APIServerOverridesDoc.Fields[0].Comments[encoder.LineComment] = "Admission plugins to enable in addition to the default plugins."
+	APIServerOverridesDoc.Fields[1].Name = "disableAdmissionPlugins"
+	APIServerOverridesDoc.Fields[1].Type = "[]string"
+	APIServerOverridesDoc.Fields[1].Note = ""
+	APIServerOverridesDoc.Fields[1].Description = "Default admission plugins to disable."
+	APIServerOverridesDoc.Fields[1].Comments[encoder.LineComment] = "Default admission plugins to disable."
+	APIServerOverridesDoc.Fields[2].Name = "oidc"
+	APIServerOverridesDoc.Fields[2].Type = "OIDCConfig"
+	APIServerOverridesDoc.Fields[2].Note = ""
+	APIServerOverridesDoc.Fields[2].Description = "OpenID Connect authentication for the API server."
+	APIServerOverridesDoc.Fields[2].Comments[encoder.LineComment] = "OpenID Connect authentication for the API server."
+	APIServerOverridesDoc.Fields[3].Name = "auditPolicy"
+	APIServerOverridesDoc.Fields[3].Type = "string"
+	APIServerOverridesDoc.Fields[3].Note = ""
+	APIServerOverridesDoc.Fields[3].Description = "Audit policy (audit.k8s.io/v1 Policy) in YAML format. Replaces the default audit policy."
+	APIServerOverridesDoc.Fields[3].Comments[encoder.LineComment] = "Audit policy (audit.k8s.io/v1 Policy) in YAML format. Replaces the default audit policy."
+
+	OIDCConfigDoc.Type = "OIDCConfig"
+	OIDCConfigDoc.Comments[encoder.LineComment] = "OIDCConfig configures OpenID Connect authentication for the API server."
+	OIDCConfigDoc.Description = "OIDCConfig configures OpenID Connect authentication for the API server."
+	OIDCConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "APIServerOverrides",
+			FieldName: "oidc",
+		},
+	}
+	OIDCConfigDoc.Fields = make([]encoder.Doc, 6)
+	OIDCConfigDoc.Fields[0].Name = "issuerURL"
+	OIDCConfigDoc.Fields[0].Type = "string"
+	OIDCConfigDoc.Fields[0].Note = ""
+	OIDCConfigDoc.Fields[0].Description = "URL of the OpenID issuer. Only the https scheme is accepted."
+	OIDCConfigDoc.Fields[0].Comments[encoder.LineComment] = "URL of the OpenID issuer. Only the https scheme is accepted."
+	OIDCConfigDoc.Fields[1].Name = "clientID"
+	OIDCConfigDoc.Fields[1].Type = "string"
+	OIDCConfigDoc.Fields[1].Note = ""
+	OIDCConfigDoc.Fields[1].Description = "Client ID for the OpenID Connect client. All tokens must be issued for this client ID."
+	OIDCConfigDoc.Fields[1].Comments[encoder.LineComment] = "Client ID for the OpenID Connect client. All tokens must be issued for this client ID."
+	OIDCConfigDoc.Fields[2].Name = "usernameClaim"
+	OIDCConfigDoc.Fields[2].Type = "string"
+	OIDCConfigDoc.Fields[2].Note = ""
+	OIDCConfigDoc.Fields[2].Description = "JWT claim to use as the user name."
+	OIDCConfigDoc.Fields[2].Comments[encoder.LineComment] = "JWT claim to use as the user name."
+	OIDCConfigDoc.Fields[3].Name = "usernamePrefix"
+	OIDCConfigDoc.Fields[3].Type = "string"
+	OIDCConfigDoc.Fields[3].Note = ""
+	OIDCConfigDoc.Fields[3].Description = "Prefix prepended to username claims to prevent clashes with existing names."
+	OIDCConfigDoc.Fields[3].Comments[encoder.LineComment] = "Prefix prepended to username claims to prevent clashes with existing names."
+	OIDCConfigDoc.Fields[4].Name = "groupsClaim"
+	OIDCConfigDoc.Fields[4].Type = "string"
+	OIDCConfigDoc.Fields[4].Note = ""
+	OIDCConfigDoc.Fields[4].Description = "JWT claim to use as the user's group."
+	OIDCConfigDoc.Fields[4].Comments[encoder.LineComment] = "JWT claim to use as the user's group."
+	OIDCConfigDoc.Fields[5].Name = "groupsPrefix"
+	OIDCConfigDoc.Fields[5].Type = "string"
+	OIDCConfigDoc.Fields[5].Note = ""
+	OIDCConfigDoc.Fields[5].Description = "Prefix prepended to group claims to prevent clashes with existing names."
+	OIDCConfigDoc.Fields[5].Comments[encoder.LineComment] = "Prefix prepended to group claims to prevent clashes with existing names."
+
+	KubeletOverridesDoc.Type = "KubeletOverrides"
+	KubeletOverridesDoc.Comments[encoder.LineComment] = "KubeletOverrides are allow-listed overrides for the kubelet configuration."
+	KubeletOverridesDoc.Description = "KubeletOverrides are allow-listed overrides for the kubelet configuration."
+	KubeletOverridesDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "KubernetesOverrides",
+			FieldName: "kubelet",
+		},
+	}
+	KubeletOverridesDoc.Fields = make([]encoder.Doc, 3)
+	KubeletOverridesDoc.Fields[0].Name = "evictionHard"
+	KubeletOverridesDoc.Fields[0].Type = "map[string]string"
+	KubeletOverridesDoc.Fields[0].Note = ""
+	KubeletOverridesDoc.Fields[0].Description = "Hard eviction thresholds, e.g. memory.available: 100Mi."
+	KubeletOverridesDoc.Fields[0].Comments[encoder.LineComment] = "Hard eviction thresholds, e.g. memory.available: 100Mi."
+	KubeletOverridesDoc.Fields[1].Name = "evictionSoft"
+	KubeletOverridesDoc.Fields[1].Type = "map[string]string"
+	KubeletOverridesDoc.Fields[1].Note = ""
+	KubeletOverridesDoc.Fields[1].Description = "Soft eviction thresholds. Each threshold requires a grace period."
+	KubeletOverridesDoc.Fields[1].Comments[encoder.LineComment] = "Soft eviction thresholds. Each threshold requires a grace period."
+	KubeletOverridesDoc.Fields[2].Name = "evictionSoftGracePeriod"
+	KubeletOverridesDoc.Fields[2].Type = "map[string]string"
+	KubeletOverridesDoc.Fields[2].Note = ""
+	KubeletOverridesDoc.Fields[2].Description = "Grace periods for the soft eviction thresholds, e.g. memory.available: 1m30s."
+	KubeletOverridesDoc.Fields[2].Comments[encoder.LineComment] = "Grace periods for the soft eviction thresholds, e.g. memory.available: 1m30s."
+
+	StateBackendConfigDoc.Type = "StateBackendConfig"
+	StateBackendConfigDoc.Comments[encoder.LineComment] = "StateBackendConfig configures where the state file is stored."
+	StateBackendConfigDoc.Description = "StateBackendConfig configures where the state file is stored.\nAt most one remote backend may be configured.\n"
+	StateBackendConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "Config",
+			FieldName: "stateBackend",
+		},
+	}
+	StateBackendConfigDoc.Fields = make([]encoder.Doc, 3)
+	StateBackendConfigDoc.Fields[0].Name = "s3"
+	StateBackendConfigDoc.Fields[0].Type = "S3StateBackendConfig"
+	StateBackendConfigDoc.Fields[0].Note = ""
+	StateBackendConfigDoc.Fields[0].Description = "Store the state as an object in an S3-compatible bucket. The object storage must support conditional writes (If-Match and If-None-Match headers)."
+	StateBackendConfigDoc.Fields[0].Comments[encoder.LineComment] = "Store the state as an object in an S3-compatible bucket. The object storage must support conditional writes (If-Match and If-None-Match headers)."
+	StateBackendConfigDoc.Fields[1].Name = "kubernetes"
+	StateBackendConfigDoc.Fields[1].Type = "KubernetesStateBackendConfig"
+	StateBackendConfigDoc.Fields[1].Note = ""
+	StateBackendConfigDoc.Fields[1].Description = "Store the state in a Kubernetes Secret or ConfigMap."
+	StateBackendConfigDoc.Fields[1].Comments[encoder.LineComment] = "Store the state in a Kubernetes Secret or ConfigMap."
+	StateBackendConfigDoc.Fields[2].Name = "encrypt"
+	StateBackendConfigDoc.Fields[2].Type = "bool"
+	StateBackendConfigDoc.Fields[2].Note = ""
+	StateBackendConfigDoc.Fields[2].Description = "Encrypt the state at rest with AES-256-GCM. The hex-encoded 32 byte key is read from the CONSTELL_STATE_ENCRYPTION_KEY environment variable."
+	StateBackendConfigDoc.Fields[2].Comments[encoder.LineComment] = "Encrypt the state at rest with AES-256-GCM. The hex-encoded 32 byte key is read from the CONSTELL_STATE_ENCRYPTION_KEY environment variable."
+
+	S3StateBackendConfigDoc.Type = "S3StateBackendConfig"
+	S3StateBackendConfigDoc.Comments[encoder.LineComment] = "S3StateBackendConfig configures storing the state in an S3-compatible bucket."
+	S3StateBackendConfigDoc.Description = "S3StateBackendConfig configures storing the state in an S3-compatible bucket."
+	S3StateBackendConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "StateBackendConfig",
+			FieldName: "s3",
+		},
+	}
+	S3StateBackendConfigDoc.Fields = make([]encoder.Doc, 5)
+	S3StateBackendConfigDoc.Fields[0].Name = "bucket"
+	S3StateBackendConfigDoc.Fields[0].Type = "string"
+	S3StateBackendConfigDoc.Fields[0].Note = ""
+	S3StateBackendConfigDoc.Fields[0].Description = "Name of the bucket."
+	S3StateBackendConfigDoc.Fields[0].Comments[encoder.LineComment] = "Name of the bucket."
+	S3StateBackendConfigDoc.Fields[1].Name = "key"
+	S3StateBackendConfigDoc.Fields[1].Type = "string"
+	S3StateBackendConfigDoc.Fields[1].Note = ""
+	S3StateBackendConfigDoc.Fields[1].Description = "Object key of the state. The lock is stored at the same key with the suffix \".lock\"."
+	S3StateBackendConfigDoc.Fields[1].Comments[encoder.LineComment] = "Object key of the state. The lock is stored at the same key with the suffix \".lock\"."
+	S3StateBackendConfigDoc.Fields[2].Name = "region"
+	S3StateBackendConfigDoc.Fields[2].Type = "string"
+	S3StateBackendConfigDoc.Fields[2].Note = ""
+	S3StateBackendConfigDoc.Fields[2].Description = "Region of the bucket."
+	S3StateBackendConfigDoc.Fields[2].Comments[encoder.LineComment] = "Region of the bucket."
+	S3StateBackendConfigDoc.Fields[3].Name = "endpoint"
+	S3StateBackendConfigDoc.Fields[3].Type = "string"
+	S3StateBackendConfigDoc.Fields[3].Note = ""
+	S3StateBackendConfigDoc.Fields[3].Description = "Optional endpoint of an S3-compatible object storage."
+	S3StateBackendConfigDoc.Fields[3].Comments[encoder.LineComment] = "Optional endpoint of an S3-compatible object storage."
+	S3StateBackendConfigDoc.Fields[4].Name = "usePathStyle"
+	S3StateBackendConfigDoc.Fields[4].Type = "bool"
+	S3StateBackendConfigDoc.Fields[4].Note = ""
+	S3StateBackendConfigDoc.Fields[4].Description = "Address the bucket in the path of the URL instead of the host name."
+	S3StateBackendConfigDoc.Fields[4].Comments[encoder.LineComment] = "Address the bucket in the path of the URL instead of the host name."
+
+	KubernetesStateBackendConfigDoc.Type = "KubernetesStateBackendConfig"
+	KubernetesStateBackendConfigDoc.Comments[encoder.LineComment] = "KubernetesStateBackendConfig configures storing the state in a Kubernetes Secret or ConfigMap."
+	KubernetesStateBackendConfigDoc.Description = "KubernetesStateBackendConfig configures storing the state in a Kubernetes Secret or ConfigMap."
+	KubernetesStateBackendConfigDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "StateBackendConfig",
+			FieldName: "kubernetes",
+		},
+	}
+	KubernetesStateBackendConfigDoc.Fields = make([]encoder.Doc, 4)
+	KubernetesStateBackendConfigDoc.Fields[0].Name = "kubeconfig"
+	KubernetesStateBackendConfigDoc.Fields[0].Type = "string"
+	KubernetesStateBackendConfigDoc.Fields[0].Note = ""
+	KubernetesStateBackendConfigDoc.Fields[0].Description = "Path to the kubeconfig of the cluster storing the state. Defaults to the kubectl loading rules, e.g., the KUBECONFIG environment variable."
+	KubernetesStateBackendConfigDoc.Fields[0].Comments[encoder.LineComment] = "Path to the kubeconfig of the cluster storing the state. Defaults to the kubectl loading rules, e.g., the KUBECONFIG environment variable."
+	KubernetesStateBackendConfigDoc.Fields[1].Name = "namespace"
+	KubernetesStateBackendConfigDoc.Fields[1].Type = "string"
+	KubernetesStateBackendConfigDoc.Fields[1].Note = ""
+	KubernetesStateBackendConfigDoc.Fields[1].Description = "Namespace of the Secret or ConfigMap."
+	KubernetesStateBackendConfigDoc.Fields[1].Comments[encoder.LineComment] = "Namespace of the Secret or ConfigMap."
+	KubernetesStateBackendConfigDoc.Fields[2].Name = "name"
+	KubernetesStateBackendConfigDoc.Fields[2].Type = "string"
+	KubernetesStateBackendConfigDoc.Fields[2].Note = ""
+	KubernetesStateBackendConfigDoc.Fields[2].Description = "Name of the Secret or ConfigMap. The lock is a Lease with the same name and the suffix \"-lock\"."
+	KubernetesStateBackendConfigDoc.Fields[2].Comments[encoder.LineComment] = "Name of the Secret or ConfigMap. The lock is a Lease with the same name and the suffix \"-lock\"."
+	KubernetesStateBackendConfigDoc.Fields[3].Name = "kind"
+	KubernetesStateBackendConfigDoc.Fields[3].Type = "string"
+	KubernetesStateBackendConfigDoc.Fields[3].Note = ""
+	KubernetesStateBackendConfigDoc.Fields[3].Description = "Kind of the object storing the state. Valid values are \"Secret\" and \"ConfigMap\"."
+	KubernetesStateBackendConfigDoc.Fields[3].Comments[encoder.LineComment] = "Kind of the object storing the state. Valid values are \"Secret\" and \"ConfigMap\"."
 
 	NodeGroupDoc.Type = "NodeGroup"
 	NodeGroupDoc.Comments[encoder.LineComment] = "NodeGroup defines a group of nodes with the same role and configuration."
@@ -453,7 +710,7 @@ func init() {
 			FieldName: "nodeGroups",
 		},
 	}
-	NodeGroupDoc.Fields = make([]encoder.Doc, 6)
+	NodeGroupDoc.Fields = make([]encoder.Doc, 11)
 	NodeGroupDoc.Fields[0].Name = "role"
 	NodeGroupDoc.Fields[0].Type = "string"
 	NodeGroupDoc.Fields[0].Note = ""
@@ -484,6 +741,57 @@ func init() {
 	NodeGroupDoc.Fields[5].Note = ""
 	NodeGroupDoc.Fields[5].Description = "Number of nodes to be initially created."
 	NodeGroupDoc.Fields[5].Comments[encoder.LineComment] = "Number of nodes to be initially created."
+	NodeGroupDoc.Fields[6].Name = "labels"
+	NodeGroupDoc.Fields[6].Type = "map[string]string"
+	NodeGroupDoc.Fields[6].Note = ""
+	NodeGroupDoc.Fields[6].Description = "Labels the kubelet registers the nodes of this group with."
+	NodeGroupDoc.Fields[6].Comments[encoder.LineComment] = "Labels the kubelet registers the nodes of this group with."
+	NodeGroupDoc.Fields[7].Name = "taints"
+	NodeGroupDoc.Fields[7].Type = "[]Taint"
+	NodeGroupDoc.Fields[7].Note = ""
+	NodeGroupDoc.Fields[7].Description = "Taints the kubelet registers the nodes of this group with."
+	NodeGroupDoc.Fields[7].Comments[encoder.LineComment] = "Taints the kubelet registers the nodes of this group with."
+	NodeGroupDoc.Fields[8].Name = "spot"
+	NodeGroupDoc.Fields[8].Type = "bool"
+	NodeGroupDoc.Fields[8].Note = ""
+	NodeGroupDoc.Fields[8].Description = "Use spot (preemptible) instances for the nodes of this group. Only supported for worker groups on AWS, Azure and GCP."
+	NodeGroupDoc.Fields[8].Comments[encoder.LineComment] = "Use spot (preemptible) instances for the nodes of this group. Only supported for worker groups on AWS, Azure and GCP."
+	NodeGroupDoc.Fields[9].Name = "minCount"
+	NodeGroupDoc.Fields[9].Type = "int"
+	NodeGroupDoc.Fields[9].Note = ""
+	NodeGroupDoc.Fields[9].Description = "Minimum number of nodes the cluster autoscaler scales this group down to. Setting minCount or maxCount enables autoscaling for the group."
+	NodeGroupDoc.Fields[9].Comments[encoder.LineComment] = "Minimum number of nodes the cluster autoscaler scales this group down to. Setting minCount or maxCount enables autoscaling for the group."
+	NodeGroupDoc.Fields[10].Name = "maxCount"
+	NodeGroupDoc.Fields[10].Type = "int"
+	NodeGroupDoc.Fields[10].Note = ""
+	NodeGroupDoc.Fields[10].Description = "Maximum number of nodes the cluster autoscaler scales this group up to. Setting minCount or maxCount enables autoscaling for the group."
+	NodeGroupDoc.Fields[10].Comments[encoder.LineComment] = "Maximum number of nodes the cluster autoscaler scales this group up to. Setting minCount or maxCount enables autoscaling for the group."
+
+	TaintDoc.Type = "Taint"
+	TaintDoc.Comments[encoder.LineComment] = "Taint is a Kubernetes node taint."
+	TaintDoc.Description = "Taint is a Kubernetes node taint."
+	TaintDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "NodeGroup",
+			FieldName: "taints",
+		},
+	}
+	TaintDoc.Fields = make([]encoder.Doc, 3)
+	TaintDoc.Fields[0].Name = "key"
+	TaintDoc.Fields[0].Type = "string"
+	TaintDoc.Fields[0].Note = ""
+	TaintDoc.Fields[0].Description = "Key of the taint."
+	TaintDoc.Fields[0].Comments[encoder.LineComment] = "Key of the taint."
+	TaintDoc.Fields[1].Name = "value"
+	TaintDoc.Fields[1].Type = "string"
+	TaintDoc.Fields[1].Note = ""
+	TaintDoc.Fields[1].Description = "Value of the taint."
+	TaintDoc.Fields[1].Comments[encoder.LineComment] = "Value of the taint."
+	TaintDoc.Fields[2].Name = "effect"
+	TaintDoc.Fields[2].Type = "string"
+	TaintDoc.Fields[2].Note = ""
+	TaintDoc.Fields[2].Description = "Effect of the taint on pods that don't tolerate it. Valid values are \"NoSchedule\", \"PreferNoSchedule\" and \"NoExecute\"."
+	TaintDoc.Fields[2].Comments[encoder.LineComment] = "Effect of the taint on pods that don't tolerate it. Valid values are \"NoSchedule\", \"PreferNoSchedule\" and \"NoExecute\"."
 
 	UnsupportedAppRegistrationErrorDoc.Type = "UnsupportedAppRegistrationError"
 	UnsupportedAppRegistrationErrorDoc.Comments[encoder.LineComment] = "UnsupportedAppRegistrationError is returned when the config contains configuration related to now unsupported app registrations."
@@ -564,6 +872,38 @@ func init() {
 	QEMUTDXDoc.Fields[0].Description = "Expected TDX measurements."
 	QEMUTDXDoc.Fields[0].Comments[encoder.LineComment] = "Expected TDX measurements."
 
+	QEMUTDXSimulatedDoc.Type = "QEMUTDXSimulated"
+	QEMUTDXSimulatedDoc.Comments[encoder.LineComment] = "QEMUTDXSimulated is the configuration for QEMU attestation using simulated TDX."
+	QEMUTDXSimulatedDoc.Description = "QEMUTDXSimulated is the configuration for QEMU attestation using simulated TDX.\nSimulated attestation provides no security guarantees and must only be used for testing.\n"
+	QEMUTDXSimulatedDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "AttestationConfig",
+			FieldName: "qemuTDXSimulated",
+		},
+	}
+	QEMUTDXSimulatedDoc.Fields = make([]encoder.Doc, 1)
+	QEMUTDXSimulatedDoc.Fields[0].Name = "measurements"
+	QEMUTDXSimulatedDoc.Fields[0].Type = "M"
+	QEMUTDXSimulatedDoc.Fields[0].Note = ""
+	QEMUTDXSimulatedDoc.Fields[0].Description = "Expected TDX measurements."
+	QEMUTDXSimulatedDoc.Fields[0].Comments[encoder.LineComment] = "Expected TDX measurements."
+
+	QEMUSEVSNPSimulatedDoc.Type = "QEMUSEVSNPSimulated"
+	QEMUSEVSNPSimulatedDoc.Comments[encoder.LineComment] = "QEMUSEVSNPSimulated is the configuration for QEMU attestation using simulated SEV-SNP."
+	QEMUSEVSNPSimulatedDoc.Description = "QEMUSEVSNPSimulated is the configuration for QEMU attestation using simulated SEV-SNP.\nSimulated attestation provides no security guarantees and must only be used for testing.\n"
+	QEMUSEVSNPSimulatedDoc.AppearsIn = []encoder.Appearance{
+		{
+			TypeName:  "AttestationConfig",
+			FieldName: "qemuSEVSNPSimulated",
+		},
+	}
+	QEMUSEVSNPSimulatedDoc.Fields = make([]encoder.Doc, 1)
+	QEMUSEVSNPSimulatedDoc.Fields[0].Name = "measurements"
+	QEMUSEVSNPSimulatedDoc.Fields[0].Type = "M"
+	QEMUSEVSNPSimulatedDoc.Fields[0].Note = ""
+	QEMUSEVSNPSimulatedDoc.Fields[0].Description = "Expected TPM measurements."
+	QEMUSEVSNPSimulatedDoc.Fields[0].Comments[encoder.LineComment] = "Expected TPM measurements."
+
 	AWSSEVSNPDoc.Type = "AWSSEVSNP"
 	AWSSEVSNPDoc.Comments[encoder.LineComment] = "AWSSEVSNP is the configuration for AWS SEV-SNP attestation."
 	AWSSEVSNPDoc.Description = "AWSSEVSNP is the configuration for AWS SEV-SNP attestation."
@@ -726,10 +1066,42 @@ func (_ AttestationConfig) Doc() *encoder.Doc {
 	return &AttestationConfigDoc
 }
 
+func (_ KubernetesOverrides) Doc() *encoder.Doc {
+	return &KubernetesOverridesDoc
+}
+
+func (_ APIServerOverrides) Doc() *encoder.Doc {
+	return &APIServerOverridesDoc
+}
+
+func (_ OIDCConfig) Doc() *encoder.Doc {
+	return &OIDCConfigDoc
+}
+
+func (_ KubeletOverrides) Doc() *encoder.Doc {
+	return &KubeletOverridesDoc
+}
+
+func (_ StateBackendConfig) Doc() *encoder.Doc {
+	return &StateBackendConfigDoc
+}
+
+func (_ S3StateBackendConfig) Doc() *encoder.Doc {
+	return &S3StateBackendConfigDoc
+}
+
+func (_ KubernetesStateBackendConfig) Doc() *encoder.Doc {
+	return &KubernetesStateBackendConfigDoc
+}
+
 func (_ NodeGroup) Doc() *encoder.Doc {
 	return &NodeGroupDoc
 }
 
+func (_ Taint) Doc() *encoder.Doc {
+	return &TaintDoc
+}
+
 func (_ UnsupportedAppRegistrationError) Doc() *encoder.Doc {
 	return &UnsupportedAppRegistrationErrorDoc
 }
@@ -750,6 +1122,14 @@ func (_ QEMUTDX) Doc() *encoder.Doc {
 	return &QEMUTDXDoc
 }
 
+func (_ QEMUTDXSimulated) Doc() *encoder.Doc {
+	return &QEMUTDXSimulatedDoc
+}
+
+func (_ QEMUSEVSNPSimulated) Doc() *encoder.Doc {
+	return &QEMUSEVSNPSimulatedDoc
+}
+
 func (_ AWSSEVSNP) Doc() *encoder.Doc {
 	return &AWSSEVSNPDoc
 }
@@ -780,12 +1160,22 @@ func GetConfigurationDoc() *encoder.FileDoc {
 			&OpenStackConfigDoc,
 			&QEMUConfigDoc,
 			&AttestationConfigDoc,
+			&KubernetesOverridesDoc,
+			&APIServerOverridesDoc,
+			&OIDCConfigDoc,
+			&KubeletOverridesDoc,
+			&StateBackendConfigDoc,
+			&S3StateBackendConfigDoc,
+			&KubernetesStateBackendConfigDoc,
 			&NodeGroupDoc,
+			&TaintDoc,
 			&UnsupportedAppRegistrationErrorDoc,
 			&SNPFirmwareSignerConfigDoc,
 			&GCPSEVESDoc,
 			&QEMUVTPMDoc,
 			&QEMUTDXDoc,
+			&QEMUTDXSimulatedDoc,
+			&QEMUSEVSNPSimulatedDoc,
 			&AWSSEVSNPDoc,
 			&AWSNitroTPMDoc,
 			&AzureSEVSNPDoc,
@@ -128,7 +128,7 @@ func TestNew(t *testing.T) {
 			if tc.config != nil {
 				require.NoError(fileHandler.WriteYAML(tc.configName, tc.config, file.OptNone))
 			}
-			result, err := New(fileHandler, tc.configName, stubAttestationFetcher{}, false)
+			result, err := New(fileHandler, tc.configName, "", stubAttestationFetcher{}, false)
 			if tc.wantErr {
 				assert.Error(err)
 				return
@@ -246,7 +246,7 @@ func TestReadConfigFile(t *testing.T) {
 			if tc.config != nil {
 				require.NoError(fileHandler.WriteYAML(tc.configName, tc.config, file.OptNone))
 			}
-			result, err := fromFile(fileHandler, tc.configName)
+			result, err := fromFile(fileHandler, tc.configName, "")
 			if tc.wantedErrType != nil {
 				assert.ErrorIs(err, tc.wantedErrType)
 				return
@@ -315,7 +315,7 @@ func TestFromFile(t *testing.T) {
 				require.NoError(fileHandler.WriteYAML(tc.configName, tc.config, file.OptNone))
 			}
 
-			result, err := fromFile(fileHandler, tc.configName)
+			result, err := fromFile(fileHandler, tc.configName, "")
 
 			if tc.wantErr {
 				assert.Error(err)
@@ -327,8 +327,79 @@ func TestFromFile(t *testing.T) {
 	}
 }
 
+func TestReadStateBackend(t *testing.T) {
+	k8sBackend := &StateBackendConfig{
+		Kubernetes: &KubernetesStateBackendConfig{Namespace: "default", Name: "constellation-state", Kind: "Secret"},
+	}
+
+	testCases := map[string]struct {
+		config      *Config
+		profile     string
+		wantBackend *StateBackendConfig
+		wantErr     bool
+	}{
+		"no config file": {},
+		"no state backend": {
+			config: Default(),
+		},
+		"state backend": {
+			config: func() *Config {
+				conf := Default()
+				conf.StateBackend = k8sBackend
+				return conf
+			}(),
+			wantBackend: k8sBackend,
+		},
+		"state backend of profile": {
+			config: func() *Config {
+				conf := Default()
+				conf.AddProfile("prod")
+				conf.Profiles["prod"].Content = []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: "stateBackend"},
+					{Kind: yaml.MappingNode, Content: []*yaml.Node{
+						{Kind: yaml.ScalarNode, Value: "kubernetes"},
+						{Kind: yaml.MappingNode, Content: []*yaml.Node{
+							{Kind: yaml.ScalarNode, Value: "namespace"}, {Kind: yaml.ScalarNode, Value: "default"},
+							{Kind: yaml.ScalarNode, Value: "name"}, {Kind: yaml.ScalarNode, Value: "constellation-state"},
+							{Kind: yaml.ScalarNode, Value: "kind"}, {Kind: yaml.ScalarNode, Value: "Secret"},
+						}},
+					}},
+				}
+				return conf
+			}(),
+			profile:     "prod",
+			wantBackend: k8sBackend,
+		},
+		"unknown profile": {
+			config:  Default(),
+			profile: "prod",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			if tc.config != nil {
+				require.NoError(fileHandler.WriteYAML(constants.ConfigFilename, tc.config, file.OptNone))
+			}
+
+			backend, err := ReadStateBackend(fileHandler, constants.ConfigFilename, tc.profile)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.wantBackend, backend)
+		})
+	}
+}
+
 func TestValidate(t *testing.T) {
-	const defaultErrCount = 37 // expect this number of error messages by default because user-specific values are not set and multiple providers are defined by default
+	const defaultErrCount = 42 // expect this number of error messages by default because user-specific values are not set and multiple providers are defined by default
 	const azErrCount = 7
 	const awsErrCount = 8
 	const gcpErrCount = 8
@@ -402,6 +473,196 @@ func TestValidate(t *testing.T) {
 				return cnf
 			}(),
 		},
+		"Azure config with node registration settings and autoscaling bounds is valid": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				group := cnf.NodeGroups[constants.WorkerDefault]
+				group.Labels = map[string]string{"pool": "spot", "node.kubernetes.io/lifecycle": "spot"}
+				group.Taints = []Taint{{Key: "spot", Value: "true", Effect: "NoSchedule"}, {Key: "example.com/gpu", Effect: "NoExecute"}}
+				group.Spot = true
+				group.MinCount = 1
+				group.MaxCount = 5
+				cnf.NodeGroups[constants.WorkerDefault] = group
+				return cnf
+			}(),
+		},
+		"Azure config with invalid node registration settings and autoscaling bounds": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				group := cnf.NodeGroups[constants.ControlPlaneDefault]
+				group.Labels = map[string]string{"node-role.kubernetes.io/gpu": ""}
+				group.Taints = []Taint{{Key: "spot", Effect: "Sometimes"}}
+				group.Spot = true
+				group.MinCount = 5
+				group.MaxCount = 1
+				cnf.NodeGroups[constants.ControlPlaneDefault] = group
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 4,
+		},
+		"QEMU config with node labels": {
+			cnf: func() *Config {
+				cnf, _ := MiniDefault()
+				require.NotNil(t, cnf)
+				group := cnf.NodeGroups[constants.WorkerDefault]
+				group.Labels = map[string]string{"pool": "default"}
+				group.Spot = true
+				cnf.NodeGroups[constants.WorkerDefault] = group
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 4,
+		},
+		"QEMU config with dual-stack networking": {
+			cnf: func() *Config {
+				cnf, _ := MiniDefault()
+				require.NotNil(t, cnf)
+				cnf.ServiceCIDR = "10.96.0.0/12,fd00:10:96::/108"
+				cnf.PodCIDR = "10.244.0.0/16,fd00:10:244::/56"
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 2, // image and measurements are not set in the mini config
+		},
+		"QEMU config with IPv6 primary and mismatching networks": {
+			cnf: func() *Config {
+				cnf, _ := MiniDefault()
+				require.NotNil(t, cnf)
+				cnf.ServiceCIDR = "fd00:10:96::/108,10.96.0.0/12"
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 4,
+		},
+		"QEMU config with simulated attestation": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderExcept(cloudprovider.QEMU)
+				cnf.SetAttestation(variant.QEMUTDXSimulated{})
+				cnf.Image = constants.BinaryVersion().String()
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 2, // measurements are placeholders
+		},
+		"QEMU debug cluster with simulated attestation": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderExcept(cloudprovider.QEMU)
+				cnf.SetAttestation(variant.QEMUTDXSimulated{})
+				cnf.Image = constants.BinaryVersion().String()
+				cnf.DebugCluster = toPtr(true)
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 1, // measurements are placeholders
+		},
+		"Azure config with dual-stack networking": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.ServiceCIDR = "10.96.0.0/12,fd00:10:96::/108"
+				cnf.PodCIDR = "10.244.0.0/16,fd00:10:244::/56"
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 1,
+		},
+		"Azure config with invalid pod CIDR": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.ServiceCIDR = "fd00:10:96::/108"
+				cnf.PodCIDR = "10.244.0.0/16,10.245.0.0/16"
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 1,
+		},
+		"Azure config with profiles": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.AddProfile("dev")
+				cnf.Profiles["prod"] = &ProfileOverlay{Kind: yaml.MappingNode, Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: "name"},
+					{Kind: yaml.ScalarNode, Value: "prod"},
+				}}
+				return cnf
+			}(),
+		},
+		"Azure config with invalid profiles": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.Profiles = map[string]*ProfileOverlay{
+					"dev": {Kind: yaml.ScalarNode, Value: "dev"},
+					"prod": {Kind: yaml.MappingNode, Content: []*yaml.Node{
+						{Kind: yaml.ScalarNode, Value: "unknownField"},
+						{Kind: yaml.ScalarNode, Value: "prod"},
+					}},
+				}
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 2,
+		},
+		"Azure config with state backend": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.StateBackend = &StateBackendConfig{
+					S3: &S3StateBackendConfig{
+						Bucket:   "constellation-state",
+						Key:      "prod/constellation-state.yaml",
+						Region:   "eu-central-1",
+						Endpoint: "https://s3.example.com",
+					},
+					Encrypt: true,
+				}
+				return cnf
+			}(),
+		},
+		"Azure config with invalid state backend": {
+			cnf: func() *Config {
+				cnf := Default()
+				cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+				cnf.Image = constants.BinaryVersion().String()
+				modifyConfigForAzureToPassValidate(cnf)
+				cnf.StateBackend = &StateBackendConfig{
+					S3: &S3StateBackendConfig{
+						Bucket: "constellation-state",
+						Key:    "constellation-state.yaml",
+						Region: "eu-central-1",
+					},
+					Kubernetes: &KubernetesStateBackendConfig{
+						Namespace: "default",
+						Name:      "constellation-state",
+						Kind:      "Pod",
+					},
+				}
+				return cnf
+			}(),
+			wantErr:      true,
+			wantErrCount: 2,
+		},
 		"default AWS config is not valid": {
 			cnf: func() *Config {
 				cnf := Default()
@@ -583,6 +844,69 @@ func TestConfigRemoveProviderExcept(t *testing.T) {
 	}
 }
 
+func TestMiniWithOptions(t *testing.T) {
+	testCases := map[string]struct {
+		opts    MiniOptions
+		wantErr bool
+	}{
+		"default": {
+			opts: DefaultMiniOptions(),
+		},
+		"multiple control-plane nodes with simulated TDX": {
+			opts: MiniOptions{
+				ControlPlaneCount:  3,
+				WorkerCount:        2,
+				VCPUs:              4,
+				Memory:             4096,
+				AttestationVariant: variant.QEMUTDXSimulated{},
+			},
+		},
+		"simulated SEV-SNP": {
+			opts: MiniOptions{
+				ControlPlaneCount:  1,
+				WorkerCount:        0,
+				VCPUs:              2,
+				Memory:             2048,
+				AttestationVariant: variant.QEMUSEVSNPSimulated{},
+			},
+		},
+		"attestation variant of other provider": {
+			opts: MiniOptions{
+				ControlPlaneCount:  1,
+				WorkerCount:        1,
+				VCPUs:              2,
+				Memory:             2048,
+				AttestationVariant: variant.AWSSEVSNP{},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			// The mini config is only valid once image and measurements are embedded into the CLI.
+			conf, err := MiniWithOptions(tc.opts)
+			if tc.wantErr {
+				assert.Nil(conf)
+				assert.Error(err)
+				return
+			}
+			require.NotNil(conf)
+
+			assert.Equal(cloudprovider.QEMU, conf.GetProvider())
+			assert.True(tc.opts.AttestationVariant.Equal(conf.GetAttestationConfig().GetVariant()))
+			assert.Equal(tc.opts.VCPUs, conf.Provider.QEMU.VCPUs)
+			assert.Equal(tc.opts.Memory, conf.Provider.QEMU.Memory)
+			assert.Equal(tc.opts.ControlPlaneCount, conf.NodeGroups[constants.DefaultControlPlaneGroupName].InitialCount)
+			assert.Equal(tc.opts.WorkerCount, conf.NodeGroups[constants.DefaultWorkerGroupName].InitialCount)
+			assert.Equal(variant.IsSimulated(tc.opts.AttestationVariant), conf.IsDebugCluster())
+		})
+	}
+}
+
 func TestConfigGeneratedDocsFresh(t *testing.T) {
 	assert := assert.New(t)
 	updateMsg := "remember to re-generate config docs! 🔨"
@@ -989,7 +1313,7 @@ func TestConfigVersionCompatibility(t *testing.T) {
 
 			fileHandler := file.NewHandler(afero.NewOsFs())
 
-			config, err := fromFile(fileHandler, tc.config)
+			config, err := fromFile(fileHandler, tc.config, "")
 
 			assert.NoError(err)
 			assert.Equal(tc.expectedConfig, config)
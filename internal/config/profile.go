@@ -0,0 +1,216 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+)
+
+// ProfileOverlay is a partial configuration that is merged over the base configuration.
+type ProfileOverlay yaml.Node
+
+// MarshalYAML returns the overlay as YAML node.
+func (p *ProfileOverlay) MarshalYAML() (any, error) {
+	return (*yaml.Node)(p), nil
+}
+
+// UnmarshalYAML stores the YAML node of the overlay, without decoding it into a config.
+// The overlay is only decoded when it is merged over the base configuration.
+func (p *ProfileOverlay) UnmarshalYAML(value *yaml.Node) error {
+	*p = ProfileOverlay(*value)
+	return nil
+}
+
+// ProfileNames returns the sorted names of all profiles defined in the config.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddProfile adds an empty profile with the given name to the config, if it doesn't exist yet.
+func (c *Config) AddProfile(name string) {
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]*ProfileOverlay)
+	}
+	if _, ok := c.Profiles[name]; !ok {
+		c.Profiles[name] = &ProfileOverlay{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+}
+
+// WithProfile returns a copy of the config with the profile of the given name merged over it.
+// Mappings of the profile are merged recursively into the config, all other values replace
+// the values of the config. c is expected to be the base configuration as read from file.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %q not found, available profiles: %v", name, c.ProfileNames())
+	}
+	overlay := (*yaml.Node)(profile)
+	if overlay.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("profile %q: expected a mapping of configuration fields", name)
+	}
+	for i := 0; i < len(overlay.Content); i += 2 {
+		switch overlay.Content[i].Value {
+		case "version":
+			return nil, fmt.Errorf("profile %q: the config version can't be changed by a profile", name)
+		case "profiles":
+			return nil, fmt.Errorf("profile %q: profiles can't be nested", name)
+		}
+	}
+
+	baseNode, err := c.baseNode()
+	if err != nil {
+		return nil, err
+	}
+	merged, err := yaml.Marshal(MergeYAML(baseNode, overlay))
+	if err != nil {
+		return nil, fmt.Errorf("marshalling merged config: %w", err)
+	}
+
+	var conf Config
+	decoder := yaml.NewDecoder(bytes.NewReader(merged))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&conf); err != nil {
+		return nil, fmt.Errorf("applying profile %q: %w", name, err)
+	}
+	conf.Profiles = c.Profiles
+	return &conf, nil
+}
+
+// WriteToFile writes the config to the file with the given name.
+// If profile is not empty, c is expected to be the configuration read with that profile.
+// All differences between c and the base configuration in the file are then written to
+// the profile, leaving the base configuration and all other profiles untouched.
+func (c *Config) WriteToFile(fileHandler file.Handler, name, profile string, opts ...file.Option) error {
+	if profile == "" {
+		return fileHandler.WriteYAML(name, c, opts...)
+	}
+
+	conf, err := fromFile(fileHandler, name, "")
+	if err != nil {
+		return err
+	}
+	base, err := conf.baseNode()
+	if err != nil {
+		return err
+	}
+	current, err := c.baseNode()
+	if err != nil {
+		return err
+	}
+	overlay := DiffYAML(base, current)
+	if overlay == nil {
+		overlay = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+
+	if conf.Profiles == nil {
+		conf.Profiles = make(map[string]*ProfileOverlay)
+	}
+	conf.Profiles[profile] = (*ProfileOverlay)(overlay)
+	return fileHandler.WriteYAML(name, conf, opts...)
+}
+
+// baseNode returns the config without its profiles as YAML node.
+func (c *Config) baseNode() (*yaml.Node, error) {
+	conf := *c
+	conf.Profiles = nil
+	var node yaml.Node
+	if err := node.Encode(&conf); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	return &node, nil
+}
+
+// MergeYAML returns the result of merging the overlay node over the base node.
+// Mappings are merged recursively. All other values of the overlay,
+// including sequences, replace the values of the base.
+// Neither base nor overlay are modified.
+func MergeYAML(base, overlay *yaml.Node) *yaml.Node {
+	if base == nil || base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		return overlay
+	}
+
+	merged := *base
+	merged.Content = append([]*yaml.Node{}, base.Content...)
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		if idx := mappingIndex(&merged, key.Value); idx >= 0 {
+			merged.Content[idx+1] = MergeYAML(merged.Content[idx+1], value)
+			continue
+		}
+		merged.Content = append(merged.Content, key, value)
+	}
+	return &merged
+}
+
+// DiffYAML returns a node that, merged over base using MergeYAML, yields target.
+// Keys missing from target are set to null. DiffYAML returns nil if base and target are equal.
+func DiffYAML(base, target *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || target.Kind != yaml.MappingNode {
+		if equalYAML(base, target) {
+			return nil
+		}
+		return target
+	}
+
+	diff := &yaml.Node{Kind: yaml.MappingNode, Tag: target.Tag}
+	for i := 0; i+1 < len(target.Content); i += 2 {
+		key, value := target.Content[i], target.Content[i+1]
+		idx := mappingIndex(base, key.Value)
+		if idx < 0 {
+			diff.Content = append(diff.Content, key, value)
+			continue
+		}
+		if d := DiffYAML(base.Content[idx+1], value); d != nil {
+			diff.Content = append(diff.Content, key, d)
+		}
+	}
+	for i := 0; i+1 < len(base.Content); i += 2 {
+		key := base.Content[i]
+		if mappingIndex(target, key.Value) < 0 {
+			diff.Content = append(diff.Content, key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		}
+	}
+
+	if len(diff.Content) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// mappingIndex returns the index of the key node with the given value in a mapping node, or -1.
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// equalYAML compares two YAML nodes by their content, ignoring style, comments and position.
+func equalYAML(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.ShortTag() != b.ShortTag() || a.Value != b.Value || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !equalYAML(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+)
+
+func TestMergeYAML(t *testing.T) {
+	testCases := map[string]struct {
+		base    string
+		overlay string
+		want    string
+	}{
+		"empty overlay": {
+			base:    "a: 1\nb: 2\n",
+			overlay: "{}",
+			want:    "a: 1\nb: 2\n",
+		},
+		"scalar replaced": {
+			base:    "a: 1\nb: 2\n",
+			overlay: "b: 3\n",
+			want:    "a: 1\nb: 3\n",
+		},
+		"key added": {
+			base:    "a: 1\n",
+			overlay: "b: 2\n",
+			want:    "a: 1\nb: 2\n",
+		},
+		"nested mapping merged": {
+			base:    "a:\n    x: 1\n    y: 2\n",
+			overlay: "a:\n    y: 3\n    z: 4\n",
+			want:    "a:\n    x: 1\n    y: 3\n    z: 4\n",
+		},
+		"sequence replaced": {
+			base:    "a:\n    - 1\n    - 2\n",
+			overlay: "a:\n    - 3\n",
+			want:    "a:\n    - 3\n",
+		},
+		"mapping replaced by scalar": {
+			base:    "a:\n    x: 1\n",
+			overlay: "a: null\n",
+			want:    "a: null\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			base := parseYAMLNode(t, tc.base)
+			baseBefore, err := yaml.Marshal(base)
+			require.NoError(err)
+
+			merged, err := yaml.Marshal(MergeYAML(base, parseYAMLNode(t, tc.overlay)))
+			require.NoError(err)
+			assert.Equal(tc.want, string(merged))
+
+			baseAfter, err := yaml.Marshal(base)
+			require.NoError(err)
+			assert.Equal(baseBefore, baseAfter, "base must not be modified")
+		})
+	}
+}
+
+func TestDiffYAML(t *testing.T) {
+	testCases := map[string]struct {
+		base     string
+		target   string
+		wantDiff string
+	}{
+		"equal": {
+			base:   "a: 1\nb:\n    - x\n",
+			target: "b:\n    - x\na: 1\n",
+		},
+		"scalar changed": {
+			base:     "a: 1\nb: 2\n",
+			target:   "a: 1\nb: 3\n",
+			wantDiff: "b: 3\n",
+		},
+		"nested scalar changed": {
+			base:     "a:\n    x: 1\n    y: 2\n",
+			target:   "a:\n    x: 1\n    y: 3\n",
+			wantDiff: "a:\n    y: 3\n",
+		},
+		"key added": {
+			base:     "a: 1\n",
+			target:   "a: 1\nb: 2\n",
+			wantDiff: "b: 2\n",
+		},
+		"key removed": {
+			base:     "a: 1\nb: 2\n",
+			target:   "a: 1\n",
+			wantDiff: "b: null\n",
+		},
+		"sequence changed": {
+			base:     "a:\n    - 1\n    - 2\n",
+			target:   "a:\n    - 1\n",
+			wantDiff: "a:\n    - 1\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			base := parseYAMLNode(t, tc.base)
+			target := parseYAMLNode(t, tc.target)
+
+			diff := DiffYAML(base, target)
+			if tc.wantDiff == "" {
+				assert.Nil(diff)
+				return
+			}
+			require.NotNil(diff)
+			out, err := yaml.Marshal(diff)
+			require.NoError(err)
+			assert.Equal(tc.wantDiff, string(out))
+		})
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	testCases := map[string]struct {
+		profiles map[string]string
+		profile  string
+		wantConf func(*Config)
+		wantErr  bool
+	}{
+		"no profile": {
+			profiles: map[string]string{"dev": "name: dev\n"},
+		},
+		"scalar overridden": {
+			profiles: map[string]string{"dev": "name: dev\n", "prod": "name: prod\n"},
+			profile:  "prod",
+			wantConf: func(c *Config) {
+				c.Name = "prod"
+			},
+		},
+		"node group merged": {
+			profiles: map[string]string{"dev": "nodeGroups:\n  worker_default:\n    initialCount: 5\n"},
+			profile:  "dev",
+			wantConf: func(c *Config) {
+				group := c.NodeGroups[constants.DefaultWorkerGroupName]
+				group.InitialCount = 5
+				c.NodeGroups[constants.DefaultWorkerGroupName] = group
+			},
+		},
+		"empty profile": {
+			profiles: map[string]string{"dev": "{}"},
+			profile:  "dev",
+		},
+		"unknown profile": {
+			profiles: map[string]string{"dev": "name: dev\n"},
+			profile:  "prod",
+			wantErr:  true,
+		},
+		"unknown field": {
+			profiles: map[string]string{"dev": "nmae: dev\n"},
+			profile:  "dev",
+			wantErr:  true,
+		},
+		"version changed": {
+			profiles: map[string]string{"dev": "version: v5\n"},
+			profile:  "dev",
+			wantErr:  true,
+		},
+		"nested profiles": {
+			profiles: map[string]string{"dev": "profiles:\n  prod: {}\n"},
+			profile:  "dev",
+			wantErr:  true,
+		},
+		"profile is not a mapping": {
+			profiles: map[string]string{"dev": "- name\n"},
+			profile:  "dev",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			conf := Default()
+			modifyConfigForAzureToPassValidate(conf)
+			for name, profile := range tc.profiles {
+				if conf.Profiles == nil {
+					conf.Profiles = map[string]*ProfileOverlay{}
+				}
+				conf.Profiles[name] = (*ProfileOverlay)(parseYAMLNode(t, profile))
+			}
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			require.NoError(fileHandler.WriteYAML(constants.ConfigFilename, conf, file.OptNone))
+
+			result, err := fromFile(fileHandler, constants.ConfigFilename, tc.profile)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(conf.ProfileNames(), result.ProfileNames())
+
+			want := Default()
+			modifyConfigForAzureToPassValidate(want)
+			if tc.wantConf != nil {
+				tc.wantConf(want)
+			}
+			result.Profiles = nil
+			assert.Equal(want, result)
+		})
+	}
+}
+
+func TestWriteToFile(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := Default()
+	modifyConfigForAzureToPassValidate(conf)
+	conf.Profiles = map[string]*ProfileOverlay{
+		"dev":  (*ProfileOverlay)(parseYAMLNode(t, "name: dev\n")),
+		"prod": (*ProfileOverlay)(parseYAMLNode(t, "name: prod\n")),
+	}
+	fileHandler := file.NewHandler(afero.NewMemMapFs())
+	require.NoError(fileHandler.WriteYAML(constants.ConfigFilename, conf, file.OptNone))
+
+	dev, err := fromFile(fileHandler, constants.ConfigFilename, "dev")
+	require.NoError(err)
+	dev.CustomEndpoint = "dev.example.com"
+	require.NoError(dev.WriteToFile(fileHandler, constants.ConfigFilename, "dev", file.OptOverwrite))
+
+	// changes are written to the selected profile
+	dev, err = fromFile(fileHandler, constants.ConfigFilename, "dev")
+	require.NoError(err)
+	assert.Equal("dev", dev.Name)
+	assert.Equal("dev.example.com", dev.CustomEndpoint)
+
+	// the base configuration and other profiles are untouched
+	base, err := fromFile(fileHandler, constants.ConfigFilename, "")
+	require.NoError(err)
+	assert.Equal(conf.Name, base.Name)
+	assert.Empty(base.CustomEndpoint)
+	prod, err := fromFile(fileHandler, constants.ConfigFilename, "prod")
+	require.NoError(err)
+	assert.Equal("prod", prod.Name)
+	assert.Empty(prod.CustomEndpoint)
+}
+
+func parseYAMLNode(t *testing.T, in string) *yaml.Node {
+	t.Helper()
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(in), &node))
+	return node.Content[0]
+}
@@ -15,10 +15,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/mod/semver"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/edgelesssys/constellation/v2/internal/api/versionsapi"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
@@ -28,6 +31,8 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/config/disktypes"
 	"github.com/edgelesssys/constellation/v2/internal/config/instancetypes"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes"
 	"github.com/edgelesssys/constellation/v2/internal/role"
 	consemver "github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
@@ -202,6 +207,15 @@ func validateAttestation(sl validator.StructLevel) {
 	if attestation.QEMUVTPM != nil {
 		attestationCount++
 	}
+	if attestation.QEMUTDX != nil {
+		attestationCount++
+	}
+	if attestation.QEMUTDXSimulated != nil {
+		attestationCount++
+	}
+	if attestation.QEMUSEVSNPSimulated != nil {
+		attestationCount++
+	}
 
 	if attestationCount < 1 {
 		sl.ReportError(attestation, "Attestation", "Attestation", "no_attestation", "")
@@ -210,6 +224,40 @@ func validateAttestation(sl validator.StructLevel) {
 	}
 }
 
+// validateConfig runs the struct level validations of the Config.
+func validateConfig(sl validator.StructLevel) {
+	validateNodeGroups(sl)
+	validateNetworking(sl)
+	validateProfiles(sl)
+	validateSimulatedAttestation(sl)
+}
+
+// validateSimulatedAttestation checks that simulated attestation is only used by debug clusters.
+// Anyone can forge simulated attestation reports, so they must never protect a production cluster.
+func validateSimulatedAttestation(sl validator.StructLevel) {
+	conf := sl.Current().Interface().(Config)
+	if conf.IsDebugCluster() {
+		return
+	}
+	if conf.Attestation.QEMUTDXSimulated != nil {
+		sl.ReportError(conf.Attestation.QEMUTDXSimulated, "qemuTDXSimulated", "QEMUTDXSimulated", "simulated_attestation", "")
+	}
+	if conf.Attestation.QEMUSEVSNPSimulated != nil {
+		sl.ReportError(conf.Attestation.QEMUSEVSNPSimulated, "qemuSEVSNPSimulated", "QEMUSEVSNPSimulated", "simulated_attestation", "")
+	}
+}
+
+// validateProfiles checks that all profiles can be merged over the base configuration.
+// The merged configuration is only validated for the selected profile.
+func validateProfiles(sl validator.StructLevel) {
+	conf := sl.Current().Interface().(Config)
+	for _, name := range conf.ProfileNames() {
+		if _, err := conf.WithProfile(name); err != nil {
+			sl.ReportError(conf.Profiles, "Profiles", "Profiles", "profile", name)
+		}
+	}
+}
+
 func validateNodeGroups(sl validator.StructLevel) {
 	nodeGroups := sl.Current().Interface().(Config).NodeGroups
 	defaultControlPlaneGroup, hasDefaultControlPlaneGroup := nodeGroups[constants.DefaultControlPlaneGroupName]
@@ -235,6 +283,229 @@ func validateNodeGroups(sl validator.StructLevel) {
 			sl.ReportError(nodeGroups, "NodeGroups", "NodeGroups", "worker_group_role_mismatch", "")
 		}
 	}
+
+	conf := sl.Current().Interface().(Config)
+	provider := conf.GetProvider()
+	groupNames := make([]string, 0, len(nodeGroups))
+	for groupName := range nodeGroups {
+		groupNames = append(groupNames, groupName)
+	}
+	sort.Strings(groupNames)
+	for _, groupName := range groupNames {
+		group := nodeGroups[groupName]
+		if group.Spot {
+			if group.Role != role.Worker.TFString() {
+				sl.ReportError(group.Spot, "NodeGroups."+groupName+".Spot", "Spot", "spot_control_plane", "")
+			}
+			switch provider {
+			case cloudprovider.AWS, cloudprovider.Azure, cloudprovider.GCP:
+			default:
+				sl.ReportError(group.Spot, "NodeGroups."+groupName+".Spot", "Spot", "node_group_provider_unsupported", provider.String())
+			}
+		}
+		if provider == cloudprovider.QEMU {
+			if len(group.Labels) > 0 {
+				sl.ReportError(group.Labels, "NodeGroups."+groupName+".Labels", "Labels", "node_group_provider_unsupported", provider.String())
+			}
+			if len(group.Taints) > 0 {
+				sl.ReportError(group.Taints, "NodeGroups."+groupName+".Taints", "Taints", "node_group_provider_unsupported", provider.String())
+			}
+		}
+	}
+}
+
+// validateNodeGroup checks the node registration settings and autoscaling bounds of a single node group.
+func validateNodeGroup(sl validator.StructLevel) {
+	group := sl.Current().Interface().(NodeGroup)
+
+	keys := make([]string, 0, len(group.Labels))
+	for key := range group.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := kubernetes.ValidateNodeLabel(key, group.Labels[key]); err != nil {
+			sl.ReportError(group.Labels, "labels", "Labels", "node_label", err.Error())
+		}
+	}
+	for _, taint := range group.Taints {
+		if err := kubernetes.ValidateNodeTaint(taint.ToKubernetes()); err != nil {
+			sl.ReportError(group.Taints, "taints", "Taints", "node_taint", err.Error())
+		}
+	}
+
+	if group.MaxCount > 0 && group.MinCount > group.MaxCount {
+		sl.ReportError(group.MaxCount, "maxCount", "MaxCount", "autoscaling_bounds", "")
+	}
+}
+
+// validateNetworking checks that the pod and service CIDRs describe a network supported by Constellation.
+func validateNetworking(sl validator.StructLevel) {
+	conf := sl.Current().Interface().(Config)
+	provider := conf.GetProvider()
+
+	serviceCIDR := conf.ServiceCIDR
+	if serviceCIDR == "" {
+		serviceCIDR = "10.96.0.0/12" // kubeadm default
+	}
+	podCIDR := conf.PodCIDR
+	if podCIDR == "" {
+		podCIDR = kubernetes.DefaultPodCIDR
+	}
+	serviceCIDRs, serviceErr := kubernetes.ParseCIDRs(serviceCIDR)
+	podCIDRs, podErr := kubernetes.ParseCIDRs(podCIDR)
+	if serviceErr != nil || podErr != nil {
+		// invalid CIDRs are reported by the cidr_list validation
+		return
+	}
+
+	// Nodes only have IPv4 addresses, which have to match the primary IP family of the cluster.
+	if serviceCIDRs.IPv4 == "" || serviceCIDRs.IPv6Primary {
+		sl.ReportError(conf.ServiceCIDR, "serviceCIDR", "ServiceCIDR", "ipv4_primary", "")
+	}
+	if podCIDRs.IPv4 == "" || podCIDRs.IPv6Primary {
+		sl.ReportError(conf.PodCIDR, "podCIDR", "PodCIDR", "ipv4_primary", "")
+	}
+	if serviceCIDRs.DualStack() != podCIDRs.DualStack() {
+		sl.ReportError(conf.PodCIDR, "podCIDR", "PodCIDR", "dual_stack_mismatch", "")
+	}
+
+	if conf.PodCIDR != "" && provider == cloudprovider.GCP {
+		sl.ReportError(conf.PodCIDR, "podCIDR", "PodCIDR", "network_provider_unsupported", provider.String())
+	}
+	// Only the AWS and QEMU Terraform modules assign IPv6 addresses to nodes.
+	if serviceCIDRs.DualStack() {
+		switch provider {
+		case cloudprovider.AWS, cloudprovider.QEMU:
+		default:
+			sl.ReportError(conf.ServiceCIDR, "serviceCIDR", "ServiceCIDR", "dual_stack_provider_unsupported", provider.String())
+		}
+	}
+}
+
+func validateCIDRList(fl validator.FieldLevel) bool {
+	_, err := kubernetes.ParseCIDRs(fl.Field().String())
+	return err == nil
+}
+
+func registerCIDRListError(ut ut.Translator) error {
+	return ut.Add("cidr_list", "{0}: {1} must be a single CIDR or an IPv4 and an IPv6 CIDR separated by a comma", true)
+}
+
+func translateCIDRListError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("cidr_list", fe.Field(), fmt.Sprintf("%q", fe.Value()))
+
+	return t
+}
+
+func registerIPv4PrimaryError(ut ut.Translator) error {
+	return ut.Add("ipv4_primary", "{0}: the first CIDR must be an IPv4 CIDR, IPv6 single-stack and IPv6 primary clusters are not supported because nodes communicate over IPv4", true)
+}
+
+func translateIPv4PrimaryError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("ipv4_primary", fe.Field())
+
+	return t
+}
+
+func registerDualStackMismatchError(ut ut.Translator) error {
+	return ut.Add("dual_stack_mismatch", "{0}: podCIDR and serviceCIDR must either both be dual-stack or both be single-stack", true)
+}
+
+func translateDualStackMismatchError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("dual_stack_mismatch", fe.Field())
+
+	return t
+}
+
+func registerDualStackProviderUnsupportedError(ut ut.Translator) error {
+	return ut.Add("dual_stack_provider_unsupported", "{0}: dual-stack networking is only supported on AWS and QEMU, not on {1}", true)
+}
+
+func translateDualStackProviderUnsupportedError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("dual_stack_provider_unsupported", fe.Field(), fe.Param())
+
+	return t
+}
+
+func registerNetworkProviderUnsupportedError(ut ut.Translator) error {
+	return ut.Add("network_provider_unsupported", "{0}: not supported on {1}", true)
+}
+
+func translateNetworkProviderUnsupportedError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("network_provider_unsupported", fe.Field(), fe.Param())
+
+	return t
+}
+
+func registerSimulatedAttestationError(ut ut.Translator) error {
+	return ut.Add("simulated_attestation", "{0}: simulated attestation provides no security and requires debugCluster to be true", true)
+}
+
+func translateSimulatedAttestationError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("simulated_attestation", fe.Field())
+
+	return t
+}
+
+func registerProfileError(ut ut.Translator) error {
+	return ut.Add("profile", "{0}: profile {1} can't be merged over the base configuration: it must be a mapping of configuration fields and must not change the version", true)
+}
+
+func translateProfileError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("profile", fe.Field(), fe.Param())
+
+	return t
+}
+
+func registerNodeLabelError(ut ut.Translator) error {
+	return ut.Add("node_label", "{0}: {1}", true)
+}
+
+func translateNodeLabelError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("node_label", fe.Field(), fe.Param())
+
+	return t
+}
+
+func registerNodeTaintError(ut ut.Translator) error {
+	return ut.Add("node_taint", "{0}: {1}", true)
+}
+
+func translateNodeTaintError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("node_taint", fe.Field(), fe.Param())
+
+	return t
+}
+
+func registerAutoscalingBoundsError(ut ut.Translator) error {
+	return ut.Add("autoscaling_bounds", "{0}: maxCount must be greater than or equal to minCount", true)
+}
+
+func translateAutoscalingBoundsError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("autoscaling_bounds", fe.Field())
+
+	return t
+}
+
+func registerSpotControlPlaneError(ut ut.Translator) error {
+	return ut.Add("spot_control_plane", "{0}: spot instances can only be used for worker groups", true)
+}
+
+func translateSpotControlPlaneError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("spot_control_plane", fe.Field())
+
+	return t
+}
+
+func registerNodeGroupProviderUnsupportedError(ut ut.Translator) error {
+	return ut.Add("node_group_provider_unsupported", "{0}: not supported on {1}", true)
+}
+
+func translateNodeGroupProviderUnsupportedError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("node_group_provider_unsupported", fe.Field(), fe.Param())
+
+	return t
 }
 
 func translateNoAttestationError(ut ut.Translator, fe validator.FieldError) string {
@@ -361,6 +632,15 @@ func (c *Config) translateMoreThanOneAttestationError(ut ut.Translator, fe valid
 	if c.Attestation.QEMUVTPM != nil {
 		definedAttestations = append(definedAttestations, "QEMUVTPM")
 	}
+	if c.Attestation.QEMUTDX != nil {
+		definedAttestations = append(definedAttestations, "QEMUTDX")
+	}
+	if c.Attestation.QEMUTDXSimulated != nil {
+		definedAttestations = append(definedAttestations, "QEMUTDXSimulated")
+	}
+	if c.Attestation.QEMUSEVSNPSimulated != nil {
+		definedAttestations = append(definedAttestations, "QEMUSEVSNPSimulated")
+	}
 
 	t, _ := ut.T("more_than_one_attestation", fe.Field(), strings.Join(definedAttestations, ", "))
 
@@ -840,3 +1120,179 @@ func validateOpenStackStateDiskField(fl validator.FieldLevel) bool {
 func validateQEMUStateDiskField(_ validator.FieldLevel) bool {
 	return true
 }
+
+// allowedAdmissionPlugins are the admission plugins that can be enabled or disabled by the user.
+// Plugins required for the security of the cluster, like NodeRestriction, are not part of this list.
+var allowedAdmissionPlugins = []string{
+	"AlwaysPullImages",
+	"DefaultIngressClass",
+	"DefaultStorageClass",
+	"DefaultTolerationSeconds",
+	"DenyServiceExternalIPs",
+	"EventRateLimit",
+	"ExtendedResourceToleration",
+	"LimitPodHardAntiAffinityTopology",
+	"LimitRanger",
+	"NamespaceAutoProvision",
+	"NamespaceExists",
+	"PodNodeSelector",
+	"PodTolerationRestriction",
+	"Priority",
+	"ResourceQuota",
+	"RuntimeClass",
+	"StorageObjectInUseProtection",
+}
+
+// allowedEvictionSignals are the kubelet eviction signals that can be configured by the user.
+var allowedEvictionSignals = []string{
+	"memory.available",
+	"nodefs.available",
+	"nodefs.inodesFree",
+	"imagefs.available",
+	"imagefs.inodesFree",
+	"pid.available",
+}
+
+func validateAdmissionPlugin(fl validator.FieldLevel) bool {
+	for _, plugin := range allowedAdmissionPlugins {
+		if fl.Field().String() == plugin {
+			return true
+		}
+	}
+	return false
+}
+
+func registerAdmissionPluginError(ut ut.Translator) error {
+	return ut.Add("admission_plugin", fmt.Sprintf("{0}: admission plugin {1} is not supported. Supported plugins are: %s", strings.Join(allowedAdmissionPlugins, ", ")), true)
+}
+
+func translateAdmissionPluginError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("admission_plugin", fe.Field(), fmt.Sprint(fe.Value()))
+
+	return t
+}
+
+func validateEvictionSignal(fl validator.FieldLevel) bool {
+	for _, signal := range allowedEvictionSignals {
+		if fl.Field().String() == signal {
+			return true
+		}
+	}
+	return false
+}
+
+func registerEvictionSignalError(ut ut.Translator) error {
+	return ut.Add("eviction_signal", fmt.Sprintf("{0}: eviction signal {1} is not supported. Supported signals are: %s", strings.Join(allowedEvictionSignals, ", ")), true)
+}
+
+func translateEvictionSignalError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("eviction_signal", fe.Field(), fmt.Sprint(fe.Value()))
+
+	return t
+}
+
+func validateDuration(fl validator.FieldLevel) bool {
+	_, err := time.ParseDuration(fl.Field().String())
+	return err == nil
+}
+
+func registerDurationError(ut ut.Translator) error {
+	return ut.Add("duration", "{0}: {1} is not a valid duration (e.g. 1m30s)", true)
+}
+
+func translateDurationError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("duration", fe.Field(), fmt.Sprint(fe.Value()))
+
+	return t
+}
+
+// validateAuditPolicy checks that the field holds a YAML encoded audit.k8s.io/v1 Policy.
+func validateAuditPolicy(fl validator.FieldLevel) bool {
+	var policy auditv1.Policy
+	if err := yaml.UnmarshalStrict([]byte(fl.Field().String()), &policy); err != nil {
+		return false
+	}
+	return policy.APIVersion == auditv1.SchemeGroupVersion.String() && policy.Kind == "Policy" && len(policy.Rules) > 0
+}
+
+func registerAuditPolicyError(ut ut.Translator) error {
+	return ut.Add("audit_policy", "{0}: must be a YAML encoded audit.k8s.io/v1 Policy with at least one rule", true)
+}
+
+func translateAuditPolicyError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("audit_policy", fe.Field())
+
+	return t
+}
+
+func validateAPIServerOverrides(sl validator.StructLevel) {
+	overrides := sl.Current().Interface().(APIServerOverrides)
+	for _, enabled := range overrides.EnableAdmissionPlugins {
+		for _, disabled := range overrides.DisableAdmissionPlugins {
+			if enabled == disabled {
+				sl.ReportError(overrides.DisableAdmissionPlugins, "disableAdmissionPlugins", "DisableAdmissionPlugins", "admission_plugin_conflict", enabled)
+			}
+		}
+	}
+}
+
+func registerAdmissionPluginConflictError(ut ut.Translator) error {
+	return ut.Add("admission_plugin_conflict", "{0}: admission plugin {1} can't be enabled and disabled at the same time", true)
+}
+
+func translateAdmissionPluginConflictError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("admission_plugin_conflict", fe.Field(), fe.Param())
+
+	return t
+}
+
+func validateStateBackend(sl validator.StructLevel) {
+	backend := sl.Current().Interface().(StateBackendConfig)
+	if backend.S3 != nil && backend.Kubernetes != nil {
+		sl.ReportError(backend.Kubernetes, "kubernetes", "Kubernetes", "state_backend", "s3")
+	}
+}
+
+func registerStateBackendError(ut ut.Translator) error {
+	return ut.Add("state_backend", "{0}: only one state backend can be configured, but {1} is configured as well", true)
+}
+
+func translateStateBackendError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("state_backend", fe.Field(), fe.Param())
+
+	return t
+}
+
+func validateKubeletOverrides(sl validator.StructLevel) {
+	overrides := sl.Current().Interface().(KubeletOverrides)
+	for signal := range overrides.EvictionSoft {
+		if _, ok := overrides.EvictionSoftGracePeriod[signal]; !ok {
+			sl.ReportError(overrides.EvictionSoftGracePeriod, "evictionSoftGracePeriod", "EvictionSoftGracePeriod", "missing_grace_period", signal)
+		}
+	}
+}
+
+func registerMissingGracePeriodError(ut ut.Translator) error {
+	return ut.Add("missing_grace_period", "{0}: soft eviction threshold {1} requires a grace period", true)
+}
+
+func translateMissingGracePeriodError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("missing_grace_period", fe.Field(), fe.Param())
+
+	return t
+}
+
+func validateDiskEncryptionProfile(fl validator.FieldLevel) bool {
+	_, err := profile.Get(fl.Field().String())
+	return err == nil
+}
+
+func registerDiskEncryptionProfileError(ut ut.Translator) error {
+	return ut.Add("disk_encryption_profile", "{0}: unknown disk encryption profile {1}, supported profiles are: {2}", true)
+}
+
+func translateDiskEncryptionProfileError(ut ut.Translator, fe validator.FieldError) string {
+	t, _ := ut.T("disk_encryption_profile", fe.Field(), fmt.Sprintf("%q", fe.Value()), strings.Join(profile.Names(), ", "))
+
+	return t
+}
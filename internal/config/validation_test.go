@@ -9,8 +9,11 @@ package config
 import (
 	"testing"
 
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestValidateVersionCompatibilityHelper checks that basic version and image short paths are correctly validated.
@@ -84,3 +87,180 @@ func TestValidateMicroserviceVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateKubernetesOverrides(t *testing.T) {
+	validAuditPolicy := `apiVersion: audit.k8s.io/v1
+kind: Policy
+rules:
+- level: Metadata
+`
+
+	testCases := map[string]struct {
+		overrides    *KubernetesOverrides
+		wantErrCount int
+	}{
+		"no overrides": {},
+		"valid overrides": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					EnableAdmissionPlugins:  []string{"AlwaysPullImages"},
+					DisableAdmissionPlugins: []string{"DefaultStorageClass"},
+					OIDC: &OIDCConfig{
+						IssuerURL:     "https://issuer.example.com",
+						ClientID:      "constellation",
+						UsernameClaim: "email",
+					},
+					AuditPolicy: validAuditPolicy,
+				},
+				Kubelet: &KubeletOverrides{
+					EvictionHard:            map[string]string{"memory.available": "100Mi"},
+					EvictionSoft:            map[string]string{"nodefs.available": "15%"},
+					EvictionSoftGracePeriod: map[string]string{"nodefs.available": "1m30s"},
+				},
+			},
+		},
+		"admission plugin not allowed": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					DisableAdmissionPlugins: []string{"NodeRestriction"},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"admission plugin enabled and disabled": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					EnableAdmissionPlugins:  []string{"AlwaysPullImages"},
+					DisableAdmissionPlugins: []string{"AlwaysPullImages"},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"oidc issuer without https": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					OIDC: &OIDCConfig{
+						IssuerURL: "http://issuer.example.com",
+						ClientID:  "constellation",
+					},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"oidc without client ID": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					OIDC: &OIDCConfig{
+						IssuerURL: "https://issuer.example.com",
+					},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"invalid audit policy": {
+			overrides: &KubernetesOverrides{
+				APIServer: &APIServerOverrides{
+					AuditPolicy: "kind: Policy",
+				},
+			},
+			wantErrCount: 1,
+		},
+		"unknown eviction signal": {
+			overrides: &KubernetesOverrides{
+				Kubelet: &KubeletOverrides{
+					EvictionHard: map[string]string{"cpu.available": "10%"},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"empty eviction threshold": {
+			overrides: &KubernetesOverrides{
+				Kubelet: &KubeletOverrides{
+					EvictionHard: map[string]string{"memory.available": ""},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"soft eviction without grace period": {
+			overrides: &KubernetesOverrides{
+				Kubelet: &KubeletOverrides{
+					EvictionSoft: map[string]string{"memory.available": "200Mi"},
+				},
+			},
+			wantErrCount: 1,
+		},
+		"invalid grace period": {
+			overrides: &KubernetesOverrides{
+				Kubelet: &KubeletOverrides{
+					EvictionSoft:            map[string]string{"memory.available": "200Mi"},
+					EvictionSoftGracePeriod: map[string]string{"memory.available": "90"},
+				},
+			},
+			wantErrCount: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cnf := Default()
+			cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+			cnf.Image = constants.BinaryVersion().String()
+			modifyConfigForAzureToPassValidate(cnf)
+			cnf.KubernetesOverrides = tc.overrides
+
+			err := cnf.Validate(false)
+			if tc.wantErrCount == 0 {
+				assert.NoError(err)
+				return
+			}
+			var valErr *ValidationError
+			require.ErrorAs(err, &valErr)
+			assert.Equalf(tc.wantErrCount, valErr.messagesCount(), "Got unexpected error count: %d: %s", valErr.messagesCount(), valErr.LongMessage())
+		})
+	}
+}
+
+func TestValidateDiskEncryptionProfile(t *testing.T) {
+	testCases := map[string]struct {
+		diskEncryptionProfile string
+		wantErr               bool
+	}{
+		"no profile": {},
+		"default profile": {
+			diskEncryptionProfile: "aes-xts-hmac-sha256",
+		},
+		"profile without integrity": {
+			diskEncryptionProfile: "aes-xts",
+		},
+		"unknown profile": {
+			diskEncryptionProfile: "aes-cbc",
+			wantErr:               true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cnf := Default()
+			cnf.RemoveProviderAndAttestationExcept(cloudprovider.Azure)
+			cnf.Image = constants.BinaryVersion().String()
+			modifyConfigForAzureToPassValidate(cnf)
+			cnf.DiskEncryptionProfile = tc.diskEncryptionProfile
+
+			err := cnf.Validate(false)
+			if !tc.wantErr {
+				assert.NoError(err)
+				return
+			}
+			var valErr *ValidationError
+			require.ErrorAs(err, &valErr)
+			assert.Equal(1, valErr.messagesCount())
+			assert.Contains(valErr.LongMessage(), "aes-xts-512")
+		})
+	}
+}
@@ -74,6 +74,8 @@ const (
 
 	// StateFilename filename that contains the entire state of the Constellation cluster.
 	StateFilename = "constellation-state.yaml"
+	// MigratedStateFilename filename the state file is moved to after it was migrated to a remote state backend.
+	MigratedStateFilename = "constellation-state.yaml.migrated"
 	// ConfigFilename filename of Constellation config file.
 	ConfigFilename = "constellation-conf.yaml"
 	// LicenseFilename filename of Constellation license file.
@@ -82,6 +84,8 @@ const (
 	AdminConfFilename = "constellation-admin.conf"
 	// MasterSecretFilename filename of Constellation mastersecret.
 	MasterSecretFilename = "constellation-mastersecret.json"
+	// RestoredSecretsFilename filename of the secrets restored from a backup, consumed by the next cluster initialization.
+	RestoredSecretsFilename = "constellation-restore.json"
 	// TerraformWorkingDir is the directory name for the TerraformClient workspace.
 	TerraformWorkingDir = "constellation-terraform"
 	// TerraformIAMWorkingDir is the directory name for the Terraform IAM Client workspace.
@@ -132,10 +136,20 @@ const (
 	CertCacheAskKey = "ask"
 	// CertCacheArkKey is the name of the key holding the ARK certificate in the SEV-SNP certificate cache.
 	CertCacheArkKey = "ark"
+	// TDXCollateralCacheConfigMapName is the name of the configMap holding the Intel TDX collateral cache in the join service.
+	TDXCollateralCacheConfigMapName = "tdx-collateral-cache"
 	// NodeVersionResourceName resource name used for NodeVersion in constellation-operator and CLI.
 	NodeVersionResourceName = "constellation-version"
 	// NodeKubernetesComponentsAnnotationKey is the name of the annotation holding the reference to the ConfigMap listing all K8s components.
 	NodeKubernetesComponentsAnnotationKey = "constellation.edgeless.systems/kubernetes-components"
+	// NodeStateDiskKeyVersionAnnotationKey is the name of the annotation requesting a state disk key version for a single node.
+	NodeStateDiskKeyVersionAnnotationKey = "constellation.edgeless.systems/state-disk-key-version"
+	// JoinApprovalAnnotationKey is the name of the annotation on a JoiningNode recording the manual approval of the join.
+	JoinApprovalAnnotationKey = "constellation.edgeless.systems/join-approval"
+	// JoinApprovalProviderIDAnnotationKey is the name of the annotation on a JoiningNode recording the provider ID of the instance requesting approval.
+	JoinApprovalProviderIDAnnotationKey = "constellation.edgeless.systems/join-approval-provider-id"
+	// JoinApprovalPeerIPAnnotationKey is the name of the annotation on a JoiningNode recording the IP address of the instance requesting approval.
+	JoinApprovalPeerIPAnnotationKey = "constellation.edgeless.systems/join-approval-peer-ip"
 	// JoiningNodesConfigMapName is the name of the configMap holding the joining nodes with the components hashes the node-operator should annotate the nodes with.
 	JoiningNodesConfigMapName = "joining-nodes"
 
@@ -159,6 +173,9 @@ const (
 	// displayed in Constellation CLI. Any non-empty value, e.g., CONSTELL_NO_SPINNER=1,
 	// can be used to disable the spinner.
 	EnvVarNoSpinner = EnvVarPrefix + "NO_SPINNER"
+	// EnvVarStateEncryptionKey is environment variable holding the hex-encoded 32 byte key
+	// used to encrypt the state file if stateBackend.encrypt is set.
+	EnvVarStateEncryptionKey = EnvVarPrefix + "STATE_ENCRYPTION_KEY"
 	// MiniConstellationUID is a sentinel value for the UID of a mini constellation.
 	MiniConstellationUID = "mini"
 	// MiniConstellationName is a sentinel value for the name of a mini constellation.
@@ -214,12 +231,24 @@ const (
 
 	// KubernetesJoinTokenTTL time to live for Kubernetes join token.
 	KubernetesJoinTokenTTL = 15 * time.Minute
+	// JoinApprovalTimeout is the time an administrator has to approve the join of a node, if manual approval is required.
+	JoinApprovalTimeout = 24 * time.Hour
 	// ConstellationNamespace namespace to deploy Constellation components into.
 	ConstellationNamespace = "kube-system"
 	// JoinConfigMap k8s config map with node join config.
 	JoinConfigMap = "join-config"
 	// InternalConfigMap k8s config map with internal Constellation config.
 	InternalConfigMap = "internal-config"
+	// AuditPolicyKey key in the internal config map with the user supplied audit policy of the API server.
+	AuditPolicyKey = "audit-policy.yaml"
+	// KubeletConfigPatchKey key in the internal config map with the user supplied patch of the kubelet configuration.
+	KubeletConfigPatchKey = "kubelet-config-patch.json"
+	// StateDiskKeyVersionKey key in the internal config map with the state disk key version all nodes should use.
+	StateDiskKeyVersionKey = "state-disk-key-version"
+	// DiskEncryptionProfileKey key in the internal config map with the name of the encryption profile of the state disks.
+	DiskEncryptionProfileKey = "disk-encryption-profile"
+	// JoinPolicyKey key in the internal config map with the policy restricting which nodes may join the cluster.
+	JoinPolicyKey = "join-policy.json"
 	// KubeadmConfigMap k8s config map with kubeadm config
 	// (holds ClusterConfiguration).
 	KubeadmConfigMap = "kubeadm-config"
@@ -285,6 +314,14 @@ b92PDCpM7FZAINQF88s1TZS/HmRXYk62UJ4eqPduvUnJmXhNikhLbMi6fw==
 	MasterSecretURIKey = "masterSecret"
 	// MasterSecretSaltURIKey is the key used for the master secret salt in Terraform Constellation cluster import URIs.
 	MasterSecretSaltURIKey = "masterSecretSalt"
+	// ConstellationIAMURIScheme is the scheme used in Terraform Constellation IAM import URIs.
+	ConstellationIAMURIScheme = "constellation-iam"
+	// ConstellationInfrastructureURIScheme is the scheme used in Terraform Constellation infrastructure import URIs.
+	ConstellationInfrastructureURIScheme = "constellation-infrastructure"
+	// CSPURIKey is the key used for the cloud service provider in Terraform Constellation IAM and infrastructure import URIs.
+	CSPURIKey = "csp"
+	// WorkspaceURIKey is the key used for the Terraform workspace directory in Terraform Constellation IAM and infrastructure import URIs.
+	WorkspaceURIKey = "workspace"
 )
 
 // BinaryVersion returns the version of this Binary.
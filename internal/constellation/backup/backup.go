@@ -0,0 +1,297 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package backup creates and opens encrypted backup bundles of a Constellation cluster.
+
+A bundle is a gzip compressed tar archive, encrypted using AES-256-GCM.
+The encryption key is derived from a user-supplied key and a random salt using HKDF.
+The sealed bundle has the following layout:
+
+	magic (8 bytes) | salt (32 bytes) | nonce (12 bytes) | ciphertext
+*/
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SecretsDir holds the secrets of the cluster, one file per secret in a folder per namespace.
+	SecretsDir = "secrets"
+	// ResourcesDir holds the CRDs and CRs of the cluster.
+	ResourcesDir = "resources"
+	// EtcdSnapshotFilename is the name of the JSON snapshot of the etcd keyspace.
+	EtcdSnapshotFilename = "etcd-keyspace.json"
+
+	// MinKeyLength is the minimum length of the user-supplied key in bytes.
+	MinKeyLength = 16
+
+	saltLength = 32
+	keyInfo    = "constellation-backup"
+)
+
+var magic = []byte("CBACKUP1")
+
+// Bundle is an in-memory backup of a Constellation cluster.
+type Bundle struct {
+	fs afero.Fs
+}
+
+// New returns an empty bundle.
+func New() *Bundle {
+	return &Bundle{fs: afero.NewMemMapFs()}
+}
+
+// FileHandler returns a file handler to read and write the files of the bundle.
+func (b *Bundle) FileHandler() file.Handler {
+	return file.NewHandler(b.fs)
+}
+
+// Seal packs all files of the bundle into an archive and encrypts it with the given key.
+func (b *Bundle) Seal(key []byte) ([]byte, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("key must be at least %d bytes long, got %d", MinKeyLength, len(key))
+	}
+	archive, err := b.archive()
+	if err != nil {
+		return nil, fmt.Errorf("archiving bundle: %w", err)
+	}
+
+	salt, err := crypto.GenerateRandomBytes(saltLength)
+	if err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := crypto.GenerateRandomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	header := append(append(append([]byte{}, magic...), salt...), nonce...)
+	// authenticate the header, so the salt can't be swapped
+	return aead.Seal(header, nonce, archive, header), nil
+}
+
+// Open decrypts a sealed bundle using the given key.
+func Open(sealed, key []byte) (*Bundle, error) {
+	if !bytes.HasPrefix(sealed, magic) {
+		return nil, errors.New("not a Constellation backup bundle")
+	}
+	if len(sealed) < len(magic)+saltLength {
+		return nil, errors.New("bundle is truncated")
+	}
+	salt := sealed[len(magic) : len(magic)+saltLength]
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerLength := len(magic) + saltLength + aead.NonceSize()
+	if len(sealed) < headerLength {
+		return nil, errors.New("bundle is truncated")
+	}
+	header := sealed[:headerLength]
+	nonce := sealed[len(magic)+saltLength : headerLength]
+
+	archive, err := aead.Open(nil, nonce, sealed[headerLength:], header)
+	if err != nil {
+		return nil, errors.New("decrypting bundle: wrong key or corrupted bundle")
+	}
+
+	b := New()
+	if err := b.extract(archive); err != nil {
+		return nil, fmt.Errorf("extracting bundle: %w", err)
+	}
+	return b, nil
+}
+
+// Extract writes all files of the bundle to dir.
+// Existing files are not overwritten.
+func (b *Bundle) Extract(dst file.Handler, dir string) error {
+	return afero.Walk(b.fs, "", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		content, err := afero.ReadFile(b.fs, path)
+		if err != nil {
+			return err
+		}
+		return dst.Write(filepath.Join(dir, path), content, file.OptMkdirAll)
+	})
+}
+
+// Secrets returns all secrets stored in the bundle.
+func (b *Bundle) Secrets() ([]corev1.Secret, error) {
+	var secrets []corev1.Secret
+	err := b.walkYAML(SecretsDir, func(raw []byte) error {
+		var secret corev1.Secret
+		if err := yaml.Unmarshal(raw, &secret); err != nil {
+			return err
+		}
+		secrets = append(secrets, secret)
+		return nil
+	})
+	return secrets, err
+}
+
+// CustomResources returns all custom resources stored in the bundle.
+// CRDs are not returned.
+func (b *Bundle) CustomResources() ([]unstructured.Unstructured, error) {
+	var crs []unstructured.Unstructured
+	err := b.walkYAML(ResourcesDir, func(raw []byte) error {
+		var cr unstructured.Unstructured
+		if err := yaml.Unmarshal(raw, &cr.Object); err != nil {
+			return err
+		}
+		if cr.GetKind() == "CustomResourceDefinition" {
+			return nil
+		}
+		crs = append(crs, cr)
+		return nil
+	})
+	return crs, err
+}
+
+// EtcdSnapshot returns the etcd snapshot stored in the bundle.
+// It returns nil if the bundle holds no snapshot.
+func (b *Bundle) EtcdSnapshot() ([]byte, error) {
+	snapshot, err := afero.ReadFile(b.fs, EtcdSnapshotFilename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// walkYAML calls fn for the content of every YAML file in dir.
+func (b *Bundle) walkYAML(dir string, fn func(raw []byte) error) error {
+	if _, err := b.fs.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return afero.Walk(b.fs, dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		raw, err := afero.ReadFile(b.fs, path)
+		if err != nil {
+			return err
+		}
+		if err := fn(raw); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+func (b *Bundle) archive() ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err := afero.Walk(b.fs, "", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		content, err := afero.ReadFile(b.fs, path)
+		if err != nil {
+			return err
+		}
+		if err := tarWriter.WriteHeader(&tar.Header{
+			Name: filepath.ToSlash(path),
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}); err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(content)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *Bundle) extract(archive []byte) error {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+
+	fileHandler := b.FileHandler()
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid file name %q", header.Name)
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return err
+		}
+		if err := fileHandler.Write(name, content, file.OptMkdirAll); err != nil {
+			return err
+		}
+	}
+}
+
+func newAEAD(key, salt []byte) (cipher.AEAD, error) {
+	derivedKey, err := crypto.DeriveKey(key, salt, []byte(keyInfo), 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
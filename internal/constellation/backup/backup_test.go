@@ -0,0 +1,157 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backup
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, 32)
+
+	testCases := map[string]struct {
+		sealKey     []byte
+		openKey     []byte
+		tamper      func([]byte) []byte
+		wantSealErr bool
+		wantOpenErr bool
+	}{
+		"success": {
+			sealKey: key,
+			openKey: key,
+		},
+		"key too short": {
+			sealKey:     []byte("short"),
+			wantSealErr: true,
+		},
+		"wrong key": {
+			sealKey:     key,
+			openKey:     bytes.Repeat([]byte{0x02}, 32),
+			wantOpenErr: true,
+		},
+		"tampered ciphertext": {
+			sealKey: key,
+			openKey: key,
+			tamper: func(b []byte) []byte {
+				b[len(b)-1] ^= 0xFF
+				return b
+			},
+			wantOpenErr: true,
+		},
+		"tampered salt": {
+			sealKey: key,
+			openKey: key,
+			tamper: func(b []byte) []byte {
+				b[len(magic)] ^= 0xFF
+				return b
+			},
+			wantOpenErr: true,
+		},
+		"not a bundle": {
+			sealKey: key,
+			openKey: key,
+			tamper: func([]byte) []byte {
+				return []byte("not a bundle")
+			},
+			wantOpenErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			bundle := New()
+			fileHandler := bundle.FileHandler()
+			require.NoError(fileHandler.Write("state.yaml", []byte("version: v1")))
+			require.NoError(fileHandler.Write(filepath.Join(SecretsDir, "default", "app.yaml"), []byte("data: {}"), file.OptMkdirAll))
+
+			sealed, err := bundle.Seal(tc.sealKey)
+			if tc.wantSealErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			if tc.tamper != nil {
+				sealed = tc.tamper(sealed)
+			}
+
+			opened, err := Open(sealed, tc.openKey)
+			if tc.wantOpenErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			openedHandler := opened.FileHandler()
+			content, err := openedHandler.Read("state.yaml")
+			require.NoError(err)
+			assert.Equal("version: v1", string(content))
+			content, err = openedHandler.Read(filepath.Join(SecretsDir, "default", "app.yaml"))
+			require.NoError(err)
+			assert.Equal("data: {}", string(content))
+		})
+	}
+}
+
+func TestResources(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	bundle := New()
+	fileHandler := bundle.FileHandler()
+	require.NoError(fileHandler.Write(filepath.Join(SecretsDir, "app", "secret.yaml"),
+		[]byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: secret\n  namespace: app\ndata:\n  key: dmFsdWU=\n"), file.OptMkdirAll))
+	require.NoError(fileHandler.Write(filepath.Join(ResourcesDir, "backups", "crds", "certificates.cert-manager.io.yaml"),
+		[]byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: certificates.cert-manager.io\n"), file.OptMkdirAll))
+	require.NoError(fileHandler.Write(filepath.Join(ResourcesDir, "backups", "cert-manager.io", "v1", "app", "Certificate", "cert.yaml"),
+		[]byte("apiVersion: cert-manager.io/v1\nkind: Certificate\nmetadata:\n  name: cert\n  namespace: app\n"), file.OptMkdirAll))
+	require.NoError(fileHandler.Write(EtcdSnapshotFilename, []byte(`{"kvs":[]}`)))
+
+	secrets, err := bundle.Secrets()
+	require.NoError(err)
+	require.Len(secrets, 1)
+	assert.Equal("secret", secrets[0].Name)
+	assert.Equal([]byte("value"), secrets[0].Data["key"])
+
+	crs, err := bundle.CustomResources()
+	require.NoError(err)
+	require.Len(crs, 1)
+	assert.Equal("Certificate", crs[0].GetKind())
+	assert.Equal("cert", crs[0].GetName())
+
+	etcdSnapshot, err := bundle.EtcdSnapshot()
+	require.NoError(err)
+	assert.Equal(`{"kvs":[]}`, string(etcdSnapshot))
+
+	extracted := file.NewHandler(afero.NewMemMapFs())
+	require.NoError(bundle.Extract(extracted, "out"))
+	content, err := extracted.Read(filepath.Join("out", ResourcesDir, "backups", "cert-manager.io", "v1", "app", "Certificate", "cert.yaml"))
+	require.NoError(err)
+	assert.Contains(string(content), "kind: Certificate")
+
+	empty := New()
+	secrets, err = empty.Secrets()
+	assert.NoError(err)
+	assert.Empty(secrets)
+	etcdSnapshot, err = empty.EtcdSnapshot()
+	assert.NoError(err)
+	assert.Nil(etcdSnapshot)
+}
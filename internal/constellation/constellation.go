@@ -1,14 +0,0 @@
-/*
-Copyright (c) Edgeless Systems GmbH
-
-SPDX-License-Identifier: AGPL-3.0-only
-*/
-
-/*
-This package capsulates the Constellation API, which is used to manage and interact
-with Constellation clusters.
-
-It should be used as the main entry point for any code that needs to interact with
-said entities, with priority over using other internal packages directly.
-*/
-package constellation
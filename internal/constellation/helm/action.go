@@ -31,6 +31,7 @@ type applyAction interface {
 	SaveChart(chartsDir string, fileHandler file.Handler) error
 	ReleaseName() string
 	IsAtomic() bool
+	Change() (ReleaseChange, error)
 }
 
 // newActionConfig creates a new action configuration for helm actions.
@@ -106,6 +107,11 @@ func (a *installAction) apply(ctx context.Context) error {
 	return err
 }
 
+// Change returns the change installing the release applies to the cluster.
+func (a *installAction) Change() (ReleaseChange, error) {
+	return newReleaseChange(ReleaseActionInstall, a.release, "", nil)
+}
+
 // ReleaseName returns the release name.
 func (a *installAction) ReleaseName() string {
 	return a.release.releaseName
@@ -125,13 +131,18 @@ func newHelmUpgradeAction(config *action.Configuration, timeout time.Duration) *
 	return action
 }
 
+func newHelmGetAction(config *action.Configuration) *action.Get {
+	return action.NewGet(config)
+}
+
 // upgradeAction is an action that upgrades a helm chart.
 type upgradeAction struct {
-	preUpgrade  func(context.Context) error
-	postUpgrade func(context.Context) error
-	release     release
-	helmAction  *action.Upgrade
-	log         debugLog
+	preUpgrade    func(context.Context) error
+	postUpgrade   func(context.Context) error
+	release       release
+	helmAction    *action.Upgrade
+	helmGetAction *action.Get
+	log           debugLog
 }
 
 // Apply installs the chart.
@@ -162,6 +173,23 @@ func (a *upgradeAction) apply(ctx context.Context) error {
 	return err
 }
 
+// Change returns the change upgrading the release applies to the cluster,
+// by comparing the new release with the currently deployed one.
+func (a *upgradeAction) Change() (ReleaseChange, error) {
+	current, err := a.helmGetAction.Run(a.release.releaseName)
+	if err != nil {
+		return ReleaseChange{}, fmt.Errorf("getting current release %s: %w", a.release.releaseName, err)
+	}
+	if current.Chart == nil || current.Chart.Metadata == nil {
+		return ReleaseChange{}, fmt.Errorf("received invalid release %s", a.release.releaseName)
+	}
+	currentValues := current.Config
+	if currentValues == nil {
+		currentValues = map[string]any{}
+	}
+	return newReleaseChange(ReleaseActionUpgrade, a.release, current.Chart.Metadata.Version, currentValues)
+}
+
 // ReleaseName returns the release name.
 func (a *upgradeAction) ReleaseName() string {
 	return a.release.releaseName
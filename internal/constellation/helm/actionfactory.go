@@ -144,7 +144,12 @@ func (a actionFactory) newInstall(release release, timeout time.Duration) *insta
 }
 
 func (a actionFactory) newUpgrade(release release, timeout time.Duration) *upgradeAction {
-	action := &upgradeAction{helmAction: newHelmUpgradeAction(a.cfg, timeout), release: release, log: a.log}
+	action := &upgradeAction{
+		helmAction:    newHelmUpgradeAction(a.cfg, timeout),
+		helmGetAction: newHelmGetAction(a.cfg),
+		release:       release,
+		log:           a.log,
+	}
 	if release.releaseName == constellationOperatorsInfo.releaseName {
 		action.preUpgrade = func(ctx context.Context) error {
 			if err := a.updateCRDs(ctx, release.chart); err != nil {
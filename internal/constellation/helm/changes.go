@@ -0,0 +1,107 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package helm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rogpeppe/go-internal/diff"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ReleaseActionInstall signals that a release will be installed.
+	ReleaseActionInstall = "install"
+	// ReleaseActionUpgrade signals that an existing release will be upgraded.
+	ReleaseActionUpgrade = "upgrade"
+)
+
+// redactedValue replaces sensitive Helm values in a [ReleaseChange].
+const redactedValue = "<redacted>"
+
+// sensitiveValueKeys are keys of Helm values holding secrets, which must never be printed.
+var sensitiveValueKeys = map[string]struct{}{
+	"masterSecret": {},
+	"salt":         {},
+	"secretData":   {},
+}
+
+// ReleaseChange describes how applying a Helm release changes the cluster.
+type ReleaseChange struct {
+	// ReleaseName is the name of the Helm release.
+	ReleaseName string `json:"releaseName"`
+	// Action is either [ReleaseActionInstall] or [ReleaseActionUpgrade].
+	Action string `json:"action"`
+	// CurrentVersion is the chart version currently deployed. Empty for new releases.
+	CurrentVersion string `json:"currentVersion,omitempty"`
+	// TargetVersion is the chart version that will be deployed.
+	TargetVersion string `json:"targetVersion"`
+	// ValuesDiff is a unified diff of the release's values. Sensitive values are redacted.
+	ValuesDiff string `json:"valuesDiff,omitempty"`
+}
+
+// newReleaseChange compares the currently deployed values of a release with the values of the new release.
+// currentValues is nil if the release is not deployed yet.
+func newReleaseChange(action string, release release, currentVersion string, currentValues map[string]any) (ReleaseChange, error) {
+	var currentYAML []byte
+	if currentValues != nil {
+		var err error
+		currentYAML, err = yaml.Marshal(redactValues(currentValues))
+		if err != nil {
+			return ReleaseChange{}, fmt.Errorf("marshalling current values: %w", err)
+		}
+	}
+	newYAML, err := yaml.Marshal(redactValues(release.values))
+	if err != nil {
+		return ReleaseChange{}, fmt.Errorf("marshalling new values: %w", err)
+	}
+
+	change := ReleaseChange{
+		ReleaseName:    release.releaseName,
+		Action:         action,
+		CurrentVersion: currentVersion,
+		TargetVersion:  release.chart.Metadata.Version,
+	}
+	if !bytes.Equal(currentYAML, newYAML) {
+		change.ValuesDiff = string(diff.Diff("current", currentYAML, "new", newYAML))
+	}
+	return change, nil
+}
+
+// IsNoOp returns true if the release is upgraded to the same version with the same values.
+func (c ReleaseChange) IsNoOp() bool {
+	return c.Action == ReleaseActionUpgrade && c.CurrentVersion == c.TargetVersion && c.ValuesDiff == ""
+}
+
+// redactValues returns a copy of values with all sensitive values replaced.
+func redactValues(values map[string]any) map[string]any {
+	redacted := make(map[string]any, len(values))
+	for k, v := range values {
+		if _, ok := sensitiveValueKeys[k]; ok {
+			redacted[k] = redactedValue
+			continue
+		}
+		redacted[k] = redactValue(v)
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactValues(v)
+	case []any:
+		redacted := make([]any, len(v))
+		for i, elem := range v {
+			redacted[i] = redactValue(elem)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
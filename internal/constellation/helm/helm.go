@@ -85,6 +85,7 @@ type Options struct {
 	AttestationVariant  variant.Variant
 	Conformance         bool
 	DeployCSIDriver     bool
+	PodCIDR             string
 	AllowDestructive    bool
 	Force               bool
 	K8sVersion          versions.ValidK8sVersion
@@ -115,13 +116,14 @@ func (h Client) loadReleases(
 ) ([]release, error) {
 	helmLoader := newLoader(csp, attestationVariant, k8sVersion, stateFile, h.cliVersion)
 	h.log.Debugf("Created new Helm loader")
-	return helmLoader.loadReleases(flags.Conformance, flags.DeployCSIDriver, flags.HelmWaitMode, secret, serviceAccURI, openStackCfg)
+	return helmLoader.loadReleases(flags.Conformance, flags.DeployCSIDriver, flags.PodCIDR, flags.HelmWaitMode, secret, serviceAccURI, openStackCfg)
 }
 
 // Applier runs the Helm actions.
 type Applier interface {
 	Apply(ctx context.Context) error
 	SaveCharts(chartsDir string, fileHandler file.Handler) error
+	Changes() ([]ReleaseChange, error)
 }
 
 // ChartApplyExecutor is a Helm action executor that applies all actions.
@@ -151,6 +153,25 @@ func (c ChartApplyExecutor) SaveCharts(chartsDir string, fileHandler file.Handle
 	return nil
 }
 
+// Changes returns the changes applying the charts would make to the cluster.
+// Upgrades that neither change the chart version nor the values are omitted.
+// Nothing is applied to the cluster.
+func (c ChartApplyExecutor) Changes() ([]ReleaseChange, error) {
+	var changes []ReleaseChange
+	for _, action := range c.actions {
+		change, err := action.Change()
+		if err != nil {
+			return nil, fmt.Errorf("getting changes for %s: %w", action.ReleaseName(), err)
+		}
+		if change.IsNoOp() {
+			c.log.Debugf("Release %q is up to date", action.ReleaseName())
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
 // mergeMaps returns a new map that is the merger of it's inputs.
 // Key collisions are resolved by taking the value of the second argument (map b).
 // Taken from: https://github.com/helm/helm/blob/dbc6d8e20fe1d58d50e6ed30f09a04a77e4c68db/pkg/cli/values/options.go#L91-L108.
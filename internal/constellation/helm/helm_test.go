@@ -8,6 +8,7 @@ package helm
 
 import (
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
@@ -20,7 +21,13 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/versions"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
 func TestMergeMaps(t *testing.T) {
@@ -232,6 +239,112 @@ func TestHelmApply(t *testing.T) {
 	}
 }
 
+func TestChanges(t *testing.T) {
+	newRelease := func(name, version string, values map[string]any) release {
+		return release{
+			chart:       &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version}},
+			values:      values,
+			releaseName: name,
+		}
+	}
+
+	testCases := map[string]struct {
+		deployed    *helmrelease.Release
+		install     bool
+		release     release
+		wantChanges []ReleaseChange
+		wantErr     bool
+	}{
+		"install": {
+			install: true,
+			release: newRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+			wantChanges: []ReleaseChange{{
+				ReleaseName:   "foo",
+				Action:        ReleaseActionInstall,
+				TargetVersion: "1.0.0",
+				ValuesDiff:    "diff current new\n--- current\n+++ new\n@@ -0,0 +1,1 @@\n+replicas: 1\n",
+			}},
+		},
+		"upgrade with new version": {
+			deployed: deployedRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+			release:  newRelease("foo", "1.1.0", map[string]any{"replicas": 1}),
+			wantChanges: []ReleaseChange{{
+				ReleaseName:    "foo",
+				Action:         ReleaseActionUpgrade,
+				CurrentVersion: "1.0.0",
+				TargetVersion:  "1.1.0",
+			}},
+		},
+		"upgrade with new values": {
+			deployed: deployedRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+			release:  newRelease("foo", "1.0.0", map[string]any{"replicas": 2}),
+			wantChanges: []ReleaseChange{{
+				ReleaseName:    "foo",
+				Action:         ReleaseActionUpgrade,
+				CurrentVersion: "1.0.0",
+				TargetVersion:  "1.0.0",
+				ValuesDiff:     "diff current new\n--- current\n+++ new\n@@ -1,1 +1,1 @@\n-replicas: 1\n+replicas: 2\n",
+			}},
+		},
+		"unchanged upgrade is omitted": {
+			deployed: deployedRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+			release:  newRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+		},
+		"sensitive values are redacted": {
+			deployed: deployedRelease("foo", "1.0.0", map[string]any{
+				"key-service": map[string]any{"masterSecret": "old"},
+			}),
+			release: newRelease("foo", "1.0.0", map[string]any{
+				"key-service": map[string]any{"masterSecret": "new"},
+			}),
+		},
+		"release not deployed": {
+			release: newRelease("foo", "1.0.0", map[string]any{"replicas": 1}),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cfg := &action.Configuration{
+				Releases:   storage.Init(driver.NewMemory()),
+				KubeClient: &kubefake.PrintingKubeClient{Out: io.Discard},
+			}
+			if tc.deployed != nil {
+				require.NoError(cfg.Releases.Create(tc.deployed))
+			}
+
+			factory := newActionFactory(nil, nil, cfg, logger.NewTest(t))
+			var releaseAction applyAction = factory.newUpgrade(tc.release, 0)
+			if tc.install {
+				releaseAction = factory.newInstall(tc.release, 0)
+			}
+			executor := ChartApplyExecutor{actions: []applyAction{releaseAction}, log: logger.NewTest(t)}
+
+			changes, err := executor.Changes()
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantChanges, changes)
+		})
+	}
+}
+
+func deployedRelease(name, version string, values map[string]any) *helmrelease.Release {
+	return &helmrelease.Release{
+		Name:    name,
+		Version: 1,
+		Chart:   &chart.Chart{Metadata: &chart.Metadata{Name: name, Version: version}},
+		Config:  values,
+		Info:    &helmrelease.Info{Status: helmrelease.StatusDeployed},
+	}
+}
+
 func getActionReleaseNames(actions []applyAction) []string {
 	releaseActionNames := []string{}
 	for _, action := range actions {
@@ -115,14 +115,17 @@ func newLoader(csp cloudprovider.Provider, attestationVariant variant.Variant, k
 type releaseApplyOrder []release
 
 // loadReleases loads the embedded helm charts and returns them as a HelmReleases object.
-func (i *chartLoader) loadReleases(conformanceMode, deployCSIDriver bool, helmWaitMode WaitMode, masterSecret uri.MasterSecret,
+func (i *chartLoader) loadReleases(conformanceMode, deployCSIDriver bool, podCIDR string, helmWaitMode WaitMode, masterSecret uri.MasterSecret,
 	serviceAccURI string, openStackCfg *config.OpenStackConfig,
 ) (releaseApplyOrder, error) {
 	ciliumRelease, err := i.loadRelease(ciliumInfo, helmWaitMode)
 	if err != nil {
 		return nil, fmt.Errorf("loading cilium: %w", err)
 	}
-	ciliumVals := extraCiliumValues(i.csp, conformanceMode, i.stateFile.Infrastructure)
+	ciliumVals, err := extraCiliumValues(i.csp, conformanceMode, podCIDR, i.stateFile.Infrastructure)
+	if err != nil {
+		return nil, fmt.Errorf("extending cilium values: %w", err)
+	}
 	ciliumRelease.values = mergeMaps(ciliumRelease.values, ciliumVals)
 
 	certManagerRelease, err := i.loadRelease(certManagerInfo, helmWaitMode)
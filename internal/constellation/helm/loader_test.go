@@ -79,7 +79,7 @@ func TestLoadReleases(t *testing.T) {
 		semver.NewFromInt(2, 10, 0, ""),
 	)
 	helmReleases, err := chartLoader.loadReleases(
-		true, false, WaitModeAtomic,
+		true, false, "", WaitModeAtomic,
 		uri.MasterSecret{Key: []byte("secret"), Salt: []byte("masterSalt")},
 		fakeServiceAccURI(cloudprovider.GCP), nil,
 	)
@@ -91,6 +91,63 @@ func TestLoadReleases(t *testing.T) {
 	}
 }
 
+func TestExtraCiliumValues(t *testing.T) {
+	testCases := map[string]struct {
+		provider        cloudprovider.Provider
+		podCIDR         string
+		wantIPv4PodCIDR string
+		wantIPv6PodCIDR string
+		wantErr         bool
+	}{
+		"default pod CIDR": {
+			provider:        cloudprovider.QEMU,
+			wantIPv4PodCIDR: "10.244.0.0/16",
+		},
+		"custom pod CIDR": {
+			provider:        cloudprovider.AWS,
+			podCIDR:         "10.200.0.0/16",
+			wantIPv4PodCIDR: "10.200.0.0/16",
+		},
+		"dual-stack": {
+			provider:        cloudprovider.QEMU,
+			podCIDR:         "10.244.0.0/16,fd00:10:244::/56",
+			wantIPv4PodCIDR: "10.244.0.0/16",
+			wantIPv6PodCIDR: "fd00:10:244::/56",
+		},
+		"invalid pod CIDR": {
+			provider: cloudprovider.AWS,
+			podCIDR:  "10.244.0.0",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			vals, err := extraCiliumValues(tc.provider, false, tc.podCIDR, state.Infrastructure{IPCidrNode: "192.168.0.0/16"})
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			operatorIPAM := vals["ipam"].(map[string]any)["operator"].(map[string]any)
+			assert.Equal([]string{tc.wantIPv4PodCIDR}, operatorIPAM["clusterPoolIPv4PodCIDRList"])
+			strictMode := vals["encryption"].(map[string]any)["strictMode"].(map[string]any)
+			assert.Equal([]string{tc.wantIPv4PodCIDR}, strictMode["podCIDRList"])
+			if tc.wantIPv6PodCIDR == "" {
+				assert.NotContains(operatorIPAM, "clusterPoolIPv6PodCIDRList")
+				assert.NotContains(vals, "ipv6")
+				return
+			}
+			assert.Equal([]string{tc.wantIPv6PodCIDR}, operatorIPAM["clusterPoolIPv6PodCIDRList"])
+			assert.Equal(map[string]any{"enabled": true}, vals["ipv6"])
+		})
+	}
+}
+
 func TestLoadAWSLoadBalancerValues(t *testing.T) {
 	sut := chartLoader{
 		clusterName: "testCluster",
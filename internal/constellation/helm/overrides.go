@@ -22,6 +22,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes"
 )
 
 // TODO(malt3): switch over to DNS name on AWS and Azure
@@ -32,7 +33,7 @@ import (
 // reuse user input from the init step. However, we can't rely on reuse-values, because
 // during upgrades we all values need to be set locally as they might have changed.
 // Also, the charts are not rendered correctly without all of these values.
-func extraCiliumValues(provider cloudprovider.Provider, conformanceMode bool, output state.Infrastructure) map[string]any {
+func extraCiliumValues(provider cloudprovider.Provider, conformanceMode bool, podCIDR string, output state.Infrastructure) (map[string]any, error) {
 	extraVals := map[string]any{}
 	if conformanceMode {
 		extraVals["kubeProxyReplacementHealthzBindAddr"] = ""
@@ -55,6 +56,28 @@ func extraCiliumValues(provider cloudprovider.Provider, conformanceMode bool, ou
 	if provider == cloudprovider.GCP {
 		extraVals["ipv4NativeRoutingCIDR"] = output.GCP.IPCidrPod
 		strictMode["podCIDRList"] = []string{output.GCP.IPCidrPod}
+	} else {
+		if podCIDR == "" {
+			podCIDR = kubernetes.DefaultPodCIDR
+		}
+		podCIDRs, err := kubernetes.ParseCIDRs(podCIDR)
+		if err != nil {
+			return nil, fmt.Errorf("parsing pod CIDR: %w", err)
+		}
+		operatorIPAM := map[string]any{
+			"clusterPoolIPv4PodCIDRList": []string{podCIDRs.IPv4},
+		}
+		// WireGuard strict mode only supports IPv4, IPv6 pod traffic is encrypted in non-strict mode.
+		strictMode["podCIDRList"] = []string{podCIDRs.IPv4}
+		if podCIDRs.IPv6 != "" {
+			operatorIPAM["clusterPoolIPv6PodCIDRList"] = []string{podCIDRs.IPv6}
+			extraVals["ipv6"] = map[string]any{
+				"enabled": true,
+			}
+		}
+		extraVals["ipam"] = map[string]any{
+			"operator": operatorIPAM,
+		}
 	}
 	extraVals["encryption"] = map[string]any{
 		"strictMode": strictMode,
@@ -74,7 +97,7 @@ func extraCiliumValues(provider cloudprovider.Provider, conformanceMode bool, ou
 		},
 	}
 
-	return extraVals
+	return extraVals, nil
 }
 
 // extraConstellationServicesValues extends the given values map by some values depending on user input.
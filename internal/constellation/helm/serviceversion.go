@@ -53,3 +53,26 @@ func (s ServiceVersions) String() string {
 func (s ServiceVersions) ConstellationServices() semver.Semver {
 	return s.constellationServices
 }
+
+// ConstellationOperators returns the version of the constellation-operators release.
+func (s ServiceVersions) ConstellationOperators() semver.Semver {
+	return s.constellationOperators
+}
+
+// Releases returns the versions of all installed releases, keyed by release name.
+// CSI drivers are keyed by their chart name.
+func (s ServiceVersions) Releases() map[string]string {
+	releases := map[string]string{
+		ciliumInfo.releaseName:                 s.cilium.String(),
+		certManagerInfo.releaseName:            s.certManager.String(),
+		constellationOperatorsInfo.releaseName: s.constellationOperators.String(),
+		constellationServicesInfo.releaseName:  s.constellationServices.String(),
+	}
+	if s.awsLBController != (semver.Semver{}) {
+		releases[awsLBControllerInfo.releaseName] = s.awsLBController.String()
+	}
+	for name, csiVersion := range s.csiVersions {
+		releases[name] = csiVersion.String()
+	}
+	return releases
+}
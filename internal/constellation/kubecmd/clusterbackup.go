@@ -0,0 +1,325 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubecmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// etcdSnapshotCommand dumps the complete etcd keyspace at a single revision as JSON.
+// The etcd image doesn't ship a shell, so a snapshot file written by 'etcdctl snapshot save'
+// can't be streamed back. Instead, etcdctl writes all keys and values to stdout.
+var etcdSnapshotCommand = etcdctlCommand("get", "", "--prefix", "--write-out=json")
+
+// etcdKeysCommand lists all keys of Kubernetes resources stored in etcd as JSON.
+var etcdKeysCommand = etcdctlCommand("get", etcdRegistryPrefix, "--prefix", "--keys-only", "--write-out=json")
+
+// etcdRegistryPrefix is the prefix of all keys the API server stores resources under.
+const etcdRegistryPrefix = "/registry/"
+
+// skippedRestoreEtcdPrefixes hold resources bound to the nodes, network, or identity of the backed up cluster,
+// as well as Secrets, which are restored through the API server to skip cluster bound secret types.
+var skippedRestoreEtcdPrefixes = []string{
+	"/registry/certificatesigningrequests/",
+	"/registry/csinodes/",
+	"/registry/endpointslices/",
+	"/registry/events/",
+	"/registry/leases/",
+	"/registry/masterleases/",
+	"/registry/minions/",
+	"/registry/pods/",
+	"/registry/ranges/",
+	"/registry/secrets/",
+	"/registry/services/endpoints/",
+	"/registry/volumeattachments/",
+}
+
+// skippedSecretTypes are secret types bound to the identity of a specific cluster.
+// They are neither backed up nor restored.
+var skippedSecretTypes = map[corev1.SecretType]struct{}{
+	corev1.SecretTypeServiceAccountToken: {},
+	corev1.SecretTypeBootstrapToken:      {},
+	"helm.sh/release.v1":                 {},
+}
+
+// skippedRestoreNamespaces hold resources created by Constellation when initializing a cluster.
+// Restoring them on a new cluster would overwrite the cluster's own configuration.
+var skippedRestoreNamespaces = map[string]struct{}{
+	metav1.NamespaceSystem:    {},
+	metav1.NamespacePublic:    {},
+	corev1.NamespaceNodeLease: {},
+}
+
+// skippedRestoreGroups hold custom resources describing the infrastructure or runtime state of a specific cluster.
+var skippedRestoreGroups = map[string]struct{}{
+	"update.edgeless.systems": {},
+	"cilium.io":               {},
+}
+
+// BackupSecrets backs up all secrets of the cluster to dir.
+// Service account and bootstrap tokens, as well as Helm release secrets, are skipped.
+func (k *KubeCmd) BackupSecrets(ctx context.Context, fileHandler file.Handler, dir string) error {
+	k.log.Debugf("Starting secret backup")
+	secrets, err := k.kubectl.ListSecrets(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return fmt.Errorf("getting secrets: %w", err)
+	}
+
+	for i := range secrets {
+		if _, ok := skippedSecretTypes[secrets[i].Type]; ok {
+			continue
+		}
+		targetFolder := filepath.Join(dir, secrets[i].Namespace)
+		if err := fileHandler.MkdirAll(targetFolder); err != nil {
+			return fmt.Errorf("creating backup dir: %w", err)
+		}
+
+		// See BackupCRDs for why kind and apiVersion have to be set manually.
+		secrets[i].Kind = "Secret"
+		secrets[i].APIVersion = "v1"
+
+		yamlBytes, err := yaml.Marshal(secrets[i])
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(targetFolder, secrets[i].Name+".yaml")
+		k.log.Debugf("Creating secret backup: %s", path)
+		if err := fileHandler.Write(path, yamlBytes); err != nil {
+			return err
+		}
+	}
+	k.log.Debugf("Secret backup complete")
+	return nil
+}
+
+// SnapshotEtcd writes a snapshot of the complete etcd keyspace to out.
+// The snapshot is taken through the control plane, by running etcdctl in one of the etcd pods,
+// and holds all keys and values at a single revision as JSON.
+func (k *KubeCmd) SnapshotEtcd(ctx context.Context, out io.Writer) error {
+	etcdPod, err := k.runningEtcdPod(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.log.Debugf("Taking etcd snapshot using pod %q", etcdPod)
+	var stderr bytes.Buffer
+	if err := k.kubectl.ExecPod(ctx, metav1.NamespaceSystem, etcdPod, "etcd", etcdSnapshotCommand, nil, out, &stderr); err != nil {
+		return fmt.Errorf("taking etcd snapshot: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// RestoreEtcd writes the Kubernetes resources of an etcd snapshot taken by SnapshotEtcd
+// to the cluster's etcd and returns the number of restored keys.
+// Existing keys are not modified. Resources bound to the backed up cluster,
+// resources in namespaces managed by Kubernetes, and Secrets are skipped.
+func (k *KubeCmd) RestoreEtcd(ctx context.Context, snapshot []byte) (int, error) {
+	var backedUp etcdKeyValues
+	if err := json.Unmarshal(snapshot, &backedUp); err != nil {
+		return 0, fmt.Errorf("parsing etcd snapshot: %w", err)
+	}
+
+	etcdPod, err := k.runningEtcdPod(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var stdout, stderr bytes.Buffer
+	if err := k.kubectl.ExecPod(ctx, metav1.NamespaceSystem, etcdPod, "etcd", etcdKeysCommand, nil, &stdout, &stderr); err != nil {
+		return 0, fmt.Errorf("listing etcd keys: %w: %s", err, stderr.String())
+	}
+	var existing etcdKeyValues
+	if err := json.Unmarshal(stdout.Bytes(), &existing); err != nil {
+		return 0, fmt.Errorf("parsing etcd keys: %w", err)
+	}
+	existingKeys := map[string]struct{}{}
+	for _, kv := range existing.KVs {
+		existingKeys[string(kv.Key)] = struct{}{}
+	}
+
+	var restored int
+	for _, kv := range backedUp.KVs {
+		key := string(kv.Key)
+		if !restorableEtcdKey(key) {
+			continue
+		}
+		if _, ok := existingKeys[key]; ok {
+			k.log.Debugf("etcd key %s already exists, skipping", key)
+			continue
+		}
+
+		// etcdctl reads the value from stdin, since it may hold arbitrary binary data
+		stderr.Reset()
+		if err := k.kubectl.ExecPod(
+			ctx, metav1.NamespaceSystem, etcdPod, "etcd", etcdctlCommand("put", key), bytes.NewReader(kv.Value), io.Discard, &stderr,
+		); err != nil {
+			return restored, fmt.Errorf("restoring etcd key %s: %w: %s", key, err, stderr.String())
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// etcdKeyValues is the JSON output of 'etcdctl get'.
+// Keys and values are base64 encoded.
+type etcdKeyValues struct {
+	KVs []etcdKeyValue `json:"kvs"`
+}
+
+type etcdKeyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// restorableEtcdKey reports whether the resource stored under key can be restored into a new cluster.
+func restorableEtcdKey(key string) bool {
+	if !strings.HasPrefix(key, etcdRegistryPrefix) {
+		return false
+	}
+	for _, prefix := range skippedRestoreEtcdPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return false
+		}
+	}
+	// keys have the form /registry/[<group>/]<resource>/[<namespace>/]<name>
+	segments := strings.Split(strings.TrimPrefix(key, etcdRegistryPrefix), "/")
+	if _, ok := skippedRestoreGroups[segments[0]]; ok {
+		return false
+	}
+	for _, segment := range segments[:len(segments)-1] {
+		if _, ok := skippedRestoreNamespaces[segment]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RestoreSecrets creates the given secrets in the cluster and returns the number of restored secrets.
+// Existing secrets are not modified. Secrets in namespaces managed by Kubernetes are skipped.
+func (k *KubeCmd) RestoreSecrets(ctx context.Context, secrets []corev1.Secret) (int, error) {
+	namespaces := map[string]struct{}{}
+	var restored int
+	for _, secret := range secrets {
+		if _, ok := skippedRestoreNamespaces[secret.Namespace]; ok {
+			continue
+		}
+		if _, ok := skippedSecretTypes[secret.Type]; ok {
+			continue
+		}
+		if err := k.ensureNamespace(ctx, secret.Namespace, namespaces); err != nil {
+			return restored, err
+		}
+
+		secret.ObjectMeta = restorableObjectMeta(secret.ObjectMeta)
+		err := k.kubectl.CreateSecret(ctx, &secret)
+		if k8serrors.IsAlreadyExists(err) {
+			k.log.Debugf("Secret %s/%s already exists, skipping", secret.Namespace, secret.Name)
+			continue
+		}
+		if err != nil {
+			return restored, fmt.Errorf("creating secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// RestoreCRs creates the given custom resources in the cluster and returns the number of restored resources.
+// Existing resources, resources without a matching CRD, and resources describing the state of the previous cluster are skipped.
+func (k *KubeCmd) RestoreCRs(ctx context.Context, crs []unstructured.Unstructured) (int, error) {
+	crds, err := k.kubectl.ListCRDs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("getting CRDs: %w", err)
+	}
+	// map group/version/kind to the resource name of the installed CRDs
+	resources := map[schema.GroupVersionKind]string{}
+	for _, crd := range crds {
+		for _, version := range crd.Spec.Versions {
+			gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.Kind}
+			resources[gvk] = crd.Spec.Names.Plural
+		}
+	}
+
+	namespaces := map[string]struct{}{}
+	var restored int
+	for _, cr := range crs {
+		gvk := cr.GroupVersionKind()
+		if _, ok := skippedRestoreGroups[gvk.Group]; ok {
+			continue
+		}
+		if _, ok := skippedRestoreNamespaces[cr.GetNamespace()]; ok {
+			continue
+		}
+		resource, ok := resources[gvk]
+		if !ok {
+			k.log.Debugf("No CRD installed for %s, skipping %s", gvk, cr.GetName())
+			continue
+		}
+		if err := k.ensureNamespace(ctx, cr.GetNamespace(), namespaces); err != nil {
+			return restored, err
+		}
+
+		obj := cr.DeepCopy()
+		unstructured.RemoveNestedField(obj.Object, "status")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "resourceVersion")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "uid")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "creationTimestamp")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
+		unstructured.RemoveNestedField(obj.Object, "metadata", "ownerReferences")
+
+		gvr := schema.GroupVersionResource{Group: gvk.Group, Version: gvk.Version, Resource: resource}
+		err := k.kubectl.CreateCR(ctx, gvr, obj)
+		if k8serrors.IsAlreadyExists(err) {
+			k.log.Debugf("%s %s already exists, skipping", gvk.Kind, cr.GetName())
+			continue
+		}
+		if err != nil {
+			return restored, fmt.Errorf("creating %s %s: %w", gvk.Kind, cr.GetName(), err)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// ensureNamespace creates the given namespace if it doesn't exist yet.
+// created caches namespaces already handled.
+func (k *KubeCmd) ensureNamespace(ctx context.Context, namespace string, created map[string]struct{}) error {
+	if namespace == "" {
+		return nil
+	}
+	if _, ok := created[namespace]; ok {
+		return nil
+	}
+	if err := k.kubectl.CreateNamespace(ctx, namespace); err != nil && !k8serrors.IsAlreadyExists(err) {
+		return fmt.Errorf("creating namespace %s: %w", namespace, err)
+	}
+	created[namespace] = struct{}{}
+	return nil
+}
+
+// restorableObjectMeta removes all metadata of an object bound to the cluster it was backed up from.
+func restorableObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Namespace:   meta.Namespace,
+		Labels:      meta.Labels,
+		Annotations: meta.Annotations,
+	}
+}
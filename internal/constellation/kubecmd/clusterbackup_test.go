@@ -0,0 +1,404 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubecmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+func TestBackupSecrets(t *testing.T) {
+	testCases := map[string]struct {
+		secrets        []corev1.Secret
+		listSecretsErr error
+		wantFiles      []string
+		wantErr        bool
+	}{
+		"success": {
+			secrets: []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}, Type: corev1.SecretTypeOpaque},
+				{ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "kube-system"}, Type: corev1.SecretTypeTLS},
+				{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "default"}, Type: corev1.SecretTypeServiceAccountToken},
+				{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.cilium.v1", Namespace: "kube-system"}, Type: "helm.sh/release.v1"},
+			},
+			wantFiles: []string{
+				filepath.Join("secrets", "default", "app.yaml"),
+				filepath.Join("secrets", "kube-system", "tls.yaml"),
+			},
+		},
+		"listing secrets fails": {
+			listSecretsErr: assert.AnError,
+			wantErr:        true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			client := KubeCmd{
+				kubectl: &stubKubectl{secrets: tc.secrets, listSecretsErr: tc.listSecretsErr},
+				log:     stubLog{},
+			}
+
+			err := client.BackupSecrets(context.Background(), fileHandler, "secrets")
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+
+			var files []string
+			for _, secret := range tc.secrets {
+				path := filepath.Join("secrets", secret.Namespace, secret.Name+".yaml")
+				if _, err := fileHandler.Stat(path); err == nil {
+					files = append(files, path)
+				}
+			}
+			assert.ElementsMatch(tc.wantFiles, files)
+
+			raw, err := fileHandler.Read(tc.wantFiles[0])
+			require.NoError(err)
+			var secret corev1.Secret
+			require.NoError(yaml.Unmarshal(raw, &secret))
+			assert.Equal("Secret", secret.Kind)
+			assert.Equal("v1", secret.APIVersion)
+		})
+	}
+}
+
+func TestSnapshotEtcd(t *testing.T) {
+	testCases := map[string]struct {
+		pods    []corev1.Pod
+		execErr error
+		wantPod string
+		wantErr bool
+	}{
+		"success": {
+			pods: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"}, Status: corev1.PodStatus{Phase: corev1.PodPending}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "etcd-1"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			},
+			wantPod: "etcd-1",
+		},
+		"no running etcd pod": {
+			pods: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+			},
+			wantErr: true,
+		},
+		"exec fails": {
+			pods: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			},
+			execErr: assert.AnError,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			kubectl := &stubKubectl{pods: tc.pods, execOutput: `{"kvs":[]}`, execErr: tc.execErr}
+			client := KubeCmd{kubectl: kubectl, log: stubLog{}}
+
+			var out bytes.Buffer
+			err := client.SnapshotEtcd(context.Background(), &out)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantPod, kubectl.execPod)
+			assert.Equal(`{"kvs":[]}`, out.String())
+		})
+	}
+}
+
+func TestRestoreEtcd(t *testing.T) {
+	runningEtcd := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	}
+	snapshot := etcdJSON(map[string]string{
+		"/registry/deployments/app/web":                      "deployment",
+		"/registry/configmaps/app/settings":                  "configmap",
+		"/registry/configmaps/app/existing":                  "backed up configmap",
+		"/registry/example.com/widgets/app/widget":           "widget",
+		"/registry/configmaps/kube-system/kubeadm-config":    "kubeadm config",
+		"/registry/pods/app/web-1234":                        "pod",
+		"/registry/minions/node-0":                           "node",
+		"/registry/secrets/app/secret":                       "secret",
+		"/registry/update.edgeless.systems/nodeversions/cfg": "nodeversion",
+		"compact_rev_key":                                    "1",
+	})
+
+	testCases := map[string]struct {
+		snapshot     []byte
+		pods         []corev1.Pod
+		existingKeys []byte
+		execErr      error
+		wantRestored map[string]string
+		wantErr      bool
+	}{
+		"success": {
+			snapshot:     snapshot,
+			pods:         runningEtcd,
+			existingKeys: etcdJSON(map[string]string{"/registry/configmaps/app/existing": ""}),
+			wantRestored: map[string]string{
+				"/registry/deployments/app/web":            "deployment",
+				"/registry/configmaps/app/settings":        "configmap",
+				"/registry/example.com/widgets/app/widget": "widget",
+			},
+		},
+		"invalid snapshot": {
+			snapshot: []byte("not json"),
+			pods:     runningEtcd,
+			wantErr:  true,
+		},
+		"no running etcd pod": {
+			snapshot: snapshot,
+			wantErr:  true,
+		},
+		"exec fails": {
+			snapshot: snapshot,
+			pods:     runningEtcd,
+			execErr:  assert.AnError,
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			kubectl := &stubKubectl{pods: tc.pods, execOutput: string(tc.existingKeys), execErr: tc.execErr}
+			client := KubeCmd{kubectl: kubectl, log: stubLog{}}
+
+			restored, err := client.RestoreEtcd(context.Background(), tc.snapshot)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(len(tc.wantRestored), restored)
+			assert.Equal(tc.wantRestored, kubectl.execStdin)
+		})
+	}
+}
+
+// etcdJSON returns the given keys and values in the JSON format of 'etcdctl get'.
+func etcdJSON(kvs map[string]string) []byte {
+	var out etcdKeyValues
+	for key, value := range kvs {
+		out.KVs = append(out.KVs, etcdKeyValue{Key: []byte(key), Value: []byte(value)})
+	}
+	raw, _ := json.Marshal(out)
+	return raw
+}
+
+func TestRestoreSecrets(t *testing.T) {
+	testCases := map[string]struct {
+		secrets      []corev1.Secret
+		createErr    error
+		wantRestored int
+		wantNs       []string
+		wantErr      bool
+	}{
+		"success": {
+			secrets: []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app", UID: "1234", ResourceVersion: "1"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "join-secret", Namespace: "kube-system"}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "app"}, Type: corev1.SecretTypeServiceAccountToken},
+			},
+			wantRestored: 1,
+			wantNs:       []string{"app"},
+		},
+		"existing secrets are skipped": {
+			secrets: []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"}},
+			},
+			createErr: k8serrors.NewAlreadyExists(schema.GroupResource{}, "app"),
+			wantNs:    []string{"app"},
+		},
+		"create fails": {
+			secrets: []corev1.Secret{
+				{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "app"}},
+			},
+			createErr: assert.AnError,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			kubectl := &stubKubectl{createErr: tc.createErr}
+			client := KubeCmd{kubectl: kubectl, log: stubLog{}}
+
+			restored, err := client.RestoreSecrets(context.Background(), tc.secrets)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantRestored, restored)
+			assert.Equal(tc.wantNs, kubectl.namespaces)
+			for _, secret := range kubectl.createdSecrets {
+				assert.Empty(secret.UID)
+				assert.Empty(secret.ResourceVersion)
+			}
+		})
+	}
+}
+
+func TestRestoreCRs(t *testing.T) {
+	crd := apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "cert-manager.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Certificate", Plural: "certificates"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1"},
+			},
+		},
+	}
+	newCR := func(apiVersion, kind, namespace, name string) unstructured.Unstructured {
+		cr := unstructured.Unstructured{}
+		require.NoError(t, yaml.Unmarshal([]byte(
+			"apiVersion: "+apiVersion+"\nkind: "+kind+"\nmetadata:\n  name: "+name+"\n  namespace: "+namespace+
+				"\n  uid: \"1234\"\n  resourceVersion: \"1\"\nstatus:\n  ready: true\n",
+		), &cr.Object))
+		return cr
+	}
+
+	testCases := map[string]struct {
+		crs          []unstructured.Unstructured
+		getCRDsError error
+		createErr    error
+		wantRestored int
+		wantErr      bool
+	}{
+		"success": {
+			crs: []unstructured.Unstructured{
+				newCR("cert-manager.io/v1", "Certificate", "app", "cert"),
+				newCR("cert-manager.io/v1alpha1", "Certificate", "app", "old-cert"),
+				newCR("update.edgeless.systems/v1alpha1", "NodeVersion", "", "constellation-version"),
+				newCR("example.com/v1", "Unknown", "app", "unknown"),
+			},
+			wantRestored: 1,
+		},
+		"existing resources are skipped": {
+			crs:       []unstructured.Unstructured{newCR("cert-manager.io/v1", "Certificate", "app", "cert")},
+			createErr: k8serrors.NewAlreadyExists(schema.GroupResource{}, "cert"),
+		},
+		"listing CRDs fails": {
+			crs:          []unstructured.Unstructured{newCR("cert-manager.io/v1", "Certificate", "app", "cert")},
+			getCRDsError: assert.AnError,
+			wantErr:      true,
+		},
+		"create fails": {
+			crs:       []unstructured.Unstructured{newCR("cert-manager.io/v1", "Certificate", "app", "cert")},
+			createErr: assert.AnError,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			kubectl := &stubKubectl{
+				crds:         []apiextensionsv1.CustomResourceDefinition{crd},
+				getCRDsError: tc.getCRDsError,
+				createErr:    tc.createErr,
+			}
+			client := KubeCmd{kubectl: kubectl, log: stubLog{}}
+
+			restored, err := client.RestoreCRs(context.Background(), tc.crs)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantRestored, restored)
+			for _, cr := range kubectl.createdCRs {
+				assert.Empty(cr.GetUID())
+				assert.Empty(cr.GetResourceVersion())
+				_, hasStatus := cr.Object["status"]
+				assert.False(hasStatus)
+			}
+		})
+	}
+}
+
+func (s *stubKubectl) ListSecrets(_ context.Context, _ string) ([]corev1.Secret, error) {
+	return s.secrets, s.listSecretsErr
+}
+
+func (s *stubKubectl) CreateSecret(_ context.Context, secret *corev1.Secret) error {
+	if s.createErr != nil {
+		return s.createErr
+	}
+	s.createdSecrets = append(s.createdSecrets, *secret)
+	return nil
+}
+
+func (s *stubKubectl) CreateCR(_ context.Context, _ schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	if s.createErr != nil {
+		return s.createErr
+	}
+	s.createdCRs = append(s.createdCRs, *obj)
+	return nil
+}
+
+func (s *stubKubectl) CreateNamespace(_ context.Context, name string) error {
+	s.namespaces = append(s.namespaces, name)
+	return nil
+}
+
+func (s *stubKubectl) ListPods(_ context.Context, _, _ string) ([]corev1.Pod, error) {
+	return s.pods, nil
+}
+
+func (s *stubKubectl) ExecPod(_ context.Context, _, pod, _ string, command []string, stdin io.Reader, stdout, _ io.Writer) error {
+	s.execPod = pod
+	if s.execErr != nil {
+		return s.execErr
+	}
+	if stdin != nil {
+		value, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		if s.execStdin == nil {
+			s.execStdin = map[string]string{}
+		}
+		s.execStdin[command[len(command)-1]] = string(value)
+		return nil
+	}
+	_, err := stdout.Write([]byte(s.execOutput))
+	return err
+}
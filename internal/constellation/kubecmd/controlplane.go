@@ -0,0 +1,228 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubecmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// controlPlaneRoleLabel is set on all control-plane nodes by kubeadm.
+const controlPlaneRoleLabel = "node-role.kubernetes.io/control-plane"
+
+// etcdMemberListCommand lists the members of the etcd cluster as JSON.
+var etcdMemberListCommand = etcdctlCommand("member", "list", "--write-out=json")
+
+// etcdctlCommand returns an etcdctl command connecting to the etcd member of the pod it is executed in.
+func etcdctlCommand(args ...string) []string {
+	return append([]string{
+		"etcdctl",
+		"--endpoints=https://127.0.0.1:2379",
+		"--cacert=/etc/kubernetes/pki/etcd/ca.crt",
+		"--cert=/etc/kubernetes/pki/etcd/healthcheck-client.crt",
+		"--key=/etc/kubernetes/pki/etcd/healthcheck-client.key",
+	}, args...)
+}
+
+// ControlPlaneNode is the join state of a control-plane node.
+type ControlPlaneNode struct {
+	// Name is the name of the Kubernetes node.
+	Name string
+	// Ready is true if the node reports the Ready condition.
+	Ready bool
+	// Attested is true if the node passed attestation, either during init or by the join-service.
+	Attested bool
+	// EtcdMember is true if the node is a voting member of the etcd cluster.
+	EtcdMember bool
+}
+
+// Joined returns true if the node is ready, attested, and a member of etcd.
+func (n ControlPlaneNode) Joined() bool {
+	return n.Ready && n.Attested && n.EtcdMember
+}
+
+// String returns a human-readable description of the node's join state.
+func (n ControlPlaneNode) String() string {
+	return fmt.Sprintf("%s: ready=%t, attested=%t, etcd member=%t", n.Name, n.Ready, n.Attested, n.EtcdMember)
+}
+
+// ControlPlaneNodes returns the join state of all control-plane nodes of the cluster, sorted by name.
+func (k *KubeCmd) ControlPlaneNodes(ctx context.Context) ([]ControlPlaneNode, error) {
+	nodes, err := k.kubectl.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting nodes: %w", err)
+	}
+	members, err := k.etcdMembers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var controlPlaneNodes []ControlPlaneNode
+	for _, node := range nodes {
+		if _, ok := node.Labels[controlPlaneRoleLabel]; !ok {
+			continue
+		}
+		_, etcdMember := members[node.Name]
+		controlPlaneNodes = append(controlPlaneNodes, ControlPlaneNode{
+			Name:       node.Name,
+			Ready:      nodeReady(node),
+			Attested:   node.Annotations[constants.NodeKubernetesComponentsAnnotationKey] != "",
+			EtcdMember: etcdMember,
+		})
+	}
+	sort.Slice(controlPlaneNodes, func(i, j int) bool {
+		return controlPlaneNodes[i].Name < controlPlaneNodes[j].Name
+	})
+	return controlPlaneNodes, nil
+}
+
+// WaitForControlPlaneNodes waits until count control-plane nodes joined the cluster, or ctx is done.
+// progress is called whenever the join state of a node changes.
+// If ctx is done before all nodes joined, a [*ControlPlaneJoinError] with the last observed state of every node is returned.
+func (k *KubeCmd) WaitForControlPlaneNodes(ctx context.Context, count int, progress func(ControlPlaneNode)) ([]ControlPlaneNode, error) {
+	ticker := time.NewTicker(k.retryInterval)
+	defer ticker.Stop()
+
+	seen := map[string]ControlPlaneNode{}
+	var nodes []ControlPlaneNode
+	for {
+		current, err := k.ControlPlaneNodes(ctx)
+		if err != nil {
+			// the API server or etcd may be unavailable while control-plane nodes are joining
+			k.log.Debugf("Getting control-plane nodes failed: %s", err)
+		} else {
+			nodes = current
+			for _, node := range nodes {
+				if seen[node.Name] != node {
+					seen[node.Name] = node
+					progress(node)
+				}
+			}
+			if countJoined(nodes) >= count {
+				return nodes, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nodes, &ControlPlaneJoinError{Nodes: nodes, Requested: count}
+		case <-ticker.C:
+		}
+	}
+}
+
+// ControlPlaneJoinError is returned if not all requested control-plane nodes joined the cluster in time.
+type ControlPlaneJoinError struct {
+	Nodes     []ControlPlaneNode
+	Requested int
+}
+
+// Quorum returns true if at least (Requested/2)+1 nodes are etcd members.
+func (e *ControlPlaneJoinError) Quorum() bool {
+	return countEtcdMembers(e.Nodes) >= e.Requested/2+1
+}
+
+// Error returns the error message, listing the state of every node.
+func (e *ControlPlaneJoinError) Error() string {
+	states := make([]string, 0, len(e.Nodes))
+	for _, node := range e.Nodes {
+		states = append(states, node.String())
+	}
+	quorum := "etcd has quorum"
+	if !e.Quorum() {
+		quorum = fmt.Sprintf("etcd has no quorum, need at least %d members", e.Requested/2+1)
+	}
+	return fmt.Sprintf(
+		"only %d of %d requested control-plane nodes joined, %d are etcd members, %s: [%s]",
+		countJoined(e.Nodes), e.Requested, countEtcdMembers(e.Nodes), quorum, strings.Join(states, "; "),
+	)
+}
+
+// etcdMembers returns the names of all voting etcd members.
+func (k *KubeCmd) etcdMembers(ctx context.Context) (map[string]struct{}, error) {
+	etcdPod, err := k.runningEtcdPod(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := k.kubectl.ExecPod(ctx, metav1.NamespaceSystem, etcdPod, "etcd", etcdMemberListCommand, nil, &stdout, &stderr); err != nil {
+		return nil, fmt.Errorf("listing etcd members: %w: %s", err, stderr.String())
+	}
+	var memberList struct {
+		Members []struct {
+			Name      string `json:"name"`
+			IsLearner bool   `json:"isLearner"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &memberList); err != nil {
+		return nil, fmt.Errorf("parsing etcd member list: %w", err)
+	}
+
+	members := map[string]struct{}{}
+	for _, member := range memberList.Members {
+		// members that were added, but haven't started yet, have no name
+		if member.Name == "" || member.IsLearner {
+			continue
+		}
+		members[member.Name] = struct{}{}
+	}
+	return members, nil
+}
+
+// runningEtcdPod returns the name of a running etcd pod of the control plane.
+func (k *KubeCmd) runningEtcdPod(ctx context.Context) (string, error) {
+	pods, err := k.kubectl.ListPods(ctx, metav1.NamespaceSystem, "component=etcd,tier=control-plane")
+	if err != nil {
+		return "", fmt.Errorf("getting etcd pods: %w", err)
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+	return "", errors.New("no running etcd pod found")
+}
+
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func countJoined(nodes []ControlPlaneNode) int {
+	var joined int
+	for _, node := range nodes {
+		if node.Joined() {
+			joined++
+		}
+	}
+	return joined
+}
+
+func countEtcdMembers(nodes []ControlPlaneNode) int {
+	var members int
+	for _, node := range nodes {
+		if node.EtcdMember {
+			members++
+		}
+	}
+	return members
+}
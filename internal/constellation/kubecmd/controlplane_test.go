@@ -0,0 +1,192 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubecmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestControlPlaneNodes(t *testing.T) {
+	runningEtcd := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-control-plane-0"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+	memberList := `{"members":[{"name":"control-plane-0"},{"name":"control-plane-1","isLearner":true},{"name":""}]}`
+
+	testCases := map[string]struct {
+		kubectl   *stubKubectl
+		wantNodes []ControlPlaneNode
+		wantErr   bool
+	}{
+		"success": {
+			kubectl: &stubKubectl{
+				nodes: []corev1.Node{
+					controlPlaneNode("control-plane-1", true, false),
+					controlPlaneNode("control-plane-0", true, true),
+					{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}},
+				},
+				pods:       runningEtcd,
+				execOutput: memberList,
+			},
+			wantNodes: []ControlPlaneNode{
+				{Name: "control-plane-0", Ready: true, Attested: true, EtcdMember: true},
+				{Name: "control-plane-1", Ready: true},
+			},
+		},
+		"getting nodes fails": {
+			kubectl: &stubKubectl{
+				nodesErr:   errors.New("failed"),
+				pods:       runningEtcd,
+				execOutput: memberList,
+			},
+			wantErr: true,
+		},
+		"no running etcd pod": {
+			kubectl: &stubKubectl{
+				nodes: []corev1.Node{controlPlaneNode("control-plane-0", true, true)},
+				pods: []corev1.Pod{{
+					ObjectMeta: metav1.ObjectMeta{Name: "etcd-control-plane-0"},
+					Status:     corev1.PodStatus{Phase: corev1.PodPending},
+				}},
+			},
+			wantErr: true,
+		},
+		"listing members fails": {
+			kubectl: &stubKubectl{
+				nodes:   []corev1.Node{controlPlaneNode("control-plane-0", true, true)},
+				pods:    runningEtcd,
+				execErr: errors.New("failed"),
+			},
+			wantErr: true,
+		},
+		"invalid member list": {
+			kubectl: &stubKubectl{
+				nodes:      []corev1.Node{controlPlaneNode("control-plane-0", true, true)},
+				pods:       runningEtcd,
+				execOutput: "not json",
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			client := KubeCmd{kubectl: tc.kubectl, log: stubLog{}}
+
+			nodes, err := client.ControlPlaneNodes(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantNodes, nodes)
+		})
+	}
+}
+
+func TestWaitForControlPlaneNodes(t *testing.T) {
+	runningEtcd := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-control-plane-0"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+	nodes := []corev1.Node{
+		controlPlaneNode("control-plane-0", true, true),
+		controlPlaneNode("control-plane-1", true, true),
+		controlPlaneNode("control-plane-2", false, false),
+	}
+
+	testCases := map[string]struct {
+		memberList   string
+		count        int
+		wantJoined   int
+		wantProgress int
+		wantErr      bool
+		wantQuorum   bool
+	}{
+		"all nodes joined": {
+			memberList:   `{"members":[{"name":"control-plane-0"},{"name":"control-plane-1"}]}`,
+			count:        2,
+			wantJoined:   2,
+			wantProgress: 3,
+		},
+		"quorum reached on timeout": {
+			memberList:   `{"members":[{"name":"control-plane-0"},{"name":"control-plane-1"}]}`,
+			count:        3,
+			wantJoined:   2,
+			wantProgress: 3,
+			wantErr:      true,
+			wantQuorum:   true,
+		},
+		"quorum not reached on timeout": {
+			memberList:   `{"members":[{"name":"control-plane-0"}]}`,
+			count:        3,
+			wantJoined:   1,
+			wantProgress: 3,
+			wantErr:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			client := KubeCmd{
+				kubectl:       &stubKubectl{nodes: nodes, pods: runningEtcd, execOutput: tc.memberList},
+				retryInterval: time.Millisecond,
+				log:           stubLog{},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			var progress int
+			got, err := client.WaitForControlPlaneNodes(ctx, tc.count, func(ControlPlaneNode) { progress++ })
+			assert.Equal(tc.wantProgress, progress)
+			assert.Equal(tc.wantJoined, countJoined(got))
+			if tc.wantErr {
+				var joinErr *ControlPlaneJoinError
+				require.ErrorAs(err, &joinErr)
+				assert.Len(joinErr.Nodes, len(nodes))
+				assert.Equal(tc.wantQuorum, joinErr.Quorum())
+				for _, node := range nodes {
+					assert.Contains(err.Error(), node.Name)
+				}
+				return
+			}
+			assert.NoError(err)
+		})
+	}
+}
+
+func controlPlaneNode(name string, ready, attested bool) corev1.Node {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{controlPlaneRoleLabel: ""},
+		},
+	}
+	if attested {
+		node.Annotations = map[string]string{constants.NodeKubernetesComponentsAnnotationKey: "k8s-components-sha256-abc"}
+	}
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}
+	return node
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"slices"
 	"sort"
 	"strings"
@@ -252,22 +253,7 @@ func (k *KubeCmd) ExtendClusterConfigCertSANs(ctx context.Context, alternativeNa
 		return fmt.Errorf("getting ClusterConfig: %w", err)
 	}
 
-	existingSANs := make(map[string]struct{})
-	for _, existingSAN := range clusterConfiguration.APIServer.CertSANs {
-		existingSANs[existingSAN] = struct{}{}
-	}
-
-	var missingSANs []string
-	for _, san := range alternativeNames {
-		if san == "" {
-			continue // skip empty SANs
-		}
-		if _, ok := existingSANs[san]; !ok {
-			missingSANs = append(missingSANs, san)
-			existingSANs[san] = struct{}{} // make sure we don't add the same SAN twice
-		}
-	}
-
+	missingSANs := missingCertSANs(clusterConfiguration.APIServer.CertSANs, alternativeNames)
 	if len(missingSANs) == 0 {
 		k.log.Debugf("No new SANs to add to the cluster's apiserver SAN field")
 		return nil
@@ -292,6 +278,37 @@ func (k *KubeCmd) ExtendClusterConfigCertSANs(ctx context.Context, alternativeNa
 	return nil
 }
 
+// MissingClusterConfigCertSANs returns the SANs that would be added to the ClusterConfig
+// stored under "kube-system/kubeadm-config" by [KubeCmd.ExtendClusterConfigCertSANs].
+// The cluster is not modified.
+func (k *KubeCmd) MissingClusterConfigCertSANs(ctx context.Context, alternativeNames []string) ([]string, error) {
+	clusterConfiguration, _, err := k.getClusterConfiguration(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting ClusterConfig: %w", err)
+	}
+	return missingCertSANs(clusterConfiguration.APIServer.CertSANs, alternativeNames), nil
+}
+
+// missingCertSANs returns the non-empty SANs of alternativeNames that are not part of existing.
+func missingCertSANs(existing, alternativeNames []string) []string {
+	existingSANs := make(map[string]struct{})
+	for _, existingSAN := range existing {
+		existingSANs[existingSAN] = struct{}{}
+	}
+
+	var missingSANs []string
+	for _, san := range alternativeNames {
+		if san == "" {
+			continue // skip empty SANs
+		}
+		if _, ok := existingSANs[san]; !ok {
+			missingSANs = append(missingSANs, san)
+			existingSANs[san] = struct{}{} // make sure we don't add the same SAN twice
+		}
+	}
+	return missingSANs
+}
+
 // GetConstellationVersion retrieves the Kubernetes and image version of a Constellation cluster,
 // as well as the Kubernetes components reference, and image reference string.
 func (k *KubeCmd) GetConstellationVersion(ctx context.Context) (NodeVersion, error) {
@@ -542,6 +559,12 @@ type kubectlInterface interface {
 	KubernetesVersion() (string, error)
 	GetCR(ctx context.Context, gvr schema.GroupVersionResource, name string) (*unstructured.Unstructured, error)
 	UpdateCR(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	CreateCR(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error
+	ListSecrets(ctx context.Context, namespace string) ([]corev1.Secret, error)
+	CreateSecret(ctx context.Context, secret *corev1.Secret) error
+	CreateNamespace(ctx context.Context, name string) error
+	ListPods(ctx context.Context, namespace, labelSelector string) ([]corev1.Pod, error)
+	ExecPod(ctx context.Context, namespace, pod, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error
 	crdLister
 }
 
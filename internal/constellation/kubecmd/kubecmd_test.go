@@ -431,6 +431,34 @@ func TestIsValidImageUpgrade(t *testing.T) {
 	}
 }
 
+func TestMissingCertSANs(t *testing.T) {
+	testCases := map[string]struct {
+		existing         []string
+		alternativeNames []string
+		want             []string
+	}{
+		"all SANs exist": {
+			existing:         []string{"192.0.2.1", "example.com"},
+			alternativeNames: []string{"example.com", "192.0.2.1"},
+		},
+		"new SANs are returned": {
+			existing:         []string{"192.0.2.1"},
+			alternativeNames: []string{"192.0.2.1", "example.com", "192.0.2.2"},
+			want:             []string{"example.com", "192.0.2.2"},
+		},
+		"empty and duplicate SANs are ignored": {
+			alternativeNames: []string{"", "example.com", "example.com"},
+			want:             []string{"example.com"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, missingCertSANs(tc.existing, tc.alternativeNames))
+		})
+	}
+}
+
 func TestUpdateK8s(t *testing.T) {
 	someErr := errors.New("error")
 	testCases := map[string]struct {
@@ -737,6 +765,17 @@ type stubKubectl struct {
 	getCRDsError      error
 	crs               []unstructured.Unstructured
 	getCRsError       error
+	secrets           []corev1.Secret
+	listSecretsErr    error
+	createdSecrets    []corev1.Secret
+	createdCRs        []unstructured.Unstructured
+	createErr         error
+	namespaces        []string
+	pods              []corev1.Pod
+	execOutput        string
+	execErr           error
+	execPod           string
+	execStdin         map[string]string
 }
 
 func (s *stubKubectl) GetConfigMap(_ context.Context, _, name string) (*corev1.ConfigMap, error) {
@@ -11,6 +11,7 @@ import (
 
 	updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // NodeVersion bundles version information of a Constellation cluster.
@@ -19,6 +20,11 @@ type NodeVersion struct {
 	imageReference    string
 	kubernetesVersion string
 	clusterStatus     string
+	conditions        []metav1.Condition
+	activeUpgrade     bool
+	outdatedNodes     []string
+	upToDateNodes     []string
+	pendingNodes      []string
 }
 
 // NewNodeVersion returns the target versions for the cluster.
@@ -31,6 +37,11 @@ func NewNodeVersion(nodeVersion updatev1alpha1.NodeVersion) (NodeVersion, error)
 		imageReference:    nodeVersion.Spec.ImageReference,
 		kubernetesVersion: nodeVersion.Spec.KubernetesClusterVersion,
 		clusterStatus:     nodeVersion.Status.Conditions[0].Message,
+		conditions:        nodeVersion.Status.Conditions,
+		activeUpgrade:     nodeVersion.Status.ActiveClusterVersionUpgrade,
+		outdatedNodes:     objectNames(nodeVersion.Status.Outdated),
+		upToDateNodes:     objectNames(nodeVersion.Status.UpToDate),
+		pendingNodes:      objectNames(nodeVersion.Status.Pending),
 	}, nil
 }
 
@@ -54,18 +65,81 @@ func (n NodeVersion) ClusterStatus() string {
 	return n.clusterStatus
 }
 
+// Conditions are the conditions of the NodeVersion resource.
+func (n NodeVersion) Conditions() []metav1.Condition {
+	return n.conditions
+}
+
+// ActiveUpgrade is true if the cluster is currently upgrading.
+func (n NodeVersion) ActiveUpgrade() bool {
+	return n.activeUpgrade
+}
+
+// OutdatedNodes are the names of nodes that are using an outdated image.
+func (n NodeVersion) OutdatedNodes() []string {
+	return n.outdatedNodes
+}
+
+// UpToDateNodes are the names of nodes that are using the latest image.
+func (n NodeVersion) UpToDateNodes() []string {
+	return n.upToDateNodes
+}
+
+// PendingNodes are the names of nodes that are joining or leaving the cluster.
+func (n NodeVersion) PendingNodes() []string {
+	return n.pendingNodes
+}
+
 // NodeStatus bundles status information about a Kubernetes node.
 type NodeStatus struct {
+	name           string
+	controlPlane   bool
+	ready          bool
+	internalIP     string
 	kubeletVersion string
 	imageVersion   string
 }
 
 // NewNodeStatus returns a new NodeStatus.
 func NewNodeStatus(node corev1.Node) NodeStatus {
-	return NodeStatus{
+	status := NodeStatus{
+		name:           node.ObjectMeta.Name,
 		kubeletVersion: node.Status.NodeInfo.KubeletVersion,
 		imageVersion:   node.ObjectMeta.Annotations["constellation.edgeless.systems/node-image"],
 	}
+	_, status.controlPlane = node.ObjectMeta.Labels["node-role.kubernetes.io/control-plane"]
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			status.ready = condition.Status == corev1.ConditionTrue
+		}
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			status.internalIP = address.Address
+			break
+		}
+	}
+	return status
+}
+
+// Name returns the name of the node.
+func (n *NodeStatus) Name() string {
+	return n.name
+}
+
+// ControlPlane is true if the node is a control-plane node.
+func (n *NodeStatus) ControlPlane() bool {
+	return n.controlPlane
+}
+
+// Ready is true if the node reports the Ready condition.
+func (n *NodeStatus) Ready() bool {
+	return n.ready
+}
+
+// InternalIP returns the internal IP address of the node.
+func (n *NodeStatus) InternalIP() string {
+	return n.internalIP
 }
 
 // KubeletVersion returns the kubelet version of the node.
@@ -78,6 +152,14 @@ func (n *NodeStatus) ImageVersion() string {
 	return n.imageVersion
 }
 
+func objectNames(refs []corev1.ObjectReference) []string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
 func updateNodeVersions(newNodeVersion updatev1alpha1.NodeVersion, node *updatev1alpha1.NodeVersion) {
 	if newNodeVersion.Spec.ImageVersion != "" {
 		node.Spec.ImageVersion = newNodeVersion.Spec.ImageVersion
@@ -0,0 +1,98 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package backend implements storage backends for the Constellation state file.
+
+A backend stores the serialized state and serializes concurrent operations on it through a lock.
+Available backends are the local state file in the workspace, an object in an S3-compatible bucket,
+and a Kubernetes Secret or ConfigMap. Any backend can be wrapped with Encrypted to encrypt the state at rest.
+*/
+package backend
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
+)
+
+// ErrConflict is returned when the state was modified by another operation since it was last read.
+var ErrConflict = errors.New("state was modified concurrently")
+
+// Backend stores the state file.
+type Backend interface {
+	// Read returns the stored state.
+	// If no state is stored, the returned error wraps os.ErrNotExist.
+	Read(ctx context.Context) ([]byte, error)
+	// Write stores the state. Backends that support conditional writes
+	// return ErrConflict if the state was modified since it was last read.
+	Write(ctx context.Context, data []byte) error
+	// Delete removes the stored state.
+	// If no state is stored, the returned error wraps os.ErrNotExist.
+	Delete(ctx context.Context) error
+	// Lock acquires the lock of the state.
+	// If the lock is already held, a *LockedError is returned.
+	Lock(ctx context.Context, info LockInfo) error
+	// Unlock releases the lock with the given ID.
+	Unlock(ctx context.Context, id string) error
+}
+
+// LockInfo describes the holder of a state lock.
+type LockInfo struct {
+	// ID uniquely identifies the lock.
+	ID string `json:"id"`
+	// Operation is the CLI operation holding the lock, e.g., "apply".
+	Operation string `json:"operation"`
+	// Who is the user and host holding the lock.
+	Who string `json:"who"`
+	// Created is the time the lock was acquired.
+	Created time.Time `json:"created"`
+}
+
+// NewLockInfo returns the information for a new lock held by the current user for the given operation.
+func NewLockInfo(operation string) (LockInfo, error) {
+	id, err := crypto.GenerateRandomBytes(16)
+	if err != nil {
+		return LockInfo{}, fmt.Errorf("generating lock ID: %w", err)
+	}
+
+	who := "unknown"
+	if u, err := user.Current(); err == nil {
+		who = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		who += "@" + host
+	}
+
+	return LockInfo{
+		ID:        hex.EncodeToString(id),
+		Operation: operation,
+		Who:       who,
+		Created:   time.Now().UTC(),
+	}, nil
+}
+
+// LockedError is returned when the state is locked by another operation.
+type LockedError struct {
+	Info LockInfo
+}
+
+// Error returns the error message.
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("state is locked by %s since %s for operation %q (lock ID %s)",
+		e.Info.Who, e.Info.Created.Format(time.RFC3339), e.Info.Operation, e.Info.ID)
+}
+
+// lockMismatchError returns the error for an unlock of a lock held by someone else.
+func lockMismatchError(id string, held LockInfo) error {
+	return fmt.Errorf("lock ID %s doesn't match the ID of the held lock %s", id, held.ID)
+}
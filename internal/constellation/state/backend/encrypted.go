@@ -0,0 +1,78 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
+)
+
+// encryptedHeader prefixes encrypted states. It is authenticated as additional data.
+var encryptedHeader = []byte("constellation-state:aes-256-gcm:v1\n")
+
+// Encrypted wraps a backend and encrypts the state at rest using AES-256-GCM.
+// Locks are passed to the wrapped backend unencrypted.
+type Encrypted struct {
+	Backend
+	aead cipher.AEAD
+}
+
+// NewEncrypted returns a backend that encrypts the state with the given 32 byte key
+// before storing it in the wrapped backend.
+func NewEncrypted(backend Backend, key []byte) (*Encrypted, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("state encryption key must be 32 bytes long, got %d bytes", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating AEAD: %w", err)
+	}
+	return &Encrypted{Backend: backend, aead: aead}, nil
+}
+
+// Read reads the encrypted state from the wrapped backend and decrypts it.
+func (e *Encrypted) Read(ctx context.Context) ([]byte, error) {
+	data, err := e.Backend.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, encryptedHeader) {
+		return nil, errors.New("state is not encrypted")
+	}
+	data = data[len(encryptedHeader):]
+	if len(data) < e.aead.NonceSize() {
+		return nil, errors.New("encrypted state is too short")
+	}
+	nonce, ciphertext := data[:e.aead.NonceSize()], data[e.aead.NonceSize():]
+	plaintext, err := e.aead.Open(nil, nonce, ciphertext, encryptedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting state, was it encrypted with a different key?: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Write encrypts the state and writes it to the wrapped backend.
+func (e *Encrypted) Write(ctx context.Context, data []byte) error {
+	nonce, err := crypto.GenerateRandomBytes(e.aead.NonceSize())
+	if err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	out := append([]byte{}, encryptedHeader...)
+	out = append(out, nonce...)
+	out = e.aead.Seal(out, nonce, data, encryptedHeader)
+	return e.Backend.Write(ctx, out)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypted(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, 32)
+	otherKey := bytes.Repeat([]byte{0x02}, 32)
+	state := []byte("version: v1\n")
+
+	testCases := map[string]struct {
+		stored  func(t *testing.T, b Backend) // writes the stored state to the underlying backend
+		readKey []byte
+		wantErr bool
+	}{
+		"encrypted with same key": {
+			stored: func(t *testing.T, b Backend) {
+				enc, err := NewEncrypted(b, key)
+				require.NoError(t, err)
+				require.NoError(t, enc.Write(context.Background(), state))
+			},
+			readKey: key,
+		},
+		"encrypted with different key": {
+			stored: func(t *testing.T, b Backend) {
+				enc, err := NewEncrypted(b, otherKey)
+				require.NoError(t, err)
+				require.NoError(t, enc.Write(context.Background(), state))
+			},
+			readKey: key,
+			wantErr: true,
+		},
+		"plaintext state": {
+			stored: func(t *testing.T, b Backend) {
+				require.NoError(t, b.Write(context.Background(), state))
+			},
+			readKey: key,
+			wantErr: true,
+		},
+		"truncated state": {
+			stored: func(t *testing.T, b Backend) {
+				require.NoError(t, b.Write(context.Background(), encryptedHeader))
+			},
+			readKey: key,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			local := NewLocal(file.NewHandler(afero.NewMemMapFs()), "constellation-state.yaml")
+			tc.stored(t, local)
+
+			enc, err := NewEncrypted(local, tc.readKey)
+			require.NoError(err)
+			data, err := enc.Read(context.Background())
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(state, data)
+
+			raw, err := local.Read(context.Background())
+			require.NoError(err)
+			assert.NotContains(string(raw), string(state))
+		})
+	}
+}
+
+func TestNewEncryptedKeyLength(t *testing.T) {
+	_, err := NewEncrypted(nil, make([]byte, 16))
+	assert.Error(t, err)
+}
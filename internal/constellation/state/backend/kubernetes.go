@@ -0,0 +1,236 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// KindSecret stores the state in a Kubernetes Secret.
+	KindSecret = "Secret"
+	// KindConfigMap stores the state in a Kubernetes ConfigMap.
+	KindConfigMap = "ConfigMap"
+
+	// stateDataKey is the key of the state in the data of the Secret or ConfigMap.
+	stateDataKey = "constellation-state.yaml"
+	// lockInfoAnnotation is the annotation of the lock Lease holding the LockInfo.
+	lockInfoAnnotation = "constellation.edgeless.systems/lock-info"
+)
+
+// KubernetesConfig configures the Kubernetes backend.
+type KubernetesConfig struct {
+	// Kubeconfig is the path to the kubeconfig of the cluster storing the state.
+	// If empty, the default loading rules of kubectl are used.
+	Kubeconfig string
+	// Namespace is the namespace of the Secret or ConfigMap.
+	Namespace string
+	// Name is the name of the Secret or ConfigMap. The lock is a Lease named Name + "-lock".
+	Name string
+	// Kind is either KindSecret or KindConfigMap.
+	Kind string
+}
+
+// Kubernetes stores the state in a Kubernetes Secret or ConfigMap.
+// Writes are conditional on the resource version of the last read state,
+// and the lock is a Lease in the same namespace.
+type Kubernetes struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	kind      string
+
+	// resourceVersion is the resource version of the last read or written state, or empty if no state exists.
+	resourceVersion string
+}
+
+// NewKubernetes returns a backend storing the state in a Kubernetes Secret or ConfigMap.
+func NewKubernetes(cfg KubernetesConfig) (*Kubernetes, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = cfg.Kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating k8s client from kubeconfig: %w", err)
+	}
+	return newKubernetes(client, cfg)
+}
+
+func newKubernetes(client kubernetes.Interface, cfg KubernetesConfig) (*Kubernetes, error) {
+	if cfg.Kind != KindSecret && cfg.Kind != KindConfigMap {
+		return nil, fmt.Errorf("unsupported kind %q, expected %s or %s", cfg.Kind, KindSecret, KindConfigMap)
+	}
+	return &Kubernetes{client: client, namespace: cfg.Namespace, name: cfg.Name, kind: cfg.Kind}, nil
+}
+
+// Read returns the state from the Secret or ConfigMap and remembers its resource version for the next write.
+func (k *Kubernetes) Read(ctx context.Context) ([]byte, error) {
+	var data []byte
+	var meta metav1.ObjectMeta
+	switch k.kind {
+	case KindSecret:
+		secret, err := k.client.CoreV1().Secrets(k.namespace).Get(ctx, k.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, k.wrapNotFound(err)
+		}
+		data, meta = secret.Data[stateDataKey], secret.ObjectMeta
+	default:
+		configMap, err := k.client.CoreV1().ConfigMaps(k.namespace).Get(ctx, k.name, metav1.GetOptions{})
+		if err != nil {
+			return nil, k.wrapNotFound(err)
+		}
+		data, meta = configMap.BinaryData[stateDataKey], configMap.ObjectMeta
+	}
+	k.resourceVersion = meta.ResourceVersion
+	return data, nil
+}
+
+// Write creates or updates the Secret or ConfigMap, if it wasn't modified since it was last read.
+func (k *Kubernetes) Write(ctx context.Context, data []byte) error {
+	meta := metav1.ObjectMeta{
+		Name:            k.name,
+		Namespace:       k.namespace,
+		ResourceVersion: k.resourceVersion,
+	}
+
+	var err error
+	switch k.kind {
+	case KindSecret:
+		secret := &corev1.Secret{ObjectMeta: meta, Data: map[string][]byte{stateDataKey: data}}
+		if k.resourceVersion == "" {
+			secret, err = k.client.CoreV1().Secrets(k.namespace).Create(ctx, secret, metav1.CreateOptions{})
+		} else {
+			secret, err = k.client.CoreV1().Secrets(k.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		}
+		if err == nil {
+			k.resourceVersion = secret.ResourceVersion
+		}
+	default:
+		configMap := &corev1.ConfigMap{ObjectMeta: meta, BinaryData: map[string][]byte{stateDataKey: data}}
+		if k.resourceVersion == "" {
+			configMap, err = k.client.CoreV1().ConfigMaps(k.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		} else {
+			configMap, err = k.client.CoreV1().ConfigMaps(k.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+		}
+		if err == nil {
+			k.resourceVersion = configMap.ResourceVersion
+		}
+	}
+	if k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("writing state to %s %s/%s: %w", k.kind, k.namespace, k.name, err)
+	}
+	return nil
+}
+
+// Delete removes the Secret or ConfigMap.
+func (k *Kubernetes) Delete(ctx context.Context) error {
+	var err error
+	switch k.kind {
+	case KindSecret:
+		err = k.client.CoreV1().Secrets(k.namespace).Delete(ctx, k.name, metav1.DeleteOptions{})
+	default:
+		err = k.client.CoreV1().ConfigMaps(k.namespace).Delete(ctx, k.name, metav1.DeleteOptions{})
+	}
+	if err != nil {
+		return k.wrapNotFound(err)
+	}
+	k.resourceVersion = ""
+	return nil
+}
+
+// Lock creates the lock Lease, if it doesn't exist yet.
+func (k *Kubernetes) Lock(ctx context.Context, info LockInfo) error {
+	rawInfo, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshalling lock info: %w", err)
+	}
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        k.lockName(),
+			Namespace:   k.namespace,
+			Annotations: map[string]string{lockInfoAnnotation: string(rawInfo)},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &info.ID,
+			AcquireTime:    &metav1.MicroTime{Time: info.Created},
+		},
+	}
+	_, err = k.client.CoordinationV1().Leases(k.namespace).Create(ctx, lease, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		held, _, err := k.readLock(ctx)
+		if err != nil {
+			return err
+		}
+		return &LockedError{Info: held}
+	}
+	if err != nil {
+		return fmt.Errorf("creating lock Lease %s/%s: %w", k.namespace, k.lockName(), err)
+	}
+	return nil
+}
+
+// Unlock deletes the lock Lease, if it holds the lock with the given ID.
+func (k *Kubernetes) Unlock(ctx context.Context, id string) error {
+	held, uid, err := k.readLock(ctx)
+	if err != nil {
+		return err
+	}
+	if held.ID != id {
+		return lockMismatchError(id, held)
+	}
+	err = k.client.CoordinationV1().Leases(k.namespace).Delete(ctx, k.lockName(), metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil {
+		return fmt.Errorf("deleting lock Lease %s/%s: %w", k.namespace, k.lockName(), err)
+	}
+	return nil
+}
+
+func (k *Kubernetes) readLock(ctx context.Context) (LockInfo, types.UID, error) {
+	lease, err := k.client.CoordinationV1().Leases(k.namespace).Get(ctx, k.lockName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return LockInfo{}, "", fmt.Errorf("lock Lease %s/%s: %w", k.namespace, k.lockName(), os.ErrNotExist)
+	}
+	if err != nil {
+		return LockInfo{}, "", fmt.Errorf("getting lock Lease %s/%s: %w", k.namespace, k.lockName(), err)
+	}
+	var held LockInfo
+	if err := json.Unmarshal([]byte(lease.Annotations[lockInfoAnnotation]), &held); err != nil {
+		return LockInfo{}, "", fmt.Errorf("unmarshalling lock info: %w", err)
+	}
+	return held, lease.UID, nil
+}
+
+func (k *Kubernetes) lockName() string {
+	return k.name + "-lock"
+}
+
+// wrapNotFound wraps NotFound errors of the Kubernetes API with os.ErrNotExist.
+func (k *Kubernetes) wrapNotFound(err error) error {
+	if k8serrors.IsNotFound(err) {
+		return fmt.Errorf("%s %s/%s: %w", k.kind, k.namespace, k.name, os.ErrNotExist)
+	}
+	return err
+}
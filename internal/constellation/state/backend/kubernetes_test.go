@@ -0,0 +1,131 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestKubernetesReadWrite(t *testing.T) {
+	for _, kind := range []string{KindSecret, KindConfigMap} {
+		t.Run(kind, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			ctx := context.Background()
+			client := newFakeClientWithResourceVersions()
+			cfg := KubernetesConfig{Namespace: "default", Name: "constellation-state", Kind: kind}
+			first, err := newKubernetes(client, cfg)
+			require.NoError(err)
+			second, err := newKubernetes(client, cfg)
+			require.NoError(err)
+
+			_, err = first.Read(ctx)
+			assert.ErrorIs(err, os.ErrNotExist)
+
+			// both operations saw no state, only the first one may create it
+			_, err = second.Read(ctx)
+			assert.ErrorIs(err, os.ErrNotExist)
+			require.NoError(first.Write(ctx, []byte("foo")))
+			assert.ErrorIs(second.Write(ctx, []byte("bar")), ErrConflict)
+
+			// updates are conditional on the last read state
+			data, err := second.Read(ctx)
+			require.NoError(err)
+			assert.Equal([]byte("foo"), data)
+			require.NoError(second.Write(ctx, []byte("bar")))
+			assert.ErrorIs(first.Write(ctx, []byte("baz")), ErrConflict)
+			require.NoError(second.Write(ctx, []byte("baz")))
+
+			data, err = first.Read(ctx)
+			require.NoError(err)
+			assert.Equal([]byte("baz"), data)
+
+			require.NoError(first.Delete(ctx))
+			assert.ErrorIs(second.Delete(ctx), os.ErrNotExist)
+		})
+	}
+}
+
+func TestKubernetesLock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	k8s, err := newKubernetes(fake.NewSimpleClientset(), KubernetesConfig{Namespace: "default", Name: "constellation-state", Kind: KindSecret})
+	require.NoError(err)
+
+	first := LockInfo{ID: "first", Operation: "apply", Who: "alice@host"}
+	second := LockInfo{ID: "second", Operation: "apply", Who: "bob@host"}
+
+	require.NoError(k8s.Lock(ctx, first))
+
+	err = k8s.Lock(ctx, second)
+	var lockedErr *LockedError
+	require.ErrorAs(err, &lockedErr)
+	assert.Equal(first.ID, lockedErr.Info.ID)
+
+	assert.Error(k8s.Unlock(ctx, second.ID))
+	require.NoError(k8s.Unlock(ctx, first.ID))
+	require.NoError(k8s.Lock(ctx, second))
+	assert.NoError(k8s.Unlock(ctx, second.ID))
+	assert.ErrorIs(k8s.Unlock(ctx, second.ID), os.ErrNotExist)
+}
+
+func TestNewKubernetesUnsupportedKind(t *testing.T) {
+	_, err := newKubernetes(fake.NewSimpleClientset(), KubernetesConfig{Namespace: "default", Name: "constellation-state", Kind: "Pod"})
+	assert.Error(t, err)
+}
+
+// newFakeClientWithResourceVersions returns a fake clientset that sets resource versions
+// and rejects updates of outdated objects, like the API server does.
+func newFakeClientWithResourceVersions() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	var version int
+	bumpVersion := func(obj runtime.Object) error {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		version++
+		accessor.SetResourceVersion(strconv.Itoa(version))
+		return nil
+	}
+
+	client.PrependReactor("create", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return false, nil, bumpVersion(action.(k8stesting.CreateAction).GetObject())
+	})
+	client.PrependReactor("update", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := action.(k8stesting.UpdateAction).GetObject()
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		current, err := client.Tracker().Get(action.GetResource(), action.GetNamespace(), accessor.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		currentAccessor, err := meta.Accessor(current)
+		if err != nil {
+			return true, nil, err
+		}
+		if currentAccessor.GetResourceVersion() != accessor.GetResourceVersion() {
+			return true, nil, k8serrors.NewConflict(action.GetResource().GroupResource(), accessor.GetName(), nil)
+		}
+		return false, nil, bumpVersion(obj)
+	})
+	return client
+}
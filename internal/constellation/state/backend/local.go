@@ -0,0 +1,78 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+)
+
+// Local stores the state in a file of the local workspace.
+// The lock is a file next to the state file, which is created exclusively.
+type Local struct {
+	fileHandler file.Handler
+	path        string
+}
+
+// NewLocal returns a backend storing the state in the file at path.
+func NewLocal(fileHandler file.Handler, path string) *Local {
+	return &Local{fileHandler: fileHandler, path: path}
+}
+
+// Read returns the content of the state file.
+func (l *Local) Read(_ context.Context) ([]byte, error) {
+	return l.fileHandler.Read(l.path)
+}
+
+// Write writes the state file, overwriting any existing file.
+func (l *Local) Write(_ context.Context, data []byte) error {
+	return l.fileHandler.Write(l.path, data, file.OptMkdirAll, file.OptOverwrite)
+}
+
+// Delete removes the state file.
+func (l *Local) Delete(_ context.Context) error {
+	return l.fileHandler.Remove(l.path)
+}
+
+// Lock creates the lock file. It fails if the lock file already exists.
+func (l *Local) Lock(_ context.Context, info LockInfo) error {
+	err := l.fileHandler.WriteJSON(l.lockPath(), info, file.OptMkdirAll)
+	if errors.Is(err, fs.ErrExist) {
+		var held LockInfo
+		if err := l.fileHandler.ReadJSON(l.lockPath(), &held); err != nil {
+			return fmt.Errorf("reading lock file: %w", err)
+		}
+		return &LockedError{Info: held}
+	}
+	if err != nil {
+		return fmt.Errorf("writing lock file: %w", err)
+	}
+	return nil
+}
+
+// Unlock removes the lock file, if it holds the lock with the given ID.
+func (l *Local) Unlock(_ context.Context, id string) error {
+	var held LockInfo
+	if err := l.fileHandler.ReadJSON(l.lockPath(), &held); err != nil {
+		return fmt.Errorf("reading lock file: %w", err)
+	}
+	if held.ID != id {
+		return lockMismatchError(id, held)
+	}
+	if err := l.fileHandler.Remove(l.lockPath()); err != nil {
+		return fmt.Errorf("removing lock file: %w", err)
+	}
+	return nil
+}
+
+func (l *Local) lockPath() string {
+	return l.path + ".lock"
+}
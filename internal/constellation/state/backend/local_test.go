@@ -0,0 +1,67 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestLocalReadWrite(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	local := NewLocal(file.NewHandler(afero.NewMemMapFs()), "workspace/constellation-state.yaml")
+
+	_, err := local.Read(ctx)
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	require.NoError(local.Write(ctx, []byte("foo")))
+	require.NoError(local.Write(ctx, []byte("bar")))
+	data, err := local.Read(ctx)
+	require.NoError(err)
+	assert.Equal([]byte("bar"), data)
+
+	require.NoError(local.Delete(ctx))
+	_, err = local.Read(ctx)
+	assert.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestLocalLock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	local := NewLocal(file.NewHandler(afero.NewMemMapFs()), "constellation-state.yaml")
+
+	first := LockInfo{ID: "first", Operation: "apply", Who: "alice@host"}
+	second := LockInfo{ID: "second", Operation: "apply", Who: "bob@host"}
+
+	require.NoError(local.Lock(ctx, first))
+
+	err := local.Lock(ctx, second)
+	var lockedErr *LockedError
+	require.ErrorAs(err, &lockedErr)
+	assert.Equal(first.ID, lockedErr.Info.ID)
+	assert.Equal(first.Who, lockedErr.Info.Who)
+
+	assert.Error(local.Unlock(ctx, second.ID))
+	require.NoError(local.Unlock(ctx, first.ID))
+	require.NoError(local.Lock(ctx, second))
+	assert.Error(local.Unlock(ctx, "unknown"))
+	assert.NoError(local.Unlock(ctx, second.ID))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+type s3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// S3Config configures the S3 backend.
+type S3Config struct {
+	// Bucket is the name of the bucket.
+	Bucket string
+	// Key is the object key of the state. The lock is stored at Key + ".lock".
+	Key string
+	// Region is the region of the bucket.
+	Region string
+	// Endpoint is an optional custom endpoint of an S3-compatible object storage.
+	Endpoint string
+	// UsePathStyle addresses the bucket in the path instead of the host name.
+	UsePathStyle bool
+}
+
+// S3 stores the state as an object in an S3-compatible bucket.
+// Writes are conditional on the ETag of the last read state, and the lock is
+// an object that is created with a conditional write, so the object storage
+// must support the If-Match and If-None-Match headers for PutObject.
+type S3 struct {
+	client s3API
+	bucket string
+	key    string
+
+	// etag is the ETag of the last read or written state, or nil if no state exists.
+	etag *string
+}
+
+// NewS3 returns a backend storing the state in an S3-compatible bucket.
+// Credentials are loaded from the default AWS credential chain.
+func NewS3(ctx context.Context, cfg S3Config) (*S3, error) {
+	clientCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS S3 client config: %w", err)
+	}
+	client := s3.NewFromConfig(clientCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+	return &S3{client: client, bucket: cfg.Bucket, key: cfg.Key}, nil
+}
+
+// Read downloads the state object and remembers its ETag for the next write.
+func (s *S3) Read(ctx context.Context) ([]byte, error) {
+	data, etag, err := s.getObject(ctx, s.key)
+	if err != nil {
+		return nil, err
+	}
+	s.etag = etag
+	return data, nil
+}
+
+// Write uploads the state object, if it wasn't modified since it was last read.
+func (s *S3) Write(ctx context.Context, data []byte) error {
+	precondition := smithyhttp.SetHeaderValue("If-None-Match", "*")
+	if s.etag != nil {
+		precondition = smithyhttp.SetHeaderValue("If-Match", *s.etag)
+	}
+	out, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &s.bucket,
+		Key:    &s.key,
+		Body:   bytes.NewReader(data),
+	}, s3.WithAPIOptions(precondition))
+	if isPreconditionFailed(err) {
+		return ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("uploading state to s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	s.etag = out.ETag
+	return nil
+}
+
+// Delete removes the state object.
+func (s *S3) Delete(ctx context.Context) error {
+	if _, _, err := s.getObject(ctx, s.key); err != nil {
+		return err
+	}
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &s.bucket, Key: &s.key}); err != nil {
+		return fmt.Errorf("deleting state from s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	s.etag = nil
+	return nil
+}
+
+// Lock creates the lock object, if it doesn't exist yet.
+func (s *S3) Lock(ctx context.Context, info LockInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("marshalling lock info: %w", err)
+	}
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &s.bucket,
+		Key:    aws.String(s.lockKey()),
+		Body:   bytes.NewReader(data),
+	}, s3.WithAPIOptions(smithyhttp.SetHeaderValue("If-None-Match", "*")))
+	if isPreconditionFailed(err) {
+		held, err := s.readLock(ctx)
+		if err != nil {
+			return err
+		}
+		return &LockedError{Info: held}
+	}
+	if err != nil {
+		return fmt.Errorf("uploading lock to s3://%s/%s: %w", s.bucket, s.lockKey(), err)
+	}
+	return nil
+}
+
+// Unlock deletes the lock object, if it holds the lock with the given ID.
+func (s *S3) Unlock(ctx context.Context, id string) error {
+	held, err := s.readLock(ctx)
+	if err != nil {
+		return err
+	}
+	if held.ID != id {
+		return lockMismatchError(id, held)
+	}
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &s.bucket, Key: aws.String(s.lockKey())}); err != nil {
+		return fmt.Errorf("deleting lock from s3://%s/%s: %w", s.bucket, s.lockKey(), err)
+	}
+	return nil
+}
+
+func (s *S3) readLock(ctx context.Context) (LockInfo, error) {
+	data, _, err := s.getObject(ctx, s.lockKey())
+	if err != nil {
+		return LockInfo{}, err
+	}
+	var held LockInfo
+	if err := json.Unmarshal(data, &held); err != nil {
+		return LockInfo{}, fmt.Errorf("unmarshalling lock info: %w", err)
+	}
+	return held, nil
+}
+
+func (s *S3) getObject(ctx context.Context, key string) ([]byte, *string, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &s.bucket, Key: &key})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, nil, fmt.Errorf("s3://%s/%s: %w", s.bucket, key, os.ErrNotExist)
+		}
+		return nil, nil, fmt.Errorf("downloading s3://%s/%s: %w", s.bucket, key, err)
+	}
+	defer out.Body.Close()
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading s3://%s/%s: %w", s.bucket, key, err)
+	}
+	return data, out.ETag, nil
+}
+
+func (s *S3) lockKey() string {
+	return s.key + ".lock"
+}
+
+// isPreconditionFailed returns true if a conditional write failed.
+// Some S3-compatible object storages return 409 Conflict instead of 412 Precondition Failed.
+func isPreconditionFailed(err error) bool {
+	var respErr *smithyhttp.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	return respErr.HTTPStatusCode() == http.StatusPreconditionFailed || respErr.HTTPStatusCode() == http.StatusConflict
+}
@@ -0,0 +1,154 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3ReadWrite(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	store := newStubS3Store()
+	first := &S3{client: store, bucket: "bucket", key: "state.yaml"}
+	second := &S3{client: store, bucket: "bucket", key: "state.yaml"}
+
+	_, err := first.Read(ctx)
+	assert.ErrorIs(err, os.ErrNotExist)
+
+	// both operations saw no state, only the first one may create it
+	_, err = second.Read(ctx)
+	assert.ErrorIs(err, os.ErrNotExist)
+	require.NoError(first.Write(ctx, []byte("foo")))
+	assert.ErrorIs(second.Write(ctx, []byte("bar")), ErrConflict)
+
+	// updates are conditional on the last read state
+	data, err := second.Read(ctx)
+	require.NoError(err)
+	assert.Equal([]byte("foo"), data)
+	require.NoError(second.Write(ctx, []byte("bar")))
+	assert.ErrorIs(first.Write(ctx, []byte("baz")), ErrConflict)
+	require.NoError(second.Write(ctx, []byte("baz")))
+
+	data, err = first.Read(ctx)
+	require.NoError(err)
+	assert.Equal([]byte("baz"), data)
+
+	require.NoError(first.Delete(ctx))
+	assert.ErrorIs(second.Delete(ctx), os.ErrNotExist)
+}
+
+func TestS3Lock(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	s3 := &S3{client: newStubS3Store(), bucket: "bucket", key: "state.yaml"}
+
+	first := LockInfo{ID: "first", Operation: "apply", Who: "alice@host"}
+	second := LockInfo{ID: "second", Operation: "apply", Who: "bob@host"}
+
+	require.NoError(s3.Lock(ctx, first))
+
+	err := s3.Lock(ctx, second)
+	var lockedErr *LockedError
+	require.ErrorAs(err, &lockedErr)
+	assert.Equal(first.ID, lockedErr.Info.ID)
+
+	assert.Error(s3.Unlock(ctx, second.ID))
+	require.NoError(s3.Unlock(ctx, first.ID))
+	require.NoError(s3.Lock(ctx, second))
+	assert.NoError(s3.Unlock(ctx, second.ID))
+	assert.ErrorIs(s3.Unlock(ctx, second.ID), os.ErrNotExist)
+}
+
+// stubS3Store is an in-memory object store that evaluates the conditional headers of PutObject.
+type stubS3Store struct {
+	objects map[string][]byte
+	etags   map[string]string
+	version int
+}
+
+func newStubS3Store() *stubS3Store {
+	return &stubS3Store{objects: map[string][]byte{}, etags: map[string]string{}}
+}
+
+func (s *stubS3Store) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	data, ok := s.objects[*in.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	etag := s.etags[*in.Key]
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data)), ETag: &etag}, nil
+}
+
+func (s *stubS3Store) PutObject(ctx context.Context, in *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	header, err := requestHeader(ctx, optFns)
+	if err != nil {
+		return nil, err
+	}
+	etag, exists := s.etags[*in.Key]
+	if (header.Get("If-None-Match") == "*" && exists) ||
+		(header.Get("If-Match") != "" && header.Get("If-Match") != etag) {
+		return nil, &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusPreconditionFailed}}}
+	}
+
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.version++
+	etag = strconv.Itoa(s.version)
+	s.objects[*in.Key] = data
+	s.etags[*in.Key] = etag
+	return &s3.PutObjectOutput{ETag: &etag}, nil
+}
+
+func (s *stubS3Store) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(s.objects, *in.Key)
+	delete(s.etags, *in.Key)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+// requestHeader runs the API options of a call on an empty request and returns its headers.
+func requestHeader(ctx context.Context, optFns []func(*s3.Options)) (http.Header, error) {
+	var opts s3.Options
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+	stack := middleware.NewStack("stub", smithyhttp.NewStackRequest)
+	for _, fn := range opts.APIOptions {
+		if err := fn(stack); err != nil {
+			return nil, err
+		}
+	}
+
+	var header http.Header
+	client := smithyhttp.ClientDoFunc(func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}, nil
+	})
+	handler := middleware.DecorateHandler(smithyhttp.NewClientHandler(client), stack)
+	if _, _, err := handler.Handle(ctx, nil); err != nil {
+		return nil, fmt.Errorf("running API options: %w", err)
+	}
+	return header, nil
+}
@@ -13,6 +13,7 @@ SPDX-License-Identifier: AGPL-3.0-only
 package state
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -20,8 +21,11 @@ import (
 
 	"dario.cat/mergo"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/validation"
+	"github.com/siderolabs/talos/pkg/machinery/config/encoder"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -71,6 +75,20 @@ func CreateOrRead(fileHandler file.Handler, path string) (*State, error) {
 	return state, nil
 }
 
+// ReadFromBackend reads the state from the given backend.
+// If the backend holds no state, the returned error wraps os.ErrNotExist.
+func ReadFromBackend(ctx context.Context, b backend.Backend) (*State, error) {
+	data, err := b.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+	state := &State{}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unmarshalling state: %w", err)
+	}
+	return state, nil
+}
+
 // State describe the entire state to describe a Constellation cluster.
 type State struct {
 	// description: |
@@ -198,6 +216,18 @@ func (s *State) WriteToFile(fileHandler file.Handler, path string) error {
 	return nil
 }
 
+// WriteToBackend writes the state to the given backend.
+func (s *State) WriteToBackend(ctx context.Context, b backend.Backend) error {
+	data, err := encoder.NewEncoder(s).Encode()
+	if err != nil {
+		return fmt.Errorf("marshalling state: %w", err)
+	}
+	if err := b.Write(ctx, data); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	return nil
+}
+
 // Merge merges the state information from other into the current state.
 // If a field is set in both states, the value of the other state is used.
 func (s *State) Merge(other *State) (*State, error) {
@@ -7,9 +7,13 @@ SPDX-License-Identifier: AGPL-3.0-only
 package state
 
 import (
+	"bytes"
+	"context"
+	"os"
 	"testing"
 
 	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state/backend"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/siderolabs/talos/pkg/machinery/config/encoder"
 	"github.com/spf13/afero"
@@ -459,3 +463,46 @@ func TestCreateOrRead(t *testing.T) {
 		})
 	}
 }
+
+func TestReadWriteBackend(t *testing.T) {
+	testCases := map[string]struct {
+		state   *State
+		encrypt bool
+	}{
+		"plaintext": {
+			state: defaultState(),
+		},
+		"encrypted": {
+			state:   defaultState(),
+			encrypt: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+			ctx := context.Background()
+
+			fh := file.NewHandler(afero.NewMemMapFs())
+			var b backend.Backend = backend.NewLocal(fh, constants.StateFilename)
+			if tc.encrypt {
+				var err error
+				b, err = backend.NewEncrypted(b, bytes.Repeat([]byte{0x01}, 32))
+				require.NoError(err)
+			}
+
+			_, err := ReadFromBackend(ctx, b)
+			assert.ErrorIs(err, os.ErrNotExist)
+
+			require.NoError(tc.state.WriteToBackend(ctx, b))
+			state, err := ReadFromBackend(ctx, b)
+			require.NoError(err)
+			assert.YAMLEq(mustMarshalYaml(require, tc.state), mustMarshalYaml(require, state))
+
+			if !tc.encrypt {
+				assert.YAMLEq(mustMarshalYaml(require, tc.state), mustReadFromFile(require, fh))
+			}
+		})
+	}
+}
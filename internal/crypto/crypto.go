@@ -47,6 +47,15 @@ func DeriveKey(secret, salt, info []byte, length uint) ([]byte, error) {
 	return key, nil
 }
 
+// StateDiskKeyID returns the DEK ID of the state disk key with the given version.
+// Version 0 is the initial key of a disk, and uses the disk UUID as ID.
+func StateDiskKeyID(diskUUID string, version uint32) string {
+	if version == 0 {
+		return diskUUID
+	}
+	return fmt.Sprintf("%s-v%d", diskUUID, version)
+}
+
 // GenerateCertificateSerialNumber generates a random serial number for an X.509 certificate.
 func GenerateCertificateSerialNumber() (*big.Int, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
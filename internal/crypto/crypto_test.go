@@ -91,6 +91,14 @@ func TestVectorsHKDF(t *testing.T) {
 	}
 }
 
+func TestStateDiskKeyID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("uuid", StateDiskKeyID("uuid", 0))
+	assert.Equal("uuid-v1", StateDiskKeyID("uuid", 1))
+	assert.NotEqual(StateDiskKeyID("uuid", 1), StateDiskKeyID("uuid", 2))
+}
+
 func TestGenerateCertificateSerialNumber(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
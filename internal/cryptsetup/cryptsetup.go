@@ -20,6 +20,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 )
 
 const (
@@ -50,17 +52,21 @@ var (
 
 // CryptSetup manages encrypted devices.
 type CryptSetup struct {
-	nameInit func(name string) (cryptDevice, error)
-	pathInit func(path string) (cryptDevice, error)
-	device   cryptDevice
+	nameInit   func(name string) (cryptDevice, error)
+	pathInit   func(path string) (cryptDevice, error)
+	paramsInit func(name string) (profile.Profile, error)
+	device     cryptDevice
+	// mappedName is the name of the active device opened by InitByName.
+	mappedName string
 }
 
 // New creates a new CryptSetup.
 // Before first use, call Init() or InitByName() to open a crypt device.
 func New() *CryptSetup {
 	return &CryptSetup{
-		nameInit: initByName,
-		pathInit: initByDevicePath,
+		nameInit:   initByName,
+		pathInit:   initByDevicePath,
+		paramsInit: encryptionParameters,
 	}
 }
 
@@ -91,6 +97,7 @@ func (c *CryptSetup) InitByName(name string) (free func(), err error) {
 		return nil, fmt.Errorf("init cryptsetup by name %q: %w", name, err)
 	}
 	c.device = device
+	c.mappedName = name
 	return c.Free, nil
 }
 
@@ -102,6 +109,7 @@ func (c *CryptSetup) Free() {
 		c.device.Free()
 		c.device = nil
 	}
+	c.mappedName = ""
 }
 
 // ActivateByPassphrase actives a crypt device using a passphrase.
@@ -147,12 +155,25 @@ func (c *CryptSetup) Deactivate(deviceName string) error {
 // Format formats a disk as a LUKS2 crypt device.
 // Optionally set integrity to true to enable dm-integrity for the device.
 func (c *CryptSetup) Format(integrity bool) error {
+	profileName := profile.AESXTS
+	if integrity {
+		profileName = profile.AESXTSHMACSHA256
+	}
+	encryptionProfile, err := profile.Get(profileName)
+	if err != nil {
+		return err
+	}
+	return c.FormatWithProfile(encryptionProfile)
+}
+
+// FormatWithProfile formats a disk as a LUKS2 crypt device using the given encryption profile.
+func (c *CryptSetup) FormatWithProfile(encryptionProfile profile.Profile) error {
 	packageLock.Lock()
 	defer packageLock.Unlock()
 	if c.device == nil {
 		return errDeviceNotOpen
 	}
-	if err := format(c.device, integrity); err != nil {
+	if err := format(c.device, encryptionProfile); err != nil {
 		return fmt.Errorf("formatting crypt device %q: %w", c.device.GetDeviceName(), err)
 	}
 	return nil
@@ -263,33 +284,95 @@ func (c *CryptSetup) TokenJSONSet(token int, json string) (int, error) {
 }
 
 // SetConstellationStateDiskToken sets the Constellation state disk token.
+// The key version recorded in an existing token is preserved.
 func (c *CryptSetup) SetConstellationStateDiskToken(diskIsInitialized bool) error {
+	token := c.constellationStateDiskToken()
+	token.DiskIsInitialized = diskIsInitialized
+	return c.setConstellationStateDiskToken(token)
+}
+
+// ConstellationStateDiskTokenIsInitialized returns true if the Constellation state disk token is set to initialized.
+func (c *CryptSetup) ConstellationStateDiskTokenIsInitialized() bool {
+	return c.constellationStateDiskToken().DiskIsInitialized
+}
+
+// EncryptionParameters returns the cipher, cipher mode, volume key size, integrity algorithm, and sector size
+// of the active crypt device, as reported by libcryptsetup.
+// Unlike the profile name recorded in the Constellation state disk token, these are the parameters used by the kernel to map the device.
+// Only works for devices opened with InitByName.
+func (c *CryptSetup) EncryptionParameters() (profile.Profile, error) {
+	packageLock.Lock()
+	defer packageLock.Unlock()
+	if c.device == nil {
+		return profile.Profile{}, errDeviceNotOpen
+	}
+	if c.mappedName == "" {
+		return profile.Profile{}, errors.New("encryption parameters are only available for active devices opened by name")
+	}
+	params, err := c.paramsInit(c.mappedName)
+	if err != nil {
+		return profile.Profile{}, fmt.Errorf("getting encryption parameters of crypt device %q: %w", c.mappedName, err)
+	}
+	return params, nil
+}
+
+// SetConstellationStateDiskProfile records the name of the encryption profile the state disk was formatted with
+// in the Constellation state disk token.
+func (c *CryptSetup) SetConstellationStateDiskProfile(name string) error {
+	token := c.constellationStateDiskToken()
+	token.Profile = name
+	return c.setConstellationStateDiskToken(token)
+}
+
+// ConstellationStateDiskProfile returns the name of the encryption profile the state disk was formatted with.
+// Disks formatted before profiles were recorded return an empty string, which refers to the default profile.
+func (c *CryptSetup) ConstellationStateDiskProfile() string {
+	return c.constellationStateDiskToken().Profile
+}
+
+// SetConstellationStateDiskKeyVersion records the version of the state disk's passphrase in the Constellation state disk token.
+func (c *CryptSetup) SetConstellationStateDiskKeyVersion(version uint32) error {
+	token := c.constellationStateDiskToken()
+	token.KeyVersion = version
+	return c.setConstellationStateDiskToken(token)
+}
+
+// ConstellationStateDiskKeyVersion returns the version of the state disk's passphrase.
+// Disks without a recorded version use the initial key version 0.
+func (c *CryptSetup) ConstellationStateDiskKeyVersion() uint32 {
+	return c.constellationStateDiskToken().KeyVersion
+}
+
+// constellationStateDiskToken returns the Constellation state disk token.
+// If the token can't be read, an empty token is returned.
+func (c *CryptSetup) constellationStateDiskToken() constellationLUKS2Token {
 	token := constellationLUKS2Token{
-		Type:              "constellation-state-disk",
-		Keyslots:          []string{},
-		DiskIsInitialized: diskIsInitialized,
+		Type:     "constellation-state-disk",
+		Keyslots: []string{},
 	}
-	json, err := json.Marshal(token)
+	stateDiskToken, err := c.device.TokenJSONGet(ConstellationStateDiskTokenID)
 	if err != nil {
-		return fmt.Errorf("marshaling token: %w", err)
+		return token
 	}
-	if _, err := c.device.TokenJSONSet(ConstellationStateDiskTokenID, string(json)); err != nil {
-		return fmt.Errorf("setting token: %w", err)
+	var existing constellationLUKS2Token
+	if err := json.Unmarshal([]byte(stateDiskToken), &existing); err != nil {
+		return token
 	}
-	return nil
+	token.DiskIsInitialized = existing.DiskIsInitialized
+	token.KeyVersion = existing.KeyVersion
+	token.Profile = existing.Profile
+	return token
 }
 
-// ConstellationStateDiskTokenIsInitialized returns true if the Constellation state disk token is set to initialized.
-func (c *CryptSetup) ConstellationStateDiskTokenIsInitialized() bool {
-	stateDiskToken, err := c.device.TokenJSONGet(ConstellationStateDiskTokenID)
+func (c *CryptSetup) setConstellationStateDiskToken(token constellationLUKS2Token) error {
+	json, err := json.Marshal(token)
 	if err != nil {
-		return false
+		return fmt.Errorf("marshaling token: %w", err)
 	}
-	var token constellationLUKS2Token
-	if err := json.Unmarshal([]byte(stateDiskToken), &token); err != nil {
-		return false
+	if _, err := c.device.TokenJSONSet(ConstellationStateDiskTokenID, string(json)); err != nil {
+		return fmt.Errorf("setting token: %w", err)
 	}
-	return token.DiskIsInitialized
+	return nil
 }
 
 // Wipe overwrites the device with zeros to initialize integrity checksums.
@@ -340,6 +423,8 @@ type constellationLUKS2Token struct {
 	Type              string   `json:"type"`
 	Keyslots          []string `json:"keyslots"`
 	DiskIsInitialized bool     `json:"diskIsInitialized"`
+	KeyVersion        uint32   `json:"keyVersion,omitempty"`
+	Profile           string   `json:"profile,omitempty"`
 }
 
 type cryptDevice interface {
@@ -8,11 +8,15 @@ SPDX-License-Identifier: AGPL-3.0-only
 package cryptsetup
 
 // #include <libcryptsetup.h>
+// #include <stdlib.h>
 import "C"
 
 import (
 	"errors"
+	"fmt"
+	"unsafe"
 
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 	"github.com/martinjungblut/go-cryptsetup"
 )
 
@@ -25,29 +29,24 @@ const (
 
 var errInvalidType = errors.New("device is not a *cryptsetup.Device")
 
-func format(device cryptDevice, integrity bool) error {
+func format(device cryptDevice, encryptionProfile profile.Profile) error {
 	switch d := device.(type) {
 	case cgoFormatter:
 		luks2Params := cryptsetup.LUKS2{
-			SectorSize: 4096,
+			SectorSize: uint32(encryptionProfile.SectorSize),
+			Integrity:  encryptionProfile.Integrity,
 			PBKDFType: &cryptsetup.PbkdfType{
-				// Use low memory recommendation from https://datatracker.ietf.org/doc/html/rfc9106#section-7
-				Type:            "argon2id",
-				TimeMs:          2000,
-				Iterations:      3,
-				ParallelThreads: 4,
-				MaxMemoryKb:     65536, // ~64MiB
+				Type:            encryptionProfile.PBKDF.Type,
+				TimeMs:          encryptionProfile.PBKDF.TimeMs,
+				Iterations:      encryptionProfile.PBKDF.Iterations,
+				ParallelThreads: encryptionProfile.PBKDF.ParallelThreads,
+				MaxMemoryKb:     encryptionProfile.PBKDF.MaxMemoryKb,
 			},
 		}
 		genericParams := cryptsetup.GenericParams{
-			Cipher:        "aes",
-			CipherMode:    "xts-plain64",
-			VolumeKeySize: 64, // 32*2 bytes for aes-xts-plain64 encryption
-		}
-
-		if integrity {
-			luks2Params.Integrity = "hmac(sha256)"
-			genericParams.VolumeKeySize += 32 // 32 bytes for hmac(sha256) integrity
+			Cipher:        encryptionProfile.Cipher,
+			CipherMode:    encryptionProfile.CipherMode,
+			VolumeKeySize: encryptionProfile.VolumeKeySize,
 		}
 
 		return d.Format(luks2Params, genericParams)
@@ -64,6 +63,32 @@ func initByName(name string) (cryptDevice, error) {
 	return cryptsetup.InitByName(name)
 }
 
+// encryptionParameters reads the encryption parameters of the active crypt device with the given mapped name.
+// go-cryptsetup doesn't expose the parameter getters of libcryptsetup, so a separate context is used.
+func encryptionParameters(name string) (profile.Profile, error) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+
+	var cd *C.struct_crypt_device
+	if res := C.crypt_init_by_name(&cd, cName); res < 0 {
+		return profile.Profile{}, fmt.Errorf("crypt_init_by_name returned error code %d", int(res))
+	}
+	defer C.crypt_free(cd)
+
+	params := profile.Profile{
+		Cipher:        C.GoString(C.crypt_get_cipher(cd)),
+		CipherMode:    C.GoString(C.crypt_get_cipher_mode(cd)),
+		VolumeKeySize: int(C.crypt_get_volume_key_size(cd)),
+		SectorSize:    int(C.crypt_get_sector_size(cd)),
+	}
+	// crypt_get_integrity_info fails for devices without integrity protection
+	var integrity C.struct_crypt_params_integrity
+	if res := C.crypt_get_integrity_info(cd, &integrity); res == 0 && integrity.integrity != nil {
+		params.Integrity = C.GoString(integrity.integrity)
+	}
+	return params, nil
+}
+
 func loadLUKS2(device cryptDevice) error {
 	switch d := device.(type) {
 	case cgoLoader:
@@ -9,6 +9,8 @@ package cryptsetup
 
 import (
 	"errors"
+
+	"github.com/edgelesssys/constellation/v2/internal/cryptsetup/profile"
 )
 
 const (
@@ -22,7 +24,7 @@ const (
 
 var errCGONotSupported = errors.New("using cryptsetup requires building with CGO")
 
-func format(_ cryptDevice, _ bool) error {
+func format(_ cryptDevice, _ profile.Profile) error {
 	return errCGONotSupported
 }
 
@@ -34,6 +36,10 @@ func initByName(_ string) (cryptDevice, error) {
 	return nil, errCGONotSupported
 }
 
+func encryptionParameters(_ string) (profile.Profile, error) {
+	return profile.Profile{}, errCGONotSupported
+}
+
 func loadLUKS2(_ cryptDevice) error {
 	return errCGONotSupported
 }
@@ -0,0 +1,180 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package profile defines the encryption profiles available for Constellation's state disks.
+
+A profile bundles the cipher, the integrity algorithm, the sector size and the PBKDF used to format a LUKS2 device.
+Profiles are referenced by name in the Constellation config, and the name is recorded in the LUKS2 token of the state disk.
+
+This package doesn't depend on libcryptsetup, so it can be used by the CLI.
+*/
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// AESXTSHMACSHA256 encrypts with AES-XTS and protects the integrity of data with HMAC-SHA256, using 4 KiB sectors.
+	AESXTSHMACSHA256 = "aes-xts-hmac-sha256"
+	// AESXTS encrypts with AES-XTS without integrity protection, using 4 KiB sectors.
+	// Use this profile if the filesystem or the application already protects the integrity of data.
+	AESXTS = "aes-xts"
+	// AESXTS512 encrypts with AES-XTS without integrity protection, using 512 byte sectors.
+	// Use this profile for workloads with many small, unaligned writes.
+	AESXTS512 = "aes-xts-512"
+
+	// Default is the profile used if no profile is configured.
+	Default = AESXTSHMACSHA256
+)
+
+// Profile describes the parameters used to format a LUKS2 device.
+type Profile struct {
+	// Name of the profile.
+	Name string
+	// Cipher is the block cipher used for encryption, e.g. "aes".
+	Cipher string
+	// CipherMode is the mode of operation of the cipher, e.g. "xts-plain64".
+	CipherMode string
+	// VolumeKeySize is the size of the volume key in bytes, including the key used for integrity protection.
+	VolumeKeySize int
+	// Integrity is the kernel name of the integrity algorithm, e.g. "hmac(sha256)".
+	// An empty string disables integrity protection.
+	Integrity string
+	// SectorSize is the encryption sector size in bytes.
+	SectorSize int
+	// PBKDF configures the key derivation function used for keyslots.
+	PBKDF PBKDF
+}
+
+// PBKDF configures the password-based key derivation function of LUKS2 keyslots.
+type PBKDF struct {
+	// Type of the PBKDF, e.g. "argon2id".
+	Type string
+	// TimeMs is the time the PBKDF should take to compute in milliseconds.
+	TimeMs uint32
+	// Iterations is the number of iterations of the PBKDF.
+	Iterations uint32
+	// MaxMemoryKb is the maximum amount of memory the PBKDF may use in KiB.
+	MaxMemoryKb uint32
+	// ParallelThreads is the number of threads used to compute the PBKDF.
+	ParallelThreads uint32
+}
+
+// HasIntegrity returns true if the profile protects the integrity of data.
+func (p Profile) HasIntegrity() bool {
+	return p.Integrity != ""
+}
+
+// CrypttabOptions returns the options used to map a device with this profile in a crypttab entry.
+func (p Profile) CrypttabOptions() string {
+	options := fmt.Sprintf("cipher=%s-%s", p.Cipher, p.CipherMode)
+	if p.HasIntegrity() {
+		// crypttab uses the cryptsetup notation, e.g. hmac-sha256 instead of hmac(sha256).
+		integrity := strings.NewReplacer("(", "-", ")", "").Replace(p.Integrity)
+		options += ",integrity=" + integrity
+	}
+	return options
+}
+
+// argon2idLowMemory uses the low memory recommendation from https://datatracker.ietf.org/doc/html/rfc9106#section-7
+var argon2idLowMemory = PBKDF{
+	Type:            "argon2id",
+	TimeMs:          2000,
+	Iterations:      3,
+	MaxMemoryKb:     65536, // ~64MiB
+	ParallelThreads: 4,
+}
+
+var profiles = map[string]Profile{
+	AESXTSHMACSHA256: {
+		Name:          AESXTSHMACSHA256,
+		Cipher:        "aes",
+		CipherMode:    "xts-plain64",
+		VolumeKeySize: 64 + 32, // 32*2 bytes for aes-xts-plain64 encryption, 32 bytes for hmac(sha256) integrity
+		Integrity:     "hmac(sha256)",
+		SectorSize:    4096,
+		PBKDF:         argon2idLowMemory,
+	},
+	AESXTS: {
+		Name:          AESXTS,
+		Cipher:        "aes",
+		CipherMode:    "xts-plain64",
+		VolumeKeySize: 64, // 32*2 bytes for aes-xts-plain64 encryption
+		SectorSize:    4096,
+		PBKDF:         argon2idLowMemory,
+	},
+	AESXTS512: {
+		Name:          AESXTS512,
+		Cipher:        "aes",
+		CipherMode:    "xts-plain64",
+		VolumeKeySize: 64, // 32*2 bytes for aes-xts-plain64 encryption
+		SectorSize:    512,
+		PBKDF:         argon2idLowMemory,
+	},
+}
+
+// Get returns the profile with the given name.
+// An empty name returns the default profile.
+func Get(name string) (Profile, error) {
+	if name == "" {
+		name = Default
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown disk encryption profile %q, supported profiles are: %s", name, strings.Join(Names(), ", "))
+	}
+	return profile, nil
+}
+
+// Names returns the names of all available profiles, sorted alphabetically.
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Equal returns true if the profile names a and b refer to the same profile.
+// An empty name refers to the default profile.
+func Equal(a, b string) bool {
+	if a == "" {
+		a = Default
+	}
+	if b == "" {
+		b = Default
+	}
+	return a == b
+}
+
+// Verify checks that the parameters of a crypt device match the profile with the given name.
+// Only the parameters used to map the device are compared, the PBKDF of the keyslots is ignored.
+func Verify(name string, actual Profile) error {
+	want, err := Get(name)
+	if err != nil {
+		return err
+	}
+	for _, param := range []struct {
+		name         string
+		want, actual any
+	}{
+		{"cipher", want.Cipher, actual.Cipher},
+		{"cipher mode", want.CipherMode, actual.CipherMode},
+		{"volume key size", want.VolumeKeySize, actual.VolumeKeySize},
+		{"integrity", want.Integrity, actual.Integrity},
+		{"sector size", want.SectorSize, actual.SectorSize},
+	} {
+		if param.want != param.actual {
+			return fmt.Errorf("%s of the device is %v, but profile %q requires %v", param.name, param.actual, want.Name, param.want)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package profile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestGet(t *testing.T) {
+	testCases := map[string]struct {
+		name            string
+		wantName        string
+		wantIntegrity   bool
+		wantSectorSize  int
+		wantCrypttabOpt string
+		wantErr         bool
+	}{
+		"empty name is default": {
+			wantName:        AESXTSHMACSHA256,
+			wantIntegrity:   true,
+			wantSectorSize:  4096,
+			wantCrypttabOpt: "cipher=aes-xts-plain64,integrity=hmac-sha256",
+		},
+		"aes-xts-hmac-sha256": {
+			name:            AESXTSHMACSHA256,
+			wantName:        AESXTSHMACSHA256,
+			wantIntegrity:   true,
+			wantSectorSize:  4096,
+			wantCrypttabOpt: "cipher=aes-xts-plain64,integrity=hmac-sha256",
+		},
+		"aes-xts": {
+			name:            AESXTS,
+			wantName:        AESXTS,
+			wantSectorSize:  4096,
+			wantCrypttabOpt: "cipher=aes-xts-plain64",
+		},
+		"aes-xts-512": {
+			name:            AESXTS512,
+			wantName:        AESXTS512,
+			wantSectorSize:  512,
+			wantCrypttabOpt: "cipher=aes-xts-plain64",
+		},
+		"unknown profile": {
+			name:    "rot13",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			profile, err := Get(tc.name)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.wantName, profile.Name)
+			assert.Equal(tc.wantIntegrity, profile.HasIntegrity())
+			assert.Equal(tc.wantSectorSize, profile.SectorSize)
+			assert.Equal(tc.wantCrypttabOpt, profile.CrypttabOptions())
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert := assert.New(t)
+
+	names := Names()
+	assert.Len(names, len(profiles))
+	assert.IsIncreasing(names)
+	for _, name := range names {
+		_, err := Get(name)
+		assert.NoError(err)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	testCases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"same profile":             {a: AESXTS, b: AESXTS, want: true},
+		"different profiles":       {a: AESXTS, b: AESXTS512},
+		"empty is default":         {a: "", b: Default, want: true},
+		"both empty":               {want: true},
+		"empty is not non-default": {a: "", b: AESXTS},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Equal(tc.a, tc.b))
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	deviceParams := func(name string) Profile {
+		p := profiles[name]
+		// parameters read from a device don't include the profile name or PBKDF
+		return Profile{
+			Cipher:        p.Cipher,
+			CipherMode:    p.CipherMode,
+			VolumeKeySize: p.VolumeKeySize,
+			Integrity:     p.Integrity,
+			SectorSize:    p.SectorSize,
+		}
+	}
+
+	testCases := map[string]struct {
+		name    string
+		actual  Profile
+		wantErr bool
+	}{
+		"matching profile": {
+			name:   AESXTS,
+			actual: deviceParams(AESXTS),
+		},
+		"empty name is default": {
+			actual: deviceParams(Default),
+		},
+		"missing integrity": {
+			name:    AESXTSHMACSHA256,
+			actual:  deviceParams(AESXTS),
+			wantErr: true,
+		},
+		"unexpected integrity": {
+			name:    AESXTS,
+			actual:  deviceParams(AESXTSHMACSHA256),
+			wantErr: true,
+		},
+		"sector size mismatch": {
+			name:    AESXTS512,
+			actual:  deviceParams(AESXTS),
+			wantErr: true,
+		},
+		"cipher mismatch": {
+			name: AESXTS,
+			actual: func() Profile {
+				p := deviceParams(AESXTS)
+				p.CipherMode = "cbc-essiv:sha256"
+				return p
+			}(),
+			wantErr: true,
+		},
+		"unknown profile": {
+			name:    "unknown",
+			actual:  deviceParams(AESXTS),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(tc.name, tc.actual)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
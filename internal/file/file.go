@@ -74,6 +74,11 @@ func NewHandler(fs afero.Fs) Handler {
 	return Handler{fs: afs}
 }
 
+// Fs returns the file system the handler operates on.
+func (h *Handler) Fs() afero.Fs {
+	return h.fs.Fs
+}
+
 // Read reads the file given name and returns the bytes read.
 func (h *Handler) Read(name string) ([]byte, error) {
 	file, err := h.fs.OpenFile(name, os.O_RDONLY, 0o600)
@@ -0,0 +1,393 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package joinaudit implements the tamper-evident audit log of join tickets issued by the JoinService.
+
+Each entry records which attested node received a ticket for which disk.
+Entries are chained by including the hash of the previous entry in the hash of each entry,
+so modifying or removing an entry invalidates the hashes of all following entries.
+
+The log is stored in a series of ConfigMaps in the kube-system namespace, each holding up to segmentSize entries.
+Full segments are marked immutable, so Kubernetes rejects further modifications.
+Auditors should keep the hash of the last verified entry: verifying the log against it
+detects rewrites of the chain up to that entry.
+*/
+package joinaudit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// TicketJoin is the ticket type of nodes joining the cluster.
+	TicketJoin = "join"
+	// TicketRejoin is the ticket type of nodes rejoining the cluster after a reboot.
+	TicketRejoin = "rejoin"
+
+	// segmentLabel marks the ConfigMaps holding the audit log.
+	segmentLabel = "constellation.edgeless.systems/join-audit-log"
+	// segmentPrefix is the name prefix of the ConfigMaps holding the audit log, followed by the segment index.
+	segmentPrefix = "join-audit-log-"
+	// segmentSize is the number of entries per ConfigMap.
+	// Entries are a few KiB at most, which keeps segments well below the size limit of ConfigMaps.
+	segmentSize = 128
+)
+
+// appendBackoff retries appends that conflict with appends of other JoinService instances.
+var appendBackoff = wait.Backoff{
+	Steps:    10,
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.5,
+}
+
+// Entry is an entry of the audit log.
+type Entry struct {
+	// Sequence is the position of the entry in the log, starting at 0.
+	Sequence uint64 `json:"sequence"`
+	// Time is the time the ticket was issued.
+	Time time.Time `json:"time"`
+	// Ticket is the type of the issued ticket, either TicketJoin or TicketRejoin.
+	Ticket string `json:"ticket"`
+	// PeerAddress is the address the ticket was requested from.
+	PeerAddress string `json:"peerAddress"`
+	// AttestationVariant is the attestation variant the node was verified with.
+	AttestationVariant string `json:"attestationVariant"`
+	// Measurements are the hex encoded values of the measurement registers in the node's validated attestation document,
+	// indexed like the expected measurements of the attestation variant.
+	Measurements map[uint32]string `json:"measurements,omitempty"`
+	// NodeName is the name of the node, taken from the CSR for join tickets.
+	NodeName string `json:"nodeName"`
+	// DiskUUID is the UUID of the node's state disk, which the issued disk key belongs to.
+	DiskUUID string `json:"diskUUID"`
+	// StateDiskKeyVersion is the version of the issued disk key.
+	// For rejoin tickets with a key rotation, this is the version of the rotated key.
+	StateDiskKeyVersion uint32 `json:"stateDiskKeyVersion"`
+	// IsControlPlane is true if the node joined as control-plane node. Only set for join tickets.
+	IsControlPlane bool `json:"isControlPlane"`
+	// PrevHash is the hash of the previous entry, or empty for the first entry.
+	PrevHash string `json:"prevHash"`
+	// Hash is the hex encoded SHA-256 hash of the entry, computed with an empty Hash field.
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry.
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("marshaling entry: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Log appends entries to the audit log.
+type Log struct {
+	client configMapClient
+	// mux serializes appends of this instance.
+	mux sync.Mutex
+	// headIndex caches the index of the last known segment. It is -1 if unknown.
+	headIndex int
+}
+
+// New returns a Log storing the audit log using the given ConfigMap client.
+// The client must be scoped to the kube-system namespace.
+func New(client configMapClient) *Log {
+	return &Log{client: client, headIndex: -1}
+}
+
+// Append adds an entry to the log.
+// Sequence, PrevHash and Hash are set by Append, and the resulting entry is returned.
+// Concurrent appends of multiple JoinService instances are serialized using the ConfigMaps' resource versions.
+func (l *Log) Append(ctx context.Context, entry Entry) (Entry, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	var appended Entry
+	err := retry.OnError(appendBackoff, isConcurrentAppend, func() error {
+		var err error
+		appended, err = l.tryAppend(ctx, entry)
+		return err
+	})
+	if err != nil {
+		return Entry{}, fmt.Errorf("appending to audit log: %w", err)
+	}
+	return appended, nil
+}
+
+// tryAppend appends the entry to the current head of the log.
+// A conflict or already existing error is returned if another instance appended an entry concurrently.
+func (l *Log) tryAppend(ctx context.Context, entry Entry) (Entry, error) {
+	head, err := l.head(ctx)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Sequence = 0
+	entry.PrevHash = ""
+	if head != nil {
+		last, err := lastEntry(head)
+		if err != nil {
+			return Entry{}, err
+		}
+		entry.Sequence = last.Sequence + 1
+		entry.PrevHash = last.Hash
+	}
+	entry.Hash, err = entry.computeHash()
+	if err != nil {
+		return Entry{}, err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("marshaling entry: %w", err)
+	}
+
+	index := int(entry.Sequence / segmentSize)
+	// seal the segment with its last entry, so full segments can't be modified anymore
+	sealed := entry.Sequence%segmentSize == segmentSize-1
+
+	if head != nil && index == l.headIndex {
+		head.Data[entryKey(entry.Sequence)] = string(data)
+		head.Immutable = &sealed
+		if _, err := l.client.Update(ctx, head, metav1.UpdateOptions{}); err != nil {
+			return Entry{}, fmt.Errorf("updating audit log segment %s: %w", head.Name, err)
+		}
+		return entry, nil
+	}
+
+	segment := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   segmentName(index),
+			Labels: map[string]string{segmentLabel: "true"},
+		},
+		Data:      map[string]string{entryKey(entry.Sequence): string(data)},
+		Immutable: &sealed,
+	}
+	if _, err := l.client.Create(ctx, segment, metav1.CreateOptions{}); err != nil {
+		return Entry{}, fmt.Errorf("creating audit log segment %s: %w", segment.Name, err)
+	}
+	l.headIndex = index
+	return entry, nil
+}
+
+// head returns the last segment of the log, or nil if the log is empty.
+func (l *Log) head(ctx context.Context) (*corev1.ConfigMap, error) {
+	if l.headIndex < 0 {
+		segments, err := listSegments(ctx, l.client)
+		if err != nil {
+			return nil, err
+		}
+		if len(segments) == 0 {
+			return nil, nil
+		}
+		l.headIndex = segments[len(segments)-1].index
+	}
+
+	head, err := l.client.Get(ctx, segmentName(l.headIndex), metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("getting audit log segment %s: %w", segmentName(l.headIndex), err)
+	}
+	// other instances may have started new segments since the head was cached
+	for {
+		next, err := l.client.Get(ctx, segmentName(l.headIndex+1), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return head, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting audit log segment %s: %w", segmentName(l.headIndex+1), err)
+		}
+		head = next
+		l.headIndex++
+	}
+}
+
+// Read returns all entries of the log, ordered by sequence number.
+// Read doesn't verify the entries, use Verify to do so.
+func Read(ctx context.Context, client configMapLister) ([]Entry, error) {
+	segments, err := listSegments(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, segment := range segments {
+		segmentEntries, err := segmentEntries(&segment.configMap)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, segmentEntries...)
+	}
+	return entries, nil
+}
+
+// Export writes the entries to w as JSON lines.
+func Export(w io.Writer, entries []Entry) error {
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("writing entry %d: %w", entry.Sequence, err)
+		}
+	}
+	return nil
+}
+
+// Import reads entries exported as JSON lines.
+func Import(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading entries: %w", err)
+	}
+	return entries, nil
+}
+
+// Verify checks that the entries form a complete hash chain, starting with the first entry of the log.
+// If anchor is not empty, the chain must contain an entry with the anchor as hash.
+// Auditors use the hash of the last previously verified entry as anchor,
+// to detect rewrites of the chain since the last verification.
+func Verify(entries []Entry, anchor string) error {
+	var prevHash string
+	anchorFound := anchor == ""
+	for i, entry := range entries {
+		if entry.Sequence != uint64(i) {
+			return fmt.Errorf("entry %d: expected sequence number %d, entries are missing", entry.Sequence, i)
+		}
+		if entry.PrevHash != prevHash {
+			return fmt.Errorf("entry %d: previous hash %q doesn't match hash %q of entry %d", entry.Sequence, entry.PrevHash, prevHash, i-1)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", entry.Sequence, err)
+		}
+		if entry.Hash != hash {
+			return fmt.Errorf("entry %d: hash %q doesn't match content, expected %q", entry.Sequence, entry.Hash, hash)
+		}
+		if entry.Hash == anchor {
+			anchorFound = true
+		}
+		prevHash = entry.Hash
+	}
+	if !anchorFound {
+		return fmt.Errorf("no entry with anchor hash %q found", anchor)
+	}
+	return nil
+}
+
+// segment is a ConfigMap holding a part of the log.
+type segment struct {
+	index     int
+	configMap corev1.ConfigMap
+}
+
+// listSegments returns the segments of the log, ordered by index.
+func listSegments(ctx context.Context, client configMapLister) ([]segment, error) {
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: segmentLabel})
+	if err != nil {
+		return nil, fmt.Errorf("listing audit log segments: %w", err)
+	}
+
+	segments := make([]segment, 0, len(list.Items))
+	for _, configMap := range list.Items {
+		index, err := strconv.Atoi(strings.TrimPrefix(configMap.Name, segmentPrefix))
+		if err != nil || !strings.HasPrefix(configMap.Name, segmentPrefix) {
+			return nil, fmt.Errorf("unexpected audit log segment %s", configMap.Name)
+		}
+		segments = append(segments, segment{index: index, configMap: configMap})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].index < segments[j].index })
+	return segments, nil
+}
+
+// segmentEntries returns the entries of a segment, ordered by sequence number.
+func segmentEntries(configMap *corev1.ConfigMap) ([]Entry, error) {
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	// keys are zero-padded sequence numbers, so lexical order is numerical order
+	sort.Strings(keys)
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		var entry Entry
+		if err := json.Unmarshal([]byte(configMap.Data[key]), &entry); err != nil {
+			return nil, fmt.Errorf("parsing entry %s of audit log segment %s: %w", key, configMap.Name, err)
+		}
+		if key != entryKey(entry.Sequence) {
+			return nil, fmt.Errorf("entry %s of audit log segment %s has sequence number %d", key, configMap.Name, entry.Sequence)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// lastEntry returns the entry with the highest sequence number of a segment.
+func lastEntry(configMap *corev1.ConfigMap) (Entry, error) {
+	entries, err := segmentEntries(configMap)
+	if err != nil {
+		return Entry{}, err
+	}
+	if len(entries) == 0 {
+		return Entry{}, fmt.Errorf("audit log segment %s is empty", configMap.Name)
+	}
+	return entries[len(entries)-1], nil
+}
+
+// segmentName returns the name of the ConfigMap holding the segment with the given index.
+func segmentName(index int) string {
+	return fmt.Sprintf("%s%06d", segmentPrefix, index)
+}
+
+// entryKey returns the ConfigMap data key of the entry with the given sequence number.
+func entryKey(sequence uint64) string {
+	return fmt.Sprintf("%010d.json", sequence)
+}
+
+// isConcurrentAppend returns true if the error was caused by an append of another JoinService instance.
+func isConcurrentAppend(err error) bool {
+	return k8serrors.IsConflict(err) || k8serrors.IsAlreadyExists(err)
+}
+
+type configMapLister interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error)
+}
+
+type configMapClient interface {
+	configMapLister
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+	Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error)
+	Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package joinaudit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestAppend(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	client := fake.NewSimpleClientset()
+	configMaps := client.CoreV1().ConfigMaps(constants.ConstellationNamespace)
+	// two instances of the JoinService append to the same log
+	logs := []*Log{New(configMaps), New(configMaps)}
+
+	numEntries := 2*segmentSize + 10
+	for i := 0; i < numEntries; i++ {
+		appended, err := logs[i%2].Append(ctx, testEntry(i))
+		require.NoError(err)
+		assert.Equal(uint64(i), appended.Sequence)
+	}
+
+	entries, err := Read(ctx, configMaps)
+	require.NoError(err)
+	require.Len(entries, numEntries)
+	assert.NoError(Verify(entries, ""))
+	assert.Equal(fmt.Sprintf("node-%d", numEntries-1), entries[numEntries-1].NodeName)
+
+	for index, wantImmutable := range []bool{true, true, false} {
+		segment, err := configMaps.Get(ctx, segmentName(index), metav1.GetOptions{})
+		require.NoError(err)
+		require.NotNil(segment.Immutable)
+		assert.Equal(wantImmutable, *segment.Immutable)
+	}
+}
+
+func TestAppendConflict(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	client := fake.NewSimpleClientset()
+	configMaps := client.CoreV1().ConfigMaps(constants.ConstellationNamespace)
+	log := New(configMaps)
+	_, err := log.Append(ctx, testEntry(0))
+	require.NoError(err)
+
+	someErr := errors.New("failed")
+	conflicts := 2
+	client.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			return false, nil, nil
+		}
+		conflicts--
+		return true, nil, k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, segmentName(0), someErr)
+	})
+
+	appended, err := log.Append(ctx, testEntry(1))
+	require.NoError(err)
+	assert.Equal(uint64(1), appended.Sequence)
+	assert.Zero(conflicts)
+
+	client.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, someErr
+	})
+	_, err = log.Append(ctx, testEntry(2))
+	assert.Error(err)
+}
+
+func TestVerify(t *testing.T) {
+	chain := func(n int) []Entry {
+		var entries []Entry
+		prevHash := ""
+		for i := 0; i < n; i++ {
+			entry := testEntry(i)
+			entry.Sequence = uint64(i)
+			entry.PrevHash = prevHash
+			hash, err := entry.computeHash()
+			require.NoError(t, err)
+			entry.Hash = hash
+			prevHash = hash
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	testCases := map[string]struct {
+		entries func() []Entry
+		anchor  func(entries []Entry) string
+		wantErr bool
+	}{
+		"valid chain": {
+			entries: func() []Entry { return chain(5) },
+		},
+		"empty log": {
+			entries: func() []Entry { return nil },
+		},
+		"valid chain with anchor": {
+			entries: func() []Entry { return chain(5) },
+			anchor:  func(entries []Entry) string { return entries[2].Hash },
+		},
+		"anchor not found": {
+			entries: func() []Entry { return chain(5) },
+			anchor:  func([]Entry) string { return "unknown" },
+			wantErr: true,
+		},
+		"modified entry": {
+			entries: func() []Entry {
+				entries := chain(5)
+				entries[2].DiskUUID = "other-disk"
+				return entries
+			},
+			wantErr: true,
+		},
+		"modified and rehashed entry": {
+			entries: func() []Entry {
+				entries := chain(5)
+				entries[2].DiskUUID = "other-disk"
+				entries[2].Hash, _ = entries[2].computeHash()
+				return entries
+			},
+			wantErr: true,
+		},
+		"removed entry": {
+			entries: func() []Entry {
+				entries := chain(5)
+				return append(entries[:2], entries[3:]...)
+			},
+			wantErr: true,
+		},
+		"removed first entry": {
+			entries: func() []Entry { return chain(5)[1:] },
+			wantErr: true,
+		},
+		"rewritten chain doesn't contain anchor": {
+			entries: func() []Entry {
+				entries := chain(5)
+				entries[0].DiskUUID = "other-disk"
+				prevHash := ""
+				for i := range entries {
+					entries[i].PrevHash = prevHash
+					entries[i].Hash, _ = entries[i].computeHash()
+					prevHash = entries[i].Hash
+				}
+				return entries
+			},
+			anchor:  func([]Entry) string { return chain(5)[3].Hash },
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			entries := tc.entries()
+			var anchor string
+			if tc.anchor != nil {
+				anchor = tc.anchor(entries)
+			}
+
+			err := Verify(entries, anchor)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	configMaps := fake.NewSimpleClientset().CoreV1().ConfigMaps(constants.ConstellationNamespace)
+	log := New(configMaps)
+	for i := 0; i < 3; i++ {
+		_, err := log.Append(ctx, testEntry(i))
+		require.NoError(err)
+	}
+	entries, err := Read(ctx, configMaps)
+	require.NoError(err)
+
+	var buf bytes.Buffer
+	require.NoError(Export(&buf, entries))
+	imported, err := Import(&buf)
+	require.NoError(err)
+
+	assert.Equal(entries, imported)
+	assert.NoError(Verify(imported, entries[2].Hash))
+
+	_, err = Import(bytes.NewBufferString("{\n"))
+	assert.Error(err)
+}
+
+func testEntry(i int) Entry {
+	return Entry{
+		Time:               time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+		Ticket:             TicketJoin,
+		PeerAddress:        "192.0.2.1:1234",
+		AttestationVariant: "gcp-sev-es",
+		Measurements: map[uint32]string{
+			4: strings.Repeat("11", 32),
+			9: strings.Repeat("22", 32),
+		},
+		NodeName: fmt.Sprintf("node-%d", i),
+		DiskUUID: fmt.Sprintf("disk-%d", i),
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -28,9 +29,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	kubernetesscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/scale/scheme"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/client-go/util/retry"
 )
 
@@ -39,6 +42,7 @@ type Kubectl struct {
 	kubernetes.Interface
 	dynamicClient      dynamic.Interface
 	apiextensionClient apiextensionsclientv1.ApiextensionsV1Interface
+	restConfig         *rest.Config
 }
 
 // NewUninitialized returns an empty Kubectl client.
@@ -114,6 +118,72 @@ func (k *Kubectl) UpdateCR(ctx context.Context, gvr schema.GroupVersionResource,
 	return k.dynamicClient.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{})
 }
 
+// CreateCR creates a Custom Resource given its group version resource.
+// Cluster scoped resources are created if the object has no namespace.
+func (k *Kubectl) CreateCR(ctx context.Context, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) error {
+	_, err := k.dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+	return err
+}
+
+// ListSecrets returns all secrets in the given namespace.
+// Secrets of all namespaces are returned if namespace is empty.
+func (k *Kubectl) ListSecrets(ctx context.Context, namespace string) ([]corev1.Secret, error) {
+	secrets, err := k.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing secrets: %w", err)
+	}
+	return secrets.Items, nil
+}
+
+// CreateSecret creates the provided secret.
+func (k *Kubectl) CreateSecret(ctx context.Context, secret *corev1.Secret) error {
+	_, err := k.CoreV1().Secrets(secret.ObjectMeta.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+// CreateNamespace creates a namespace with the given name.
+func (k *Kubectl) CreateNamespace(ctx context.Context, name string) error {
+	_, err := k.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+	return err
+}
+
+// ListPods returns all pods in the given namespace matching the label selector.
+func (k *Kubectl) ListPods(ctx context.Context, namespace, labelSelector string) ([]corev1.Pod, error) {
+	pods, err := k.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("listing pods: %w", err)
+	}
+	return pods.Items, nil
+}
+
+// ExecPod runs a command in a container of a pod, and streams its output to stdout and stderr.
+// If stdin is not nil, it is streamed to the command's standard input.
+// The command is executed directly, without a shell.
+func (k *Kubectl) ExecPod(ctx context.Context, namespace, pod, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	req := k.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, kubernetesscheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(k.restConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("creating executor: %w", err)
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
+
 // CreateConfigMap creates the provided configmap.
 func (k *Kubectl) CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
 	_, err := k.CoreV1().ConfigMaps(configMap.ObjectMeta.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
@@ -277,6 +347,8 @@ func (k *Kubectl) AddNodeSelectorsToDeployment(ctx context.Context, selectors ma
 }
 
 func (k *Kubectl) initialize(clientConfig *rest.Config) error {
+	k.restConfig = clientConfig
+
 	clientset, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
 		return fmt.Errorf("creating k8s client from kubeconfig: %w", err)
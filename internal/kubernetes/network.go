@@ -0,0 +1,80 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// DefaultPodCIDR is the IPv4 pod CIDR used by Cilium if no pod CIDR is configured.
+const DefaultPodCIDR = "10.244.0.0/16"
+
+// CIDRs are the IPv4 and IPv6 CIDRs of a single-stack or dual-stack Kubernetes network.
+type CIDRs struct {
+	// IPv4 is the IPv4 CIDR of the network, or empty for IPv6 single-stack networks.
+	IPv4 string
+	// IPv6 is the IPv6 CIDR of the network, or empty for IPv4 single-stack networks.
+	IPv6 string
+	// IPv6Primary is true if the IPv6 CIDR is the primary CIDR of the network.
+	IPv6Primary bool
+}
+
+// ParseCIDRs parses a comma-separated list of CIDRs, as accepted by kubeadm's serviceSubnet and podSubnet.
+// The list may contain either a single CIDR, or one IPv4 and one IPv6 CIDR for dual-stack networks.
+// The first CIDR of the list is the primary CIDR.
+func ParseCIDRs(list string) (CIDRs, error) {
+	var cidrs CIDRs
+	entries := strings.Split(list, ",")
+	if len(entries) > 2 {
+		return CIDRs{}, fmt.Errorf("expected at most 2 CIDRs, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(entry))
+		if err != nil {
+			return CIDRs{}, fmt.Errorf("parsing CIDR %q: %w", entry, err)
+		}
+		if prefix != prefix.Masked() {
+			return CIDRs{}, fmt.Errorf("CIDR %q has host bits set", entry)
+		}
+		if prefix.Addr().Is4() {
+			if cidrs.IPv4 != "" {
+				return CIDRs{}, errors.New("dual-stack networks need one IPv4 and one IPv6 CIDR, got two IPv4 CIDRs")
+			}
+			cidrs.IPv4 = prefix.String()
+			continue
+		}
+		if cidrs.IPv6 != "" {
+			return CIDRs{}, errors.New("dual-stack networks need one IPv4 and one IPv6 CIDR, got two IPv6 CIDRs")
+		}
+		cidrs.IPv6 = prefix.String()
+		cidrs.IPv6Primary = i == 0
+	}
+	return cidrs, nil
+}
+
+// DualStack returns true if the network has both an IPv4 and an IPv6 CIDR.
+func (c CIDRs) DualStack() bool {
+	return c.IPv4 != "" && c.IPv6 != ""
+}
+
+// String returns the CIDRs as comma-separated list, starting with the primary CIDR.
+func (c CIDRs) String() string {
+	var list []string
+	if c.IPv4 != "" {
+		list = append(list, c.IPv4)
+	}
+	if c.IPv6 != "" {
+		list = append(list, c.IPv6)
+	}
+	if c.IPv6Primary && len(list) == 2 {
+		list[0], list[1] = list[1], list[0]
+	}
+	return strings.Join(list, ",")
+}
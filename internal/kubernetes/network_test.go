@@ -0,0 +1,86 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCIDRs(t *testing.T) {
+	testCases := map[string]struct {
+		list          string
+		wantCIDRs     CIDRs
+		wantDualStack bool
+		wantErr       bool
+	}{
+		"ipv4": {
+			list:      "10.96.0.0/12",
+			wantCIDRs: CIDRs{IPv4: "10.96.0.0/12"},
+		},
+		"ipv6": {
+			list:      "fd00:10:96::/108",
+			wantCIDRs: CIDRs{IPv6: "fd00:10:96::/108", IPv6Primary: true},
+		},
+		"dual-stack": {
+			list:          "10.96.0.0/12,fd00:10:96::/108",
+			wantCIDRs:     CIDRs{IPv4: "10.96.0.0/12", IPv6: "fd00:10:96::/108"},
+			wantDualStack: true,
+		},
+		"dual-stack with IPv6 primary": {
+			list:          "fd00:10:96::/108, 10.96.0.0/12",
+			wantCIDRs:     CIDRs{IPv4: "10.96.0.0/12", IPv6: "fd00:10:96::/108", IPv6Primary: true},
+			wantDualStack: true,
+		},
+		"empty": {
+			list:    "",
+			wantErr: true,
+		},
+		"invalid CIDR": {
+			list:    "10.96.0.0",
+			wantErr: true,
+		},
+		"host bits set": {
+			list:    "10.96.0.1/12",
+			wantErr: true,
+		},
+		"two IPv4 CIDRs": {
+			list:    "10.96.0.0/12,10.112.0.0/12",
+			wantErr: true,
+		},
+		"two IPv6 CIDRs": {
+			list:    "fd00:10:96::/108,fd00:10:97::/108",
+			wantErr: true,
+		},
+		"too many CIDRs": {
+			list:    "10.96.0.0/12,fd00:10:96::/108,10.112.0.0/12",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			cidrs, err := ParseCIDRs(tc.list)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.wantCIDRs, cidrs)
+			assert.Equal(tc.wantDualStack, cidrs.DualStack())
+
+			reparsed, err := ParseCIDRs(cidrs.String())
+			require.NoError(err)
+			assert.Equal(cidrs, reparsed)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Node labels and taints are passed from the node group configuration to the nodes through cloud provider tags.
+// Not all cloud providers allow commas in tag values, so the lists are separated by spaces instead.
+// Apart from the separator, the format matches the kubelet's --node-labels and --register-with-taints flags.
+const nodeRegistrationSeparator = " "
+
+// MarshalNodeLabels encodes node labels as a space separated list of key=value pairs, sorted by key.
+func MarshalNodeLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, nodeRegistrationSeparator)
+}
+
+// UnmarshalNodeLabels decodes node labels encoded by MarshalNodeLabels.
+func UnmarshalNodeLabels(s string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, pair := range strings.Fields(s) {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid node label %q: missing '='", pair)
+		}
+		if err := ValidateNodeLabel(key, value); err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// MarshalNodeTaints encodes node taints as a space separated list of key[=value]:effect entries.
+func MarshalNodeTaints(taints []corev1.Taint) string {
+	entries := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		entry := taint.Key
+		if taint.Value != "" {
+			entry += "=" + taint.Value
+		}
+		entries = append(entries, entry+":"+string(taint.Effect))
+	}
+	return strings.Join(entries, nodeRegistrationSeparator)
+}
+
+// UnmarshalNodeTaints decodes node taints encoded by MarshalNodeTaints.
+func UnmarshalNodeTaints(s string) ([]corev1.Taint, error) {
+	var taints []corev1.Taint
+	for _, entry := range strings.Fields(s) {
+		keyValue, effect, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid node taint %q: missing effect", entry)
+		}
+		key, value, _ := strings.Cut(keyValue, "=")
+		taint := corev1.Taint{Key: key, Value: value, Effect: corev1.TaintEffect(effect)}
+		if err := ValidateNodeTaint(taint); err != nil {
+			return nil, err
+		}
+		taints = append(taints, taint)
+	}
+	return taints, nil
+}
+
+// KubeletNodeLabels returns the value of the kubelet's --node-labels flag for the given labels.
+func KubeletNodeLabels(labels map[string]string) string {
+	return strings.ReplaceAll(MarshalNodeLabels(labels), nodeRegistrationSeparator, ",")
+}
+
+// KubeletNodeTaints returns the value of the kubelet's --register-with-taints flag for the given taints.
+func KubeletNodeTaints(taints []corev1.Taint) string {
+	return strings.ReplaceAll(MarshalNodeTaints(taints), nodeRegistrationSeparator, ",")
+}
+
+// ValidateNodeLabel checks that a node label is valid and can be set by the kubelet.
+// The kubelet may only set labels in the kubernetes.io and k8s.io namespaces
+// if they are prefixed with node.kubernetes.io/ or kubelet.kubernetes.io/.
+func ValidateNodeLabel(key, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid node label key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return fmt.Errorf("invalid value for node label %q: %s", key, strings.Join(errs, "; "))
+	}
+	if prefix, _, ok := strings.Cut(key, "/"); ok && isKubernetesNamespace(prefix) &&
+		prefix != "node.kubernetes.io" && prefix != "kubelet.kubernetes.io" {
+		return fmt.Errorf("node label %q: the kubelet may only set labels in the %s namespace if they are prefixed with node.kubernetes.io/ or kubelet.kubernetes.io/", key, prefix)
+	}
+	return nil
+}
+
+// ValidateNodeTaint checks that a node taint is valid.
+func ValidateNodeTaint(taint corev1.Taint) error {
+	if errs := validation.IsQualifiedName(taint.Key); len(errs) > 0 {
+		return fmt.Errorf("invalid node taint key %q: %s", taint.Key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(taint.Value); len(errs) > 0 {
+		return fmt.Errorf("invalid value for node taint %q: %s", taint.Key, strings.Join(errs, "; "))
+	}
+	switch taint.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		return nil
+	default:
+		return fmt.Errorf("invalid effect %q for node taint %q: must be one of %s, %s, %s", taint.Effect, taint.Key,
+			corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)
+	}
+}
+
+func isKubernetesNamespace(prefix string) bool {
+	return prefix == "kubernetes.io" || strings.HasSuffix(prefix, ".kubernetes.io") ||
+		prefix == "k8s.io" || strings.HasSuffix(prefix, ".k8s.io")
+}
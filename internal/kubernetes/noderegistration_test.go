@@ -0,0 +1,112 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeLabels(t *testing.T) {
+	testCases := map[string]struct {
+		labels      map[string]string
+		wantEncoded string
+		wantKubelet string
+	}{
+		"empty": {
+			labels: map[string]string{},
+		},
+		"multiple labels are sorted": {
+			labels:      map[string]string{"pool": "high-memory", "example.com/gpu": "", "node.kubernetes.io/lifecycle": "spot"},
+			wantEncoded: "example.com/gpu= node.kubernetes.io/lifecycle=spot pool=high-memory",
+			wantKubelet: "example.com/gpu=,node.kubernetes.io/lifecycle=spot,pool=high-memory",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			encoded := MarshalNodeLabels(tc.labels)
+			assert.Equal(tc.wantEncoded, encoded)
+			assert.Equal(tc.wantKubelet, KubeletNodeLabels(tc.labels))
+
+			decoded, err := UnmarshalNodeLabels(encoded)
+			require.NoError(err)
+			assert.Equal(tc.labels, decoded)
+		})
+	}
+}
+
+func TestUnmarshalNodeLabelsInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"missing value separator": "pool",
+		"invalid key":             "-pool=a",
+		"invalid value":           "pool=a b=c/d",
+		"reserved namespace":      "node-role.kubernetes.io/worker=",
+		"reserved k8s.io":         "k8s.io/foo=bar",
+	}
+
+	for name, encoded := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalNodeLabels(encoded)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNodeTaints(t *testing.T) {
+	testCases := map[string]struct {
+		taints      []corev1.Taint
+		wantEncoded string
+		wantKubelet string
+	}{
+		"empty": {},
+		"with and without value": {
+			taints: []corev1.Taint{
+				{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule},
+			},
+			wantEncoded: "nvidia.com/gpu=present:NoSchedule spot:PreferNoSchedule",
+			wantKubelet: "nvidia.com/gpu=present:NoSchedule,spot:PreferNoSchedule",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			encoded := MarshalNodeTaints(tc.taints)
+			assert.Equal(tc.wantEncoded, encoded)
+			assert.Equal(tc.wantKubelet, KubeletNodeTaints(tc.taints))
+
+			decoded, err := UnmarshalNodeTaints(encoded)
+			require.NoError(err)
+			assert.Equal(tc.taints, decoded)
+		})
+	}
+}
+
+func TestUnmarshalNodeTaintsInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"missing effect": "spot=true",
+		"invalid effect": "spot=true:Sometimes",
+		"invalid key":    "=true:NoSchedule",
+	}
+
+	for name, encoded := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := UnmarshalNodeTaints(encoded)
+			assert.Error(t, err)
+		})
+	}
+}
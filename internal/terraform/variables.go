@@ -69,6 +69,10 @@ type AWSClusterVariables struct {
 	CustomEndpoint string `hcl:"custom_endpoint" cty:"custom_endpoint"`
 	// InternalLoadBalancer is true if an internal load balancer should be created.
 	InternalLoadBalancer bool `hcl:"internal_load_balancer" cty:"internal_load_balancer"`
+	// DiskEncryptionProfile is the name of the encryption profile used for the state disks.
+	DiskEncryptionProfile string `hcl:"disk_encryption_profile" cty:"disk_encryption_profile"`
+	// EnableIPv6 assigns IPv6 addresses to the nodes for dual-stack clusters.
+	EnableIPv6 bool `hcl:"enable_ipv6,optional" cty:"enable_ipv6"`
 }
 
 // GetCreateMAA gets the CreateMAA variable.
@@ -99,6 +103,16 @@ type AWSNodeGroup struct {
 	InstanceType string `hcl:"instance_type" cty:"instance_type"`
 	// DiskType is the EBS disk type to use for the state disk.
 	DiskType string `hcl:"disk_type" cty:"disk_type"`
+	// NodeLabels is the space separated list of labels the kubelet registers the nodes with.
+	NodeLabels string `hcl:"node_labels" cty:"node_labels"`
+	// NodeTaints is the space separated list of taints the kubelet registers the nodes with.
+	NodeTaints string `hcl:"node_taints" cty:"node_taints"`
+	// Spot configures the node group to use spot instances.
+	Spot bool `hcl:"spot" cty:"spot"`
+	// MinCount is the minimum number of nodes the autoscaler scales the group down to. 0 if not configured.
+	MinCount int `hcl:"min_count" cty:"min_count"`
+	// MaxCount is the maximum number of nodes the autoscaler scales the group up to. 0 if not configured.
+	MaxCount int `hcl:"max_count" cty:"max_count"`
 }
 
 // AWSIAMVariables is user configuration for creating the IAM configuration with Terraform on Microsoft Azure.
@@ -136,6 +150,8 @@ type GCPClusterVariables struct {
 	CustomEndpoint string `hcl:"custom_endpoint" cty:"custom_endpoint"`
 	// InternalLoadBalancer is true if an internal load balancer should be created.
 	InternalLoadBalancer bool `hcl:"internal_load_balancer" cty:"internal_load_balancer"`
+	// DiskEncryptionProfile is the name of the encryption profile used for the state disks.
+	DiskEncryptionProfile string `hcl:"disk_encryption_profile" cty:"disk_encryption_profile"`
 }
 
 // GetCreateMAA gets the CreateMAA variable.
@@ -163,6 +179,16 @@ type GCPNodeGroup struct {
 	Zone         string `hcl:"zone" cty:"zone"`
 	InstanceType string `hcl:"instance_type" cty:"instance_type"`
 	DiskType     string `hcl:"disk_type" cty:"disk_type"`
+	// NodeLabels is the space separated list of labels the kubelet registers the nodes with.
+	NodeLabels string `hcl:"node_labels" cty:"node_labels"`
+	// NodeTaints is the space separated list of taints the kubelet registers the nodes with.
+	NodeTaints string `hcl:"node_taints" cty:"node_taints"`
+	// Spot configures the node group to use spot instances.
+	Spot bool `hcl:"spot" cty:"spot"`
+	// MinCount is the minimum number of nodes the autoscaler scales the group down to. 0 if not configured.
+	MinCount int `hcl:"min_count" cty:"min_count"`
+	// MaxCount is the maximum number of nodes the autoscaler scales the group up to. 0 if not configured.
+	MaxCount int `hcl:"max_count" cty:"max_count"`
 }
 
 // GCPIAMVariables is user configuration for creating the IAM confioguration with Terraform on GCP.
@@ -210,6 +236,8 @@ type AzureClusterVariables struct {
 	CustomEndpoint string `hcl:"custom_endpoint" cty:"custom_endpoint"`
 	// InternalLoadBalancer is true if an internal load balancer should be created.
 	InternalLoadBalancer bool `hcl:"internal_load_balancer" cty:"internal_load_balancer"`
+	// DiskEncryptionProfile is the name of the encryption profile used for the state disks.
+	DiskEncryptionProfile string `hcl:"disk_encryption_profile" cty:"disk_encryption_profile"`
 	// MarketplaceImage is the (optional) Azure Marketplace image to use.
 	MarketplaceImage *AzureMarketplaceImageVariables `hcl:"marketplace_image" cty:"marketplace_image"`
 }
@@ -241,6 +269,16 @@ type AzureNodeGroup struct {
 	DiskSizeGB   int      `hcl:"disk_size" cty:"disk_size"`
 	DiskType     string   `hcl:"disk_type" cty:"disk_type"`
 	Zones        []string `hcl:"zones" cty:"zones"`
+	// NodeLabels is the space separated list of labels the kubelet registers the nodes with.
+	NodeLabels string `hcl:"node_labels" cty:"node_labels"`
+	// NodeTaints is the space separated list of taints the kubelet registers the nodes with.
+	NodeTaints string `hcl:"node_taints" cty:"node_taints"`
+	// Spot configures the node group to use spot instances.
+	Spot bool `hcl:"spot" cty:"spot"`
+	// MinCount is the minimum number of nodes the autoscaler scales the group down to. 0 if not configured.
+	MinCount int `hcl:"min_count" cty:"min_count"`
+	// MaxCount is the maximum number of nodes the autoscaler scales the group up to. 0 if not configured.
+	MaxCount int `hcl:"max_count" cty:"max_count"`
 }
 
 // AzureIAMVariables is user configuration for creating the IAM configuration with Terraform on Microsoft Azure.
@@ -301,6 +339,8 @@ type OpenStackClusterVariables struct {
 	CustomEndpoint string `hcl:"custom_endpoint" cty:"custom_endpoint"`
 	// InternalLoadBalancer is true if an internal load balancer should be created.
 	InternalLoadBalancer bool `hcl:"internal_load_balancer" cty:"internal_load_balancer"`
+	// DiskEncryptionProfile is the name of the encryption profile used for the state disks.
+	DiskEncryptionProfile string `hcl:"disk_encryption_profile" cty:"disk_encryption_profile"`
 }
 
 // GetCreateMAA gets the CreateMAA variable.
@@ -331,6 +371,10 @@ type OpenStackNodeGroup struct {
 	StateDiskType string `hcl:"state_disk_type" cty:"state_disk_type"`
 	// StateDiskSizeGB is the size of the state disk to allocate to each node, in GB.
 	StateDiskSizeGB int `hcl:"state_disk_size" cty:"state_disk_size"`
+	// NodeLabels is the space separated list of labels the kubelet registers the nodes with.
+	NodeLabels string `hcl:"node_labels" cty:"node_labels"`
+	// NodeTaints is the space separated list of taints the kubelet registers the nodes with.
+	NodeTaints string `hcl:"node_taints" cty:"node_taints"`
 }
 
 // TODO(malt3): Add support for OpenStack IAM variables.
@@ -374,6 +418,10 @@ type QEMUVariables struct {
 	CustomEndpoint string `hcl:"custom_endpoint" cty:"custom_endpoint"`
 	// InternalLoadBalancer is true if an internal load balancer should be created.
 	InternalLoadBalancer bool `hcl:"internal_load_balancer" cty:"internal_load_balancer"`
+	// DiskEncryptionProfile is the name of the encryption profile used for the state disks.
+	DiskEncryptionProfile string `hcl:"disk_encryption_profile" cty:"disk_encryption_profile"`
+	// EnableIPv6 assigns IPv6 addresses to the nodes for dual-stack clusters.
+	EnableIPv6 bool `hcl:"enable_ipv6,optional" cty:"enable_ipv6"`
 }
 
 // GetCreateMAA gets the CreateMAA variable.
@@ -35,6 +35,11 @@ func TestAWSClusterVariables(t *testing.T) {
 				Zone:            "eu-central-1c",
 				InstanceType:    "x1.bar",
 				DiskType:        "bardisk",
+				NodeLabels:      "pool=spot",
+				NodeTaints:      "spot=true:NoSchedule",
+				Spot:            true,
+				MinCount:        1,
+				MaxCount:        5,
 			},
 		},
 		Region:                 "eu-central-1",
@@ -45,6 +50,8 @@ func TestAWSClusterVariables(t *testing.T) {
 		Debug:                  true,
 		EnableSNP:              true,
 		CustomEndpoint:         "example.com",
+		DiskEncryptionProfile:  "aes-xts",
+		EnableIPv6:             true,
 	}
 
 	// test that the variables are correctly rendered
@@ -62,7 +69,12 @@ node_groups = {
     disk_type     = "foodisk"
     initial_count = 1
     instance_type = "x1.foo"
+    max_count     = 0
+    min_count     = 0
+    node_labels   = ""
+    node_taints   = ""
     role          = "control-plane"
+    spot          = false
     zone          = "eu-central-1b"
   }
   worker_default = {
@@ -70,12 +82,19 @@ node_groups = {
     disk_type     = "bardisk"
     initial_count = 2
     instance_type = "x1.bar"
+    max_count     = 5
+    min_count     = 1
+    node_labels   = "pool=spot"
+    node_taints   = "spot=true:NoSchedule"
     role          = "worker"
+    spot          = true
     zone          = "eu-central-1c"
   }
 }
-custom_endpoint        = "example.com"
-internal_load_balancer = false
+custom_endpoint         = "example.com"
+internal_load_balancer  = false
+disk_encryption_profile = "aes-xts"
+enable_ipv6             = true
 `
 	got := vars.String()
 	assert.Equal(t, strings.Fields(want), strings.Fields(got)) // to ignore whitespace differences
@@ -137,7 +156,12 @@ node_groups = {
     disk_type     = "pd-ssd"
     initial_count = 1
     instance_type = "n2d-standard-4"
+    max_count     = 0
+    min_count     = 0
+    node_labels   = ""
+    node_taints   = ""
     role          = "control-plane"
+    spot          = false
     zone          = "eu-central-1a"
   }
   worker_default = {
@@ -145,12 +169,18 @@ node_groups = {
     disk_type     = "pd-ssd"
     initial_count = 1
     instance_type = "n2d-standard-8"
+    max_count     = 0
+    min_count     = 0
+    node_labels   = ""
+    node_taints   = ""
     role          = "worker"
+    spot          = false
     zone          = "eu-central-1b"
   }
 }
-custom_endpoint        = "example.com"
-internal_load_balancer = false
+custom_endpoint         = "example.com"
+internal_load_balancer  = false
+disk_encryption_profile = ""
 `
 	got := vars.String()
 	assert.Equal(t, strings.Fields(want), strings.Fields(got)) // to ignore whitespace differences
@@ -217,12 +247,18 @@ node_groups = {
     disk_type     = "StandardSSD_LRS"
     initial_count = 1
     instance_type = "Standard_D2s_v3"
+    max_count     = 0
+    min_count     = 0
+    node_labels   = ""
+    node_taints   = ""
     role          = "ControlPlane"
+    spot          = false
     zones         = null
   }
 }
-custom_endpoint        = "example.com"
-internal_load_balancer = false
+custom_endpoint         = "example.com"
+internal_load_balancer  = false
+disk_encryption_profile = ""
 marketplace_image = {
   name      = "constellation"
   product   = "constellation"
@@ -280,6 +316,8 @@ node_groups = {
   control_plane_default = {
     flavor_id       = "flavor-0123456789abcdef"
     initial_count   = 1
+    node_labels     = ""
+    node_taints     = ""
     role            = "control-plane"
     state_disk_size = 30
     state_disk_type = "performance-8"
@@ -288,7 +326,7 @@ node_groups = {
 }
 cloud                      = "my-cloud"
 floating_ip_pool_id        = "fip-pool-0123456789abcdef"
-image_id                  = "https://example.com/image.raw"
+image_id                   = "https://example.com/image.raw"
 direct_download            = true
 openstack_user_domain_name = "my-user-domain"
 openstack_username         = "my-username"
@@ -296,6 +334,7 @@ openstack_password         = "my-password"
 debug                      = true
 custom_endpoint            = "example.com"
 internal_load_balancer     = false
+disk_encryption_profile    = ""
 `
 	got := vars.String()
 	assert.Equal(t, strings.Fields(want), strings.Fields(got)) // to ignore whitespace differences
@@ -342,7 +381,7 @@ machine                 = "q35"
 libvirt_uri             = "qemu:///system"
 libvirt_socket_path     = "/var/run/libvirt/libvirt-sock"
 constellation_boot_mode = "uefi"
-image_id  = "/var/lib/libvirt/images/cluster-name.qcow2"
+image_id                = "/var/lib/libvirt/images/cluster-name.qcow2"
 image_format            = "raw"
 metadata_api_image      = "example.com/metadata-api:latest"
 metadata_libvirt_uri    = "qemu:///system"
@@ -351,6 +390,8 @@ constellation_initrd    = "/var/lib/libvirt/images/cluster-name-initrd"
 constellation_cmdline   = "console=ttyS0,115200n8"
 custom_endpoint         = "example.com"
 internal_load_balancer  = false
+disk_encryption_profile = ""
+enable_ipv6             = false
 `
 	got := vars.String()
 	assert.Equal(t, strings.Fields(want), strings.Fields(got)) // to ignore whitespace differences
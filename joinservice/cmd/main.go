@@ -27,8 +27,10 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/atlscredentials"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/joinservice/internal/certcache"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/joinpolicy"
 	"github.com/edgelesssys/constellation/v2/joinservice/internal/kms"
 	"github.com/edgelesssys/constellation/v2/joinservice/internal/kubeadm"
 	"github.com/edgelesssys/constellation/v2/joinservice/internal/kubernetes"
@@ -73,6 +75,7 @@ func main() {
 	if err != nil {
 		log.With(zap.Error(err)).Fatalf("Failed to create certificate chain cache")
 	}
+	go certCacheClient.RefreshCollateral(context.Background())
 
 	validator, err := watcher.NewValidator(log.Named("validator"), attVariant, handler, cachedCerts)
 	if err != nil {
@@ -85,11 +88,17 @@ func main() {
 	vpcCtx, cancel := context.WithTimeout(context.Background(), vpcIPTimeout)
 	defer cancel()
 
-	vpcIP, err := getVPCIP(vpcCtx, *provider)
+	metadataClient, closeMetadata, err := newMetadataClient(vpcCtx, *provider)
+	if err != nil {
+		log.With(zap.Error(err)).Fatalf("Failed to create metadata client")
+	}
+	defer closeMetadata()
+
+	self, err := metadataClient.Self(vpcCtx)
 	if err != nil {
 		log.With(zap.Error(err)).Fatalf("Failed to get IP in VPC")
 	}
-	apiServerEndpoint := net.JoinHostPort(vpcIP, strconv.Itoa(constants.KubernetesPort))
+	apiServerEndpoint := net.JoinHostPort(self.VPCIP, strconv.Itoa(constants.KubernetesPort))
 	kubeadm, err := kubeadm.New(apiServerEndpoint, log.Named("kubeadm"))
 	if err != nil {
 		log.With(zap.Error(err)).Fatalf("Failed to create kubeadm")
@@ -107,6 +116,9 @@ func main() {
 		kubeadm,
 		keyServiceClient,
 		kubeClient,
+		joinpolicy.New(log.Named("joinPolicy"), metadataClient, kubeClient),
+		joinaudit.New(kubeClient.ConfigMaps()),
+		validator,
 		log.Named("server"),
 	)
 	if err != nil {
@@ -131,46 +143,44 @@ func main() {
 	}
 }
 
-func getVPCIP(ctx context.Context, provider string) (string, error) {
-	var metadataClient metadataAPI
-	var err error
+// newMetadataClient creates a metadata client for the cloud provider.
+// The returned function releases the resources of the client.
+func newMetadataClient(ctx context.Context, provider string) (metadataAPI, func(), error) {
+	noop := func() {}
 
 	switch cloudprovider.FromString(provider) {
 	case cloudprovider.AWS:
-		metadataClient, err = awscloud.New(ctx)
+		metadataClient, err := awscloud.New(ctx)
 		if err != nil {
-			return "", err
+			return nil, nil, err
 		}
+		return metadataClient, noop, nil
 	case cloudprovider.Azure:
-		metadataClient, err = azurecloud.New(ctx)
+		metadataClient, err := azurecloud.New(ctx)
 		if err != nil {
-			return "", err
+			return nil, nil, err
 		}
+		return metadataClient, noop, nil
 	case cloudprovider.GCP:
 		gcpMeta, err := gcpcloud.New(ctx)
 		if err != nil {
-			return "", err
+			return nil, nil, err
 		}
-		defer gcpMeta.Close()
-		metadataClient = gcpMeta
+		return gcpMeta, gcpMeta.Close, nil
 	case cloudprovider.OpenStack:
-		metadataClient, err = openstack.New(ctx)
+		metadataClient, err := openstack.New(ctx)
 		if err != nil {
-			return "", err
+			return nil, nil, err
 		}
+		return metadataClient, noop, nil
 	case cloudprovider.QEMU:
-		metadataClient = qemucloud.New()
+		return qemucloud.New(), noop, nil
 	default:
-		return "", errors.New("unsupported cloud provider")
-	}
-
-	self, err := metadataClient.Self(ctx)
-	if err != nil {
-		return "", err
+		return nil, nil, errors.New("unsupported cloud provider")
 	}
-	return self.VPCIP, nil
 }
 
 type metadataAPI interface {
 	Self(ctx context.Context) (metadata.InstanceMetadata, error)
+	List(ctx context.Context) ([]metadata.InstanceMetadata, error)
 }
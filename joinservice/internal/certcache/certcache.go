@@ -4,7 +4,7 @@ Copyright (c) Edgeless Systems GmbH
 SPDX-License-Identifier: AGPL-3.0-only
 */
 
-// Package certcache implements an in-cluster SEV-SNP certificate cache.
+// Package certcache implements an in-cluster SEV-SNP certificate cache and Intel TDX collateral cache.
 package certcache
 
 import (
@@ -12,13 +12,16 @@ import (
 	"crypto/x509"
 	"fmt"
 
+	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/joinservice/internal/certcache/amdkds"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/certcache/intelpcs"
 	"github.com/google/go-sev-guest/abi"
 	"github.com/google/go-sev-guest/verify/trust"
+	"go.uber.org/zap"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -27,7 +30,8 @@ type Client struct {
 	log        *logger.Logger
 	attVariant variant.Variant
 	kdsClient
-	kubeClient kubeClient
+	tdxCollateral tdxCollateralCache
+	kubeClient    kubeClient
 }
 
 // NewClient creates a new CertCacheClient.
@@ -35,16 +39,18 @@ func NewClient(log *logger.Logger, kubeClient kubeClient, attVariant variant.Var
 	kdsClient := amdkds.NewKDSClient(trust.DefaultHTTPSGetter())
 
 	return &Client{
-		attVariant: attVariant,
-		log:        log,
-		kubeClient: kubeClient,
-		kdsClient:  kdsClient,
+		attVariant:    attVariant,
+		log:           log,
+		kubeClient:    kubeClient,
+		kdsClient:     kdsClient,
+		tdxCollateral: intelpcs.New(log.Named("intelpcs"), kubeClient),
 	}
 }
 
 // CreateCertChainCache creates a certificate chain cache for the given attestation variant
 // and returns the cached certificates, if applicable.
 // If the certificate chain cache already exists, nothing is done.
+// For Intel TDX, the collateral required to verify quotes is prefetched into the collateral cache.
 func (c *Client) CreateCertChainCache(ctx context.Context) (*CachedCerts, error) {
 	var reportSigner abi.ReportSigner
 	switch c.attVariant {
@@ -52,6 +58,16 @@ func (c *Client) CreateCertChainCache(ctx context.Context) (*CachedCerts, error)
 		reportSigner = abi.VcekReportSigner
 	case variant.AWSSEVSNP{}:
 		reportSigner = abi.VlekReportSigner
+	case variant.QEMUTDX{}:
+		c.log.Debugf("Creating %s collateral cache", c.attVariant)
+		// Collateral missing from the cache is retrieved when validating the first quote,
+		// so failing to reach Intel PCS now must not prevent the JoinService from starting.
+		if err := c.tdxCollateral.Prefetch(ctx); err != nil {
+			c.log.With(zap.Error(err)).Warnf("Failed to prefetch TDX collateral")
+		}
+		return &CachedCerts{
+			tdxCollateral: c.tdxCollateral,
+		}, nil
 	default:
 		c.log.Debugf("No certificate chain caching possible for attestation variant %s", c.attVariant)
 		return nil, nil
@@ -68,10 +84,20 @@ func (c *Client) CreateCertChainCache(ctx context.Context) (*CachedCerts, error)
 	}, nil
 }
 
+// RefreshCollateral periodically refreshes the cached Intel TDX collateral until ctx is done.
+// For other attestation variants, it returns immediately.
+func (c *Client) RefreshCollateral(ctx context.Context) {
+	if c.attVariant != (variant.QEMUTDX{}) {
+		return
+	}
+	c.tdxCollateral.Run(ctx)
+}
+
 // CachedCerts contains the cached certificates.
 type CachedCerts struct {
-	ask *x509.Certificate
-	ark *x509.Certificate
+	ask           *x509.Certificate
+	ark           *x509.Certificate
+	tdxCollateral tdx.CollateralGetter
 }
 
 // SevSnpCerts returns the cached SEV-SNP ASK and ARK certificates.
@@ -79,6 +105,11 @@ func (c *CachedCerts) SevSnpCerts() (ask, ark *x509.Certificate) {
 	return c.ask, c.ark
 }
 
+// TDXCollateral returns a getter for the cached Intel TDX collateral.
+func (c *CachedCerts) TDXCollateral() tdx.CollateralGetter {
+	return c.tdxCollateral
+}
+
 // createCertChainCache creates a certificate chain cache configmap with the ASK and ARK
 // retrieved from the KDS and returns ASK and ARK. If the configmap already exists and both ASK and ARK are present,
 // nothing is done and the existing ASK and ARK are returned. If the configmap already exists but either ASK or ARK
@@ -205,3 +236,9 @@ type kubeClient interface {
 type kdsClient interface {
 	CertChain(signingType abi.ReportSigner) (ask, ark *x509.Certificate, err error)
 }
+
+type tdxCollateralCache interface {
+	GetContext(ctx context.Context, url string) (map[string][]string, []byte, error)
+	Prefetch(ctx context.Context) error
+	Run(ctx context.Context)
+}
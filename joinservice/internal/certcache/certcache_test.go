@@ -137,6 +137,94 @@ func TestCreateCertChainCache(t *testing.T) {
 	}
 }
 
+func TestCreateCertChainCacheTDX(t *testing.T) {
+	testCases := map[string]struct {
+		attVariant      variant.Variant
+		prefetchErr     error
+		wantCollateral  bool
+		wantPrefetch    bool
+		wantCachedCerts bool
+	}{
+		"tdx": {
+			attVariant:      variant.QEMUTDX{},
+			wantCollateral:  true,
+			wantPrefetch:    true,
+			wantCachedCerts: true,
+		},
+		"tdx, Intel PCS unavailable": {
+			attVariant:      variant.QEMUTDX{},
+			prefetchErr:     assert.AnError,
+			wantCollateral:  true,
+			wantPrefetch:    true,
+			wantCachedCerts: true,
+		},
+		"no caching": {
+			attVariant: variant.QEMUVTPM{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			collateral := &stubTDXCollateral{prefetchErr: tc.prefetchErr}
+			c := &Client{
+				attVariant:    tc.attVariant,
+				log:           logger.NewTest(t),
+				kubeClient:    &stubKubeClient{},
+				tdxCollateral: collateral,
+			}
+
+			cachedCerts, err := c.CreateCertChainCache(context.Background())
+			require.NoError(err)
+			assert.Equal(tc.wantPrefetch, collateral.prefetched)
+			if !tc.wantCachedCerts {
+				assert.Nil(cachedCerts)
+				return
+			}
+			require.NotNil(cachedCerts)
+			assert.Equal(collateral, cachedCerts.TDXCollateral())
+		})
+	}
+}
+
+func TestRefreshCollateral(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	collateral := &stubTDXCollateral{}
+	c := &Client{attVariant: variant.QEMUVTPM{}, tdxCollateral: collateral}
+	c.RefreshCollateral(ctx)
+	assert.False(collateral.ran)
+
+	c.attVariant = variant.QEMUTDX{}
+	c.RefreshCollateral(ctx)
+	assert.True(collateral.ran)
+}
+
+type stubTDXCollateral struct {
+	prefetchErr error
+	prefetched  bool
+	ran         bool
+}
+
+func (s *stubTDXCollateral) GetContext(context.Context, string) (map[string][]string, []byte, error) {
+	return nil, nil, nil
+}
+
+func (s *stubTDXCollateral) Prefetch(context.Context) error {
+	s.prefetched = true
+	return s.prefetchErr
+}
+
+func (s *stubTDXCollateral) Run(ctx context.Context) {
+	s.ran = true
+	<-ctx.Done()
+}
+
 type stubKdsClient struct {
 	askResponse  []byte
 	arkResponse  []byte
@@ -0,0 +1,314 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+// Package intelpcs implements a cache for the collateral Intel TDX quotes are verified against.
+//
+// The collateral (PCK CRLs, TCB info, QE identity and the root CA CRL) is retrieved from the
+// Intel PCS (Provisioning Certification Service) and stored in a ConfigMap, so that quotes
+// can still be verified while Intel PCS is unavailable.
+// Cached collateral is refreshed periodically and served until it expires.
+//
+// All collateral is signed by Intel and verified against Intel's root certificate on every use,
+// so neither the cache nor the ConfigMap need to be trusted.
+package intelpcs
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/google/go-tdx-guest/pcs"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// RefreshInterval is the interval after which cached collateral is retrieved from Intel PCS again.
+	RefreshInterval = time.Hour
+	// RootCACRLURL is the URL of the CRL of Intel's SGX root CA.
+	RootCACRLURL = "https://certificates.trustedservices.intel.com/IntelSGXRootCA.der"
+
+	// pcsTimeout is the timeout for a single request to Intel PCS.
+	pcsTimeout = 10 * time.Second
+	// kubeTimeout is the timeout for reading or writing the cache ConfigMap.
+	kubeTimeout = 10 * time.Second
+)
+
+// Cache caches collateral retrieved from Intel PCS in a ConfigMap.
+// It implements tdx.CollateralGetter and can be used as the collateral getter of the TDX validator.
+type Cache struct {
+	log             *logger.Logger
+	getter          collateralGetter
+	kubeClient      kubeClient
+	clock           clock.WithTicker
+	refreshInterval time.Duration
+
+	mux     sync.Mutex
+	entries map[string]entry
+}
+
+// New creates a new collateral cache, storing collateral in the TDX collateral cache ConfigMap.
+func New(log *logger.Logger, kubeClient kubeClient) *Cache {
+	return &Cache{
+		log:             log,
+		getter:          tdx.NewPCSGetter(&http.Client{Timeout: pcsTimeout}),
+		kubeClient:      kubeClient,
+		clock:           clock.RealClock{},
+		refreshInterval: RefreshInterval,
+		entries:         make(map[string]entry),
+	}
+}
+
+// GetContext returns the header and body of the collateral at the given URL.
+// Cached collateral is returned if it was retrieved within the refresh interval.
+// Otherwise, the collateral is retrieved from Intel PCS and cached.
+// If Intel PCS is unavailable, cached collateral is returned until it expires.
+func (c *Cache) GetContext(ctx context.Context, url string) (map[string][]string, []byte, error) {
+	log := c.log.With(zap.String("url", url))
+	now := c.clock.Now()
+
+	cached, ok := c.cached(ctx, url)
+	if ok && cached.fresh(now, c.refreshInterval) {
+		log.Debugf("Collateral cache hit")
+		return cached.Header, cached.Body, nil
+	}
+
+	log.Debugf("Retrieving collateral from Intel PCS")
+	header, body, err := c.getter.GetContext(ctx, url)
+	if err != nil {
+		if ok && now.Before(cached.NextUpdate) {
+			log.With(zap.Error(err), zap.Time("nextUpdate", cached.NextUpdate)).Warnf("Failed to retrieve collateral from Intel PCS, using cached collateral")
+			return cached.Header, cached.Body, nil
+		}
+		return nil, nil, fmt.Errorf("retrieving collateral from Intel PCS: %w", err)
+	}
+	c.store(url, header, body, now)
+
+	return header, body, nil
+}
+
+// Prefetch retrieves the collateral required to verify quotes of any TDX platform, i.e., the QE identity and the CRLs.
+// TCB info is specific to the FMSPC of a platform, and is cached when the first quote of a platform is verified.
+func (c *Cache) Prefetch(ctx context.Context) error {
+	var errs error
+	for _, url := range []string{
+		pcs.QeIdentityURL(),
+		pcs.PckCrlURL("platform"),
+		pcs.PckCrlURL("processor"),
+		RootCACRLURL,
+	} {
+		if _, _, err := c.GetContext(ctx, url); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// Refresh retrieves all collateral cached by this instance from Intel PCS again.
+// Collateral that can't be retrieved stays cached until it expires.
+func (c *Cache) Refresh(ctx context.Context) {
+	c.mux.Lock()
+	urls := make([]string, 0, len(c.entries))
+	for _, cached := range c.entries {
+		urls = append(urls, cached.URL)
+	}
+	c.mux.Unlock()
+
+	for _, url := range urls {
+		header, body, err := c.getter.GetContext(ctx, url)
+		if err != nil {
+			c.log.With(zap.Error(err), zap.String("url", url)).Warnf("Failed to refresh collateral")
+			continue
+		}
+		c.store(url, header, body, c.clock.Now())
+	}
+}
+
+// Run refreshes the cached collateral every refresh interval until ctx is done.
+func (c *Cache) Run(ctx context.Context) {
+	ticker := c.clock.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			c.log.Debugf("Refreshing collateral cache")
+			c.Refresh(ctx)
+		}
+	}
+}
+
+// cached returns the cached collateral for the given URL.
+// If the collateral held in memory is stale, the ConfigMap is checked for collateral
+// refreshed by another instance of the JoinService.
+func (c *Cache) cached(ctx context.Context, url string) (entry, bool) {
+	key := cacheKey(url)
+
+	c.mux.Lock()
+	cached, ok := c.entries[key]
+	c.mux.Unlock()
+	if ok && cached.fresh(c.clock.Now(), c.refreshInterval) {
+		return cached, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, kubeTimeout)
+	defer cancel()
+	raw, err := c.kubeClient.GetConfigMapData(ctx, constants.TDXCollateralCacheConfigMapName, key)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		c.log.With(zap.Error(err)).Warnf("Failed to read collateral cache configmap")
+	}
+	if err != nil || raw == "" {
+		return cached, ok
+	}
+	var stored entry
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil || stored.URL != url {
+		c.log.With(zap.String("key", key)).Warnf("Ignoring invalid collateral cache entry")
+		return cached, ok
+	}
+	if ok && !stored.Fetched.After(cached.Fetched) {
+		return cached, true
+	}
+
+	c.mux.Lock()
+	c.entries[key] = stored
+	c.mux.Unlock()
+	return stored, true
+}
+
+// store caches the given collateral in memory and in the ConfigMap.
+// Collateral without a known expiry date isn't cached.
+// Failing to write the ConfigMap isn't an error, since the collateral can still be served from memory.
+func (c *Cache) store(url string, header map[string][]string, body []byte, fetched time.Time) {
+	log := c.log.With(zap.String("url", url))
+	nextUpdate, err := nextUpdate(body)
+	if err != nil {
+		log.With(zap.Error(err)).Warnf("Not caching collateral with unknown expiry")
+		return
+	}
+	key := cacheKey(url)
+	newEntry := entry{
+		URL:        url,
+		Header:     header,
+		Body:       body,
+		Fetched:    fetched,
+		NextUpdate: nextUpdate,
+	}
+
+	c.mux.Lock()
+	c.entries[key] = newEntry
+	c.mux.Unlock()
+
+	raw, err := json.Marshal(newEntry)
+	if err != nil {
+		log.With(zap.Error(err)).Warnf("Failed to marshal collateral cache entry")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), kubeTimeout)
+	defer cancel()
+	if err := c.writeConfigMap(ctx, key, string(raw)); err != nil {
+		log.With(zap.Error(err)).Warnf("Failed to write collateral cache configmap")
+	}
+}
+
+// writeConfigMap writes the given key to the collateral cache ConfigMap, creating it if it doesn't exist.
+func (c *Cache) writeConfigMap(ctx context.Context, key, value string) error {
+	err := c.kubeClient.UpdateConfigMap(ctx, constants.TDXCollateralCacheConfigMapName, key, value)
+	if !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	err = c.kubeClient.CreateConfigMap(ctx, constants.TDXCollateralCacheConfigMapName, map[string]string{key: value})
+	if k8serrors.IsAlreadyExists(err) {
+		// Another instance of the JoinService created the ConfigMap in the meantime.
+		return c.kubeClient.UpdateConfigMap(ctx, constants.TDXCollateralCacheConfigMapName, key, value)
+	}
+	return err
+}
+
+// entry is a cached collateral response of Intel PCS.
+type entry struct {
+	URL    string              `json:"url"`
+	Header map[string][]string `json:"header"`
+	Body   []byte              `json:"body"`
+	// Fetched is the time the collateral was retrieved from Intel PCS.
+	Fetched time.Time `json:"fetched"`
+	// NextUpdate is the time the collateral expires.
+	NextUpdate time.Time `json:"nextUpdate"`
+}
+
+// fresh returns true if the collateral was retrieved within the refresh interval and is not expired.
+func (e entry) fresh(now time.Time, refreshInterval time.Duration) bool {
+	return now.Before(e.Fetched.Add(refreshInterval)) && now.Before(e.NextUpdate)
+}
+
+// nextUpdate returns the expiry date of the given TCB info, QE identity or CRL.
+func nextUpdate(body []byte) (time.Time, error) {
+	var signed struct {
+		TCBInfo *struct {
+			NextUpdate time.Time `json:"nextUpdate"`
+		} `json:"tcbInfo"`
+		EnclaveIdentity *struct {
+			NextUpdate time.Time `json:"nextUpdate"`
+		} `json:"enclaveIdentity"`
+	}
+	if err := json.Unmarshal(body, &signed); err == nil {
+		switch {
+		case signed.TCBInfo != nil && !signed.TCBInfo.NextUpdate.IsZero():
+			return signed.TCBInfo.NextUpdate, nil
+		case signed.EnclaveIdentity != nil && !signed.EnclaveIdentity.NextUpdate.IsZero():
+			return signed.EnclaveIdentity.NextUpdate, nil
+		}
+		return time.Time{}, errors.New("no next update in collateral")
+	}
+
+	crl, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing CRL: %w", err)
+	}
+	if crl.NextUpdate.IsZero() {
+		return time.Time{}, errors.New("no next update in CRL")
+	}
+	return crl.NextUpdate, nil
+}
+
+// invalidKeyChars matches all characters not allowed in ConfigMap keys.
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
+
+// cacheKey returns the ConfigMap key for the collateral at the given URL,
+// e.g., "api.trustedservices.intel.com-tdx-certification-v4-qe-identity".
+func cacheKey(url string) string {
+	key := strings.Trim(invalidKeyChars.ReplaceAllString(strings.TrimPrefix(url, "https://"), "-"), "-.")
+	if len(key) > 253 {
+		digest := sha256.Sum256([]byte(url))
+		key = hex.EncodeToString(digest[:])
+	}
+	return key
+}
+
+type collateralGetter interface {
+	GetContext(ctx context.Context, url string) (map[string][]string, []byte, error)
+}
+
+type kubeClient interface {
+	CreateConfigMap(ctx context.Context, name string, data map[string]string) error
+	GetConfigMapData(ctx context.Context, name, key string) (string, error)
+	UpdateConfigMap(ctx context.Context, name, key, value string) error
+}
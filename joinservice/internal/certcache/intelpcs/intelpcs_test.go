@@ -0,0 +1,390 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package intelpcs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/google/go-tdx-guest/abi"
+	"github.com/google/go-tdx-guest/pcs"
+	tdxtesting "github.com/google/go-tdx-guest/testing"
+	"github.com/google/go-tdx-guest/testing/testdata"
+	"github.com/google/go-tdx-guest/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	testclock "k8s.io/utils/clock/testing"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// sampleTime is a point in time at which the sample collateral of go-tdx-guest is valid.
+// The sample TCB info expires at 2023-07-18, the sample QE identity at 2023-07-08.
+var sampleTime = time.Date(2023, time.July, 1, 1, 0, 0, 0, time.UTC)
+
+func TestGet(t *testing.T) {
+	qeIdentityURL := pcs.QeIdentityURL()
+	unavailableErr := errors.New("unavailable")
+
+	testCases := map[string]struct {
+		kubeClient    *stubKubeClient
+		prepare       func(*Cache)
+		elapsed       time.Duration
+		getterErr     error
+		wantGetCalls  int
+		wantErr       bool
+		wantConfigMap bool
+	}{
+		"retrieve from PCS": {
+			kubeClient:    &stubKubeClient{},
+			wantGetCalls:  1,
+			wantConfigMap: true,
+		},
+		"fresh cache": {
+			kubeClient:    &stubKubeClient{},
+			prepare:       mustGet(qeIdentityURL),
+			elapsed:       RefreshInterval / 2,
+			wantGetCalls:  1,
+			wantConfigMap: true,
+		},
+		"stale cache is refreshed": {
+			kubeClient:    &stubKubeClient{},
+			prepare:       mustGet(qeIdentityURL),
+			elapsed:       2 * RefreshInterval,
+			wantGetCalls:  2,
+			wantConfigMap: true,
+		},
+		"PCS unavailable, stale cache is used": {
+			kubeClient:    &stubKubeClient{},
+			prepare:       mustGet(qeIdentityURL),
+			elapsed:       2 * RefreshInterval,
+			getterErr:     unavailableErr,
+			wantGetCalls:  2,
+			wantConfigMap: true,
+		},
+		"PCS unavailable, cache expired": {
+			kubeClient:   &stubKubeClient{},
+			prepare:      mustGet(qeIdentityURL),
+			elapsed:      30 * 24 * time.Hour,
+			getterErr:    unavailableErr,
+			wantGetCalls: 2,
+			wantErr:      true,
+		},
+		"PCS unavailable, nothing cached": {
+			kubeClient:   &stubKubeClient{},
+			getterErr:    unavailableErr,
+			wantGetCalls: 1,
+			wantErr:      true,
+		},
+		"writing configmap fails": {
+			kubeClient:   &stubKubeClient{updateErr: errors.New("failed")},
+			wantGetCalls: 1,
+		},
+		"reading configmap fails": {
+			kubeClient:   &stubKubeClient{getErr: errors.New("failed")},
+			prepare:      mustGet(qeIdentityURL),
+			elapsed:      RefreshInterval / 2,
+			wantGetCalls: 1,
+		},
+		"invalid configmap entry": {
+			kubeClient: &stubKubeClient{data: map[string]string{
+				cacheKey(qeIdentityURL): "invalid",
+			}},
+			wantGetCalls:  1,
+			wantConfigMap: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			clock := testclock.NewFakeClock(sampleTime)
+			getter := &stubGetter{}
+			cache := newTestCache(t, getter, tc.kubeClient, clock)
+			if tc.prepare != nil {
+				tc.prepare(cache)
+			}
+			clock.Step(tc.elapsed)
+			getter.err = tc.getterErr
+
+			header, body, err := cache.GetContext(context.Background(), qeIdentityURL)
+			assert.Equal(tc.wantGetCalls, getter.calls)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(testdata.QeIdentityBody, body)
+			assert.Equal(tdxtesting.QeIdentityHeader, header)
+			if tc.wantConfigMap {
+				assert.Contains(tc.kubeClient.data, cacheKey(qeIdentityURL))
+			}
+		})
+	}
+}
+
+func TestGetFromOtherInstance(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	clock := testclock.NewFakeClock(sampleTime)
+	kubeClient := &stubKubeClient{}
+	require.NoError(newTestCache(t, &stubGetter{}, kubeClient, clock).Prefetch(context.Background()))
+
+	// a second JoinService instance can use the collateral cached by the first one,
+	// even if Intel PCS is unavailable
+	clock.Step(2 * RefreshInterval)
+	getter := &stubGetter{err: errors.New("unavailable")}
+	cache := newTestCache(t, getter, kubeClient, clock)
+	_, body, err := cache.GetContext(context.Background(), pcs.PckCrlURL("platform"))
+	require.NoError(err)
+	assert.Equal(testdata.PckCrlBody, body)
+	assert.Equal(1, getter.calls)
+
+	// collateral refreshed by the first instance is used without retrieving it again
+	getter.err = nil
+	_, _, err = cache.GetContext(context.Background(), RootCACRLURL)
+	require.NoError(err)
+	refreshed := newTestCache(t, getter, kubeClient, clock)
+	_, _, err = refreshed.GetContext(context.Background(), RootCACRLURL)
+	require.NoError(err)
+	assert.Equal(2, getter.calls)
+}
+
+func TestRefresh(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	clock := testclock.NewFakeClock(sampleTime)
+	getter := &stubGetter{}
+	cache := newTestCache(t, getter, &stubKubeClient{}, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		cache.Run(ctx)
+		close(done)
+	}()
+
+	require.NoError(cache.Prefetch(ctx))
+	assert.Equal(4, getter.getCalls())
+
+	assert.Eventually(func() bool { return clock.HasWaiters() }, time.Second, 10*time.Millisecond)
+	clock.Step(RefreshInterval)
+	assert.Eventually(func() bool { return getter.getCalls() == 8 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestVerifyWithCache(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	quote, err := abi.QuoteToProto(testdata.RawQuote)
+	require.NoError(err)
+
+	clock := testclock.NewFakeClock(sampleTime)
+	kubeClient := &stubKubeClient{}
+	verifyQuote := func(cache *Cache) error {
+		return verify.TdxQuote(quote, &verify.Options{
+			CheckRevocations: true,
+			GetCollateral:    true,
+			Getter:           httpsGetter{cache: cache},
+			Now:              clock.Now(),
+		})
+	}
+	// The TCB levels of the sample TCB info don't match the sample quote. Verification fails at the
+	// TCB status check, after all collateral was retrieved and verified against Intel's root certificate.
+	errTCBStatus := "PCS's reported TDX TCB info failed TCB status check: no matching TCB level found"
+
+	cache := newTestCache(t, &stubGetter{}, kubeClient, clock)
+	assert.EqualError(verifyQuote(cache), errTCBStatus)
+
+	// Intel PCS outage: the collateral cached by another instance is used
+	clock.Step(24 * time.Hour)
+	cache = newTestCache(t, &stubGetter{err: errors.New("unavailable")}, kubeClient, clock)
+	assert.EqualError(verifyQuote(cache), errTCBStatus)
+
+	// once the cached collateral expires, it can't be retrieved anymore
+	clock.Step(14 * 24 * time.Hour)
+	err = verifyQuote(cache)
+	require.Error(err)
+	assert.NotEqual(errTCBStatus, err.Error())
+}
+
+func TestNextUpdate(t *testing.T) {
+	testCases := map[string]struct {
+		body           []byte
+		wantNextUpdate time.Time
+		wantErr        bool
+	}{
+		"tcb info": {
+			body:           testdata.TcbInfoBody,
+			wantNextUpdate: time.Date(2023, time.July, 18, 8, 42, 58, 0, time.UTC),
+		},
+		"qe identity": {
+			body:           testdata.QeIdentityBody,
+			wantNextUpdate: time.Date(2023, time.July, 8, 7, 24, 59, 0, time.UTC),
+		},
+		"pck crl": {
+			body: testdata.PckCrlBody,
+		},
+		"root ca crl": {
+			body: testdata.RootCrlBody,
+		},
+		"json without next update": {
+			body:    []byte(`{"tcbInfo":{}}`),
+			wantErr: true,
+		},
+		"invalid data": {
+			body:    []byte("invalid"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			nextUpdate, err := nextUpdate(tc.body)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.False(nextUpdate.IsZero())
+			if !tc.wantNextUpdate.IsZero() {
+				assert.True(tc.wantNextUpdate.Equal(nextUpdate))
+			}
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	assert := assert.New(t)
+
+	urls := []string{
+		pcs.QeIdentityURL(),
+		pcs.TcbInfoURL("50806f000000"),
+		pcs.TcbInfoURL("00806f050000"),
+		pcs.PckCrlURL("platform"),
+		pcs.PckCrlURL("processor"),
+		RootCACRLURL,
+		"https://example.com/" + strings.Repeat("a", 300),
+	}
+	keys := make(map[string]struct{})
+	for _, url := range urls {
+		key := cacheKey(url)
+		assert.Empty(validation.IsConfigMapKey(key), key)
+		keys[key] = struct{}{}
+	}
+	assert.Len(keys, len(urls))
+	assert.Equal("api.trustedservices.intel.com-tdx-certification-v4-qe-identity", cacheKey(pcs.QeIdentityURL()))
+}
+
+func newTestCache(t *testing.T, getter *stubGetter, kubeClient *stubKubeClient, clock *testclock.FakeClock) *Cache {
+	cache := New(logger.NewTest(t), kubeClient)
+	cache.getter = getter
+	cache.clock = clock
+	return cache
+}
+
+// mustGet returns a function retrieving the collateral at the given URL using the cache.
+func mustGet(url string) func(*Cache) {
+	return func(c *Cache) {
+		if _, _, err := c.GetContext(context.Background(), url); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// httpsGetter retrieves collateral from the cache for go-tdx-guest, which doesn't pass a context.
+type httpsGetter struct {
+	cache *Cache
+}
+
+func (g httpsGetter) Get(url string) (map[string][]string, []byte, error) {
+	return g.cache.GetContext(context.Background(), url)
+}
+
+// stubGetter serves the sample collateral of go-tdx-guest.
+type stubGetter struct {
+	mux   sync.Mutex
+	calls int
+	err   error
+}
+
+func (s *stubGetter) GetContext(_ context.Context, url string) (map[string][]string, []byte, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.calls++
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	// the sample quote was issued by the platform CA
+	if url == pcs.PckCrlURL("processor") {
+		url = pcs.PckCrlURL("platform")
+	}
+	return tdxtesting.TestGetter.Get(url)
+}
+
+func (s *stubGetter) getCalls() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.calls
+}
+
+type stubKubeClient struct {
+	data      map[string]string
+	getErr    error
+	updateErr error
+}
+
+func (s *stubKubeClient) CreateConfigMap(_ context.Context, name string, data map[string]string) error {
+	if s.data != nil {
+		return k8serrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	s.data = make(map[string]string)
+	for key, value := range data {
+		s.data[key] = value
+	}
+	return nil
+}
+
+func (s *stubKubeClient) GetConfigMapData(_ context.Context, name, key string) (string, error) {
+	if s.getErr != nil {
+		return "", s.getErr
+	}
+	if s.data == nil {
+		return "", k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return s.data[key], nil
+}
+
+func (s *stubKubeClient) UpdateConfigMap(_ context.Context, name, key, value string) error {
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	if s.data == nil {
+		return k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	s.data[key] = value
+	return nil
+}
@@ -0,0 +1,401 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package joinpolicy decides whether a node that passed attestation may join the cluster.
+
+Attestation only proves that a node runs a genuine Constellation image.
+The join policy additionally restricts which cloud instances may join, how many nodes may join at the same time,
+and whether joins need to be approved by an administrator.
+
+The policy is read from the internal-config ConfigMap on every join request, so changes take effect immediately.
+If no policy is configured, all nodes that pass attestation may join.
+Denied joins are recorded as Kubernetes events for the join service.
+*/
+package joinpolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud/metadata"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/kubernetes"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Reasons of the events recorded for denied joins.
+const (
+	reasonNotAllowed       = "JoinNotAllowed"
+	reasonTooManyJoins     = "JoinThrottled"
+	reasonApprovalRequired = "JoinApprovalRequired"
+	reasonApprovalDenied   = "JoinApprovalDenied"
+	reasonApprovalMismatch = "JoinApprovalMismatch"
+)
+
+// joinSlotGracePeriod is the time a node keeps its join slot after reserving it,
+// even if it isn't listed as joining node yet.
+// It covers the time between reserving the slot and recording the node as joining.
+const joinSlotGracePeriod = time.Minute
+
+// Policy restricts which nodes may join the cluster.
+type Policy struct {
+	// AllowedInstances lists the provider IDs or names of instances that may join.
+	AllowedInstances []string `json:"allowedInstances,omitempty"`
+	// AllowedScalingGroups lists the IDs of scaling groups whose instances may join.
+	// If neither instances nor scaling groups are listed, all instances may join.
+	AllowedScalingGroups []string `json:"allowedScalingGroups,omitempty"`
+	// MaxConcurrentJoins limits the number of nodes that may join at the same time.
+	// A node is joining from receiving its join ticket until it is registered in Kubernetes.
+	// Each joining node holds one of MaxConcurrentJoins join slots, which are reserved atomically.
+	// Zero means no limit.
+	MaxConcurrentJoins int `json:"maxConcurrentJoins,omitempty"`
+	// RequireApproval requires an administrator to approve the join of each node
+	// that isn't created by the node operator.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// Parse parses a JSON encoded join policy. An empty policy allows all joins.
+// Unknown fields are rejected, so a misspelled restriction isn't silently ignored.
+func Parse(raw string) (Policy, error) {
+	var policy Policy
+	if raw == "" {
+		return policy, nil
+	}
+	decoder := json.NewDecoder(bytes.NewBufferString(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return Policy{}, err
+	}
+	if policy.MaxConcurrentJoins < 0 {
+		return Policy{}, fmt.Errorf("maxConcurrentJoins must not be negative, got %d", policy.MaxConcurrentJoins)
+	}
+	return policy, nil
+}
+
+// restrictsInstances returns true if only listed instances or scaling groups may join.
+func (p Policy) restrictsInstances() bool {
+	return len(p.AllowedInstances) > 0 || len(p.AllowedScalingGroups) > 0
+}
+
+// allows returns true if the instance is on one of the allow-lists.
+func (p Policy) allows(instance metadata.InstanceMetadata) bool {
+	if slices.Contains(p.AllowedInstances, instance.ProviderID) || slices.Contains(p.AllowedInstances, instance.Name) {
+		return true
+	}
+	return instance.ScalingGroupID != "" && slices.Contains(p.AllowedScalingGroups, instance.ScalingGroupID)
+}
+
+// Request is a join request to check against the policy.
+type Request struct {
+	// PeerAddr is the address the request originates from.
+	PeerAddr string
+	// NodeName is the name of the node, as requested in its kubelet certificate.
+	NodeName string
+	// ComponentsReference is the name of the ConfigMap listing the Kubernetes components of the node.
+	ComponentsReference string
+	// IsControlPlane is true if the node joins as control-plane node.
+	IsControlPlane bool
+}
+
+// Engine checks join requests against the join policy.
+type Engine struct {
+	log        *logger.Logger
+	metadata   metadataAPI
+	kubeClient kubeClient
+}
+
+// New creates a new Engine.
+func New(log *logger.Logger, metadata metadataAPI, kubeClient kubeClient) *Engine {
+	return &Engine{
+		log:        log,
+		metadata:   metadata,
+		kubeClient: kubeClient,
+	}
+}
+
+// Check returns nil if the join request is allowed by the policy.
+// Otherwise, a gRPC status error is returned and the denial is recorded as Kubernetes event.
+// If manual approval is required, Check requests the approval of the node as a side effect.
+func (e *Engine) Check(ctx context.Context, req Request) error {
+	log := e.log.With(zap.String("peerAddress", req.PeerAddr), zap.String("nodeName", req.NodeName))
+
+	rawPolicy, err := e.kubeClient.GetConfigMapData(ctx, constants.InternalConfigMap, constants.JoinPolicyKey)
+	if err != nil {
+		return status.Errorf(codes.Internal, "getting join policy: %s", err)
+	}
+	// an invalid policy denies all joins, since the intended restrictions are unknown
+	policy, err := Parse(rawPolicy)
+	if err != nil {
+		return status.Errorf(codes.Internal, "parsing join policy: %s", err)
+	}
+
+	nodeName, err := kubernetes.NodeName(req.NodeName)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid node name: %s", err)
+	}
+
+	var instance *metadata.InstanceMetadata
+	if policy.restrictsInstances() || policy.RequireApproval {
+		instance, err = e.findInstance(ctx, req.PeerAddr)
+		if err != nil {
+			return status.Errorf(codes.Internal, "looking up instance of peer: %s", err)
+		}
+	}
+
+	if policy.restrictsInstances() {
+		if instance == nil {
+			return e.deny(ctx, log, codes.PermissionDenied, reasonNotAllowed,
+				"node %s (%s) is not an instance of the cluster's cloud environment", nodeName, req.PeerAddr)
+		}
+		if !policy.allows(*instance) {
+			return e.deny(ctx, log, codes.PermissionDenied, reasonNotAllowed,
+				"instance %s of node %s is not allowed to join by the join policy", instance.ProviderID, nodeName)
+		}
+	}
+
+	if !policy.RequireApproval && policy.MaxConcurrentJoins == 0 {
+		return nil
+	}
+	joiningNodes, err := e.kubeClient.ListJoiningNodes(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "listing joining nodes: %s", err)
+	}
+
+	if policy.RequireApproval {
+		if err := e.checkApproval(ctx, log, req, nodeName, instance, joiningNodes); err != nil {
+			return err
+		}
+	}
+
+	if policy.MaxConcurrentJoins > 0 {
+		if err := e.reserveJoinSlot(ctx, log, policy.MaxConcurrentJoins, nodeName, joiningNodes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reserveJoinSlot reserves one of the join slots for the node.
+// Slots are reserved atomically, so concurrent requests can't exceed the limit.
+// A slot is free if its holder finished joining, or never started to join after the grace period.
+func (e *Engine) reserveJoinSlot(ctx context.Context, log *logger.Logger, maxJoins int, nodeName string,
+	joiningNodes []kubernetes.JoiningNode,
+) error {
+	slots, err := e.kubeClient.ListJoinSlots(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "listing join slots: %s", err)
+	}
+	slotsByName := make(map[string]kubernetes.JoinSlot, len(slots))
+	for _, slot := range slots {
+		slotsByName[slot.Name] = slot
+	}
+
+	joining := joiningNodeNames(joiningNodes)
+	var held int
+	for i := 0; i < maxJoins; i++ {
+		slot, ok := slotsByName[joinSlotName(i)]
+		if !ok {
+			continue
+		}
+		if slot.Holder == nodeName {
+			// the node is retrying its join and already holds a slot
+			log.Debugf("Node already holds join slot %s", slot.Name)
+			return nil
+		}
+		if slotHeld(slot, joining) {
+			held++
+		}
+	}
+
+	for i := 0; i < maxJoins; i++ {
+		slot, ok := slotsByName[joinSlotName(i)]
+		if !ok {
+			slot = kubernetes.JoinSlot{Name: joinSlotName(i)}
+		} else if slotHeld(slot, joining) {
+			continue
+		}
+		err := e.kubeClient.ReserveJoinSlot(ctx, slot, nodeName)
+		if errors.Is(err, kubernetes.ErrJoinSlotTaken) {
+			// another join service instance reserved the slot first
+			held++
+			continue
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "reserving join slot: %s", err)
+		}
+		log.Debugf("Reserved join slot %s", slot.Name)
+		return nil
+	}
+
+	return e.deny(ctx, log, codes.ResourceExhausted, reasonTooManyJoins,
+		"node %s has to wait for %d joining nodes, at most %d nodes may join at the same time",
+		nodeName, held, maxJoins)
+}
+
+// checkApproval returns nil if the join of the node was approved.
+// Nodes created by the node operator are approved implicitly.
+// For other nodes, approval is requested by creating a JoiningNode marked as pending.
+func (e *Engine) checkApproval(ctx context.Context, log *logger.Logger, req Request, nodeName string,
+	instance *metadata.InstanceMetadata, joiningNodes []kubernetes.JoiningNode,
+) error {
+	approvalReq := kubernetes.ApprovalRequest{
+		NodeName:            req.NodeName,
+		ComponentsReference: req.ComponentsReference,
+		IsControlPlane:      req.IsControlPlane,
+		PeerIP:              peerIP(req.PeerAddr),
+	}
+	if instance != nil {
+		approvalReq.ProviderID = instance.ProviderID
+	}
+
+	var approval kubernetes.JoiningNode
+	for _, joiningNode := range joiningNodes {
+		if joiningNode.NodeName == nodeName && joiningNode.Approval != "" {
+			approval = joiningNode
+		}
+	}
+
+	// an approval only applies to the instance it was requested for,
+	// so another instance can't join under the name of an approved node
+	if (approval.Approval == kubernetes.ApprovalApproved || approval.Approval == kubernetes.ApprovalPending) &&
+		(approval.ProviderID != approvalReq.ProviderID || approval.PeerIP != approvalReq.PeerIP) {
+		return e.deny(ctx, log, codes.PermissionDenied, reasonApprovalMismatch,
+			"approval of node %s was requested by instance %s (%s), but the node joins from instance %s (%s)",
+			nodeName, describeInstance(approval.ProviderID), approval.PeerIP,
+			describeInstance(approvalReq.ProviderID), approvalReq.PeerIP)
+	}
+
+	switch approval.Approval {
+	case kubernetes.ApprovalApproved:
+		log.Infof("Join was approved manually")
+		return nil
+	case kubernetes.ApprovalDenied:
+		return e.deny(ctx, log, codes.PermissionDenied, reasonApprovalDenied, "join of node %s was denied", nodeName)
+	}
+
+	if instance != nil {
+		pending, err := e.kubeClient.IsPendingJoin(ctx, instance.ProviderID)
+		if err != nil {
+			return status.Errorf(codes.Internal, "checking pending nodes: %s", err)
+		}
+		if pending {
+			log.Infof("Join is approved, since the node was created by the node operator")
+			return nil
+		}
+	}
+
+	if approval.Approval != kubernetes.ApprovalPending {
+		if err := e.kubeClient.RequestJoinApproval(ctx, approvalReq); err != nil {
+			return status.Errorf(codes.Internal, "requesting join approval: %s", err)
+		}
+	}
+	return e.deny(ctx, log, codes.PermissionDenied, reasonApprovalRequired,
+		"join of node %s from instance %s (%s) requires approval: kubectl annotate joiningnode %s %s=%s --overwrite",
+		nodeName, describeInstance(approvalReq.ProviderID), approvalReq.PeerIP,
+		nodeName, constants.JoinApprovalAnnotationKey, kubernetes.ApprovalApproved)
+}
+
+// findInstance returns the instance with the VPC IP of the peer, or nil if no such instance exists.
+func (e *Engine) findInstance(ctx context.Context, peerAddr string) (*metadata.InstanceMetadata, error) {
+	peerIP := peerIP(peerAddr)
+	if peerIP == "" {
+		// the peer can't be mapped to an instance
+		return nil, nil
+	}
+	instances, err := e.metadata.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing instances: %w", err)
+	}
+	for _, instance := range instances {
+		if instance.VPCIP == peerIP {
+			return &instance, nil
+		}
+	}
+	return nil, nil
+}
+
+// deny logs and records the denial of a join and returns the error for the node.
+func (e *Engine) deny(ctx context.Context, log *logger.Logger, code codes.Code, reason, format string, args ...any) error {
+	message := fmt.Sprintf(format, args...)
+	log.With(zap.String("reason", reason)).Warnf("Join denied: %s", message)
+	if err := e.kubeClient.RecordWarning(ctx, reason, message); err != nil {
+		log.With(zap.Error(err)).Errorf("Failed recording join denial")
+	}
+	return status.Error(code, message)
+}
+
+// peerIP returns the IP of the peer address, or an empty string if the address is invalid.
+func peerIP(peerAddr string) string {
+	ip, _, err := net.SplitHostPort(peerAddr)
+	if err != nil {
+		return ""
+	}
+	return ip
+}
+
+// describeInstance returns the provider ID for display, or a placeholder if the instance is unknown.
+func describeInstance(providerID string) string {
+	if providerID == "" {
+		return "<unknown>"
+	}
+	return providerID
+}
+
+// joinSlotName returns the name of the i-th join slot.
+func joinSlotName(i int) string {
+	return fmt.Sprintf("join-slot-%d", i)
+}
+
+// joiningNodeNames returns the names of the nodes that are currently joining.
+// Nodes that wait for approval or were denied haven't received a join ticket and aren't joining.
+func joiningNodeNames(joiningNodes []kubernetes.JoiningNode) map[string]struct{} {
+	joining := map[string]struct{}{}
+	for _, joiningNode := range joiningNodes {
+		if joiningNode.Approval == kubernetes.ApprovalPending || joiningNode.Approval == kubernetes.ApprovalDenied {
+			continue
+		}
+		joining[joiningNode.NodeName] = struct{}{}
+	}
+	return joining
+}
+
+// slotHeld returns true if the holder of the slot is still joining,
+// or reserved the slot recently and may not be recorded as joining yet.
+func slotHeld(slot kubernetes.JoinSlot, joining map[string]struct{}) bool {
+	if slot.Holder == "" {
+		return false
+	}
+	if _, ok := joining[slot.Holder]; ok {
+		return true
+	}
+	return time.Since(slot.AcquireTime) < joinSlotGracePeriod
+}
+
+type metadataAPI interface {
+	// List retrieves all instances belonging to the current Constellation.
+	List(ctx context.Context) ([]metadata.InstanceMetadata, error)
+}
+
+type kubeClient interface {
+	GetConfigMapData(ctx context.Context, name, key string) (string, error)
+	ListJoiningNodes(ctx context.Context) ([]kubernetes.JoiningNode, error)
+	RequestJoinApproval(ctx context.Context, req kubernetes.ApprovalRequest) error
+	ListJoinSlots(ctx context.Context) ([]kubernetes.JoinSlot, error)
+	ReserveJoinSlot(ctx context.Context, slot kubernetes.JoinSlot, nodeName string) error
+	IsPendingJoin(ctx context.Context, providerID string) (bool, error)
+	RecordWarning(ctx context.Context, reason, message string) error
+}
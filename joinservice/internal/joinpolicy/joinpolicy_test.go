@@ -0,0 +1,497 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package joinpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud/metadata"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestParse(t *testing.T) {
+	testCases := map[string]struct {
+		raw        string
+		wantPolicy Policy
+		wantErr    bool
+	}{
+		"empty": {},
+		"full policy": {
+			raw: `{"allowedInstances":["worker-0"],"allowedScalingGroups":["group"],"maxConcurrentJoins":2,"requireApproval":true}`,
+			wantPolicy: Policy{
+				AllowedInstances:     []string{"worker-0"},
+				AllowedScalingGroups: []string{"group"},
+				MaxConcurrentJoins:   2,
+				RequireApproval:      true,
+			},
+		},
+		"unknown field": {
+			raw:     `{"allowedInstance":["worker-0"]}`,
+			wantErr: true,
+		},
+		"negative max concurrent joins": {
+			raw:     `{"maxConcurrentJoins":-1}`,
+			wantErr: true,
+		},
+		"invalid json": {
+			raw:     `{`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.raw)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantPolicy, policy)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	someErr := errors.New("failed")
+	worker := metadata.InstanceMetadata{
+		Name:           "worker-0",
+		ProviderID:     "gcp:///project/zone/worker-0",
+		VPCIP:          "192.0.2.1",
+		ScalingGroupID: "projects/project/zones/zone/instanceGroupManagers/workers",
+	}
+	instances := &stubMetadata{instances: []metadata.InstanceMetadata{worker}}
+	req := Request{
+		PeerAddr:            "192.0.2.1:1234",
+		NodeName:            "Worker_0",
+		ComponentsReference: "components",
+	}
+	approvalReq := kubernetes.ApprovalRequest{
+		NodeName:            "Worker_0",
+		ComponentsReference: "components",
+		ProviderID:          "gcp:///project/zone/worker-0",
+		PeerIP:              "192.0.2.1",
+	}
+	now := time.Now()
+	longAgo := now.Add(-time.Hour)
+
+	testCases := map[string]struct {
+		policy           string
+		metadata         *stubMetadata
+		kubeClient       *stubKubeClient
+		req              Request
+		wantCode         codes.Code
+		wantReason       string
+		wantApprovalReq  bool
+		wantReservedSlot string
+	}{
+		"no policy": {
+			metadata:   &stubMetadata{listErr: someErr},
+			kubeClient: &stubKubeClient{},
+			req:        req,
+		},
+		"instance allowed by provider ID": {
+			policy:     `{"allowedInstances":["gcp:///project/zone/worker-0"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        req,
+		},
+		"instance allowed by name": {
+			policy:     `{"allowedInstances":["worker-0"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        req,
+		},
+		"instance allowed by scaling group": {
+			policy:     `{"allowedScalingGroups":["projects/project/zones/zone/instanceGroupManagers/workers"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        req,
+		},
+		"instance not allowed": {
+			policy:     `{"allowedInstances":["worker-1"],"allowedScalingGroups":["other-group"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonNotAllowed,
+		},
+		"peer is no instance": {
+			policy:     `{"allowedInstances":["worker-0"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        Request{PeerAddr: "198.51.100.1:1234", NodeName: "worker-0"},
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonNotAllowed,
+		},
+		"unknown peer address": {
+			policy:     `{"allowedInstances":["worker-0"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        Request{PeerAddr: "unknown", NodeName: "worker-0"},
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonNotAllowed,
+		},
+		"listing instances fails": {
+			policy:     `{"allowedInstances":["worker-0"]}`,
+			metadata:   &stubMetadata{listErr: someErr},
+			kubeClient: &stubKubeClient{},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"invalid policy": {
+			policy:     `{"allowedInstance":["worker-0"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"getting policy fails": {
+			metadata:   instances,
+			kubeClient: &stubKubeClient{getConfigMapDataErr: someErr},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"free join slot is reserved": {
+			policy:   `{"maxConcurrentJoins":2}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{
+					{NodeName: "worker-1"},
+					{NodeName: "worker-2", Approval: kubernetes.ApprovalPending},
+				},
+				joinSlots: []kubernetes.JoinSlot{{Name: "join-slot-0", Holder: "worker-1", AcquireTime: longAgo}},
+			},
+			req:              req,
+			wantReservedSlot: "join-slot-1",
+		},
+		"join slots exhausted": {
+			policy:   `{"maxConcurrentJoins":2}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{
+					{NodeName: "worker-1"},
+					{NodeName: "control-plane-0"},
+					{NodeName: "control-plane-0", Approval: kubernetes.ApprovalApproved},
+				},
+				joinSlots: []kubernetes.JoinSlot{
+					{Name: "join-slot-0", Holder: "worker-1", AcquireTime: longAgo},
+					{Name: "join-slot-1", Holder: "control-plane-0", AcquireTime: longAgo},
+				},
+			},
+			req:        req,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: reasonTooManyJoins,
+		},
+		"recently reserved join slot is held": {
+			policy:   `{"maxConcurrentJoins":1}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joinSlots: []kubernetes.JoinSlot{{Name: "join-slot-0", Holder: "worker-1", AcquireTime: now}},
+			},
+			req:        req,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: reasonTooManyJoins,
+		},
+		"join slot of finished join is reused": {
+			policy:   `{"maxConcurrentJoins":1}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{
+					{NodeName: "worker-1", Approval: kubernetes.ApprovalPending},
+				},
+				joinSlots: []kubernetes.JoinSlot{{Name: "join-slot-0", Holder: "worker-1", AcquireTime: longAgo}},
+			},
+			req:              req,
+			wantReservedSlot: "join-slot-0",
+		},
+		"slots beyond the limit are ignored": {
+			policy:   `{"maxConcurrentJoins":1}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{{NodeName: "worker-1"}},
+				joinSlots: []kubernetes.JoinSlot{
+					{Name: "join-slot-0", Holder: "worker-1", AcquireTime: now},
+					{Name: "join-slot-1", Holder: "worker-0", AcquireTime: now},
+				},
+			},
+			req:        req,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: reasonTooManyJoins,
+		},
+		"requesting node already holds a join slot": {
+			policy:   `{"maxConcurrentJoins":1}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{{NodeName: "worker-0"}},
+				joinSlots:    []kubernetes.JoinSlot{{Name: "join-slot-0", Holder: "worker-0", AcquireTime: now}},
+			},
+			req: req,
+		},
+		"join slot reserved concurrently": {
+			policy:     `{"maxConcurrentJoins":1}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{reserveJoinSlotErr: kubernetes.ErrJoinSlotTaken},
+			req:        req,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: reasonTooManyJoins,
+		},
+		"reserving join slot fails": {
+			policy:     `{"maxConcurrentJoins":1}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{reserveJoinSlotErr: someErr},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"listing join slots fails": {
+			policy:     `{"maxConcurrentJoins":1}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{listJoinSlotsErr: someErr},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"listing joining nodes fails": {
+			policy:     `{"maxConcurrentJoins":1}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{listJoiningNodesErr: someErr},
+			req:        req,
+			wantCode:   codes.Internal,
+		},
+		"approval requested": {
+			policy:          `{"requireApproval":true}`,
+			metadata:        instances,
+			kubeClient:      &stubKubeClient{},
+			req:             req,
+			wantCode:        codes.PermissionDenied,
+			wantReason:      reasonApprovalRequired,
+			wantApprovalReq: true,
+		},
+		"approval already requested": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalPending, ProviderID: worker.ProviderID, PeerIP: worker.VPCIP},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalRequired,
+		},
+		"approved": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalApproved, ProviderID: worker.ProviderID, PeerIP: worker.VPCIP},
+			}},
+			req: req,
+		},
+		"approved for another instance": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalApproved, ProviderID: "gcp:///project/zone/worker-1", PeerIP: worker.VPCIP},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalMismatch,
+		},
+		"approved for another peer IP": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalApproved, ProviderID: worker.ProviderID, PeerIP: "192.0.2.2"},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalMismatch,
+		},
+		"approved without recorded instance": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalApproved},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalMismatch,
+		},
+		"approval requested by another instance": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalPending, ProviderID: "gcp:///project/zone/worker-1", PeerIP: "192.0.2.2"},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalMismatch,
+		},
+		"approval denied": {
+			policy:   `{"requireApproval":true}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{joiningNodes: []kubernetes.JoiningNode{
+				{NodeName: "worker-0", Approval: kubernetes.ApprovalDenied},
+			}},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonApprovalDenied,
+		},
+		"created by node operator": {
+			policy:     `{"requireApproval":true}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{pendingJoins: []string{"gcp:///project/zone/worker-0"}},
+			req:        req,
+		},
+		"approved but join slots exhausted": {
+			policy:   `{"requireApproval":true,"maxConcurrentJoins":1}`,
+			metadata: instances,
+			kubeClient: &stubKubeClient{
+				joiningNodes: []kubernetes.JoiningNode{
+					{NodeName: "worker-0", Approval: kubernetes.ApprovalApproved, ProviderID: worker.ProviderID, PeerIP: worker.VPCIP},
+					{NodeName: "worker-1"},
+				},
+				joinSlots: []kubernetes.JoinSlot{{Name: "join-slot-0", Holder: "worker-1", AcquireTime: longAgo}},
+			},
+			req:        req,
+			wantCode:   codes.ResourceExhausted,
+			wantReason: reasonTooManyJoins,
+		},
+		"requesting approval fails": {
+			policy:          `{"requireApproval":true}`,
+			metadata:        instances,
+			kubeClient:      &stubKubeClient{requestJoinApprovalErr: someErr},
+			req:             req,
+			wantCode:        codes.Internal,
+			wantApprovalReq: true,
+		},
+		"recording event fails": {
+			policy:     `{"allowedInstances":["worker-1"]}`,
+			metadata:   instances,
+			kubeClient: &stubKubeClient{recordWarningErr: someErr},
+			req:        req,
+			wantCode:   codes.PermissionDenied,
+			wantReason: reasonNotAllowed,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			tc.kubeClient.policy = tc.policy
+			engine := New(logger.NewTest(t), tc.metadata, tc.kubeClient)
+
+			err := engine.Check(context.Background(), tc.req)
+
+			if tc.wantApprovalReq {
+				assert.Equal([]kubernetes.ApprovalRequest{approvalReq}, tc.kubeClient.approvalRequests)
+			} else {
+				assert.Empty(tc.kubeClient.approvalRequests)
+			}
+			if tc.wantReservedSlot != "" {
+				assert.Equal([]string{tc.wantReservedSlot}, tc.kubeClient.reservedSlots)
+			} else if tc.wantCode == codes.OK {
+				assert.Empty(tc.kubeClient.reservedSlots)
+			}
+			if tc.wantReason != "" {
+				assert.Equal([]string{tc.wantReason}, tc.kubeClient.eventReasons)
+			} else {
+				assert.Empty(tc.kubeClient.eventReasons)
+			}
+			if tc.wantCode == codes.OK {
+				assert.NoError(err)
+				return
+			}
+			assert.Equal(tc.wantCode, status.Code(err))
+		})
+	}
+}
+
+type stubMetadata struct {
+	instances []metadata.InstanceMetadata
+	listErr   error
+}
+
+func (s *stubMetadata) List(_ context.Context) ([]metadata.InstanceMetadata, error) {
+	return s.instances, s.listErr
+}
+
+type stubKubeClient struct {
+	policy              string
+	getConfigMapDataErr error
+
+	joiningNodes        []kubernetes.JoiningNode
+	listJoiningNodesErr error
+
+	pendingJoins []string
+
+	approvalRequests       []kubernetes.ApprovalRequest
+	requestJoinApprovalErr error
+
+	joinSlots          []kubernetes.JoinSlot
+	listJoinSlotsErr   error
+	reservedSlots      []string
+	reserveJoinSlotErr error
+
+	eventReasons     []string
+	recordWarningErr error
+}
+
+func (s *stubKubeClient) GetConfigMapData(_ context.Context, name, key string) (string, error) {
+	if name != constants.InternalConfigMap || key != constants.JoinPolicyKey {
+		return "", errors.New("unexpected config map data requested")
+	}
+	return s.policy, s.getConfigMapDataErr
+}
+
+func (s *stubKubeClient) ListJoiningNodes(_ context.Context) ([]kubernetes.JoiningNode, error) {
+	return s.joiningNodes, s.listJoiningNodesErr
+}
+
+func (s *stubKubeClient) RequestJoinApproval(_ context.Context, req kubernetes.ApprovalRequest) error {
+	s.approvalRequests = append(s.approvalRequests, req)
+	return s.requestJoinApprovalErr
+}
+
+func (s *stubKubeClient) ListJoinSlots(_ context.Context) ([]kubernetes.JoinSlot, error) {
+	return s.joinSlots, s.listJoinSlotsErr
+}
+
+func (s *stubKubeClient) ReserveJoinSlot(_ context.Context, slot kubernetes.JoinSlot, nodeName string) error {
+	if s.reserveJoinSlotErr != nil {
+		return s.reserveJoinSlotErr
+	}
+	s.reservedSlots = append(s.reservedSlots, slot.Name)
+	return nil
+}
+
+func (s *stubKubeClient) IsPendingJoin(_ context.Context, providerID string) (bool, error) {
+	for _, pending := range s.pendingJoins {
+		if pending == providerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *stubKubeClient) RecordWarning(_ context.Context, reason, _ string) error {
+	s.eventReasons = append(s.eventReasons, reason)
+	return s.recordWarningErr
+}
@@ -10,6 +10,7 @@ package kubernetes
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -17,15 +18,21 @@ import (
 
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 )
 
+// joinServiceName is the name of the join service DaemonSet, which events are recorded for.
+const joinServiceName = "join-service"
+
 // Client is a kubernetes client.
 type Client struct {
 	client    *kubernetes.Clientset
@@ -76,6 +83,24 @@ func (c *Client) GetConfigMapData(ctx context.Context, name, key string) (string
 	return cm.Data[key], nil
 }
 
+// GetNodeAnnotation returns the value of the annotation with the given key on the node with the given name.
+// If the node does not exist, an empty string is returned.
+func (c *Client) GetNodeAnnotation(ctx context.Context, nodeName, key string) (string, error) {
+	k8sNodeName, err := k8sCompliantHostname(nodeName)
+	if err != nil {
+		return "", err
+	}
+	node, err := c.client.CoreV1().Nodes().Get(ctx, k8sNodeName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get node: %w", err)
+	}
+
+	return node.Annotations[key], nil
+}
+
 // GetK8sComponentsRefFromNodeVersionCRD returns the K8sComponentsRef from the node version CRD.
 func (c *Client) GetK8sComponentsRefFromNodeVersionCRD(ctx context.Context, nodeName string) (string, error) {
 	nodeVersionResource := schema.GroupVersionResource{Group: "update.edgeless.systems", Version: "v1alpha1", Resource: "nodeversions"}
@@ -149,6 +174,241 @@ func (c *Client) addWorkerToJoiningNodes(ctx context.Context, joiningNode *unstr
 	return nil
 }
 
+// Values of the join approval annotation of JoiningNodes.
+const (
+	// ApprovalPending marks a JoiningNode created to request manual approval of a join.
+	ApprovalPending = "pending"
+	// ApprovalApproved marks a JoiningNode whose join was approved by an administrator.
+	ApprovalApproved = "approved"
+	// ApprovalDenied marks a JoiningNode whose join was denied by an administrator.
+	ApprovalDenied = "denied"
+)
+
+// JoiningNode is a node that received a join ticket, or is waiting for approval to receive one.
+type JoiningNode struct {
+	// NodeName is the Kubernetes name of the node.
+	NodeName string
+	// Approval is the value of the join approval annotation, if set.
+	Approval string
+	// ProviderID and PeerIP identify the instance that requested the approval, if approval was requested.
+	ProviderID string
+	PeerIP     string
+}
+
+// ApprovalRequest is a request for the manual approval of a join.
+type ApprovalRequest struct {
+	// NodeName is the name of the node, as requested in its kubelet certificate.
+	NodeName string
+	// ComponentsReference is the name of the ConfigMap listing the Kubernetes components of the node.
+	ComponentsReference string
+	// IsControlPlane is true if the node joins as control-plane node.
+	IsControlPlane bool
+	// ProviderID is the provider ID of the instance requesting to join. It's empty if the instance is unknown.
+	ProviderID string
+	// PeerIP is the IP address the join request originates from.
+	PeerIP string
+}
+
+// ListJoiningNodes returns all JoiningNode resources.
+func (c *Client) ListJoiningNodes(ctx context.Context) ([]JoiningNode, error) {
+	joiningNodeResource := schema.GroupVersionResource{Group: "update.edgeless.systems", Version: "v1alpha1", Resource: "joiningnodes"}
+	list, err := c.dynClient.Resource(joiningNodeResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list joining nodes: %w", err)
+	}
+
+	joiningNodes := make([]JoiningNode, 0, len(list.Items))
+	for _, item := range list.Items {
+		nodeName, _, err := unstructured.NestedString(item.Object, "spec", "name")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get node name of joining node %s: %w", item.GetName(), err)
+		}
+		annotations := item.GetAnnotations()
+		joiningNodes = append(joiningNodes, JoiningNode{
+			NodeName:   nodeName,
+			Approval:   annotations[constants.JoinApprovalAnnotationKey],
+			ProviderID: annotations[constants.JoinApprovalProviderIDAnnotationKey],
+			PeerIP:     annotations[constants.JoinApprovalPeerIPAnnotationKey],
+		})
+	}
+	return joiningNodes, nil
+}
+
+// RequestJoinApproval creates a JoiningNode for the requesting node, marked as pending approval.
+// The JoiningNode is named after the node, regardless of the node's role,
+// so administrators can approve or deny the join by annotating it.
+// The provider ID and IP of the requesting instance are recorded as annotations,
+// so administrators can verify them, and an approval can't be used by another instance.
+func (c *Client) RequestJoinApproval(ctx context.Context, req ApprovalRequest) error {
+	compliantNodeName, err := k8sCompliantHostname(req.NodeName)
+	if err != nil {
+		return fmt.Errorf("failed to get k8s compliant hostname: %w", err)
+	}
+
+	// Unapproved JoiningNodes are removed by the node operator once the deadline is reached.
+	deadline := metav1.NewTime(time.Now().Add(constants.JoinApprovalTimeout))
+	joiningNode := &unstructured.Unstructured{}
+	joiningNode.SetUnstructuredContent(map[string]any{
+		"apiVersion": "update.edgeless.systems/v1alpha1",
+		"kind":       "JoiningNode",
+		"metadata": map[string]any{
+			"name": compliantNodeName,
+			"annotations": map[string]any{
+				constants.JoinApprovalAnnotationKey:           ApprovalPending,
+				constants.JoinApprovalProviderIDAnnotationKey: req.ProviderID,
+				constants.JoinApprovalPeerIPAnnotationKey:     req.PeerIP,
+			},
+		},
+		"spec": map[string]any{
+			"name":                compliantNodeName,
+			"componentsreference": req.ComponentsReference,
+			"iscontrolplane":      req.IsControlPlane,
+			"deadline":            deadline,
+		},
+	})
+
+	joiningNodeResource := schema.GroupVersionResource{Group: "update.edgeless.systems", Version: "v1alpha1", Resource: "joiningnodes"}
+	_, err = c.dynClient.Resource(joiningNodeResource).Apply(ctx, joiningNode.GetName(), joiningNode, metav1.ApplyOptions{FieldManager: "join-service"})
+	if err != nil {
+		return fmt.Errorf("failed to create joining node pending approval: %w", err)
+	}
+	return nil
+}
+
+// IsPendingJoin returns true if a PendingNode exists for the instance with the provided provider ID,
+// which is waiting to join the cluster. PendingNodes are created by the node operator for nodes it creates.
+func (c *Client) IsPendingJoin(ctx context.Context, providerID string) (bool, error) {
+	pendingNodeResource := schema.GroupVersionResource{Group: "update.edgeless.systems", Version: "v1alpha1", Resource: "pendingnodes"}
+	list, err := c.dynClient.Resource(pendingNodeResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to list pending nodes: %w", err)
+	}
+
+	for _, item := range list.Items {
+		pendingProviderID, _, err := unstructured.NestedString(item.Object, "spec", "providerID")
+		if err != nil {
+			return false, fmt.Errorf("failed to get provider ID of pending node %s: %w", item.GetName(), err)
+		}
+		goal, _, err := unstructured.NestedString(item.Object, "spec", "goal")
+		if err != nil {
+			return false, fmt.Errorf("failed to get goal of pending node %s: %w", item.GetName(), err)
+		}
+		if pendingProviderID == providerID && goal == "Join" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// joinSlotLabel marks the Leases backing the join slots of the join policy.
+const joinSlotLabel = "constellation.edgeless.systems/join-slot"
+
+// ErrJoinSlotTaken is returned if a join slot was reserved by another request since it was listed.
+var ErrJoinSlotTaken = errors.New("join slot was reserved concurrently")
+
+// JoinSlot is a reservation of one of the concurrent joins permitted by the join policy.
+// Join slots are backed by Leases, so they can be reserved atomically by all join service instances.
+type JoinSlot struct {
+	// Name is the name of the Lease backing the slot.
+	Name string
+	// Holder is the name of the node holding the slot.
+	Holder string
+	// AcquireTime is the time the holder reserved the slot.
+	AcquireTime time.Time
+	// resourceVersion is the version of the Lease the slot was read from. It's empty if the Lease doesn't exist.
+	resourceVersion string
+}
+
+// ListJoinSlots returns all join slots that were reserved at some point.
+func (c *Client) ListJoinSlots(ctx context.Context) ([]JoinSlot, error) {
+	leases, err := c.client.CoordinationV1().Leases(constants.ConstellationNamespace).List(ctx, metav1.ListOptions{LabelSelector: joinSlotLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list join slots: %w", err)
+	}
+
+	slots := make([]JoinSlot, 0, len(leases.Items))
+	for _, lease := range leases.Items {
+		slot := JoinSlot{Name: lease.Name, resourceVersion: lease.ResourceVersion}
+		if lease.Spec.HolderIdentity != nil {
+			slot.Holder = *lease.Spec.HolderIdentity
+		}
+		if lease.Spec.AcquireTime != nil {
+			slot.AcquireTime = lease.Spec.AcquireTime.Time
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// ReserveJoinSlot reserves the join slot for the node.
+// An existing slot is only taken over if it wasn't changed since it was listed.
+// If another request reserved the slot in the meantime, ErrJoinSlotTaken is returned.
+func (c *Client) ReserveJoinSlot(ctx context.Context, slot JoinSlot, nodeName string) error {
+	now := metav1.NewMicroTime(time.Now())
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            slot.Name,
+			Namespace:       constants.ConstellationNamespace,
+			Labels:          map[string]string{joinSlotLabel: ""},
+			ResourceVersion: slot.resourceVersion,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: &nodeName,
+			AcquireTime:    &now,
+		},
+	}
+
+	leases := c.client.CoordinationV1().Leases(constants.ConstellationNamespace)
+	var err error
+	if slot.resourceVersion == "" {
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+	} else {
+		_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	}
+	if k8serrors.IsAlreadyExists(err) || k8serrors.IsConflict(err) {
+		return ErrJoinSlotTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve join slot %s: %w", slot.Name, err)
+	}
+	return nil
+}
+
+// RecordWarning records a warning event for the join service.
+func (c *Client) RecordWarning(ctx context.Context, reason, message string) error {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", joinServiceName, now.UnixNano()),
+			Namespace: constants.ConstellationNamespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       "DaemonSet",
+			Name:       joinServiceName,
+			Namespace:  constants.ConstellationNamespace,
+		},
+		Reason:              reason,
+		Message:             message,
+		Type:                corev1.EventTypeWarning,
+		Source:              corev1.EventSource{Component: joinServiceName},
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		ReportingController: joinServiceName,
+	}
+	_, err := c.client.CoreV1().Events(constants.ConstellationNamespace).Create(ctx, event, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	return nil
+}
+
+// NodeName returns the Kubernetes name of the node with the provided hostname.
+func NodeName(hostname string) (string, error) {
+	return k8sCompliantHostname(hostname)
+}
+
 var validHostnameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
 
 // k8sCompliantHostname transforms a hostname to an RFC 1123 compliant, lowercase subdomain as required by Kubernetes node names.
@@ -182,6 +442,11 @@ func (c *Client) CreateConfigMap(ctx context.Context, name string, data map[stri
 	return nil
 }
 
+// ConfigMaps returns a client for the ConfigMaps in the kube-system namespace.
+func (c *Client) ConfigMaps() corev1client.ConfigMapInterface {
+	return c.client.CoreV1().ConfigMaps(constants.ConstellationNamespace)
+}
+
 // UpdateConfigMap updates the configmap with the provided name by writing the provided key and value.
 func (c *Client) UpdateConfigMap(ctx context.Context, name, key, value string) error {
 	cm, err := c.client.CoreV1().ConfigMaps(constants.ConstellationNamespace).Get(ctx, name, metav1.GetOptions{})
@@ -9,21 +9,28 @@ package server
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/crypto"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/grpclog"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/joinpolicy"
 	"github.com/edgelesssys/constellation/v2/joinservice/joinproto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	kubeadmv1 "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
@@ -38,13 +45,17 @@ type Server struct {
 	dataKeyGetter   dataKeyGetter
 	ca              certificateAuthority
 	kubeClient      kubeClient
+	joinPolicy      joinPolicy
+	auditLog        auditLog
+	attestationInfo attestationInfo
 	joinproto.UnimplementedAPIServer
 }
 
 // New initializes a new Server.
 func New(
 	measurementSalt []byte, ca certificateAuthority,
-	joinTokenGetter joinTokenGetter, dataKeyGetter dataKeyGetter, kubeClient kubeClient, log *logger.Logger,
+	joinTokenGetter joinTokenGetter, dataKeyGetter dataKeyGetter, kubeClient kubeClient, joinPolicy joinPolicy,
+	auditLog auditLog, attestationInfo attestationInfo, log *logger.Logger,
 ) (*Server, error) {
 	return &Server{
 		measurementSalt: measurementSalt,
@@ -53,6 +64,9 @@ func New(
 		dataKeyGetter:   dataKeyGetter,
 		ca:              ca,
 		kubeClient:      kubeClient,
+		joinPolicy:      joinPolicy,
+		auditLog:        auditLog,
+		attestationInfo: attestationInfo,
 	}, nil
 }
 
@@ -75,6 +89,8 @@ func (s *Server) Run(creds credentials.TransportCredentials, port string) error
 }
 
 // IssueJoinTicket handles join requests of Constellation nodes.
+// Requests are checked against the join policy before any secrets are retrieved.
+// Issued tickets are recorded in the join audit log. If recording fails, no ticket is issued.
 // A node will receive:
 // - stateful disk encryption key.
 // - Kubernetes join token.
@@ -82,9 +98,34 @@ func (s *Server) Run(creds credentials.TransportCredentials, port string) error
 // In addition, control plane nodes receive:
 // - a decryption key for CA certificates uploaded to the Kubernetes cluster.
 func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTicketRequest) (*joinproto.IssueJoinTicketResponse, error) {
-	log := s.log.With(zap.String("peerAddress", grpclog.PeerAddrFromContext(ctx)))
+	peerAddr := grpclog.PeerAddrFromContext(ctx)
+	log := s.log.With(zap.String("peerAddress", peerAddr))
 	log.Infof("IssueJoinTicket called")
 
+	nodeName, err := s.ca.GetNodeNameFromCSR(req.CertificateRequest)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting node name from CSR")
+		return nil, status.Errorf(codes.Internal, "getting node name from CSR: %s", err)
+	}
+
+	log.Infof("Querying NodeVersion custom resource for components ConfigMap name")
+	componentsConfigMapName, err := s.getK8sComponentsConfigMapName(ctx)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting components ConfigMap name")
+		return nil, status.Errorf(codes.Internal, "getting components ConfigMap name: %s", err)
+	}
+
+	log.Infof("Checking join policy")
+	if err := s.joinPolicy.Check(ctx, joinpolicy.Request{
+		PeerAddr:            peerAddr,
+		NodeName:            nodeName,
+		ComponentsReference: componentsConfigMapName,
+		IsControlPlane:      req.IsControlPlane,
+	}); err != nil {
+		log.With(zap.Error(err)).Errorf("Join denied by join policy")
+		return nil, err
+	}
+
 	log.Infof("Requesting measurement secret")
 	measurementSecret, err := s.dataKeyGetter.GetDataKey(ctx, attestation.MeasurementSecretContext, crypto.DerivedKeyLengthDefault)
 	if err != nil {
@@ -92,8 +133,15 @@ func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTi
 		return nil, status.Errorf(codes.Internal, "getting measurement secret: %s", err)
 	}
 
+	log.Infof("Querying %s ConfigMap for state disk key version", constants.InternalConfigMap)
+	stateDiskKeyVersion, err := s.getStateDiskKeyVersion(ctx, "")
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting state disk key version")
+		return nil, status.Errorf(codes.Internal, "getting state disk key version: %s", err)
+	}
+
 	log.Infof("Requesting disk encryption key")
-	stateDiskKey, err := s.dataKeyGetter.GetDataKey(ctx, req.DiskUuid, crypto.StateDiskKeyLength)
+	stateDiskKey, err := s.dataKeyGetter.GetDataKey(ctx, crypto.StateDiskKeyID(req.DiskUuid, stateDiskKeyVersion), crypto.StateDiskKeyLength)
 	if err != nil {
 		log.With(zap.Error(err)).Errorf("Failed to get key for stateful disk")
 		return nil, status.Errorf(codes.Internal, "getting key for stateful disk: %s", err)
@@ -106,13 +154,6 @@ func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTi
 		return nil, status.Errorf(codes.Internal, "generating Kubernetes join arguments: %s", err)
 	}
 
-	log.Infof("Querying NodeVersion custom resource for components ConfigMap name")
-	componentsConfigMapName, err := s.getK8sComponentsConfigMapName(ctx)
-	if err != nil {
-		log.With(zap.Error(err)).Errorf("Failed getting components ConfigMap name")
-		return nil, status.Errorf(codes.Internal, "getting components ConfigMap name: %s", err)
-	}
-
 	log.Infof("Querying %s ConfigMap for components", componentsConfigMapName)
 	components, err := s.kubeClient.GetComponents(ctx, componentsConfigMapName)
 	if err != nil {
@@ -120,6 +161,25 @@ func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTi
 		return nil, status.Errorf(codes.Internal, "getting components: %s", err)
 	}
 
+	log.Infof("Querying %s ConfigMap for Kubernetes configuration overrides", constants.InternalConfigMap)
+	auditPolicy, err := s.kubeClient.GetConfigMapData(ctx, constants.InternalConfigMap, constants.AuditPolicyKey)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting audit policy from ConfigMap")
+		return nil, status.Errorf(codes.Internal, "getting audit policy: %s", err)
+	}
+	kubeletConfigPatch, err := s.kubeClient.GetConfigMapData(ctx, constants.InternalConfigMap, constants.KubeletConfigPatchKey)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting kubelet configuration patch from ConfigMap")
+		return nil, status.Errorf(codes.Internal, "getting kubelet configuration patch: %s", err)
+	}
+
+	log.Infof("Querying %s ConfigMap for disk encryption profile", constants.InternalConfigMap)
+	diskEncryptionProfile, err := s.kubeClient.GetConfigMapData(ctx, constants.InternalConfigMap, constants.DiskEncryptionProfileKey)
+	if err != nil {
+		log.With(zap.Error(err)).Errorf("Failed getting disk encryption profile from ConfigMap")
+		return nil, status.Errorf(codes.Internal, "getting disk encryption profile: %s", err)
+	}
+
 	log.Infof("Creating signed kubelet certificate")
 	kubeletCert, err := s.ca.GetCertificate(req.CertificateRequest)
 	if err != nil {
@@ -144,20 +204,28 @@ func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTi
 		}
 	}
 
-	nodeName, err := s.ca.GetNodeNameFromCSR(req.CertificateRequest)
-	if err != nil {
-		log.With(zap.Error(err)).Errorf("Failed getting node name from CSR")
-		return nil, status.Errorf(codes.Internal, "getting node name from CSR: %s", err)
-	}
-
 	if err := s.kubeClient.AddNodeToJoiningNodes(ctx, nodeName, componentsConfigMapName, req.IsControlPlane); err != nil {
 		log.With(zap.Error(err)).Errorf("Failed adding node to joining nodes")
 		return nil, status.Errorf(codes.Internal, "adding node to joining nodes: %s", err)
 	}
 
+	log.Infof("Recording join ticket in audit log")
+	if err := s.recordTicket(ctx, joinaudit.Entry{
+		Ticket:              joinaudit.TicketJoin,
+		PeerAddress:         peerAddr,
+		NodeName:            nodeName,
+		DiskUUID:            req.DiskUuid,
+		StateDiskKeyVersion: stateDiskKeyVersion,
+		IsControlPlane:      req.IsControlPlane,
+	}); err != nil {
+		log.With(zap.Error(err)).Errorf("Failed recording join ticket in audit log")
+		return nil, status.Errorf(codes.Internal, "recording join ticket in audit log: %s", err)
+	}
+
 	log.Infof("IssueJoinTicket successful")
 	return &joinproto.IssueJoinTicketResponse{
 		StateDiskKey:             stateDiskKey,
+		StateDiskKeyVersion:      stateDiskKeyVersion,
 		MeasurementSalt:          s.measurementSalt,
 		MeasurementSecret:        measurementSecret,
 		ApiServerEndpoint:        kubeArgs.APIServerEndpoint,
@@ -166,12 +234,19 @@ func (s *Server) IssueJoinTicket(ctx context.Context, req *joinproto.IssueJoinTi
 		KubeletCert:              kubeletCert,
 		ControlPlaneFiles:        controlPlaneFiles,
 		KubernetesComponents:     components,
+		AuditPolicy:              []byte(auditPolicy),
+		KubeletConfigPatch:       []byte(kubeletConfigPatch),
+		DiskEncryptionProfile:    diskEncryptionProfile,
 	}, nil
 }
 
 // IssueRejoinTicket issues a ticket for nodes to rejoin cluster.
+// If a newer state disk key version was requested for the cluster or the node,
+// the ticket additionally contains the key to rotate the node's state disk passphrase to.
+// Issued tickets are recorded in the join audit log, if the Kubernetes API is available.
 func (s *Server) IssueRejoinTicket(ctx context.Context, req *joinproto.IssueRejoinTicketRequest) (*joinproto.IssueRejoinTicketResponse, error) {
-	log := s.log.With(zap.String("peerAddress", grpclog.PeerAddrFromContext(ctx)))
+	peerAddr := grpclog.PeerAddrFromContext(ctx)
+	log := s.log.With(zap.String("peerAddress", peerAddr))
 	log.Infof("IssueRejoinTicket called")
 
 	log.Infof("Requesting measurement secret")
@@ -182,19 +257,144 @@ func (s *Server) IssueRejoinTicket(ctx context.Context, req *joinproto.IssueRejo
 	}
 
 	log.Infof("Requesting disk encryption key")
-	stateDiskKey, err := s.dataKeyGetter.GetDataKey(ctx, req.DiskUuid, crypto.StateDiskKeyLength)
+	stateDiskKey, err := s.dataKeyGetter.GetDataKey(ctx, crypto.StateDiskKeyID(req.DiskUuid, req.StateDiskKeyVersion), crypto.StateDiskKeyLength)
 	if err != nil {
 		log.With(zap.Error(err)).Errorf("Unable to get key for stateful disk")
 		return nil, status.Errorf(codes.Internal, "unable to get key for stateful disk: %s", err)
 	}
 
+	// Nodes need to be able to rejoin even if the Kubernetes API is unavailable,
+	// e.g. when recovering a cluster, so a failure to look up a key rotation is not fatal.
+	var keyRotation *joinproto.StateDiskKeyRotation
+	issuedKeyVersion := req.StateDiskKeyVersion
+	stateDiskKeyVersion, err := s.getStateDiskKeyVersion(ctx, req.NodeName)
+	if err != nil {
+		log.With(zap.Error(err)).Warnf("Failed getting state disk key version, skipping key rotation")
+	} else if stateDiskKeyVersion > req.StateDiskKeyVersion {
+		log.With(zap.Uint32("from", req.StateDiskKeyVersion), zap.Uint32("to", stateDiskKeyVersion)).Infof("Requesting rotated disk encryption key")
+		rotatedKey, err := s.dataKeyGetter.GetDataKey(ctx, crypto.StateDiskKeyID(req.DiskUuid, stateDiskKeyVersion), crypto.StateDiskKeyLength)
+		if err != nil {
+			log.With(zap.Error(err)).Errorf("Unable to get rotated key for stateful disk")
+			return nil, status.Errorf(codes.Internal, "unable to get rotated key for stateful disk: %s", err)
+		}
+		keyRotation = &joinproto.StateDiskKeyRotation{
+			StateDiskKey:        rotatedKey,
+			StateDiskKeyVersion: stateDiskKeyVersion,
+		}
+		issuedKeyVersion = stateDiskKeyVersion
+	}
+
+	log.Infof("Recording rejoin ticket in audit log")
+	if err := s.recordTicket(ctx, joinaudit.Entry{
+		Ticket:              joinaudit.TicketRejoin,
+		PeerAddress:         peerAddr,
+		NodeName:            req.NodeName,
+		DiskUUID:            req.DiskUuid,
+		StateDiskKeyVersion: issuedKeyVersion,
+	}); err != nil {
+		// like key rotations, the audit log depends on the Kubernetes API, which may be unavailable during recovery
+		log.With(zap.Error(err)).Warnf("Failed recording rejoin ticket in audit log")
+	}
+
 	log.Infof("IssueRejoinTicket successful")
 	return &joinproto.IssueRejoinTicketResponse{
-		StateDiskKey:      stateDiskKey,
-		MeasurementSecret: measurementSecret,
+		StateDiskKey:         stateDiskKey,
+		MeasurementSecret:    measurementSecret,
+		StateDiskKeyRotation: keyRotation,
 	}, nil
 }
 
+// recordTicket adds an entry for an issued ticket to the audit log.
+// The time and the attestation details of the entry are set by recordTicket.
+// The measurements are read from the attestation document the peer presented in the aTLS handshake.
+func (s *Server) recordTicket(ctx context.Context, entry joinaudit.Entry) error {
+	attestationVariant := s.attestationInfo.Variant()
+	attDoc, err := peerAttestationDocument(ctx, attestationVariant)
+	if err != nil {
+		return fmt.Errorf("getting attestation document of peer: %w", err)
+	}
+	measured, err := s.attestationInfo.MeasuredValues(attDoc)
+	if err != nil {
+		return fmt.Errorf("reading measurements from attestation document: %w", err)
+	}
+
+	entry.Time = time.Now().UTC()
+	entry.AttestationVariant = attestationVariant.String()
+	if len(measured) > 0 {
+		entry.Measurements = make(map[uint32]string, len(measured))
+		for idx, value := range measured {
+			entry.Measurements[idx] = hex.EncodeToString(value)
+		}
+	}
+	appended, err := s.auditLog.Append(ctx, entry)
+	if err != nil {
+		return err
+	}
+	s.log.With(zap.Uint64("sequence", appended.Sequence), zap.String("hash", appended.Hash)).Debugf("Recorded ticket in audit log")
+	return nil
+}
+
+// peerAttestationDocument returns the attestation document embedded in the certificate the peer presented in the aTLS handshake.
+// The document was validated during the handshake, before the request was accepted.
+func peerAttestationDocument(ctx context.Context, attestationVariant variant.Getter) ([]byte, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("no peer in request context")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, errors.New("peer is not connected using aTLS")
+	}
+	if len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil, errors.New("peer presented no certificate")
+	}
+	for _, ext := range tlsInfo.State.PeerCertificates[0].Extensions {
+		if ext.Id.Equal(attestationVariant.OID()) {
+			return ext.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("peer certificate contains no %s attestation document", attestationVariant)
+}
+
+// getStateDiskKeyVersion returns the state disk key version a node should use.
+// This is the higher version of the cluster wide version set in the internal-config ConfigMap,
+// and the version requested by the node's annotation. If nodeName is empty, only the cluster wide version is used.
+func (s *Server) getStateDiskKeyVersion(ctx context.Context, nodeName string) (uint32, error) {
+	clusterVersion, err := s.kubeClient.GetConfigMapData(ctx, constants.InternalConfigMap, constants.StateDiskKeyVersionKey)
+	if err != nil {
+		return 0, fmt.Errorf("getting cluster state disk key version: %w", err)
+	}
+	version, err := parseStateDiskKeyVersion(clusterVersion)
+	if err != nil {
+		return 0, fmt.Errorf("parsing cluster state disk key version: %w", err)
+	}
+	if nodeName == "" {
+		return version, nil
+	}
+
+	nodeVersion, err := s.kubeClient.GetNodeAnnotation(ctx, nodeName, constants.NodeStateDiskKeyVersionAnnotationKey)
+	if err != nil {
+		return 0, fmt.Errorf("getting node state disk key version: %w", err)
+	}
+	parsedNodeVersion, err := parseStateDiskKeyVersion(nodeVersion)
+	if err != nil {
+		return 0, fmt.Errorf("parsing node state disk key version: %w", err)
+	}
+	return max(version, parsedNodeVersion), nil
+}
+
+// parseStateDiskKeyVersion parses a state disk key version. An empty string is the initial version 0.
+func parseStateDiskKeyVersion(version string) (uint32, error) {
+	if version == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.ParseUint(version, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(parsed), nil
+}
+
 // getK8sComponentsConfigMapName reads the k8s components config map name from a VolumeMount that is backed by the k8s-version ConfigMap.
 func (s *Server) getK8sComponentsConfigMapName(ctx context.Context) (string, error) {
 	k8sComponentsRef, err := s.kubeClient.GetK8sComponentsRefFromNodeVersionCRD(ctx, "constellation-version")
@@ -227,5 +427,27 @@ type certificateAuthority interface {
 type kubeClient interface {
 	GetK8sComponentsRefFromNodeVersionCRD(ctx context.Context, nodeName string) (string, error)
 	GetComponents(ctx context.Context, configMapName string) (components.Components, error)
+	GetConfigMapData(ctx context.Context, name, key string) (string, error)
+	GetNodeAnnotation(ctx context.Context, nodeName, key string) (string, error)
 	AddNodeToJoiningNodes(ctx context.Context, nodeName string, componentsHash string, isControlPlane bool) error
 }
+
+// joinPolicy decides whether a node may join the cluster.
+type joinPolicy interface {
+	// Check returns a gRPC status error if the join request is denied.
+	Check(ctx context.Context, req joinpolicy.Request) error
+}
+
+// auditLog records issued tickets.
+type auditLog interface {
+	// Append adds an entry to the audit log and returns the chained entry.
+	Append(ctx context.Context, entry joinaudit.Entry) (joinaudit.Entry, error)
+}
+
+// attestationInfo provides the attestation details nodes are verified with.
+type attestationInfo interface {
+	// Variant returns the attestation variant of the cluster.
+	Variant() variant.Variant
+	// MeasuredValues returns the values of the measurement registers in a validated attestation document.
+	MeasuredValues(attDoc []byte) (map[uint32][]byte, error)
+}
@@ -7,18 +7,31 @@ SPDX-License-Identifier: AGPL-3.0-only
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/internal/attestation"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
+	"github.com/edgelesssys/constellation/v2/internal/joinaudit"
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
+	"github.com/edgelesssys/constellation/v2/joinservice/internal/joinpolicy"
 	"github.com/edgelesssys/constellation/v2/joinservice/joinproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	kubeadmv1 "k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta3"
 )
 
@@ -54,7 +67,12 @@ func TestIssueJoinTicket(t *testing.T) {
 		kms                            stubKeyGetter
 		ca                             stubCA
 		kubeClient                     stubKubeClient
+		joinPolicy                     stubJoinPolicy
+		auditLog                       stubAuditLog
+		attestationInfo                stubAttestationInfo
+		noAttestationDocument          bool
 		missingComponentsReferenceFile bool
+		wantKeyVersion                 uint32
 		wantErr                        bool
 	}{
 		"worker node": {
@@ -66,6 +84,58 @@ func TestIssueJoinTicket(t *testing.T) {
 			ca:         stubCA{cert: testCert, nodeName: "node"},
 			kubeClient: stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
 		},
+		"with state disk key version": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid + "-v2":                         testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca: stubCA{cert: testCert, nodeName: "node"},
+			kubeClient: stubKubeClient{
+				getComponentsVal:                         clusterComponents,
+				getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref",
+				configMapData:                            map[string]string{constants.StateDiskKeyVersionKey: "2"},
+			},
+			wantKeyVersion: 2,
+		},
+		"with disk encryption profile": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca: stubCA{cert: testCert, nodeName: "node"},
+			kubeClient: stubKubeClient{
+				getComponentsVal:                         clusterComponents,
+				getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref",
+				configMapData:                            map[string]string{constants.DiskEncryptionProfileKey: "aes-xts"},
+			},
+		},
+		"invalid state disk key version": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca: stubCA{cert: testCert, nodeName: "node"},
+			kubeClient: stubKubeClient{
+				getComponentsVal:                         clusterComponents,
+				getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref",
+				configMapData:                            map[string]string{constants.StateDiskKeyVersionKey: "two"},
+			},
+			wantErr: true,
+		},
+		"join policy denies": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca:         stubCA{cert: testCert, nodeName: "node"},
+			kubeClient: stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
+			joinPolicy: stubJoinPolicy{checkErr: someErr},
+			wantErr:    true,
+		},
 		"kubeclient fails": {
 			kubeadm: stubTokenGetter{token: testJoinToken},
 			kms: stubKeyGetter{dataKeys: map[string][]byte{
@@ -136,6 +206,39 @@ func TestIssueJoinTicket(t *testing.T) {
 			ca:         stubCA{cert: testCert, nodeName: "node"},
 			kubeClient: stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
 		},
+		"recording in audit log fails": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca:         stubCA{cert: testCert, nodeName: "node"},
+			kubeClient: stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
+			auditLog:   stubAuditLog{appendErr: someErr},
+			wantErr:    true,
+		},
+		"peer presented no attestation document": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca:                    stubCA{cert: testCert, nodeName: "node"},
+			kubeClient:            stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
+			noAttestationDocument: true,
+			wantErr:               true,
+		},
+		"reading measurements fails": {
+			kubeadm: stubTokenGetter{token: testJoinToken},
+			kms: stubKeyGetter{dataKeys: map[string][]byte{
+				uuid:                                 testKey,
+				attestation.MeasurementSecretContext: measurementSecret,
+			}},
+			ca:              stubCA{cert: testCert, nodeName: "node"},
+			kubeClient:      stubKubeClient{getComponentsVal: clusterComponents, getK8sComponentsRefFromNodeVersionCRDVal: "k8s-components-ref"},
+			attestationInfo: stubAttestationInfo{measuredErr: someErr},
+			wantErr:         true,
+		},
 		"GetControlPlaneCertificateKey fails": {
 			isControlPlane: true,
 			kubeadm:        stubTokenGetter{token: testJoinToken, certificateKeyErr: someErr},
@@ -162,21 +265,35 @@ func TestIssueJoinTicket(t *testing.T) {
 				joinTokenGetter: tc.kubeadm,
 				dataKeyGetter:   tc.kms,
 				kubeClient:      &tc.kubeClient,
+				joinPolicy:      &tc.joinPolicy,
+				auditLog:        &tc.auditLog,
+				attestationInfo: tc.attestationInfo,
 				log:             logger.NewTest(t),
 			}
 
+			attDoc := testAttestationDocument
+			if tc.noAttestationDocument {
+				attDoc = nil
+			}
 			req := &joinproto.IssueJoinTicketRequest{
 				DiskUuid:       "uuid",
 				IsControlPlane: tc.isControlPlane,
 			}
-			resp, err := api.IssueJoinTicket(context.Background(), req)
+			resp, err := api.IssueJoinTicket(attestedPeerContext(attDoc), req)
 			if tc.wantErr {
 				assert.Error(err)
 				return
 			}
 
 			require.NoError(err)
-			assert.Equal(tc.kms.dataKeys[uuid], resp.StateDiskKey)
+			assert.Equal(joinpolicy.Request{
+				NodeName:            tc.ca.nodeName,
+				ComponentsReference: tc.kubeClient.getK8sComponentsRefFromNodeVersionCRDVal,
+				IsControlPlane:      tc.isControlPlane,
+				PeerAddr:            testPeerAddr,
+			}, tc.joinPolicy.req)
+			assert.Equal(tc.kms.dataKeys[crypto.StateDiskKeyID(uuid, tc.wantKeyVersion)], resp.StateDiskKey)
+			assert.Equal(tc.wantKeyVersion, resp.StateDiskKeyVersion)
 			assert.Equal(salt, resp.MeasurementSalt)
 			assert.Equal(tc.kms.dataKeys[attestation.MeasurementSecretContext], resp.MeasurementSecret)
 			assert.Equal(tc.kubeadm.token.APIServerEndpoint, resp.ApiServerEndpoint)
@@ -184,9 +301,27 @@ func TestIssueJoinTicket(t *testing.T) {
 			assert.Equal(tc.kubeadm.token.Token, resp.Token)
 			assert.Equal(tc.ca.cert, resp.KubeletCert)
 			assert.Equal(tc.kubeClient.getComponentsVal, resp.KubernetesComponents)
+			assert.Equal(tc.kubeClient.configMapData[constants.AuditPolicyKey], string(resp.AuditPolicy))
+			assert.Equal(tc.kubeClient.configMapData[constants.KubeletConfigPatchKey], string(resp.KubeletConfigPatch))
+			assert.Equal(tc.kubeClient.configMapData[constants.DiskEncryptionProfileKey], resp.DiskEncryptionProfile)
 			assert.Equal(tc.ca.nodeName, tc.kubeClient.joiningNodeName)
 			assert.Equal(tc.kubeClient.getK8sComponentsRefFromNodeVersionCRDVal, tc.kubeClient.componentsRef)
 
+			require.Len(tc.auditLog.entries, 1)
+			entry := tc.auditLog.entries[0]
+			assert.Equal(joinaudit.TicketJoin, entry.Ticket)
+			assert.Equal(testPeerAddr, entry.PeerAddress)
+			assert.Equal(variant.Dummy{}.String(), entry.AttestationVariant)
+			assert.Equal(map[uint32]string{
+				4:  strings.Repeat("11", 32),
+				11: strings.Repeat("00", 32),
+			}, entry.Measurements)
+			assert.Equal(tc.ca.nodeName, entry.NodeName)
+			assert.Equal(uuid, entry.DiskUUID)
+			assert.Equal(tc.wantKeyVersion, entry.StateDiskKeyVersion)
+			assert.Equal(tc.isControlPlane, entry.IsControlPlane)
+			assert.False(entry.Time.IsZero())
+
 			if tc.isControlPlane {
 				assert.Len(resp.ControlPlaneFiles, len(tc.kubeadm.files))
 			}
@@ -198,8 +333,12 @@ func TestIssueRejoinTicker(t *testing.T) {
 	uuid := "uuid"
 
 	testCases := map[string]struct {
-		keyGetter stubKeyGetter
-		wantErr   bool
+		keyGetter       stubKeyGetter
+		kubeClient      stubKubeClient
+		auditLog        stubAuditLog
+		keyVersion      uint32
+		wantKeyRotation *joinproto.StateDiskKeyRotation
+		wantErr         bool
 	}{
 		"success": {
 			keyGetter: stubKeyGetter{
@@ -209,6 +348,66 @@ func TestIssueRejoinTicker(t *testing.T) {
 				},
 			},
 		},
+		"key rotation requested for cluster": {
+			keyGetter: stubKeyGetter{
+				dataKeys: map[string][]byte{
+					uuid + "-v1":                         {0x1, 0x2, 0x3},
+					uuid + "-v2":                         {0x7, 0x8, 0x9},
+					attestation.MeasurementSecretContext: {0x4, 0x5, 0x6},
+				},
+			},
+			kubeClient: stubKubeClient{configMapData: map[string]string{constants.StateDiskKeyVersionKey: "2"}},
+			keyVersion: 1,
+			wantKeyRotation: &joinproto.StateDiskKeyRotation{
+				StateDiskKey:        []byte{0x7, 0x8, 0x9},
+				StateDiskKeyVersion: 2,
+			},
+		},
+		"key rotation requested for node": {
+			keyGetter: stubKeyGetter{
+				dataKeys: map[string][]byte{
+					uuid:                                 {0x1, 0x2, 0x3},
+					uuid + "-v3":                         {0x7, 0x8, 0x9},
+					attestation.MeasurementSecretContext: {0x4, 0x5, 0x6},
+				},
+			},
+			kubeClient: stubKubeClient{
+				configMapData:   map[string]string{constants.StateDiskKeyVersionKey: "1"},
+				nodeAnnotations: map[string]string{constants.NodeStateDiskKeyVersionAnnotationKey: "3"},
+			},
+			wantKeyRotation: &joinproto.StateDiskKeyRotation{
+				StateDiskKey:        []byte{0x7, 0x8, 0x9},
+				StateDiskKeyVersion: 3,
+			},
+		},
+		"disk already uses newer key version": {
+			keyGetter: stubKeyGetter{
+				dataKeys: map[string][]byte{
+					uuid + "-v2":                         {0x1, 0x2, 0x3},
+					attestation.MeasurementSecretContext: {0x4, 0x5, 0x6},
+				},
+			},
+			kubeClient: stubKubeClient{configMapData: map[string]string{constants.StateDiskKeyVersionKey: "1"}},
+			keyVersion: 2,
+		},
+		"key version lookup fails": {
+			keyGetter: stubKeyGetter{
+				dataKeys: map[string][]byte{
+					uuid:                                 {0x1, 0x2, 0x3},
+					attestation.MeasurementSecretContext: {0x4, 0x5, 0x6},
+				},
+			},
+			kubeClient: stubKubeClient{getConfigMapDataErr: errors.New("error")},
+		},
+		"recording in audit log fails": {
+			keyGetter: stubKeyGetter{
+				dataKeys: map[string][]byte{
+					uuid:                                 {0x1, 0x2, 0x3},
+					attestation.MeasurementSecretContext: {0x4, 0x5, 0x6},
+				},
+			},
+			auditLog: stubAuditLog{appendErr: errors.New("error")},
+		},
 		"failure": {
 			keyGetter: stubKeyGetter{
 				dataKeys:      make(map[string][]byte),
@@ -227,13 +426,18 @@ func TestIssueRejoinTicker(t *testing.T) {
 				ca:              stubCA{},
 				joinTokenGetter: stubTokenGetter{},
 				dataKeyGetter:   tc.keyGetter,
+				kubeClient:      &tc.kubeClient,
+				auditLog:        &tc.auditLog,
+				attestationInfo: stubAttestationInfo{},
 				log:             logger.NewTest(t),
 			}
 
 			req := &joinproto.IssueRejoinTicketRequest{
-				DiskUuid: uuid,
+				DiskUuid:            uuid,
+				StateDiskKeyVersion: tc.keyVersion,
+				NodeName:            "node",
 			}
-			resp, err := api.IssueRejoinTicket(context.Background(), req)
+			resp, err := api.IssueRejoinTicket(attestedPeerContext(testAttestationDocument), req)
 			if tc.wantErr {
 				assert.Error(err)
 				return
@@ -241,7 +445,28 @@ func TestIssueRejoinTicker(t *testing.T) {
 
 			require.NoError(err)
 			assert.Equal(tc.keyGetter.dataKeys[attestation.MeasurementSecretContext], resp.MeasurementSecret)
-			assert.Equal(tc.keyGetter.dataKeys[uuid], resp.StateDiskKey)
+			assert.Equal(tc.keyGetter.dataKeys[crypto.StateDiskKeyID(uuid, tc.keyVersion)], resp.StateDiskKey)
+
+			require.Len(tc.auditLog.entries, 1)
+			entry := tc.auditLog.entries[0]
+			assert.Equal(joinaudit.TicketRejoin, entry.Ticket)
+			assert.Equal(testPeerAddr, entry.PeerAddress)
+			assert.Len(entry.Measurements, 2)
+			assert.Equal("node", entry.NodeName)
+			assert.Equal(uuid, entry.DiskUUID)
+			wantKeyVersion := tc.keyVersion
+			if tc.wantKeyRotation != nil {
+				wantKeyVersion = tc.wantKeyRotation.StateDiskKeyVersion
+			}
+			assert.Equal(wantKeyVersion, entry.StateDiskKeyVersion)
+
+			if tc.wantKeyRotation == nil {
+				assert.Nil(resp.StateDiskKeyRotation)
+				return
+			}
+			require.NotNil(resp.StateDiskKeyRotation)
+			assert.Equal(tc.wantKeyRotation.StateDiskKey, resp.StateDiskKeyRotation.StateDiskKey)
+			assert.Equal(tc.wantKeyRotation.StateDiskKeyVersion, resp.StateDiskKeyRotation.StateDiskKeyVersion)
 		})
 	}
 }
@@ -289,6 +514,12 @@ type stubKubeClient struct {
 	getComponentsVal []*components.Component
 	getComponentsErr error
 
+	configMapData       map[string]string
+	getConfigMapDataErr error
+
+	nodeAnnotations      map[string]string
+	getNodeAnnotationErr error
+
 	getK8sComponentsRefFromNodeVersionCRDErr error
 	getK8sComponentsRefFromNodeVersionCRDVal string
 
@@ -305,8 +536,75 @@ func (s *stubKubeClient) GetComponents(_ context.Context, _ string) (components.
 	return s.getComponentsVal, s.getComponentsErr
 }
 
+func (s *stubKubeClient) GetConfigMapData(_ context.Context, _, key string) (string, error) {
+	return s.configMapData[key], s.getConfigMapDataErr
+}
+
+func (s *stubKubeClient) GetNodeAnnotation(_ context.Context, _, key string) (string, error) {
+	return s.nodeAnnotations[key], s.getNodeAnnotationErr
+}
+
 func (s *stubKubeClient) AddNodeToJoiningNodes(_ context.Context, nodeName string, componentsRef string, _ bool) error {
 	s.joiningNodeName = nodeName
 	s.componentsRef = componentsRef
 	return s.addNodeToJoiningNodesErr
 }
+
+type stubJoinPolicy struct {
+	req      joinpolicy.Request
+	checkErr error
+}
+
+func (s *stubJoinPolicy) Check(_ context.Context, req joinpolicy.Request) error {
+	s.req = req
+	return s.checkErr
+}
+
+type stubAuditLog struct {
+	entries   []joinaudit.Entry
+	appendErr error
+}
+
+func (s *stubAuditLog) Append(_ context.Context, entry joinaudit.Entry) (joinaudit.Entry, error) {
+	s.entries = append(s.entries, entry)
+	return entry, s.appendErr
+}
+
+const testPeerAddr = "192.0.2.1:1234"
+
+var testAttestationDocument = []byte("attestation document")
+
+// attestedPeerContext returns a context of a request by a peer that presented the attestation document in the aTLS handshake.
+func attestedPeerContext(attDoc []byte) context.Context {
+	cert := &x509.Certificate{}
+	if attDoc != nil {
+		cert.Extensions = []pkix.Extension{{Id: variant.Dummy{}.OID(), Value: attDoc}}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1234},
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+		},
+	})
+}
+
+type stubAttestationInfo struct {
+	measuredErr error
+}
+
+func (stubAttestationInfo) Variant() variant.Variant {
+	return variant.Dummy{}
+}
+
+func (s stubAttestationInfo) MeasuredValues(attDoc []byte) (map[uint32][]byte, error) {
+	if s.measuredErr != nil {
+		return nil, s.measuredErr
+	}
+	if !bytes.Equal(attDoc, testAttestationDocument) {
+		return nil, errors.New("unexpected attestation document")
+	}
+	return map[uint32][]byte{
+		4:  bytes.Repeat([]byte{0x11}, 32),
+		11: bytes.Repeat([]byte{0x00}, 32),
+	}, nil
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
@@ -46,8 +47,13 @@ func NewValidator(log *logger.Logger, variant variant.Variant, fileHandler file.
 	return u, err
 }
 
+type measuredValuesGetter interface {
+	MeasuredValues(attDoc []byte) (map[uint32][]byte, error)
+}
+
 type cachedCerts interface {
 	SevSnpCerts() (ask *x509.Certificate, ark *x509.Certificate)
+	TDXCollateral() tdx.CollateralGetter
 }
 
 // Validate calls the validators Validate method, and prevents any updates during the call.
@@ -64,6 +70,26 @@ func (u *Updatable) OID() asn1.ObjectIdentifier {
 	return u.Validator.OID()
 }
 
+// Variant returns the attestation variant of the validator.
+func (u *Updatable) Variant() variant.Variant {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+	return u.variant
+}
+
+// MeasuredValues returns the values of the measurement registers in an attestation document
+// that was validated by the validator, indexed like the expected measurements.
+// If the validator doesn't provide measurements, nil is returned.
+func (u *Updatable) MeasuredValues(attDoc []byte) (map[uint32][]byte, error) {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+	validator, ok := u.Validator.(measuredValuesGetter)
+	if !ok {
+		return nil, nil
+	}
+	return validator.MeasuredValues(attDoc)
+}
+
 // Update switches out the underlying validator.
 func (u *Updatable) Update() error {
 	u.mux.Lock()
@@ -90,6 +116,7 @@ func (u *Updatable) Update() error {
 	if err != nil {
 		return fmt.Errorf("choosing validator: %w", err)
 	}
+	u.useCachedCollateral(validator)
 	u.Validator = validator
 
 	return nil
@@ -117,6 +144,17 @@ func (u *Updatable) configWithCerts(cfg config.AttestationCfg) (config.Attestati
 	return cfg, nil
 }
 
+// useCachedCollateral configures the validator to retrieve its attestation collateral from the cache, if applicable.
+func (u *Updatable) useCachedCollateral(validator atls.Validator) {
+	tdxValidator, ok := validator.(*tdx.Validator)
+	if !ok || u.cachedCerts == nil {
+		return
+	}
+	if collateral := u.cachedCerts.TDXCollateral(); collateral != nil {
+		tdxValidator.SetCollateralGetter(collateral)
+	}
+}
+
 // getCachedAskCert returns the cached SEV-SNP ASK certificate.
 func (u *Updatable) getCachedAskCert() (x509.Certificate, error) {
 	if u.cachedCerts == nil {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
@@ -40,15 +41,16 @@ func TestMain(m *testing.M) {
 
 func TestNewUpdateableValidator(t *testing.T) {
 	testCases := map[string]struct {
-		variant  variant.Variant
-		config   config.AttestationCfg
-		snpCerts *stubSnpCerts
-		wantErr  bool
+		variant              variant.Variant
+		config               config.AttestationCfg
+		cachedCerts          *stubCachedCerts
+		wantCachedCollateral bool
+		wantErr              bool
 	}{
 		"azure": {
 			variant: variant.AzureSEVSNP{},
 			config:  config.DefaultForAzureSEVSNP(),
-			snpCerts: &stubSnpCerts{
+			cachedCerts: &stubCachedCerts{
 				ask: &x509.Certificate{},
 				ark: &x509.Certificate{},
 			},
@@ -61,6 +63,12 @@ func TestNewUpdateableValidator(t *testing.T) {
 			variant: variant.QEMUVTPM{},
 			config:  &config.QEMUVTPM{Measurements: measurements.M{11: measurements.WithAllBytes(0x00, measurements.Enforce, measurements.PCRMeasurementLength)}},
 		},
+		"qemu tdx": {
+			variant:              variant.QEMUTDX{},
+			config:               &config.QEMUTDX{Measurements: measurements.M{0: measurements.WithAllBytes(0x00, measurements.Enforce, measurements.TDXMeasurementLength)}},
+			cachedCerts:          &stubCachedCerts{tdxCollateral: &stubCollateralGetter{}},
+			wantCachedCollateral: true,
+		},
 		"no file": {
 			variant: variant.AzureSEVSNP{},
 			wantErr: true,
@@ -89,26 +97,42 @@ func TestNewUpdateableValidator(t *testing.T) {
 				logger.NewTest(t),
 				tc.variant,
 				handler,
-				tc.snpCerts,
+				tc.cachedCerts,
 			)
 			if tc.wantErr {
 				assert.Error(err)
 			} else {
 				assert.NoError(err)
 			}
+			if tc.wantCachedCollateral {
+				assert.True(tc.cachedCerts.tdxCollateralUsed)
+			}
 		})
 	}
 }
 
-type stubSnpCerts struct {
-	ask *x509.Certificate
-	ark *x509.Certificate
+type stubCachedCerts struct {
+	ask               *x509.Certificate
+	ark               *x509.Certificate
+	tdxCollateral     *stubCollateralGetter
+	tdxCollateralUsed bool
 }
 
-func (s *stubSnpCerts) SevSnpCerts() (ask *x509.Certificate, ark *x509.Certificate) {
+func (s *stubCachedCerts) SevSnpCerts() (ask *x509.Certificate, ark *x509.Certificate) {
 	return s.ask, s.ark
 }
 
+func (s *stubCachedCerts) TDXCollateral() tdx.CollateralGetter {
+	s.tdxCollateralUsed = true
+	return s.tdxCollateral
+}
+
+type stubCollateralGetter struct{}
+
+func (s *stubCollateralGetter) GetContext(context.Context, string) (map[string][]string, []byte, error) {
+	return nil, nil, nil
+}
+
 func TestUpdate(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -133,6 +157,11 @@ func TestUpdate(t *testing.T) {
 
 	// call update once to initialize the server's validator
 	require.NoError(validator.Update())
+	assert.Equal(variant.Dummy{}, validator.Variant())
+	// the fake validator of the dummy variant doesn't provide measurements
+	measured, err := validator.MeasuredValues([]byte("attestation document"))
+	assert.NoError(err)
+	assert.Nil(measured)
 
 	// create tls config and start the server
 	serverConfig, err := atls.CreateAttestationServerTLSConfig(nil, []atls.Validator{validator})
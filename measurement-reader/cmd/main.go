@@ -30,7 +30,7 @@ func main() {
 
 	var m []sorted.Measurement
 	switch attestationVariant {
-	case variant.AWSNitroTPM{}, variant.AWSSEVSNP{}, variant.AzureSEVSNP{}, variant.AzureTrustedLaunch{}, variant.GCPSEVES{}, variant.QEMUVTPM{}:
+	case variant.AWSNitroTPM{}, variant.AWSSEVSNP{}, variant.AzureSEVSNP{}, variant.AzureTrustedLaunch{}, variant.GCPSEVES{}, variant.QEMUVTPM{}, variant.QEMUSEVSNPSimulated{}:
 		m, err = tpm.Measurements()
 		if err != nil {
 			log.With(zap.Error(err)).Fatalf("Failed to read TPM measurements")
@@ -40,6 +40,11 @@ func main() {
 		if err != nil {
 			log.With(zap.Error(err)).Fatalf("Failed to read Intel TDX measurements")
 		}
+	case variant.QEMUTDXSimulated{}:
+		m, err = tdx.SimulatedMeasurements()
+		if err != nil {
+			log.With(zap.Error(err)).Fatalf("Failed to read simulated Intel TDX measurements")
+		}
 	default:
 		log.With(zap.String("attestationVariant", variantString)).Fatalf("Unsupported attestation variant")
 	}
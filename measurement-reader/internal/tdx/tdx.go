@@ -9,6 +9,7 @@ package tdx
 
 import (
 	"github.com/edgelesssys/constellation/v2/internal/attestation/tdx"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
 	"github.com/edgelesssys/constellation/v2/measurement-reader/internal/sorted"
 )
 
@@ -21,3 +22,13 @@ func Measurements() ([]sorted.Measurement, error) {
 
 	return sorted.SortMeasurements(m, sorted.TDX), nil
 }
+
+// SimulatedMeasurements returns a sorted list of the runtime measurements of the simulated TDX device.
+func SimulatedMeasurements() ([]sorted.Measurement, error) {
+	m, err := tdx.GetSelectedSimulatedMeasurements(vtpm.OpenVTPM, []int{0, 1, 2, 3, 4})
+	if err != nil {
+		return nil, err
+	}
+
+	return sorted.SortMeasurements(m, sorted.TDX), nil
+}
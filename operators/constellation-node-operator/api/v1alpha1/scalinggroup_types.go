@@ -9,6 +9,7 @@ package v1alpha1
 import (
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,6 +43,12 @@ type ScalingGroupSpec struct {
 	Max int32 `json:"max,omitempty"`
 	// Role is the role of the nodes in the scaling group.
 	Role NodeRole `json:"role,omitempty"`
+	// NodeLabels are the labels the nodes of the scaling group are registered with.
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+	// NodeTaints are the taints the nodes of the scaling group are registered with.
+	NodeTaints []corev1.Taint `json:"nodeTaints,omitempty"`
+	// Spot specifies whether the scaling group uses spot instances, which may be evicted by the cloud provider at any time.
+	Spot bool `json:"spot,omitempty"`
 }
 
 // NodeRole is the role of a node.
@@ -6,7 +6,15 @@ SPDX-License-Identifier: AGPL-3.0-only
 
 package api
 
-import updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud"
+	"github.com/edgelesssys/constellation/v2/internal/kubernetes"
+	updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
 
 // ScalingGroup is a cloud provider scaling group.
 type ScalingGroup struct {
@@ -21,4 +29,52 @@ type ScalingGroup struct {
 	AutoscalingGroupName string
 	// Role is the role of the nodes in the scaling group.
 	Role updatev1alpha1.NodeRole
+	// NodeLabels are the labels the nodes of the scaling group are registered with.
+	NodeLabels map[string]string
+	// NodeTaints are the taints the nodes of the scaling group are registered with.
+	NodeTaints []corev1.Taint
+	// Spot is true if the scaling group uses spot instances.
+	Spot bool
+	// Min is the minimum size of the scaling group, as configured by the user. 0 if not configured.
+	Min int32
+	// Max is the maximum size of the scaling group, as configured by the user. 0 if not configured.
+	Max int32
+}
+
+// ParseNodeGroupTags sets the node registration settings and autoscaling bounds
+// of the scaling group from the tags (or labels) of the scaling group.
+func (g *ScalingGroup) ParseNodeGroupTags(tags map[string]string) error {
+	labels, err := kubernetes.UnmarshalNodeLabels(tags[cloud.TagNodeLabels])
+	if err != nil {
+		return fmt.Errorf("parsing node labels of scaling group %s: %w", g.Name, err)
+	}
+	if len(labels) > 0 {
+		g.NodeLabels = labels
+	}
+	g.NodeTaints, err = kubernetes.UnmarshalNodeTaints(tags[cloud.TagNodeTaints])
+	if err != nil {
+		return fmt.Errorf("parsing node taints of scaling group %s: %w", g.Name, err)
+	}
+	g.Spot = tags[cloud.TagSpot] == "true"
+	if g.Min, err = parseSize(tags[cloud.TagAutoscalingMin]); err != nil {
+		return fmt.Errorf("parsing autoscaling minimum of scaling group %s: %w", g.Name, err)
+	}
+	if g.Max, err = parseSize(tags[cloud.TagAutoscalingMax]); err != nil {
+		return fmt.Errorf("parsing autoscaling maximum of scaling group %s: %w", g.Name, err)
+	}
+	return nil
+}
+
+func parseSize(s string) (int32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("negative size %d", size)
+	}
+	return int32(size), nil
 }
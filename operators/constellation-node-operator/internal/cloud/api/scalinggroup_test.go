@@ -0,0 +1,69 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseNodeGroupTags(t *testing.T) {
+	testCases := map[string]struct {
+		tags      map[string]string
+		wantGroup ScalingGroup
+		wantErr   bool
+	}{
+		"no tags": {
+			tags: map[string]string{},
+		},
+		"all tags": {
+			tags: map[string]string{
+				"constellation-node-labels":     "pool=spot",
+				"constellation-node-taints":     "spot=true:NoSchedule",
+				"constellation-spot":            "true",
+				"constellation-autoscaling-min": "1",
+				"constellation-autoscaling-max": "5",
+			},
+			wantGroup: ScalingGroup{
+				NodeLabels: map[string]string{"pool": "spot"},
+				NodeTaints: []corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+				Spot:       true,
+				Min:        1,
+				Max:        5,
+			},
+		},
+		"invalid labels": {
+			tags:    map[string]string{"constellation-node-labels": "pool"},
+			wantErr: true,
+		},
+		"invalid taints": {
+			tags:    map[string]string{"constellation-node-taints": "spot=true"},
+			wantErr: true,
+		},
+		"invalid size": {
+			tags:    map[string]string{"constellation-autoscaling-max": "-1"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var group ScalingGroup
+			err := group.ParseNodeGroupTags(tc.tags)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantGroup, group)
+		})
+	}
+}
@@ -168,6 +168,7 @@ func (c *Client) ListScalingGroups(ctx context.Context, uid string) ([]cspapi.Sc
 
 		var role updatev1alpha1.NodeRole
 		var nodeGroupName string
+		tags := map[string]string{}
 		for _, tag := range group.Tags {
 			if tag.Key == nil || tag.Value == nil {
 				continue
@@ -179,6 +180,7 @@ func (c *Client) ListScalingGroups(ctx context.Context, uid string) ([]cspapi.Sc
 			case "constellation-node-group":
 				nodeGroupName = *tag.Value
 			}
+			tags[key] = *tag.Value
 		}
 
 		// fallback for legacy clusters
@@ -207,13 +209,17 @@ func (c *Client) ListScalingGroups(ctx context.Context, uid string) ([]cspapi.Sc
 			return nil, fmt.Errorf("getting autoscaler group name: %w", err)
 		}
 
-		results = append(results, cspapi.ScalingGroup{
+		scalingGroup := cspapi.ScalingGroup{
 			Name:                 name,
 			NodeGroupName:        nodeGroupName,
 			GroupID:              *group.AutoScalingGroupName,
 			AutoscalingGroupName: autoscalerGroupName,
 			Role:                 role,
-		})
+		}
+		if err := scalingGroup.ParseNodeGroupTags(tags); err != nil {
+			return nil, err
+		}
+		results = append(results, scalingGroup)
 	}
 	return results, nil
 }
@@ -18,6 +18,7 @@ import (
 	cspapi "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/internal/cloud/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestGetScalingGroupImage(t *testing.T) {
@@ -268,6 +269,26 @@ func TestListScalingGroups(t *testing.T) {
 									Key:   toPtr("constellation-node-group"),
 									Value: toPtr("foo-group"),
 								},
+								{
+									Key:   toPtr("constellation-node-labels"),
+									Value: toPtr("pool=spot"),
+								},
+								{
+									Key:   toPtr("constellation-node-taints"),
+									Value: toPtr("spot=true:NoSchedule"),
+								},
+								{
+									Key:   toPtr("constellation-spot"),
+									Value: toPtr("true"),
+								},
+								{
+									Key:   toPtr("constellation-autoscaling-min"),
+									Value: toPtr("1"),
+								},
+								{
+									Key:   toPtr("constellation-autoscaling-max"),
+									Value: toPtr("5"),
+								},
 							},
 						},
 						{
@@ -298,6 +319,11 @@ func TestListScalingGroups(t *testing.T) {
 					GroupID:              "worker-asg-2",
 					AutoscalingGroupName: "worker-asg-2",
 					Role:                 "Worker",
+					NodeLabels:           map[string]string{"pool": "spot"},
+					NodeTaints:           []corev1.Taint{{Key: "spot", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+					Spot:                 true,
+					Min:                  1,
+					Max:                  5,
 				},
 			},
 		},
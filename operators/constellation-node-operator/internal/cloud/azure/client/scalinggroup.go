@@ -136,13 +136,23 @@ func (c *Client) ListScalingGroups(ctx context.Context, uid string) ([]cspapi.Sc
 				return nil, fmt.Errorf("getting autoscaling group name: %w", err)
 			}
 
-			results = append(results, cspapi.ScalingGroup{
+			scalingGroup := cspapi.ScalingGroup{
 				Name:                 name,
 				NodeGroupName:        nodeGroupName,
 				GroupID:              *scaleSet.ID,
 				AutoscalingGroupName: autoscalerGroupName,
 				Role:                 role,
-			})
+			}
+			tags := map[string]string{}
+			for key, value := range scaleSet.Tags {
+				if value != nil {
+					tags[key] = *value
+				}
+			}
+			if err := scalingGroup.ParseNodeGroupTags(tags); err != nil {
+				return nil, err
+			}
+			results = append(results, scalingGroup)
 		}
 	}
 	return results, nil
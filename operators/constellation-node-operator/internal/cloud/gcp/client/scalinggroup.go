@@ -176,13 +176,30 @@ func (c *Client) ListScalingGroups(ctx context.Context, uid string) ([]cspapi.Sc
 				return nil, fmt.Errorf("getting autoscaling group name: %w", err)
 			}
 
-			results = append(results, cspapi.ScalingGroup{
+			scalingGroup := cspapi.ScalingGroup{
 				Name:                 name,
 				NodeGroupName:        nodeGroupName,
 				GroupID:              groupID,
 				AutoscalingGroupName: autoscalerGroupName,
 				Role:                 role,
-			})
+			}
+			// Node labels and taints are stored in the template's metadata,
+			// since their encoding isn't allowed in GCP label values.
+			tags := map[string]string{}
+			for key, value := range template.Properties.Labels {
+				tags[key] = value
+			}
+			if template.Properties.Metadata != nil {
+				for _, item := range template.Properties.Metadata.Items {
+					if item != nil && item.Key != nil && item.Value != nil {
+						tags[*item.Key] = *item.Value
+					}
+				}
+			}
+			if err := scalingGroup.ParseNodeGroupTags(tags); err != nil {
+				return nil, err
+			}
+			results = append(results, scalingGroup)
 		}
 	}
 	return results, nil
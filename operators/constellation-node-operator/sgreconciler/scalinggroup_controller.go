@@ -14,6 +14,8 @@ import (
 	updatev1alpha1 "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/api/v1alpha1"
 	cspapi "github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/internal/cloud/api"
 	"github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/internal/executor"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -65,7 +67,7 @@ func (r *ExternalScalingGroupReconciler) Reconcile(ctx context.Context) (executo
 
 	// create all scaling groups that are newly discovered
 	for _, group := range nodeGroups {
-		exists, err := patchNodeGroupName(ctx, r.k8sClient, group.Name, group.NodeGroupName)
+		exists, err := patchScalingGroup(ctx, r.k8sClient, group)
 		if err != nil {
 			return executor.Result{}, err
 		}
@@ -81,6 +83,11 @@ func (r *ExternalScalingGroupReconciler) Reconcile(ctx context.Context) (executo
 			nodeGroupName:        group.NodeGroupName,
 			autoscalingGroupName: group.AutoscalingGroupName,
 			role:                 group.Role,
+			nodeLabels:           group.NodeLabels,
+			nodeTaints:           group.NodeTaints,
+			spot:                 group.Spot,
+			min:                  group.Min,
+			max:                  group.Max,
 		})
 		if err != nil {
 			return executor.Result{}, err
@@ -110,11 +117,13 @@ func (r *ExternalScalingGroupReconciler) Reconcile(ctx context.Context) (executo
 	return executor.Result{}, nil
 }
 
-// patchNodeGroupName patches the node group name of a scaling group resource (if necessary and it exists).
-func patchNodeGroupName(ctx context.Context, k8sClient k8sReadWriter, resourceName, nodeGroupName string) (exists bool, err error) {
+// patchScalingGroup patches the node group name and node registration settings
+// of a scaling group resource (if necessary and it exists).
+// Autoscaling bounds are only set on creation, so changes made by the user are kept.
+func patchScalingGroup(ctx context.Context, k8sClient k8sReadWriter, group cspapi.ScalingGroup) (exists bool, err error) {
 	logr := log.FromContext(ctx)
 	var scalingGroup updatev1alpha1.ScalingGroup
-	err = k8sClient.Get(ctx, client.ObjectKey{Name: resourceName}, &scalingGroup)
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: group.Name}, &scalingGroup)
 	if k8sErrors.IsNotFound(err) {
 		// scaling group does not exist
 		// no need to patch
@@ -123,22 +132,41 @@ func patchNodeGroupName(ctx context.Context, k8sClient k8sReadWriter, resourceNa
 	if err != nil {
 		return false, err
 	}
-	if scalingGroup.Spec.NodeGroupName == nodeGroupName {
-		// scaling group already has the correct node group name
+	if scalingGroupUpToDate(scalingGroup.Spec, group) {
+		// scaling group already has the correct node group name and node registration settings
 		return true /* exists */, nil
 	}
-	logr.Info("patching node group name", "resourceName", resourceName, "nodeGroupName", nodeGroupName)
+	logr.Info("patching scaling group", "resourceName", group.Name, "nodeGroupName", group.NodeGroupName)
 	return true, retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		if err := k8sClient.Get(ctx, client.ObjectKey{Name: resourceName}, &scalingGroup); err != nil {
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: group.Name}, &scalingGroup); err != nil {
 			return err
 		}
-		scalingGroup.Spec.NodeGroupName = nodeGroupName
+		scalingGroup.Spec.NodeGroupName = group.NodeGroupName
+		scalingGroup.Spec.NodeLabels = group.NodeLabels
+		scalingGroup.Spec.NodeTaints = group.NodeTaints
+		scalingGroup.Spec.Spot = group.Spot
 		return k8sClient.Update(ctx, &scalingGroup)
 	})
 }
 
+// scalingGroupUpToDate returns true if the spec matches the node group name and node registration settings of the group.
+func scalingGroupUpToDate(spec updatev1alpha1.ScalingGroupSpec, group cspapi.ScalingGroup) bool {
+	return spec.NodeGroupName == group.NodeGroupName &&
+		spec.Spot == group.Spot &&
+		equality.Semantic.DeepEqual(spec.NodeLabels, group.NodeLabels) &&
+		equality.Semantic.DeepEqual(spec.NodeTaints, group.NodeTaints)
+}
+
 func createScalingGroupIfNotExists(ctx context.Context, config newScalingGroupConfig) error {
 	logr := log.FromContext(ctx)
+	autoscaling, minSize, maxSize := false, int32(defaultScalingGroupMin), int32(defaultScalingGroupMax)
+	if config.min > 0 || config.max > 0 {
+		// the user configured autoscaling bounds for the node group
+		autoscaling, minSize, maxSize = true, config.min, config.max
+		if maxSize == 0 {
+			maxSize = max(defaultScalingGroupMax, minSize)
+		}
+	}
 	err := config.k8sClient.Create(ctx, &updatev1alpha1.ScalingGroup{
 		TypeMeta: metav1.TypeMeta{APIVersion: "update.edgeless.systems/v1alpha1", Kind: "ScalingGroup"},
 		ObjectMeta: metav1.ObjectMeta{
@@ -149,9 +177,13 @@ func createScalingGroupIfNotExists(ctx context.Context, config newScalingGroupCo
 			GroupID:             config.groupID,
 			AutoscalerGroupName: config.autoscalingGroupName,
 			NodeGroupName:       config.nodeGroupName,
-			Min:                 defaultScalingGroupMin,
-			Max:                 defaultScalingGroupMax,
+			Autoscaling:         autoscaling,
+			Min:                 minSize,
+			Max:                 maxSize,
 			Role:                config.role,
+			NodeLabels:          config.nodeLabels,
+			NodeTaints:          config.nodeTaints,
+			Spot:                config.spot,
 		},
 	})
 	if k8sErrors.IsAlreadyExists(err) {
@@ -182,4 +214,9 @@ type newScalingGroupConfig struct {
 	nodeGroupName        string
 	autoscalingGroupName string
 	role                 updatev1alpha1.NodeRole
+	nodeLabels           map[string]string
+	nodeTaints           []corev1.Taint
+	spot                 bool
+	min                  int32
+	max                  int32
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/operators/constellation-node-operator/v2/internal/constants"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -30,6 +31,10 @@ import (
 func TestCreateScalingGroupIfNotExists(t *testing.T) {
 	testCases := map[string]struct {
 		createErr        error
+		nodeLabels       map[string]string
+		nodeTaints       []corev1.Taint
+		spot             bool
+		min, max         int32
 		wantScalingGroup *updatev1alpha1.ScalingGroup
 		wantErr          bool
 	}{
@@ -50,6 +55,51 @@ func TestCreateScalingGroupIfNotExists(t *testing.T) {
 				},
 			},
 		},
+		"create with node registration and autoscaling bounds": {
+			nodeLabels: map[string]string{"workload": "batch"},
+			nodeTaints: []corev1.Taint{{Key: "dedicated", Value: "batch", Effect: corev1.TaintEffectNoSchedule}},
+			spot:       true,
+			min:        2,
+			max:        5,
+			wantScalingGroup: &updatev1alpha1.ScalingGroup{
+				TypeMeta: metav1.TypeMeta{APIVersion: "update.edgeless.systems/v1alpha1", Kind: "ScalingGroup"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "resource-name",
+				},
+				Spec: updatev1alpha1.ScalingGroupSpec{
+					NodeVersion:         mainconstants.NodeVersionResourceName,
+					GroupID:             "group-id",
+					AutoscalerGroupName: "autoscaling-group-name",
+					NodeGroupName:       "node-group-name",
+					Autoscaling:         true,
+					Min:                 2,
+					Max:                 5,
+					Role:                updatev1alpha1.WorkerRole,
+					NodeLabels:          map[string]string{"workload": "batch"},
+					NodeTaints:          []corev1.Taint{{Key: "dedicated", Value: "batch", Effect: corev1.TaintEffectNoSchedule}},
+					Spot:                true,
+				},
+			},
+		},
+		"create with only min size": {
+			min: 12,
+			wantScalingGroup: &updatev1alpha1.ScalingGroup{
+				TypeMeta: metav1.TypeMeta{APIVersion: "update.edgeless.systems/v1alpha1", Kind: "ScalingGroup"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "resource-name",
+				},
+				Spec: updatev1alpha1.ScalingGroupSpec{
+					NodeVersion:         mainconstants.NodeVersionResourceName,
+					GroupID:             "group-id",
+					AutoscalerGroupName: "autoscaling-group-name",
+					NodeGroupName:       "node-group-name",
+					Autoscaling:         true,
+					Min:                 12,
+					Max:                 12,
+					Role:                updatev1alpha1.WorkerRole,
+				},
+			},
+		},
 		"create fails": {
 			createErr: errors.New("create failed"),
 			wantErr:   true,
@@ -87,6 +137,11 @@ func TestCreateScalingGroupIfNotExists(t *testing.T) {
 				nodeGroupName:        "node-group-name",
 				autoscalingGroupName: "autoscaling-group-name",
 				role:                 updatev1alpha1.WorkerRole,
+				nodeLabels:           tc.nodeLabels,
+				nodeTaints:           tc.nodeTaints,
+				spot:                 tc.spot,
+				min:                  tc.min,
+				max:                  tc.max,
 			}
 			err := createScalingGroupIfNotExists(context.Background(), newScalingGroupConfig)
 			if tc.wantErr {
@@ -100,12 +155,14 @@ func TestCreateScalingGroupIfNotExists(t *testing.T) {
 	}
 }
 
-func TestPatchNodeGroupName(t *testing.T) {
+func TestPatchScalingGroup(t *testing.T) {
 	testCases := map[string]struct {
 		getRes     client.Object
 		getErr     error
 		updateErr  error
+		nodeLabels map[string]string
 		wantExists bool
+		wantUpdate bool
 		wantErr    bool
 	}{
 		"patching works": {
@@ -125,6 +182,7 @@ func TestPatchNodeGroupName(t *testing.T) {
 				},
 			},
 			wantExists: true,
+			wantUpdate: true,
 		},
 		"name already set": {
 			getRes: &updatev1alpha1.ScalingGroup{
@@ -145,6 +203,28 @@ func TestPatchNodeGroupName(t *testing.T) {
 			},
 			wantExists: true,
 		},
+		"node labels changed": {
+			getRes: &updatev1alpha1.ScalingGroup{
+				TypeMeta: metav1.TypeMeta{APIVersion: "update.edgeless.systems/v1alpha1", Kind: "ScalingGroup"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "resource-name",
+				},
+				Spec: updatev1alpha1.ScalingGroupSpec{
+					NodeVersion:         mainconstants.NodeVersionResourceName,
+					GroupID:             "group-id",
+					NodeGroupName:       "node-group-name",
+					AutoscalerGroupName: "autoscaling-group-name",
+					Autoscaling:         true,
+					Min:                 1,
+					Max:                 10,
+					Role:                updatev1alpha1.WorkerRole,
+					NodeLabels:          map[string]string{"workload": "batch"},
+				},
+			},
+			nodeLabels: map[string]string{"workload": "web"},
+			wantExists: true,
+			wantUpdate: true,
+		},
 		"does not exist": {
 			getErr:     k8sErrors.NewNotFound(schema.GroupResource{}, "resource-name"),
 			wantExists: false,
@@ -184,13 +264,26 @@ func TestPatchNodeGroupName(t *testing.T) {
 				getErr:    tc.getErr,
 				updateErr: tc.updateErr,
 			}
-			gotExists, gotErr := patchNodeGroupName(context.Background(), k8sClient, "resource-name", "node-group-name")
+			group := cspapi.ScalingGroup{
+				Name:          "resource-name",
+				NodeGroupName: "node-group-name",
+				NodeLabels:    tc.nodeLabels,
+			}
+			gotExists, gotErr := patchScalingGroup(context.Background(), k8sClient, group)
 			if tc.wantErr {
 				assert.Error(gotErr)
 				return
 			}
 			require.NoError(gotErr)
 			assert.Equal(tc.wantExists, gotExists)
+			if !tc.wantUpdate {
+				assert.Empty(k8sClient.updatedObjects)
+				return
+			}
+			require.Len(k8sClient.updatedObjects, 1)
+			updated := k8sClient.updatedObjects[0].(*updatev1alpha1.ScalingGroup)
+			assert.Equal(group.NodeGroupName, updated.Spec.NodeGroupName)
+			assert.Equal(group.NodeLabels, updated.Spec.NodeLabels)
 		})
 	}
 }
@@ -198,6 +291,7 @@ func TestPatchNodeGroupName(t *testing.T) {
 type fakeK8sClient struct {
 	getRes         client.Object
 	createdObjects []client.Object
+	updatedObjects []client.Object
 	createErr      error
 	listErr        error
 	getErr         error
@@ -230,8 +324,12 @@ func (s *fakeK8sClient) Get(_ context.Context, _ types.NamespacedName, out clien
 	return nil
 }
 
-func (s *fakeK8sClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
-	return s.updateErr
+func (s *fakeK8sClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	if s.updateErr != nil {
+		return s.updateErr
+	}
+	s.updatedObjects = append(s.updatedObjects, obj.DeepCopyObject().(client.Object))
+	return nil
 }
 
 func (s *fakeK8sClient) List(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
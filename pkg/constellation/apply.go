@@ -9,8 +9,8 @@ package constellation
 import (
 	"context"
 	"fmt"
+	"net"
 
-	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
@@ -18,6 +18,7 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
 	"github.com/edgelesssys/constellation/v2/internal/license"
+	"google.golang.org/grpc"
 )
 
 // ApplyContext denotes the context in which the apply command is run.
@@ -36,14 +37,18 @@ const (
 type Applier struct {
 	log            debugLog
 	licenseChecker licenseChecker
-	spinner        spinnerInterf
+	onProgress     ProgressFunc
 
 	applyContext ApplyContext
 
 	// newDialer creates a new aTLS gRPC dialer.
-	newDialer     func(validator atls.Validator) *dialer.Dialer
-	kubecmdClient kubecmdClient
-	helmClient    helmApplier
+	newDialer NewDialerFunc
+	// newInfraApplier and newTerminator manage the Terraform workspace of the cluster.
+	newInfraApplier func(context.Context, InfrastructureOptions) (infraApplier, func(), error)
+	newTerminator   func() infraTerminator
+	kubecmdClient   kubecmdClient
+	helmClient      helmApplier
+	releaseLister   releaseVersionLister
 }
 
 type licenseChecker interface {
@@ -54,18 +59,42 @@ type debugLog interface {
 	Debugf(format string, args ...any)
 }
 
+// Dialer dials the gRPC services of Constellation nodes.
+type Dialer interface {
+	// Dial connects to target over aTLS, validating the attestation of the node.
+	Dial(ctx context.Context, target string) (*grpc.ClientConn, error)
+	// DialInsecure connects to target without TLS.
+	DialInsecure(ctx context.Context, target string) (*grpc.ClientConn, error)
+}
+
+// NewDialerFunc returns a Dialer validating the attestation of nodes with validator.
+type NewDialerFunc func(validator Validator) Dialer
+
+// DefaultDialer returns a Dialer connecting to nodes over TCP.
+func DefaultDialer(validator Validator) Dialer {
+	return dialer.New(nil, validator, &net.Dialer{})
+}
+
 // NewApplier creates a new Applier.
+// onProgress is called when long-running operations make progress. It may be nil.
+// newDialer creates the dialers used to connect to the nodes of the cluster. If it's nil, [DefaultDialer] is used.
 func NewApplier(
-	log debugLog, spinner spinnerInterf,
+	log debugLog, onProgress ProgressFunc,
 	applyContext ApplyContext,
-	newDialer func(validator atls.Validator) *dialer.Dialer,
+	newDialer NewDialerFunc,
 ) *Applier {
+	if newDialer == nil {
+		newDialer = DefaultDialer
+	}
 	return &Applier{
 		log:            log,
-		spinner:        spinner,
+		onProgress:     onProgress,
 		licenseChecker: license.NewChecker(),
 		applyContext:   applyContext,
 		newDialer:      newDialer,
+
+		newInfraApplier: newInfraApplier,
+		newTerminator:   newInfraTerminator,
 	}
 }
 
@@ -79,8 +108,13 @@ func (a *Applier) SetKubeConfig(kubeConfig []byte) error {
 	if err != nil {
 		return err
 	}
+	releaseLister, err := helm.NewReleaseVersionClient(kubeConfig, a.log)
+	if err != nil {
+		return err
+	}
 	a.kubecmdClient = kubecmdClient
 	a.helmClient = helmClient
+	a.releaseLister = releaseLister
 	return nil
 }
 
@@ -19,7 +19,7 @@ import (
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/bootstrapper/initproto"
-	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
 	"github.com/edgelesssys/constellation/v2/internal/grpc/grpclog"
@@ -38,6 +38,43 @@ type InitPayload struct {
 	K8sVersion      versions.ValidK8sVersion
 	ConformanceMode bool
 	ServiceCIDR     string
+	// PodCIDR is the pod network of the cluster. An empty value refers to the default pod network.
+	PodCIDR string
+	// KubernetesOverrides are optional overrides for the API server and kubelet configuration.
+	KubernetesOverrides *config.KubernetesOverrides
+	// DiskEncryptionProfile is the name of the encryption profile of the state disks.
+	// It must match the profile the infrastructure was created with.
+	DiskEncryptionProfile string
+}
+
+// kubernetesConfigOverrides converts the user supplied overrides to their protobuf representation.
+func kubernetesConfigOverrides(overrides *config.KubernetesOverrides) *initproto.KubernetesConfigOverrides {
+	if overrides == nil {
+		return nil
+	}
+
+	res := &initproto.KubernetesConfigOverrides{}
+	if apiServer := overrides.APIServer; apiServer != nil {
+		res.ApiserverEnableAdmissionPlugins = apiServer.EnableAdmissionPlugins
+		res.ApiserverDisableAdmissionPlugins = apiServer.DisableAdmissionPlugins
+		res.AuditPolicy = []byte(apiServer.AuditPolicy)
+		if oidc := apiServer.OIDC; oidc != nil {
+			res.ApiserverOidc = &initproto.OIDCConfig{
+				IssuerUrl:      oidc.IssuerURL,
+				ClientId:       oidc.ClientID,
+				UsernameClaim:  oidc.UsernameClaim,
+				UsernamePrefix: oidc.UsernamePrefix,
+				GroupsClaim:    oidc.GroupsClaim,
+				GroupsPrefix:   oidc.GroupsPrefix,
+			}
+		}
+	}
+	if kubelet := overrides.Kubelet; kubelet != nil {
+		res.KubeletEvictionHard = kubelet.EvictionHard
+		res.KubeletEvictionSoft = kubelet.EvictionSoft
+		res.KubeletEvictionSoftGracePeriod = kubelet.EvictionSoftGracePeriod
+	}
+	return res
 }
 
 // GrpcDialer dials a gRPC server.
@@ -46,11 +83,11 @@ type GrpcDialer interface {
 }
 
 // Init performs the init RPC.
+// If the bootstrapper fails to initialize the cluster, a [*NonRetriableInitError] holding its logs is returned.
 func (a *Applier) Init(
 	ctx context.Context,
-	validator atls.Validator,
+	validator Validator,
 	state *state.State,
-	clusterLogWriter io.Writer,
 	payload InitPayload,
 ) (
 	InitOutput,
@@ -58,16 +95,19 @@ func (a *Applier) Init(
 ) {
 	// Prepare the Request
 	req := &initproto.InitRequest{
-		KmsUri:               payload.MasterSecret.EncodeToURI(),
-		StorageUri:           uri.NoStoreURI,
-		MeasurementSalt:      payload.MeasurementSalt,
-		KubernetesVersion:    versions.VersionConfigs[payload.K8sVersion].ClusterVersion,
-		KubernetesComponents: versions.VersionConfigs[payload.K8sVersion].KubernetesComponents,
-		ConformanceMode:      payload.ConformanceMode,
-		InitSecret:           state.Infrastructure.InitSecret,
-		ClusterName:          state.Infrastructure.Name,
-		ApiserverCertSans:    state.Infrastructure.APIServerCertSANs,
-		ServiceCidr:          payload.ServiceCIDR,
+		KmsUri:                    payload.MasterSecret.EncodeToURI(),
+		StorageUri:                uri.NoStoreURI,
+		MeasurementSalt:           payload.MeasurementSalt,
+		KubernetesVersion:         versions.VersionConfigs[payload.K8sVersion].ClusterVersion,
+		KubernetesComponents:      versions.VersionConfigs[payload.K8sVersion].KubernetesComponents,
+		ConformanceMode:           payload.ConformanceMode,
+		InitSecret:                state.Infrastructure.InitSecret,
+		ClusterName:               state.Infrastructure.Name,
+		ApiserverCertSans:         state.Infrastructure.APIServerCertSANs,
+		ServiceCidr:               payload.ServiceCIDR,
+		PodCidr:                   payload.PodCIDR,
+		KubernetesConfigOverrides: kubernetesConfigOverrides(payload.KubernetesOverrides),
+		DiskEncryptionProfile:     payload.DiskEncryptionProfile,
 	}
 
 	doer := &initDoer{
@@ -76,10 +116,9 @@ func (a *Applier) Init(
 			state.Infrastructure.ClusterEndpoint,
 			strconv.Itoa(constants.BootstrapperPort),
 		),
-		req:              req,
-		log:              a.log,
-		clusterLogWriter: clusterLogWriter,
-		spinner:          a.spinner,
+		req:            req,
+		log:            a.log,
+		reportProgress: a.reportProgress,
 	}
 
 	// Create a wrapper function that allows logging any returned error from the retrier before checking if it's the expected retriable one.
@@ -91,12 +130,17 @@ func (a *Applier) Init(
 
 	// Perform the RPC
 	a.log.Debugf("Making initialization call, doer is %+v", doer)
-	a.spinner.Start("Connecting ", false)
+	a.reportProgress(PhaseConnecting, false)
 	retrier := retry.NewIntervalRetrier(doer, 30*time.Second, serviceIsUnavailable)
-	if err := retrier.Do(ctx); err != nil {
+	err := retrier.Do(ctx)
+	if doer.connectedOnce {
+		a.reportProgress(PhaseInitializing, true)
+	} else {
+		a.reportProgress(PhaseConnecting, true)
+	}
+	if err != nil {
 		return InitOutput{}, fmt.Errorf("doing init call: %w", err)
 	}
-	a.spinner.Stop()
 	a.log.Debugf("Initialization request finished")
 
 	a.log.Debugf("Rewriting cluster server address in kubeconfig to %s", state.Infrastructure.ClusterEndpoint)
@@ -145,10 +189,12 @@ type initDoer struct {
 	req           *initproto.InitRequest
 	log           debugLog
 	connectedOnce bool
-	spinner       spinnerInterf
 
-	// clusterLogWriter is the writer to which the cluster logs are written.
-	clusterLogWriter io.Writer
+	// reportProgress is called when the init call enters or finishes a phase.
+	reportProgress func(phase Phase, done bool)
+
+	// clusterLogs are the logs collected from the bootstrapper after the init call failed.
+	clusterLogs []byte
 
 	// Read-Only-fields:
 
@@ -156,12 +202,6 @@ type initDoer struct {
 	resp *initproto.InitSuccessResponse
 }
 
-type spinnerInterf interface {
-	Start(text string, showDots bool)
-	Stop()
-	io.Writer
-}
-
 // Do performs the init gRPC call.
 func (d *initDoer) Do(ctx context.Context) error {
 	// connectedOnce is set in handleGRPCStateChanges when a connection was established in one retry attempt.
@@ -202,11 +242,12 @@ func (d *initDoer) Do(ctx context.Context) error {
 		if e := d.getLogs(resp); e != nil {
 			d.log.Debugf("Failed to collect logs: %s", e)
 			return &NonRetriableInitError{
+				ClusterLogs:      d.clusterLogs,
 				LogCollectionErr: e,
 				Err:              err,
 			}
 		}
-		return &NonRetriableInitError{Err: err}
+		return &NonRetriableInitError{ClusterLogs: d.clusterLogs, Err: err}
 	}
 
 	switch res.Kind.(type) {
@@ -216,33 +257,36 @@ func (d *initDoer) Do(ctx context.Context) error {
 		if e := d.getLogs(resp); e != nil {
 			d.log.Debugf("Failed to get logs from cluster: %s", e)
 			return &NonRetriableInitError{
+				ClusterLogs:      d.clusterLogs,
 				LogCollectionErr: e,
 				Err:              errors.New(res.GetInitFailure().GetError()),
 			}
 		}
-		return &NonRetriableInitError{Err: errors.New(res.GetInitFailure().GetError())}
+		return &NonRetriableInitError{ClusterLogs: d.clusterLogs, Err: errors.New(res.GetInitFailure().GetError())}
 	case nil:
 		d.log.Debugf("Cluster returned nil response type")
 		err = errors.New("empty response from cluster")
 		if e := d.getLogs(resp); e != nil {
 			d.log.Debugf("Failed to collect logs: %s", e)
 			return &NonRetriableInitError{
+				ClusterLogs:      d.clusterLogs,
 				LogCollectionErr: e,
 				Err:              err,
 			}
 		}
-		return &NonRetriableInitError{Err: err}
+		return &NonRetriableInitError{ClusterLogs: d.clusterLogs, Err: err}
 	default:
 		d.log.Debugf("Cluster returned unknown response type")
 		err = errors.New("unknown response from cluster")
 		if e := d.getLogs(resp); e != nil {
 			d.log.Debugf("Failed to collect logs: %s", e)
 			return &NonRetriableInitError{
+				ClusterLogs:      d.clusterLogs,
 				LogCollectionErr: e,
 				Err:              err,
 			}
 		}
-		return &NonRetriableInitError{Err: err}
+		return &NonRetriableInitError{ClusterLogs: d.clusterLogs, Err: err}
 	}
 	return nil
 }
@@ -272,9 +316,7 @@ func (d *initDoer) getLogs(resp initproto.API_InitClient) error {
 		if log == nil {
 			return errors.New("received empty logs")
 		}
-		if _, err := d.clusterLogWriter.Write(log); err != nil {
-			return fmt.Errorf("writing logs: %w", err)
-		}
+		d.clusterLogs = append(d.clusterLogs, log...)
 	}
 
 	d.log.Debugf("Received cluster logs")
@@ -284,15 +326,19 @@ func (d *initDoer) getLogs(resp initproto.API_InitClient) error {
 func (d *initDoer) handleGRPCStateChanges(ctx context.Context, wg *sync.WaitGroup, conn *grpc.ClientConn) {
 	grpclog.LogStateChangesUntilReady(ctx, conn, d.log, wg, func() {
 		d.connectedOnce = true
-		d.spinner.Stop()
-		d.spinner.Start("Initializing cluster ", false)
+		d.reportProgress(PhaseConnecting, true)
+		d.reportProgress(PhaseInitializing, false)
 	})
 }
 
 // NonRetriableInitError is returned when the init RPC fails and the error is not retriable.
 type NonRetriableInitError struct {
+	// ClusterLogs are the logs collected from the bootstrapper. They may be incomplete if LogCollectionErr is set.
+	ClusterLogs []byte
+	// LogCollectionErr is set if collecting the logs from the bootstrapper failed.
 	LogCollectionErr error
-	Err              error
+	// Err is the error returned by the init RPC.
+	Err error
 }
 
 // Error returns the error message.
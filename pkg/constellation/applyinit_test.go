@@ -11,14 +11,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"net"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/edgelesssys/constellation/v2/bootstrapper/initproto"
-	"github.com/edgelesssys/constellation/v2/internal/atls"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/config"
@@ -205,18 +203,18 @@ func TestInit(t *testing.T) {
 			stop := setupTestInitServer(netDialer, tc.server, tc.initServerEndpoint)
 			defer stop()
 
+			var progress []ProgressEvent
 			a := &Applier{
-				log:     logger.NewTest(t),
-				spinner: &nopSpinner{},
-				newDialer: func(atls.Validator) *dialer.Dialer {
+				log:        logger.NewTest(t),
+				onProgress: func(event ProgressEvent) { progress = append(progress, event) },
+				newDialer: func(Validator) Dialer {
 					return dialer.New(nil, nil, netDialer)
 				},
 			}
 
-			clusterLogs := &bytes.Buffer{}
 			ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
 			defer cancel()
-			_, err := a.Init(ctx, nil, tc.state, clusterLogs, InitPayload{
+			_, err := a.Init(ctx, nil, tc.state, InitPayload{
 				MasterSecret:    uri.MasterSecret{},
 				MeasurementSalt: []byte{},
 				K8sVersion:      "v1.26.5",
@@ -224,10 +222,77 @@ func TestInit(t *testing.T) {
 			})
 			if tc.wantErr {
 				assert.Error(err)
-				assert.Equal(tc.wantClusterLogs, clusterLogs.Bytes())
+				var clusterLogs []byte
+				var nonRetriable *NonRetriableInitError
+				if errors.As(err, &nonRetriable) {
+					clusterLogs = nonRetriable.ClusterLogs
+				}
+				assert.Equal(tc.wantClusterLogs, clusterLogs)
 			} else {
 				assert.NoError(err)
 			}
+			assert.NotEmpty(progress)
+			assert.Equal(ProgressEvent{Phase: PhaseConnecting}, progress[0])
+			assert.True(progress[len(progress)-1].Done)
+		})
+	}
+}
+
+func TestKubernetesConfigOverrides(t *testing.T) {
+	testCases := map[string]struct {
+		overrides *config.KubernetesOverrides
+		want      *initproto.KubernetesConfigOverrides
+	}{
+		"no overrides": {},
+		"empty overrides": {
+			overrides: &config.KubernetesOverrides{},
+			want:      &initproto.KubernetesConfigOverrides{},
+		},
+		"all overrides": {
+			overrides: &config.KubernetesOverrides{
+				APIServer: &config.APIServerOverrides{
+					EnableAdmissionPlugins:  []string{"AlwaysPullImages"},
+					DisableAdmissionPlugins: []string{"DefaultStorageClass"},
+					OIDC: &config.OIDCConfig{
+						IssuerURL:      "https://issuer.example.com",
+						ClientID:       "client",
+						UsernameClaim:  "email",
+						UsernamePrefix: "oidc:",
+						GroupsClaim:    "groups",
+						GroupsPrefix:   "oidc:",
+					},
+					AuditPolicy: "policy",
+				},
+				Kubelet: &config.KubeletOverrides{
+					EvictionHard:            map[string]string{"memory.available": "100Mi"},
+					EvictionSoft:            map[string]string{"memory.available": "200Mi"},
+					EvictionSoftGracePeriod: map[string]string{"memory.available": "1m"},
+				},
+			},
+			want: &initproto.KubernetesConfigOverrides{
+				ApiserverEnableAdmissionPlugins:  []string{"AlwaysPullImages"},
+				ApiserverDisableAdmissionPlugins: []string{"DefaultStorageClass"},
+				ApiserverOidc: &initproto.OIDCConfig{
+					IssuerUrl:      "https://issuer.example.com",
+					ClientId:       "client",
+					UsernameClaim:  "email",
+					UsernamePrefix: "oidc:",
+					GroupsClaim:    "groups",
+					GroupsPrefix:   "oidc:",
+				},
+				AuditPolicy:                    []byte("policy"),
+				KubeletEvictionHard:            map[string]string{"memory.available": "100Mi"},
+				KubeletEvictionSoft:            map[string]string{"memory.available": "200Mi"},
+				KubeletEvictionSoftGracePeriod: map[string]string{"memory.available": "1m"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(tc.want, kubernetesConfigOverrides(tc.overrides))
 		})
 	}
 }
@@ -286,13 +351,12 @@ func TestAttestation(t *testing.T) {
 
 	initer := &Applier{
 		log: logger.NewTest(t),
-		newDialer: func(v atls.Validator) *dialer.Dialer {
+		newDialer: func(v Validator) Dialer {
 			return dialer.New(nil, v, netDialer)
 		},
-		spinner: &nopSpinner{},
 	}
 
-	_, err := initer.Init(ctx, validator, state, io.Discard, InitPayload{
+	_, err := initer.Init(ctx, validator, state, InitPayload{
 		MasterSecret:    uri.MasterSecret{},
 		MeasurementSalt: []byte{},
 		K8sVersion:      "v1.26.5",
@@ -345,16 +409,6 @@ func (i *testIssuer) Issue(_ context.Context, userData []byte, _ []byte) ([]byte
 	)
 }
 
-type nopSpinner struct {
-	io.Writer
-}
-
-func (s *nopSpinner) Start(string, bool) {}
-func (s *nopSpinner) Stop()              {}
-func (s *nopSpinner) Write(p []byte) (n int, err error) {
-	return s.Writer.Write(p)
-}
-
 func setupTestInitServer(dialer *testdialer.BufconnDialer, server initproto.APIServer, host string) func() {
 	serverCreds := atlscredentials.New(nil, nil)
 	initServer := grpc.NewServer(grpc.Creds(serverCreds))
@@ -0,0 +1,21 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+/*
+Package constellation is the Go SDK to manage the lifecycle of Constellation clusters.
+
+An [Applier] creates and terminates the infrastructure of a cluster, and initializes, upgrades and verifies the cluster on it.
+The Constellation CLI and the Terraform provider are built on top of it.
+The infrastructure is managed through a Terraform workspace configured by [InfrastructureOptions].
+
+Long-running operations accept a [context.Context] and can be canceled through it.
+Instead of printing to a terminal, they report their progress to the [ProgressFunc]
+passed to [NewApplier].
+
+Code within this module should use this package as the main entry point to interact with clusters,
+with priority over using other internal packages directly.
+*/
+package constellation
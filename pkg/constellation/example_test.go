@@ -0,0 +1,81 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
+)
+
+// stdLogger implements the loggers of the package on top of the standard library.
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...any) { log.Printf("DEBUG: "+format, args...) }
+func (stdLogger) Infof(format string, args ...any)  { log.Printf("INFO: "+format, args...) }
+func (stdLogger) Warnf(format string, args ...any)  { log.Printf("WARN: "+format, args...) }
+
+// This example creates the infrastructure of a cluster, initializes the cluster on it, and terminates it again.
+func Example() {
+	ctx := context.Background()
+
+	conf, err := constellation.ReadConfig("constellation-conf.yaml", "", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	applier := constellation.NewApplier(stdLogger{}, func(event constellation.ProgressEvent) {
+		fmt.Printf("%s done=%t\n", event.Phase, event.Done)
+	}, constellation.ApplyContextCLI, nil)
+
+	infraOpts := constellation.InfrastructureOptions{
+		WorkingDir: "constellation-terraform",
+		BackupDir:  "constellation-upgrade",
+		Output:     os.Stderr,
+	}
+	infra, err := applier.CreateInfrastructure(ctx, conf, infraOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := applier.TerminateInfrastructure(ctx, infraOpts); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	validator, err := constellation.NewValidator(conf.GetAttestationConfig(), stdLogger{})
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	masterSecret, err := applier.GenerateMasterSecret()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	measurementSalt, err := applier.GenerateMeasurementSalt()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	out, err := applier.Init(ctx, validator, constellation.NewState().SetInfrastructure(infra), constellation.InitPayload{
+		MasterSecret:          masterSecret,
+		MeasurementSalt:       measurementSalt,
+		K8sVersion:            conf.KubernetesVersion,
+		ServiceCIDR:           conf.ServiceCIDR,
+		DiskEncryptionProfile: conf.DiskEncryptionProfile,
+	})
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	fmt.Printf("initialized cluster %s\n", out.ClusterID)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+)
+
+// PrepareHelmCharts loads Helm charts for Constellation and returns an executor to apply them.
+func (a *Applier) PrepareHelmCharts(
+	flags helm.Options, state *state.State, serviceAccURI string, masterSecret uri.MasterSecret, openStackCfg *config.OpenStackConfig,
+) (helm.Applier, bool, error) {
+	if a.helmClient == nil {
+		return nil, false, errors.New("helm client not initialized")
+	}
+
+	executor, includesUpgrades, err := a.helmClient.PrepareApply(flags, state, serviceAccURI, masterSecret, openStackCfg)
+	if err != nil {
+		return nil, false, err
+	}
+	return &progressHelmApplier{Applier: executor, reportProgress: a.reportProgress}, includesUpgrades, nil
+}
+
+// progressHelmApplier reports the progress of applying Helm charts.
+type progressHelmApplier struct {
+	helm.Applier
+	reportProgress func(phase Phase, done bool)
+}
+
+// Apply applies the prepared Helm charts.
+func (p *progressHelmApplier) Apply(ctx context.Context) error {
+	p.reportProgress(PhaseApplyingHelmCharts, false)
+	defer p.reportProgress(PhaseApplyingHelmCharts, true)
+	return p.Applier.Apply(ctx)
+}
+
+// HelmReleaseVersions are the versions of the Helm releases installed in a cluster.
+type HelmReleaseVersions struct {
+	// ConstellationServices is the version of the constellation-services release.
+	ConstellationServices Semver
+	// ConstellationOperators is the version of the constellation-operators release.
+	ConstellationOperators Semver
+	// Releases are the versions of all installed releases, keyed by release name.
+	// CSI drivers are keyed by their chart name.
+	Releases map[string]string
+}
+
+// GetHelmReleaseVersions returns the versions of the Helm releases currently installed in the cluster.
+func (a *Applier) GetHelmReleaseVersions() (HelmReleaseVersions, error) {
+	if a.releaseLister == nil {
+		return HelmReleaseVersions{}, errors.New("helm client not initialized")
+	}
+
+	versions, err := a.releaseLister.Versions()
+	if err != nil {
+		return HelmReleaseVersions{}, fmt.Errorf("getting Helm release versions: %w", err)
+	}
+	return HelmReleaseVersions{
+		ConstellationServices:  versions.ConstellationServices(),
+		ConstellationOperators: versions.ConstellationOperators(),
+		Releases:               versions.Releases(),
+	}, nil
+}
+
+type helmApplier interface {
+	PrepareApply(
+		flags helm.Options, stateFile *state.State, serviceAccURI string, masterSecret uri.MasterSecret, openStackCfg *config.OpenStackConfig,
+	) (
+		helm.Applier, bool, error)
+}
+
+type releaseVersionLister interface {
+	Versions() (helm.ServiceVersions, error)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/edgelesssys/constellation/v2/internal/api/attestationconfigapi"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
+	"github.com/spf13/afero"
+)
+
+// InfrastructureOptions configures the Terraform workspace holding the cloud resources of a cluster.
+type InfrastructureOptions struct {
+	// WorkingDir is the directory of the Terraform workspace.
+	WorkingDir string
+	// BackupDir is the directory the Terraform workspace is backed up to before it's changed.
+	// It's only used by [Applier.CreateInfrastructure].
+	BackupDir string
+	// TerraformLogLevel is the log level of Terraform. Defaults to no logging.
+	TerraformLogLevel TerraformLogLevel
+	// Output receives the output of Terraform. If it's nil, the output is discarded.
+	Output io.Writer
+}
+
+// CreateInfrastructure creates the cloud resources of a cluster as described by conf,
+// or updates them if the Terraform workspace in opts.WorkingDir already holds resources.
+// If creating the resources fails, the resources created so far are destroyed.
+// The returned infrastructure has to be set on the [State] passed to [Applier.Init].
+func (a *Applier) CreateInfrastructure(ctx context.Context, conf *Config, opts InfrastructureOptions) (Infrastructure, error) {
+	if opts.WorkingDir == "" {
+		return Infrastructure{}, errors.New("no Terraform working directory specified")
+	}
+	a.reportProgress(PhaseCreatingInfrastructure, false)
+	defer a.reportProgress(PhaseCreatingInfrastructure, true)
+
+	infraApplier, cleanup, err := a.newInfraApplier(ctx, opts)
+	if err != nil {
+		return Infrastructure{}, err
+	}
+	defer cleanup()
+
+	a.log.Debugf("Planning infrastructure in %s", opts.WorkingDir)
+	if _, err := infraApplier.Plan(ctx, conf); err != nil {
+		return Infrastructure{}, fmt.Errorf("planning infrastructure: %w", err)
+	}
+	a.log.Debugf("Applying infrastructure")
+	infra, err := infraApplier.Apply(ctx, conf.GetProvider(), cloudcmd.WithRollbackOnError)
+	if err != nil {
+		return Infrastructure{}, fmt.Errorf("applying infrastructure: %w", err)
+	}
+	return infra, nil
+}
+
+// TerminateInfrastructure destroys all cloud resources of the Terraform workspace in opts.WorkingDir
+// and removes the workspace.
+func (a *Applier) TerminateInfrastructure(ctx context.Context, opts InfrastructureOptions) error {
+	if opts.WorkingDir == "" {
+		return errors.New("no Terraform working directory specified")
+	}
+	a.reportProgress(PhaseTerminatingInfrastructure, false)
+	defer a.reportProgress(PhaseTerminatingInfrastructure, true)
+
+	a.log.Debugf("Destroying infrastructure in %s", opts.WorkingDir)
+	if err := a.newTerminator().Terminate(ctx, opts.WorkingDir, opts.TerraformLogLevel); err != nil {
+		return fmt.Errorf("destroying infrastructure: %w", err)
+	}
+	return nil
+}
+
+// ReadConfig reads and validates the Constellation config file at path.
+// If profile isn't empty, the profile with that name is merged over the base configuration.
+// If force is true, validation errors that can be ignored are only logged.
+func ReadConfig(path, profile string, force bool) (*Config, error) {
+	return config.New(file.NewHandler(afero.NewOsFs()), path, profile, attestationconfigapi.NewFetcher(), force)
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return state.New()
+}
+
+type infraApplier interface {
+	Plan(ctx context.Context, conf *config.Config) (bool, error)
+	Apply(ctx context.Context, csp cloudprovider.Provider, withRollback cloudcmd.RollbackBehavior) (state.Infrastructure, error)
+}
+
+type infraTerminator interface {
+	Terminate(ctx context.Context, workspace string, logLevel terraform.LogLevel) error
+}
+
+func newInfraApplier(ctx context.Context, opts InfrastructureOptions) (infraApplier, func(), error) {
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	return cloudcmd.NewApplier(ctx, out, opts.WorkingDir, opts.BackupDir, opts.TerraformLogLevel, file.NewHandler(afero.NewOsFs()))
+}
+
+func newInfraTerminator() infraTerminator {
+	return cloudcmd.NewTerminator()
+}
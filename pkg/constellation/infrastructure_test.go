@@ -0,0 +1,169 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInfrastructure(t *testing.T) {
+	infra := state.Infrastructure{UID: "uid", ClusterEndpoint: "192.0.2.1"}
+
+	testCases := map[string]struct {
+		applier       *stubInfraApplier
+		newApplierErr error
+		workingDir    string
+		wantInfra     state.Infrastructure
+		wantErr       bool
+	}{
+		"success": {
+			applier:    &stubInfraApplier{infra: infra},
+			workingDir: "terraform",
+			wantInfra:  infra,
+		},
+		"no working dir": {
+			applier: &stubInfraApplier{infra: infra},
+			wantErr: true,
+		},
+		"creating applier fails": {
+			newApplierErr: assert.AnError,
+			workingDir:    "terraform",
+			wantErr:       true,
+		},
+		"plan fails": {
+			applier:    &stubInfraApplier{planErr: assert.AnError},
+			workingDir: "terraform",
+			wantErr:    true,
+		},
+		"apply fails": {
+			applier:    &stubInfraApplier{applyErr: assert.AnError},
+			workingDir: "terraform",
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			conf := config.Default()
+			conf.RemoveProviderAndAttestationExcept(cloudprovider.QEMU)
+
+			var cleanedUp bool
+			var progress []ProgressEvent
+			a := &Applier{
+				log:        logger.NewTest(t),
+				onProgress: func(event ProgressEvent) { progress = append(progress, event) },
+				newInfraApplier: func(_ context.Context, opts InfrastructureOptions) (infraApplier, func(), error) {
+					assert.Equal(tc.workingDir, opts.WorkingDir)
+					return tc.applier, func() { cleanedUp = true }, tc.newApplierErr
+				},
+			}
+
+			got, err := a.CreateInfrastructure(context.Background(), conf, InfrastructureOptions{WorkingDir: tc.workingDir})
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.wantInfra, got)
+			assert.Equal(cloudprovider.QEMU, tc.applier.provider)
+			assert.Equal(cloudcmd.WithRollbackOnError, tc.applier.rollback)
+			assert.True(cleanedUp)
+			assert.Equal([]ProgressEvent{
+				{Phase: PhaseCreatingInfrastructure},
+				{Phase: PhaseCreatingInfrastructure, Done: true},
+			}, progress)
+		})
+	}
+}
+
+func TestTerminateInfrastructure(t *testing.T) {
+	testCases := map[string]struct {
+		terminator *stubInfraTerminator
+		workingDir string
+		wantErr    bool
+	}{
+		"success": {
+			terminator: &stubInfraTerminator{},
+			workingDir: "terraform",
+		},
+		"no working dir": {
+			terminator: &stubInfraTerminator{},
+			wantErr:    true,
+		},
+		"terminate fails": {
+			terminator: &stubInfraTerminator{terminateErr: assert.AnError},
+			workingDir: "terraform",
+			wantErr:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			a := &Applier{
+				log:           logger.NewTest(t),
+				newTerminator: func() infraTerminator { return tc.terminator },
+			}
+
+			err := a.TerminateInfrastructure(context.Background(), InfrastructureOptions{
+				WorkingDir:        tc.workingDir,
+				TerraformLogLevel: terraform.LogLevelDebug,
+			})
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.workingDir, tc.terminator.workspace)
+			assert.Equal(terraform.LogLevelDebug, tc.terminator.logLevel)
+		})
+	}
+}
+
+type stubInfraApplier struct {
+	infra    state.Infrastructure
+	planErr  error
+	applyErr error
+
+	provider cloudprovider.Provider
+	rollback cloudcmd.RollbackBehavior
+}
+
+func (s *stubInfraApplier) Plan(context.Context, *config.Config) (bool, error) {
+	return true, s.planErr
+}
+
+func (s *stubInfraApplier) Apply(_ context.Context, csp cloudprovider.Provider, rollback cloudcmd.RollbackBehavior) (state.Infrastructure, error) {
+	s.provider = csp
+	s.rollback = rollback
+	return s.infra, s.applyErr
+}
+
+type stubInfraTerminator struct {
+	terminateErr error
+
+	workspace string
+	logLevel  terraform.LogLevel
+}
+
+func (s *stubInfraTerminator) Terminate(_ context.Context, workspace string, logLevel terraform.LogLevel) error {
+	s.workspace = workspace
+	s.logLevel = logLevel
+	return s.terminateErr
+}
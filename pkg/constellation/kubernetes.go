@@ -0,0 +1,219 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/semver"
+	"github.com/edgelesssys/constellation/v2/internal/versions"
+	"github.com/spf13/afero"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+var errKubecmdNotInitialised = errors.New("kubernetes client not initialized")
+
+// ExtendClusterConfigCertSANs extends the ClusterConfig stored under "kube-system/kubeadm-config" with the given SANs.
+func (a *Applier) ExtendClusterConfigCertSANs(ctx context.Context, clusterEndpoint, customEndpoint string, additionalAPIServerCertSANs []string) error {
+	if a.kubecmdClient == nil {
+		return errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseExtendingCertSANs, false)
+	defer a.reportProgress(PhaseExtendingCertSANs, true)
+
+	sans := append([]string{clusterEndpoint, customEndpoint}, additionalAPIServerCertSANs...)
+	if err := a.kubecmdClient.ExtendClusterConfigCertSANs(ctx, sans); err != nil {
+		return fmt.Errorf("extending cert SANs: %w", err)
+	}
+	return nil
+}
+
+// MissingClusterConfigCertSANs returns the SANs [Applier.ExtendClusterConfigCertSANs] would add to the ClusterConfig.
+func (a *Applier) MissingClusterConfigCertSANs(ctx context.Context, clusterEndpoint, customEndpoint string, additionalAPIServerCertSANs []string) ([]string, error) {
+	if a.kubecmdClient == nil {
+		return nil, errKubecmdNotInitialised
+	}
+
+	sans := append([]string{clusterEndpoint, customEndpoint}, additionalAPIServerCertSANs...)
+	missingSANs, err := a.kubecmdClient.MissingClusterConfigCertSANs(ctx, sans)
+	if err != nil {
+		return nil, fmt.Errorf("getting missing cert SANs: %w", err)
+	}
+	return missingSANs, nil
+}
+
+// GetConstellationVersion returns the image and Kubernetes version the cluster is currently targeting.
+func (a *Applier) GetConstellationVersion(ctx context.Context) (kubecmd.NodeVersion, error) {
+	if a.kubecmdClient == nil {
+		return kubecmd.NodeVersion{}, errKubecmdNotInitialised
+	}
+
+	return a.kubecmdClient.GetConstellationVersion(ctx)
+}
+
+// GetClusterAttestationConfig returns the attestation config currently set for the cluster.
+func (a *Applier) GetClusterAttestationConfig(ctx context.Context, variant variant.Variant) (config.AttestationCfg, error) {
+	if a.kubecmdClient == nil {
+		return nil, errKubecmdNotInitialised
+	}
+
+	return a.kubecmdClient.GetClusterAttestationConfig(ctx, variant)
+}
+
+// ApplyJoinConfig creates or updates the Constellation cluster's join-config ConfigMap.
+func (a *Applier) ApplyJoinConfig(ctx context.Context, newAttestConfig config.AttestationCfg, measurementSalt []byte) error {
+	if a.kubecmdClient == nil {
+		return errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseUpdatingAttestationConfig, false)
+	defer a.reportProgress(PhaseUpdatingAttestationConfig, true)
+
+	return a.kubecmdClient.ApplyJoinConfig(ctx, newAttestConfig, measurementSalt)
+}
+
+// UpgradeNodeImage upgrades the node image of the cluster to the given version.
+func (a *Applier) UpgradeNodeImage(ctx context.Context, imageVersion semver.Semver, imageReference string, force bool) error {
+	if a.kubecmdClient == nil {
+		return errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseUpgradingImage, false)
+	defer a.reportProgress(PhaseUpgradingImage, true)
+
+	return a.kubecmdClient.UpgradeNodeImage(ctx, imageVersion, imageReference, force)
+}
+
+// UpgradeKubernetesVersion upgrades the Kubernetes version of the cluster to the given version.
+func (a *Applier) UpgradeKubernetesVersion(ctx context.Context, kubernetesVersion versions.ValidK8sVersion, force bool) error {
+	if a.kubecmdClient == nil {
+		return errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseUpgradingKubernetes, false)
+	defer a.reportProgress(PhaseUpgradingKubernetes, true)
+
+	return a.kubecmdClient.UpgradeKubernetesVersion(ctx, kubernetesVersion, force)
+}
+
+// BackupCRDs backs up all CRDs to upgradeDir on fs.
+func (a *Applier) BackupCRDs(ctx context.Context, fs afero.Fs, upgradeDir string) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	if a.kubecmdClient == nil {
+		return nil, errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseBackingUpResources, false)
+	defer a.reportProgress(PhaseBackingUpResources, true)
+
+	return a.kubecmdClient.BackupCRDs(ctx, file.NewHandler(fs), upgradeDir)
+}
+
+// BackupCRs backs up all CRs of the given CRDs to upgradeDir on fs.
+func (a *Applier) BackupCRs(ctx context.Context, fs afero.Fs, crds []apiextensionsv1.CustomResourceDefinition, upgradeDir string) error {
+	if a.kubecmdClient == nil {
+		return errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseBackingUpResources, false)
+	defer a.reportProgress(PhaseBackingUpResources, true)
+
+	return a.kubecmdClient.BackupCRs(ctx, file.NewHandler(fs), crds, upgradeDir)
+}
+
+// WaitForControlPlaneNodes waits until count control-plane nodes are ready, attested, and members of etcd, or ctx is done.
+// progress is called whenever the join state of a control-plane node changes.
+// If ctx is done first, a [*ControlPlaneJoinError] listing the state of every node is returned.
+func (a *Applier) WaitForControlPlaneNodes(ctx context.Context, count int, progress func(ControlPlaneNode)) ([]ControlPlaneNode, error) {
+	if a.kubecmdClient == nil {
+		return nil, errKubecmdNotInitialised
+	}
+	a.reportProgress(PhaseWaitingForControlPlane, false)
+	defer a.reportProgress(PhaseWaitingForControlPlane, true)
+
+	nodes, err := a.kubecmdClient.WaitForControlPlaneNodes(ctx, count, func(node kubecmd.ControlPlaneNode) {
+		progress(ControlPlaneNode(node))
+	})
+	var joinErr *kubecmd.ControlPlaneJoinError
+	if errors.As(err, &joinErr) {
+		return controlPlaneNodes(nodes), &ControlPlaneJoinError{Nodes: controlPlaneNodes(joinErr.Nodes), Requested: joinErr.Requested}
+	}
+	return controlPlaneNodes(nodes), err
+}
+
+// ControlPlaneNode is the join state of a control-plane node.
+type ControlPlaneNode struct {
+	// Name is the name of the Kubernetes node.
+	Name string
+	// Ready is true if the node reports the Ready condition.
+	Ready bool
+	// Attested is true if the node passed attestation, either during init or by the join-service.
+	Attested bool
+	// EtcdMember is true if the node is a voting member of the etcd cluster.
+	EtcdMember bool
+}
+
+// Joined returns true if the node is ready, attested, and a member of etcd.
+func (n ControlPlaneNode) Joined() bool {
+	return kubecmd.ControlPlaneNode(n).Joined()
+}
+
+// String returns a human-readable description of the node's join state.
+func (n ControlPlaneNode) String() string {
+	return kubecmd.ControlPlaneNode(n).String()
+}
+
+// ControlPlaneJoinError is returned if not all requested control-plane nodes joined the cluster in time.
+type ControlPlaneJoinError struct {
+	// Nodes is the last observed join state of every control-plane node.
+	Nodes []ControlPlaneNode
+	// Requested is the number of control-plane nodes that were waited for.
+	Requested int
+}
+
+// Quorum returns true if at least (Requested/2)+1 nodes are etcd members.
+func (e *ControlPlaneJoinError) Quorum() bool {
+	return e.kubecmdError().Quorum()
+}
+
+// Error returns the error message, listing the state of every node.
+func (e *ControlPlaneJoinError) Error() string {
+	return e.kubecmdError().Error()
+}
+
+func (e *ControlPlaneJoinError) kubecmdError() *kubecmd.ControlPlaneJoinError {
+	nodes := make([]kubecmd.ControlPlaneNode, 0, len(e.Nodes))
+	for _, node := range e.Nodes {
+		nodes = append(nodes, kubecmd.ControlPlaneNode(node))
+	}
+	return &kubecmd.ControlPlaneJoinError{Nodes: nodes, Requested: e.Requested}
+}
+
+// controlPlaneNodes converts the join states returned by kubecmd.
+func controlPlaneNodes(nodes []kubecmd.ControlPlaneNode) []ControlPlaneNode {
+	if nodes == nil {
+		return nil
+	}
+	converted := make([]ControlPlaneNode, 0, len(nodes))
+	for _, node := range nodes {
+		converted = append(converted, ControlPlaneNode(node))
+	}
+	return converted
+}
+
+type kubecmdClient interface {
+	UpgradeNodeImage(ctx context.Context, imageVersion semver.Semver, imageReference string, force bool) error
+	UpgradeKubernetesVersion(ctx context.Context, kubernetesVersion versions.ValidK8sVersion, force bool) error
+	ExtendClusterConfigCertSANs(ctx context.Context, alternativeNames []string) error
+	MissingClusterConfigCertSANs(ctx context.Context, alternativeNames []string) ([]string, error)
+	GetConstellationVersion(ctx context.Context) (kubecmd.NodeVersion, error)
+	GetClusterAttestationConfig(ctx context.Context, variant variant.Variant) (config.AttestationCfg, error)
+	ApplyJoinConfig(ctx context.Context, newAttestConfig config.AttestationCfg, measurementSalt []byte) error
+	BackupCRs(ctx context.Context, fileHandler file.Handler, crds []apiextensionsv1.CustomResourceDefinition, upgradeDir string) error
+	BackupCRDs(ctx context.Context, fileHandler file.Handler, upgradeDir string) ([]apiextensionsv1.CustomResourceDefinition, error)
+	WaitForControlPlaneNodes(ctx context.Context, count int, progress func(kubecmd.ControlPlaneNode)) ([]kubecmd.ControlPlaneNode, error)
+}
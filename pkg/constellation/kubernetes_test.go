@@ -0,0 +1,110 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForControlPlaneNodes(t *testing.T) {
+	joined := kubecmd.ControlPlaneNode{Name: "control-plane-0", Ready: true, Attested: true, EtcdMember: true}
+	pending := kubecmd.ControlPlaneNode{Name: "control-plane-1", Ready: true}
+
+	testCases := map[string]struct {
+		client     *stubKubecmdClient
+		wantNodes  []ControlPlaneNode
+		wantJoin   bool
+		wantQuorum bool
+		wantErr    bool
+	}{
+		"all nodes joined": {
+			client:    &stubKubecmdClient{nodes: []kubecmd.ControlPlaneNode{joined, joined}},
+			wantNodes: []ControlPlaneNode{ControlPlaneNode(joined), ControlPlaneNode(joined)},
+		},
+		"not all nodes joined": {
+			client: &stubKubecmdClient{
+				nodes:   []kubecmd.ControlPlaneNode{joined, joined, pending},
+				waitErr: &kubecmd.ControlPlaneJoinError{Nodes: []kubecmd.ControlPlaneNode{joined, joined, pending}, Requested: 3},
+			},
+			wantNodes:  []ControlPlaneNode{ControlPlaneNode(joined), ControlPlaneNode(joined), ControlPlaneNode(pending)},
+			wantJoin:   true,
+			wantQuorum: true,
+			wantErr:    true,
+		},
+		"no quorum": {
+			client: &stubKubecmdClient{
+				nodes:   []kubecmd.ControlPlaneNode{joined, pending},
+				waitErr: &kubecmd.ControlPlaneJoinError{Nodes: []kubecmd.ControlPlaneNode{joined, pending}, Requested: 3},
+			},
+			wantNodes: []ControlPlaneNode{ControlPlaneNode(joined), ControlPlaneNode(pending)},
+			wantJoin:  true,
+			wantErr:   true,
+		},
+		"other error": {
+			client:  &stubKubecmdClient{waitErr: errors.New("failed")},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			var events []ProgressEvent
+			a := &Applier{
+				log:           logger.NewTest(t),
+				onProgress:    func(event ProgressEvent) { events = append(events, event) },
+				kubecmdClient: tc.client,
+			}
+
+			var progress []ControlPlaneNode
+			nodes, err := a.WaitForControlPlaneNodes(context.Background(), 2, func(node ControlPlaneNode) {
+				progress = append(progress, node)
+			})
+			assert.Equal(tc.wantNodes, nodes)
+			assert.Equal(tc.wantNodes, progress)
+			assert.Equal([]ProgressEvent{
+				{Phase: PhaseWaitingForControlPlane},
+				{Phase: PhaseWaitingForControlPlane, Done: true},
+			}, events)
+			if !tc.wantErr {
+				assert.NoError(err)
+				return
+			}
+			require.Error(err)
+
+			var joinErr *ControlPlaneJoinError
+			assert.Equal(tc.wantJoin, errors.As(err, &joinErr))
+			if tc.wantJoin {
+				assert.Equal(tc.wantNodes, joinErr.Nodes)
+				assert.Equal(tc.wantQuorum, joinErr.Quorum())
+				assert.Contains(err.Error(), pending.Name)
+			}
+		})
+	}
+}
+
+type stubKubecmdClient struct {
+	kubecmdClient
+	nodes   []kubecmd.ControlPlaneNode
+	waitErr error
+}
+
+func (c *stubKubecmdClient) WaitForControlPlaneNodes(_ context.Context, _ int, progress func(kubecmd.ControlPlaneNode)) ([]kubecmd.ControlPlaneNode, error) {
+	for _, node := range c.nodes {
+		progress(node)
+	}
+	return c.nodes, c.waitErr
+}
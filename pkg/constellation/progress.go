@@ -0,0 +1,56 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+// Phase is a step of a long-running cluster operation.
+type Phase string
+
+const (
+	// PhaseCreatingInfrastructure is entered while creating the cloud resources of a cluster.
+	PhaseCreatingInfrastructure Phase = "creating-infrastructure"
+	// PhaseTerminatingInfrastructure is entered while destroying the cloud resources of a cluster.
+	PhaseTerminatingInfrastructure Phase = "terminating-infrastructure"
+	// PhaseConnecting is entered while connecting to the bootstrapper of the first control-plane node.
+	PhaseConnecting Phase = "connecting"
+	// PhaseInitializing is entered once the bootstrapper accepted the init request.
+	PhaseInitializing Phase = "initializing"
+	// PhaseVerifying is entered while fetching and validating the attestation of a node.
+	PhaseVerifying Phase = "verifying"
+	// PhaseUpdatingAttestationConfig is entered while updating the attestation config of a cluster.
+	PhaseUpdatingAttestationConfig Phase = "updating-attestation-config"
+	// PhaseExtendingCertSANs is entered while extending the SANs of the API server certificate.
+	PhaseExtendingCertSANs Phase = "extending-cert-sans"
+	// PhaseBackingUpResources is entered while backing up CRDs and custom resources before an upgrade.
+	PhaseBackingUpResources Phase = "backing-up-resources"
+	// PhaseApplyingHelmCharts is entered while installing or upgrading the Helm charts of a cluster.
+	PhaseApplyingHelmCharts Phase = "applying-helm-charts"
+	// PhaseWaitingForControlPlane is entered while waiting for the control-plane nodes of a new cluster to join.
+	PhaseWaitingForControlPlane Phase = "waiting-for-control-plane"
+	// PhaseUpgradingImage is entered while scheduling the upgrade of the node image.
+	PhaseUpgradingImage Phase = "upgrading-image"
+	// PhaseUpgradingKubernetes is entered while scheduling the upgrade of the Kubernetes version.
+	PhaseUpgradingKubernetes Phase = "upgrading-kubernetes"
+)
+
+// ProgressEvent reports the progress of a long-running cluster operation.
+type ProgressEvent struct {
+	// Phase is the step the operation is in.
+	Phase Phase
+	// Done is true if the phase finished.
+	Done bool
+}
+
+// ProgressFunc is called whenever a long-running cluster operation enters or finishes a phase.
+// It's called synchronously and must not block.
+type ProgressFunc func(ProgressEvent)
+
+// reportProgress calls the progress callback of the Applier, if one is set.
+func (a *Applier) reportProgress(phase Phase, done bool) {
+	if a.onProgress != nil {
+		a.onProgress(ProgressEvent{Phase: phase, Done: done})
+	}
+}
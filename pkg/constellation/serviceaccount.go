@@ -0,0 +1,21 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+)
+
+// MarshalServiceAccountURI returns the service account URI for the given cloud provider.
+func MarshalServiceAccountURI(provider cloudprovider.Provider, payload ServiceAccountPayload) (string, error) {
+	return cloudcmd.MarshalServiceAccountURI(provider, payload)
+}
+
+// ServiceAccountPayload is data a service account URI can be built
+// from for a given cloud provider.
+type ServiceAccountPayload = cloudcmd.ServiceAccountPayload
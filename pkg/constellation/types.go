@@ -0,0 +1,75 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/azureshared"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/gcpshared"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/openstack"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
+	"github.com/edgelesssys/constellation/v2/internal/semver"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
+	"github.com/edgelesssys/constellation/v2/internal/versions"
+)
+
+// The types below are part of the API of this package, but are defined in internal packages.
+// They are re-exported, so code outside of this module can use them.
+// Unlike the types defined in this package, they change together with the internal packages,
+// and aren't covered by the compatibility guarantees of this package yet.
+type (
+	// Validator validates attestation documents of Constellation nodes.
+	Validator = atls.Validator
+	// Variant is an attestation variant.
+	Variant = variant.Variant
+	// CloudProvider is a cloud provider supported by Constellation.
+	CloudProvider = cloudprovider.Provider
+
+	// State describes the infrastructure and the cluster values of a Constellation cluster.
+	State = state.State
+	// Infrastructure describes the cloud resources of a Constellation cluster.
+	Infrastructure = state.Infrastructure
+	// ClusterValues describes the values of an initialized Constellation cluster.
+	ClusterValues = state.ClusterValues
+
+	// MasterSecret is the secret all keys of a cluster are derived from.
+	MasterSecret = uri.MasterSecret
+	// KubernetesVersion is a Kubernetes version supported by Constellation.
+	KubernetesVersion = versions.ValidK8sVersion
+	// KubernetesOverrides are overrides for the API server and kubelet configuration.
+	KubernetesOverrides = config.KubernetesOverrides
+	// Config is the configuration of a cluster, as read by [ReadConfig].
+	Config = config.Config
+	// AttestationConfig is the attestation configuration of a cluster.
+	AttestationConfig = config.AttestationCfg
+	// OpenStackConfig is the OpenStack specific configuration of a cluster.
+	OpenStackConfig = config.OpenStackConfig
+
+	// HelmOptions configures the installation and upgrade of the Helm charts of a cluster.
+	HelmOptions = helm.Options
+	// HelmApplier applies prepared Helm charts to a cluster.
+	HelmApplier = helm.Applier
+	// NodeVersion is the image and Kubernetes version a cluster is targeting.
+	NodeVersion = kubecmd.NodeVersion
+	// Semver is a semantic version.
+	Semver = semver.Semver
+	// TerraformLogLevel is the log level of Terraform.
+	TerraformLogLevel = terraform.LogLevel
+
+	// GCPServiceAccountKey is a GCP service account key.
+	GCPServiceAccountKey = gcpshared.ServiceAccountKey
+	// AzureApplicationCredentials are credentials of an Azure application.
+	AzureApplicationCredentials = azureshared.ApplicationCredentials
+	// OpenStackAccountKey is an OpenStack account key.
+	OpenStackAccountKey = openstack.AccountKey
+)
@@ -0,0 +1,195 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/crypto"
+	"github.com/edgelesssys/constellation/v2/verify/verifyproto"
+)
+
+// VerifyOptions configures the verification of a Constellation node.
+type VerifyOptions struct {
+	// Endpoint is the address of the verification service of the node, in the format "host:port".
+	Endpoint string
+	// Nonce is included in the attestation to guarantee its freshness.
+	// If empty, a random nonce is generated.
+	Nonce []byte
+}
+
+// VerifyOutput is the result of a successful verification.
+type VerifyOutput struct {
+	// AttestationDocument is the raw attestation document of the node.
+	AttestationDocument []byte
+	// Nonce is the nonce the attestation document was issued for.
+	Nonce []byte
+}
+
+// ValidatorLogger logs warnings and information while validating attestation documents.
+type ValidatorLogger interface {
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// NewValidator returns a Validator for the attestation variant and the expected measurements of cfg.
+func NewValidator(cfg AttestationConfig, log ValidatorLogger) (Validator, error) {
+	return choose.Validator(cfg, log)
+}
+
+// Verify fetches an attestation document from the verification service of a node
+// and validates it using the given validator.
+func (a *Applier) Verify(ctx context.Context, validator Validator, opts VerifyOptions) (VerifyOutput, error) {
+	a.reportProgress(PhaseVerifying, false)
+	defer a.reportProgress(PhaseVerifying, true)
+
+	nonce := opts.Nonce
+	if len(nonce) == 0 {
+		var err error
+		nonce, err = crypto.GenerateRandomBytes(32)
+		if err != nil {
+			return VerifyOutput{}, fmt.Errorf("generating nonce: %w", err)
+		}
+	}
+
+	a.log.Debugf("Dialing endpoint: %q", opts.Endpoint)
+	// The verification service doesn't use aTLS: the attestation is validated explicitly below.
+	conn, err := a.newDialer(nil).DialInsecure(ctx, opts.Endpoint)
+	if err != nil {
+		return VerifyOutput{}, fmt.Errorf("dialing verification service: %w", err)
+	}
+	defer conn.Close()
+
+	a.log.Debugf("Sending attestation request")
+	resp, err := verifyproto.NewAPIClient(conn).GetAttestation(ctx, &verifyproto.GetAttestationRequest{Nonce: nonce})
+	if err != nil {
+		return VerifyOutput{}, fmt.Errorf("getting attestation: %w", err)
+	}
+
+	a.log.Debugf("Verifying attestation")
+	signedData, err := validator.Validate(ctx, resp.Attestation, nonce)
+	if err != nil {
+		return VerifyOutput{}, fmt.Errorf("validating attestation: %w", err)
+	}
+	if !bytes.Equal(signedData, []byte(constants.ConstellationVerifyServiceUserData)) {
+		return VerifyOutput{}, errors.New("signed data in attestation does not match expected user data")
+	}
+
+	return VerifyOutput{AttestationDocument: resp.Attestation, Nonce: nonce}, nil
+}
+
+// Measurements returns the SHA256 PCR values quoted in the attestation document, keyed by PCR index.
+// Only TPM based attestation documents are supported.
+func (o VerifyOutput) Measurements() (map[uint32][]byte, error) {
+	var doc vtpm.AttestationDocument
+	if err := json.Unmarshal(o.AttestationDocument, &doc); err != nil {
+		return nil, fmt.Errorf("unmarshalling attestation document: %w", err)
+	}
+	if doc.Attestation == nil {
+		return nil, errors.New("attestation document does not contain a TPM attestation")
+	}
+
+	quoteIdx, err := vtpm.GetSHA256QuoteIndex(doc.Attestation.Quotes)
+	if err != nil {
+		return nil, fmt.Errorf("getting SHA256 quote: %w", err)
+	}
+	return doc.Attestation.Quotes[quoteIdx].Pcrs.Pcrs, nil
+}
+
+// UpdateInitMeasurements sets the owner and cluster measurement values in the attestation config depending on the
+// attestation variant.
+func UpdateInitMeasurements(attConfig config.AttestationCfg, ownerID, clusterID string) error {
+	m := attConfig.GetMeasurements()
+
+	switch attConfig.GetVariant() {
+	case variant.AWSNitroTPM{}, variant.AWSSEVSNP{}, variant.AzureTrustedLaunch{}, variant.AzureSEVSNP{}, variant.GCPSEVES{}, variant.QEMUVTPM{}, variant.QEMUSEVSNPSimulated{}:
+		if err := updateMeasurementTPM(m, uint32(measurements.PCRIndexOwnerID), ownerID); err != nil {
+			return err
+		}
+		return updateMeasurementTPM(m, uint32(measurements.PCRIndexClusterID), clusterID)
+	case variant.QEMUTDX{}, variant.QEMUTDXSimulated{}:
+		// Measuring ownerID is currently not implemented for Constellation
+		// Since adding support for measuring ownerID to TDX would require additional code changes,
+		// the current implementation does not support it, but can be changed if we decide to add support in the future
+		return updateMeasurementTDX(m, uint32(measurements.TDXIndexClusterID), clusterID)
+	default:
+		return errors.New("selecting attestation variant: unknown attestation variant")
+	}
+}
+
+// updateMeasurementTDX updates the TDX measurement value in the attestation config for the given measurement index.
+func updateMeasurementTDX(m measurements.M, measurementIdx uint32, encoded string) error {
+	if encoded == "" {
+		delete(m, measurementIdx)
+		return nil
+	}
+	decoded, err := decodeMeasurement(encoded)
+	if err != nil {
+		return err
+	}
+
+	// new_measurement_value := hash(old_measurement_value || data_to_extend)
+	// Since we use the DG.MR.RTMR.EXTEND call to extend the register, data_to_extend is the hash of our input
+	hashedInput := sha512.Sum384(decoded)
+	oldExpected := m[measurementIdx].Expected
+	expectedMeasurementSum := sha512.Sum384(append(oldExpected[:], hashedInput[:]...))
+	m[measurementIdx] = measurements.Measurement{
+		Expected:      expectedMeasurementSum[:],
+		ValidationOpt: m[measurementIdx].ValidationOpt,
+	}
+	return nil
+}
+
+// updateMeasurementTPM updates the TPM measurement value in the attestation config for the given measurement index.
+func updateMeasurementTPM(m measurements.M, measurementIdx uint32, encoded string) error {
+	if encoded == "" {
+		delete(m, measurementIdx)
+		return nil
+	}
+	decoded, err := decodeMeasurement(encoded)
+	if err != nil {
+		return err
+	}
+
+	// new_pcr_value := hash(old_pcr_value || data_to_extend)
+	// Since we use the TPM2_PCR_Event call to extend the PCR, data_to_extend is the hash of our input
+	hashedInput := sha256.Sum256(decoded)
+	oldExpected := m[measurementIdx].Expected
+	expectedMeasurement := sha256.Sum256(append(oldExpected[:], hashedInput[:]...))
+	m[measurementIdx] = measurements.Measurement{
+		Expected:      expectedMeasurement[:],
+		ValidationOpt: m[measurementIdx].ValidationOpt,
+	}
+	return nil
+}
+
+// decodeMeasurement is a utility function that decodes the given string as hex or base64.
+func decodeMeasurement(encoded string) ([]byte, error) {
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil {
+		hexErr := err
+		decoded, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("input [%s] could neither be hex decoded (%w) nor base64 decoded (%w)", encoded, hexErr, err)
+		}
+	}
+	return decoded, nil
+}
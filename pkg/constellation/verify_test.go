@@ -0,0 +1,393 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package constellation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
+	"github.com/edgelesssys/constellation/v2/internal/grpc/testdialer"
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/verify/verifyproto"
+	"github.com/google/go-tpm-tools/proto/attest"
+	tpmProto "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestVerify(t *testing.T) {
+	testCases := map[string]struct {
+		attestationDoc atls.FakeAttestationDoc
+		nonce          []byte
+		attestationErr error
+		wantErr        bool
+	}{
+		"success": {
+			attestationDoc: atls.FakeAttestationDoc{
+				UserData: []byte(constants.ConstellationVerifyServiceUserData),
+				Nonce:    []byte("nonce"),
+			},
+			nonce: []byte("nonce"),
+		},
+		"attestation error": {
+			attestationDoc: atls.FakeAttestationDoc{
+				UserData: []byte(constants.ConstellationVerifyServiceUserData),
+				Nonce:    []byte("nonce"),
+			},
+			nonce:          []byte("nonce"),
+			attestationErr: errors.New("error"),
+			wantErr:        true,
+		},
+		"user data does not match": {
+			attestationDoc: atls.FakeAttestationDoc{
+				UserData: []byte("wrong user data"),
+				Nonce:    []byte("nonce"),
+			},
+			nonce:   []byte("nonce"),
+			wantErr: true,
+		},
+		"nonce does not match": {
+			attestationDoc: atls.FakeAttestationDoc{
+				UserData: []byte(constants.ConstellationVerifyServiceUserData),
+				Nonce:    []byte("wrong nonce"),
+			},
+			nonce:   []byte("nonce"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			attestation, err := json.Marshal(tc.attestationDoc)
+			require.NoError(err)
+			verifyAPI := &stubVerifyAPI{
+				attestation:    &verifyproto.GetAttestationResponse{Attestation: attestation},
+				attestationErr: tc.attestationErr,
+			}
+
+			netDialer := testdialer.NewBufconnDialer()
+			verifyDialer := dialer.New(nil, nil, netDialer)
+			verifyServer := grpc.NewServer()
+			verifyproto.RegisterAPIServer(verifyServer, verifyAPI)
+
+			addr := net.JoinHostPort("192.0.2.1", strconv.Itoa(constants.VerifyServiceNodePortGRPC))
+			listener := netDialer.GetListener(addr)
+			go verifyServer.Serve(listener)
+			defer verifyServer.GracefulStop()
+
+			a := &Applier{
+				log:       logger.NewTest(t),
+				newDialer: func(Validator) Dialer { return verifyDialer },
+			}
+
+			out, err := a.Verify(context.Background(), atls.NewFakeValidator(variant.Dummy{}), VerifyOptions{
+				Endpoint: addr,
+				Nonce:    tc.nonce,
+			})
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(attestation, out.AttestationDocument)
+			assert.Equal(tc.nonce, out.Nonce)
+		})
+	}
+}
+
+type stubVerifyAPI struct {
+	attestation    *verifyproto.GetAttestationResponse
+	attestationErr error
+	verifyproto.UnimplementedAPIServer
+}
+
+func (a stubVerifyAPI) GetAttestation(context.Context, *verifyproto.GetAttestationRequest) (*verifyproto.GetAttestationResponse, error) {
+	return a.attestation, a.attestationErr
+}
+
+func TestVerifyOutputMeasurements(t *testing.T) {
+	newDoc := func(quotes ...*tpmProto.Quote) []byte {
+		doc, err := json.Marshal(vtpm.AttestationDocument{Attestation: &attest.Attestation{Quotes: quotes}})
+		require.NoError(t, err)
+		return doc
+	}
+	sha256PCRs := map[uint32][]byte{
+		0:  {0x00, 0x01},
+		15: {0x0f, 0x0f},
+	}
+
+	testCases := map[string]struct {
+		doc     []byte
+		want    map[uint32][]byte
+		wantErr bool
+	}{
+		"success": {
+			doc: newDoc(
+				&tpmProto.Quote{Pcrs: &tpmProto.PCRs{Hash: tpmProto.HashAlgo_SHA1, Pcrs: map[uint32][]byte{0: {0xff}}}},
+				&tpmProto.Quote{Pcrs: &tpmProto.PCRs{Hash: tpmProto.HashAlgo_SHA256, Pcrs: sha256PCRs}},
+			),
+			want: sha256PCRs,
+		},
+		"no SHA256 quote": {
+			doc: newDoc(
+				&tpmProto.Quote{Pcrs: &tpmProto.PCRs{Hash: tpmProto.HashAlgo_SHA1, Pcrs: map[uint32][]byte{0: {0xff}}}},
+			),
+			wantErr: true,
+		},
+		"no quotes": {
+			doc:     newDoc(),
+			wantErr: true,
+		},
+		"no TPM attestation": {
+			doc:     []byte(`{"UserData":"dGVzdA=="}`),
+			wantErr: true,
+		},
+		"invalid document": {
+			doc:     []byte("invalid"),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := VerifyOutput{AttestationDocument: tc.doc}.Measurements()
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.want, got)
+		})
+	}
+}
+
+func TestValidatorUpdateInitPCRs(t *testing.T) {
+	zero := measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength)
+	one := measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength)
+	one64 := base64.StdEncoding.EncodeToString(one.Expected[:])
+	oneHash := sha256.Sum256(one.Expected[:])
+	pcrZeroUpdatedOne := sha256.Sum256(append(zero.Expected[:], oneHash[:]...))
+	newTestPCRs := func() measurements.M {
+		return measurements.M{
+			0:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			1:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			2:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			3:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			4:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			5:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			6:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			7:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			8:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			9:  measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			10: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			11: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			12: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			13: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			14: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			15: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			16: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			17: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			18: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			19: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			20: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			21: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			22: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength),
+			23: measurements.WithAllBytes(0x00, measurements.WarnOnly, measurements.PCRMeasurementLength),
+		}
+	}
+
+	testCases := map[string]struct {
+		config    config.AttestationCfg
+		ownerID   string
+		clusterID string
+		wantErr   bool
+	}{
+		"gcp update owner ID": {
+			config: &config.GCPSEVES{
+				Measurements: newTestPCRs(),
+			},
+			ownerID: one64,
+		},
+		"gcp update cluster ID": {
+			config: &config.GCPSEVES{
+				Measurements: newTestPCRs(),
+			},
+			clusterID: one64,
+		},
+		"gcp update both": {
+			config: &config.GCPSEVES{
+				Measurements: newTestPCRs(),
+			},
+			ownerID:   one64,
+			clusterID: one64,
+		},
+		"azure update owner ID": {
+			config: &config.AzureSEVSNP{
+				Measurements: newTestPCRs(),
+			},
+			ownerID: one64,
+		},
+		"azure update cluster ID": {
+			config: &config.AzureSEVSNP{
+				Measurements: newTestPCRs(),
+			},
+			clusterID: one64,
+		},
+		"azure update both": {
+			config: &config.AzureSEVSNP{
+				Measurements: newTestPCRs(),
+			},
+			ownerID:   one64,
+			clusterID: one64,
+		},
+		"owner ID and cluster ID empty": {
+			config: &config.AzureSEVSNP{
+				Measurements: newTestPCRs(),
+			},
+		},
+		"invalid encoding": {
+			config: &config.GCPSEVES{
+				Measurements: newTestPCRs(),
+			},
+			ownerID: "invalid",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := UpdateInitMeasurements(tc.config, tc.ownerID, tc.clusterID)
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(t, err)
+			m := tc.config.GetMeasurements()
+			for i := 0; i < len(m); i++ {
+				switch {
+				case i == int(measurements.PCRIndexClusterID) && tc.clusterID == "":
+					// should be deleted
+					_, ok := m[uint32(i)]
+					assert.False(ok)
+
+				case i == int(measurements.PCRIndexClusterID):
+					pcr, ok := m[uint32(i)]
+					assert.True(ok)
+					assert.Equal(pcrZeroUpdatedOne[:], pcr.Expected)
+
+				case i == int(measurements.PCRIndexOwnerID) && tc.ownerID == "":
+					// should be deleted
+					_, ok := m[uint32(i)]
+					assert.False(ok)
+
+				case i == int(measurements.PCRIndexOwnerID):
+					pcr, ok := m[uint32(i)]
+					assert.True(ok)
+					assert.Equal(pcrZeroUpdatedOne[:], pcr.Expected)
+
+				default:
+					if i >= 17 && i <= 22 {
+						assert.Equal(one, m[uint32(i)])
+					} else {
+						assert.Equal(zero, m[uint32(i)])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorUpdateInitMeasurementsTDX(t *testing.T) {
+	zero := measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength)
+	one := measurements.WithAllBytes(0x11, true, measurements.TDXMeasurementLength)
+	one64 := base64.StdEncoding.EncodeToString(one.Expected[:])
+	oneHash := sha512.Sum384(one.Expected[:])
+	tdxZeroUpdatedOne := sha512.Sum384(append(zero.Expected[:], oneHash[:]...))
+	newTestTDXMeasurements := func() measurements.M {
+		return measurements.M{
+			0: measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength),
+			1: measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength),
+			2: measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength),
+			3: measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength),
+			4: measurements.WithAllBytes(0x00, true, measurements.TDXMeasurementLength),
+		}
+	}
+
+	testCases := map[string]struct {
+		measurements measurements.M
+		clusterID    string
+		wantErr      bool
+	}{
+		"QEMUT TDX update update cluster ID": {
+			measurements: newTestTDXMeasurements(),
+			clusterID:    one64,
+		},
+		"cluster ID empty": {
+			measurements: newTestTDXMeasurements(),
+		},
+		"invalid encoding": {
+			measurements: newTestTDXMeasurements(),
+			clusterID:    "invalid",
+			wantErr:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cfg := &config.QEMUTDX{Measurements: tc.measurements}
+
+			err := UpdateInitMeasurements(cfg, "", tc.clusterID)
+
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			for i := 0; i < len(tc.measurements); i++ {
+				switch {
+				case i == measurements.TDXIndexClusterID && tc.clusterID == "":
+					// should be deleted
+					_, ok := cfg.Measurements[uint32(i)]
+					assert.False(ok)
+
+				case i == measurements.TDXIndexClusterID:
+					pcr, ok := cfg.Measurements[uint32(i)]
+					assert.True(ok)
+					assert.Equal(tdxZeroUpdatedOne[:], pcr.Expected)
+
+				default:
+					assert.Equal(zero, cfg.Measurements[uint32(i)])
+				}
+			}
+		})
+	}
+}
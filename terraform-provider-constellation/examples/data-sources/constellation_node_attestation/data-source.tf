@@ -0,0 +1,10 @@
+data "constellation_attestation" "foo" {} # Fill accordingly for the CSP and attestation variant
+
+resource "constellation_cluster" "foo" {} # Fill accordingly for the CSP
+
+data "constellation_node_attestation" "example" {
+  endpoints   = [constellation_cluster.foo.out_of_cluster_endpoint]
+  attestation = data.constellation_attestation.foo.attestation
+  owner_id    = constellation_cluster.foo.owner_id
+  cluster_id  = constellation_cluster.foo.cluster_id
+}
@@ -0,0 +1 @@
+terraform import constellation_iam.constellation_iam constellation-iam://?csp=<csp>&workspace=<path-to-iam-workspace>
@@ -0,0 +1,10 @@
+resource "constellation_iam" "example" {
+  workspace = "constellation-iam"
+  csp       = "gcp"
+  gcp = {
+    project_id         = "constellation-331613"
+    service_account_id = "constell-sa"
+    region             = "europe-west3"
+    zone               = "europe-west3-b"
+  }
+}
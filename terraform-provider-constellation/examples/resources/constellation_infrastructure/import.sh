@@ -0,0 +1 @@
+terraform import constellation_infrastructure.constellation_infrastructure constellation-infrastructure://?csp=<csp>&workspace=<path-to-infrastructure-workspace>
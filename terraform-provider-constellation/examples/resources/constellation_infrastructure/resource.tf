@@ -0,0 +1,47 @@
+data "constellation_image" "bar" {} # Fill accordingly for the CSP
+
+resource "constellation_iam" "foo" {} # Fill accordingly for the CSP
+
+resource "constellation_infrastructure" "example" {
+  workspace           = "constellation-infrastructure"
+  csp                 = "gcp"
+  name                = "constell"
+  image               = data.constellation_image.bar.image
+  attestation_variant = "gcp-sev-es"
+  node_groups = {
+    control_plane_default = {
+      role          = "control-plane"
+      initial_count = 3
+    }
+    worker_default = {
+      role          = "worker"
+      initial_count = 2
+    }
+  }
+  gcp = {
+    project_id = "constellation-331613"
+    region     = "europe-west3"
+    zone       = "europe-west3-b"
+  }
+}
+
+resource "constellation_cluster" "baz" {
+  csp                     = "gcp"
+  name                    = constellation_infrastructure.example.cluster_name
+  uid                     = constellation_infrastructure.example.uid
+  image                   = data.constellation_image.bar.image
+  init_secret             = constellation_infrastructure.example.init_secret
+  out_of_cluster_endpoint = constellation_infrastructure.example.out_of_cluster_endpoint
+  in_cluster_endpoint     = constellation_infrastructure.example.in_cluster_endpoint
+  api_server_cert_sans    = constellation_infrastructure.example.api_server_cert_sans
+  gcp = {
+    project_id          = "constellation-331613"
+    service_account_key = constellation_iam.foo.service_account_key
+  }
+  network_config = {
+    ip_cidr_node    = constellation_infrastructure.example.ip_cidr_node
+    ip_cidr_service = "10.96.0.0/12"
+    ip_cidr_pod     = constellation_infrastructure.example.ip_cidr_pod
+  }
+  # ...
+}
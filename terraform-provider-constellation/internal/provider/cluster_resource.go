@@ -7,17 +7,15 @@ SPDX-License-Identifier: AGPL-3.0-only
 package provider
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,18 +24,18 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/azureshared"
 	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/openstack"
 	"github.com/edgelesssys/constellation/v2/internal/compatibility"
 	"github.com/edgelesssys/constellation/v2/internal/config"
 	"github.com/edgelesssys/constellation/v2/internal/constants"
-	"github.com/edgelesssys/constellation/v2/internal/constellation"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/helm"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/kubecmd"
 	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
-	"github.com/edgelesssys/constellation/v2/internal/grpc/dialer"
 	"github.com/edgelesssys/constellation/v2/internal/kms/uri"
 	"github.com/edgelesssys/constellation/v2/internal/license"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
 	datastruct "github.com/edgelesssys/constellation/v2/terraform-provider-constellation/internal/data"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -96,6 +94,7 @@ type ClusterResourceModel struct {
 	Attestation          types.Object `tfsdk:"attestation"`
 	GCP                  types.Object `tfsdk:"gcp"`
 	Azure                types.Object `tfsdk:"azure"`
+	OpenStack            types.Object `tfsdk:"openstack"`
 
 	OwnerID    types.String `tfsdk:"owner_id"`
 	ClusterID  types.String `tfsdk:"cluster_id"`
@@ -129,6 +128,22 @@ type azureAttribute struct {
 	LoadBalancerName         string `tfsdk:"load_balancer_name"`
 }
 
+// openStackAttribute is the openstack attribute's data model.
+type openStackAttribute struct {
+	AuthURL                 string `tfsdk:"auth_url"`
+	Username                string `tfsdk:"username"`
+	Password                string `tfsdk:"password"`
+	ProjectID               string `tfsdk:"project_id"`
+	ProjectName             string `tfsdk:"project_name"`
+	UserDomainName          string `tfsdk:"user_domain_name"`
+	ProjectDomainName       string `tfsdk:"project_domain_name"`
+	RegionName              string `tfsdk:"region_name"`
+	FloatingIPPoolID        string `tfsdk:"floating_ip_pool_id"`
+	DeployYawolLoadBalancer bool   `tfsdk:"deploy_yawol_load_balancer"`
+	YawolImageID            string `tfsdk:"yawol_image_id"`
+	YawolFlavorID           string `tfsdk:"yawol_flavor_id"`
+}
+
 // extraMicroservicesAttribute is the extra microservices attribute's data model.
 type extraMicroservicesAttribute struct {
 	CSIDriver bool `tfsdk:"csi_driver"`
@@ -336,6 +351,74 @@ func (r *ClusterResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					},
 				},
 			},
+			"openstack": schema.SingleNestedAttribute{
+				MarkdownDescription: "OpenStack-specific configuration. Also used for STACKIT.",
+				Description:         "OpenStack-specific configuration. Also used for STACKIT.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"auth_url": schema.StringAttribute{
+						MarkdownDescription: "URL of the OpenStack identity service (Keystone).",
+						Description:         "URL of the OpenStack identity service (Keystone).",
+						Required:            true,
+					},
+					"username": schema.StringAttribute{
+						MarkdownDescription: "Name of the OpenStack user used within the cluster.",
+						Description:         "Name of the OpenStack user used within the cluster.",
+						Required:            true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "Password of the OpenStack user used within the cluster.",
+						Description:         "Password of the OpenStack user used within the cluster.",
+						Required:            true,
+						Sensitive:           true,
+					},
+					"project_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the OpenStack project the cluster resides in.",
+						Description:         "ID of the OpenStack project the cluster resides in.",
+						Required:            true,
+					},
+					"project_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the OpenStack project the cluster resides in.",
+						Description:         "Name of the OpenStack project the cluster resides in.",
+						Required:            true,
+					},
+					"user_domain_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the OpenStack domain the user belongs to.",
+						Description:         "Name of the OpenStack domain the user belongs to.",
+						Required:            true,
+					},
+					"project_domain_name": schema.StringAttribute{
+						MarkdownDescription: "Name of the OpenStack domain the project belongs to.",
+						Description:         "Name of the OpenStack domain the project belongs to.",
+						Required:            true,
+					},
+					"region_name": schema.StringAttribute{
+						MarkdownDescription: "OpenStack region the cluster resides in.",
+						Description:         "OpenStack region the cluster resides in.",
+						Required:            true,
+					},
+					"floating_ip_pool_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the floating IP pool used by the cluster's load balancers.",
+						Description:         "ID of the floating IP pool used by the cluster's load balancers.",
+						Required:            true,
+					},
+					"deploy_yawol_load_balancer": schema.BoolAttribute{
+						MarkdownDescription: "Whether to deploy the [yawol](https://github.com/stackitcloud/yawol) load balancer. Only supported on STACKIT.",
+						Description:         "Whether to deploy the yawol load balancer. Only supported on STACKIT.",
+						Optional:            true,
+					},
+					"yawol_image_id": schema.StringAttribute{
+						MarkdownDescription: "OpenStack image ID used by the yawol load balancer. Only required if `deploy_yawol_load_balancer` is set.",
+						Description:         "OpenStack image ID used by the yawol load balancer. Only required if deploy_yawol_load_balancer is set.",
+						Optional:            true,
+					},
+					"yawol_flavor_id": schema.StringAttribute{
+						MarkdownDescription: "OpenStack flavor ID used by the yawol load balancer. Only required if `deploy_yawol_load_balancer` is set.",
+						Description:         "OpenStack flavor ID used by the yawol load balancer. Only required if deploy_yawol_load_balancer is set.",
+						Optional:            true,
+					},
+				},
+			},
 
 			// Computed (output) attributes
 			"owner_id": schema.StringAttribute{
@@ -409,6 +492,37 @@ func (r *ClusterResource) ValidateConfig(ctx context.Context, req resource.Valid
 			"GCP configuration not allowed", "When csp is not set to 'gcp', setting the 'gcp' configuration has no effect.",
 		)
 	}
+
+	// OpenStack Config is required for OpenStack and STACKIT
+	isOpenStack := cloudprovider.FromString(data.CSP.ValueString()) == cloudprovider.OpenStack
+	if isOpenStack && data.OpenStack.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("openstack"),
+			"OpenStack configuration missing", "When csp is set to 'openstack' or 'stackit', the 'openstack' configuration must be set.",
+		)
+	}
+
+	// OpenStack Config should not be set for other CSPs
+	if !isOpenStack && !data.OpenStack.IsNull() {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("openstack"),
+			"OpenStack configuration not allowed", "When csp is not set to 'openstack' or 'stackit', setting the 'openstack' configuration has no effect.",
+		)
+	}
+
+	// yawol settings are required when deploying the yawol load balancer
+	if !data.OpenStack.IsNull() && !data.OpenStack.IsUnknown() {
+		var openStackConfig openStackAttribute
+		if diags := data.OpenStack.As(ctx, &openStackConfig, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true}); diags.HasError() {
+			return
+		}
+		if openStackConfig.DeployYawolLoadBalancer && (openStackConfig.YawolImageID == "" || openStackConfig.YawolFlavorID == "") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("openstack"),
+				"yawol configuration missing", "When 'deploy_yawol_load_balancer' is set, 'yawol_image_id' and 'yawol_flavor_id' must be set.",
+			)
+		}
+	}
 }
 
 // Configure configures the resource.
@@ -427,12 +541,8 @@ func (r *ClusterResource) Configure(_ context.Context, req resource.ConfigureReq
 		return
 	}
 
-	newDialer := func(validator atls.Validator) *dialer.Dialer {
-		return dialer.New(nil, validator, &net.Dialer{})
-	}
-
 	r.newApplier = func(ctx context.Context, validator atls.Validator) *constellation.Applier {
-		return constellation.NewApplier(&tfContextLogger{ctx: ctx}, &nopSpinner{}, constellation.ApplyContextTerraform, newDialer)
+		return constellation.NewApplier(&tfContextLogger{ctx: ctx}, tfProgressLogger(ctx), constellation.ApplyContextTerraform, constellation.DefaultDialer)
 	}
 }
 
@@ -470,9 +580,14 @@ func (r *ClusterResource) ModifyPlan(ctx context.Context, req resource.ModifyPla
 		}
 
 		// Warn the user about possibly destructive changes in case microservice changes are to be applied.
-		currVer, diags := r.getMicroserviceVersion(&currentState)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
+		// The current version might have been read from the cluster by drift detection,
+		// so it isn't necessarily compatible with the provider.
+		currVer, err := semver.New(currentState.MicroserviceVersion.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("constellation_microservice_version"),
+				"Invalid microservice version",
+				fmt.Sprintf("Parsing microservice version: %s", err))
 			return
 		}
 
@@ -520,15 +635,12 @@ func (r *ClusterResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	// All Calls to the Constellation API are idempotent, thus we don't need to implement reading.
-
-	// Alternatively, we could:
-
-	// Retrieve more up-to-date data from the cluster. e.g.:
-	// - CSI Driver enabled?
-	// - Kubernetes version?
-	// - Microservice version?
-	// - Attestation Config?
+	// Compare the state against the running cluster, so that manual changes to the cluster
+	// show up as a difference in the plan and are corrected by the next apply.
+	resp.Diagnostics.Append(r.detectDrift(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -753,6 +865,7 @@ func (r *ClusterResource) apply(ctx context.Context, data *ClusterResourceModel,
 	serviceAccPayload := constellation.ServiceAccountPayload{}
 	var gcpConfig gcpAttribute
 	var azureConfig azureAttribute
+	var openStackConfig openStackAttribute
 	switch csp {
 	case cloudprovider.GCP:
 		convertDiags = data.GCP.As(ctx, &gcpConfig, basetypes.ObjectAsOptions{})
@@ -789,6 +902,22 @@ func (r *ClusterResource) apply(ctx context.Context, data *ClusterResourceModel,
 			PreferredAuthMethod: azureshared.AuthMethodUserAssignedIdentity,
 			UamiResourceID:      azureConfig.UamiResourceID,
 		}
+	case cloudprovider.OpenStack:
+		convertDiags = data.OpenStack.As(ctx, &openStackConfig, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true}) // yawol settings are optional
+		diags.Append(convertDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		serviceAccPayload.OpenStack = openstack.AccountKey{
+			AuthURL:           openStackConfig.AuthURL,
+			Username:          openStackConfig.Username,
+			Password:          openStackConfig.Password,
+			ProjectID:         openStackConfig.ProjectID,
+			ProjectName:       openStackConfig.ProjectName,
+			UserDomainName:    openStackConfig.UserDomainName,
+			ProjectDomainName: openStackConfig.ProjectDomainName,
+			RegionName:        openStackConfig.RegionName,
+		}
 	}
 	serviceAccURI, err := constellation.MarshalServiceAccountURI(csp, serviceAccPayload)
 	if err != nil {
@@ -911,6 +1040,14 @@ func (r *ClusterResource) apply(ctx context.Context, data *ClusterResourceModel,
 		masterSecret:        secrets.masterSecret,
 		serviceAccURI:       serviceAccURI,
 	}
+	if csp == cloudprovider.OpenStack {
+		payload.openStackCfg = &config.OpenStackConfig{
+			FloatingIPPoolID:        openStackConfig.FloatingIPPoolID,
+			DeployYawolLoadBalancer: &openStackConfig.DeployYawolLoadBalancer,
+			YawolImageID:            openStackConfig.YawolImageID,
+			YawolFlavorID:           openStackConfig.YawolFlavorID,
+		}
+	}
 	helmDiags := r.applyHelmCharts(ctx, applier, payload, stateFile)
 	diags.Append(helmDiags...)
 	if diags.HasError() {
@@ -918,20 +1055,25 @@ func (r *ClusterResource) apply(ctx context.Context, data *ClusterResourceModel,
 	}
 
 	if !skipNodeUpgrade {
-		// Upgrade node image
-		err = applier.UpgradeNodeImage(ctx,
-			imageSemver,
-			image.Reference,
-			false)
-		var upgradeImageErr *compatibility.InvalidUpgradeError
-		switch {
-		case errors.Is(err, kubecmd.ErrInProgress):
-			diags.AddWarning("Skipping OS image upgrade", "Another upgrade is already in progress.")
-		case errors.As(err, &upgradeImageErr):
-			diags.AddWarning("Ignoring invalid OS image upgrade", err.Error())
-		case err != nil:
-			diags.AddError("Upgrading OS image", err.Error())
-			return diags
+		// Constellation does not support image upgrades on all CSPs. Not supported are: QEMU, OpenStack
+		if csp == cloudprovider.AWS || csp == cloudprovider.Azure || csp == cloudprovider.GCP {
+			// Upgrade node image
+			err = applier.UpgradeNodeImage(ctx,
+				imageSemver,
+				image.Reference,
+				false)
+			var upgradeImageErr *compatibility.InvalidUpgradeError
+			switch {
+			case errors.Is(err, kubecmd.ErrInProgress):
+				diags.AddWarning("Skipping OS image upgrade", "Another upgrade is already in progress.")
+			case errors.As(err, &upgradeImageErr):
+				diags.AddWarning("Ignoring invalid OS image upgrade", err.Error())
+			case err != nil:
+				diags.AddError("Upgrading OS image", err.Error())
+				return diags
+			}
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("Image upgrades are not supported for provider %s, skipping OS image upgrade", csp))
 		}
 
 		// Upgrade Kubernetes components
@@ -993,15 +1135,16 @@ func (r *ClusterResource) runInitRPC(ctx context.Context, applier *constellation
 	data *ClusterResourceModel, validator atls.Validator, stateFile *state.State,
 ) diag.Diagnostics {
 	diags := diag.Diagnostics{}
-	clusterLogs := &bytes.Buffer{}
 	initOutput, err := applier.Init(
-		ctx, validator, stateFile, clusterLogs,
+		ctx, validator, stateFile,
 		constellation.InitPayload{
-			MasterSecret:    payload.masterSecret,
-			MeasurementSalt: payload.measurementSalt,
-			K8sVersion:      payload.k8sVersion,
-			ConformanceMode: false, // Conformance mode does't need to be configurable through the TF provider for now.
-			ServiceCIDR:     payload.networkCfg.IPCidrService.ValueString(),
+			MasterSecret:          payload.masterSecret,
+			MeasurementSalt:       payload.measurementSalt,
+			K8sVersion:            payload.k8sVersion,
+			ConformanceMode:       false, // Conformance mode does't need to be configurable through the TF provider for now.
+			ServiceCIDR:           payload.networkCfg.IPCidrService.ValueString(),
+			KubernetesOverrides:   nil, // Kubernetes configuration overrides aren't configurable through the TF provider for now.
+			DiskEncryptionProfile: "",  // Disk encryption profiles aren't configurable through the TF provider for now. The default profile is used.
 		})
 	if err != nil {
 		var nonRetriable *constellation.NonRetriableInitError
@@ -1012,7 +1155,7 @@ func (r *ClusterResource) runInitRPC(ctx context.Context, applier *constellation
 				diags.AddError("Bootstrapper log collection failed.",
 					fmt.Sprintf("Failed to collect logs from bootstrapper: %s\n", nonRetriable.LogCollectionErr))
 			} else {
-				diags.AddWarning("Cluster log collection succeeded.", clusterLogs.String())
+				diags.AddWarning("Cluster log collection succeeded.", string(nonRetriable.ClusterLogs))
 			}
 		} else {
 			diags.AddError("Cluster initialization failed.", fmt.Sprintf("You might try to apply the resource again.\nError: %s", err))
@@ -1037,6 +1180,7 @@ type applyHelmChartsPayload struct {
 	DeployCSIDriver     bool                     // Whether to deploy the CSI driver.
 	masterSecret        uri.MasterSecret         // master secret of the cluster.
 	serviceAccURI       string                   // URI of the service account used within the cluster.
+	openStackCfg        *config.OpenStackConfig  // OpenStack-specific configuration. Only used for OpenStack clusters.
 }
 
 // applyHelmCharts applies the Helm charts to the cluster.
@@ -1060,7 +1204,7 @@ func (r *ClusterResource) applyHelmCharts(ctx context.Context, applier *constell
 	}
 
 	executor, _, err := applier.PrepareHelmCharts(options, state,
-		payload.serviceAccURI, payload.masterSecret, nil)
+		payload.serviceAccURI, payload.masterSecret, payload.openStackCfg)
 	var upgradeErr *compatibility.InvalidUpgradeError
 	if err != nil {
 		if !errors.As(err, &upgradeErr) {
@@ -1215,6 +1359,238 @@ func (r *ClusterResource) getAPIServerCertSANs(ctx context.Context, data *Cluste
 	return apiServerCertSANs, diags
 }
 
+// clusterStateReader reads the state of a running cluster.
+type clusterStateReader interface {
+	GetConstellationVersion(ctx context.Context) (kubecmd.NodeVersion, error)
+	GetClusterAttestationConfig(ctx context.Context, variant variant.Variant) (config.AttestationCfg, error)
+	GetHelmReleaseVersions() (constellation.HelmReleaseVersions, error)
+	MissingClusterConfigCertSANs(ctx context.Context, clusterEndpoint, customEndpoint string, additionalAPIServerCertSANs []string) ([]string, error)
+}
+
+// liveClusterState groups the values read from a running cluster that are checked for drift.
+type liveClusterState struct {
+	imageVersion           string
+	imageReference         string
+	kubernetesVersion      string
+	constellationServices  semver.Semver
+	constellationOperators semver.Semver
+	attestation            config.AttestationCfg // nil if the attestation config wasn't read.
+	missingCertSANs        []string
+}
+
+// detectDrift reads the running cluster and updates the values in data that no longer match the cluster.
+// Not being able to reach the cluster only results in a warning, since the state is also refreshed
+// before the cluster is destroyed, when the cluster might already be gone.
+func (r *ClusterResource) detectDrift(ctx context.Context, data *ClusterResourceModel) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	if data.KubeConfig.ValueString() == "" {
+		// Nothing to compare against yet, e.g. while importing a cluster.
+		return diags
+	}
+
+	var attestationVariant variant.Variant
+	if !data.Attestation.IsNull() {
+		att, convertDiags := r.convertAttestationConfig(ctx, *data)
+		diags.Append(convertDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		attestationVariant = att.variant
+	}
+
+	apiServerCertSANs, convertDiags := r.getAPIServerCertSANs(ctx, data)
+	diags.Append(convertDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	applier := r.newApplier(ctx, nil)
+	if err := applier.SetKubeConfig([]byte(data.KubeConfig.ValueString())); err != nil {
+		diags.AddWarning("Skipping drift detection", fmt.Sprintf("Setting kubeconfig: %s", err))
+		return diags
+	}
+
+	live, err := readLiveClusterState(ctx, applier, attestationVariant, data.OutOfClusterEndpoint.ValueString(), apiServerCertSANs)
+	if err != nil {
+		diags.AddWarning("Skipping drift detection", fmt.Sprintf("Reading cluster state: %s", err))
+		return diags
+	}
+
+	diags.Append(r.setClusterDrift(ctx, data, live)...)
+	return diags
+}
+
+// readLiveClusterState reads the values checked for drift from the cluster.
+// The attestation config is only read if attestationVariant is not nil.
+func readLiveClusterState(ctx context.Context, reader clusterStateReader, attestationVariant variant.Variant,
+	clusterEndpoint string, apiServerCertSANs []string,
+) (liveClusterState, error) {
+	nodeVersion, err := reader.GetConstellationVersion(ctx)
+	if err != nil {
+		return liveClusterState{}, fmt.Errorf("getting cluster version: %w", err)
+	}
+
+	serviceVersions, err := reader.GetHelmReleaseVersions()
+	if err != nil {
+		return liveClusterState{}, err
+	}
+
+	missingCertSANs, err := reader.MissingClusterConfigCertSANs(ctx, clusterEndpoint, "", apiServerCertSANs)
+	if err != nil {
+		return liveClusterState{}, err
+	}
+
+	live := liveClusterState{
+		imageVersion:           nodeVersion.ImageVersion(),
+		imageReference:         nodeVersion.ImageReference(),
+		kubernetesVersion:      nodeVersion.KubernetesVersion(),
+		constellationServices:  serviceVersions.ConstellationServices,
+		constellationOperators: serviceVersions.ConstellationOperators,
+		missingCertSANs:        missingCertSANs,
+	}
+
+	if attestationVariant != nil {
+		live.attestation, err = reader.GetClusterAttestationConfig(ctx, attestationVariant)
+		if err != nil {
+			return liveClusterState{}, fmt.Errorf("getting attestation config: %w", err)
+		}
+	}
+
+	return live, nil
+}
+
+// setClusterDrift overwrites the values in data that differ from the live cluster state,
+// and adds a warning for every drifted value.
+func (r *ClusterResource) setClusterDrift(ctx context.Context, data *ClusterResourceModel, live liveClusterState) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+
+	// Kubernetes version
+	if live.kubernetesVersion != "" && data.KubernetesVersion.ValueString() != live.kubernetesVersion {
+		if !data.KubernetesVersion.IsNull() {
+			diags.AddWarning("Kubernetes version drift detected",
+				fmt.Sprintf("The cluster runs Kubernetes %s instead of %s.", live.kubernetesVersion, data.KubernetesVersion.ValueString()))
+		}
+		data.KubernetesVersion = types.StringValue(live.kubernetesVersion)
+	}
+
+	// Microservice version
+	// All Constellation-versioned Helm releases are expected to be at the microservice version.
+	for _, releaseVersion := range []semver.Semver{live.constellationServices, live.constellationOperators} {
+		if releaseVersion == (semver.Semver{}) {
+			continue
+		}
+		if microserviceVersion, err := semver.New(data.MicroserviceVersion.ValueString()); err == nil && microserviceVersion.Compare(releaseVersion) == 0 {
+			continue
+		}
+		if !data.MicroserviceVersion.IsNull() {
+			diags.AddWarning("Microservice version drift detected",
+				fmt.Sprintf("The cluster's Helm releases are at version %s instead of %s.", releaseVersion, data.MicroserviceVersion.ValueString()))
+		}
+		data.MicroserviceVersion = types.StringValue(releaseVersion.String())
+		break
+	}
+
+	// OS image
+	// Constellation does not support image upgrades on all CSPs, so image drift can't be corrected on QEMU and OpenStack.
+	csp := cloudprovider.FromString(data.CSP.ValueString())
+	if !data.Image.IsNull() && (csp == cloudprovider.AWS || csp == cloudprovider.Azure || csp == cloudprovider.GCP) {
+		var image imageAttribute
+		convertDiags := data.Image.As(ctx, &image, basetypes.ObjectAsOptions{})
+		diags.Append(convertDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		liveImageVersion, err := semver.New(live.imageVersion)
+		imageVersion, imageErr := semver.New(image.Version)
+		if err == nil && (imageErr != nil || imageVersion.Compare(liveImageVersion) != 0) {
+			diags.AddWarning("OS image drift detected",
+				fmt.Sprintf("The cluster targets OS image %s instead of %s.", live.imageVersion, image.Version))
+			image.Version = live.imageVersion
+			image.Reference = live.imageReference
+			data.Image, convertDiags = types.ObjectValueFrom(ctx, data.Image.AttributeTypes(ctx), image)
+			diags.Append(convertDiags...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+	}
+
+	// Attestation config
+	if live.attestation != nil {
+		att, convertDiags := r.convertAttestationConfig(ctx, *data)
+		diags.Append(convertDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		equal, err := att.config.EqualTo(live.attestation)
+		if err != nil {
+			diags.AddError("Comparing attestation config", err.Error())
+			return diags
+		}
+		if !equal {
+			diags.AddWarning("Attestation config drift detected",
+				fmt.Sprintf("The attestation config in the %s ConfigMap differs from the configured one.", constants.JoinConfigMap))
+			attestation, convertDiags := convertToTfAttestationObject(ctx, data.Attestation, live.attestation)
+			diags.Append(convertDiags...)
+			if diags.HasError() {
+				return diags
+			}
+			data.Attestation = attestation
+		}
+	}
+
+	// API server certificate SANs
+	// Missing SANs are removed from the state, so that the next apply adds them again.
+	if len(live.missingCertSANs) > 0 {
+		diags.AddWarning("API server certificate SAN drift detected",
+			fmt.Sprintf("The cluster's API server certificate is missing the following SANs: %s.", strings.Join(live.missingCertSANs, ", ")))
+
+		apiServerCertSANs, convertDiags := r.getAPIServerCertSANs(ctx, data)
+		diags.Append(convertDiags...)
+		if diags.HasError() {
+			return diags
+		}
+
+		var presentSANs []string
+		for _, san := range apiServerCertSANs {
+			if !slices.Contains(live.missingCertSANs, san) {
+				presentSANs = append(presentSANs, san)
+			}
+		}
+		if len(presentSANs) != len(apiServerCertSANs) {
+			data.APIServerCertSANs, convertDiags = types.ListValueFrom(ctx, types.StringType, presentSANs)
+			diags.Append(convertDiags...)
+		}
+	}
+
+	return diags
+}
+
+// convertToTfAttestationObject converts the given attestation config to an attestation object of the same type as current.
+// Attributes that don't apply to the attestation variant keep their current value.
+func convertToTfAttestationObject(ctx context.Context, current types.Object, attestationCfg config.AttestationCfg) (types.Object, diag.Diagnostics) {
+	tfAttestation, err := convertToTfAttestationCfg(attestationCfg)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Converting attestation config", err.Error())
+		return current, diags
+	}
+
+	attestation, diags := types.ObjectValueFrom(ctx, current.AttributeTypes(ctx), tfAttestation)
+	if diags.HasError() {
+		return current, diags
+	}
+	if attestationCfg.GetVariant().Equal(variant.AzureSEVSNP{}) {
+		return attestation, diags
+	}
+
+	attributes := attestation.Attributes()
+	attributes["azure_firmware_signer_config"] = current.Attributes()["azure_firmware_signer_config"]
+	return types.ObjectValue(current.AttributeTypes(ctx), attributes)
+}
+
 // tfContextLogger is a logging adapter between the tflog package and
 // Constellation's logger.
 type tfContextLogger struct {
@@ -1233,8 +1609,23 @@ func (l *tfContextLogger) Warnf(format string, args ...any) {
 	tflog.Warn(l.ctx, fmt.Sprintf(format, args...))
 }
 
-type nopSpinner struct{ io.Writer }
+// tfLogWriter is an io.Writer forwarding everything written to it to the tflog package.
+type tfLogWriter struct {
+	ctx context.Context // bind context to struct to satisfy interface
+}
 
-func (s *nopSpinner) Start(string, bool)              {}
-func (s *nopSpinner) Stop()                           {}
-func (s *nopSpinner) Write([]byte) (n int, err error) { return 1, nil }
+func (w *tfLogWriter) Write(p []byte) (int, error) {
+	tflog.Info(w.ctx, strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+// tfProgressLogger returns a progress callback logging the phases of cluster operations to Terraform.
+func tfProgressLogger(ctx context.Context) constellation.ProgressFunc {
+	return func(event constellation.ProgressEvent) {
+		if event.Done {
+			tflog.Info(ctx, fmt.Sprintf("Finished phase %q", event.Phase))
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("Entered phase %q", event.Phase))
+	}
+}
@@ -9,13 +9,22 @@ package provider
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/edgelesssys/constellation/v2/internal/attestation/measurements"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/config/imageversion"
 	"github.com/edgelesssys/constellation/v2/internal/semver"
 	"github.com/edgelesssys/constellation/v2/internal/versions"
+	tfassets "github.com/edgelesssys/constellation/v2/terraform"
 	"github.com/edgelesssys/constellation/v2/terraform-provider-constellation/internal/data"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
@@ -109,6 +118,155 @@ func TestViolatedImageConstraint(t *testing.T) {
 	}
 }
 
+func TestSetClusterDrift(t *testing.T) {
+	ctx := context.Background()
+	attestationTypes := newAttestationConfigAttributeSchema(attributeInput).GetType().(basetypes.ObjectType).AttrTypes
+	imageTypes := newImageAttributeSchema(attributeInput).GetType().(basetypes.ObjectType).AttrTypes
+
+	newModel := func(t *testing.T, csp string) *ClusterResourceModel {
+		attestation, diags := types.ObjectValueFrom(ctx, attestationTypes, attestationAttribute{
+			Variant:      "gcp-sev-es",
+			Measurements: map[string]measurementAttribute{"4": {Expected: "abcd"}},
+		})
+		require.False(t, diags.HasError(), diags)
+		image, diags := types.ObjectValueFrom(ctx, imageTypes, imageAttribute{
+			Version:   "v2.15.0",
+			Reference: "projects/constellation-images/global/images/v2-15-0-gcp-sev-es-stable",
+			ShortPath: "v2.15.0",
+		})
+		require.False(t, diags.HasError(), diags)
+		sans, diags := types.ListValueFrom(ctx, types.StringType, []string{"192.0.2.1", "example.com"})
+		require.False(t, diags.HasError(), diags)
+
+		return &ClusterResourceModel{
+			CSP:                 types.StringValue(csp),
+			KubernetesVersion:   types.StringValue(string(versions.V1_28)),
+			MicroserviceVersion: types.StringValue("v2.15.0"),
+			Image:               image,
+			Attestation:         attestation,
+			APIServerCertSANs:   sans,
+		}
+	}
+	newLive := func() liveClusterState {
+		return liveClusterState{
+			imageVersion:           "v2.15.0",
+			imageReference:         "projects/constellation-images/global/images/v2-15-0-gcp-sev-es-stable",
+			kubernetesVersion:      string(versions.V1_28),
+			constellationServices:  semver.NewFromInt(2, 15, 0, ""),
+			constellationOperators: semver.NewFromInt(2, 15, 0, ""),
+			attestation: &config.GCPSEVES{
+				Measurements: measurements.M{4: {Expected: []byte{0xab, 0xcd}, ValidationOpt: measurements.Enforce}},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		csp                     string
+		modifyLive              func(*liveClusterState)
+		wantWarnings            int
+		wantK8sVersion          string
+		wantMicroserviceVersion string
+		wantImage               imageAttribute
+		wantMeasurement         string
+		wantSANs                []string
+	}{
+		"no drift": {
+			csp:                     "gcp",
+			modifyLive:              func(*liveClusterState) {},
+			wantK8sVersion:          string(versions.V1_28),
+			wantMicroserviceVersion: "v2.15.0",
+			wantImage: imageAttribute{
+				Version:   "v2.15.0",
+				Reference: "projects/constellation-images/global/images/v2-15-0-gcp-sev-es-stable",
+				ShortPath: "v2.15.0",
+			},
+			wantMeasurement: "abcd",
+			wantSANs:        []string{"192.0.2.1", "example.com"},
+		},
+		"drift everywhere": {
+			csp: "gcp",
+			modifyLive: func(live *liveClusterState) {
+				live.kubernetesVersion = string(versions.V1_27)
+				live.constellationOperators = semver.NewFromInt(2, 14, 0, "")
+				live.imageVersion = "v2.14.0"
+				live.imageReference = "projects/constellation-images/global/images/v2-14-0-gcp-sev-es-stable"
+				live.attestation = &config.GCPSEVES{
+					Measurements: measurements.M{4: measurements.WithAllBytes(0x00, measurements.Enforce, measurements.PCRMeasurementLength)},
+				}
+				live.missingCertSANs = []string{"example.com"}
+			},
+			wantWarnings:            5,
+			wantK8sVersion:          string(versions.V1_27),
+			wantMicroserviceVersion: "v2.14.0",
+			wantImage: imageAttribute{
+				Version:   "v2.14.0",
+				Reference: "projects/constellation-images/global/images/v2-14-0-gcp-sev-es-stable",
+				ShortPath: "v2.15.0",
+			},
+			wantMeasurement: "0000000000000000000000000000000000000000000000000000000000000000",
+			wantSANs:        []string{"192.0.2.1"},
+		},
+		"image drift is ignored on QEMU": {
+			csp: "qemu",
+			modifyLive: func(live *liveClusterState) {
+				live.imageVersion = "v2.14.0"
+			},
+			wantK8sVersion:          string(versions.V1_28),
+			wantMicroserviceVersion: "v2.15.0",
+			wantImage: imageAttribute{
+				Version:   "v2.15.0",
+				Reference: "projects/constellation-images/global/images/v2-15-0-gcp-sev-es-stable",
+				ShortPath: "v2.15.0",
+			},
+			wantMeasurement: "abcd",
+			wantSANs:        []string{"192.0.2.1", "example.com"},
+		},
+		"attestation config not read": {
+			csp: "gcp",
+			modifyLive: func(live *liveClusterState) {
+				live.attestation = nil
+			},
+			wantK8sVersion:          string(versions.V1_28),
+			wantMicroserviceVersion: "v2.15.0",
+			wantImage: imageAttribute{
+				Version:   "v2.15.0",
+				Reference: "projects/constellation-images/global/images/v2-15-0-gcp-sev-es-stable",
+				ShortPath: "v2.15.0",
+			},
+			wantMeasurement: "abcd",
+			wantSANs:        []string{"192.0.2.1", "example.com"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			data := newModel(t, tc.csp)
+			live := newLive()
+			tc.modifyLive(&live)
+
+			diags := (&ClusterResource{}).setClusterDrift(ctx, data, live)
+			require.False(diags.HasError(), diags)
+			assert.Equal(tc.wantWarnings, diags.WarningsCount(), diags)
+
+			assert.Equal(tc.wantK8sVersion, data.KubernetesVersion.ValueString())
+			assert.Equal(tc.wantMicroserviceVersion, data.MicroserviceVersion.ValueString())
+			var image imageAttribute
+			require.False(data.Image.As(ctx, &image, basetypes.ObjectAsOptions{}).HasError())
+			assert.Equal(tc.wantImage, image)
+			var attestation attestationAttribute
+			require.False(data.Attestation.As(ctx, &attestation, basetypes.ObjectAsOptions{}).HasError())
+			assert.Equal("gcp-sev-es", attestation.Variant)
+			assert.Equal(tc.wantMeasurement, attestation.Measurements["4"].Expected)
+			var sans []string
+			require.False(data.APIServerCertSANs.ElementsAs(ctx, &sans, false).HasError())
+			assert.Equal(tc.wantSANs, sans)
+		})
+	}
+}
+
 func TestAccClusterResourceImports(t *testing.T) {
 	// Set the path to the Terraform binary for acceptance testing when running under Bazel.
 	bazelPreCheck := func() { bazelSetTerraformBinaryPath(t) }
@@ -452,6 +610,109 @@ func TestAccClusterResource(t *testing.T) {
 				},
 			},
 		},
+		"openstack config missing": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithVersion("v2.13.0"),
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: fullClusterTestingConfig(t, "openstack") + fmt.Sprintf(`
+					resource "constellation_cluster" "test" {
+						csp                     = "stackit"
+						name                    = "constell"
+						uid                     = "test"
+						image                   = data.constellation_image.bar.image
+						attestation             = data.constellation_attestation.foo.attestation
+						init_secret             = "deadbeef"
+						master_secret           = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						master_secret_salt      = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						measurement_salt        = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						out_of_cluster_endpoint = "192.0.2.1"
+						in_cluster_endpoint     = "192.0.2.1"
+						network_config = {
+						  ip_cidr_node    = "0.0.0.0/24"
+						  ip_cidr_service = "0.0.0.0/24"
+						}
+						kubernetes_version = "%s"
+						constellation_microservice_version = "%s"
+					  }
+				`, versions.Default, providerVersion.String()),
+					ExpectError: regexp.MustCompile(".*the 'openstack' configuration must be set.*"),
+				},
+			},
+		},
+		"openstack yawol config missing": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithVersion("v2.13.0"),
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: fullClusterTestingConfig(t, "openstack") + fmt.Sprintf(`
+					resource "constellation_cluster" "test" {
+						csp                     = "stackit"
+						name                    = "constell"
+						uid                     = "test"
+						image                   = data.constellation_image.bar.image
+						attestation             = data.constellation_attestation.foo.attestation
+						init_secret             = "deadbeef"
+						master_secret           = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						master_secret_salt      = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						measurement_salt        = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						out_of_cluster_endpoint = "192.0.2.1"
+						in_cluster_endpoint     = "192.0.2.1"
+						network_config = {
+						  ip_cidr_node    = "0.0.0.0/24"
+						  ip_cidr_service = "0.0.0.0/24"
+						}
+						openstack = {
+						  auth_url                   = "https://keystone.example.com/v3"
+						  username                   = "constell"
+						  password                   = "secret"
+						  project_id                 = "test"
+						  project_name               = "test"
+						  user_domain_name           = "portal_mvp"
+						  project_domain_name        = "portal_mvp"
+						  region_name                = "RegionOne"
+						  floating_ip_pool_id        = "test"
+						  deploy_yawol_load_balancer = true
+						}
+						kubernetes_version = "%s"
+						constellation_microservice_version = "%s"
+					  }
+				`, versions.Default, providerVersion.String()),
+					ExpectError: regexp.MustCompile(".*'yawol_image_id' and 'yawol_flavor_id' must be set.*"),
+				},
+			},
+		},
+		"qemu plan success": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithVersion("v2.13.0"),
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: fullClusterTestingConfig(t, "qemu") + fmt.Sprintf(`
+					resource "constellation_cluster" "test" {
+						csp                     = "qemu"
+						name                    = "constell"
+						uid                     = "test"
+						image                   = data.constellation_image.bar.image
+						attestation             = data.constellation_attestation.foo.attestation
+						init_secret             = "deadbeef"
+						master_secret           = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						master_secret_salt      = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						measurement_salt        = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+						out_of_cluster_endpoint = "10.42.0.2"
+						in_cluster_endpoint     = "10.42.0.2"
+						network_config = {
+						  ip_cidr_node    = "10.42.0.0/16"
+						  ip_cidr_service = "10.96.0.0/12"
+						}
+						kubernetes_version = "%s"
+						constellation_microservice_version = "%s"
+					  }
+				`, versions.Default, providerVersion.String()),
+					PlanOnly:           true,
+					ExpectNonEmptyPlan: true,
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -461,6 +722,134 @@ func TestAccClusterResource(t *testing.T) {
 	}
 }
 
+func TestAccClusterResourceQEMU(t *testing.T) {
+	// Set the path to the Terraform binary for acceptance testing when running under Bazel.
+	bazelPreCheck := func() { bazelSetTerraformBinaryPath(t) }
+	// The cluster runs in VMs on the local libvirt daemon. The QEMU metadata API is started in a Docker container.
+	qemuPreCheck := func() {
+		for _, socket := range []string{qemuLibvirtSocket, "/var/run/docker.sock"} {
+			if _, err := os.Stat(socket); err != nil {
+				t.Skipf("Skipping QEMU acceptance test: %s not available: %s", socket, err)
+			}
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithVersion(providerVersion.String()),
+		PreCheck: func() {
+			bazelPreCheck()
+			qemuPreCheck()
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: qemuClusterTestingConfig(t),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("constellation_cluster.test", "cluster_id"),
+					resource.TestCheckResourceAttrSet("constellation_cluster.test", "owner_id"),
+					resource.TestCheckResourceAttrSet("constellation_cluster.test", "kubeconfig"),
+				),
+			},
+		},
+		// Destroying the configuration removes the cluster from the state and deletes the VMs created by the QEMU module.
+		CheckDestroy: func(s *terraform.State) error {
+			for _, module := range s.Modules {
+				for name := range module.Resources {
+					return fmt.Errorf("resource %s still exists after destroy", name)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// qemuLibvirtSocket is the socket of the system libvirt daemon the QEMU acceptance test creates its VMs with.
+const qemuLibvirtSocket = "/var/run/libvirt/libvirt-sock"
+
+// qemuClusterTestingConfig returns a configuration creating a QEMU cluster with one control-plane and one worker node.
+// The VMs are created with the QEMU Terraform module the CLI uses.
+func qemuClusterTestingConfig(t *testing.T) string {
+	t.Helper()
+
+	return fmt.Sprintf(`
+	provider "constellation" {}
+
+	data "constellation_image" "bar" {
+		version             = "%[1]s"
+		attestation_variant = "qemu-vtpm"
+		csp                 = "qemu"
+	}
+
+	data "constellation_attestation" "foo" {
+		csp                 = "qemu"
+		attestation_variant = "qemu-vtpm"
+		image               = data.constellation_image.bar.image
+	}
+
+	module "qemu_infrastructure" {
+		source = "%[2]s"
+		name   = "constell-acc"
+		node_groups = {
+			control_plane_default = { role = "control-plane", initial_count = 1, disk_size = 10, vcpus = 2, memory = 2048 }
+			worker_default        = { role = "worker", initial_count = 1, disk_size = 10, vcpus = 2, memory = 2048 }
+		}
+		image_id                = data.constellation_image.bar.image.reference
+		image_format            = "raw"
+		constellation_boot_mode = "uefi"
+		machine                 = "q35"
+		libvirt_uri             = "qemu:///system"
+		libvirt_socket_path     = "%[3]s"
+		metadata_libvirt_uri    = "qemu:///system"
+		metadata_api_image      = "%[4]s"
+		nvram                   = "/usr/share/OVMF/OVMF_VARS.fd"
+	}
+
+	resource "constellation_cluster" "test" {
+		csp                     = "qemu"
+		name                    = module.qemu_infrastructure.name
+		uid                     = module.qemu_infrastructure.uid
+		image                   = data.constellation_image.bar.image
+		attestation             = data.constellation_attestation.foo.attestation
+		init_secret             = module.qemu_infrastructure.init_secret
+		master_secret           = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+		master_secret_salt      = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+		measurement_salt        = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+		out_of_cluster_endpoint = module.qemu_infrastructure.out_of_cluster_endpoint
+		in_cluster_endpoint     = module.qemu_infrastructure.in_cluster_endpoint
+		api_server_cert_sans    = module.qemu_infrastructure.api_server_cert_sans
+		network_config = {
+			ip_cidr_node    = module.qemu_infrastructure.ip_cidr_node
+			ip_cidr_service = "10.96.0.0/12"
+		}
+		kubernetes_version                 = "%[5]s"
+		constellation_microservice_version = "%[1]s"
+	}
+	`, providerVersion.String(), qemuTerraformModule(t), qemuLibvirtSocket, imageversion.QEMUMetadata(), versions.Default)
+}
+
+// qemuTerraformModule writes the embedded QEMU Terraform module to a temporary directory and returns its path.
+func qemuTerraformModule(t *testing.T) string {
+	t.Helper()
+
+	const moduleDir = "infrastructure/qemu"
+	dst := t.TempDir()
+	err := fs.WalkDir(tfassets.Assets, moduleDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, strings.TrimPrefix(path, moduleDir))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		content, err := tfassets.Assets.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0o644)
+	})
+	require.NoError(t, err)
+	return dst
+}
+
 func fullClusterTestingConfig(t *testing.T, csp string) string {
 	t.Helper()
 
@@ -509,6 +898,32 @@ func fullClusterTestingConfig(t *testing.T, csp string) string {
 			attestation_variant = "gcp-sev-es"
 			image               = data.constellation_image.bar.image
 		}`
+	case "openstack":
+		return providerConfig + `
+		data "constellation_image" "bar" {
+			version             = "v2.13.0"
+			attestation_variant = "qemu-vtpm"
+			csp                 = "openstack"
+		}
+
+		data "constellation_attestation" "foo" {
+			csp                 = "openstack"
+			attestation_variant = "qemu-vtpm"
+			image               = data.constellation_image.bar.image
+		}`
+	case "qemu":
+		return providerConfig + `
+		data "constellation_image" "bar" {
+			version             = "v2.13.0"
+			attestation_variant = "qemu-vtpm"
+			csp                 = "qemu"
+		}
+
+		data "constellation_attestation" "foo" {
+			csp                 = "qemu"
+			attestation_variant = "qemu-vtpm"
+			image               = data.constellation_image.bar.image
+		}`
 	default:
 		t.Fatal("unknown csp")
 		return ""
@@ -86,10 +86,18 @@ func convertFromTfAttestationCfg(tfAttestation attestationAttribute, attestation
 			MicrocodeVersion:  newVersion(tfAttestation.MicrocodeVersion),
 			AMDRootKey:        rootKey,
 		}
+	case variant.AWSNitroTPM{}:
+		attestationConfig = &config.AWSNitroTPM{
+			Measurements: c11nMeasurements,
+		}
 	case variant.GCPSEVES{}:
 		attestationConfig = &config.GCPSEVES{
 			Measurements: c11nMeasurements,
 		}
+	case variant.QEMUVTPM{}:
+		attestationConfig = &config.QEMUVTPM{
+			Measurements: c11nMeasurements,
+		}
 	default:
 		return nil, fmt.Errorf("unknown attestation variant: %s", attestationVariant)
 	}
@@ -127,7 +135,7 @@ func convertToTfAttestation(attVar variant.Variant, snpVersions attestationconfi
 			return tfAttestation, err
 		}
 		tfAttestation.AzureSNPFirmwareSignerConfig = tfFirmwareCfg
-	case variant.GCPSEVES{}:
+	case variant.AWSNitroTPM{}, variant.GCPSEVES{}, variant.QEMUVTPM{}:
 		// no additional fields
 	default:
 		return tfAttestation, fmt.Errorf("unknown attestation variant: %s", attVar)
@@ -135,6 +143,48 @@ func convertToTfAttestation(attVar variant.Variant, snpVersions attestationconfi
 	return tfAttestation, nil
 }
 
+// convertToTfAttestationCfg converts a constellation attestation config, e.g. as read from a running cluster,
+// to the related terraform struct.
+func convertToTfAttestationCfg(attestationCfg config.AttestationCfg) (attestationAttribute, error) {
+	tfAttestation := attestationAttribute{
+		Variant:      attestationCfg.GetVariant().String(),
+		Measurements: convertToTfMeasurements(attestationCfg.GetMeasurements()),
+	}
+
+	switch c := attestationCfg.(type) {
+	case *config.AzureSEVSNP:
+		certStr, err := certAsString(c.AMDRootKey)
+		if err != nil {
+			return tfAttestation, err
+		}
+		tfFirmwareCfg, err := convertToTfFirmwareCfg(c.FirmwareSignerConfig)
+		if err != nil {
+			return tfAttestation, err
+		}
+		tfAttestation.BootloaderVersion = c.BootloaderVersion.Value
+		tfAttestation.TEEVersion = c.TEEVersion.Value
+		tfAttestation.SNPVersion = c.SNPVersion.Value
+		tfAttestation.MicrocodeVersion = c.MicrocodeVersion.Value
+		tfAttestation.AMDRootKey = certStr
+		tfAttestation.AzureSNPFirmwareSignerConfig = tfFirmwareCfg
+	case *config.AWSSEVSNP:
+		certStr, err := certAsString(c.AMDRootKey)
+		if err != nil {
+			return tfAttestation, err
+		}
+		tfAttestation.BootloaderVersion = c.BootloaderVersion.Value
+		tfAttestation.TEEVersion = c.TEEVersion.Value
+		tfAttestation.SNPVersion = c.SNPVersion.Value
+		tfAttestation.MicrocodeVersion = c.MicrocodeVersion.Value
+		tfAttestation.AMDRootKey = certStr
+	case *config.AWSNitroTPM, *config.GCPSEVES, *config.QEMUVTPM:
+		// no additional fields
+	default:
+		return tfAttestation, fmt.Errorf("unknown attestation variant: %s", attestationCfg.GetVariant())
+	}
+	return tfAttestation, nil
+}
+
 func certAsString(cert config.Certificate) (string, error) {
 	certBytes, err := cert.MarshalJSON()
 	if err != nil {
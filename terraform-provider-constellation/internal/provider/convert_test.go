@@ -63,6 +63,34 @@ func TestParseAttestationConfig(t *testing.T) {
 		assert.Len(t, azureCfg.FirmwareSignerConfig.AcceptedKeyDigests, 1)
 	})
 
+	t.Run("QEMU vTPM success", func(t *testing.T) {
+		attestationVariant := variant.QEMUVTPM{}
+
+		cfg, err := convertFromTfAttestationCfg(testAttestation, attestationVariant)
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+
+		qemuCfg, ok := cfg.(*config.QEMUVTPM)
+		require.True(t, ok)
+
+		require.Equal(t, []byte("Hello"), qemuCfg.Measurements[1].Expected)
+		require.Equal(t, measurements.Enforce, qemuCfg.Measurements[1].ValidationOpt)
+
+		require.Equal(t, []byte("world"), qemuCfg.Measurements[2].Expected)
+		require.Equal(t, measurements.WarnOnly, qemuCfg.Measurements[2].ValidationOpt)
+	})
+
+	t.Run("AWS Nitro TPM success", func(t *testing.T) {
+		attestationVariant := variant.AWSNitroTPM{}
+
+		cfg, err := convertFromTfAttestationCfg(testAttestation, attestationVariant)
+		require.NoError(t, err)
+
+		awsCfg, ok := cfg.(*config.AWSNitroTPM)
+		require.True(t, ok)
+		assert.Len(t, awsCfg.Measurements, 2)
+	})
+
 	// Test error scenarios
 	t.Run("invalid_measurement_index", func(t *testing.T) {
 		testAttestation.Measurements = map[string]measurementAttribute{"invalid": {Expected: "data"}}
@@ -82,3 +110,67 @@ func TestParseAttestationConfig(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestConvertToTfAttestationCfg(t *testing.T) {
+	testCases := map[string]struct {
+		attestationCfg config.AttestationCfg
+		wantErr        bool
+	}{
+		"Azure SEV-SNP": {
+			attestationCfg: func() config.AttestationCfg {
+				cfg := config.DefaultForAzureSEVSNP()
+				cfg.BootloaderVersion = config.AttestationVersion{Value: 1}
+				cfg.TEEVersion = config.AttestationVersion{Value: 2}
+				cfg.SNPVersion = config.AttestationVersion{Value: 3}
+				cfg.MicrocodeVersion = config.AttestationVersion{Value: 4}
+				cfg.Measurements = measurements.M{4: measurements.WithAllBytes(0x11, measurements.Enforce, measurements.PCRMeasurementLength)}
+				return cfg
+			}(),
+		},
+		"AWS SEV-SNP": {
+			attestationCfg: func() config.AttestationCfg {
+				cfg := config.DefaultForAWSSEVSNP()
+				cfg.BootloaderVersion = config.AttestationVersion{Value: 1}
+				cfg.TEEVersion = config.AttestationVersion{Value: 2}
+				cfg.SNPVersion = config.AttestationVersion{Value: 3}
+				cfg.MicrocodeVersion = config.AttestationVersion{Value: 4}
+				cfg.Measurements = measurements.M{4: measurements.WithAllBytes(0x11, measurements.WarnOnly, measurements.PCRMeasurementLength)}
+				return cfg
+			}(),
+		},
+		"GCP SEV-ES": {
+			attestationCfg: &config.GCPSEVES{
+				Measurements: measurements.M{
+					4: measurements.WithAllBytes(0x11, measurements.Enforce, measurements.PCRMeasurementLength),
+					9: measurements.WithAllBytes(0x22, measurements.WarnOnly, measurements.PCRMeasurementLength),
+				},
+			},
+		},
+		"unsupported variant": {
+			attestationCfg: &config.AzureTrustedLaunch{},
+			wantErr:        true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			tfAttestation, err := convertToTfAttestationCfg(tc.attestationCfg)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			require.NoError(err)
+			assert.Equal(tc.attestationCfg.GetVariant().String(), tfAttestation.Variant)
+
+			// converting back must yield the original config
+			cfg, err := convertFromTfAttestationCfg(tfAttestation, tc.attestationCfg.GetVariant())
+			require.NoError(err)
+			equal, err := cfg.EqualTo(tc.attestationCfg)
+			require.NoError(err)
+			assert.True(equal)
+		})
+	}
+}
@@ -0,0 +1,586 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource                   = &IAMResource{}
+	_ resource.ResourceWithImportState    = &IAMResource{}
+	_ resource.ResourceWithValidateConfig = &IAMResource{}
+)
+
+// NewIAMResource creates a new IAM resource.
+func NewIAMResource() resource.Resource {
+	return &IAMResource{}
+}
+
+// IAMResource defines the resource implementation.
+type IAMResource struct{}
+
+// IAMResourceModel describes the resource data model.
+type IAMResourceModel struct {
+	Workspace types.String `tfsdk:"workspace"`
+	CSP       types.String `tfsdk:"csp"`
+	AWS       types.Object `tfsdk:"aws"`
+	Azure     types.Object `tfsdk:"azure"`
+	GCP       types.Object `tfsdk:"gcp"`
+
+	ServiceAccountKey           types.String `tfsdk:"service_account_key"`
+	SubscriptionID              types.String `tfsdk:"subscription_id"`
+	TenantID                    types.String `tfsdk:"tenant_id"`
+	UAMIID                      types.String `tfsdk:"uami_id"`
+	ControlPlaneInstanceProfile types.String `tfsdk:"control_plane_instance_profile"`
+	WorkerNodesInstanceProfile  types.String `tfsdk:"worker_nodes_instance_profile"`
+}
+
+// awsIAMAttribute is the aws attribute's data model.
+type awsIAMAttribute struct {
+	Region string `tfsdk:"region"`
+	Prefix string `tfsdk:"prefix"`
+}
+
+var awsIAMAttributeTypes = map[string]attr.Type{
+	"region": types.StringType,
+	"prefix": types.StringType,
+}
+
+// azureIAMAttribute is the azure attribute's data model.
+type azureIAMAttribute struct {
+	Location         string `tfsdk:"location"`
+	ResourceGroup    string `tfsdk:"resource_group"`
+	ServicePrincipal string `tfsdk:"service_principal"`
+}
+
+var azureIAMAttributeTypes = map[string]attr.Type{
+	"location":          types.StringType,
+	"resource_group":    types.StringType,
+	"service_principal": types.StringType,
+}
+
+// gcpIAMAttribute is the gcp attribute's data model.
+type gcpIAMAttribute struct {
+	ProjectID        string `tfsdk:"project_id"`
+	ServiceAccountID string `tfsdk:"service_account_id"`
+	Region           string `tfsdk:"region"`
+	Zone             string `tfsdk:"zone"`
+}
+
+var gcpIAMAttributeTypes = map[string]attr.Type{
+	"project_id":         types.StringType,
+	"service_account_id": types.StringType,
+	"region":             types.StringType,
+	"zone":               types.StringType,
+}
+
+// Metadata returns the metadata of the resource.
+func (r *IAMResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam"
+}
+
+// Schema returns the schema of the resource.
+func (r *IAMResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource for the IAM configuration required by a Constellation cluster. " +
+			"The outputs can be passed to the [constellation_infrastructure](./infrastructure.md) and [constellation_cluster](./cluster.md) resources.",
+		Description: "Resource for the IAM configuration required by a Constellation cluster.",
+
+		Attributes: map[string]schema.Attribute{
+			// Input attributes
+			"workspace": newWorkspaceAttributeSchema("IAM configuration"),
+			"csp":       newIAMCSPAttributeSchema(),
+			"aws": schema.SingleNestedAttribute{
+				MarkdownDescription: "AWS-specific configuration.",
+				Description:         "AWS-specific configuration.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"region": schema.StringAttribute{
+						MarkdownDescription: "AWS region the IAM configuration is created in.",
+						Description:         "AWS region the IAM configuration is created in.",
+						Required:            true,
+					},
+					"prefix": schema.StringAttribute{
+						MarkdownDescription: "Name prefix for all IAM resources.",
+						Description:         "Name prefix for all IAM resources.",
+						Required:            true,
+					},
+				},
+			},
+			"azure": schema.SingleNestedAttribute{
+				MarkdownDescription: "Azure-specific configuration.",
+				Description:         "Azure-specific configuration.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"location": schema.StringAttribute{
+						MarkdownDescription: "Azure location the IAM configuration is created in.",
+						Description:         "Azure location the IAM configuration is created in.",
+						Required:            true,
+					},
+					"resource_group": schema.StringAttribute{
+						MarkdownDescription: "Name of the Azure resource group to create. The cluster's infrastructure must be created in the same resource group.",
+						Description:         "Name of the Azure resource group to create. The cluster's infrastructure must be created in the same resource group.",
+						Required:            true,
+					},
+					"service_principal": schema.StringAttribute{
+						MarkdownDescription: "Name of the service principal to create.",
+						Description:         "Name of the service principal to create.",
+						Required:            true,
+					},
+				},
+			},
+			"gcp": schema.SingleNestedAttribute{
+				MarkdownDescription: "GCP-specific configuration.",
+				Description:         "GCP-specific configuration.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"project_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the GCP project the IAM configuration is created in.",
+						Description:         "ID of the GCP project the IAM configuration is created in.",
+						Required:            true,
+					},
+					"service_account_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the service account to create.",
+						Description:         "ID of the service account to create.",
+						Required:            true,
+					},
+					"region": schema.StringAttribute{
+						MarkdownDescription: "GCP region the IAM configuration is created in.",
+						Description:         "GCP region the IAM configuration is created in.",
+						Required:            true,
+					},
+					"zone": schema.StringAttribute{
+						MarkdownDescription: "GCP zone the IAM configuration is created in.",
+						Description:         "GCP zone the IAM configuration is created in.",
+						Required:            true,
+					},
+				},
+			},
+
+			// Computed (output) attributes
+			"service_account_key": schema.StringAttribute{
+				MarkdownDescription: "Base64-encoded private key JSON object of the created service account. Only set for GCP. " +
+					"Use it as `gcp.service_account_key` of the `constellation_cluster` resource.",
+				Description: "Base64-encoded private key JSON object of the created service account. Only set for GCP.",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"subscription_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Azure subscription the IAM configuration was created in. Only set for Azure.",
+				Description:         "ID of the Azure subscription the IAM configuration was created in. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tenant_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Azure tenant the IAM configuration was created in. Only set for Azure.",
+				Description:         "ID of the Azure tenant the IAM configuration was created in. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uami_id": schema.StringAttribute{
+				MarkdownDescription: "Resource ID of the created user assigned managed identity (UAMI). Only set for Azure. " +
+					"Use it as `azure.uami_resource_id` of the `constellation_cluster` resource.",
+				Description: "Resource ID of the created user assigned managed identity (UAMI). Only set for Azure.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"control_plane_instance_profile": schema.StringAttribute{
+				MarkdownDescription: "Name of the instance profile for control-plane nodes. Only set for AWS.",
+				Description:         "Name of the instance profile for control-plane nodes. Only set for AWS.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"worker_nodes_instance_profile": schema.StringAttribute{
+				MarkdownDescription: "Name of the instance profile for worker nodes. Only set for AWS.",
+				Description:         "Name of the instance profile for worker nodes. Only set for AWS.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the configuration for the resource.
+func (r *IAMResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data IAMResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCSPConfig(data.CSP, map[cloudprovider.Provider]types.Object{
+		cloudprovider.AWS:   data.AWS,
+		cloudprovider.Azure: data.Azure,
+		cloudprovider.GCP:   data.GCP,
+	})...)
+}
+
+// Create is called when the resource is created.
+func (r *IAMResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read data supplied by Terraform runtime into the model
+	var data IAMResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	csp := cloudprovider.FromString(data.CSP.ValueString())
+	opts := &cloudcmd.IAMConfigOptions{
+		TFLogLevel:  terraform.LogLevelNone,
+		TFWorkspace: data.Workspace.ValueString(),
+	}
+	var convertDiags diag.Diagnostics
+	switch csp {
+	case cloudprovider.AWS:
+		var awsConfig awsIAMAttribute
+		convertDiags = data.AWS.As(ctx, &awsConfig, basetypes.ObjectAsOptions{})
+		opts.AWS = cloudcmd.AWSIAMConfig{
+			Region: awsConfig.Region,
+			Prefix: awsConfig.Prefix,
+		}
+	case cloudprovider.Azure:
+		var azureConfig azureIAMAttribute
+		convertDiags = data.Azure.As(ctx, &azureConfig, basetypes.ObjectAsOptions{})
+		opts.Azure = cloudcmd.AzureIAMConfig{
+			Location:         azureConfig.Location,
+			ResourceGroup:    azureConfig.ResourceGroup,
+			ServicePrincipal: azureConfig.ServicePrincipal,
+		}
+	case cloudprovider.GCP:
+		var gcpConfig gcpIAMAttribute
+		convertDiags = data.GCP.As(ctx, &gcpConfig, basetypes.ObjectAsOptions{})
+		opts.GCP = cloudcmd.GCPIAMConfig{
+			ProjectID:        gcpConfig.ProjectID,
+			ServiceAccountID: gcpConfig.ServiceAccountID,
+			Region:           gcpConfig.Region,
+			Zone:             gcpConfig.Zone,
+		}
+	}
+	resp.Diagnostics.Append(convertDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	iamOutput, err := cloudcmd.NewIAMCreator(&tfLogWriter{ctx: ctx}).Create(ctx, csp, opts)
+	if err != nil {
+		resp.Diagnostics.AddError("Creating IAM configuration", err.Error())
+		return
+	}
+	setIAMOutput(&data, iamOutput)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *IAMResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data IAMResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	resp.Diagnostics.Append(checkWorkspace(workspace, "IAM configuration")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	csp := cloudprovider.FromString(data.CSP.ValueString())
+	iamOutput, err := cloudcmd.NewIAMCreator(&tfLogWriter{ctx: ctx}).Show(ctx, csp, workspace)
+	if err != nil {
+		resp.Diagnostics.AddError("Reading IAM configuration", err.Error())
+		return
+	}
+	setIAMOutput(&data, iamOutput)
+
+	// Inputs are only unset after an import. Recover them from the variables of the workspace.
+	if data.AWS.IsNull() && data.Azure.IsNull() && data.GCP.IsNull() {
+		resp.Diagnostics.Append(setIAMInputsFromWorkspace(ctx, &data, csp)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource.
+func (r *IAMResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All inputs require a replacement of the resource, so there is nothing to update.
+	var data IAMResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete destroys the resource.
+func (r *IAMResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data IAMResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := cloudcmd.NewIAMDestroyer().DestroyIAMConfiguration(ctx, data.Workspace.ValueString(), terraform.LogLevelNone); err != nil {
+		resp.Diagnostics.AddError("Destroying IAM configuration", err.Error())
+		return
+	}
+}
+
+// ImportState imports to the resource.
+func (r *IAMResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	csp, workspace, diags := parseWorkspaceImportURI(req.ID, constants.ConstellationIAMURIScheme)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("csp"), csp)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace"), workspace)...)
+}
+
+// setIAMOutput writes the outputs of an IAM configuration to the data model.
+func setIAMOutput(data *IAMResourceModel, iamOutput cloudcmd.IAMOutput) {
+	data.ServiceAccountKey = types.StringNull()
+	data.SubscriptionID = types.StringNull()
+	data.TenantID = types.StringNull()
+	data.UAMIID = types.StringNull()
+	data.ControlPlaneInstanceProfile = types.StringNull()
+	data.WorkerNodesInstanceProfile = types.StringNull()
+
+	switch iamOutput.CloudProvider {
+	case cloudprovider.AWS:
+		data.ControlPlaneInstanceProfile = types.StringValue(iamOutput.AWSOutput.ControlPlaneInstanceProfile)
+		data.WorkerNodesInstanceProfile = types.StringValue(iamOutput.AWSOutput.WorkerNodeInstanceProfile)
+	case cloudprovider.Azure:
+		data.SubscriptionID = types.StringValue(iamOutput.AzureOutput.SubscriptionID)
+		data.TenantID = types.StringValue(iamOutput.AzureOutput.TenantID)
+		data.UAMIID = types.StringValue(iamOutput.AzureOutput.UAMIID)
+	case cloudprovider.GCP:
+		data.ServiceAccountKey = types.StringValue(iamOutput.GCPOutput.ServiceAccountKey)
+	}
+}
+
+// setIAMInputsFromWorkspace sets the CSP-specific inputs of the data model
+// from the Terraform variables stored in the IAM workspace.
+func setIAMInputsFromWorkspace(ctx context.Context, data *IAMResourceModel, csp cloudprovider.Provider) diag.Diagnostics {
+	var diags diag.Diagnostics
+	varsFile := filepath.Join(data.Workspace.ValueString(), "terraform.tfvars")
+	varBytes, err := os.ReadFile(varsFile)
+	if err != nil {
+		diags.AddError("Reading IAM workspace", fmt.Sprintf("Reading %s: %s", varsFile, err))
+		return diags
+	}
+
+	var convertDiags diag.Diagnostics
+	switch csp {
+	case cloudprovider.AWS:
+		var vars terraform.AWSIAMVariables
+		err = terraform.VariablesFromBytes(varBytes, &vars)
+		data.AWS, convertDiags = types.ObjectValueFrom(ctx, awsIAMAttributeTypes, awsIAMAttribute{
+			Region: vars.Region,
+			Prefix: vars.Prefix,
+		})
+	case cloudprovider.Azure:
+		var vars terraform.AzureIAMVariables
+		err = terraform.VariablesFromBytes(varBytes, &vars)
+		location := vars.Location
+		if location == "" && vars.Region != nil {
+			location = *vars.Region
+		}
+		data.Azure, convertDiags = types.ObjectValueFrom(ctx, azureIAMAttributeTypes, azureIAMAttribute{
+			Location:         location,
+			ResourceGroup:    vars.ResourceGroup,
+			ServicePrincipal: vars.ServicePrincipal,
+		})
+	case cloudprovider.GCP:
+		var vars terraform.GCPIAMVariables
+		err = terraform.VariablesFromBytes(varBytes, &vars)
+		data.GCP, convertDiags = types.ObjectValueFrom(ctx, gcpIAMAttributeTypes, gcpIAMAttribute{
+			ProjectID:        vars.Project,
+			ServiceAccountID: vars.ServiceAccountID,
+			Region:           vars.Region,
+			Zone:             vars.Zone,
+		})
+	}
+	if err != nil {
+		diags.AddError("Parsing IAM workspace", fmt.Sprintf("Parsing %s: %s", varsFile, err))
+		return diags
+	}
+	diags.Append(convertDiags...)
+	return diags
+}
+
+// newWorkspaceAttributeSchema returns the schema of the workspace attribute
+// shared by the resources wrapping Constellation's Terraform configurations.
+func newWorkspaceAttributeSchema(resourceName string) schema.Attribute {
+	return schema.StringAttribute{
+		MarkdownDescription: fmt.Sprintf("Path to the directory the Terraform workspace of the %s is kept in. "+
+			"The directory holds the Terraform state of the %s and must be persisted, e.g., next to your own Terraform state.", resourceName, resourceName),
+		Description: fmt.Sprintf("Path to the directory the Terraform workspace of the %s is kept in.", resourceName),
+		Required:    true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// newIAMCSPAttributeSchema returns the schema of the csp attribute of
+// resources that only support the CSPs Constellation can create an IAM configuration for.
+func newIAMCSPAttributeSchema() schema.Attribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "CSP (Cloud Service Provider) to use. Can be one of `aws`, `azure` or `gcp`.",
+		Description:         "CSP (Cloud Service Provider) to use. Can be one of aws, azure or gcp.",
+		Required:            true,
+		Validators: []validator.String{
+			stringvalidator.OneOf("aws", "azure", "gcp"),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// validateCSPConfig checks that the configuration block of the selected CSP is set,
+// and warns about configuration blocks of other CSPs.
+func validateCSPConfig(csp types.String, cspConfigs map[cloudprovider.Provider]types.Object) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if csp.IsUnknown() {
+		return diags
+	}
+	selected := cloudprovider.FromString(csp.ValueString())
+	for provider, cspConfig := range cspConfigs {
+		name := strings.ToLower(provider.String())
+		switch {
+		case provider == selected && cspConfig.IsNull():
+			diags.AddAttributeError(
+				path.Root(name),
+				fmt.Sprintf("%s configuration missing", provider),
+				fmt.Sprintf("When csp is set to '%s', the '%s' configuration must be set.", name, name),
+			)
+		case provider != selected && !cspConfig.IsNull():
+			diags.AddAttributeWarning(
+				path.Root(name),
+				fmt.Sprintf("%s configuration not allowed", provider),
+				fmt.Sprintf("When csp is not set to '%s', setting the '%s' configuration has no effect.", name, name),
+			)
+		}
+	}
+	return diags
+}
+
+// checkWorkspace ensures the Terraform workspace of a resource exists.
+// The workspace holds the state of the resource's cloud resources. If it is missing, the resource must
+// not be removed from the Terraform state, since Terraform would then create the cloud resources a second time.
+func checkWorkspace(workspace, resource string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	_, err := os.Stat(workspace)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		diags.AddError(
+			fmt.Sprintf("Reading %s", resource),
+			fmt.Sprintf("The Terraform workspace %q does not exist, but the %s's cloud resources may still exist. "+
+				"Restore the workspace from a backup. If the workspace was moved, remove the resource from the Terraform state "+
+				"with 'terraform state rm' and import it from the new workspace location.", workspace, resource),
+		)
+	case err != nil:
+		diags.AddError(fmt.Sprintf("Reading %s", resource), fmt.Sprintf("Checking Terraform workspace %q: %s", workspace, err))
+	}
+	return diags
+}
+
+// parseWorkspaceImportURI parses an import URI of the form '<scheme>://?csp=<...>&workspace=<...>'.
+func parseWorkspaceImportURI(id, scheme string) (csp, workspace string, diags diag.Diagnostics) {
+	expectedSchemaMsg := fmt.Sprintf("Expected URI of schema '%s://?%s=<...>&%s=<...>'",
+		scheme, constants.CSPURIKey, constants.WorkspaceURIKey)
+
+	uri, err := url.Parse(id)
+	if err != nil {
+		diags.AddError("Parsing import URI", fmt.Sprintf("Parsing import URI: %s.\n%s", err, expectedSchemaMsg))
+		return "", "", diags
+	}
+	if uri.Scheme != scheme {
+		diags.AddError("Parsing import URI",
+			fmt.Sprintf("Parsing import URI: Invalid scheme '%s'.\n%s", uri.Scheme, expectedSchemaMsg))
+		return "", "", diags
+	}
+
+	query := uri.Query()
+	csp = query.Get(constants.CSPURIKey)
+	workspace = query.Get(constants.WorkspaceURIKey)
+	if csp == "" {
+		diags.AddError("Parsing import URI",
+			fmt.Sprintf("Parsing import URI: Missing query parameter '%s'.\n%s", constants.CSPURIKey, expectedSchemaMsg))
+		return "", "", diags
+	}
+	switch cloudprovider.FromString(csp) {
+	case cloudprovider.AWS, cloudprovider.Azure, cloudprovider.GCP:
+	default:
+		diags.AddError("Parsing import URI",
+			fmt.Sprintf("Parsing import URI: Unsupported CSP '%s'.\n%s", csp, expectedSchemaMsg))
+		return "", "", diags
+	}
+	if workspace == "" {
+		diags.AddError("Parsing import URI",
+			fmt.Sprintf("Parsing import URI: Missing query parameter '%s'.\n%s", constants.WorkspaceURIKey, expectedSchemaMsg))
+		return "", "", diags
+	}
+	return strings.ToLower(csp), workspace, diags
+}
@@ -0,0 +1,257 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkspaceImportURI(t *testing.T) {
+	testCases := map[string]struct {
+		id            string
+		wantCSP       string
+		wantWorkspace string
+		wantErr       bool
+	}{
+		"success": {
+			id:            "constellation-iam://?csp=gcp&workspace=/tmp/iam",
+			wantCSP:       "gcp",
+			wantWorkspace: "/tmp/iam",
+		},
+		"csp is normalized": {
+			id:            "constellation-iam://?csp=AWS&workspace=iam",
+			wantCSP:       "aws",
+			wantWorkspace: "iam",
+		},
+		"wrong scheme": {
+			id:      "constellation-cluster://?csp=gcp&workspace=/tmp/iam",
+			wantErr: true,
+		},
+		"csp missing": {
+			id:      "constellation-iam://?workspace=/tmp/iam",
+			wantErr: true,
+		},
+		"unsupported csp": {
+			id:      "constellation-iam://?csp=qemu&workspace=/tmp/iam",
+			wantErr: true,
+		},
+		"workspace missing": {
+			id:      "constellation-iam://?csp=gcp",
+			wantErr: true,
+		},
+		"invalid uri": {
+			id:      "constellation-iam://%",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			csp, workspace, diags := parseWorkspaceImportURI(tc.id, constants.ConstellationIAMURIScheme)
+			if tc.wantErr {
+				assert.True(diags.HasError())
+				return
+			}
+			assert.False(diags.HasError())
+			assert.Equal(tc.wantCSP, csp)
+			assert.Equal(tc.wantWorkspace, workspace)
+		})
+	}
+}
+
+func TestCheckWorkspace(t *testing.T) {
+	testCases := map[string]struct {
+		createWorkspace bool
+		wantErr         bool
+	}{
+		"workspace exists": {
+			createWorkspace: true,
+		},
+		"workspace missing": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			workspace := filepath.Join(t.TempDir(), "workspace")
+			if tc.createWorkspace {
+				require.NoError(t, os.Mkdir(workspace, 0o700))
+			}
+
+			diags := checkWorkspace(workspace, "infrastructure")
+			assert.Equal(tc.wantErr, diags.HasError())
+		})
+	}
+}
+
+func TestSetIAMInputsFromWorkspace(t *testing.T) {
+	testCases := map[string]struct {
+		csp       cloudprovider.Provider
+		tfvars    string
+		wantAWS   awsIAMAttribute
+		wantAzure azureIAMAttribute
+		wantGCP   gcpIAMAttribute
+		wantErr   bool
+	}{
+		"aws": {
+			csp:     cloudprovider.AWS,
+			tfvars:  "region = \"eu-central-1\"\nname_prefix = \"test\"\n",
+			wantAWS: awsIAMAttribute{Region: "eu-central-1", Prefix: "test"},
+		},
+		"azure": {
+			csp:       cloudprovider.Azure,
+			tfvars:    "location = \"westeurope\"\nservice_principal_name = \"sp\"\nresource_group_name = \"rg\"\n",
+			wantAzure: azureIAMAttribute{Location: "westeurope", ServicePrincipal: "sp", ResourceGroup: "rg"},
+		},
+		"gcp": {
+			csp:     cloudprovider.GCP,
+			tfvars:  "project_id = \"project\"\nregion = \"europe-west3\"\nzone = \"europe-west3-a\"\nservice_account_id = \"sa\"\n",
+			wantGCP: gcpIAMAttribute{ProjectID: "project", Region: "europe-west3", Zone: "europe-west3-a", ServiceAccountID: "sa"},
+		},
+		"invalid tfvars": {
+			csp:     cloudprovider.GCP,
+			tfvars:  "project_id = ",
+			wantErr: true,
+		},
+		"missing tfvars": {
+			csp:     cloudprovider.GCP,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			workspace := t.TempDir()
+			if tc.tfvars != "" {
+				require.NoError(os.WriteFile(filepath.Join(workspace, "terraform.tfvars"), []byte(tc.tfvars), 0o644))
+			}
+			data := &IAMResourceModel{Workspace: types.StringValue(workspace)}
+
+			diags := setIAMInputsFromWorkspace(context.Background(), data, tc.csp)
+			if tc.wantErr {
+				assert.True(diags.HasError())
+				return
+			}
+			require.False(diags.HasError())
+
+			switch tc.csp {
+			case cloudprovider.AWS:
+				var got awsIAMAttribute
+				require.False(data.AWS.As(context.Background(), &got, basetypes.ObjectAsOptions{}).HasError())
+				assert.Equal(tc.wantAWS, got)
+			case cloudprovider.Azure:
+				var got azureIAMAttribute
+				require.False(data.Azure.As(context.Background(), &got, basetypes.ObjectAsOptions{}).HasError())
+				assert.Equal(tc.wantAzure, got)
+			case cloudprovider.GCP:
+				var got gcpIAMAttribute
+				require.False(data.GCP.As(context.Background(), &got, basetypes.ObjectAsOptions{}).HasError())
+				assert.Equal(tc.wantGCP, got)
+			}
+		})
+	}
+}
+
+func TestSetIAMOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	data := &IAMResourceModel{}
+	setIAMOutput(data, cloudcmd.IAMOutput{
+		CloudProvider: cloudprovider.Azure,
+		AzureOutput: cloudcmd.AzureIAMOutput{
+			SubscriptionID: "subscription",
+			TenantID:       "tenant",
+			UAMIID:         "uami",
+		},
+	})
+	assert.Equal("subscription", data.SubscriptionID.ValueString())
+	assert.Equal("tenant", data.TenantID.ValueString())
+	assert.Equal("uami", data.UAMIID.ValueString())
+	assert.True(data.ServiceAccountKey.IsNull())
+	assert.True(data.ControlPlaneInstanceProfile.IsNull())
+	assert.True(data.WorkerNodesInstanceProfile.IsNull())
+}
+
+func TestAccIAMResource(t *testing.T) {
+	// Set the path to the Terraform binary for acceptance testing when running under Bazel.
+	bazelPreCheck := func() { bazelSetTerraformBinaryPath(t) }
+
+	testCases := map[string]resource.TestCase{
+		"csp config missing": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					resource "constellation_iam" "test" {
+						workspace = "iam"
+						csp       = "gcp"
+					}
+					`,
+					ExpectError: regexp.MustCompile(".*the 'gcp' configuration must be set.*"),
+				},
+			},
+		},
+		"unsupported csp": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					resource "constellation_iam" "test" {
+						workspace = "iam"
+						csp       = "qemu"
+					}
+					`,
+					ExpectError: regexp.MustCompile(".*Attribute csp value must be one of.*"),
+				},
+			},
+		},
+		"import non-existent workspace": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					resource "constellation_iam" "test" {}
+					`,
+					ResourceName:  "constellation_iam.test",
+					ImportState:   true,
+					ImportStateId: "constellation-iam://?csp=gcp&workspace=does-not-exist",
+					ExpectError:   regexp.MustCompile(".*Cannot import non-existent remote object.*"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, tc)
+		})
+	}
+}
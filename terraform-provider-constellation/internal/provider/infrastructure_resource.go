@@ -0,0 +1,685 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/cloudcmd"
+	"github.com/edgelesssys/constellation/v2/internal/config"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/edgelesssys/constellation/v2/internal/role"
+	"github.com/edgelesssys/constellation/v2/internal/terraform"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/spf13/afero"
+)
+
+var (
+	// Ensure provider defined types fully satisfy framework interfaces.
+	_ resource.Resource                   = &InfrastructureResource{}
+	_ resource.ResourceWithImportState    = &InfrastructureResource{}
+	_ resource.ResourceWithValidateConfig = &InfrastructureResource{}
+)
+
+// NewInfrastructureResource creates a new infrastructure resource.
+func NewInfrastructureResource() resource.Resource {
+	return &InfrastructureResource{}
+}
+
+// InfrastructureResource defines the resource implementation.
+type InfrastructureResource struct{}
+
+// InfrastructureResourceModel describes the resource data model.
+type InfrastructureResourceModel struct {
+	Workspace            types.String `tfsdk:"workspace"`
+	CSP                  types.String `tfsdk:"csp"`
+	Name                 types.String `tfsdk:"name"`
+	Image                types.Object `tfsdk:"image"`
+	AttestationVariant   types.String `tfsdk:"attestation_variant"`
+	NodeGroups           types.Map    `tfsdk:"node_groups"`
+	InternalLoadBalancer types.Bool   `tfsdk:"internal_load_balancer"`
+	AWS                  types.Object `tfsdk:"aws"`
+	Azure                types.Object `tfsdk:"azure"`
+	GCP                  types.Object `tfsdk:"gcp"`
+
+	UID                      types.String `tfsdk:"uid"`
+	ClusterName              types.String `tfsdk:"cluster_name"`
+	InitSecret               types.String `tfsdk:"init_secret"`
+	OutOfClusterEndpoint     types.String `tfsdk:"out_of_cluster_endpoint"`
+	InClusterEndpoint        types.String `tfsdk:"in_cluster_endpoint"`
+	APIServerCertSANs        types.List   `tfsdk:"api_server_cert_sans"`
+	IPCidrNode               types.String `tfsdk:"ip_cidr_node"`
+	IPCidrPod                types.String `tfsdk:"ip_cidr_pod"`
+	AttestationURL           types.String `tfsdk:"attestation_url"`
+	UAMIClientID             types.String `tfsdk:"uami_client_id"`
+	NetworkSecurityGroupName types.String `tfsdk:"network_security_group_name"`
+	LoadBalancerName         types.String `tfsdk:"load_balancer_name"`
+}
+
+// nodeGroupAttribute is the node group attribute's data model.
+// Optional fields use basetypes, as a go type cannot handle null values.
+type nodeGroupAttribute struct {
+	Role            string                `tfsdk:"role"`
+	InitialCount    int                   `tfsdk:"initial_count"`
+	InstanceType    basetypes.StringValue `tfsdk:"instance_type"`
+	StateDiskSizeGB basetypes.Int64Value  `tfsdk:"state_disk_size_gb"`
+	StateDiskType   basetypes.StringValue `tfsdk:"state_disk_type"`
+	Zone            basetypes.StringValue `tfsdk:"zone"`
+}
+
+// awsInfrastructureAttribute is the aws attribute's data model.
+type awsInfrastructureAttribute struct {
+	Region                      string `tfsdk:"region"`
+	Zone                        string `tfsdk:"zone"`
+	ControlPlaneInstanceProfile string `tfsdk:"control_plane_instance_profile"`
+	WorkerNodesInstanceProfile  string `tfsdk:"worker_nodes_instance_profile"`
+}
+
+// azureInfrastructureAttribute is the azure attribute's data model.
+type azureInfrastructureAttribute struct {
+	Location       string `tfsdk:"location"`
+	ResourceGroup  string `tfsdk:"resource_group"`
+	SubscriptionID string `tfsdk:"subscription_id"`
+	TenantID       string `tfsdk:"tenant_id"`
+	UAMIID         string `tfsdk:"uami_id"`
+	SecureBoot     bool   `tfsdk:"secure_boot"`
+}
+
+// gcpInfrastructureAttribute is the gcp attribute's data model.
+type gcpInfrastructureAttribute struct {
+	ProjectID string `tfsdk:"project_id"`
+	Region    string `tfsdk:"region"`
+	Zone      string `tfsdk:"zone"`
+}
+
+// Metadata returns the metadata of the resource.
+func (r *InfrastructureResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_infrastructure"
+}
+
+// Schema returns the schema of the resource.
+func (r *InfrastructureResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Resource for the cloud infrastructure of a Constellation cluster. " +
+			"The outputs can be passed to the [constellation_cluster](./cluster.md) resource.",
+		Description: "Resource for the cloud infrastructure of a Constellation cluster.",
+
+		Attributes: map[string]schema.Attribute{
+			// Input attributes
+			"workspace":           newWorkspaceAttributeSchema("infrastructure"),
+			"csp":                 newIAMCSPAttributeSchema(),
+			"image":               newImageAttributeSchema(attributeInput),
+			"attestation_variant": newAttestationVariantAttributeSchema(attributeInput),
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the cluster.",
+				Description:         "Name of the cluster.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"node_groups": schema.MapNestedAttribute{
+				MarkdownDescription: "Node groups of the cluster, keyed by their name. " +
+					"At least one control-plane and one worker group are required.",
+				Description: "Node groups of the cluster, keyed by their name.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"role": schema.StringAttribute{
+							MarkdownDescription: "Role of the nodes in the group. Can be one of `control-plane` or `worker`.",
+							Description:         "Role of the nodes in the group. Can be one of control-plane or worker.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("control-plane", "worker"),
+							},
+						},
+						"initial_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of nodes created in the group.",
+							Description:         "Number of nodes created in the group.",
+							Required:            true,
+						},
+						"instance_type": schema.StringAttribute{
+							MarkdownDescription: "VM instance type of the nodes. When not set, the CSP's default instance type is used.",
+							Description:         "VM instance type of the nodes. When not set, the CSP's default instance type is used.",
+							Optional:            true,
+						},
+						"state_disk_size_gb": schema.Int64Attribute{
+							MarkdownDescription: "Size of the nodes' state disk in GB. When not set, 30 GB are used.",
+							Description:         "Size of the nodes' state disk in GB. When not set, 30 GB are used.",
+							Optional:            true,
+						},
+						"state_disk_type": schema.StringAttribute{
+							MarkdownDescription: "Type of the nodes' state disk. When not set, the CSP's default disk type is used.",
+							Description:         "Type of the nodes' state disk. When not set, the CSP's default disk type is used.",
+							Optional:            true,
+						},
+						"zone": schema.StringAttribute{
+							MarkdownDescription: "Zone the nodes are created in. When not set, the zone of the CSP configuration is used.",
+							Description:         "Zone the nodes are created in. When not set, the zone of the CSP configuration is used.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"internal_load_balancer": schema.BoolAttribute{
+				MarkdownDescription: "Use an internal load balancer for the Kubernetes API server. Defaults to `false`.",
+				Description:         "Use an internal load balancer for the Kubernetes API server. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"aws": schema.SingleNestedAttribute{
+				MarkdownDescription: "AWS-specific configuration.",
+				Description:         "AWS-specific configuration.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"region": schema.StringAttribute{
+						MarkdownDescription: "AWS region the cluster is created in.",
+						Description:         "AWS region the cluster is created in.",
+						Required:            true,
+					},
+					"zone": schema.StringAttribute{
+						MarkdownDescription: "AWS availability zone the cluster is created in.",
+						Description:         "AWS availability zone the cluster is created in.",
+						Required:            true,
+					},
+					"control_plane_instance_profile": schema.StringAttribute{
+						MarkdownDescription: "Name of the instance profile for control-plane nodes. " +
+							"Use the `control_plane_instance_profile` output of the `constellation_iam` resource.",
+						Description: "Name of the instance profile for control-plane nodes.",
+						Required:    true,
+					},
+					"worker_nodes_instance_profile": schema.StringAttribute{
+						MarkdownDescription: "Name of the instance profile for worker nodes. " +
+							"Use the `worker_nodes_instance_profile` output of the `constellation_iam` resource.",
+						Description: "Name of the instance profile for worker nodes.",
+						Required:    true,
+					},
+				},
+			},
+			"azure": schema.SingleNestedAttribute{
+				MarkdownDescription: "Azure-specific configuration.",
+				Description:         "Azure-specific configuration.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"location": schema.StringAttribute{
+						MarkdownDescription: "Azure location the cluster is created in.",
+						Description:         "Azure location the cluster is created in.",
+						Required:            true,
+					},
+					"resource_group": schema.StringAttribute{
+						MarkdownDescription: "Name of the Azure resource group the cluster is created in.",
+						Description:         "Name of the Azure resource group the cluster is created in.",
+						Required:            true,
+					},
+					"subscription_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the Azure subscription the cluster is created in.",
+						Description:         "ID of the Azure subscription the cluster is created in.",
+						Required:            true,
+					},
+					"tenant_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the Azure tenant the cluster is created in.",
+						Description:         "ID of the Azure tenant the cluster is created in.",
+						Required:            true,
+					},
+					"uami_id": schema.StringAttribute{
+						MarkdownDescription: "Resource ID of the user assigned managed identity (UAMI) used within the cluster. " +
+							"Use the `uami_id` output of the `constellation_iam` resource.",
+						Description: "Resource ID of the user assigned managed identity (UAMI) used within the cluster.",
+						Required:    true,
+					},
+					"secure_boot": schema.BoolAttribute{
+						MarkdownDescription: "Enable secure boot for the cluster's VMs. Defaults to `false`.",
+						Description:         "Enable secure boot for the cluster's VMs. Defaults to false.",
+						Optional:            true,
+					},
+				},
+			},
+			"gcp": schema.SingleNestedAttribute{
+				MarkdownDescription: "GCP-specific configuration.",
+				Description:         "GCP-specific configuration.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"project_id": schema.StringAttribute{
+						MarkdownDescription: "ID of the GCP project the cluster is created in.",
+						Description:         "ID of the GCP project the cluster is created in.",
+						Required:            true,
+					},
+					"region": schema.StringAttribute{
+						MarkdownDescription: "GCP region the cluster is created in.",
+						Description:         "GCP region the cluster is created in.",
+						Required:            true,
+					},
+					"zone": schema.StringAttribute{
+						MarkdownDescription: "GCP zone the cluster is created in.",
+						Description:         "GCP zone the cluster is created in.",
+						Required:            true,
+					},
+				},
+			},
+
+			// Computed (output) attributes
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "The UID of the cluster.",
+				Description:         "The UID of the cluster.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cluster_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the cluster's cloud resources, consisting of the `name` and a random suffix.",
+				Description:         "The name of the cluster's cloud resources, consisting of the name and a random suffix.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"init_secret": schema.StringAttribute{
+				MarkdownDescription: "Secret used for initialization of the cluster.",
+				Description:         "Secret used for initialization of the cluster.",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"out_of_cluster_endpoint": schema.StringAttribute{
+				MarkdownDescription: "The endpoint of the cluster, reachable from outside the cluster's network.",
+				Description:         "The endpoint of the cluster, reachable from outside the cluster's network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"in_cluster_endpoint": schema.StringAttribute{
+				MarkdownDescription: "The endpoint of the cluster within the cluster's network.",
+				Description:         "The endpoint of the cluster within the cluster's network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"api_server_cert_sans": schema.ListAttribute{
+				MarkdownDescription: "List of Subject Alternative Names (SANs) for the API server certificate.",
+				Description:         "List of Subject Alternative Names (SANs) for the API server certificate.",
+				ElementType:         types.StringType,
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ip_cidr_node": schema.StringAttribute{
+				MarkdownDescription: "CIDR range of the cluster's node network.",
+				Description:         "CIDR range of the cluster's node network.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ip_cidr_pod": schema.StringAttribute{
+				MarkdownDescription: "CIDR range of the cluster's pod network. Only set for GCP.",
+				Description:         "CIDR range of the cluster's pod network. Only set for GCP.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"attestation_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the Microsoft Azure Attestation (MAA) provider. Only set for Azure.",
+				Description:         "URL of the Microsoft Azure Attestation (MAA) provider. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"uami_client_id": schema.StringAttribute{
+				MarkdownDescription: "Client ID of the user assigned managed identity (UAMI) used within the cluster. Only set for Azure.",
+				Description:         "Client ID of the user assigned managed identity (UAMI) used within the cluster. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"network_security_group_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the Azure network security group used for the cluster. Only set for Azure.",
+				Description:         "Name of the Azure network security group used for the cluster. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"load_balancer_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the Azure load balancer used by the cluster. Only set for Azure.",
+				Description:         "Name of the Azure load balancer used by the cluster. Only set for Azure.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig validates the configuration for the resource.
+func (r *InfrastructureResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data InfrastructureResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateCSPConfig(data.CSP, map[cloudprovider.Provider]types.Object{
+		cloudprovider.AWS:   data.AWS,
+		cloudprovider.Azure: data.Azure,
+		cloudprovider.GCP:   data.GCP,
+	})...)
+
+	if data.NodeGroups.IsUnknown() || data.NodeGroups.IsNull() {
+		return
+	}
+	var hasControlPlane, hasWorker bool
+	for _, group := range data.NodeGroups.Elements() {
+		groupObj, ok := group.(types.Object)
+		if !ok {
+			continue
+		}
+		nodeRole, ok := groupObj.Attributes()["role"].(types.String)
+		if !ok || nodeRole.IsUnknown() {
+			// Role might only be known after apply, so we can't validate the groups.
+			return
+		}
+		switch role.FromString(nodeRole.ValueString()) {
+		case role.ControlPlane:
+			hasControlPlane = true
+		case role.Worker:
+			hasWorker = true
+		}
+	}
+	if !hasControlPlane || !hasWorker {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("node_groups"),
+			"Invalid node groups",
+			"At least one node group with role 'control-plane' and one node group with role 'worker' must be set.",
+		)
+	}
+}
+
+// Create is called when the resource is created.
+func (r *InfrastructureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read data supplied by Terraform runtime into the model
+	var data InfrastructureResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, cloudcmd.WithRollbackOnError)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *InfrastructureResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data InfrastructureResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	resp.Diagnostics.Append(checkWorkspace(workspace, "infrastructure")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	applier, cleanup, diags := newInfrastructureApplier(ctx, workspace, os.TempDir())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cleanup()
+
+	infraState, err := applier.Show(ctx, cloudprovider.FromString(data.CSP.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Reading infrastructure", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(setInfrastructureOutput(ctx, &data, infraState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource.
+func (r *InfrastructureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read data supplied by Terraform runtime into the model
+	var data InfrastructureResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &data, cloudcmd.WithoutRollbackOnError)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete destroys the resource.
+func (r *InfrastructureResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data InfrastructureResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := cloudcmd.NewTerminator().Terminate(ctx, data.Workspace.ValueString(), terraform.LogLevelNone); err != nil {
+		resp.Diagnostics.AddError("Destroying infrastructure", err.Error())
+		return
+	}
+}
+
+// ImportState imports to the resource.
+func (r *InfrastructureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	csp, workspace, diags := parseWorkspaceImportURI(req.ID, constants.ConstellationInfrastructureURIScheme)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("csp"), csp)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace"), workspace)...)
+}
+
+// apply plans and applies the infrastructure described by the data model,
+// and writes the resulting outputs back to it.
+func (r *InfrastructureResource) apply(ctx context.Context, data *InfrastructureResourceModel, rollback cloudcmd.RollbackBehavior) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	conf, convertDiags := r.toConfig(ctx, data)
+	diags.Append(convertDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	backupDir, err := os.MkdirTemp("", "constellation-infrastructure-")
+	if err != nil {
+		diags.AddError("Creating backup directory", err.Error())
+		return diags
+	}
+	defer os.RemoveAll(backupDir)
+
+	applier, cleanup, applierDiags := newInfrastructureApplier(ctx, data.Workspace.ValueString(), backupDir)
+	diags.Append(applierDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	defer cleanup()
+
+	if _, err := applier.Plan(ctx, conf); err != nil {
+		diags.AddError("Planning infrastructure", err.Error())
+		return diags
+	}
+	infraState, err := applier.Apply(ctx, conf.GetProvider(), rollback)
+	if err != nil {
+		diags.AddError("Applying infrastructure", err.Error())
+		return diags
+	}
+
+	diags.Append(setInfrastructureOutput(ctx, data, infraState)...)
+	return diags
+}
+
+// toConfig converts the data model to a Constellation config.
+func (r *InfrastructureResource) toConfig(ctx context.Context, data *InfrastructureResourceModel) (*config.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	csp := cloudprovider.FromString(data.CSP.ValueString())
+
+	var image imageAttribute
+	diags.Append(data.Image.As(ctx, &image, basetypes.ObjectAsOptions{})...)
+	var nodeGroups map[string]nodeGroupAttribute
+	diags.Append(data.NodeGroups.ElementsAs(ctx, &nodeGroups, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	attestationVariant, err := variant.FromString(data.AttestationVariant.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("attestation_variant"),
+			"Invalid Attestation Variant",
+			fmt.Sprintf("Invalid attestation variant: %s", data.AttestationVariant.ValueString()))
+		return nil, diags
+	}
+
+	conf := config.Default()
+	conf.Name = data.Name.ValueString()
+	conf.Image = image.ShortPath
+	conf.InternalLoadBalancer = data.InternalLoadBalancer.ValueBool()
+	conf.NodeGroups = make(map[string]config.NodeGroup, len(nodeGroups))
+	for name, group := range nodeGroups {
+		stateDiskSizeGB := 30
+		if !group.StateDiskSizeGB.IsNull() {
+			stateDiskSizeGB = int(group.StateDiskSizeGB.ValueInt64())
+		}
+		conf.NodeGroups[name] = config.NodeGroup{
+			Role:            group.Role,
+			InitialCount:    group.InitialCount,
+			InstanceType:    group.InstanceType.ValueString(),
+			StateDiskSizeGB: stateDiskSizeGB,
+			StateDiskType:   group.StateDiskType.ValueString(),
+			Zone:            group.Zone.ValueString(),
+		}
+	}
+
+	// Optional fields of the CSP configuration are converted to their zero value when unset.
+	opts := basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true}
+	switch csp {
+	case cloudprovider.AWS:
+		var awsConfig awsInfrastructureAttribute
+		diags.Append(data.AWS.As(ctx, &awsConfig, opts)...)
+		conf.Provider.AWS.Region = awsConfig.Region
+		conf.Provider.AWS.Zone = awsConfig.Zone
+		conf.Provider.AWS.IAMProfileControlPlane = awsConfig.ControlPlaneInstanceProfile
+		conf.Provider.AWS.IAMProfileWorkerNodes = awsConfig.WorkerNodesInstanceProfile
+	case cloudprovider.Azure:
+		var azureConfig azureInfrastructureAttribute
+		diags.Append(data.Azure.As(ctx, &azureConfig, opts)...)
+		conf.Provider.Azure.Location = azureConfig.Location
+		conf.Provider.Azure.ResourceGroup = azureConfig.ResourceGroup
+		conf.Provider.Azure.SubscriptionID = azureConfig.SubscriptionID
+		conf.Provider.Azure.TenantID = azureConfig.TenantID
+		conf.Provider.Azure.UserAssignedIdentity = azureConfig.UAMIID
+		conf.Provider.Azure.SecureBoot = &azureConfig.SecureBoot
+	case cloudprovider.GCP:
+		var gcpConfig gcpInfrastructureAttribute
+		diags.Append(data.GCP.As(ctx, &gcpConfig, opts)...)
+		conf.Provider.GCP.Project = gcpConfig.ProjectID
+		conf.Provider.GCP.Region = gcpConfig.Region
+		conf.Provider.GCP.Zone = gcpConfig.Zone
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	conf.RemoveProviderExcept(csp)
+	conf.SetAttestation(attestationVariant)
+	return conf, diags
+}
+
+// newInfrastructureApplier creates an applier operating on the given Terraform workspace.
+func newInfrastructureApplier(ctx context.Context, workspace, backupDir string) (*cloudcmd.Applier, func(), diag.Diagnostics) {
+	var diags diag.Diagnostics
+	applier, cleanup, err := cloudcmd.NewApplier(
+		ctx, &tfLogWriter{ctx: ctx}, workspace, backupDir,
+		terraform.LogLevelNone, file.NewHandler(afero.NewOsFs()),
+	)
+	if err != nil {
+		diags.AddError("Setting up Terraform", err.Error())
+		return nil, nil, diags
+	}
+	return applier, cleanup, diags
+}
+
+// setInfrastructureOutput writes the outputs of the infrastructure to the data model.
+func setInfrastructureOutput(ctx context.Context, data *InfrastructureResourceModel, infraState state.Infrastructure) diag.Diagnostics {
+	var diags diag.Diagnostics
+	data.UID = types.StringValue(infraState.UID)
+	data.ClusterName = types.StringValue(infraState.Name)
+	data.InitSecret = types.StringValue(string(infraState.InitSecret))
+	data.OutOfClusterEndpoint = types.StringValue(infraState.ClusterEndpoint)
+	data.InClusterEndpoint = types.StringValue(infraState.InClusterEndpoint)
+	data.IPCidrNode = types.StringValue(infraState.IPCidrNode)
+	data.APIServerCertSANs, diags = types.ListValueFrom(ctx, types.StringType, infraState.APIServerCertSANs)
+
+	data.IPCidrPod = types.StringNull()
+	if infraState.GCP != nil {
+		data.IPCidrPod = types.StringValue(infraState.GCP.IPCidrPod)
+	}
+
+	data.AttestationURL = types.StringNull()
+	data.UAMIClientID = types.StringNull()
+	data.NetworkSecurityGroupName = types.StringNull()
+	data.LoadBalancerName = types.StringNull()
+	if infraState.Azure != nil {
+		data.AttestationURL = types.StringValue(infraState.Azure.AttestationURL)
+		data.UAMIClientID = types.StringValue(infraState.Azure.UserAssignedIdentity)
+		data.NetworkSecurityGroupName = types.StringValue(infraState.Azure.NetworkSecurityGroupName)
+		data.LoadBalancerName = types.StringValue(infraState.Azure.LoadBalancerName)
+	}
+	return diags
+}
@@ -0,0 +1,274 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/cloud/cloudprovider"
+	"github.com/edgelesssys/constellation/v2/internal/constellation/state"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfrastructureToConfig(t *testing.T) {
+	nodeGroupTypes := map[string]attr.Type{
+		"role":               types.StringType,
+		"initial_count":      types.Int64Type,
+		"instance_type":      types.StringType,
+		"state_disk_size_gb": types.Int64Type,
+		"state_disk_type":    types.StringType,
+		"zone":               types.StringType,
+	}
+	nodeGroups := types.MapValueMust(types.ObjectType{AttrTypes: nodeGroupTypes}, map[string]attr.Value{
+		"control_plane_default": types.ObjectValueMust(nodeGroupTypes, map[string]attr.Value{
+			"role":               types.StringValue("control-plane"),
+			"initial_count":      types.Int64Value(3),
+			"instance_type":      types.StringNull(),
+			"state_disk_size_gb": types.Int64Null(),
+			"state_disk_type":    types.StringNull(),
+			"zone":               types.StringNull(),
+		}),
+		"worker_default": types.ObjectValueMust(nodeGroupTypes, map[string]attr.Value{
+			"role":               types.StringValue("worker"),
+			"initial_count":      types.Int64Value(2),
+			"instance_type":      types.StringValue("n2d-standard-8"),
+			"state_disk_size_gb": types.Int64Value(50),
+			"state_disk_type":    types.StringValue("pd-balanced"),
+			"zone":               types.StringValue("europe-west3-b"),
+		}),
+	})
+	image := types.ObjectValueMust(map[string]attr.Type{
+		"version":    types.StringType,
+		"reference":  types.StringType,
+		"short_path": types.StringType,
+	}, map[string]attr.Value{
+		"version":    types.StringValue("v2.13.0"),
+		"reference":  types.StringValue("projects/constellation-images/global/images/v2-13-0-gcp-sev-es-stable"),
+		"short_path": types.StringValue("v2.13.0"),
+	})
+	gcp := types.ObjectValueMust(map[string]attr.Type{
+		"project_id": types.StringType,
+		"region":     types.StringType,
+		"zone":       types.StringType,
+	}, map[string]attr.Value{
+		"project_id": types.StringValue("project"),
+		"region":     types.StringValue("europe-west3"),
+		"zone":       types.StringValue("europe-west3-a"),
+	})
+
+	testCases := map[string]struct {
+		variant string
+		wantErr bool
+	}{
+		"success": {
+			variant: "gcp-sev-es",
+		},
+		"invalid variant": {
+			variant: "invalid",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			data := &InfrastructureResourceModel{
+				CSP:                  types.StringValue("gcp"),
+				Name:                 types.StringValue("test"),
+				Image:                image,
+				AttestationVariant:   types.StringValue(tc.variant),
+				NodeGroups:           nodeGroups,
+				InternalLoadBalancer: types.BoolValue(true),
+				GCP:                  gcp,
+			}
+
+			conf, diags := (&InfrastructureResource{}).toConfig(context.Background(), data)
+			if tc.wantErr {
+				assert.True(diags.HasError())
+				return
+			}
+			require.False(diags.HasError(), diags)
+
+			assert.Equal("test", conf.Name)
+			assert.Equal("v2.13.0", conf.Image)
+			assert.True(conf.InternalLoadBalancer)
+			assert.Equal(cloudprovider.GCP, conf.GetProvider())
+			assert.Equal(variant.GCPSEVES{}, conf.GetAttestationConfig().GetVariant())
+			assert.Equal("project", conf.Provider.GCP.Project)
+			assert.Equal("europe-west3", conf.Provider.GCP.Region)
+			assert.Equal("europe-west3-a", conf.Provider.GCP.Zone)
+			assert.Nil(conf.Provider.AWS)
+			assert.Nil(conf.Provider.Azure)
+
+			require.Len(conf.NodeGroups, 2)
+			controlPlane := conf.NodeGroups["control_plane_default"]
+			assert.Equal("control-plane", controlPlane.Role)
+			assert.Equal(3, controlPlane.InitialCount)
+			assert.Equal("n2d-standard-4", controlPlane.InstanceType) // CSP default
+			assert.Equal(30, controlPlane.StateDiskSizeGB)
+			assert.Equal("pd-ssd", controlPlane.StateDiskType) // CSP default
+			assert.Equal("europe-west3-a", controlPlane.Zone)  // zone of the GCP config
+			worker := conf.NodeGroups["worker_default"]
+			assert.Equal("worker", worker.Role)
+			assert.Equal(2, worker.InitialCount)
+			assert.Equal("n2d-standard-8", worker.InstanceType)
+			assert.Equal(50, worker.StateDiskSizeGB)
+			assert.Equal("pd-balanced", worker.StateDiskType)
+			assert.Equal("europe-west3-b", worker.Zone)
+		})
+	}
+}
+
+func TestSetInfrastructureOutput(t *testing.T) {
+	testCases := map[string]struct {
+		infra         state.Infrastructure
+		wantIPCidrPod types.String
+		wantLBName    types.String
+	}{
+		"gcp": {
+			infra: state.Infrastructure{
+				UID:               "uid",
+				Name:              "test-uid",
+				ClusterEndpoint:   "192.0.2.1",
+				InClusterEndpoint: "192.0.2.2",
+				InitSecret:        []byte("secret"),
+				APIServerCertSANs: []string{"192.0.2.1", "example.com"},
+				IPCidrNode:        "10.9.0.0/16",
+				GCP:               &state.GCP{ProjectID: "project", IPCidrPod: "10.10.0.0/16"},
+			},
+			wantIPCidrPod: types.StringValue("10.10.0.0/16"),
+			wantLBName:    types.StringNull(),
+		},
+		"azure": {
+			infra: state.Infrastructure{
+				UID:               "uid",
+				Name:              "test-uid",
+				ClusterEndpoint:   "192.0.2.1",
+				InClusterEndpoint: "192.0.2.2",
+				InitSecret:        []byte("secret"),
+				APIServerCertSANs: []string{"192.0.2.1", "example.com"},
+				IPCidrNode:        "10.9.0.0/16",
+				Azure:             &state.Azure{LoadBalancerName: "lb"},
+			},
+			wantIPCidrPod: types.StringNull(),
+			wantLBName:    types.StringValue("lb"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			require := require.New(t)
+
+			data := &InfrastructureResourceModel{}
+			diags := setInfrastructureOutput(context.Background(), data, tc.infra)
+			require.False(diags.HasError())
+
+			assert.Equal("uid", data.UID.ValueString())
+			assert.Equal("test-uid", data.ClusterName.ValueString())
+			assert.Equal("192.0.2.1", data.OutOfClusterEndpoint.ValueString())
+			assert.Equal("192.0.2.2", data.InClusterEndpoint.ValueString())
+			assert.Equal("secret", data.InitSecret.ValueString())
+			assert.Equal("10.9.0.0/16", data.IPCidrNode.ValueString())
+			var sans []string
+			require.False(data.APIServerCertSANs.ElementsAs(context.Background(), &sans, false).HasError())
+			assert.Equal(tc.infra.APIServerCertSANs, sans)
+			assert.Equal(tc.wantIPCidrPod, data.IPCidrPod)
+			assert.Equal(tc.wantLBName, data.LoadBalancerName)
+		})
+	}
+}
+
+func TestAccInfrastructureResource(t *testing.T) {
+	// Set the path to the Terraform binary for acceptance testing when running under Bazel.
+	bazelPreCheck := func() { bazelSetTerraformBinaryPath(t) }
+
+	testCases := map[string]resource.TestCase{
+		"csp config missing": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + infrastructureTestingConfig(`
+						node_groups = {
+							control_plane_default = { role = "control-plane", initial_count = 1 }
+							worker_default        = { role = "worker", initial_count = 1 }
+						}
+					`),
+					ExpectError: regexp.MustCompile(".*the 'aws' configuration must be set.*"),
+				},
+			},
+		},
+		"worker group missing": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + infrastructureTestingConfig(`
+						node_groups = {
+							control_plane_default = { role = "control-plane", initial_count = 1 }
+						}
+						aws = {
+							region                         = "eu-central-1"
+							zone                           = "eu-central-1a"
+							control_plane_instance_profile = "control-plane"
+							worker_nodes_instance_profile  = "worker"
+						}
+					`),
+					ExpectError: regexp.MustCompile(".*At least one node group with role 'control-plane' and one node group.*"),
+				},
+			},
+		},
+		"import invalid uri": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					resource "constellation_infrastructure" "test" {}
+					`,
+					ResourceName:  "constellation_infrastructure.test",
+					ImportState:   true,
+					ImportStateId: "constellation-iam://?csp=aws&workspace=infrastructure",
+					ExpectError:   regexp.MustCompile(".*Invalid scheme.*"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, tc)
+		})
+	}
+}
+
+func infrastructureTestingConfig(extra string) string {
+	return `
+	resource "constellation_infrastructure" "test" {
+		workspace           = "infrastructure"
+		csp                 = "aws"
+		name                = "test"
+		attestation_variant = "aws-sev-snp"
+		image = {
+			version    = "v2.13.0"
+			reference  = "ami-04f8d379e7d5f6a16"
+			short_path = "v2.13.0"
+		}
+	` + extra + `
+	}
+	`
+}
@@ -0,0 +1,302 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/choose"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/variant"
+	"github.com/edgelesssys/constellation/v2/internal/constants"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &NodeAttestationDataSource{}
+
+// NewNodeAttestationDataSource creates a new node attestation data source.
+func NewNodeAttestationDataSource() datasource.DataSource {
+	return &NodeAttestationDataSource{
+		newVerifier: func(ctx context.Context) nodeVerifier {
+			return constellation.NewApplier(&tfContextLogger{ctx: ctx}, nil, constellation.ApplyContextTerraform, constellation.DefaultDialer)
+		},
+	}
+}
+
+// NodeAttestationDataSource defines the data source implementation.
+type NodeAttestationDataSource struct {
+	newVerifier func(ctx context.Context) nodeVerifier
+}
+
+// nodeVerifier fetches and validates the attestation document of a node.
+type nodeVerifier interface {
+	Verify(ctx context.Context, validator atls.Validator, opts constellation.VerifyOptions) (constellation.VerifyOutput, error)
+}
+
+// NodeAttestationDataSourceModel describes the data source data model.
+type NodeAttestationDataSourceModel struct {
+	Endpoints        types.List   `tfsdk:"endpoints"`
+	Attestation      types.Object `tfsdk:"attestation"`
+	OwnerID          types.String `tfsdk:"owner_id"`
+	ClusterID        types.String `tfsdk:"cluster_id"`
+	FailOnError      types.Bool   `tfsdk:"fail_on_error"`
+	Verified         types.Bool   `tfsdk:"verified"`
+	NodeAttestations types.List   `tfsdk:"nodes"`
+}
+
+// nodeAttestationAttribute is the data model of the attestation result of a single node.
+type nodeAttestationAttribute struct {
+	Endpoint     string            `tfsdk:"endpoint"`
+	Verified     bool              `tfsdk:"verified"`
+	Error        string            `tfsdk:"error"`
+	Measurements map[string]string `tfsdk:"measurements"`
+}
+
+var nodeAttestationAttributeTypes = map[string]attr.Type{
+	"endpoint":     types.StringType,
+	"verified":     types.BoolType,
+	"error":        types.StringType,
+	"measurements": types.MapType{ElemType: types.StringType},
+}
+
+// Metadata returns the metadata for the data source.
+func (d *NodeAttestationDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node_attestation"
+}
+
+// Schema returns the schema for the data source.
+func (d *NodeAttestationDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Data source to verify the attestation of running Constellation nodes.",
+		MarkdownDescription: "Data source to verify the attestation of running Constellation nodes. " +
+			"Each endpoint is asked for an attestation document, which is validated against the given attestation config. " +
+			"By default, reading the data source fails if any endpoint doesn't attest correctly, so resources depending on it aren't deployed.",
+
+		Attributes: map[string]schema.Attribute{
+			"endpoints": schema.ListAttribute{
+				MarkdownDescription: "Endpoints of the verification service to attest. " +
+					"Either the IP address of single nodes, or the `out_of_cluster_endpoint` of the cluster's load balancer. " +
+					fmt.Sprintf("When no port is given, the verification service's node port `%d` is used.", constants.VerifyServiceNodePortGRPC),
+				Description: "Endpoints of the verification service to attest.",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"attestation": newAttestationConfigAttributeSchema(attributeInput),
+			"owner_id": schema.StringAttribute{
+				MarkdownDescription: "The owner ID of the cluster, as output by the `constellation_cluster` resource. " +
+					"When not set, the owner ID measurement isn't validated. At least one of `owner_id` and `cluster_id` must be set.",
+				Description: "The owner ID of the cluster. When not set, the owner ID measurement isn't validated. " +
+					"At least one of owner_id and cluster_id must be set.",
+				Optional: true,
+			},
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "The cluster ID of the cluster, as output by the `constellation_cluster` resource. " +
+					"When not set, the cluster ID measurement isn't validated. At least one of `owner_id` and `cluster_id` must be set.",
+				Description: "The cluster ID of the cluster. When not set, the cluster ID measurement isn't validated. " +
+					"At least one of owner_id and cluster_id must be set.",
+				Optional: true,
+			},
+			"fail_on_error": schema.BoolAttribute{
+				MarkdownDescription: "Fail reading the data source if any endpoint doesn't attest correctly. Defaults to `true`. " +
+					"When set to `false`, the verdict is only reported through the `verified` outputs.",
+				Description: "Fail reading the data source if any endpoint doesn't attest correctly. Defaults to true.",
+				Optional:    true,
+			},
+			"verified": schema.BoolAttribute{
+				MarkdownDescription: "Whether all endpoints attested correctly.",
+				Description:         "Whether all endpoints attested correctly.",
+				Computed:            true,
+			},
+			"nodes": schema.ListNestedAttribute{
+				MarkdownDescription: "Attestation results, in the order of `endpoints`.",
+				Description:         "Attestation results, in the order of endpoints.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"endpoint": schema.StringAttribute{
+							MarkdownDescription: "The attested endpoint, including the port.",
+							Description:         "The attested endpoint, including the port.",
+							Computed:            true,
+						},
+						"verified": schema.BoolAttribute{
+							MarkdownDescription: "Whether the endpoint attested correctly.",
+							Description:         "Whether the endpoint attested correctly.",
+							Computed:            true,
+						},
+						"error": schema.StringAttribute{
+							MarkdownDescription: "The reason the attestation failed. Empty if the endpoint attested correctly.",
+							Description:         "The reason the attestation failed. Empty if the endpoint attested correctly.",
+							Computed:            true,
+						},
+						"measurements": schema.MapAttribute{
+							MarkdownDescription: "The hex-encoded measurements reported by the node, keyed by their index.",
+							Description:         "The hex-encoded measurements reported by the node, keyed by their index.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Read reads from the data source.
+func (d *NodeAttestationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data NodeAttestationDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without either ID, the attestation wouldn't be bound to the cluster
+	if data.OwnerID.ValueString() == "" && data.ClusterID.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cluster_id"),
+			"Missing Cluster ID",
+			"At least one of owner_id and cluster_id must be set to verify the cluster.",
+		)
+		return
+	}
+
+	var endpoints []string
+	resp.Diagnostics.Append(data.Endpoints.ElementsAs(ctx, &endpoints, false)...)
+	var tfAttestation attestationAttribute
+	resp.Diagnostics.Append(data.Attestation.As(ctx, &tfAttestation, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attestationVariant, err := variant.FromString(tfAttestation.Variant)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("attestation").AtName("variant"),
+			"Invalid Attestation Variant",
+			fmt.Sprintf("Invalid attestation variant: %s", tfAttestation.Variant),
+		)
+		return
+	}
+	attestationCfg, err := convertFromTfAttestationCfg(tfAttestation, attestationVariant)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("attestation"),
+			"Invalid Attestation Config",
+			fmt.Sprintf("Parsing attestation config: %s", err),
+		)
+		return
+	}
+	if err := constellation.UpdateInitMeasurements(attestationCfg, data.OwnerID.ValueString(), data.ClusterID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Updating expected measurements", err.Error())
+		return
+	}
+
+	validator, err := choose.Validator(attestationCfg, &tfContextLogger{ctx: ctx})
+	if err != nil {
+		resp.Diagnostics.AddError("Choosing validator", err.Error())
+		return
+	}
+
+	nodes, verified := verifyNodes(ctx, d.newVerifier(ctx), validator, endpoints)
+	failOnError := data.FailOnError.IsNull() || data.FailOnError.ValueBool()
+	for _, node := range nodes {
+		if node.Verified {
+			continue
+		}
+		if failOnError {
+			resp.Diagnostics.AddError("Verifying node attestation", fmt.Sprintf("Verifying %s: %s", node.Endpoint, node.Error))
+		} else {
+			resp.Diagnostics.AddWarning("Verifying node attestation", fmt.Sprintf("Verifying %s: %s", node.Endpoint, node.Error))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	data.Verified = types.BoolValue(verified)
+	data.NodeAttestations, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: nodeAttestationAttributeTypes}, nodes)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	tflog.Trace(ctx, "read constellation node attestation data source")
+}
+
+// verifyNodes verifies the attestation of every endpoint, and reports whether all of them attested correctly.
+func verifyNodes(ctx context.Context, verifier nodeVerifier, validator atls.Validator, endpoints []string) ([]nodeAttestationAttribute, bool) {
+	allVerified := true
+	nodes := make([]nodeAttestationAttribute, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		node := verifyNode(ctx, verifier, validator, endpoint)
+		allVerified = allVerified && node.Verified
+		nodes = append(nodes, node)
+	}
+	return nodes, allVerified
+}
+
+// verifyNode verifies the attestation of a single endpoint.
+func verifyNode(ctx context.Context, verifier nodeVerifier, validator atls.Validator, endpoint string) nodeAttestationAttribute {
+	node := nodeAttestationAttribute{Endpoint: endpoint, Measurements: map[string]string{}}
+
+	endpoint, err := endpointWithDefaultPort(endpoint, constants.VerifyServiceNodePortGRPC)
+	if err != nil {
+		node.Error = fmt.Sprintf("invalid endpoint: %s", err)
+		return node
+	}
+	node.Endpoint = endpoint
+
+	out, err := verifier.Verify(ctx, validator, constellation.VerifyOptions{Endpoint: endpoint})
+	if err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	node.Verified = true
+
+	measurements, err := out.Measurements()
+	if err != nil {
+		// The attestation was validated, so failing to parse the quoted values for the output isn't fatal.
+		tflog.Warn(ctx, fmt.Sprintf("Parsing measurements of %s: %s", endpoint, err))
+		return node
+	}
+	for idx, value := range measurements {
+		node.Measurements[strconv.FormatUint(uint64(idx), 10)] = hex.EncodeToString(value)
+	}
+	return node
+}
+
+// endpointWithDefaultPort returns the endpoint, with the default port appended if it has none.
+func endpointWithDefaultPort(endpoint string, defaultPort int) (string, error) {
+	if endpoint == "" {
+		return "", errors.New("endpoint is empty")
+	}
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		if !strings.Contains(err.Error(), "missing port in address") {
+			return "", err
+		}
+		return net.JoinHostPort(endpoint, strconv.Itoa(defaultPort)), nil
+	}
+	return endpoint, nil
+}
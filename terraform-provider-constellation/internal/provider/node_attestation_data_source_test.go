@@ -0,0 +1,208 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/atls"
+	"github.com/edgelesssys/constellation/v2/internal/attestation/vtpm"
+	"github.com/edgelesssys/constellation/v2/pkg/constellation"
+	"github.com/google/go-tpm-tools/proto/attest"
+	tpmProto "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyNodes(t *testing.T) {
+	attDoc, err := json.Marshal(vtpm.AttestationDocument{
+		Attestation: &attest.Attestation{
+			Quotes: []*tpmProto.Quote{{
+				Pcrs: &tpmProto.PCRs{
+					Hash: tpmProto.HashAlgo_SHA256,
+					Pcrs: map[uint32][]byte{4: {0xab, 0xcd}},
+				},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		endpoints    []string
+		verifier     *stubNodeVerifier
+		wantNodes    []nodeAttestationAttribute
+		wantVerified bool
+	}{
+		"all nodes verified": {
+			endpoints: []string{"192.0.2.1", "192.0.2.2:9090"},
+			verifier:  &stubNodeVerifier{out: constellation.VerifyOutput{AttestationDocument: attDoc}},
+			wantNodes: []nodeAttestationAttribute{
+				{Endpoint: "192.0.2.1:30081", Verified: true, Measurements: map[string]string{"4": "abcd"}},
+				{Endpoint: "192.0.2.2:9090", Verified: true, Measurements: map[string]string{"4": "abcd"}},
+			},
+			wantVerified: true,
+		},
+		"verification fails": {
+			endpoints: []string{"192.0.2.1"},
+			verifier:  &stubNodeVerifier{err: errors.New("validating attestation: measurement mismatch")},
+			wantNodes: []nodeAttestationAttribute{
+				{Endpoint: "192.0.2.1:30081", Error: "validating attestation: measurement mismatch", Measurements: map[string]string{}},
+			},
+		},
+		"measurements not parsable": {
+			endpoints: []string{"192.0.2.1"},
+			verifier:  &stubNodeVerifier{out: constellation.VerifyOutput{AttestationDocument: []byte("{}")}},
+			wantNodes: []nodeAttestationAttribute{
+				{Endpoint: "192.0.2.1:30081", Verified: true, Measurements: map[string]string{}},
+			},
+			wantVerified: true,
+		},
+		"invalid endpoint": {
+			endpoints: []string{"192.0.2.1", ""},
+			verifier:  &stubNodeVerifier{out: constellation.VerifyOutput{AttestationDocument: attDoc}},
+			wantNodes: []nodeAttestationAttribute{
+				{Endpoint: "192.0.2.1:30081", Verified: true, Measurements: map[string]string{"4": "abcd"}},
+				{Endpoint: "", Error: "invalid endpoint: endpoint is empty", Measurements: map[string]string{}},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			nodes, verified := verifyNodes(context.Background(), tc.verifier, nil, tc.endpoints)
+			assert.Equal(tc.wantNodes, nodes)
+			assert.Equal(tc.wantVerified, verified)
+		})
+	}
+}
+
+func TestEndpointWithDefaultPort(t *testing.T) {
+	testCases := map[string]struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		"ip without port": {
+			endpoint: "192.0.2.1",
+			want:     "192.0.2.1:30081",
+		},
+		"ip with port": {
+			endpoint: "192.0.2.1:9090",
+			want:     "192.0.2.1:9090",
+		},
+		"hostname without port": {
+			endpoint: "example.com",
+			want:     "example.com:30081",
+		},
+		"ipv6 without port": {
+			endpoint: "2001:db8::1",
+			wantErr:  true,
+		},
+		"bracketed ipv6 with port": {
+			endpoint: "[2001:db8::1]:9090",
+			want:     "[2001:db8::1]:9090",
+		},
+		"empty": {
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := endpointWithDefaultPort(tc.endpoint, 30081)
+			if tc.wantErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tc.want, got)
+		})
+	}
+}
+
+func TestAccNodeAttestationSource(t *testing.T) {
+	// Set the path to the Terraform binary for acceptance testing when running under Bazel.
+	bazelPreCheck := func() { bazelSetTerraformBinaryPath(t) }
+
+	testCases := map[string]resource.TestCase{
+		"unreachable endpoint": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					data "constellation_attestation" "test" {
+						csp = "gcp"
+						attestation_variant = "gcp-sev-es"
+						image = {
+							version = "v2.13.0"
+							reference = "v2.13.0"
+							short_path = "v2.13.0"
+						}
+					}
+
+					data "constellation_node_attestation" "test" {
+						endpoints   = ["127.0.0.1:1"]
+						attestation = data.constellation_attestation.test.attestation
+						cluster_id  = "0000000000000000000000000000000000000000000000000000000000000000"
+					}
+					`,
+					ExpectError: regexp.MustCompile(".*Verifying node attestation.*"),
+				},
+			},
+		},
+		"missing owner and cluster ID": {
+			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+			PreCheck:                 bazelPreCheck,
+			Steps: []resource.TestStep{
+				{
+					Config: testingConfig + `
+					data "constellation_attestation" "test" {
+						csp = "gcp"
+						attestation_variant = "gcp-sev-es"
+						image = {
+							version = "v2.13.0"
+							reference = "v2.13.0"
+							short_path = "v2.13.0"
+						}
+					}
+
+					data "constellation_node_attestation" "test" {
+						endpoints   = ["127.0.0.1:1"]
+						attestation = data.constellation_attestation.test.attestation
+					}
+					`,
+					ExpectError: regexp.MustCompile(".*Missing Cluster ID.*"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, tc)
+		})
+	}
+}
+
+type stubNodeVerifier struct {
+	out constellation.VerifyOutput
+	err error
+}
+
+func (v *stubNodeVerifier) Verify(_ context.Context, _ atls.Validator, _ constellation.VerifyOptions) (constellation.VerifyOutput, error) {
+	return v.out, v.err
+}
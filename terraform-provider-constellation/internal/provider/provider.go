@@ -92,12 +92,14 @@ func (p *ConstellationProvider) Configure(ctx context.Context, req provider.Conf
 func (p *ConstellationProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewClusterResource,
+		NewIAMResource,
+		NewInfrastructureResource,
 	}
 }
 
 // DataSources lists the data sources implemented by the provider.
 func (p *ConstellationProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		NewImageDataSource, NewAttestationDataSource,
+		NewImageDataSource, NewAttestationDataSource, NewNodeAttestationDataSource,
 	}
 }
@@ -30,11 +30,12 @@ func newAttestationVariantAttributeSchema(t attributeType) schema.Attribute {
 			"  * `aws-sev-snp`\n" +
 			"  * `aws-nitro-tpm`\n" +
 			"  * `azure-sev-snp`\n" +
-			"  * `gcp-sev-es`\n",
+			"  * `gcp-sev-es`\n" +
+			"  * `qemu-vtpm`\n",
 		Required: isInput,
 		Computed: !isInput,
 		Validators: []validator.String{
-			stringvalidator.OneOf("aws-sev-snp", "aws-nitro-tpm", "azure-sev-snp", "gcp-sev-es"),
+			stringvalidator.OneOf("aws-sev-snp", "aws-nitro-tpm", "azure-sev-snp", "gcp-sev-es", "qemu-vtpm"),
 		},
 	}
 }
@@ -46,7 +47,7 @@ func newCSPAttributeSchema() schema.Attribute {
 			"See the [full list of CSPs](https://docs.edgeless.systems/constellation/overview/clouds) that Constellation supports.",
 		Required: true,
 		Validators: []validator.String{
-			stringvalidator.OneOf("aws", "azure", "gcp"),
+			stringvalidator.OneOf("aws", "azure", "gcp", "openstack", "stackit", "qemu"),
 		},
 	}
 }
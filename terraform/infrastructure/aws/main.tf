@@ -67,8 +67,9 @@ resource "random_password" "init_secret" {
 }
 
 resource "aws_vpc" "vpc" {
-  cidr_block = "192.168.0.0/16"
-  tags       = merge(local.tags, { Name = "${local.name}-vpc" })
+  cidr_block                       = "192.168.0.0/16"
+  assign_generated_ipv6_cidr_block = var.enable_ipv6
+  tags                             = merge(local.tags, { Name = "${local.name}-vpc" })
 }
 
 module "public_private_subnet" {
@@ -77,6 +78,7 @@ module "public_private_subnet" {
   vpc_id                   = aws_vpc.vpc.id
   cidr_vpc_subnet_nodes    = local.cidr_vpc_subnet_nodes
   cidr_vpc_subnet_internet = "192.168.0.0/20"
+  cidr_vpc_ipv6            = var.enable_ipv6 ? aws_vpc.vpc.ipv6_cidr_block : ""
   zone                     = var.zone
   zones                    = local.zones
   tags                     = local.tags
@@ -126,19 +128,21 @@ resource "aws_security_group" "security_group" {
   tags        = local.tags
 
   egress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = ["0.0.0.0/0"]
-    description = "Allow all outbound traffic"
+    from_port        = 0
+    to_port          = 0
+    protocol         = "-1"
+    cidr_blocks      = ["0.0.0.0/0"]
+    ipv6_cidr_blocks = var.enable_ipv6 ? ["::/0"] : []
+    description      = "Allow all outbound traffic"
   }
 
   ingress {
-    from_port   = split("-", local.ports_node_range)[0]
-    to_port     = split("-", local.ports_node_range)[1]
-    protocol    = "tcp"
-    cidr_blocks = ["0.0.0.0/0"]
-    description = "K8s node ports"
+    from_port        = split("-", local.ports_node_range)[0]
+    to_port          = split("-", local.ports_node_range)[1]
+    protocol         = "tcp"
+    cidr_blocks      = ["0.0.0.0/0"]
+    ipv6_cidr_blocks = var.enable_ipv6 ? ["::/0"] : []
+    description      = "K8s node ports"
   }
 
   dynamic "ingress" {
@@ -153,11 +157,12 @@ resource "aws_security_group" "security_group" {
   }
 
   ingress {
-    from_port   = 0
-    to_port     = 0
-    protocol    = "-1"
-    cidr_blocks = [aws_vpc.vpc.cidr_block]
-    description = "allow all internal"
+    from_port        = 0
+    to_port          = 0
+    protocol         = "-1"
+    cidr_blocks      = [aws_vpc.vpc.cidr_block]
+    ipv6_cidr_blocks = var.enable_ipv6 ? [aws_vpc.vpc.ipv6_cidr_block] : []
+    description      = "allow all internal"
   }
 
 }
@@ -198,6 +203,9 @@ module "instance_group" {
   subnetwork           = module.public_private_subnet.private_subnet_id[each.value.zone]
   iam_instance_profile = local.iam_instance_profile[each.value.role]
   enable_snp           = var.enable_snp
+  spot                 = each.value.spot
+  min_count            = each.value.min_count
+  max_count            = each.value.max_count
   tags = merge(
     local.tags,
     { Name = "${local.name}-${each.value.role}" },
@@ -205,7 +213,13 @@ module "instance_group" {
     { constellation-node-group = each.key },
     { constellation-uid = local.uid },
     { constellation-init-secret-hash = local.init_secret_hash },
-    { "kubernetes.io/cluster/${local.name}" = "owned" }
+    { constellation-disk-encryption-profile = var.disk_encryption_profile },
+    { "kubernetes.io/cluster/${local.name}" = "owned" },
+    each.value.node_labels != "" ? { constellation-node-labels = each.value.node_labels } : {},
+    each.value.node_taints != "" ? { constellation-node-taints = each.value.node_taints } : {},
+    each.value.spot ? { constellation-spot = "true" } : {},
+    each.value.min_count > 0 ? { constellation-autoscaling-min = tostring(each.value.min_count) } : {},
+    each.value.max_count > 0 ? { constellation-autoscaling-max = tostring(each.value.max_count) } : {},
   )
 }
 
@@ -28,6 +28,14 @@ resource "aws_launch_template" "launch_template" {
     name = var.iam_instance_profile
   }
   vpc_security_group_ids = var.security_groups
+
+  dynamic "instance_market_options" {
+    for_each = var.spot ? [1] : []
+    content {
+      market_type = "spot"
+    }
+  }
+
   metadata_options {
     http_endpoint               = "enabled"
     http_tokens                 = "required"
@@ -78,8 +86,8 @@ resource "aws_autoscaling_group" "autoscaling_group" {
   launch_template {
     id = aws_launch_template.launch_template.id
   }
-  min_size            = 1
-  max_size            = 10
+  min_size            = var.min_count > 0 ? var.min_count : 1
+  max_size            = var.max_count > 0 ? max(var.max_count, var.initial_count) : 10
   desired_capacity    = var.initial_count
   vpc_zone_identifier = [var.subnetwork]
   target_group_arns   = var.target_group_arns
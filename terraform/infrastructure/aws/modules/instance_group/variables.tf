@@ -78,6 +78,24 @@ variable "enable_snp" {
   description = "Enable AMD SEV-SNP for the instances."
 }
 
+variable "spot" {
+  type        = bool
+  default     = false
+  description = "Use spot instances for the nodes."
+}
+
+variable "min_count" {
+  type        = number
+  default     = 0
+  description = "Minimum number of nodes the autoscaler scales the group down to. 0 means autoscaling bounds are not configured."
+}
+
+variable "max_count" {
+  type        = number
+  default     = 0
+  description = "Maximum number of nodes the autoscaler scales the group up to. 0 means autoscaling bounds are not configured."
+}
+
 variable "zone" {
   type        = string
   description = "Zone to deploy the instance group in."
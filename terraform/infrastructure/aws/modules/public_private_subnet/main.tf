@@ -29,6 +29,9 @@ locals {
     l      = 14
     m      = 15 # => 192.168.191.0/24 (last reserved zonal private subnet cidr). In reality, AWS doesn't have that many zones in a region.
   }
+  enable_ipv6 = var.cidr_vpc_ipv6 != ""
+  # the /64 IPv6 subnets are numbered like the IPv4 subnets, public subnets start after the private ones
+  ipv6_public_subnet_offset = 16
 }
 
 data "aws_availability_zones" "available" {
@@ -52,6 +55,9 @@ resource "aws_subnet" "private" {
   vpc_id            = var.vpc_id
   cidr_block        = cidrsubnet(var.cidr_vpc_subnet_nodes, 4, local.az_number[each.value.name_suffix])
   availability_zone = each.key
+  ipv6_cidr_block   = local.enable_ipv6 ? cidrsubnet(var.cidr_vpc_ipv6, 8, local.az_number[each.value.name_suffix]) : null
+  # nodes get an IPv6 address in addition to their IPv4 address
+  assign_ipv6_address_on_creation = local.enable_ipv6
   tags              = merge(var.tags, { Name = "${var.name}-subnet-nodes" }, { "kubernetes.io/role/internal-elb" = 1 }) # aws-load-balancer-controller needs role annotation
   lifecycle {
     ignore_changes = [
@@ -63,8 +69,10 @@ resource "aws_subnet" "private" {
 resource "aws_subnet" "public" {
   for_each          = data.aws_availability_zone.all
   vpc_id            = var.vpc_id
-  cidr_block        = cidrsubnet(var.cidr_vpc_subnet_internet, 4, local.az_number[each.value.name_suffix])
-  availability_zone = each.key
+  cidr_block                      = cidrsubnet(var.cidr_vpc_subnet_internet, 4, local.az_number[each.value.name_suffix])
+  availability_zone               = each.key
+  ipv6_cidr_block                 = local.enable_ipv6 ? cidrsubnet(var.cidr_vpc_ipv6, 8, local.ipv6_public_subnet_offset + local.az_number[each.value.name_suffix]) : null
+  assign_ipv6_address_on_creation = local.enable_ipv6
   tags              = merge(var.tags, { Name = "${var.name}-subnet-internet" }, { "kubernetes.io/role/elb" = 1 }) # aws-load-balancer-controller needs role annotation
   lifecycle {
     ignore_changes = [
@@ -85,6 +93,12 @@ resource "aws_nat_gateway" "gw" {
   tags          = merge(var.tags, { Name = "${var.name}-nat-gateway" })
 }
 
+resource "aws_egress_only_internet_gateway" "gw" {
+  count  = local.enable_ipv6 ? 1 : 0
+  vpc_id = var.vpc_id
+  tags   = merge(var.tags, { Name = "${var.name}-egress-only-internet-gateway" })
+}
+
 resource "aws_route_table" "private_nat" {
   for_each = toset(var.zones)
   vpc_id   = var.vpc_id
@@ -94,6 +108,14 @@ resource "aws_route_table" "private_nat" {
     cidr_block     = "0.0.0.0/0"
     nat_gateway_id = aws_nat_gateway.gw[each.key].id
   }
+
+  dynamic "route" {
+    for_each = local.enable_ipv6 ? [1] : []
+    content {
+      ipv6_cidr_block        = "::/0"
+      egress_only_gateway_id = aws_egress_only_internet_gateway.gw[0].id
+    }
+  }
 }
 
 resource "aws_route_table" "public_igw" {
@@ -105,6 +127,14 @@ resource "aws_route_table" "public_igw" {
     cidr_block = "0.0.0.0/0"
     gateway_id = aws_internet_gateway.gw.id
   }
+
+  dynamic "route" {
+    for_each = local.enable_ipv6 ? [1] : []
+    content {
+      ipv6_cidr_block = "::/0"
+      gateway_id      = aws_internet_gateway.gw.id
+    }
+  }
 }
 
 resource "aws_route_table_association" "private_nat" {
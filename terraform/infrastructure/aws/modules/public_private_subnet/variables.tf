@@ -28,6 +28,12 @@ variable "cidr_vpc_subnet_internet" {
   description = "CIDR block for the subnet that contains resources reachable from the Internet."
 }
 
+variable "cidr_vpc_ipv6" {
+  type        = string
+  default     = ""
+  description = "IPv6 CIDR block of the VPC. If set, the subnets are dual-stack and get an IPv6 CIDR block from this range."
+}
+
 variable "tags" {
   type        = map(string)
   description = "Tags to add to the resource."
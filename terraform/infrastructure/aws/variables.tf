@@ -21,6 +21,11 @@ variable "node_groups" {
     disk_size     = number
     disk_type     = string
     zone          = string
+    node_labels   = optional(string, "")
+    node_taints   = optional(string, "")
+    spot          = optional(bool, false)
+    min_count     = optional(number, 0)
+    max_count     = optional(number, 0)
   }))
   description = "A map of node group names to node group configurations."
   validation {
@@ -56,6 +61,18 @@ variable "internal_load_balancer" {
   description = "Whether to use an internal load balancer for the cluster."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks of the nodes. If not set, the default profile will be used."
+}
+
+variable "enable_ipv6" {
+  type        = bool
+  default     = false
+  description = "Whether to assign IPv6 addresses to the nodes, in addition to IPv4 addresses. Required for dual-stack clusters."
+}
+
 # AWS-specific variables
 
 variable "iam_instance_profile_name_worker_nodes" {
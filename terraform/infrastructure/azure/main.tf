@@ -246,9 +246,16 @@ module "scale_set_group" {
   tags = merge(
     local.tags,
     { constellation-init-secret-hash = local.init_secret_hash },
+    { constellation-disk-encryption-profile = var.disk_encryption_profile },
     { constellation-maa-url = var.create_maa ? azurerm_attestation_provider.attestation_provider[0].attestation_uri : "" },
+    each.value.node_labels != "" ? { constellation-node-labels = each.value.node_labels } : {},
+    each.value.node_taints != "" ? { constellation-node-taints = each.value.node_taints } : {},
+    each.value.spot ? { constellation-spot = "true" } : {},
+    each.value.min_count > 0 ? { constellation-autoscaling-min = tostring(each.value.min_count) } : {},
+    each.value.max_count > 0 ? { constellation-autoscaling-max = tostring(each.value.max_count) } : {},
   )
 
+  spot                      = each.value.spot
   initial_count             = each.value.initial_count
   state_disk_size           = each.value.disk_size
   state_disk_type           = each.value.disk_type
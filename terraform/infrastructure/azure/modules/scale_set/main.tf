@@ -47,6 +47,8 @@ resource "azurerm_linux_virtual_machine_scale_set" "scale_set" {
   disable_password_authentication = false
   upgrade_mode                    = "Manual"
   secure_boot_enabled             = var.secure_boot
+  priority                        = var.spot ? "Spot" : "Regular"
+  eviction_policy                 = var.spot ? "Delete" : null
   # specify the image id only if a non-marketplace image is used
   source_image_id = var.marketplace_image != null ? null : var.image_id
   tags            = local.tags
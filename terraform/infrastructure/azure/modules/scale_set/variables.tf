@@ -107,3 +107,9 @@ variable "marketplace_image" {
   default     = null
   description = "Marketplace image to use for the cluster nodes."
 }
+
+variable "spot" {
+  type        = bool
+  default     = false
+  description = "Use spot instances for the nodes."
+}
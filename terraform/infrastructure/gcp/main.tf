@@ -168,27 +168,33 @@ resource "google_compute_firewall" "firewall_internal_pods" {
 
 
 module "instance_group" {
-  source              = "./modules/instance_group"
-  for_each            = var.node_groups
-  base_name           = local.name
-  node_group_name     = each.key
-  role                = each.value.role
-  zone                = each.value.zone
-  uid                 = local.uid
-  instance_type       = each.value.instance_type
-  initial_count       = each.value.initial_count
-  image_id            = var.image_id
-  disk_size           = each.value.disk_size
-  disk_type           = each.value.disk_type
-  network             = google_compute_network.vpc_network.id
-  subnetwork          = google_compute_subnetwork.vpc_subnetwork.id
-  alias_ip_range_name = google_compute_subnetwork.vpc_subnetwork.secondary_ip_range[0].range_name
-  kube_env            = local.kube_env
-  debug               = var.debug
-  named_ports         = each.value.role == "control-plane" ? local.control_plane_named_ports : []
-  labels              = local.labels
-  init_secret_hash    = local.init_secret_hash
-  custom_endpoint     = var.custom_endpoint
+  source                  = "./modules/instance_group"
+  for_each                = var.node_groups
+  base_name               = local.name
+  node_group_name         = each.key
+  role                    = each.value.role
+  zone                    = each.value.zone
+  uid                     = local.uid
+  instance_type           = each.value.instance_type
+  initial_count           = each.value.initial_count
+  image_id                = var.image_id
+  disk_size               = each.value.disk_size
+  disk_type               = each.value.disk_type
+  network                 = google_compute_network.vpc_network.id
+  subnetwork              = google_compute_subnetwork.vpc_subnetwork.id
+  alias_ip_range_name     = google_compute_subnetwork.vpc_subnetwork.secondary_ip_range[0].range_name
+  kube_env                = local.kube_env
+  debug                   = var.debug
+  named_ports             = each.value.role == "control-plane" ? local.control_plane_named_ports : []
+  labels                  = local.labels
+  init_secret_hash        = local.init_secret_hash
+  custom_endpoint         = var.custom_endpoint
+  disk_encryption_profile = var.disk_encryption_profile
+  node_labels             = each.value.node_labels
+  node_taints             = each.value.node_taints
+  spot                    = each.value.spot
+  min_count               = each.value.min_count
+  max_count               = each.value.max_count
 }
 
 resource "google_compute_address" "loadbalancer_ip_internal" {
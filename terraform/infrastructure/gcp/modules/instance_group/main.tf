@@ -26,10 +26,16 @@ resource "google_compute_instance_template" "template" {
   name         = local.name
   machine_type = var.instance_type
   tags         = ["constellation-${var.uid}"] // Note that this is also applied as a label
-  labels = merge(var.labels, {
-    constellation-role       = var.role,
-    constellation-node-group = var.node_group_name,
-  })
+  labels = merge(
+    var.labels,
+    {
+      constellation-role       = var.role,
+      constellation-node-group = var.node_group_name,
+    },
+    var.spot ? { constellation-spot = "true" } : {},
+    var.min_count > 0 ? { constellation-autoscaling-min = tostring(var.min_count) } : {},
+    var.max_count > 0 ? { constellation-autoscaling-max = tostring(var.max_count) } : {},
+  )
 
   confidential_instance_config {
     enable_confidential_compute = true
@@ -53,11 +59,16 @@ resource "google_compute_instance_template" "template" {
     type         = "PERSISTENT"
   }
 
-  metadata = {
-    kube-env                       = var.kube_env
-    constellation-init-secret-hash = var.init_secret_hash
-    serial-port-enable             = var.debug ? "TRUE" : "FALSE"
-  }
+  metadata = merge(
+    {
+      kube-env                              = var.kube_env
+      constellation-init-secret-hash        = var.init_secret_hash
+      constellation-disk-encryption-profile = var.disk_encryption_profile
+      serial-port-enable                    = var.debug ? "TRUE" : "FALSE"
+    },
+    var.node_labels != "" ? { constellation-node-labels = var.node_labels } : {},
+    var.node_taints != "" ? { constellation-node-taints = var.node_taints } : {},
+  )
 
   network_interface {
     network    = var.network
@@ -70,6 +81,10 @@ resource "google_compute_instance_template" "template" {
 
   scheduling {
     on_host_maintenance = "TERMINATE"
+    # Spot VMs can't be restarted automatically after preemption.
+    automatic_restart  = var.spot ? false : true
+    preemptible        = var.spot
+    provisioning_model = var.spot ? "SPOT" : "STANDARD"
   }
 
   service_account {
@@ -73,6 +73,12 @@ variable "init_secret_hash" {
   description = "BCrypt Hash of the initialization secret."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks."
+}
+
 variable "named_ports" {
   type        = list(object({ name = string, port = number }))
   default     = []
@@ -99,3 +105,33 @@ variable "custom_endpoint" {
   type        = string
   description = "Custom endpoint to use for the Kubernetes API server. If not set, the default endpoint will be used."
 }
+
+variable "node_labels" {
+  type        = string
+  default     = ""
+  description = "Space separated list of labels the kubelet registers the nodes with."
+}
+
+variable "node_taints" {
+  type        = string
+  default     = ""
+  description = "Space separated list of taints the kubelet registers the nodes with."
+}
+
+variable "spot" {
+  type        = bool
+  default     = false
+  description = "Use spot VMs for the nodes."
+}
+
+variable "min_count" {
+  type        = number
+  default     = 0
+  description = "Minimum number of nodes the autoscaler scales the group down to. 0 means autoscaling bounds are not configured."
+}
+
+variable "max_count" {
+  type        = number
+  default     = 0
+  description = "Maximum number of nodes the autoscaler scales the group up to. 0 means autoscaling bounds are not configured."
+}
@@ -13,6 +13,11 @@ variable "node_groups" {
     disk_size     = number
     disk_type     = string
     initial_count = number
+    node_labels   = optional(string, "")
+    node_taints   = optional(string, "")
+    spot          = optional(bool, false)
+    min_count     = optional(number, 0)
+    max_count     = optional(number, 0)
   }))
   description = "A map of node group names to node group configurations."
   validation {
@@ -44,6 +49,12 @@ variable "internal_load_balancer" {
   description = "Whether to use an internal load balancer for the cluster."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks of the nodes. If not set, the default profile will be used."
+}
+
 # GCP-specific variables
 
 variable "project" {
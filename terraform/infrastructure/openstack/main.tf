@@ -175,6 +175,9 @@ module "instance_group" {
   uid                        = local.uid
   network_id                 = openstack_networking_network_v2.vpc_network.id
   init_secret_hash           = local.init_secret_hash
+  disk_encryption_profile    = var.disk_encryption_profile
+  node_labels                = each.value.node_labels
+  node_taints                = each.value.node_taints
   identity_internal_url      = local.identity_internal_url
   openstack_username         = var.openstack_username
   openstack_password         = var.openstack_password
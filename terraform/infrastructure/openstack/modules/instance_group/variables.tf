@@ -72,6 +72,24 @@ variable "init_secret_hash" {
   description = "Hash of the init secret."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks."
+}
+
+variable "node_labels" {
+  type        = string
+  default     = ""
+  description = "Space separated list of labels the kubelet registers the nodes with."
+}
+
+variable "node_taints" {
+  type        = string
+  default     = ""
+  description = "Space separated list of taints the kubelet registers the nodes with."
+}
+
 variable "identity_internal_url" {
   type        = string
   description = "Internal URL of the Identity service."
@@ -13,6 +13,8 @@ variable "node_groups" {
     state_disk_size = number // size of state disk (GiB)
     state_disk_type = string // type of state disk. Can be 'standard' or 'premium'
     zone            = string // availability zone
+    node_labels     = optional(string, "") // space separated list of labels the kubelet registers the nodes with
+    node_taints     = optional(string, "") // space separated list of taints the kubelet registers the nodes with
   }))
 
   validation {
@@ -40,6 +42,12 @@ variable "custom_endpoint" {
   description = "Custom endpoint to use for the Kubernetes API server. If not set, the default endpoint will be used."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks of the nodes. If not set, the default profile will be used."
+}
+
 # OpenStack-specific variables
 
 variable "cloud" {
@@ -47,6 +47,8 @@ resource "docker_container" "qemu_metadata" {
     "${var.metadata_libvirt_uri}",
     "--initsecrethash",
     "${random_password.init_secret.bcrypt_hash}",
+    "--diskencryptionprofile",
+    var.disk_encryption_profile,
   ]
   mounts {
     source = abspath(var.libvirt_socket_path)
@@ -110,7 +112,7 @@ resource "libvirt_volume" "constellation_initrd" {
 resource "libvirt_network" "constellation" {
   name      = "${var.name}-network"
   mode      = "nat"
-  addresses = ["10.42.0.0/16"]
+  addresses = concat(["10.42.0.0/16"], var.enable_ipv6 ? ["fd00:42::/64"] : [])
   dhcp {
     enabled = true
   }
@@ -33,6 +33,18 @@ variable "custom_endpoint" {
   description = "Custom endpoint to use for the Kubernetes API server. If not set, the default endpoint will be used."
 }
 
+variable "disk_encryption_profile" {
+  type        = string
+  default     = ""
+  description = "Name of the encryption profile used for the state disks of the nodes. If not set, the default profile will be used."
+}
+
+variable "enable_ipv6" {
+  type        = bool
+  default     = false
+  description = "Whether to assign IPv6 addresses to the nodes, in addition to IPv4 addresses. Required for dual-stack clusters."
+}
+
 # QEMU-specific variables
 
 variable "machine" {
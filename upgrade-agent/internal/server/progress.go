@@ -0,0 +1,70 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package server
+
+import (
+	"github.com/edgelesssys/constellation/v2/internal/logger"
+	"github.com/edgelesssys/constellation/v2/upgrade-agent/upgradeproto"
+	"go.uber.org/zap"
+)
+
+// progressReporter reports the progress of an update to the caller.
+type progressReporter interface {
+	// reportProgress reports a state change of an update step.
+	reportProgress(step upgradeproto.UpdateStep, state upgradeproto.UpdateStepState, msg string)
+	// reportLog reports the output of a command executed in an update step.
+	reportLog(step upgradeproto.UpdateStep, log []byte)
+}
+
+// logReporter writes the progress of an update to the server log.
+// It is used for unary update requests.
+type logReporter struct {
+	log *logger.Logger
+}
+
+func (r *logReporter) reportProgress(step upgradeproto.UpdateStep, state upgradeproto.UpdateStepState, msg string) {
+	r.log.With(zap.String("step", step.String()), zap.String("state", state.String())).Infof("Update progress: %s", msg)
+}
+
+func (r *logReporter) reportLog(step upgradeproto.UpdateStep, log []byte) {
+	r.log.With(zap.String("step", step.String())).Debugf("Command output: %s", log)
+}
+
+// streamReporter sends the progress of an update to a client stream.
+// Progress is also written to the server log.
+type streamReporter struct {
+	log    *logger.Logger
+	stream upgradeproto.Update_ExecuteUpdateStreamServer
+}
+
+func (r *streamReporter) reportProgress(step upgradeproto.UpdateStep, state upgradeproto.UpdateStepState, msg string) {
+	(&logReporter{log: r.log}).reportProgress(step, state, msg)
+	r.send(&upgradeproto.ExecuteUpdateStreamResponse{
+		Kind: &upgradeproto.ExecuteUpdateStreamResponse_Progress{
+			Progress: &upgradeproto.UpdateProgress{Step: step, State: state, Message: msg},
+		},
+	})
+}
+
+func (r *streamReporter) reportLog(step upgradeproto.UpdateStep, log []byte) {
+	if len(log) == 0 {
+		return
+	}
+	r.send(&upgradeproto.ExecuteUpdateStreamResponse{
+		Kind: &upgradeproto.ExecuteUpdateStreamResponse_Log{
+			Log: &upgradeproto.UpdateLog{Step: step, Log: log},
+		},
+	})
+}
+
+// send sends a message to the client. Failing to report progress does not abort the update,
+// since aborting would leave the node in an undefined state.
+func (r *streamReporter) send(msg *upgradeproto.ExecuteUpdateStreamResponse) {
+	if err := r.stream.Send(msg); err != nil {
+		r.log.With(zap.Error(err)).Warnf("Failed to send update progress")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"sync"
 
 	"github.com/edgelesssys/constellation/v2/internal/constants"
 	"github.com/edgelesssys/constellation/v2/internal/file"
@@ -27,12 +28,24 @@ import (
 	"github.com/edgelesssys/constellation/v2/internal/logger"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
 	"github.com/edgelesssys/constellation/v2/upgrade-agent/upgradeproto"
+	"go.uber.org/zap"
 	"golang.org/x/mod/semver"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// snapshotDir is the directory where files are saved before an update.
+	snapshotDir = "/run/state/upgrade-agent/snapshot"
+	// kubernetesConfigDir contains the static pod manifests, kubeconfigs and PKI updated by kubeadm.
+	kubernetesConfigDir = "/etc/kubernetes"
+	// kubeletConfigPath is the kubelet configuration updated by kubeadm.
+	kubeletConfigPath = "/var/lib/kubelet/config.yaml"
+	// kubeletFlagsPath is the kubelet flags file updated by kubeadm.
+	kubeletFlagsPath = "/var/lib/kubelet/kubeadm-flags.env"
+)
+
 var errInvalidKubernetesVersion = errors.New("invalid kubernetes version")
 
 // Server is the upgrade-agent server.
@@ -40,6 +53,8 @@ type Server struct {
 	file       file.Handler
 	grpcServer serveStopper
 	log        *logger.Logger
+	// updateMux ensures that only one update is executed at a time.
+	updateMux sync.Mutex
 	upgradeproto.UnimplementedUpdateServer
 }
 
@@ -99,29 +114,117 @@ func (s *Server) Stop() {
 }
 
 // ExecuteUpdate installs & verifies the provided kubeadm, then executes `kubeadm upgrade plan` & `kubeadm upgrade apply {wanted_Kubernetes_Version}` to upgrade to the specified version.
+// If any step fails, the replaced binaries and configuration files are restored.
 func (s *Server) ExecuteUpdate(ctx context.Context, updateRequest *upgradeproto.ExecuteUpdateRequest) (*upgradeproto.ExecuteUpdateResponse, error) {
+	if err := s.executeUpdate(ctx, updateRequest, &logReporter{log: s.log}); err != nil {
+		return nil, err
+	}
+	return &upgradeproto.ExecuteUpdateResponse{}, nil
+}
+
+// ExecuteUpdateStream executes the same update as ExecuteUpdate, but streams the progress
+// of each step and the output of kubeadm to the caller.
+func (s *Server) ExecuteUpdateStream(updateRequest *upgradeproto.ExecuteUpdateRequest, stream upgradeproto.Update_ExecuteUpdateStreamServer) error {
+	return s.executeUpdate(stream.Context(), updateRequest, &streamReporter{log: s.log, stream: stream})
+}
+
+func (s *Server) executeUpdate(ctx context.Context, updateRequest *upgradeproto.ExecuteUpdateRequest, reporter progressReporter) error {
+	if !s.updateMux.TryLock() {
+		return status.Error(codes.Aborted, "another update is already in progress")
+	}
+	defer s.updateMux.Unlock()
+
 	s.log.Infof("Upgrade to Kubernetes version started: %s", updateRequest.WantedKubernetesVersion)
+	u := &updater{
+		installer:   installer.NewOSInstaller(),
+		runner:      execRunner{},
+		file:        s.file,
+		snapshotDir: snapshotDir,
+		reporter:    reporter,
+	}
+	if err := u.update(ctx, updateRequest); err != nil {
+		s.log.With(zap.Error(err)).Errorf("Upgrade to Kubernetes version failed: %s", updateRequest.WantedKubernetesVersion)
+		return err
+	}
+	s.log.Infof("Upgrade to Kubernetes version succeeded: %s", updateRequest.WantedKubernetesVersion)
+	return nil
+}
+
+// updater executes a single update transaction.
+type updater struct {
+	installer   osInstaller
+	runner      commandRunner
+	file        file.Handler
+	snapshotDir string
+	reporter    progressReporter
+}
 
-	installer := installer.NewOSInstaller()
-	err := prepareUpdate(ctx, installer, updateRequest)
-	if errors.Is(err, errInvalidKubernetesVersion) {
-		return nil, status.Errorf(codes.Internal, "unable to verify the Kubernetes version %s: %s", updateRequest.WantedKubernetesVersion, err)
-	} else if err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to install the kubeadm binary: %s", err)
+// update snapshots all files touched by the update, installs the Kubernetes components and runs kubeadm.
+// If a step after the snapshot fails, the snapshot is restored.
+// Only node-local state is rolled back: changes kubeadm already applied to cluster objects are kept.
+func (u *updater) update(ctx context.Context, updateRequest *upgradeproto.ExecuteUpdateRequest) (retErr error) {
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_VERIFY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "verifying Kubernetes version")
+	if err := verifyVersion(updateRequest.WantedKubernetesVersion); err != nil {
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_VERIFY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "unable to verify the Kubernetes version %s: %s", updateRequest.WantedKubernetesVersion, err)
 	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_VERIFY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
 
-	upgradeCmd := exec.CommandContext(ctx, "kubeadm", "upgrade", "plan", updateRequest.WantedKubernetesVersion)
-	if out, err := upgradeCmd.CombinedOutput(); err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to execute kubeadm upgrade plan %s: %s: %s", updateRequest.WantedKubernetesVersion, err, string(out))
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_SNAPSHOT, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "saving binaries and configuration")
+	snap, err := newSnapshot(u.file, u.snapshotDir, snapshotPaths(updateComponents(updateRequest)))
+	if err != nil {
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_SNAPSHOT, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "unable to snapshot the node before updating: %s", err)
 	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_SNAPSHOT, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
+
+	defer func() {
+		if retErr == nil {
+			_ = snap.discard()
+			return
+		}
+		retErr = u.rollback(snap, retErr)
+	}()
 
-	applyCmd := exec.CommandContext(ctx, "kubeadm", "upgrade", "apply", "--yes", "--patches", constants.KubeadmPatchDir, updateRequest.WantedKubernetesVersion)
-	if out, err := applyCmd.CombinedOutput(); err != nil {
-		return nil, status.Errorf(codes.Internal, "unable to execute kubeadm upgrade apply: %s: %s", err, string(out))
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_INSTALL, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "installing Kubernetes components")
+	if err := prepareUpdate(ctx, u.installer, updateRequest); err != nil {
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_INSTALL, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "unable to install the kubeadm binary: %s", err)
 	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_INSTALL, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
 
-	s.log.Infof("Upgrade to Kubernetes version succeeded: %s", updateRequest.WantedKubernetesVersion)
-	return &upgradeproto.ExecuteUpdateResponse{}, nil
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_PLAN, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "running kubeadm upgrade plan")
+	out, err := u.runner.CombinedOutput(ctx, "kubeadm", "upgrade", "plan", updateRequest.WantedKubernetesVersion)
+	u.reporter.reportLog(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_PLAN, out)
+	if err != nil {
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_PLAN, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "unable to execute kubeadm upgrade plan %s: %s: %s", updateRequest.WantedKubernetesVersion, err, string(out))
+	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_PLAN, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
+
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_APPLY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "running kubeadm upgrade apply")
+	out, err = u.runner.CombinedOutput(ctx, "kubeadm", "upgrade", "apply", "--yes", "--patches", constants.KubeadmPatchDir, updateRequest.WantedKubernetesVersion)
+	u.reporter.reportLog(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_APPLY, out)
+	if err != nil {
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_APPLY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "unable to execute kubeadm upgrade apply: %s: %s", err, string(out))
+	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_KUBEADM_APPLY, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
+
+	return nil
+}
+
+// rollback restores the snapshot after a failed update and returns the error to report to the caller.
+func (u *updater) rollback(snap *snapshot, updateErr error) error {
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_ROLLBACK, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_STARTED, "restoring binaries and configuration")
+	if err := snap.restore(); err != nil {
+		// Keep the snapshot around for manual recovery.
+		u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_ROLLBACK, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_FAILED, err.Error())
+		return status.Errorf(codes.Internal, "%s; rolling back failed, snapshot kept at %s: %s", status.Convert(updateErr).Message(), u.snapshotDir, err)
+	}
+	u.reporter.reportProgress(upgradeproto.UpdateStep_UPDATE_STEP_ROLLBACK, upgradeproto.UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED, "")
+	_ = snap.discard()
+	return updateErr
 }
 
 // prepareUpdate downloads & installs the specified kubeadm version and verifies the desired Kubernetes version.
@@ -132,6 +235,17 @@ func prepareUpdate(ctx context.Context, installer osInstaller, updateRequest *up
 		return err
 	}
 
+	// Download & install the Kubernetes components.
+	for _, c := range updateComponents(updateRequest) {
+		if err := installer.Install(ctx, c); err != nil {
+			return fmt.Errorf("installing Kubernetes component %q: %w", c.Url, err)
+		}
+	}
+	return nil
+}
+
+// updateComponents returns the Kubernetes components to install for the given request.
+func updateComponents(updateRequest *upgradeproto.ExecuteUpdateRequest) components.Components {
 	var cs components.Components
 	if len(updateRequest.KubeadmUrl) > 0 {
 		cs = append(cs, &components.Component{
@@ -141,15 +255,20 @@ func prepareUpdate(ctx context.Context, installer osInstaller, updateRequest *up
 			Extract:     false,
 		})
 	}
-	cs = append(cs, updateRequest.KubernetesComponents...)
+	return append(cs, updateRequest.KubernetesComponents...)
+}
 
-	// Download & install the Kubernetes components.
+// snapshotPaths returns the paths that are modified when installing the given components and running kubeadm.
+func snapshotPaths(cs components.Components) []string {
+	paths := []string{
+		kubernetesConfigDir,
+		kubeletConfigPath,
+		kubeletFlagsPath,
+	}
 	for _, c := range cs {
-		if err := installer.Install(ctx, c); err != nil {
-			return fmt.Errorf("installing Kubernetes component %q: %w", c.Url, err)
-		}
+		paths = append(paths, c.InstallPath)
 	}
-	return nil
+	return paths
 }
 
 // verifyVersion verifies the provided Kubernetes version.
@@ -165,6 +284,17 @@ type osInstaller interface {
 	Install(ctx context.Context, kubernetesComponent *components.Component) error
 }
 
+type commandRunner interface {
+	// CombinedOutput runs the command and returns its combined stdout and stderr.
+	CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+type execRunner struct{}
+
+func (execRunner) CombinedOutput(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
 type serveStopper interface {
 	// Serve starts the server.
 	Serve(lis net.Listener) error
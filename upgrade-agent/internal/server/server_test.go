@@ -8,11 +8,14 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/edgelesssys/constellation/v2/internal/file"
 	"github.com/edgelesssys/constellation/v2/internal/versions/components"
 	"github.com/edgelesssys/constellation/v2/upgrade-agent/upgradeproto"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -143,6 +146,121 @@ func TestPrepareUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	updateRequest := &upgradeproto.ExecuteUpdateRequest{
+		WantedKubernetesVersion: "v1.1.1",
+		KubernetesComponents: []*components.Component{
+			{
+				Url:         "http://example.com/kubeadm",
+				InstallPath: "/bin/kubeadm",
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		installer    osInstaller
+		runner       *stubCommandRunner
+		wantErr      bool
+		wantRollback bool
+	}{
+		"works": {
+			installer: stubOsInstaller{},
+			runner:    &stubCommandRunner{},
+		},
+		"install error": {
+			installer:    stubOsInstaller{InstallErr: errors.New("install error")},
+			runner:       &stubCommandRunner{},
+			wantErr:      true,
+			wantRollback: true,
+		},
+		"kubeadm plan error": {
+			installer:    stubOsInstaller{},
+			runner:       &stubCommandRunner{errs: map[string]error{"plan": errors.New("plan error")}},
+			wantErr:      true,
+			wantRollback: true,
+		},
+		"kubeadm apply error": {
+			installer:    stubOsInstaller{},
+			runner:       &stubCommandRunner{errs: map[string]error{"apply": errors.New("apply error")}},
+			wantErr:      true,
+			wantRollback: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			assert := assert.New(t)
+
+			fileHandler := file.NewHandler(afero.NewMemMapFs())
+			require.NoError(fileHandler.Write("/bin/kubeadm", []byte("old"), file.OptMkdirAll))
+			// simulate the installer and kubeadm modifying the node
+			tc.runner.onRun = func() {
+				_ = fileHandler.Write("/bin/kubeadm", []byte("new"), file.OptOverwrite)
+			}
+			reporter := &stubReporter{}
+
+			u := &updater{
+				installer:   tc.installer,
+				runner:      tc.runner,
+				file:        fileHandler,
+				snapshotDir: "/snapshot",
+				reporter:    reporter,
+			}
+
+			err := u.update(context.Background(), updateRequest)
+			if tc.wantErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			assert.Equal(tc.wantRollback, reporter.hasStep(upgradeproto.UpdateStep_UPDATE_STEP_ROLLBACK))
+
+			kubeadm, err := fileHandler.Read("/bin/kubeadm")
+			require.NoError(err)
+			if tc.wantRollback {
+				assert.Equal("old", string(kubeadm))
+			} else {
+				assert.Equal("new", string(kubeadm))
+			}
+			_, err = fileHandler.Stat("/snapshot")
+			assert.Error(err, "snapshot should be removed")
+		})
+	}
+}
+
+type stubCommandRunner struct {
+	// errs maps kubeadm upgrade subcommands to the error they return.
+	errs  map[string]error
+	onRun func()
+}
+
+func (s *stubCommandRunner) CombinedOutput(_ context.Context, _ string, args ...string) ([]byte, error) {
+	if s.onRun != nil {
+		s.onRun()
+	}
+	return []byte("output"), s.errs[args[1]]
+}
+
+type stubReporter struct {
+	steps []upgradeproto.UpdateStep
+}
+
+func (s *stubReporter) reportProgress(step upgradeproto.UpdateStep, _ upgradeproto.UpdateStepState, _ string) {
+	s.steps = append(s.steps, step)
+}
+
+func (s *stubReporter) reportLog(_ upgradeproto.UpdateStep, _ []byte) {}
+
+func (s *stubReporter) hasStep(step upgradeproto.UpdateStep) bool {
+	for _, st := range s.steps {
+		if st == step {
+			return true
+		}
+	}
+	return false
+}
+
 type stubOsInstaller struct {
 	InstallErr error
 }
@@ -0,0 +1,113 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+)
+
+// snapshot holds copies of files and directories that are modified during an update,
+// so that they can be restored if the update fails.
+type snapshot struct {
+	file    file.Handler
+	dir     string
+	entries []snapshotEntry
+}
+
+// snapshotEntry is a single file or directory recorded in a snapshot.
+type snapshotEntry struct {
+	// path is the original location of the file or directory.
+	path string
+	// backup is the location of the copy inside the snapshot directory.
+	backup string
+	// existed is false if there was no file or directory at path when the snapshot was taken.
+	existed bool
+	// isDir is true if path is a directory.
+	isDir bool
+}
+
+// newSnapshot copies the given paths into dir.
+// Paths that do not exist are recorded, so that they are removed again on restore.
+func newSnapshot(fileHandler file.Handler, dir string, paths []string) (*snapshot, error) {
+	if err := fileHandler.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("removing stale snapshot: %w", err)
+	}
+	if err := fileHandler.MkdirAll(dir); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	s := &snapshot{file: fileHandler, dir: dir}
+	for i, path := range paths {
+		entry := snapshotEntry{
+			path:   path,
+			backup: filepath.Join(dir, strconv.Itoa(i)),
+		}
+
+		info, err := fileHandler.Stat(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			s.entries = append(s.entries, entry)
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("stat %q: %w", path, err)
+		}
+
+		entry.existed = true
+		entry.isDir = info.IsDir()
+		if entry.isDir {
+			if err := fileHandler.MkdirAll(entry.backup); err != nil {
+				return nil, fmt.Errorf("creating snapshot directory for %q: %w", path, err)
+			}
+			err = fileHandler.CopyDir(path, entry.backup)
+		} else {
+			err = fileHandler.CopyFile(path, entry.backup)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("copying %q to snapshot: %w", path, err)
+		}
+		s.entries = append(s.entries, entry)
+	}
+
+	return s, nil
+}
+
+// restore returns all recorded paths to their snapshotted state.
+// Files in a recorded directory are overwritten with their copies, but files that
+// were added to the directory after the snapshot was taken are kept.
+// Entries are restored in reverse order, so that a file recorded after a directory
+// containing it is overwritten by the directory's copy.
+func (s *snapshot) restore() error {
+	var errs error
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+
+		var err error
+		switch {
+		case !entry.existed:
+			err = s.file.RemoveAll(entry.path)
+		case entry.isDir:
+			err = s.file.CopyDir(entry.backup, entry.path, file.OptOverwrite)
+		default:
+			err = s.file.CopyFile(entry.backup, entry.path, file.OptOverwrite, file.OptMkdirAll)
+		}
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("restoring %q: %w", entry.path, err))
+		}
+	}
+	return errs
+}
+
+// discard removes the snapshot directory.
+func (s *snapshot) discard() error {
+	return s.file.RemoveAll(s.dir)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright (c) Edgeless Systems GmbH
+
+SPDX-License-Identifier: AGPL-3.0-only
+*/
+
+package server
+
+import (
+	"testing"
+
+	"github.com/edgelesssys/constellation/v2/internal/file"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	fileHandler := file.NewHandler(afero.NewMemMapFs())
+	require.NoError(fileHandler.Write("/bin/kubeadm", []byte("old kubeadm"), file.OptMkdirAll))
+	require.NoError(fileHandler.Write("/etc/kubernetes/manifests/etcd.yaml", []byte("old etcd"), file.OptMkdirAll))
+
+	snap, err := newSnapshot(fileHandler, "/snapshot", []string{"/etc/kubernetes", "/bin/kubeadm", "/bin/kubelet"})
+	require.NoError(err)
+
+	// modify the node
+	require.NoError(fileHandler.Write("/bin/kubeadm", []byte("new kubeadm"), file.OptOverwrite))
+	require.NoError(fileHandler.Write("/bin/kubelet", []byte("new kubelet"), file.OptOverwrite))
+	require.NoError(fileHandler.Write("/etc/kubernetes/manifests/etcd.yaml", []byte("new etcd"), file.OptOverwrite))
+
+	require.NoError(snap.restore())
+
+	kubeadm, err := fileHandler.Read("/bin/kubeadm")
+	require.NoError(err)
+	assert.Equal("old kubeadm", string(kubeadm))
+	etcd, err := fileHandler.Read("/etc/kubernetes/manifests/etcd.yaml")
+	require.NoError(err)
+	assert.Equal("old etcd", string(etcd))
+	_, err = fileHandler.Stat("/bin/kubelet")
+	assert.Error(err)
+
+	require.NoError(snap.discard())
+	_, err = fileHandler.Stat("/snapshot")
+	assert.Error(err)
+}
+
+func TestSnapshotOverlappingPaths(t *testing.T) {
+	require := require.New(t)
+	assert := assert.New(t)
+
+	fileHandler := file.NewHandler(afero.NewMemMapFs())
+	require.NoError(fileHandler.Write("/bin/crictl", []byte("old crictl"), file.OptMkdirAll))
+
+	snap, err := newSnapshot(fileHandler, "/snapshot", []string{"/bin", "/bin/kubeadm"})
+	require.NoError(err)
+
+	require.NoError(fileHandler.Write("/bin/crictl", []byte("new crictl"), file.OptOverwrite))
+	require.NoError(fileHandler.Write("/bin/kubeadm", []byte("new kubeadm"), file.OptOverwrite))
+
+	require.NoError(snap.restore())
+
+	crictl, err := fileHandler.Read("/bin/crictl")
+	require.NoError(err)
+	assert.Equal("old crictl", string(crictl))
+	_, err = fileHandler.Stat("/bin/kubeadm")
+	assert.Error(err)
+}
@@ -25,6 +25,121 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// UpdateStep is a step of the update transaction.
+type UpdateStep int32
+
+const (
+	UpdateStep_UPDATE_STEP_UNSPECIFIED   UpdateStep = 0
+	UpdateStep_UPDATE_STEP_VERIFY        UpdateStep = 1
+	UpdateStep_UPDATE_STEP_SNAPSHOT      UpdateStep = 2
+	UpdateStep_UPDATE_STEP_INSTALL       UpdateStep = 3
+	UpdateStep_UPDATE_STEP_KUBEADM_PLAN  UpdateStep = 4
+	UpdateStep_UPDATE_STEP_KUBEADM_APPLY UpdateStep = 5
+	UpdateStep_UPDATE_STEP_ROLLBACK      UpdateStep = 6
+)
+
+// Enum value maps for UpdateStep.
+var (
+	UpdateStep_name = map[int32]string{
+		0: "UPDATE_STEP_UNSPECIFIED",
+		1: "UPDATE_STEP_VERIFY",
+		2: "UPDATE_STEP_SNAPSHOT",
+		3: "UPDATE_STEP_INSTALL",
+		4: "UPDATE_STEP_KUBEADM_PLAN",
+		5: "UPDATE_STEP_KUBEADM_APPLY",
+		6: "UPDATE_STEP_ROLLBACK",
+	}
+	UpdateStep_value = map[string]int32{
+		"UPDATE_STEP_UNSPECIFIED":   0,
+		"UPDATE_STEP_VERIFY":        1,
+		"UPDATE_STEP_SNAPSHOT":      2,
+		"UPDATE_STEP_INSTALL":       3,
+		"UPDATE_STEP_KUBEADM_PLAN":  4,
+		"UPDATE_STEP_KUBEADM_APPLY": 5,
+		"UPDATE_STEP_ROLLBACK":      6,
+	}
+)
+
+func (x UpdateStep) Enum() *UpdateStep {
+	p := new(UpdateStep)
+	*p = x
+	return p
+}
+
+func (x UpdateStep) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateStep) Descriptor() protoreflect.EnumDescriptor {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes[0].Descriptor()
+}
+
+func (UpdateStep) Type() protoreflect.EnumType {
+	return &file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes[0]
+}
+
+func (x UpdateStep) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateStep.Descriptor instead.
+func (UpdateStep) EnumDescriptor() ([]byte, []int) {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{0}
+}
+
+// UpdateStepState is the state of an update step.
+type UpdateStepState int32
+
+const (
+	UpdateStepState_UPDATE_STEP_STATE_UNSPECIFIED UpdateStepState = 0
+	UpdateStepState_UPDATE_STEP_STATE_STARTED     UpdateStepState = 1
+	UpdateStepState_UPDATE_STEP_STATE_SUCCEEDED   UpdateStepState = 2
+	UpdateStepState_UPDATE_STEP_STATE_FAILED      UpdateStepState = 3
+)
+
+// Enum value maps for UpdateStepState.
+var (
+	UpdateStepState_name = map[int32]string{
+		0: "UPDATE_STEP_STATE_UNSPECIFIED",
+		1: "UPDATE_STEP_STATE_STARTED",
+		2: "UPDATE_STEP_STATE_SUCCEEDED",
+		3: "UPDATE_STEP_STATE_FAILED",
+	}
+	UpdateStepState_value = map[string]int32{
+		"UPDATE_STEP_STATE_UNSPECIFIED": 0,
+		"UPDATE_STEP_STATE_STARTED":     1,
+		"UPDATE_STEP_STATE_SUCCEEDED":   2,
+		"UPDATE_STEP_STATE_FAILED":      3,
+	}
+)
+
+func (x UpdateStepState) Enum() *UpdateStepState {
+	p := new(UpdateStepState)
+	*p = x
+	return p
+}
+
+func (x UpdateStepState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateStepState) Descriptor() protoreflect.EnumDescriptor {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes[1].Descriptor()
+}
+
+func (UpdateStepState) Type() protoreflect.EnumType {
+	return &file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes[1]
+}
+
+func (x UpdateStepState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateStepState.Descriptor instead.
+func (UpdateStepState) EnumDescriptor() ([]byte, []int) {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{1}
+}
+
 type ExecuteUpdateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -134,6 +249,212 @@ func (*ExecuteUpdateResponse) Descriptor() ([]byte, []int) {
 	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{1}
 }
 
+// ExecuteUpdateStreamResponse is a single message of the ExecuteUpdateStream response stream.
+type ExecuteUpdateStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Kind:
+	//	*ExecuteUpdateStreamResponse_Progress
+	//	*ExecuteUpdateStreamResponse_Log
+	Kind isExecuteUpdateStreamResponse_Kind `protobuf_oneof:"kind"`
+}
+
+func (x *ExecuteUpdateStreamResponse) Reset() {
+	*x = ExecuteUpdateStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteUpdateStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteUpdateStreamResponse) ProtoMessage() {}
+
+func (x *ExecuteUpdateStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteUpdateStreamResponse.ProtoReflect.Descriptor instead.
+func (*ExecuteUpdateStreamResponse) Descriptor() ([]byte, []int) {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *ExecuteUpdateStreamResponse) GetKind() isExecuteUpdateStreamResponse_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return nil
+}
+
+func (x *ExecuteUpdateStreamResponse) GetProgress() *UpdateProgress {
+	if x, ok := x.GetKind().(*ExecuteUpdateStreamResponse_Progress); ok {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ExecuteUpdateStreamResponse) GetLog() *UpdateLog {
+	if x, ok := x.GetKind().(*ExecuteUpdateStreamResponse_Log); ok {
+		return x.Log
+	}
+	return nil
+}
+
+type isExecuteUpdateStreamResponse_Kind interface {
+	isExecuteUpdateStreamResponse_Kind()
+}
+
+type ExecuteUpdateStreamResponse_Progress struct {
+	Progress *UpdateProgress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type ExecuteUpdateStreamResponse_Log struct {
+	Log *UpdateLog `protobuf:"bytes,2,opt,name=log,proto3,oneof"`
+}
+
+func (*ExecuteUpdateStreamResponse_Progress) isExecuteUpdateStreamResponse_Kind() {}
+
+func (*ExecuteUpdateStreamResponse_Log) isExecuteUpdateStreamResponse_Kind() {}
+
+// UpdateProgress reports a state change of an update step.
+type UpdateProgress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Step is the step the progress report refers to.
+	Step UpdateStep `protobuf:"varint,1,opt,name=step,proto3,enum=upgrade.UpdateStep" json:"step,omitempty"`
+	// State is the new state of the step.
+	State UpdateStepState `protobuf:"varint,2,opt,name=state,proto3,enum=upgrade.UpdateStepState" json:"state,omitempty"`
+	// Message is a human readable description of the state change.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *UpdateProgress) Reset() {
+	*x = UpdateProgress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProgress) ProtoMessage() {}
+
+func (x *UpdateProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProgress.ProtoReflect.Descriptor instead.
+func (*UpdateProgress) Descriptor() ([]byte, []int) {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateProgress) GetStep() UpdateStep {
+	if x != nil {
+		return x.Step
+	}
+	return UpdateStep_UPDATE_STEP_UNSPECIFIED
+}
+
+func (x *UpdateProgress) GetState() UpdateStepState {
+	if x != nil {
+		return x.State
+	}
+	return UpdateStepState_UPDATE_STEP_STATE_UNSPECIFIED
+}
+
+func (x *UpdateProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// UpdateLog contains output of a command executed during the update.
+type UpdateLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Step is the step the command was executed in.
+	Step UpdateStep `protobuf:"varint,1,opt,name=step,proto3,enum=upgrade.UpdateStep" json:"step,omitempty"`
+	// Log is the combined stdout and stderr of the command.
+	Log []byte `protobuf:"bytes,2,opt,name=log,proto3" json:"log,omitempty"`
+}
+
+func (x *UpdateLog) Reset() {
+	*x = UpdateLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLog) ProtoMessage() {}
+
+func (x *UpdateLog) ProtoReflect() protoreflect.Message {
+	mi := &file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLog.ProtoReflect.Descriptor instead.
+func (*UpdateLog) Descriptor() ([]byte, []int) {
+	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateLog) GetStep() UpdateStep {
+	if x != nil {
+		return x.Step
+	}
+	return UpdateStep_UPDATE_STEP_UNSPECIFIED
+}
+
+func (x *UpdateLog) GetLog() []byte {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
 var File_upgrade_agent_upgradeproto_upgrade_proto protoreflect.FileDescriptor
 
 var file_upgrade_agent_upgradeproto_upgrade_proto_rawDesc = []byte{
@@ -159,17 +480,66 @@ var file_upgrade_agent_upgradeproto_upgrade_proto_rawDesc = []byte{
 	0x74, 0x52, 0x14, 0x6b, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x65, 0x73, 0x43, 0x6f, 0x6d,
 	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x17, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75,
 	0x74, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x32, 0x58, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x4e, 0x0a, 0x0d, 0x45, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x75, 0x70,
-	0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x75, 0x70, 0x67,
+	0x22, 0x84, 0x01, 0x0a, 0x1b, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x35, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x48, 0x00, 0x52, 0x08, 0x70,
+	0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67, 0x48, 0x00, 0x52, 0x03, 0x6c, 0x6f, 0x67, 0x42,
+	0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x83, 0x01, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x27, 0x0a, 0x04, 0x73, 0x74,
+	0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x65, 0x70, 0x52, 0x04, 0x73,
+	0x74, 0x65, 0x70, 0x12, 0x2e, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x74, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x46, 0x0a,
+	0x09, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4c, 0x6f, 0x67, 0x12, 0x27, 0x0a, 0x04, 0x73, 0x74,
+	0x65, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x65, 0x70, 0x52, 0x04, 0x73,
+	0x74, 0x65, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x6c, 0x6f, 0x67, 0x2a, 0xcb, 0x01, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x53, 0x74, 0x65, 0x70, 0x12, 0x1b, 0x0a, 0x17, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53,
+	0x54, 0x45, 0x50, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x16, 0x0a, 0x12, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50,
+	0x5f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x59, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x55, 0x50, 0x44,
+	0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48, 0x4f,
+	0x54, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54,
+	0x45, 0x50, 0x5f, 0x49, 0x4e, 0x53, 0x54, 0x41, 0x4c, 0x4c, 0x10, 0x03, 0x12, 0x1c, 0x0a, 0x18,
+	0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x4b, 0x55, 0x42, 0x45,
+	0x41, 0x44, 0x4d, 0x5f, 0x50, 0x4c, 0x41, 0x4e, 0x10, 0x04, 0x12, 0x1d, 0x0a, 0x19, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x4b, 0x55, 0x42, 0x45, 0x41, 0x44,
+	0x4d, 0x5f, 0x41, 0x50, 0x50, 0x4c, 0x59, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x55, 0x50, 0x44,
+	0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x52, 0x4f, 0x4c, 0x4c, 0x42, 0x41, 0x43,
+	0x4b, 0x10, 0x06, 0x2a, 0x92, 0x01, 0x0a, 0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x74,
+	0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x1d, 0x55, 0x50, 0x44, 0x41, 0x54,
+	0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x53, 0x54, 0x41, 0x52, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x55, 0x50, 0x44,
+	0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53,
+	0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x55, 0x50,
+	0x44, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x54, 0x45, 0x50, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x32, 0xb6, 0x01, 0x0a, 0x06, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x4e, 0x0a, 0x0d, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x45,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x13, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1d, 0x2e, 0x75, 0x70, 0x67,
 	0x72, 0x61, 0x64, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73,
-	0x73, 0x73, 0x79, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x2d, 0x61, 0x67,
-	0x65, 0x6e, 0x74, 0x2f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x75, 0x70, 0x67, 0x72,
+	0x61, 0x64, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30,
+	0x01, 0x42, 0x44, 0x5a, 0x42, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x65, 0x64, 0x67, 0x65, 0x6c, 0x65, 0x73, 0x73, 0x73, 0x79, 0x73, 0x2f, 0x63, 0x6f, 0x6e, 0x73,
+	0x74, 0x65, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x76, 0x32, 0x2f, 0x75, 0x70, 0x67,
+	0x72, 0x61, 0x64, 0x65, 0x2d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x75, 0x70, 0x67, 0x72, 0x61,
+	0x64, 0x65, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -184,21 +554,34 @@ func file_upgrade_agent_upgradeproto_upgrade_proto_rawDescGZIP() []byte {
 	return file_upgrade_agent_upgradeproto_upgrade_proto_rawDescData
 }
 
-var file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_upgrade_agent_upgradeproto_upgrade_proto_goTypes = []interface{}{
-	(*ExecuteUpdateRequest)(nil),  // 0: upgrade.ExecuteUpdateRequest
-	(*ExecuteUpdateResponse)(nil), // 1: upgrade.ExecuteUpdateResponse
-	(*components.Component)(nil),  // 2: components.Component
+	(UpdateStep)(0),                     // 0: upgrade.UpdateStep
+	(UpdateStepState)(0),                // 1: upgrade.UpdateStepState
+	(*ExecuteUpdateRequest)(nil),        // 2: upgrade.ExecuteUpdateRequest
+	(*ExecuteUpdateResponse)(nil),       // 3: upgrade.ExecuteUpdateResponse
+	(*ExecuteUpdateStreamResponse)(nil), // 4: upgrade.ExecuteUpdateStreamResponse
+	(*UpdateProgress)(nil),              // 5: upgrade.UpdateProgress
+	(*UpdateLog)(nil),                   // 6: upgrade.UpdateLog
+	(*components.Component)(nil),        // 7: components.Component
 }
 var file_upgrade_agent_upgradeproto_upgrade_proto_depIdxs = []int32{
-	2, // 0: upgrade.ExecuteUpdateRequest.kubernetes_components:type_name -> components.Component
-	0, // 1: upgrade.Update.ExecuteUpdate:input_type -> upgrade.ExecuteUpdateRequest
-	1, // 2: upgrade.Update.ExecuteUpdate:output_type -> upgrade.ExecuteUpdateResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	7, // 0: upgrade.ExecuteUpdateRequest.kubernetes_components:type_name -> components.Component
+	5, // 1: upgrade.ExecuteUpdateStreamResponse.progress:type_name -> upgrade.UpdateProgress
+	6, // 2: upgrade.ExecuteUpdateStreamResponse.log:type_name -> upgrade.UpdateLog
+	0, // 3: upgrade.UpdateProgress.step:type_name -> upgrade.UpdateStep
+	1, // 4: upgrade.UpdateProgress.state:type_name -> upgrade.UpdateStepState
+	0, // 5: upgrade.UpdateLog.step:type_name -> upgrade.UpdateStep
+	2, // 6: upgrade.Update.ExecuteUpdate:input_type -> upgrade.ExecuteUpdateRequest
+	2, // 7: upgrade.Update.ExecuteUpdateStream:input_type -> upgrade.ExecuteUpdateRequest
+	3, // 8: upgrade.Update.ExecuteUpdate:output_type -> upgrade.ExecuteUpdateResponse
+	4, // 9: upgrade.Update.ExecuteUpdateStream:output_type -> upgrade.ExecuteUpdateStreamResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_upgrade_agent_upgradeproto_upgrade_proto_init() }
@@ -231,19 +614,60 @@ func file_upgrade_agent_upgradeproto_upgrade_proto_init() {
 				return nil
 			}
 		}
+		file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteUpdateStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateProgress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateLog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes[2].OneofWrappers = []interface{}{
+		(*ExecuteUpdateStreamResponse_Progress)(nil),
+		(*ExecuteUpdateStreamResponse_Log)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_upgrade_agent_upgradeproto_upgrade_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_upgrade_agent_upgradeproto_upgrade_proto_goTypes,
 		DependencyIndexes: file_upgrade_agent_upgradeproto_upgrade_proto_depIdxs,
+		EnumInfos:         file_upgrade_agent_upgradeproto_upgrade_proto_enumTypes,
 		MessageInfos:      file_upgrade_agent_upgradeproto_upgrade_proto_msgTypes,
 	}.Build()
 	File_upgrade_agent_upgradeproto_upgrade_proto = out.File
@@ -265,6 +689,9 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type UpdateClient interface {
 	ExecuteUpdate(ctx context.Context, in *ExecuteUpdateRequest, opts ...grpc.CallOption) (*ExecuteUpdateResponse, error)
+	// ExecuteUpdateStream executes the same transactional update as ExecuteUpdate,
+	// but streams the progress of each step and the output of executed commands.
+	ExecuteUpdateStream(ctx context.Context, in *ExecuteUpdateRequest, opts ...grpc.CallOption) (Update_ExecuteUpdateStreamClient, error)
 }
 
 type updateClient struct {
@@ -284,9 +711,44 @@ func (c *updateClient) ExecuteUpdate(ctx context.Context, in *ExecuteUpdateReque
 	return out, nil
 }
 
+func (c *updateClient) ExecuteUpdateStream(ctx context.Context, in *ExecuteUpdateRequest, opts ...grpc.CallOption) (Update_ExecuteUpdateStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Update_serviceDesc.Streams[0], "/upgrade.Update/ExecuteUpdateStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &updateExecuteUpdateStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Update_ExecuteUpdateStreamClient interface {
+	Recv() (*ExecuteUpdateStreamResponse, error)
+	grpc.ClientStream
+}
+
+type updateExecuteUpdateStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *updateExecuteUpdateStreamClient) Recv() (*ExecuteUpdateStreamResponse, error) {
+	m := new(ExecuteUpdateStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // UpdateServer is the server API for Update service.
 type UpdateServer interface {
 	ExecuteUpdate(context.Context, *ExecuteUpdateRequest) (*ExecuteUpdateResponse, error)
+	// ExecuteUpdateStream executes the same transactional update as ExecuteUpdate,
+	// but streams the progress of each step and the output of executed commands.
+	ExecuteUpdateStream(*ExecuteUpdateRequest, Update_ExecuteUpdateStreamServer) error
 }
 
 // UnimplementedUpdateServer can be embedded to have forward compatible implementations.
@@ -296,6 +758,9 @@ type UnimplementedUpdateServer struct {
 func (*UnimplementedUpdateServer) ExecuteUpdate(context.Context, *ExecuteUpdateRequest) (*ExecuteUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecuteUpdate not implemented")
 }
+func (*UnimplementedUpdateServer) ExecuteUpdateStream(*ExecuteUpdateRequest, Update_ExecuteUpdateStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExecuteUpdateStream not implemented")
+}
 
 func RegisterUpdateServer(s *grpc.Server, srv UpdateServer) {
 	s.RegisterService(&_Update_serviceDesc, srv)
@@ -319,6 +784,27 @@ func _Update_ExecuteUpdate_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Update_ExecuteUpdateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExecuteUpdateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UpdateServer).ExecuteUpdateStream(m, &updateExecuteUpdateStreamServer{stream})
+}
+
+type Update_ExecuteUpdateStreamServer interface {
+	Send(*ExecuteUpdateStreamResponse) error
+	grpc.ServerStream
+}
+
+type updateExecuteUpdateStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *updateExecuteUpdateStreamServer) Send(m *ExecuteUpdateStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Update_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "upgrade.Update",
 	HandlerType: (*UpdateServer)(nil),
@@ -328,6 +814,12 @@ var _Update_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Update_ExecuteUpdate_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExecuteUpdateStream",
+			Handler:       _Update_ExecuteUpdateStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "upgrade-agent/upgradeproto/upgrade.proto",
 }